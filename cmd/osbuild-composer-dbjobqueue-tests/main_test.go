@@ -97,6 +97,12 @@ func testMigrationPath(t *testing.T, makeJobQueue func(migration string, clean b
 	defer stop()
 	require.NoError(t, err)
 
+	last, err := dbjobqueue.SchemaVersion()
+	require.NoError(t, err)
+	require.NoError(t, q.(jobqueue.HealthChecker).CheckHealth(context.Background()))
+	err = q.(jobqueue.SchemaChecker).CheckSchema(context.Background())
+	require.EqualError(t, err, fmt.Sprintf("database is migrated to version 8, expected at least version %d", last))
+
 	id, err := q.Enqueue("test", "{\"arg\": \"impormtanmt\"}", nil, "")
 	require.NoError(t, err)
 	require.NotEmpty(t, id)
@@ -121,6 +127,9 @@ func testMigrationPath(t *testing.T, makeJobQueue func(migration string, clean b
 	require.NoError(t, err)
 	defer db_q.Close()
 	require.NoError(t, err)
+	require.NoError(t, db_q.CheckHealth(context.Background()))
+	require.NoError(t, db_q.CheckSchema(context.Background()))
+	testNewerSchema(t, db_q)
 
 	// make sure entering escaped nullbytes works in last
 	id2, err := db_q.Enqueue("test", "{\"arg\": \"impormtanmt\"}", nil, "")
@@ -139,3 +148,18 @@ func testMigrationPath(t *testing.T, makeJobQueue func(migration string, clean b
 	require.NoError(t, json.Unmarshal(result, &tr2))
 	require.Equal(t, tr, tr2)
 }
+
+// testNewerSchema checks that a database migrated past the schema of the
+// queue, as during a rolling upgrade, passes the schema check
+func testNewerSchema(t *testing.T, q *dbjobqueue.DBJobQueue) {
+	conn, err := pgx.Connect(context.Background(), jobqueuetest.TestDbURL())
+	require.NoError(t, err)
+	defer conn.Close(context.Background())
+
+	_, err = conn.Exec(context.Background(), "UPDATE schema_version SET version = version + 1")
+	require.NoError(t, err)
+	require.NoError(t, q.CheckSchema(context.Background()))
+
+	_, err = conn.Exec(context.Background(), "UPDATE schema_version SET version = version - 1")
+	require.NoError(t, err)
+}
@@ -25,10 +25,13 @@ import (
 	"github.com/osbuild/image-builder/pkg/distrofactory"
 	"github.com/osbuild/image-builder/pkg/experimentalflags"
 	"github.com/osbuild/image-builder/pkg/reporegistry"
+	"github.com/osbuild/osbuild-composer/internal/audit"
 	"github.com/osbuild/osbuild-composer/internal/auth"
 	"github.com/osbuild/osbuild-composer/internal/cloudapi"
 	v2 "github.com/osbuild/osbuild-composer/internal/cloudapi/v2"
+	"github.com/osbuild/osbuild-composer/internal/cloudcredentials"
 	"github.com/osbuild/osbuild-composer/internal/jobqueue/fsjobqueue"
+	"github.com/osbuild/osbuild-composer/internal/secrets"
 	"github.com/osbuild/osbuild-composer/internal/weldr"
 	"github.com/osbuild/osbuild-composer/internal/worker"
 )
@@ -48,6 +51,13 @@ type Composer struct {
 	api     *cloudapi.Server
 
 	weldrListener, localWorkerListener, workerListener, apiListener, promListener net.Listener
+
+	// weldr API served on a TCP socket, restricted by weldrRoles
+	weldrTCPListener net.Listener
+	weldrRoles       *weldr.RoleMapping
+
+	// audit records the mutating calls of all APIs, nil if disabled
+	audit *audit.Log
 }
 
 func NewComposer(config *ComposerConfigFile, stateDir, cacheDir string) (*Composer, error) {
@@ -62,9 +72,16 @@ func NewComposer(config *ComposerConfigFile, stateDir, cacheDir string) (*Compos
 		JWTEnabled:           config.Worker.EnableJWT,
 		TenantProviderFields: config.Worker.JWTTenantProviderFields,
 		WorkerWatchFreq:      time.Minute * 5,
+		AdminUsers:           config.Worker.AdminUsers,
+		AdminJWTUserFields:   config.Worker.JWTUserFields,
 	}
 
 	var err error
+	workerConfig.Routes, err = config.workerRoutes()
+	if err != nil {
+		return nil, fmt.Errorf("worker API: %v", err)
+	}
+
 	if config.Worker.EnableArtifacts {
 		workerConfig.ArtifactsDir, err = c.ensureStateDirectory("artifacts", 0755)
 		if err != nil {
@@ -72,6 +89,55 @@ func NewComposer(config *ComposerConfigFile, stateDir, cacheDir string) (*Compos
 		}
 	}
 
+	secretsDir, err := c.ensureStateDirectory("secrets", 0700)
+	if err != nil {
+		return nil, err
+	}
+	secretsKeyFile := config.Worker.SecretsKeyFile
+	if secretsKeyFile == "" {
+		secretsKeyFile = path.Join(c.stateDir, "secrets.key")
+	}
+	secretsKey, err := secrets.LoadOrCreateKey(secretsKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	workerConfig.Secrets, err = secrets.NewVault(secretsKey, secrets.NewFileBackend(secretsDir))
+	if err != nil {
+		return nil, fmt.Errorf("cannot set up secrets: %v", err)
+	}
+
+	if config.Audit.Enabled {
+		auditPath := config.Audit.Path
+		if auditPath == "" {
+			auditDir, err := c.ensureStateDirectory("audit", 0700)
+			if err != nil {
+				return nil, err
+			}
+			auditPath = path.Join(auditDir, "audit.jsonl")
+		}
+		options := audit.Options{
+			MaxSize:  int64(config.Audit.MaxSizeMiB) * 1024 * 1024,
+			MaxFiles: config.Audit.MaxFiles,
+		}
+		if config.Audit.ForwardToLog {
+			options.Logger = logrus.StandardLogger()
+		}
+		c.audit, err = audit.NewLog(auditPath, options)
+		if err != nil {
+			return nil, err
+		}
+		workerConfig.Audit = c.audit
+	}
+
+	credentialsDir, err := c.ensureStateDirectory("cloud-credentials", 0700)
+	if err != nil {
+		return nil, err
+	}
+	workerConfig.CloudCredentials, err = cloudcredentials.NewRegistry(credentialsDir, workerConfig.Secrets)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load cloud credentials: %v", err)
+	}
+
 	c.distros = distrofactory.NewDefault()
 	err = c.distros.RegisterAliases(config.DistroAliases)
 	if err != nil {
@@ -157,10 +223,52 @@ func (c *Composer) InitWeldr(weldrListener net.Listener, distrosImageTypeDenylis
 	if err != nil {
 		return err
 	}
+	c.weldr.SetAuditLog(c.audit)
 	c.weldrListener = weldrListener
 	return nil
 }
 
+// InitWeldrTCP makes the weldr API, which must have been initialized with
+// InitWeldr, also available on a TCP socket. Connections must use TLS and are
+// authenticated either by mTLS or by JWT. Each user is assigned a role from
+// the weldr_api configuration, which limits the routes they may call.
+func (c *Composer) InitWeldrTCP(cert, key string, l net.Listener) error {
+	if c.weldr == nil {
+		return fmt.Errorf("weldr API on TCP requires the weldr API to be initialized")
+	}
+
+	// If both are off or both are on, error out
+	if c.config.WeldrAPI.EnableJWT == c.config.WeldrAPI.EnableMTLS {
+		return fmt.Errorf("weldr API: Either mTLS or JWT authentication must be enabled")
+	}
+
+	var err error
+	c.weldrRoles, err = c.config.weldrRoleMapping()
+	if err != nil {
+		return err
+	}
+
+	clientAuth := tls.RequireAndVerifyClientCert
+	if c.config.WeldrAPI.EnableJWT {
+		// jwt enabled => tls listener without client auth
+		clientAuth = tls.NoClientCert
+	}
+
+	tlsConfig, err := createTLSConfig(&connectionConfig{
+		CACertFile:     c.config.WeldrAPI.CA,
+		ServerKeyFile:  key,
+		ServerCertFile: cert,
+		AllowedDomains: c.config.WeldrAPI.AllowedDomains,
+		ClientAuth:     clientAuth,
+	})
+	if err != nil {
+		return fmt.Errorf("Error creating TLS configuration for weldr API: %v", err)
+	}
+
+	c.weldrTCPListener = tls.NewListener(l, tlsConfig)
+	return nil
+}
+
 func (c *Composer) InitMetricsAPI(prometheus net.Listener) {
 	c.promListener = prometheus
 }
@@ -170,6 +278,7 @@ func (c *Composer) InitAPI(cert, key string, enableTLS bool, enableMTLS bool, en
 		JWTEnabled:                    c.config.Koji.EnableJWT,
 		TenantProviderFields:          c.config.Koji.JWTTenantProviderFields,
 		BootcUseRemoteContainerSource: c.config.Bootc.UseRemoteContainerSource,
+		Audit:                         c.audit,
 	}
 
 	// handle experimental image-builder manifest generation option using the
@@ -178,6 +287,15 @@ func (c *Composer) InitAPI(cert, key string, enableTLS bool, enableMTLS bool, en
 		config.ImageBuilderManifestGeneration = true
 	}
 
+	var err error
+	config.Roles, config.APIKeys, err = c.config.cloudAPIAuthorization()
+	if err != nil {
+		return fmt.Errorf("API: %v", err)
+	}
+	if enableJWT && len(config.APIKeys) > 0 {
+		return fmt.Errorf("API: API keys can't be used together with JWT authentication")
+	}
+
 	c.api = cloudapi.NewServer(c.workers, c.distros, c.repos, config)
 
 	if !enableTLS {
@@ -185,14 +303,15 @@ func (c *Composer) InitAPI(cert, key string, enableTLS bool, enableMTLS bool, en
 		return nil
 	}
 
-	// If both are off or both are on, error out
-	if enableJWT == enableMTLS {
+	// If both are off or both are on, error out, unless API keys
+	// authenticate the callers
+	if enableJWT && enableMTLS || !enableJWT && !enableMTLS && len(config.APIKeys) == 0 {
 		return fmt.Errorf("API: Either mTLS or JWT authentication must be enabled")
 	}
 
 	clientAuth := tls.RequireAndVerifyClientCert
-	if enableJWT {
-		// jwt enabled => tls listener without client auth
+	if !enableMTLS {
+		// jwt or API keys => tls listener without client auth
 		clientAuth = tls.NoClientCert
 	}
 
@@ -260,7 +379,9 @@ func (c *Composer) Start() error {
 		logrus.Fatal("neither the weldr API socket nor the composer API socket is enabled, osbuild-composer is useless without one of these APIs enabled")
 	}
 
-	var localWorkerAPI, remoteWorkerAPI, composerAPI, prometheusAPI *http.Server
+	var localWorkerAPI, remoteWorkerAPI, composerAPI, prometheusAPI, weldrTCPAPI *http.Server
+
+	healthz, readyz := c.healthCheckers()
 
 	if c.localWorkerListener != nil {
 		localWorkerAPI = &http.Server{
@@ -318,6 +439,8 @@ func (c *Composer) Start() error {
 		// trailing slash for rooted subtrees, whereas the
 		// handler functions don't.
 		mux.Handle(apiRouteV2+"/", c.api.V2(apiRouteV2))
+		mux.Handle("/healthz", healthz)
+		mux.Handle("/readyz", readyz)
 
 		handler := http.Handler(mux)
 		var err error
@@ -330,6 +453,8 @@ func (c *Composer) Start() error {
 				[]string{
 					"/api/image-builder-composer/v2/openapi/?$",
 					"/api/image-builder-composer/v2/errors/?$",
+					"^/healthz$",
+					"^/readyz$",
 				}, mux)
 			if err != nil {
 				panic(err)
@@ -354,6 +479,8 @@ func (c *Composer) Start() error {
 		// metrics listener on another port
 		metricsMux := http.NewServeMux()
 		metricsMux.Handle("/metrics", promhttp.Handler().(http.HandlerFunc))
+		metricsMux.Handle("/healthz", healthz)
+		metricsMux.Handle("/readyz", readyz)
 
 		prometheusAPI = &http.Server{
 			ErrorLog:          c.logger,
@@ -376,6 +503,46 @@ func (c *Composer) Start() error {
 				panic(err)
 			}
 		}()
+
+		if c.config.WeldrAPI.SourceCheckInterval != "" {
+			interval, err := time.ParseDuration(c.config.WeldrAPI.SourceCheckInterval)
+			if err != nil {
+				return fmt.Errorf("invalid weldr source_check_interval: %v", err)
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go c.weldr.WatchSources(ctx, interval)
+		}
+	}
+
+	if c.weldrTCPListener != nil {
+		handler := c.weldrRoles.Handler(c.weldr)
+		var err error
+		if c.config.WeldrAPI.EnableJWT {
+			handler, err = auth.BuildJWTAuthHandler(
+				c.config.WeldrAPI.JWTKeysURLs,
+				c.config.WeldrAPI.JWTKeysCA,
+				c.config.WeldrAPI.JWTACLFile,
+				[]string{},
+				handler,
+			)
+			if err != nil {
+				panic(err)
+			}
+		}
+
+		weldrTCPAPI = &http.Server{
+			ErrorLog:          c.logger,
+			Handler:           handler,
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+
+		go func() {
+			err := weldrTCPAPI.Serve(c.weldrTCPListener)
+			if err != nil && err != http.ErrServerClosed {
+				panic(err)
+			}
+		}()
 	}
 
 	sigint := make(chan os.Signal, 1)
@@ -418,6 +585,13 @@ func (c *Composer) Start() error {
 		}
 	}
 
+	if c.weldrTCPListener != nil {
+		err := weldrTCPAPI.Shutdown(context.Background())
+		if err != nil {
+			panic(err)
+		}
+	}
+
 	if c.weldrListener != nil {
 		err := c.weldr.Shutdown(context.Background())
 		if err != nil {
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"os"
@@ -9,6 +11,12 @@ import (
 	"strings"
 
 	"github.com/BurntSushi/toml"
+
+	"github.com/osbuild/osbuild-composer/internal/auth"
+	v2 "github.com/osbuild/osbuild-composer/internal/cloudapi/v2"
+	"github.com/osbuild/osbuild-composer/internal/logsink"
+	"github.com/osbuild/osbuild-composer/internal/weldr"
+	"github.com/osbuild/osbuild-composer/internal/worker"
 )
 
 type ComposerConfigFile struct {
@@ -16,16 +24,22 @@ type ComposerConfigFile struct {
 	Worker             WorkerAPIConfig   `toml:"worker"`
 	WeldrAPI           WeldrAPIConfig    `toml:"weldr_api"`
 	Bootc              BootcConfig       `toml:"bootc"`
+	Audit              AuditConfig       `toml:"audit"`
+	Tracing            TracingConfig     `toml:"tracing"`
 	DistroAliases      map[string]string `toml:"distro_aliases" env:"DISTRO_ALIASES"`
 	LogLevel           string            `toml:"log_level"`
 	LogFormat          string            `toml:"log_format"`
 	DNFJson            string            `toml:"dnf-json"`
 	IgnoreMissingRepos bool              `toml:"ignore_missing_repos"`
-	SplunkHost         string            `env:"SPLUNK_HEC_HOST"`
-	SplunkPort         string            `env:"SPLUNK_HEC_PORT"`
-	SplunkToken        string            `env:"SPLUNK_HEC_TOKEN"`
-	GlitchTipDSN       string            `env:"GLITCHTIP_DSN"`
-	DeploymentChannel  string            `env:"CHANNEL"`
+	// LogSinks forward the log to external log stores
+	LogSinks []logsink.Config `toml:"log_sinks"`
+	// SplunkHost and GlitchTipDSN add a log sink each, they predate
+	// LogSinks and are kept for existing deployments
+	SplunkHost        string `env:"SPLUNK_HEC_HOST"`
+	SplunkPort        string `env:"SPLUNK_HEC_PORT"`
+	SplunkToken       string `env:"SPLUNK_HEC_TOKEN"`
+	GlitchTipDSN      string `env:"GLITCHTIP_DSN"`
+	DeploymentChannel string `env:"CHANNEL"`
 }
 
 type KojiAPIConfig struct {
@@ -38,6 +52,25 @@ type KojiAPIConfig struct {
 	JWTKeysCA               string   `toml:"jwt_ca_file"`
 	JWTACLFile              string   `toml:"jwt_acl_file"`
 	JWTTenantProviderFields []string `toml:"jwt_tenant_provider_fields"`
+	// JWTRoleFields are the claims holding the roles of a caller, roles
+	// are only enforced if set
+	JWTRoleFields []string `toml:"jwt_role_fields"`
+	// Roles maps the values of JWTRoleFields to one of the roles "none",
+	// "viewer", "composer" or "admin"
+	Roles map[string]string `toml:"roles"`
+	// DefaultRole is the role of callers without any of the values in Roles
+	DefaultRole string `toml:"default_role"`
+	// APIKeys are static service accounts authenticating with a bearer
+	// token, for deployments without an OIDC provider. They can't be used
+	// together with JWT.
+	APIKeys map[string]APIKeyConfig `toml:"api_keys"`
+}
+
+type APIKeyConfig struct {
+	// KeySHA256 is the hex encoded SHA-256 of the key
+	KeySHA256 string `toml:"key_sha256"`
+	Tenant    string `toml:"tenant"`
+	Role      string `toml:"role"`
 }
 
 type WorkerAPIConfig struct {
@@ -61,16 +94,92 @@ type WorkerAPIConfig struct {
 	JWTACLFile              string   `toml:"jwt_acl_file"`
 	JWTTenantProviderFields []string `toml:"jwt_tenant_provider_fields"`
 	WorkerHeartbeatTimeout  string   `toml:"worker_heartbeat_timeout"`
+	// AdminUsers may list, drain and evict workers. They are identified by
+	// the common name of their client certificate or the first non-empty
+	// JWT claim in JWTUserFields.
+	AdminUsers    []string `toml:"admin_users"`
+	JWTUserFields []string `toml:"jwt_user_fields"`
+	// SecretsKeyFile holds the key the secrets of upload targets are
+	// encrypted with. If empty, a key is generated in the state directory.
+	SecretsKeyFile string `toml:"secrets_key_file" env:"SECRETS_KEY_FILE"`
+	// Routes send the jobs of other channels to pools of workers, so that
+	// shared workers can serve many tenants
+	Routes []WorkerRouteConfig `toml:"routes"`
+}
+
+// WorkerRouteConfig routes the jobs of the channels to the workers of the
+// pool. Channels are the ones jobs are enqueued with, "org-" followed by the
+// tenant or "*" for all channels. Routes of "*" can exclude channels, e.g.
+// the ones of tenants with reserved workers. The pool is the channel of the
+// serving workers. Empty job types and arches match all of them, labels are
+// the worker labels the jobs request.
+type WorkerRouteConfig struct {
+	Channels        []string `toml:"channels"`
+	ExcludeChannels []string `toml:"exclude_channels"`
+	JobTypes        []string `toml:"job_types"`
+	Arches          []string `toml:"arches"`
+	Labels          []string `toml:"labels"`
+	Pool            string   `toml:"pool"`
 }
 
 type WeldrAPIConfig struct {
 	DistroConfigs map[string]WeldrDistroConfig `toml:"distros"`
+	// SourceCheckInterval enables periodic health checks of the user
+	// defined sources when set to a duration, e.g. "1h"
+	SourceCheckInterval string `toml:"source_check_interval"`
+
+	// The following options apply to the weldr API served on the TCP
+	// socket (osbuild-composer-weldr.socket), which always uses TLS and
+	// either mTLS or JWT for authentication.
+	AllowedDomains []string `toml:"allowed_domains"`
+	CA             string   `toml:"ca"`
+	EnableMTLS     bool     `toml:"enable_mtls"`
+	EnableJWT      bool     `toml:"enable_jwt"`
+	JWTKeysURLs    []string `toml:"jwt_keys_urls"`
+	JWTKeysCA      string   `toml:"jwt_ca_file"`
+	JWTACLFile     string   `toml:"jwt_acl_file"`
+	// JWTUserFields are the claims identifying a user, the first non-empty
+	// one is used
+	JWTUserFields []string `toml:"jwt_user_fields"`
+	// Users maps a user (the common name of the client certificate or the
+	// JWT user claim) to one of the roles "none", "read-only", "composer"
+	// or "admin"
+	Users map[string]string `toml:"users"`
+	// DefaultRole is the role of authenticated users not listed in Users
+	DefaultRole string `toml:"default_role"`
 }
 
 type WeldrDistroConfig struct {
 	ImageTypeDenyList []string `toml:"image_type_denylist"`
 }
 
+// AuditConfig configures the log of the mutating calls of all APIs
+type AuditConfig struct {
+	Enabled bool `toml:"enabled"`
+	// Path of the log, defaults to audit/audit.jsonl in the state directory
+	Path string `toml:"path"`
+	// MaxSizeMiB is the size at which the log is rotated, zero disables
+	// the rotation
+	MaxSizeMiB int `toml:"max_size_mib"`
+	// MaxFiles is the number of rotated logs which are kept
+	MaxFiles int `toml:"max_files"`
+	// ForwardToLog additionally sends the records to the log of composer,
+	// and thereby to the journal and Splunk if they are configured
+	ForwardToLog bool `toml:"forward_to_log"`
+}
+
+// TracingConfig configures the export of the spans of the APIs and the
+// jobs to an OpenTelemetry collector
+type TracingConfig struct {
+	// Endpoint is the OTLP/HTTP URL the spans are sent to, e.g.
+	// "https://collector.example.com:4318/v1/traces". Tracing is disabled
+	// if it's empty.
+	Endpoint string            `toml:"endpoint" env:"TRACING_ENDPOINT"`
+	Headers  map[string]string `toml:"headers"`
+	// SampleRatio is the fraction of the traces which are recorded
+	SampleRatio float64 `toml:"sample_ratio" env:"TRACING_SAMPLE_RATIO"`
+}
+
 // BootcConfig holds configuration options specific to bootc composes.
 type BootcConfig struct {
 	UseRemoteContainerSource bool `toml:"use_remote_container_source" env:"BOOTC_USE_REMOTE_CONTAINER_SOURCE"`
@@ -90,6 +199,125 @@ func (c *ComposerConfigFile) weldrDistrosImageTypeDenyList() map[string][]string
 	return distrosImageTypeDenyList
 }
 
+// weldrRoleMapping returns the role mapping applied to the weldr API served
+// on the TCP socket.
+func (c *ComposerConfigFile) weldrRoleMapping() (*weldr.RoleMapping, error) {
+	defaultRole, err := weldr.ParseRole(c.WeldrAPI.DefaultRole)
+	if err != nil {
+		return nil, err
+	}
+
+	mapping := &weldr.RoleMapping{
+		Users:         map[string]weldr.Role{},
+		DefaultRole:   defaultRole,
+		JWTUserFields: c.WeldrAPI.JWTUserFields,
+	}
+	for user, name := range c.WeldrAPI.Users {
+		role, err := weldr.ParseRole(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid role for weldr user %q: %v", user, err)
+		}
+		mapping.Users[user] = role
+	}
+
+	return mapping, nil
+}
+
+// cloudAPIAuthorization returns the role mapping of JWT callers and the API
+// keys of the cloud API. The role mapping is nil if no role fields are
+// configured.
+func (c *ComposerConfigFile) cloudAPIAuthorization() (*v2.RoleMapping, map[string]auth.APIKey, error) {
+	var mapping *v2.RoleMapping
+	if len(c.Koji.JWTRoleFields) > 0 {
+		defaultRole, err := v2.ParseRole(c.Koji.DefaultRole)
+		if err != nil {
+			return nil, nil, err
+		}
+		mapping = &v2.RoleMapping{
+			JWTRoleFields: c.Koji.JWTRoleFields,
+			Roles:         map[string]v2.Role{},
+			DefaultRole:   defaultRole,
+		}
+		for value, name := range c.Koji.Roles {
+			role, err := v2.ParseRole(name)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid role for %q: %v", value, err)
+			}
+			mapping.Roles[value] = role
+		}
+	}
+
+	keys := map[string]auth.APIKey{}
+	for name, key := range c.Koji.APIKeys {
+		if _, err := v2.ParseRole(key.Role); err != nil {
+			return nil, nil, fmt.Errorf("invalid role for API key %q: %v", name, err)
+		}
+		hash := strings.ToLower(key.KeySHA256)
+		if sum, err := hex.DecodeString(hash); err != nil || len(sum) != sha256.Size {
+			return nil, nil, fmt.Errorf("API key %q: key_sha256 must be a hex encoded SHA-256", name)
+		}
+		if _, ok := keys[hash]; ok {
+			return nil, nil, fmt.Errorf("API key %q: key is used by another API key", name)
+		}
+		keys[hash] = auth.APIKey{
+			Name:   name,
+			Tenant: key.Tenant,
+			Role:   key.Role,
+		}
+	}
+
+	return mapping, keys, nil
+}
+
+// workerRoutes returns the routes of the worker API
+func (c *ComposerConfigFile) workerRoutes() ([]worker.Route, error) {
+	var routes []worker.Route
+	for _, r := range c.Worker.Routes {
+		route := worker.Route(r)
+		if err := route.Validate(); err != nil {
+			return nil, err
+		}
+		routes = append(routes, route)
+	}
+	return routes, nil
+}
+
+// logSinks returns the configured log sinks including the ones set up by
+// the Splunk and GlitchTip environment variables
+func (c *ComposerConfigFile) logSinks() ([]logsink.Config, error) {
+	sinks := append([]logsink.Config{}, c.LogSinks...)
+	if c.SplunkHost != "" {
+		sinks = append(sinks, logsink.Config{
+			Type:  logsink.TypeSplunk,
+			URL:   fmt.Sprintf("https://%s:%s/services/collector/event", c.SplunkHost, c.SplunkPort),
+			Token: c.SplunkToken,
+			// keep the messages as they were before LogSinks
+			Formatted: true,
+		})
+	}
+	if c.GlitchTipDSN != "" {
+		sinks = append(sinks, logsink.Config{
+			Type: logsink.TypeGlitchTip,
+			URL:  c.GlitchTipDSN,
+		})
+	}
+
+	glitchTip := false
+	for i, sink := range sinks {
+		if err := sink.Validate(); err != nil {
+			return nil, fmt.Errorf("log sink %d: %v", i, err)
+		}
+		// the sentry client is global
+		if sink.Type == logsink.TypeGlitchTip {
+			if glitchTip {
+				return nil, fmt.Errorf("log sink %d: only one glitchtip log sink is supported", i)
+			}
+			glitchTip = true
+		}
+	}
+	return sinks, nil
+}
+
 // GetDefaultConfig returns the default configuration of osbuild-composer
 // Defaults:
 //   - 'azure-rhui', 'azure-sap-rhui', 'ec2', 'ec2-ha', 'ec2-sap' image types on 'rhel-*'
@@ -97,9 +325,10 @@ func (c *ComposerConfigFile) weldrDistrosImageTypeDenyList() map[string][]string
 func GetDefaultConfig() *ComposerConfigFile {
 	return &ComposerConfigFile{
 		Koji: KojiAPIConfig{
-			EnableTLS:  true,
-			EnableMTLS: true,
-			EnableJWT:  false,
+			EnableTLS:   true,
+			EnableMTLS:  true,
+			EnableJWT:   false,
+			DefaultRole: "viewer",
 		},
 		Worker: WorkerAPIConfig{
 			RequestJobTimeout:      "0",
@@ -111,7 +340,7 @@ func GetDefaultConfig() *ComposerConfigFile {
 			WorkerHeartbeatTimeout: "1h",
 		},
 		WeldrAPI: WeldrAPIConfig{
-			map[string]WeldrDistroConfig{
+			DistroConfigs: map[string]WeldrDistroConfig{
 				"rhel-*": {
 					ImageTypeDenyList: []string{
 						"azure-eap7-rhui",
@@ -124,6 +353,17 @@ func GetDefaultConfig() *ComposerConfigFile {
 					},
 				},
 			},
+			EnableMTLS:    true,
+			JWTUserFields: []string{"preferred_username", "sub"},
+			DefaultRole:   "read-only",
+		},
+		Audit: AuditConfig{
+			Enabled:    true,
+			MaxSizeMiB: 100,
+			MaxFiles:   10,
+		},
+		Tracing: TracingConfig{
+			SampleRatio: 1,
 		},
 		DistroAliases: map[string]string{
 			"rhel-7":  "rhel-7.9",
@@ -218,6 +458,16 @@ func loadConfigFromEnv(intf interface{}) error {
 				return err
 			}
 			fieldV.SetBool(value)
+		case reflect.Float64:
+			confV, ok := lookupEnvTagValue(fieldT)
+			if !ok {
+				continue
+			}
+			value, err := strconv.ParseFloat(confV, 64)
+			if err != nil {
+				return err
+			}
+			fieldV.SetFloat(value)
 		case reflect.Slice:
 			// no-op
 			continue
@@ -254,5 +504,20 @@ func loadConfigFromEnv(intf interface{}) error {
 func DumpConfig(c ComposerConfigFile, w io.Writer) error {
 	// sensor sensitive fields
 	c.Worker.PGPassword = ""
+	// the headers usually carry the credentials of the collector
+	c.Tracing.Headers = nil
+	c.SplunkToken = ""
+	// and the DSN the key of the project
+	c.GlitchTipDSN = ""
+	sinks := make([]logsink.Config, len(c.LogSinks))
+	for i, sink := range c.LogSinks {
+		sink.Token = ""
+		sink.Headers = nil
+		if sink.Type == logsink.TypeGlitchTip {
+			sink.URL = ""
+		}
+		sinks[i] = sink
+	}
+	c.LogSinks = sinks
 	return toml.NewEncoder(w).Encode(c)
 }
@@ -6,8 +6,15 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/auth"
+	v2 "github.com/osbuild/osbuild-composer/internal/cloudapi/v2"
+	"github.com/osbuild/osbuild-composer/internal/logsink"
+	"github.com/osbuild/osbuild-composer/internal/weldr"
+	"github.com/osbuild/osbuild-composer/internal/worker"
 )
 
 func TestEmpty(t *testing.T) {
@@ -32,9 +39,10 @@ func TestDefaultConfig(t *testing.T) {
 	require.Equal(t, "", defaultConfig.Worker.JWTKeysCA)
 
 	require.Equal(t, KojiAPIConfig{
-		EnableTLS:  true,
-		EnableMTLS: true,
-		EnableJWT:  false,
+		EnableTLS:   true,
+		EnableMTLS:  true,
+		EnableJWT:   false,
+		DefaultRole: "viewer",
 	}, defaultConfig.Koji)
 
 	require.Equal(t, WorkerAPIConfig{
@@ -61,6 +69,9 @@ func TestDefaultConfig(t *testing.T) {
 				},
 			},
 		},
+		EnableMTLS:    true,
+		JWTUserFields: []string{"preferred_username", "sub"},
+		DefaultRole:   "read-only",
 	}
 
 	require.Equal(t, expectedWeldrAPIConfig, defaultConfig.WeldrAPI)
@@ -73,6 +84,14 @@ func TestDefaultConfig(t *testing.T) {
 	}
 	require.Equal(t, expectedDistroAliases, defaultConfig.DistroAliases)
 
+	require.Equal(t, AuditConfig{
+		Enabled:    true,
+		MaxSizeMiB: 100,
+		MaxFiles:   10,
+	}, defaultConfig.Audit)
+
+	require.Equal(t, TracingConfig{SampleRatio: 1}, defaultConfig.Tracing)
+
 	require.Equal(t, "journal", defaultConfig.LogFormat)
 	require.Equal(t, BootcConfig{}, defaultConfig.Bootc)
 }
@@ -90,13 +109,52 @@ func TestConfig(t *testing.T) {
 
 	require.Equal(t, []string{"qcow2", "vmdk"}, config.WeldrAPI.DistroConfigs["*"].ImageTypeDenyList)
 	require.Equal(t, []string{"qcow2"}, config.WeldrAPI.DistroConfigs["rhel-84"].ImageTypeDenyList)
+	require.Equal(t, "6h", config.WeldrAPI.SourceCheckInterval)
+	require.Equal(t, map[string]string{"ci-bot": "composer", "jdoe": "admin"}, config.WeldrAPI.Users)
+	require.Equal(t, "none", config.WeldrAPI.DefaultRole)
 
 	require.Equal(t, "overwrite-me-db", config.Worker.PGDatabase)
+	require.Equal(t, []string{"jdoe"}, config.Worker.AdminUsers)
+	require.Equal(t, []string{"preferred_username"}, config.Worker.JWTUserFields)
+	require.Equal(t, "/etc/osbuild-composer/secrets.key", config.Worker.SecretsKeyFile)
 
 	require.False(t, config.Koji.EnableJWT)
 	require.Equal(t, []string{"https://sso.redhat.com/auth/realms/redhat-external/protocol/openid-connect/certs"}, config.Koji.JWTKeysURLs)
 	require.Equal(t, "", config.Koji.JWTKeysCA)
 	require.Equal(t, "/var/lib/osbuild-composer/acl", config.Koji.JWTACLFile)
+	require.Equal(t, []string{"groups"}, config.Koji.JWTRoleFields)
+	require.Equal(t, map[string]string{"image-builder-users": "composer", "image-builder-admins": "admin"}, config.Koji.Roles)
+	require.Equal(t, "none", config.Koji.DefaultRole)
+	require.Equal(t, "composer", config.Koji.APIKeys["ci"].Role)
+
+	require.Equal(t, AuditConfig{
+		Enabled:      true,
+		Path:         "/var/log/osbuild-composer/audit.jsonl",
+		MaxSizeMiB:   100,
+		MaxFiles:     3,
+		ForwardToLog: true,
+	}, config.Audit)
+
+	require.Equal(t, TracingConfig{
+		Endpoint:    "https://collector.example.com:4318/v1/traces",
+		Headers:     map[string]string{"Authorization": "Bearer overwrite-me"},
+		SampleRatio: 0.1,
+	}, config.Tracing)
+
+	require.Equal(t, []logsink.Config{
+		{
+			Type:          "loki",
+			URL:           "https://loki.example.com/loki/api/v1/push",
+			Token:         "overwrite-me",
+			Labels:        map[string]string{"env": "staging"},
+			Level:         "info",
+			FlushInterval: 10 * time.Second,
+		},
+		{
+			Type: "otlp",
+			URL:  "https://collector.example.com:4318/v1/logs",
+		},
+	}, config.LogSinks)
 
 	// 'rhel-8' and 'rhel-9' aliases are overwritten by the config file
 	expectedDistroAliases := map[string]string{
@@ -112,6 +170,7 @@ func TestConfig(t *testing.T) {
 	require.NoError(t, os.Setenv("PGDATABASE", "composer-db"))
 	// NOTE: use negated config value to ensure that the env variable overrides the config file value
 	require.NoError(t, os.Setenv("BOOTC_USE_REMOTE_CONTAINER_SOURCE", strconv.FormatBool(!config.Bootc.UseRemoteContainerSource)))
+	require.NoError(t, os.Setenv("TRACING_SAMPLE_RATIO", "0.5"))
 
 	// Reload the config from the file and verify that the env variables take precedence
 	config, err = LoadConfig("testdata/test.toml")
@@ -120,6 +179,7 @@ func TestConfig(t *testing.T) {
 
 	require.Equal(t, "composer-db", config.Worker.PGDatabase)
 	require.False(t, config.Bootc.UseRemoteContainerSource)
+	require.Equal(t, 0.5, config.Tracing.SampleRatio)
 }
 
 func TestWeldrDistrosImageTypeDenyList(t *testing.T) {
@@ -149,6 +209,45 @@ func TestDumpConfig(t *testing.T) {
 	require.NotContains(t, buf.String(), "sensitive")
 	// DumpConfig takes a copy
 	require.Equal(t, "sensitive", config.Worker.PGPassword)
+
+	config.LogSinks = []logsink.Config{
+		{Type: "http", URL: "https://logs.example.com", Token: "sensitive", Headers: map[string]string{"X-Key": "sensitive"}},
+		{Type: "glitchtip", URL: "https://sensitive@glitchtip.example.com/1"},
+	}
+	config.SplunkToken = "sensitive"
+	config.GlitchTipDSN = "https://sensitive@glitchtip.example.com/1"
+	buf.Reset()
+	require.NoError(t, DumpConfig(*config, &buf))
+	require.Contains(t, buf.String(), "https://logs.example.com")
+	require.NotContains(t, buf.String(), "sensitive")
+	require.Equal(t, "sensitive", config.LogSinks[0].Token)
+}
+
+func TestLogSinks(t *testing.T) {
+	config := &ComposerConfigFile{
+		LogSinks: []logsink.Config{
+			{Type: "http", URL: "https://logs.example.com"},
+		},
+		SplunkHost:   "splunk.example.com",
+		SplunkPort:   "8088",
+		SplunkToken:  "hec-token",
+		GlitchTipDSN: "https://key@glitchtip.example.com/1",
+	}
+	sinks, err := config.logSinks()
+	require.NoError(t, err)
+	require.Equal(t, []logsink.Config{
+		{Type: "http", URL: "https://logs.example.com"},
+		{Type: "splunk", URL: "https://splunk.example.com:8088/services/collector/event", Token: "hec-token", Formatted: true},
+		{Type: "glitchtip", URL: "https://key@glitchtip.example.com/1"},
+	}, sinks)
+
+	config.LogSinks = append(config.LogSinks, logsink.Config{Type: "glitchtip", URL: "https://key@glitchtip.example.com/2"})
+	_, err = config.logSinks()
+	require.EqualError(t, err, "log sink 3: only one glitchtip log sink is supported")
+
+	config.LogSinks = []logsink.Config{{Type: "syslog"}}
+	_, err = config.logSinks()
+	require.EqualError(t, err, `log sink 0: unknown log sink type "syslog"`)
 }
 
 func TestEnvStrToMap(t *testing.T) {
@@ -186,3 +285,78 @@ func TestConfigFromEnv(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, expectedDistroAliases, config.DistroAliases)
 }
+
+func TestWeldrRoleMapping(t *testing.T) {
+	config, err := LoadConfig("testdata/test.toml")
+	require.NoError(t, err)
+
+	mapping, err := config.weldrRoleMapping()
+	require.NoError(t, err)
+	require.Equal(t, weldr.RoleNone, mapping.DefaultRole)
+	require.Equal(t, map[string]weldr.Role{"ci-bot": weldr.RoleComposer, "jdoe": weldr.RoleAdmin}, mapping.Users)
+
+	config.WeldrAPI.Users["mallory"] = "root"
+	_, err = config.weldrRoleMapping()
+	require.Error(t, err)
+}
+
+func TestCloudAPIAuthorization(t *testing.T) {
+	config, err := LoadConfig("testdata/test.toml")
+	require.NoError(t, err)
+
+	mapping, keys, err := config.cloudAPIAuthorization()
+	require.NoError(t, err)
+	require.Equal(t, &v2.RoleMapping{
+		JWTRoleFields: []string{"groups"},
+		Roles:         map[string]v2.Role{"image-builder-users": v2.RoleComposer, "image-builder-admins": v2.RoleAdmin},
+		DefaultRole:   v2.RoleNone,
+	}, mapping)
+	require.Equal(t, map[string]auth.APIKey{
+		"6fdb0a6e5b8e0c5b0d6c1bde7b5a7c0f6cf8d8c1d1c7ab27ab6ed3da5c4b0fb1": {Name: "ci", Tenant: "42", Role: "composer"},
+	}, keys)
+
+	// roles are only enforced with role fields
+	mapping, _, err = GetDefaultConfig().cloudAPIAuthorization()
+	require.NoError(t, err)
+	require.Nil(t, mapping)
+
+	config.Koji.Roles["mallory"] = "root"
+	_, _, err = config.cloudAPIAuthorization()
+	require.Error(t, err)
+	delete(config.Koji.Roles, "mallory")
+
+	config.Koji.APIKeys["plain"] = APIKeyConfig{KeySHA256: "secret", Role: "viewer"}
+	_, _, err = config.cloudAPIAuthorization()
+	require.Error(t, err)
+}
+
+func TestWorkerRoutes(t *testing.T) {
+	config, err := LoadConfig("testdata/test.toml")
+	require.NoError(t, err)
+
+	routes, err := config.workerRoutes()
+	require.NoError(t, err)
+	require.Equal(t, []worker.Route{
+		{
+			Channels:        []string{worker.AnyChannel},
+			ExcludeChannels: []string{"org-kingfisher"},
+			JobTypes:        []string{worker.JobTypeDepsolve, worker.JobTypeKojiInit},
+			Pool:            "org-shared",
+		},
+		{
+			Channels: []string{"org-kingfisher"},
+			JobTypes: []string{worker.JobTypeOSBuild},
+			Arches:   []string{"x86_64"},
+			Labels:   []string{"fips"},
+			Pool:     "org-reserved",
+		},
+	}, routes)
+
+	routes, err = GetDefaultConfig().workerRoutes()
+	require.NoError(t, err)
+	require.Empty(t, routes)
+
+	config.Worker.Routes = append(config.Worker.Routes, WorkerRouteConfig{Pool: "org-shared"})
+	_, err = config.workerRoutes()
+	require.Error(t, err)
+}
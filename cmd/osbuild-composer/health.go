@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/osbuild/image-builder/pkg/reporegistry"
+
+	"github.com/osbuild/osbuild-composer/internal/auth"
+	"github.com/osbuild/osbuild-composer/internal/health"
+)
+
+// healthCheckers returns the checkers of the /healthz and /readyz endpoints.
+// /healthz only checks that the job queue can be reached, without which
+// composer can't do anything. /readyz also checks that the job queue is
+// migrated and the other dependencies of the APIs. A database which isn't
+// migrated yet takes composer out of rotation instead of restarting it.
+func (c *Composer) healthCheckers() (*health.Checker, *health.Checker) {
+	jobQueue := health.Check{
+		Name: "jobqueue",
+		Run:  c.workers.CheckJobQueue,
+	}
+	healthz := health.NewChecker(jobQueue)
+	readyz := health.NewChecker(jobQueue, health.Check{
+		Name: "jobqueue/schema",
+		Run:  c.workers.CheckJobQueueSchema,
+	}, health.Check{
+		Name: "repositories",
+		Run:  c.checkRepositories,
+	})
+
+	for _, arch := range c.repositoryArches() {
+		readyz.Add(health.Check{
+			Name: "workers/" + arch,
+			Run: func(ctx context.Context) error {
+				available, err := c.workers.WorkerAvailableForArch(arch)
+				if err != nil {
+					return err
+				}
+				if !available {
+					return fmt.Errorf("no worker for %s is registered", arch)
+				}
+				return nil
+			},
+			// workers connect to composer and scale independently,
+			// taking composer out of rotation doesn't bring them back
+			Advisory: true,
+		})
+	}
+
+	type jwks struct {
+		url, ca string
+	}
+	seen := map[jwks]bool{}
+	addJWKS := func(enabled bool, urls []string, ca string) {
+		if !enabled {
+			return
+		}
+		for _, url := range urls {
+			if seen[jwks{url, ca}] {
+				continue
+			}
+			seen[jwks{url, ca}] = true
+			readyz.Add(health.Check{
+				Name: "jwks/" + url,
+				Run: func(ctx context.Context) error {
+					return auth.CheckJWKS(ctx, url, ca)
+				},
+			})
+		}
+	}
+	addJWKS(c.config.Koji.EnableJWT, c.config.Koji.JWTKeysURLs, c.config.Koji.JWTKeysCA)
+	addJWKS(c.config.Worker.EnableJWT, c.config.Worker.JWTKeysURLs, c.config.Worker.JWTKeysCA)
+	addJWKS(c.weldrTCPListener != nil && c.config.WeldrAPI.EnableJWT, c.config.WeldrAPI.JWTKeysURLs, c.config.WeldrAPI.JWTKeysCA)
+
+	return healthz, readyz
+}
+
+// checkRepositories verifies that the repository definitions can still be
+// loaded and that at least one of their distributions is supported
+func (c *Composer) checkRepositories(ctx context.Context) error {
+	repos, err := reporegistry.New(repositoryConfigs, nil)
+	if err != nil {
+		var noRepos *reporegistry.NoReposLoadedError
+		if errors.As(err, &noRepos) && c.config.IgnoreMissingRepos {
+			return nil
+		}
+		return fmt.Errorf("error loading repository definitions: %w", err)
+	}
+	for _, name := range repos.ListDistros() {
+		if c.distros.GetDistro(name) != nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("none of the distributions with repository definitions is supported")
+}
+
+// repositoryArches returns the architectures composer has repositories for
+func (c *Composer) repositoryArches() []string {
+	if c.repos == nil {
+		return nil
+	}
+	arches := map[string]bool{}
+	for _, name := range c.repos.ListDistros() {
+		d := c.distros.GetDistro(name)
+		if d == nil {
+			continue
+		}
+		for _, arch := range d.ListArches() {
+			if repos, err := c.repos.DistroHasRepos(name, arch); err == nil && len(repos) > 0 {
+				arches[arch] = true
+			}
+		}
+	}
+	result := make([]string, 0, len(arches))
+	for arch := range arches {
+		result = append(result, arch)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -9,11 +9,10 @@ import (
 
 	"github.com/coreos/go-systemd/v22/activation"
 	"github.com/coreos/go-systemd/v22/journal"
-	"github.com/getsentry/sentry-go"
-	sentrylogrus "github.com/getsentry/sentry-go/logrus"
 	_ "github.com/osbuild/image-builder/data/repositories"
 	"github.com/osbuild/osbuild-composer/internal/common"
-	slogger "github.com/osbuild/osbuild-composer/pkg/splunk_logger"
+	"github.com/osbuild/osbuild-composer/internal/logsink"
+	"github.com/osbuild/osbuild-composer/internal/tracing"
 	"github.com/sirupsen/logrus"
 )
 
@@ -88,35 +87,41 @@ func main() {
 	dumpWriter.Close()
 
 	if config.DeploymentChannel != "" {
-		logrus.AddHook(&slogger.EnvironmentHook{Channel: config.DeploymentChannel})
+		logrus.AddHook(&logsink.EnvironmentHook{Channel: config.DeploymentChannel})
 	}
 
-	if config.SplunkHost != "" {
-		hook, err := slogger.NewSplunkHook(context.Background(), config.SplunkHost, config.SplunkPort, config.SplunkToken, "osbuild-composer")
-
+	logSinks, err := config.logSinks()
+	if err != nil {
+		logrus.Fatalf("Error setting up log sinks: %v", err)
+	}
+	glitchTip := false
+	for _, sinkConfig := range logSinks {
+		hook, closeSink, err := logsink.Open(context.Background(), sinkConfig, "osbuild-composer")
 		if err != nil {
-			panic(err)
+			logrus.Fatalf("Error setting up %s log sink: %v", sinkConfig.Type, err)
 		}
 		logrus.AddHook(hook)
+		defer closeSink()
+		glitchTip = glitchTip || sinkConfig.Type == logsink.TypeGlitchTip
 	}
-
-	if config.GlitchTipDSN != "" {
-		err = sentry.Init(sentry.ClientOptions{
-			Dsn: config.GlitchTipDSN,
-		})
-		if err != nil {
-			panic(err)
-		}
-
-		sentryhook := sentrylogrus.NewLogHookFromClient([]logrus.Level{logrus.PanicLevel,
-			logrus.FatalLevel, logrus.ErrorLevel},
-			sentry.CurrentHub().Client())
-		logrus.AddHook(sentryhook)
-
-	} else {
+	if !glitchTip {
 		logrus.Warn("GLITCHTIP_DSN not configured, skipping initializing Sentry/Glitchtip")
 	}
 
+	shutdownTracing, err := tracing.Init("osbuild-composer", tracing.Config{
+		Endpoint:    config.Tracing.Endpoint,
+		Headers:     config.Tracing.Headers,
+		SampleRatio: config.Tracing.SampleRatio,
+	})
+	if err != nil {
+		logrus.Fatalf("Error setting up tracing: %v", err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			logrus.Errorf("Error flushing the remaining spans: %v", err)
+		}
+	}()
+
 	stateDir, ok := os.LookupEnv("STATE_DIRECTORY")
 	if !ok {
 		logrus.Fatal("STATE_DIRECTORY is not set. Is the service file missing StateDirectory=?")
@@ -154,6 +159,17 @@ func main() {
 		}
 	}
 
+	if l, exists := listeners["osbuild-composer-weldr.socket"]; exists {
+		if len(l) != 1 {
+			logrus.Fatal("The osbuild-composer-weldr.socket unit is misconfigured. It should contain only one socket.")
+		}
+
+		err = composer.InitWeldrTCP(ServerCertFile, ServerKeyFile, l[0])
+		if err != nil {
+			logrus.Fatalf("Error initializing weldr API on TCP: %v", err)
+		}
+	}
+
 	if l, exists := listeners["osbuild-local-worker.socket"]; exists {
 		if len(l) != 1 {
 			logrus.Fatal("The osbuild-local-worker.socket unit is misconfigured. It should contain only one socket.")
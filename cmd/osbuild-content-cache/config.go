@@ -0,0 +1,90 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/osbuild/image-builder/pkg/datasizes"
+)
+
+type Config struct {
+	Host string
+	Port string
+
+	CacheDir string
+	// 0 means no limit
+	MaxSize int64
+
+	// serve TLS, clients need a certificate signed by TLSClientCA if set
+	TLSCert     string
+	TLSKey      string
+	TLSClientCA string
+
+	// used to fetch files, like the RepositoryMTLSConfig of the workers
+	UpstreamCA         string
+	UpstreamClientCert string
+	UpstreamClientKey  string
+	UpstreamProxy      *url.URL
+	// URL prefixes files may be fetched from, required so that the cache
+	// can't be used to fetch arbitrary URLs
+	AllowedUpstreams []string
+
+	// serve the metrics without TLS on this address if set
+	MetricsAddress string
+}
+
+func newConfigFromCmdline(args []string) (*Config, error) {
+	var config Config
+	var maxSize, upstreamProxy, allowedUpstreams string
+
+	fs := flag.NewFlagSet("content-cache", flag.ContinueOnError)
+	fs.StringVar(&config.Host, "host", "localhost", "host to listen on")
+	fs.StringVar(&config.Port, "port", "8003", "port to listen on")
+	fs.StringVar(&config.CacheDir, "cache-dir", "/var/cache/osbuild-content-cache", "directory to cache the content in")
+	fs.StringVar(&maxSize, "max-size", "", "size budget of the cache, for example \"500 GiB\"")
+	fs.StringVar(&config.TLSCert, "tls-cert", "", "certificate to serve TLS with")
+	fs.StringVar(&config.TLSKey, "tls-key", "", "key of the TLS certificate")
+	fs.StringVar(&config.TLSClientCA, "tls-client-ca", "", "CA the client certificates need to be signed by")
+	fs.StringVar(&config.UpstreamCA, "upstream-ca", "", "CA of the repositories")
+	fs.StringVar(&config.UpstreamClientCert, "upstream-client-cert", "", "client certificate for the repositories")
+	fs.StringVar(&config.UpstreamClientKey, "upstream-client-key", "", "key of the client certificate for the repositories")
+	fs.StringVar(&upstreamProxy, "upstream-proxy", "", "proxy to fetch files from the repositories with")
+	fs.StringVar(&allowedUpstreams, "allowed-upstreams", "", "comma separated URL prefixes files may be fetched from, required")
+	fs.StringVar(&config.MetricsAddress, "metrics-address", "", "address to serve the metrics on without TLS")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if (config.TLSCert == "") != (config.TLSKey == "") {
+		return nil, errors.New("-tls-cert and -tls-key need to be set together")
+	}
+	if config.TLSClientCA != "" && config.TLSCert == "" {
+		return nil, errors.New("-tls-client-ca needs -tls-cert and -tls-key")
+	}
+	if (config.UpstreamClientCert == "") != (config.UpstreamClientKey == "") {
+		return nil, errors.New("-upstream-client-cert and -upstream-client-key need to be set together")
+	}
+
+	if maxSize != "" {
+		size, err := datasizes.Parse(maxSize)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -max-size: %w", err)
+		}
+		config.MaxSize = int64(size)
+	}
+	if upstreamProxy != "" {
+		u, err := url.Parse(upstreamProxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -upstream-proxy: %w", err)
+		}
+		config.UpstreamProxy = u
+	}
+	if allowedUpstreams == "" {
+		return nil, errors.New("-allowed-upstreams is required")
+	}
+	config.AllowedUpstreams = strings.Split(allowedUpstreams, ",")
+	return &config, nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigFromCmdline(t *testing.T) {
+	config, err := newConfigFromCmdline([]string{
+		"-cache-dir", "/srv/cache",
+		"-max-size", "2 GiB",
+		"-upstream-proxy", "http://proxy.example.com:3128",
+		"-allowed-upstreams", "https://cdn.example.com/,https://mirror.example.com/",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &Config{
+		Host:             "localhost",
+		Port:             "8003",
+		CacheDir:         "/srv/cache",
+		MaxSize:          2 * 1024 * 1024 * 1024,
+		UpstreamProxy:    &url.URL{Scheme: "http", Host: "proxy.example.com:3128"},
+		AllowedUpstreams: []string{"https://cdn.example.com/", "https://mirror.example.com/"},
+	}, config)
+}
+
+func TestConfigFromCmdlineErrors(t *testing.T) {
+	for _, tc := range []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"-tls-cert", "cert.pem"}, "-tls-cert and -tls-key need to be set together"},
+		{[]string{"-tls-client-ca", "ca.pem"}, "-tls-client-ca needs -tls-cert and -tls-key"},
+		{[]string{"-upstream-client-key", "key.pem"}, "-upstream-client-cert and -upstream-client-key need to be set together"},
+		{[]string{"-cache-dir", "/srv/cache"}, "-allowed-upstreams is required"},
+	} {
+		_, err := newConfigFromCmdline(tc.args)
+		assert.EqualError(t, err, tc.err)
+	}
+
+	_, err := newConfigFromCmdline([]string{"-max-size", "huge"})
+	assert.ErrorContains(t, err, "invalid -max-size")
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
+
+	"github.com/osbuild/osbuild-composer/internal/contentcache"
+)
+
+func newTLSConfig(config *Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+	if config.TLSClientCA != "" {
+		caCertPEM, err := os.ReadFile(config.TLSClientCA)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read client CA: %w", err)
+		}
+		clientCAs := x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(caCertPEM) {
+			return nil, fmt.Errorf("cannot append client CA %s", config.TLSClientCA)
+		}
+		tlsConfig.ClientCAs = clientCAs
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsConfig, nil
+}
+
+func newCache(config *Config) (*contentcache.Cache, error) {
+	fileClient, err := contentcache.NewUpstreamClient(config.UpstreamCA, config.UpstreamClientCert, config.UpstreamClientKey, config.UpstreamProxy)
+	if err != nil {
+		return nil, err
+	}
+	return contentcache.New(contentcache.Config{
+		Dir:              config.CacheDir,
+		MaxSize:          config.MaxSize,
+		FileClient:       fileClient,
+		AllowedUpstreams: config.AllowedUpstreams,
+	})
+}
+
+func run(ctx context.Context, args []string, logger *logrus.Logger) error {
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	config, err := newConfigFromCmdline(args)
+	if err != nil {
+		return err
+	}
+
+	cache, err := newCache(config)
+	if err != nil {
+		return err
+	}
+
+	servers := []*http.Server{
+		{
+			Addr:              net.JoinHostPort(config.Host, config.Port),
+			Handler:           cache,
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+	}
+	if config.TLSCert != "" {
+		servers[0].TLSConfig, err = newTLSConfig(config)
+		if err != nil {
+			return err
+		}
+	}
+	if config.MetricsAddress != "" {
+		metricsMux := http.NewServeMux()
+		metricsMux.Handle("/metrics", promhttp.Handler())
+		servers = append(servers, &http.Server{
+			Addr:              config.MetricsAddress,
+			Handler:           metricsMux,
+			ReadHeaderTimeout: 10 * time.Second,
+		})
+	}
+
+	for i, srv := range servers {
+		go func(srv *http.Server, useTLS bool) {
+			logger.Infof("listening on %s", srv.Addr)
+			var err error
+			if useTLS {
+				err = srv.ListenAndServeTLS(config.TLSCert, config.TLSKey)
+			} else {
+				err = srv.ListenAndServe()
+			}
+			if err != nil && err != http.ErrServerClosed {
+				logger.Errorf("error listening and serving: %v", err)
+				cancel()
+			}
+		}(srv, i == 0 && config.TLSCert != "")
+	}
+
+	<-ctx.Done()
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer shutdownCancel()
+	for _, srv := range servers {
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			logger.Errorf("error shutting down http server: %v", err)
+		}
+	}
+	return nil
+}
+
+func main() {
+	logger := logrus.StandardLogger()
+	if err := run(context.Background(), os.Args[1:], logger); err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type buildPhase string
+
+const (
+	phaseIdle      buildPhase = "idle"
+	phasePreparing buildPhase = "preparing"
+	phaseBuilding  buildPhase = "building"
+	phaseCanceling buildPhase = "canceling"
+	phaseDone      buildPhase = "done"
+	phaseFailed    buildPhase = "failed"
+)
+
+// buildState tracks the build the executor is running. Finished builds are
+// tracked by their result files, so the state only covers the running one and
+// the ID of the last build.
+type buildState struct {
+	config *Config
+
+	mu sync.Mutex
+	// ID of the running or last build, until it was released
+	id       string
+	active   bool
+	phase    buildPhase
+	cmd      *exec.Cmd
+	canceled bool
+	done     chan struct{}
+	builds   int
+}
+
+func newBuildState(config *Config) *buildState {
+	return &buildState{
+		config: config,
+		phase:  phaseIdle,
+	}
+}
+
+// start creates the build dir for a new build and returns it together with
+// the ID of the build. If the executor accepts several builds, the previous
+// build needs to be released first, so its client can still fetch the
+// results.
+func (bs *buildState) start() (string, string, error) {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	if bs.active {
+		return "", "", ErrAlreadyBuilding
+	}
+	if bs.config.MultiBuild {
+		if bs.id != "" {
+			return "", "", ErrAlreadyBuilding
+		}
+		// leftovers of an executor which was restarted
+		if err := cleanupBuild(bs.config); err != nil {
+			return "", "", err
+		}
+	}
+
+	buildDir, err := createBuildDir(bs.config)
+	if err != nil {
+		return "", "", err
+	}
+
+	bs.id = uuid.New().String()
+	bs.active = true
+	bs.phase = phasePreparing
+	bs.cmd = nil
+	bs.canceled = false
+	bs.done = make(chan struct{})
+	bs.builds++
+	return buildDir, bs.id, nil
+}
+
+// isBuild returns whether id is the ID of the running or last build. If the
+// executor only accepts a single build, the ID is optional.
+func (bs *buildState) isBuild(id string) bool {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	return bs.matches(id)
+}
+
+func (bs *buildState) matches(id string) bool {
+	if id == "" {
+		return !bs.config.MultiBuild
+	}
+	return id == bs.id
+}
+
+// running registers the osbuild process of the build so it can be canceled.
+// It returns false if the build was canceled before osbuild started.
+func (bs *buildState) running(cmd *exec.Cmd) bool {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	if bs.canceled {
+		return false
+	}
+	bs.phase = phaseBuilding
+	bs.cmd = cmd
+	return true
+}
+
+// finish marks the running build as finished, the result files need to be
+// written before
+func (bs *buildState) finish() {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	bs.active = false
+	bs.phase = phaseIdle
+	bs.cmd = nil
+	close(bs.done)
+}
+
+// release kills osbuild if the build is still running and waits for it to
+// stop. If the executor accepts several builds, the build dir and the result
+// are removed so it is ready for the next build, otherwise the executor stays
+// used up.
+func (bs *buildState) release(id string) error {
+	bs.mu.Lock()
+	if !bs.matches(id) {
+		bs.mu.Unlock()
+		return ErrUnknownBuild
+	}
+	if bs.active {
+		bs.canceled = true
+		bs.phase = phaseCanceling
+		if bs.cmd != nil && bs.cmd.Process != nil {
+			// the build handler reaps the process
+			_ = bs.cmd.Process.Kill()
+		}
+		done := bs.done
+		bs.mu.Unlock()
+		<-done
+		bs.mu.Lock()
+	}
+	defer bs.mu.Unlock()
+
+	// the build might have been released in the meantime
+	if !bs.config.MultiBuild || bs.id != id {
+		return nil
+	}
+	if err := cleanupBuild(bs.config); err != nil {
+		return err
+	}
+	bs.id = ""
+	return nil
+}
+
+// isCanceled returns whether the running build was canceled
+func (bs *buildState) isCanceled() bool {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	return bs.canceled
+}
+
+type buildStatus struct {
+	Phase  buildPhase `json:"phase"`
+	Builds int        `json:"builds"`
+}
+
+func (bs *buildState) status() buildStatus {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	status := buildStatus{
+		Phase:  bs.phase,
+		Builds: bs.builds,
+	}
+	if !bs.active {
+		buildResult := newBuildResult(bs.config)
+		switch {
+		case buildResult.Good():
+			status.Phase = phaseDone
+		case buildResult.Bad():
+			status.Phase = phaseFailed
+		}
+	}
+	return status
+}
+
+// cleanupBuild removes the build dir and the result of the last build
+func cleanupBuild(config *Config) error {
+	buildResult := newBuildResult(config)
+	for _, path := range []string{buildResult.resultGood, buildResult.resultBad, filepath.Join(config.BuildDirBase, "build")} {
+		if err := os.RemoveAll(path); err != nil {
+			return fmt.Errorf("cannot clean up build: %w", err)
+		}
+	}
+	return nil
+}
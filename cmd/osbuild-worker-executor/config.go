@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"flag"
 )
 
@@ -9,6 +10,17 @@ type Config struct {
 	Port string
 
 	BuildDirBase string
+
+	// serve TLS, clients need a certificate signed by TLSClientCA if set
+	TLSCert     string
+	TLSKey      string
+	TLSClientCA string
+	// clients need to send the token in this file as bearer token if set
+	TokenFile string
+
+	// accept a new build once the previous one finished, instead of only
+	// a single build per executor
+	MultiBuild bool
 }
 
 func newConfigFromCmdline(args []string) (*Config, error) {
@@ -18,8 +30,20 @@ func newConfigFromCmdline(args []string) (*Config, error) {
 	fs.StringVar(&config.Host, "host", "localhost", "host to listen on")
 	fs.StringVar(&config.Port, "port", "8001", "port to listen on")
 	fs.StringVar(&config.BuildDirBase, "build-path", "/var/tmp/worker-executor", "base dir to run the builds in")
+	fs.StringVar(&config.TLSCert, "tls-cert", "", "certificate to serve TLS with")
+	fs.StringVar(&config.TLSKey, "tls-key", "", "key of the TLS certificate")
+	fs.StringVar(&config.TLSClientCA, "tls-client-ca", "", "CA the client certificates need to be signed by")
+	fs.StringVar(&config.TokenFile, "token-file", "", "file with the token clients need to authenticate with")
+	fs.BoolVar(&config.MultiBuild, "multi-build", false, "accept several sequential builds")
 	if err := fs.Parse(args); err != nil {
 		return nil, err
 	}
+
+	if (config.TLSCert == "") != (config.TLSKey == "") {
+		return nil, errors.New("-tls-cert and -tls-key need to be set together")
+	}
+	if config.TLSClientCA != "" && config.TLSCert == "" {
+		return nil, errors.New("-tls-client-ca needs -tls-cert and -tls-key")
+	}
 	return &config, nil
 }
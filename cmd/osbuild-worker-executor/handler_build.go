@@ -26,6 +26,7 @@ var (
 
 var (
 	ErrAlreadyBuilding = errors.New("build already starte")
+	ErrUnknownBuild    = errors.New("unknown build")
 )
 
 type writeFlusher struct {
@@ -41,7 +42,7 @@ func (wf *writeFlusher) Write(p []byte) (n int, err error) {
 	return n, err
 }
 
-func runOSBuild(logger *logrus.Logger, buildDir string, control *controlJSON, output io.Writer) (string, error) {
+func runOSBuild(logger *logrus.Logger, state *buildState, buildDir string, control *controlJSON, output io.Writer) (string, error) {
 	manifest, err := os.ReadFile(filepath.Join(buildDir, "manifest.json"))
 	if err != nil {
 		return "", fmt.Errorf("cannot read manifest file: %w", err)
@@ -81,6 +82,11 @@ func runOSBuild(logger *logrus.Logger, buildDir string, control *controlJSON, ou
 		return "", err
 	}
 	wPipe.Close()
+	if !state.running(cmd) {
+		// canceled while the input was unpacked, cmd.Wait() below
+		// reports the killed process
+		_ = cmd.Process.Kill()
+	}
 
 	flusher, ok := output.(http.Flusher)
 	if !ok {
@@ -170,7 +176,7 @@ func createBuildDir(config *Config) (string, error) {
 	buildDirBase := config.BuildDirBase
 
 	// we could create a per-build dir here but the goal is to
-	// only have a single build only so we don't bother
+	// only have a single build at a time so we don't bother
 	if err := os.MkdirAll(buildDirBase, 0700); err != nil {
 		return "", fmt.Errorf("cannot create build base dir: %v", err)
 	}
@@ -270,14 +276,35 @@ func handleIncludedSources(atar *tar.Reader, buildDir string) error {
 
 // test for real via:
 // curl -o - --data-binary "@./test.tar" -H "Content-Type: application/x-tar"  -X POST http://localhost:8001/api/v1/build
-func handleBuild(logger *logrus.Logger, config *Config) http.Handler {
+//
+// and cancel or release the build with the ID from the X-Build-Id header of
+// the response via:
+// curl -X DELETE -H "X-Build-Id: <id>" http://localhost:8001/api/v1/build
+func handleBuild(logger *logrus.Logger, config *Config, state *buildState) http.Handler {
 	return http.HandlerFunc(
 		func(w http.ResponseWriter, r *http.Request) {
 			logger.Debugf("handlerBuild called on %s", r.URL.Path)
 			defer r.Body.Close()
 
-			if r.Method != http.MethodPost {
-				http.Error(w, "build endpoint only supports POST", http.StatusMethodNotAllowed)
+			switch r.Method {
+			case http.MethodPost:
+				// start a build below
+			case http.MethodDelete:
+				err := state.release(r.Header.Get(osbuildexecutor.BuildIDHeader))
+				if errors.Is(err, ErrUnknownBuild) {
+					http.Error(w, "unknown build", http.StatusNotFound)
+					return
+				}
+				if err != nil {
+					logger.Errorf("cannot release build: %v", err)
+					http.Error(w, fmt.Sprintf("cannot release build: %v", err), http.StatusInternalServerError)
+					return
+				}
+				logger.Info("build released")
+				w.WriteHeader(http.StatusNoContent)
+				return
+			default:
+				http.Error(w, "build endpoint only supports POST and DELETE", http.StatusMethodNotAllowed)
 				return
 			}
 
@@ -296,7 +323,7 @@ func handleBuild(logger *logrus.Logger, config *Config) http.Handler {
 				return
 			}
 
-			buildDir, err := createBuildDir(config)
+			buildDir, buildID, err := state.start()
 			if err != nil {
 				logger.Error(err)
 				if err == ErrAlreadyBuilding {
@@ -306,6 +333,10 @@ func handleBuild(logger *logrus.Logger, config *Config) http.Handler {
 				}
 				return
 			}
+			defer state.finish()
+			// the client needs the ID to fetch the result and to release
+			// the build, even if it fails before osbuild runs
+			w.Header().Set(osbuildexecutor.BuildIDHeader, buildID)
 
 			// manifest.json is the osbuild input
 			if err := handleManifestJSON(atar, buildDir); err != nil {
@@ -324,7 +355,11 @@ func handleBuild(logger *logrus.Logger, config *Config) http.Handler {
 
 			// run osbuild and stream the output to the client
 			buildResult := newBuildResult(config)
-			_, err = runOSBuild(logger, buildDir, control, w)
+			_, err = runOSBuild(logger, state, buildDir, control, w)
+			if state.isCanceled() {
+				logger.Info("build was canceled")
+				return
+			}
 			if werr := buildResult.Mark(err); werr != nil {
 				logger.Errorf("cannot write result file %v", werr)
 			}
@@ -17,6 +17,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	main "github.com/osbuild/osbuild-composer/cmd/osbuild-worker-executor"
+	"github.com/osbuild/osbuild-composer/internal/osbuildexecutor"
 )
 
 func TestBuildMustPOST(t *testing.T) {
@@ -343,3 +344,222 @@ echo osbuild failure on stdout
 	assert.Contains(t, string(body), "osbuild failure on stdout")
 	assert.Contains(t, loggerHook.LastEntry().Message, "unable to move result file to output directory:")
 }
+
+// releaseBuild cancels or releases the build with the given ID and returns
+// the status code of the response
+func releaseBuild(t *testing.T, baseURL, buildID string) int {
+	req, err := http.NewRequest(http.MethodDelete, baseURL+"api/v1/build", nil)
+	assert.NoError(t, err)
+	if buildID != "" {
+		req.Header.Set(osbuildexecutor.BuildIDHeader, buildID)
+	}
+	rsp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	defer rsp.Body.Close()
+	return rsp.StatusCode
+}
+
+// getResult fetches a file of the result of the build with the given ID
+func getResult(t *testing.T, baseURL, path, buildID string) (int, string) {
+	req, err := http.NewRequest(http.MethodGet, baseURL+"api/v1/result/"+path, nil)
+	assert.NoError(t, err)
+	req.Header.Set(osbuildexecutor.BuildIDHeader, buildID)
+	rsp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	defer rsp.Body.Close()
+	body, err := io.ReadAll(rsp.Body)
+	assert.NoError(t, err)
+	return rsp.StatusCode, string(body)
+}
+
+func TestBuildCancel(t *testing.T) {
+	baseURL, baseBuildDir, _ := runTestServer(t, "-multi-build")
+	endpoint := baseURL + "api/v1/build"
+
+	restore := main.MockOsbuildBinary(t, `#!/bin/sh
+>&3 echo '^^{"message": "started"}'
+exec sleep 60
+`)
+	defer restore()
+
+	buf := makeTestPost(t, `{"exports": ["tree"]}`, `{"fake": "manifest"}`)
+	rsp, err := http.Post(endpoint, "application/x-tar", buf)
+	assert.NoError(t, err)
+	defer rsp.Body.Close()
+	assert.Equal(t, http.StatusCreated, rsp.StatusCode)
+	buildID := rsp.Header.Get(osbuildexecutor.BuildIDHeader)
+	assert.NotEmpty(t, buildID)
+
+	// wait for osbuild to run
+	line, err := bufio.NewReader(rsp.Body).ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, "^^{\"message\": \"started\"}\n", line)
+	assert.Equal(t, "building", getStatus(t, baseURL)["phase"])
+
+	// the build can only be canceled with its ID
+	assert.Equal(t, http.StatusNotFound, releaseBuild(t, baseURL, ""))
+	assert.Equal(t, http.StatusNotFound, releaseBuild(t, baseURL, "other-build"))
+	assert.Equal(t, "building", getStatus(t, baseURL)["phase"])
+
+	start := time.Now()
+	assert.Equal(t, http.StatusNoContent, releaseBuild(t, baseURL, buildID))
+	assert.Less(t, time.Since(start), 30*time.Second)
+
+	// the build dir is gone and the executor is ready for a new build
+	assert.NoDirExists(t, filepath.Join(baseBuildDir, "build"))
+	assert.Equal(t, "idle", getStatus(t, baseURL)["phase"])
+
+	restore = main.MockOsbuildBinary(t, fmt.Sprintf(`#!/bin/sh
+mkdir -p %[1]s/build/output/image
+`, baseBuildDir))
+	defer restore()
+	buf = makeTestPost(t, `{"exports": ["tree"]}`, `{"fake": "manifest"}`)
+	rsp, err = http.Post(endpoint, "application/x-tar", buf)
+	assert.NoError(t, err)
+	defer rsp.Body.Close()
+	assert.Equal(t, http.StatusCreated, rsp.StatusCode)
+}
+
+func TestBuildRelease(t *testing.T) {
+	baseURL, buildBaseDir, _ := runTestServer(t, "-multi-build")
+
+	restore := main.MockOsbuildBinary(t, fmt.Sprintf(`#!/bin/sh
+mkdir -p %[1]s/build/output/image
+`, buildBaseDir))
+	defer restore()
+
+	buf := makeTestPost(t, `{"exports": ["tree"]}`, `{"fake": "manifest"}`)
+	rsp, err := http.Post(baseURL+"api/v1/build", "application/x-tar", buf)
+	assert.NoError(t, err)
+	defer rsp.Body.Close()
+	assert.Equal(t, http.StatusCreated, rsp.StatusCode)
+	_, err = io.ReadAll(rsp.Body)
+	assert.NoError(t, err)
+	buildID := rsp.Header.Get(osbuildexecutor.BuildIDHeader)
+	assert.FileExists(t, filepath.Join(buildBaseDir, "result.good"))
+
+	// releasing a finished build removes its result
+	assert.Equal(t, http.StatusNoContent, releaseBuild(t, baseURL, buildID))
+	assert.NoFileExists(t, filepath.Join(buildBaseDir, "result.good"))
+	assert.NoDirExists(t, filepath.Join(buildBaseDir, "build"))
+	assert.Equal(t, "idle", getStatus(t, baseURL)["phase"])
+
+	// and it can't be released twice
+	assert.Equal(t, http.StatusNotFound, releaseBuild(t, baseURL, buildID))
+}
+
+func TestBuildCancelSingleBuild(t *testing.T) {
+	baseURL, baseBuildDir, _ := runTestServer(t)
+	endpoint := baseURL + "api/v1/build"
+
+	restore := main.MockOsbuildBinary(t, `#!/bin/sh
+>&3 echo '^^{"message": "started"}'
+exec sleep 60
+`)
+	defer restore()
+
+	buf := makeTestPost(t, `{"exports": ["tree"]}`, `{"fake": "manifest"}`)
+	rsp, err := http.Post(endpoint, "application/x-tar", buf)
+	assert.NoError(t, err)
+	defer rsp.Body.Close()
+	assert.Equal(t, http.StatusCreated, rsp.StatusCode)
+	line, err := bufio.NewReader(rsp.Body).ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, "^^{\"message\": \"started\"}\n", line)
+
+	// there is only a single build, the ID is optional
+	assert.Equal(t, http.StatusNoContent, releaseBuild(t, baseURL, ""))
+
+	// osbuild is stopped, but the executor doesn't accept another build
+	assert.DirExists(t, filepath.Join(baseBuildDir, "build"))
+	buf = makeTestPost(t, `{"exports": ["tree"]}`, `{"fake": "manifest"}`)
+	rsp, err = http.Post(endpoint, "application/x-tar", buf)
+	assert.NoError(t, err)
+	defer rsp.Body.Close()
+	assert.Equal(t, http.StatusConflict, rsp.StatusCode)
+}
+
+func TestBuildMultipleSequentialBuilds(t *testing.T) {
+	baseURL, buildDir, _ := runTestServer(t, "-multi-build")
+	endpoint := baseURL + "api/v1/build"
+
+	for i := 1; i <= 2; i++ {
+		restore := main.MockOsbuildBinary(t, fmt.Sprintf(`#!/bin/sh
+mkdir -p %[1]s/build/output/image
+echo "fake-build-result-%[2]d" > %[1]s/build/output/image/disk.img
+`, buildDir, i))
+		defer restore()
+
+		buf := makeTestPost(t, `{"exports": ["tree"]}`, `{"fake": "manifest"}`)
+		rsp, err := http.Post(endpoint, "application/x-tar", buf)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, rsp.StatusCode)
+		_, err = io.ReadAll(rsp.Body)
+		assert.NoError(t, err)
+		rsp.Body.Close()
+		buildID := rsp.Header.Get(osbuildexecutor.BuildIDHeader)
+
+		status, body := getResult(t, baseURL, "image/disk.img", buildID)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, fmt.Sprintf("fake-build-result-%d\n", i), body)
+		assert.Equal(t, "done", getStatus(t, baseURL)["phase"])
+		assert.Equal(t, http.StatusNoContent, releaseBuild(t, baseURL, buildID))
+	}
+
+	status := getStatus(t, baseURL)
+	assert.Equal(t, "idle", status["phase"])
+	assert.Equal(t, 2.0, status["builds"])
+}
+
+func TestBuildClientsTakingTurns(t *testing.T) {
+	baseURL, buildDir, _ := runTestServer(t, "-multi-build")
+	endpoint := baseURL + "api/v1/build"
+
+	build := func(result string) *http.Response {
+		restore := main.MockOsbuildBinary(t, fmt.Sprintf(`#!/bin/sh
+mkdir -p %[1]s/build/output/image
+echo "%[2]s" > %[1]s/build/output/image/disk.img
+`, buildDir, result))
+		defer restore()
+
+		buf := makeTestPost(t, `{"exports": ["tree"]}`, `{"fake": "manifest"}`)
+		rsp, err := http.Post(endpoint, "application/x-tar", buf)
+		assert.NoError(t, err)
+		_, err = io.ReadAll(rsp.Body)
+		assert.NoError(t, err)
+		rsp.Body.Close()
+		return rsp
+	}
+
+	rsp := build("first")
+	assert.Equal(t, http.StatusCreated, rsp.StatusCode)
+	first := rsp.Header.Get(osbuildexecutor.BuildIDHeader)
+
+	// the second client has to wait until the first one fetched its result
+	rsp = build("second")
+	assert.Equal(t, http.StatusConflict, rsp.StatusCode)
+	status, _ := getResult(t, baseURL, "image/disk.img", "")
+	assert.Equal(t, http.StatusNotFound, status)
+	status, _ = getResult(t, baseURL, "image/disk.img", "other-build")
+	assert.Equal(t, http.StatusNotFound, status)
+
+	status, body := getResult(t, baseURL, "image/disk.img", first)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "first\n", body)
+	assert.Equal(t, http.StatusNoContent, releaseBuild(t, baseURL, first))
+
+	rsp = build("second")
+	assert.Equal(t, http.StatusCreated, rsp.StatusCode)
+	second := rsp.Header.Get(osbuildexecutor.BuildIDHeader)
+	assert.NotEqual(t, first, second)
+
+	// the first client can neither see nor release the build of the second
+	status, _ = getResult(t, baseURL, "image/disk.img", first)
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, http.StatusNotFound, releaseBuild(t, baseURL, first))
+
+	status, body = getResult(t, baseURL, "image/disk.img", second)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "second\n", body)
+	assert.Equal(t, http.StatusNoContent, releaseBuild(t, baseURL, second))
+}
@@ -8,9 +8,11 @@ import (
 	"path/filepath"
 
 	"github.com/sirupsen/logrus"
+
+	"github.com/osbuild/osbuild-composer/internal/osbuildexecutor"
 )
 
-func handleLog(logger *logrus.Logger, config *Config) http.Handler {
+func handleLog(logger *logrus.Logger, config *Config, state *buildState) http.Handler {
 	return http.HandlerFunc(
 		func(w http.ResponseWriter, r *http.Request) {
 			logger.Debugf("handlerLog called on %s", r.URL.Path)
@@ -18,6 +20,10 @@ func handleLog(logger *logrus.Logger, config *Config) http.Handler {
 				http.Error(w, "result endpoint only supports Get", http.StatusMethodNotAllowed)
 				return
 			}
+			if !state.isBuild(r.Header.Get(osbuildexecutor.BuildIDHeader)) {
+				http.Error(w, "unknown build", http.StatusNotFound)
+				return
+			}
 
 			var f *os.File
 			var err error
@@ -7,9 +7,11 @@ import (
 	"path/filepath"
 
 	"github.com/sirupsen/logrus"
+
+	"github.com/osbuild/osbuild-composer/internal/osbuildexecutor"
 )
 
-func handleResult(logger *logrus.Logger, config *Config) http.Handler {
+func handleResult(logger *logrus.Logger, config *Config, state *buildState) http.Handler {
 	return http.HandlerFunc(
 		func(w http.ResponseWriter, r *http.Request) {
 			logger.Debugf("handlerResult called on %s", r.URL.Path)
@@ -17,6 +19,10 @@ func handleResult(logger *logrus.Logger, config *Config) http.Handler {
 				http.Error(w, "result endpoint only supports Get", http.StatusMethodNotAllowed)
 				return
 			}
+			if !state.isBuild(r.Header.Get(osbuildexecutor.BuildIDHeader)) {
+				http.Error(w, "unknown build", http.StatusNotFound)
+				return
+			}
 			buildResult := newBuildResult(config)
 			switch {
 			case buildResult.Bad():
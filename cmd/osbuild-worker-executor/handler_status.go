@@ -0,0 +1,25 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+)
+
+func handleStatus(logger *logrus.Logger, state *buildState) http.Handler {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			logger.Debugf("handlerStatus called on %s", r.URL.Path)
+			if r.Method != http.MethodGet {
+				http.Error(w, "status endpoint only supports Get", http.StatusMethodNotAllowed)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			if err := json.NewEncoder(w).Encode(state.status()); err != nil {
+				logger.Errorf("Unable to write status to response: %v", err)
+			}
+		},
+	)
+}
@@ -0,0 +1,50 @@
+package main_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getStatus(t *testing.T, baseURL string) map[string]interface{} {
+	rsp, err := http.Get(baseURL + "api/v1/status")
+	require.NoError(t, err)
+	defer rsp.Body.Close()
+	require.Equal(t, http.StatusOK, rsp.StatusCode)
+
+	var status map[string]interface{}
+	require.NoError(t, json.NewDecoder(rsp.Body).Decode(&status))
+	return status
+}
+
+func TestStatusIdle(t *testing.T) {
+	baseURL, _, _ := runTestServer(t)
+
+	assert.Equal(t, map[string]interface{}{"phase": "idle", "builds": 0.0}, getStatus(t, baseURL))
+}
+
+func TestStatusFinished(t *testing.T) {
+	baseURL, buildBaseDir, _ := runTestServer(t)
+
+	simulateBuildResult(t, buildBaseDir, "result.good", "fake-log")
+	assert.Equal(t, "done", getStatus(t, baseURL)["phase"])
+}
+
+func TestStatusFailed(t *testing.T) {
+	baseURL, buildBaseDir, _ := runTestServer(t)
+
+	simulateBuildResult(t, buildBaseDir, "result.bad", "fake-log")
+	assert.Equal(t, "failed", getStatus(t, baseURL)["phase"])
+}
+
+func TestStatusMustGET(t *testing.T) {
+	baseURL, _, _ := runTestServer(t)
+
+	rsp, err := http.Post(baseURL+"api/v1/status", "application/json", nil)
+	require.NoError(t, err)
+	defer rsp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, rsp.StatusCode)
+}
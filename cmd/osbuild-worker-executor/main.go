@@ -2,11 +2,15 @@ package main
 
 import (
 	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"sync"
 	"time"
 
@@ -16,15 +20,52 @@ import (
 // based on the excellent post
 // https://grafana.com/blog/2024/02/09/how-i-write-http-services-in-go-after-13-years/
 
-func newServer(logger *logrus.Logger, config *Config) http.Handler {
+func newServer(logger *logrus.Logger, config *Config, token string) http.Handler {
 	mux := http.NewServeMux()
-	addRoutes(mux, logger, config)
+	addRoutes(mux, logger, config, newBuildState(config))
 	var handler http.Handler = mux
 	// todo: consider centralize logginer here?
 	//handler = loggingMiddleware(handler)
+	if token != "" {
+		handler = tokenMiddleware(logger, token, handler)
+	}
 	return handler
 }
 
+// tokenMiddleware rejects requests without the shared token as bearer token
+func tokenMiddleware(logger *logrus.Logger, token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				logger.Warnf("unauthorized request to %s from %s", r.URL.Path, r.RemoteAddr)
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		},
+	)
+}
+
+func newTLSConfig(config *Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+	if config.TLSClientCA != "" {
+		caCertPEM, err := os.ReadFile(config.TLSClientCA)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read client CA: %w", err)
+		}
+		clientCAs := x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(caCertPEM) {
+			return nil, fmt.Errorf("cannot append client CA %s", config.TLSClientCA)
+		}
+		tlsConfig.ClientCAs = clientCAs
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsConfig, nil
+}
+
 func run(ctx context.Context, args []string, getenv func(string) string, logger *logrus.Logger) error {
 	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
 	defer cancel()
@@ -34,15 +75,39 @@ func run(ctx context.Context, args []string, getenv func(string) string, logger
 		return err
 	}
 
-	srv := newServer(logger, config)
+	var token string
+	if config.TokenFile != "" {
+		data, err := os.ReadFile(config.TokenFile)
+		if err != nil {
+			return fmt.Errorf("cannot read token: %w", err)
+		}
+		token = strings.TrimSpace(string(data))
+		if token == "" {
+			return fmt.Errorf("token file %s is empty", config.TokenFile)
+		}
+	}
+
+	srv := newServer(logger, config, token)
 	httpServer := &http.Server{
 		Addr:              net.JoinHostPort(config.Host, config.Port),
 		Handler:           srv,
 		ReadHeaderTimeout: 10 * time.Second,
 	}
+	if config.TLSCert != "" {
+		httpServer.TLSConfig, err = newTLSConfig(config)
+		if err != nil {
+			return err
+		}
+	}
 	go func() {
 		logger.Printf("listening on %s\n", httpServer.Addr)
-		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		var err error
+		if config.TLSCert != "" {
+			err = httpServer.ListenAndServeTLS(config.TLSCert, config.TLSKey)
+		} else {
+			err = httpServer.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			fmt.Fprintf(os.Stderr, "error listening and serving: %s\n", err)
 		}
 	}()
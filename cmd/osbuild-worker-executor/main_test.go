@@ -19,8 +19,7 @@ import (
 
 const defaultTimeout = 5 * time.Second
 
-func waitReady(ctx context.Context, timeout time.Duration, endpoint string) error {
-	client := http.Client{}
+func waitReady(ctx context.Context, client *http.Client, timeout time.Duration, endpoint string) error {
 	startTime := time.Now()
 	for {
 		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
@@ -29,14 +28,12 @@ func waitReady(ctx context.Context, timeout time.Duration, endpoint string) erro
 		}
 
 		resp, err := client.Do(req)
-		if err != nil {
-			continue
-		}
-		if resp.StatusCode == http.StatusOK {
+		if err == nil {
 			resp.Body.Close()
-			return nil
+			if resp.StatusCode == http.StatusOK {
+				return nil
+			}
 		}
-		resp.Body.Close()
 
 		select {
 		case <-ctx.Done():
@@ -64,13 +61,19 @@ func getFreePort() (int, error) {
 	return addr.Port, nil
 }
 
-func runTestServer(t *testing.T) (baseURL, buildBaseDir string, loggerHook *logrusTest.Hook) {
+func runTestServer(t *testing.T, extraArgs ...string) (baseURL, buildBaseDir string, loggerHook *logrusTest.Hook) {
+	return runTestServerWithClient(t, "http", &http.Client{}, extraArgs...)
+}
+
+// runTestServerWithClient runs the executor and waits for it to answer the
+// client
+func runTestServerWithClient(t *testing.T, scheme string, client *http.Client, extraArgs ...string) (baseURL, buildBaseDir string, loggerHook *logrusTest.Hook) {
 	host := "localhost"
 	port, err := getFreePort()
 	assert.NoError(t, err, "failed to find a free port on localhost")
 
 	buildBaseDir = t.TempDir()
-	baseURL = fmt.Sprintf("http://%s:%d/", host, port)
+	baseURL = fmt.Sprintf("%s://%s:%d/", scheme, host, port)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	t.Cleanup(cancel)
@@ -83,11 +86,12 @@ func runTestServer(t *testing.T) (baseURL, buildBaseDir string, loggerHook *logr
 		"-port", strconv.Itoa(port),
 		"-build-path", buildBaseDir,
 	}
+	args = append(args, extraArgs...)
 	go func() {
 		_ = main.Run(ctx, args, os.Getenv, logger)
 	}()
 
-	err = waitReady(ctx, defaultTimeout, baseURL)
+	err = waitReady(ctx, client, defaultTimeout, baseURL)
 	assert.NoError(t, err)
 
 	return baseURL, buildBaseDir, loggerHook
@@ -6,9 +6,10 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func addRoutes(mux *http.ServeMux, logger *logrus.Logger, config *Config) {
-	mux.Handle("/api/v1/build", handleBuild(logger, config))
-	mux.Handle("/api/v1/result/", http.StripPrefix("/api/v1/result/", handleResult(logger, config)))
-	mux.Handle("/api/v1/log", http.StripPrefix("/api/v1/log", handleLog(logger, config)))
+func addRoutes(mux *http.ServeMux, logger *logrus.Logger, config *Config, state *buildState) {
+	mux.Handle("/api/v1/build", handleBuild(logger, config, state))
+	mux.Handle("/api/v1/status", handleStatus(logger, state))
+	mux.Handle("/api/v1/result/", http.StripPrefix("/api/v1/result/", handleResult(logger, config, state)))
+	mux.Handle("/api/v1/log", http.StripPrefix("/api/v1/log", handleLog(logger, config, state)))
 	mux.Handle("/", handleRoot(logger, config))
 }
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/osbuild/image-builder/pkg/cloud/azure"
+	"github.com/osbuild/image-builder/pkg/cloud/gcp"
+	"github.com/osbuild/osbuild-composer/internal/cloud/awscloud"
+	"github.com/osbuild/osbuild-composer/internal/cloudcredentials"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+)
+
+// gcpDefaultCredentialsMu serializes the creation of GCP clients from the
+// Application Default Credentials. Impersonating a service account points
+// them to a file of its own for a moment.
+var gcpDefaultCredentialsMu sync.Mutex
+
+// resolveCloudCredentials returns the cloud credentials with id of the tenant
+// of the job, or nil if id is nil
+func resolveCloudCredentials(job worker.Job, id *uuid.UUID, cloud cloudcredentials.Type) (*cloudcredentials.Credentials, error) {
+	if id == nil {
+		return nil, nil
+	}
+	creds, err := job.CloudCredentials(*id)
+	if err != nil {
+		return nil, err
+	}
+	if creds.Type() != cloud {
+		return nil, fmt.Errorf("cloud credentials %s are for %s, not %s", id, creds.Type(), cloud)
+	}
+	return creds, nil
+}
+
+// getAWSForCredentials returns an *awscloud.AWS object with the role of the
+// cloud credentials. The role is assumed with the credentials of the worker
+// configuration, or the defaults if there are none.
+func (impl *OSBuildJobImpl) getAWSForCredentials(creds *cloudcredentials.Credentials, region string, jobID uuid.UUID) (*awscloud.AWS, error) {
+	return assumeAWSRole(impl.AWSCreds, creds, region, jobID)
+}
+
+func assumeAWSRole(awsCreds string, creds *cloudcredentials.Credentials, region string, jobID uuid.UUID) (*awscloud.AWS, error) {
+	logrus.Infof("[AWS] 🔑 assuming role %s of the tenant", creds.AWS.RoleARN)
+	return awscloud.NewFromAssumedRole(awsCreds, region, creds.AWS.RoleARN, creds.AWS.ExternalID, "osbuild-worker-"+jobID.String())
+}
+
+// getGCPForCredentials returns a *gcp.GCP object impersonating the service
+// account of the cloud credentials. The credentials of the worker
+// configuration need to be allowed to create tokens for it.
+func (impl *OSBuildJobImpl) getGCPForCredentials(creds *cloudcredentials.Credentials) (*gcp.GCP, error) {
+	if impl.GCPConfig.Creds == "" {
+		return nil, fmt.Errorf("impersonating a GCP service account needs GCP credentials in the worker configuration")
+	}
+	source, err := os.ReadFile(impl.GCPConfig.Creds)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load GCP credentials from file %q: %v", impl.GCPConfig.Creds, err)
+	}
+
+	config, err := json.Marshal(map[string]interface{}{
+		"type":                              "impersonated_service_account",
+		"service_account_impersonation_url": fmt.Sprintf("https://iamcredentials.googleapis.com/v1/projects/-/serviceAccounts/%s:generateAccessToken", creds.GCP.ServiceAccount),
+		"source_credentials":                json.RawMessage(source),
+		"project_id":                        creds.GCP.ProjectID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.CreateTemp("", "gcp-impersonation-*.json")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+	_, err = f.Write(config)
+	if err == nil {
+		err = f.Close()
+	} else {
+		f.Close()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// gcp.New() only accepts service account keys, impersonated credentials
+	// are only picked up from the Application Default Credentials
+	logrus.Infof("[GCP] 🔑 impersonating service account %s of the tenant", creds.GCP.ServiceAccount)
+	gcpDefaultCredentialsMu.Lock()
+	defer gcpDefaultCredentialsMu.Unlock()
+	previous, isSet := os.LookupEnv(gcp.GCPCredentialsEnvName)
+	defer func() {
+		if isSet {
+			os.Setenv(gcp.GCPCredentialsEnvName, previous)
+		} else {
+			os.Unsetenv(gcp.GCPCredentialsEnvName)
+		}
+	}()
+	err = os.Setenv(gcp.GCPCredentialsEnvName, f.Name())
+	if err != nil {
+		return nil, err
+	}
+	return gcp.New(nil)
+}
+
+// getAzureForCredentials returns an *azure.Client with the service principal
+// of the cloud credentials
+func getAzureForCredentials(creds *cloudcredentials.Credentials, tenantID, subscriptionID string) (*azure.Client, error) {
+	logrus.Infof("[Azure] 🔑 using service principal %s of the tenant", creds.Azure.ClientID)
+	return azure.NewClient(azure.Credentials{
+		ClientID:     creds.Azure.ClientID,
+		ClientSecret: creds.Azure.ClientSecret,
+	}, tenantID, subscriptionID)
+}
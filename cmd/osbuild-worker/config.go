@@ -2,12 +2,21 @@ package main
 
 import (
 	"fmt"
+	"net"
+	"net/url"
 	"os"
+	"path/filepath"
+	"sort"
 	"time"
 
 	"github.com/BurntSushi/toml"
 	"github.com/osbuild/image-builder/pkg/cloud/azure"
+	"github.com/osbuild/image-builder/pkg/datasizes"
 	"github.com/sirupsen/logrus"
+
+	"github.com/osbuild/osbuild-composer/internal/logsink"
+	"github.com/osbuild/osbuild-composer/internal/target"
+	"github.com/osbuild/osbuild-composer/internal/worker"
 )
 
 type composerConfig struct {
@@ -72,6 +81,17 @@ type executorConfig struct {
 	Type       string `toml:"type"`
 	IAMProfile string `toml:"iam_profile"`
 	KeyName    string `toml:"key_name"`
+	// qemu.kvm only, memory is in MiB
+	Image  string `toml:"image"`
+	Memory int    `toml:"memory"`
+	CPUs   int    `toml:"cpus"`
+	// remote only, URLs of pre-provisioned osbuild-worker-executors and
+	// the certificates to connect to them with mTLS
+	Endpoints  []string `toml:"endpoints"`
+	CA         string   `toml:"ca"`
+	ClientCert string   `toml:"client_cert"`
+	ClientKey  string   `toml:"client_key"`
+	TokenFile  string   `toml:"token_file"`
 }
 
 type repositoryMTLSConfig struct {
@@ -90,6 +110,55 @@ type bootcInfoResolveConfig struct {
 	CleanupImages bool `toml:"cleanup_images"`
 }
 
+type storeConfig struct {
+	// size budget of each osbuild store of the worker, for example "100 GiB"
+	MaxSize string `toml:"max_size"`
+	// osbuild manifests whose sources are fetched into the stores when the
+	// worker starts
+	PrewarmManifests []string `toml:"prewarm_manifests"`
+}
+
+// maxSizeBytes returns the budget of a store in bytes, 0 means no limit
+func (c *storeConfig) maxSizeBytes() (int64, error) {
+	if c.MaxSize == "" {
+		return 0, nil
+	}
+	size, err := datasizes.Parse(c.MaxSize)
+	if err != nil {
+		return 0, fmt.Errorf("invalid store max_size %q: %w", c.MaxSize, err)
+	}
+	return int64(size), nil
+}
+
+type contentCacheConfig struct {
+	// URL of an osbuild-content-cache, the packages and container images
+	// of the builds are fetched through it. With repository_mtls the worker
+	// authenticates with its client certificate, the cache needs to be
+	// trusted by the CA of repository_mtls then.
+	URL string `toml:"url"`
+}
+
+type sourceBundlesConfig struct {
+	// directory the source bundles of offline builds are placed in, jobs
+	// refer to them by their file name
+	Dir string `toml:"dir"`
+}
+
+type tracingConfig struct {
+	// OTLP/HTTP endpoint the spans are sent to, for example
+	// "https://collector.example.com:4318/v1/traces"
+	Endpoint string            `toml:"endpoint"`
+	Headers  map[string]string `toml:"headers"`
+	// fraction of the traces started by the worker which are recorded,
+	// default value: 1
+	SampleRatio *float64 `toml:"sample_ratio"`
+}
+
+type healthConfig struct {
+	// local address of the health endpoint, for example "localhost:8701"
+	Address string `toml:"address"`
+}
+
 type workerConfig struct {
 	Composer       *composerConfig             `toml:"composer"`
 	Koji           map[string]kojiServerConfig `toml:"koji"`
@@ -111,6 +180,29 @@ type workerConfig struct {
 	DeploymentChannel string `toml:"deployment_channel"`
 	// clean store between runs, this should only be used with workers running on AWS within an ASG
 	CleanStore bool `toml:"clean_store"`
+	// keeps the stores within a size budget instead
+	Store        *storeConfig        `toml:"store"`
+	ContentCache *contentCacheConfig `toml:"content_cache"`
+	// source bundles for building without access to the repositories
+	SourceBundles *sourceBundlesConfig `toml:"source_bundles"`
+	// spans of the jobs are exported to an OpenTelemetry collector
+	Tracing *tracingConfig `toml:"tracing"`
+	// the log is forwarded to these external log stores
+	LogSinks []logsink.Config `toml:"log_sinks"`
+	// serves /healthz, reporting the state of the executor, the stores
+	// and the credentials
+	Health *healthConfig `toml:"health"`
+	// number of jobs of a type to run in parallel, for example
+	// { "depsolve" = 4, "osbuild" = 1 }. Job types without a concurrency
+	// share a slot with the other resolve jobs or the other jobs.
+	Concurrency map[string]int `toml:"concurrency"`
+	// free form labels advertised to composer, osbuild jobs requiring
+	// labels are only handed out to workers having all of them
+	Labels []string `toml:"labels"`
+	// the worker drains and exits after running this many jobs or after
+	// running for this long (for example "12h"), 0 means no limit
+	MaxJobs     int           `toml:"max_jobs"`
+	MaxLifetime time.Duration `toml:"max_lifetime"`
 }
 
 func parseConfig(file string) (*workerConfig, error) {
@@ -143,12 +235,120 @@ func parseConfig(file string) (*workerConfig, error) {
 		}
 	}
 
+	if _, err := planJobSlots(config.Concurrency); err != nil {
+		return nil, err
+	}
+
+	if config.MaxJobs < 0 {
+		return nil, fmt.Errorf("max_jobs must not be negative, got %d", config.MaxJobs)
+	}
+	if config.MaxLifetime < 0 {
+		return nil, fmt.Errorf("max_lifetime must not be negative, got %s", config.MaxLifetime)
+	}
+
+	if config.ContentCache != nil {
+		u, err := url.Parse(config.ContentCache.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("content_cache needs an http or https url, got %q", config.ContentCache.URL)
+		}
+	}
+
+	if config.SourceBundles != nil && !filepath.IsAbs(config.SourceBundles.Dir) {
+		return nil, fmt.Errorf("source_bundles needs an absolute dir, got %q", config.SourceBundles.Dir)
+	}
+
+	if config.Tracing != nil {
+		u, err := url.Parse(config.Tracing.Endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("tracing needs an http or https endpoint, got %q", config.Tracing.Endpoint)
+		}
+		if r := config.Tracing.SampleRatio; r != nil && (*r < 0 || *r > 1) {
+			return nil, fmt.Errorf("tracing sample_ratio must be between 0 and 1, got %v", *r)
+		}
+	}
+
+	if config.Health != nil {
+		if _, _, err := net.SplitHostPort(config.Health.Address); err != nil {
+			return nil, fmt.Errorf("health needs an address like \"localhost:8701\", got %q", config.Health.Address)
+		}
+	}
+
+	glitchTip := false
+	for i, sink := range config.LogSinks {
+		if err := sink.Validate(); err != nil {
+			return nil, fmt.Errorf("log sink %d: %v", i, err)
+		}
+		if sink.Type == logsink.TypeGlitchTip {
+			if glitchTip {
+				return nil, fmt.Errorf("log sink %d: only one glitchtip log sink is supported", i)
+			}
+			glitchTip = true
+		}
+	}
+
+	if config.Store != nil {
+		if _, err := config.Store.maxSizeBytes(); err != nil {
+			return nil, err
+		}
+		if config.CleanStore {
+			return nil, fmt.Errorf("store can't be managed when clean_store is set")
+		}
+		// other executors have their own stores
+		if config.OSBuildExecutor.Type != "host" {
+			return nil, fmt.Errorf("store can only be managed with the host OSBuildExecutor")
+		}
+	}
+
 	switch config.OSBuildExecutor.Type {
-	case "host", "aws.ec2", "qemu.kvm":
+	case "host", "aws.ec2":
 		// good and supported
+	case "qemu.kvm":
+		if config.OSBuildExecutor.Image == "" {
+			return nil, fmt.Errorf("OSBuildExecutor qemu.kvm needs an image")
+		}
+		if config.OSBuildExecutor.Memory < 0 || config.OSBuildExecutor.CPUs < 0 {
+			return nil, fmt.Errorf("OSBuildExecutor qemu.kvm needs a positive memory size and number of CPUs")
+		}
+	case "remote":
+		if len(config.OSBuildExecutor.Endpoints) == 0 {
+			return nil, fmt.Errorf("OSBuildExecutor remote needs at least one endpoint")
+		}
+		if (config.OSBuildExecutor.ClientCert == "") != (config.OSBuildExecutor.ClientKey == "") {
+			return nil, fmt.Errorf("OSBuildExecutor remote needs both a client certificate and key")
+		}
+		if config.OSBuildExecutor.ClientCert != "" && config.OSBuildExecutor.CA == "" {
+			return nil, fmt.Errorf("OSBuildExecutor remote needs a CA to use a client certificate")
+		}
 	default:
-		return nil, fmt.Errorf("OSBuildExecutor needs to be host, aws.ec2, or qemu.kvm. Got: %s.", config.OSBuildExecutor)
+		return nil, fmt.Errorf("OSBuildExecutor needs to be host, aws.ec2, qemu.kvm, or remote. Got: %s.", config.OSBuildExecutor)
 	}
 
 	return &config, nil
 }
+
+// capabilities returns what the worker advertises to composer: the upload
+// targets it is configured for, its executor and its labels
+func (config *workerConfig) capabilities() []string {
+	capabilities := []string{
+		worker.ExecutorCapability(config.OSBuildExecutor.Type),
+	}
+	for server, kojiConfig := range config.Koji {
+		// the worker only supports Kerberos authentication for Koji
+		if kojiConfig.Kerberos != nil {
+			capabilities = append(capabilities, worker.KojiServerCapability(server))
+		}
+	}
+	if config.Azure != nil {
+		capabilities = append(capabilities, worker.TargetCapability(target.TargetNameAzureImage))
+	}
+	if config.OCI != nil {
+		capabilities = append(capabilities,
+			worker.TargetCapability(target.TargetNameOCI),
+			worker.TargetCapability(target.TargetNameOCIObjectStorage))
+	}
+	for _, label := range config.Labels {
+		capabilities = append(capabilities, worker.LabelCapability(label))
+	}
+	sort.Strings(capabilities)
+	return capabilities
+}
@@ -4,8 +4,12 @@ import (
 	"os"
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/common"
+	"github.com/osbuild/osbuild-composer/internal/logsink"
 )
 
 func Test_parseConfig(t *testing.T) {
@@ -157,6 +161,230 @@ key_name = "osbuild-worker"
 				CleanStore:        false,
 			},
 		},
+		{
+			name: "qemu_kvm",
+			config: `
+[osbuild_executor]
+type = "qemu.kvm"
+image = "/var/lib/osbuild-worker/executor.qcow2"
+memory = 8192
+cpus = 4
+`,
+			want: &workerConfig{
+				BasePath: "/api/worker/v1",
+				OSBuildExecutor: &executorConfig{
+					Type:   "qemu.kvm",
+					Image:  "/var/lib/osbuild-worker/executor.qcow2",
+					Memory: 8192,
+					CPUs:   4,
+				},
+				DeploymentChannel: "local",
+			},
+		},
+		{
+			name: "remote",
+			config: `
+[osbuild_executor]
+type = "remote"
+endpoints = ["https://builder1.example.com:8001", "https://builder2.example.com:8001"]
+ca = "/etc/osbuild-worker/executor-ca.pem"
+client_cert = "/etc/osbuild-worker/executor-crt.pem"
+client_key = "/etc/osbuild-worker/executor-key.pem"
+token_file = "/etc/osbuild-worker/executor-token"
+`,
+			want: &workerConfig{
+				BasePath: "/api/worker/v1",
+				OSBuildExecutor: &executorConfig{
+					Type:       "remote",
+					Endpoints:  []string{"https://builder1.example.com:8001", "https://builder2.example.com:8001"},
+					CA:         "/etc/osbuild-worker/executor-ca.pem",
+					ClientCert: "/etc/osbuild-worker/executor-crt.pem",
+					ClientKey:  "/etc/osbuild-worker/executor-key.pem",
+					TokenFile:  "/etc/osbuild-worker/executor-token",
+				},
+				DeploymentChannel: "local",
+			},
+		},
+		{
+			name: "concurrency",
+			config: `
+[concurrency]
+depsolve = 4
+container-resolve = 2
+osbuild = 1
+`,
+			want: &workerConfig{
+				BasePath: "/api/worker/v1",
+				OSBuildExecutor: &executorConfig{
+					Type: "host",
+				},
+				DeploymentChannel: "local",
+				Concurrency: map[string]int{
+					"depsolve":          4,
+					"container-resolve": 2,
+					"osbuild":           1,
+				},
+			},
+		},
+		{
+			name:   "labels",
+			config: `labels = ["fips", "datacenter=ams"]`,
+			want: &workerConfig{
+				BasePath: "/api/worker/v1",
+				OSBuildExecutor: &executorConfig{
+					Type: "host",
+				},
+				DeploymentChannel: "local",
+				Labels:            []string{"fips", "datacenter=ams"},
+			},
+		},
+		{
+			name: "limits",
+			config: `
+max_jobs = 10
+max_lifetime = "12h"
+`,
+			want: &workerConfig{
+				BasePath: "/api/worker/v1",
+				OSBuildExecutor: &executorConfig{
+					Type: "host",
+				},
+				DeploymentChannel: "local",
+				MaxJobs:           10,
+				MaxLifetime:       12 * time.Hour,
+			},
+		},
+		{
+			name: "store",
+			config: `
+[store]
+max_size = "100 GiB"
+prewarm_manifests = ["/etc/osbuild-worker/prewarm/fedora.json"]
+`,
+			want: &workerConfig{
+				BasePath: "/api/worker/v1",
+				OSBuildExecutor: &executorConfig{
+					Type: "host",
+				},
+				DeploymentChannel: "local",
+				Store: &storeConfig{
+					MaxSize:          "100 GiB",
+					PrewarmManifests: []string{"/etc/osbuild-worker/prewarm/fedora.json"},
+				},
+			},
+		},
+		{
+			name: "content cache",
+			config: `
+[content_cache]
+url = "https://cache.example.com:8003"
+`,
+			want: &workerConfig{
+				BasePath: "/api/worker/v1",
+				OSBuildExecutor: &executorConfig{
+					Type: "host",
+				},
+				DeploymentChannel: "local",
+				ContentCache: &contentCacheConfig{
+					URL: "https://cache.example.com:8003",
+				},
+			},
+		},
+		{
+			name: "source bundles",
+			config: `
+[source_bundles]
+dir = "/var/lib/osbuild-worker/source-bundles"
+`,
+			want: &workerConfig{
+				BasePath: "/api/worker/v1",
+				OSBuildExecutor: &executorConfig{
+					Type: "host",
+				},
+				DeploymentChannel: "local",
+				SourceBundles: &sourceBundlesConfig{
+					Dir: "/var/lib/osbuild-worker/source-bundles",
+				},
+			},
+		},
+		{
+			name: "tracing",
+			config: `
+[tracing]
+endpoint = "https://collector.example.com:4318/v1/traces"
+sample_ratio = 0.25
+[tracing.headers]
+Authorization = "Bearer token"
+`,
+			want: &workerConfig{
+				BasePath: "/api/worker/v1",
+				OSBuildExecutor: &executorConfig{
+					Type: "host",
+				},
+				DeploymentChannel: "local",
+				Tracing: &tracingConfig{
+					Endpoint:    "https://collector.example.com:4318/v1/traces",
+					Headers:     map[string]string{"Authorization": "Bearer token"},
+					SampleRatio: common.ToPtr(0.25),
+				},
+			},
+		},
+		{
+			name: "log sinks",
+			config: `
+[[log_sinks]]
+type = "splunk"
+url = "https://splunk.example.com:8088/services/collector/event"
+token = "hec-token"
+[[log_sinks]]
+type = "loki"
+url = "http://loki:3100/loki/api/v1/push"
+level = "info"
+batch_size = 100
+flush_interval = "1s"
+[log_sinks.labels]
+env = "staging"
+`,
+			want: &workerConfig{
+				BasePath: "/api/worker/v1",
+				OSBuildExecutor: &executorConfig{
+					Type: "host",
+				},
+				DeploymentChannel: "local",
+				LogSinks: []logsink.Config{
+					{
+						Type:  "splunk",
+						URL:   "https://splunk.example.com:8088/services/collector/event",
+						Token: "hec-token",
+					},
+					{
+						Type:          "loki",
+						URL:           "http://loki:3100/loki/api/v1/push",
+						Labels:        map[string]string{"env": "staging"},
+						Level:         "info",
+						BatchSize:     100,
+						FlushInterval: time.Second,
+					},
+				},
+			},
+		},
+		{
+			name: "health",
+			config: `
+[health]
+address = "localhost:8701"
+`,
+			want: &workerConfig{
+				BasePath: "/api/worker/v1",
+				OSBuildExecutor: &executorConfig{
+					Type: "host",
+				},
+				DeploymentChannel: "local",
+				Health: &healthConfig{
+					Address: "localhost:8701",
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -192,6 +420,124 @@ upload_threads = -5
 		require.Error(t, err)
 	})
 
+	t.Run("qemu.kvm without image", func(t *testing.T) {
+		configFile := prepareConfig(t, `
+[osbuild_executor]
+type = "qemu.kvm"
+`)
+		_, err := parseConfig(configFile)
+		require.EqualError(t, err, "OSBuildExecutor qemu.kvm needs an image")
+	})
+
+	t.Run("remote without endpoints", func(t *testing.T) {
+		configFile := prepareConfig(t, `
+[osbuild_executor]
+type = "remote"
+`)
+		_, err := parseConfig(configFile)
+		require.EqualError(t, err, "OSBuildExecutor remote needs at least one endpoint")
+	})
+
+	t.Run("remote with client certificate but no key", func(t *testing.T) {
+		configFile := prepareConfig(t, `
+[osbuild_executor]
+type = "remote"
+endpoints = ["https://builder1.example.com:8001"]
+ca = "/etc/osbuild-worker/executor-ca.pem"
+client_cert = "/etc/osbuild-worker/executor-crt.pem"
+`)
+		_, err := parseConfig(configFile)
+		require.EqualError(t, err, "OSBuildExecutor remote needs both a client certificate and key")
+	})
+
+	t.Run("unknown job type in concurrency", func(t *testing.T) {
+		configFile := prepareConfig(t, `
+[concurrency]
+unicorn = 2
+`)
+		_, err := parseConfig(configFile)
+		require.EqualError(t, err, "unknown job type in concurrency configuration: unicorn")
+	})
+
+	t.Run("zero concurrency", func(t *testing.T) {
+		configFile := prepareConfig(t, `
+[concurrency]
+osbuild = 0
+`)
+		_, err := parseConfig(configFile)
+		require.EqualError(t, err, "concurrency of osbuild jobs needs to be at least 1, got 0")
+	})
+
+	t.Run("negative max jobs", func(t *testing.T) {
+		configFile := prepareConfig(t, `max_jobs = -1`)
+		_, err := parseConfig(configFile)
+		require.EqualError(t, err, "max_jobs must not be negative, got -1")
+	})
+
+	t.Run("invalid store size", func(t *testing.T) {
+		configFile := prepareConfig(t, `
+[store]
+max_size = "lots"
+`)
+		_, err := parseConfig(configFile)
+		require.ErrorContains(t, err, `invalid store max_size "lots"`)
+	})
+
+	t.Run("store with clean_store", func(t *testing.T) {
+		configFile := prepareConfig(t, `
+clean_store = true
+[store]
+max_size = "10 GiB"
+`)
+		_, err := parseConfig(configFile)
+		require.EqualError(t, err, "store can't be managed when clean_store is set")
+	})
+
+	t.Run("content cache without url", func(t *testing.T) {
+		configFile := prepareConfig(t, `
+[content_cache]
+`)
+		_, err := parseConfig(configFile)
+		require.EqualError(t, err, `content_cache needs an http or https url, got ""`)
+	})
+
+	t.Run("source bundles with relative dir", func(t *testing.T) {
+		configFile := prepareConfig(t, `
+[source_bundles]
+dir = "bundles"
+`)
+		_, err := parseConfig(configFile)
+		require.EqualError(t, err, `source_bundles needs an absolute dir, got "bundles"`)
+	})
+
+	t.Run("tracing with invalid sample ratio", func(t *testing.T) {
+		configFile := prepareConfig(t, `
+[tracing]
+endpoint = "http://localhost:4318/v1/traces"
+sample_ratio = 2.0
+`)
+		_, err := parseConfig(configFile)
+		require.EqualError(t, err, `tracing sample_ratio must be between 0 and 1, got 2`)
+	})
+
+	t.Run("health without port", func(t *testing.T) {
+		configFile := prepareConfig(t, `
+[health]
+address = "localhost"
+`)
+		_, err := parseConfig(configFile)
+		require.EqualError(t, err, `health needs an address like "localhost:8701", got "localhost"`)
+	})
+
+	t.Run("log sink without url", func(t *testing.T) {
+		configFile := prepareConfig(t, `
+[[log_sinks]]
+type = "otlp"
+`)
+		_, err := parseConfig(configFile)
+		require.EqualError(t, err, `log sink 0: otlp log sink needs an http or https url, got ""`)
+	})
+
 }
 
 func prepareConfig(t *testing.T, config string) string {
@@ -206,3 +552,34 @@ func prepareConfig(t *testing.T, config string) string {
 	require.NoError(t, f.Close())
 	return configFile
 }
+
+func Test_workerCapabilities(t *testing.T) {
+	configFile := prepareConfig(t, `
+labels = ["fips", "datacenter=ams"]
+
+[koji."koji.example.com".kerberos]
+principal = "toucan@EXAMPLE.COM"
+keytab = "/etc/osbuild-worker/client.keytab"
+
+[koji."password.example.com"]
+
+[azure]
+credentials = "/etc/osbuild-worker/azure-creds"
+
+[osbuild_executor]
+type = "aws.ec2"
+`)
+	config, err := parseConfig(configFile)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"executor:aws.ec2",
+		"label:datacenter=ams",
+		"label:fips",
+		"target:org.osbuild.azure.image",
+		"target:org.osbuild.koji:koji.example.com",
+	}, config.capabilities())
+
+	config, err = parseConfig(prepareConfig(t, ``))
+	require.NoError(t, err)
+	require.Equal(t, []string{"executor:host"}, config.capabilities())
+}
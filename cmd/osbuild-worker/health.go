@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+
+	"github.com/osbuild/osbuild-composer/internal/health"
+	"github.com/osbuild/osbuild-composer/internal/osbuildexecutor"
+)
+
+// newHealthChecker returns the checker of the local health endpoint of the
+// worker. It reports whether the executor can run builds, the osbuild stores
+// are writable and the configured credentials can be read.
+func newHealthChecker(config *workerConfig, stores []string, remotePool *osbuildexecutor.RemotePool) *health.Checker {
+	checker := health.NewChecker(health.Check{
+		Name: "executor",
+		Run: func(ctx context.Context) error {
+			return checkExecutor(ctx, config.OSBuildExecutor, remotePool)
+		},
+	})
+
+	for _, store := range stores {
+		checker.Add(health.Check{
+			Name: "store/" + filepath.Base(store),
+			Run: func(context.Context) error {
+				return checkStore(store)
+			},
+		})
+	}
+
+	credentials := credentialFiles(config)
+	names := make([]string, 0, len(credentials))
+	for name := range credentials {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		path := credentials[name]
+		checker.Add(health.Check{
+			Name: "credentials/" + name,
+			Run: func(context.Context) error {
+				return health.Readable(path)
+			},
+		})
+	}
+
+	return checker
+}
+
+func checkExecutor(ctx context.Context, config *executorConfig, remotePool *osbuildexecutor.RemotePool) error {
+	switch config.Type {
+	case "host":
+		_, err := exec.LookPath("osbuild")
+		return err
+	case "qemu.kvm":
+		return health.Readable(config.Image)
+	case "remote":
+		return remotePool.CheckHealth(ctx)
+	default:
+		// instances of aws.ec2 are provisioned per build
+		return nil
+	}
+}
+
+// checkStore checks that osbuild can write to the store, stores which
+// haven't been used yet are created in their parent
+func checkStore(store string) error {
+	err := health.Writable(store)
+	if errors.Is(err, os.ErrNotExist) {
+		err = health.Writable(filepath.Dir(store))
+	}
+	if err != nil {
+		return fmt.Errorf("osbuild store is not writable: %w", err)
+	}
+	return nil
+}
+
+// credentialFiles returns the paths of the configured credentials by name
+func credentialFiles(config *workerConfig) map[string]string {
+	files := map[string]string{}
+	add := func(name, path string) {
+		if path != "" {
+			files[name] = path
+		}
+	}
+
+	if config.AWS != nil {
+		add("aws", config.AWS.Credentials)
+		add("aws_s3", config.AWS.S3Credentials)
+	}
+	if config.GCP != nil {
+		add("gcp", config.GCP.Credentials)
+	}
+	if config.Azure != nil {
+		add("azure", config.Azure.Credentials)
+	}
+	if config.OCI != nil {
+		add("oci", config.OCI.Credentials)
+	}
+	if config.GenericS3 != nil {
+		add("generic_s3", config.GenericS3.Credentials)
+	}
+	if config.Containers != nil {
+		add("containers", config.Containers.AuthFilePath)
+	}
+	if config.Authentication != nil {
+		add("authentication/offline_token", config.Authentication.OfflineTokenPath)
+		add("authentication/client_secret", config.Authentication.ClientSecretPath)
+	}
+	for server, koji := range config.Koji {
+		if koji.Kerberos != nil {
+			add("koji/"+server, koji.Kerberos.KeyTab)
+		}
+	}
+	if config.RepositoryMTLSConfig != nil {
+		add("repository_mtls/client_key", config.RepositoryMTLSConfig.MTLSClientKey)
+		add("repository_mtls/client_cert", config.RepositoryMTLSConfig.MTLSClientCert)
+	}
+	if config.OSBuildExecutor != nil && config.OSBuildExecutor.Type == "remote" {
+		add("osbuild_executor/client_key", config.OSBuildExecutor.ClientKey)
+		add("osbuild_executor/client_cert", config.OSBuildExecutor.ClientCert)
+		add("osbuild_executor/token", config.OSBuildExecutor.TokenFile)
+	}
+	return files
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/health"
+)
+
+func TestHealthChecker(t *testing.T) {
+	dir := t.TempDir()
+	awsCredentials := filepath.Join(dir, "aws")
+	require.NoError(t, os.WriteFile(awsCredentials, []byte("[default]"), 0600))
+
+	config := &workerConfig{
+		OSBuildExecutor: &executorConfig{
+			Type: "aws.ec2",
+		},
+		AWS: &awsConfig{
+			Credentials: awsCredentials,
+		},
+		Koji: map[string]kojiServerConfig{
+			"koji.example.com": {
+				Kerberos: &kerberosConfig{
+					KeyTab: filepath.Join(dir, "missing.keytab"),
+				},
+			},
+		},
+	}
+	stores := []string{
+		// not used yet
+		filepath.Join(dir, "osbuild-store"),
+		filepath.Join(dir, "missing", "osbuild-store-1"),
+	}
+
+	report := newHealthChecker(config, stores, nil).Run(context.Background())
+	require.Equal(t, health.StatusFailed, report.Status)
+	require.Len(t, report.Checks, 5)
+	require.Equal(t, health.StatusOK, report.Checks["executor"].Status)
+	require.Equal(t, health.StatusOK, report.Checks["store/osbuild-store"].Status)
+	require.Equal(t, health.StatusFailed, report.Checks["store/osbuild-store-1"].Status)
+	require.Equal(t, health.StatusOK, report.Checks["credentials/aws"].Status)
+	require.Equal(t, health.StatusFailed, report.Checks["credentials/koji/koji.example.com"].Status)
+}
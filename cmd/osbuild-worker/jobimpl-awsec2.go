@@ -5,14 +5,26 @@ import (
 	"fmt"
 
 	smithy "github.com/aws/smithy-go"
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 
 	"github.com/osbuild/osbuild-composer/internal/cloud/awscloud"
+	"github.com/osbuild/osbuild-composer/internal/cloudcredentials"
 	"github.com/osbuild/osbuild-composer/internal/worker"
 	"github.com/osbuild/osbuild-composer/internal/worker/clienterrors"
 )
 
-func getAWS(awsCreds, region string) (*awscloud.AWS, error) {
+// getAWS returns an *awscloud.AWS object with the role of the tenant's cloud
+// credentials the image was uploaded with, or with the credentials of the
+// worker if there are none
+func getAWS(job worker.Job, awsCreds, region string, cloudCredentials *uuid.UUID) (*awscloud.AWS, error) {
+	creds, err := resolveCloudCredentials(job, cloudCredentials, cloudcredentials.TypeAWS)
+	if err != nil {
+		return nil, err
+	}
+	if creds != nil {
+		return assumeAWSRole(awsCreds, creds, region, job.Id())
+	}
 	if awsCreds != "" {
 		return awscloud.NewFromFile(awsCreds, region)
 	}
@@ -41,7 +53,7 @@ func (impl *AWSEC2CopyJobImpl) Run(job worker.Job) error {
 		return err
 	}
 
-	aws, err := getAWS(impl.AWSCreds, args.TargetRegion)
+	aws, err := getAWS(job, impl.AWSCreds, args.TargetRegion, args.CloudCredentials)
 	if err != nil {
 		logWithId.Errorf("Error creating aws client: %v", err)
 		result.JobError = clienterrors.New(clienterrors.ErrorInvalidConfig, "Invalid worker config", nil)
@@ -120,7 +132,7 @@ func (impl *AWSEC2ShareJobImpl) Run(job worker.Job) error {
 		args.Region = cjResult.Region
 	}
 
-	aws, err := getAWS(impl.AWSCreds, args.Region)
+	aws, err := getAWS(job, impl.AWSCreds, args.Region, args.CloudCredentials)
 	if err != nil {
 		logWithId.Errorf("Error creating aws client: %v", err)
 		result.JobError = clienterrors.New(clienterrors.ErrorInvalidConfig, "Invalid worker config", nil)
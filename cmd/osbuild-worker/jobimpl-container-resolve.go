@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
@@ -69,7 +70,9 @@ func (impl *ContainerResolveJobImpl) Run(job worker.Job) error {
 	resolver := container.NewResolver(args.Arch)
 	resolver.AuthFilePath = impl.AuthFilePath
 
+	start := time.Now()
 	resolved, err := ResolveContainers(resolver, args.PipelineSpecs)
+	result.ResolveSeconds = time.Since(start).Seconds()
 	if err != nil {
 		result.JobError = clienterrors.New(clienterrors.ErrorContainerResolution, err.Error(), nil)
 		return err
@@ -5,12 +5,14 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/osbuild/image-builder/pkg/depsolvednf"
 	"github.com/osbuild/image-builder/pkg/rpmmd"
 	"github.com/osbuild/image-builder/pkg/sbom"
+	"github.com/osbuild/osbuild-composer/internal/lockfile"
 	"github.com/osbuild/osbuild-composer/internal/worker"
 	"github.com/osbuild/osbuild-composer/internal/worker/clienterrors"
 )
@@ -61,7 +63,7 @@ type DepsolveJobImpl struct {
 // (matching map keys).
 //
 // On error, returns a partial result with JobError set.
-func (impl *DepsolveJobImpl) depsolve(packageSets map[string][]rpmmd.PackageSet, modulePlatformID, arch, releasever string, sbomType sbom.StandardType, logWithId *logrus.Entry) *worker.DepsolveJobResult {
+func (impl *DepsolveJobImpl) depsolve(packageSets map[string][]rpmmd.PackageSet, modulePlatformID, arch, releasever string, sbomType sbom.StandardType, lf *lockfile.Lockfile, logWithId *logrus.Entry) *worker.DepsolveJobResult {
 	result := &worker.DepsolveJobResult{}
 
 	solver := impl.Solver.NewWithConfig(modulePlatformID, releasever, arch, "")
@@ -89,6 +91,12 @@ func (impl *DepsolveJobImpl) depsolve(packageSets map[string][]rpmmd.PackageSet,
 			result.JobError = workerClientErrorFrom(err, logWithId)
 			return result
 		}
+		if lf != nil {
+			if err := lf.Verify(name, res.Transactions); err != nil {
+				result.JobError = clienterrors.New(clienterrors.ErrorLockfileMismatch, "Depsolved packages don't match the lockfile", err.Error())
+				return result
+			}
+		}
 		if solverName == "" {
 			solverName = res.Solver
 		}
@@ -189,8 +197,10 @@ func (impl *DepsolveJobImpl) Run(job worker.Job) error {
 		}
 	}
 
-	depsolveResult := impl.depsolve(args.PackageSets, args.ModulePlatformID, args.Arch, args.Releasever, args.SbomType, logWithId)
+	start := time.Now()
+	depsolveResult := impl.depsolve(args.PackageSets, args.ModulePlatformID, args.Arch, args.Releasever, args.SbomType, args.Lockfile, logWithId)
 	result = *depsolveResult
+	result.SolveSeconds = time.Since(start).Seconds()
 
 	if err := impl.Solver.CleanCache(); err != nil {
 		// log and ignore
@@ -5,6 +5,7 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"math/big"
@@ -15,10 +16,12 @@ import (
 	"runtime/debug"
 	"slices"
 	"strings"
+	"time"
 
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/osbuild/image-builder/pkg/arch"
 	"github.com/osbuild/image-builder/pkg/cloud/azure"
@@ -32,9 +35,14 @@ import (
 	"github.com/osbuild/image-builder/pkg/upload/oci"
 	"github.com/osbuild/image-builder/pkg/upload/vmware"
 	"github.com/osbuild/osbuild-composer/internal/cloud/awscloud"
+	"github.com/osbuild/osbuild-composer/internal/cloudcredentials"
 	"github.com/osbuild/osbuild-composer/internal/common"
+	"github.com/osbuild/osbuild-composer/internal/contentcache"
 	"github.com/osbuild/osbuild-composer/internal/osbuildexecutor"
+	"github.com/osbuild/osbuild-composer/internal/osbuildstore"
+	"github.com/osbuild/osbuild-composer/internal/sourcebundle"
 	"github.com/osbuild/osbuild-composer/internal/target"
+	"github.com/osbuild/osbuild-composer/internal/tracing"
 	"github.com/osbuild/osbuild-composer/internal/worker"
 	"github.com/osbuild/osbuild-composer/internal/worker/clienterrors"
 )
@@ -82,6 +90,8 @@ type ExecutorConfiguration struct {
 	Type       string
 	IAMProfile string
 	KeyName    string
+	QEMUKVM    osbuildexecutor.QEMUKVMConfig
+	RemotePool *osbuildexecutor.RemotePool
 }
 
 type OSBuildJobImpl struct {
@@ -99,6 +109,13 @@ type OSBuildJobImpl struct {
 	ContainersConfig     ContainersConfiguration
 	PulpConfig           PulpConfiguration
 	RepositoryMTLSConfig *RepositoryMTLSConfig
+	// manages the size of Store, nil if it's kept forever or cleaned after
+	// every job
+	StoreManager *osbuildstore.Manager
+	// sources are fetched through this osbuild-content-cache if set
+	ContentCacheURL string
+	// directory of the source bundles of offline builds
+	SourceBundlesDir string
 }
 
 // Returns an *awscloud.AWS object with the credentials of the request. If they
@@ -153,12 +170,27 @@ func (impl *OSBuildJobImpl) verifyS3TargetConfiguration() error {
 	return nil
 }
 
-func (impl *OSBuildJobImpl) getAWSForS3Target(options *target.AWSS3TargetOptions) (*awscloud.AWS, string, error) {
+// getAWSForS3Target returns the AWS object and the bucket for the target.
+// creds are the cloud credentials of the tenant the target refers to, nil if
+// it doesn't.
+func (impl *OSBuildJobImpl) getAWSForS3Target(options *target.AWSS3TargetOptions, creds *cloudcredentials.Credentials, jobID uuid.UUID) (*awscloud.AWS, string, error) {
 	var aws *awscloud.AWS = nil
 	var err error
 
 	bucket := options.Bucket
 
+	if creds != nil {
+		// the tenant's role can't write to the bucket of the worker
+		if options.Endpoint != "" || options.Region == "" {
+			return nil, "", fmt.Errorf("cloud credentials are only supported for AWS S3")
+		}
+		if bucket == "" {
+			bucket = creds.AWS.Bucket
+		}
+		aws, err = impl.getAWSForCredentials(creds, options.Region, jobID)
+		return aws, bucket, err
+	}
+
 	// Endpoint == "" && Region != "" => AWS (Weldr and Composer)
 	if options.Endpoint == "" && options.Region != "" {
 		if impl.AWSS3Creds != "" {
@@ -213,6 +245,8 @@ func (impl *OSBuildJobImpl) getGCP(credentials []byte) (*gcp.GCP, error) {
 	}
 
 	logrus.Info("[GCP] 🔑 using Application Default Credentials via Google library")
+	gcpDefaultCredentialsMu.Lock()
+	defer gcpDefaultCredentialsMu.Unlock()
 	return gcp.New(nil)
 }
 
@@ -345,6 +379,38 @@ func makeJobErrorFromOsbuildOutput(result *osbuild.Result) *clienterrors.Error {
 	return clienterrors.New(clienterrors.ErrorBuildJob, "build failure", errors)
 }
 
+// osbuildSourcesEnv returns the environment osbuild needs to fetch the
+// sources with the registry credentials and the repository client
+// certificate of the worker
+func osbuildSourcesEnv(authFilePath string, mtls *RepositoryMTLSConfig) []string {
+	var extraEnv []string
+	if authFilePath != "" {
+		extraEnv = []string{
+			fmt.Sprintf("REGISTRY_AUTH_FILE=%s", authFilePath),
+		}
+	}
+
+	// Both curl and ostree input share the same MTLS config
+	if mtls != nil {
+		// Setting a CA cert with hosted Pulp with break the build since Pulp redirects HTTPS requests to AWS S3 which has
+		// a different CA which is part of OS cert bundle. Both curl and ostree commands only support either explicit CA file
+		// or OS cert bundle, but not both. To verify hosted Pulp CA, enroll its CA into the OS cert bundle instead.
+		if mtls.CA != "" {
+			extraEnv = append(extraEnv, fmt.Sprintf("OSBUILD_SOURCES_CURL_SSL_CA_CERT=%s", mtls.CA))
+			extraEnv = append(extraEnv, fmt.Sprintf("OSBUILD_SOURCES_OSTREE_SSL_CA_CERT=%s", mtls.CA))
+		}
+		extraEnv = append(extraEnv, fmt.Sprintf("OSBUILD_SOURCES_CURL_SSL_CLIENT_KEY=%s", mtls.MTLSClientKey))
+		extraEnv = append(extraEnv, fmt.Sprintf("OSBUILD_SOURCES_CURL_SSL_CLIENT_CERT=%s", mtls.MTLSClientCert))
+		extraEnv = append(extraEnv, fmt.Sprintf("OSBUILD_SOURCES_OSTREE_SSL_CLIENT_KEY=%s", mtls.MTLSClientKey))
+		extraEnv = append(extraEnv, fmt.Sprintf("OSBUILD_SOURCES_OSTREE_SSL_CLIENT_CERT=%s", mtls.MTLSClientCert))
+		if mtls.Proxy != nil {
+			extraEnv = append(extraEnv, fmt.Sprintf("OSBUILD_SOURCES_CURL_PROXY=%s", mtls.Proxy.String()))
+			extraEnv = append(extraEnv, fmt.Sprintf("OSBUILD_SOURCES_OSTREE_PROXY=%s", mtls.Proxy.String()))
+		}
+	}
+	return extraEnv
+}
+
 func (impl *OSBuildJobImpl) Run(job worker.Job) error {
 	logWithId := logrus.WithField("jobId", job.Id().String())
 	// Initialize variable needed for reporting back to osbuild-composer.
@@ -409,6 +475,43 @@ func (impl *OSBuildJobImpl) Run(job worker.Job) error {
 		return err
 	}
 
+	// An offline build imports its source bundle into the store, the
+	// bundle has the manifest and describes the image if the job doesn't
+	var manifestInfo *worker.ManifestInfo
+	if jobArgs.SourceBundle != "" {
+		if impl.OSBuildExecutor.Type != "host" {
+			osbuildJobResult.JobError = clienterrors.New(clienterrors.ErrorInvalidConfig, "Source bundles can only be built with the host osbuild executor", nil)
+			return nil
+		}
+		bundlePath, err := sourceBundlePath(impl.SourceBundlesDir, jobArgs.SourceBundle)
+		if err != nil {
+			osbuildJobResult.JobError = clienterrors.New(clienterrors.ErrorSourceBundle, err.Error(), nil)
+			return err
+		}
+		logWithId.Infof("Importing source bundle %s into %s", bundlePath, impl.Store)
+		bundle, err := sourcebundle.ImportFile(bundlePath, impl.Store)
+		if err != nil {
+			osbuildJobResult.JobError = clienterrors.New(clienterrors.ErrorSourceBundle, "Unable to import the source bundle", err.Error())
+			return err
+		}
+		if len(jobArgs.Manifest) == 0 {
+			jobArgs.Manifest = bundle.Manifest
+			manifestInfo = &worker.ManifestInfo{
+				PipelineNames: &worker.PipelineNames{
+					Build:   bundle.Info.BuildPipelines,
+					Payload: bundle.Info.PayloadPipelines,
+				},
+			}
+		}
+		if len(jobArgs.Targets) == 0 {
+			t := target.NewWorkerServerTarget()
+			t.ImageName = bundle.Info.ExportFilename
+			t.OsbuildArtifact.ExportName = bundle.Info.ExportName
+			t.OsbuildArtifact.ExportFilename = bundle.Info.ExportFilename
+			jobArgs.Targets = []*target.Target{t}
+		}
+	}
+
 	// Read targets from BootcPreManifest dynargs if configured
 	if len(jobArgs.Targets) > 0 && jobArgs.PreManifestDynArgsIdx != nil {
 		osbuildJobResult.JobError = clienterrors.New(
@@ -442,7 +545,6 @@ func (impl *OSBuildJobImpl) Run(job worker.Job) error {
 	}
 
 	// In case the manifest is empty, try to get it from dynamic args
-	var manifestInfo *worker.ManifestInfo
 	if len(jobArgs.Manifest) == 0 {
 		if job.NDynamicArgs() > 0 {
 			var manifestJR worker.ManifestJobByIDResult
@@ -518,50 +620,33 @@ func (impl *OSBuildJobImpl) Run(job worker.Job) error {
 		return nil
 	}
 
-	var extraEnv []string
-	if impl.ContainersConfig.AuthFilePath != "" {
-		extraEnv = []string{
-			fmt.Sprintf("REGISTRY_AUTH_FILE=%s", impl.ContainersConfig.AuthFilePath),
-		}
-	}
-
-	// Both curl and ostree input share the same MTLS config
-	if impl.RepositoryMTLSConfig != nil {
-		// Setting a CA cert with hosted Pulp with break the build since Pulp redirects HTTPS requests to AWS S3 which has
-		// a different CA which is part of OS cert bundle. Both curl and ostree commands only support either explicit CA file
-		// or OS cert bundle, but not both. To verify hosted Pulp CA, enroll its CA into the OS cert bundle instead.
-		if impl.RepositoryMTLSConfig.CA != "" {
-			extraEnv = append(extraEnv, fmt.Sprintf("OSBUILD_SOURCES_CURL_SSL_CA_CERT=%s", impl.RepositoryMTLSConfig.CA))
-			extraEnv = append(extraEnv, fmt.Sprintf("OSBUILD_SOURCES_OSTREE_SSL_CA_CERT=%s", impl.RepositoryMTLSConfig.CA))
-		}
-		extraEnv = append(extraEnv, fmt.Sprintf("OSBUILD_SOURCES_CURL_SSL_CLIENT_KEY=%s", impl.RepositoryMTLSConfig.MTLSClientKey))
-		extraEnv = append(extraEnv, fmt.Sprintf("OSBUILD_SOURCES_CURL_SSL_CLIENT_CERT=%s", impl.RepositoryMTLSConfig.MTLSClientCert))
-		extraEnv = append(extraEnv, fmt.Sprintf("OSBUILD_SOURCES_OSTREE_SSL_CLIENT_KEY=%s", impl.RepositoryMTLSConfig.MTLSClientKey))
-		extraEnv = append(extraEnv, fmt.Sprintf("OSBUILD_SOURCES_OSTREE_SSL_CLIENT_CERT=%s", impl.RepositoryMTLSConfig.MTLSClientCert))
-		if impl.RepositoryMTLSConfig.Proxy != nil {
-			extraEnv = append(extraEnv, fmt.Sprintf("OSBUILD_SOURCES_CURL_PROXY=%s", impl.RepositoryMTLSConfig.Proxy.String()))
-			extraEnv = append(extraEnv, fmt.Sprintf("OSBUILD_SOURCES_OSTREE_PROXY=%s", impl.RepositoryMTLSConfig.Proxy.String()))
-		}
-	}
+	extraEnv := osbuildSourcesEnv(impl.ContainersConfig.AuthFilePath, impl.RepositoryMTLSConfig)
 
 	// Run osbuild and handle two kinds of errors
 	var executor osbuildexecutor.Executor
 	switch impl.OSBuildExecutor.Type {
 	case "host":
 		executor = osbuildexecutor.NewHostExecutor()
-	case "aws.ec2":
+	case "aws.ec2", "qemu.kvm", "remote":
 		err = os.MkdirAll("/var/tmp/osbuild-composer", 0755)
 		if err != nil {
-			osbuildJobResult.JobError = clienterrors.New(clienterrors.ErrorInvalidConfig, "Unable to create /var/tmp/osbuild-composer needed to aws.ec2 executor", nil)
+			osbuildJobResult.JobError = clienterrors.New(clienterrors.ErrorInvalidConfig, fmt.Sprintf("Unable to create /var/tmp/osbuild-composer needed to %s executor", impl.OSBuildExecutor.Type), nil)
 			return err
 		}
 		tmpDir, err := os.MkdirTemp("/var/tmp/osbuild-composer", "")
 		if err != nil {
-			osbuildJobResult.JobError = clienterrors.New(clienterrors.ErrorInvalidConfig, "Unable to create /var/tmp/osbuild-composer needed to aws.ec2 executor", nil)
+			osbuildJobResult.JobError = clienterrors.New(clienterrors.ErrorInvalidConfig, fmt.Sprintf("Unable to create /var/tmp/osbuild-composer needed to %s executor", impl.OSBuildExecutor.Type), nil)
 			return err
 		}
 		defer os.RemoveAll(tmpDir)
-		executor = osbuildexecutor.NewAWSEC2Executor(impl.OSBuildExecutor.IAMProfile, impl.OSBuildExecutor.KeyName, job.Id().String(), tmpDir)
+		switch impl.OSBuildExecutor.Type {
+		case "aws.ec2":
+			executor = osbuildexecutor.NewAWSEC2Executor(impl.OSBuildExecutor.IAMProfile, impl.OSBuildExecutor.KeyName, job.Id().String(), tmpDir)
+		case "qemu.kvm":
+			executor = osbuildexecutor.NewQEMUKVMExecutor(impl.OSBuildExecutor.QEMUKVM, tmpDir)
+		case "remote":
+			executor = osbuildexecutor.NewRemoteExecutor(impl.OSBuildExecutor.RemotePool, tmpDir)
+		}
 	default:
 		osbuildJobResult.JobError = clienterrors.New(clienterrors.ErrorInvalidConfig, "No osbuild executor defined", nil)
 		return err
@@ -577,7 +662,34 @@ func (impl *OSBuildJobImpl) Run(job worker.Job) error {
 		JSONOutput: true,
 	}
 
-	osbuildJobResult.OSBuildOutput, err = executor.RunOSBuild(jobArgs.Manifest, logWithId, job, opts)
+	// the manifest of the job is kept as is for the Koji build, only the
+	// build fetches its sources through the content cache
+	buildManifest := []byte(jobArgs.Manifest)
+	if impl.ContentCacheURL != "" {
+		buildManifest, err = contentcache.RewriteManifest(buildManifest, impl.ContentCacheURL, impl.RepositoryMTLSConfig != nil)
+		if err != nil {
+			osbuildJobResult.JobError = clienterrors.New(clienterrors.ErrorBuildJob, "Unable to route the sources through the content cache", err.Error())
+			return err
+		}
+	}
+
+	var storeBuild *osbuildstore.Build
+	if impl.StoreManager != nil {
+		storeBuild = prepareStore(impl.StoreManager, buildManifest, opts, logWithId)
+	}
+
+	// the spans of the pipelines and stages are children of the build
+	buildCtx, buildSpan := tracing.Start(job.Context(), "osbuild")
+	osbuildJobResult.Timings = &worker.OSBuildTimings{}
+	osbuildJobResult.OSBuildOutput, err = executor.RunOSBuild(buildManifest, logWithId, job.WithContext(buildCtx), opts, osbuildJobResult.Timings)
+	if err == nil && !osbuildJobResult.OSBuildOutput.Success {
+		tracing.End(buildSpan, errors.New("osbuild failed"))
+	} else {
+		tracing.End(buildSpan, err)
+	}
+	if storeBuild != nil {
+		osbuildJobResult.StoreStats = finishStore(impl.StoreManager, storeBuild, logWithId)
+	}
 	// handle the case where something around running osbuild failed (starting, IO errors, etc.)
 	if err != nil {
 		osbuildJobResult.JobError = clienterrors.New(clienterrors.ErrorBuildJob, "osbuild failed", err.Error())
@@ -608,6 +720,23 @@ func (impl *OSBuildJobImpl) Run(job worker.Job) error {
 	for _, jobTarget := range jobArgs.Targets {
 		var targetResult *target.TargetResult
 		artifact := jobTarget.OsbuildArtifact
+
+		// composer keeps the passwords and keys of the target, the job only
+		// carries references to them
+		err = target.OpenSecrets(jobTarget, job.Secret)
+		if err != nil {
+			osbuildJobResult.TargetResults = append(osbuildJobResult.TargetResults, &target.TargetResult{
+				Name:            jobTarget.Name,
+				OsbuildArtifact: &artifact,
+				TargetError:     clienterrors.New(clienterrors.ErrorInvalidTargetConfig, fmt.Sprintf("cannot resolve the secrets of the target: %v", err), nil),
+			})
+			continue
+		}
+
+		uploadStart := time.Now()
+		_, uploadSpan := tracing.Start(job.Context(), "upload "+string(jobTarget.Name),
+			attribute.String("composer.target.name", string(jobTarget.Name)),
+			attribute.String("composer.target.image_name", jobTarget.ImageName))
 		switch targetOptions := jobTarget.Options.(type) {
 		case *target.WorkerServerTargetOptions:
 			targetResult = target.NewWorkerServerTargetResult(&target.WorkerServerTargetResultOptions{
@@ -683,7 +812,17 @@ func (impl *OSBuildJobImpl) Run(job worker.Job) error {
 
 		case *target.AWSTargetOptions:
 			targetResult = target.NewAWSTargetResult(nil, &artifact)
-			a, err := impl.getAWS(targetOptions.Region, targetOptions.AccessKeyID, targetOptions.SecretAccessKey, targetOptions.SessionToken)
+			creds, err := resolveCloudCredentials(job, targetOptions.CloudCredentials, cloudcredentials.TypeAWS)
+			if err != nil {
+				targetResult.TargetError = clienterrors.New(clienterrors.ErrorInvalidTargetConfig, err.Error(), nil)
+				break
+			}
+			var a *awscloud.AWS
+			if creds != nil {
+				a, err = impl.getAWSForCredentials(creds, targetOptions.Region, job.Id())
+			} else {
+				a, err = impl.getAWS(targetOptions.Region, targetOptions.AccessKeyID, targetOptions.SecretAccessKey, targetOptions.SessionToken)
+			}
 			if err != nil {
 				targetResult.TargetError = clienterrors.New(clienterrors.ErrorInvalidConfig, err.Error(), nil)
 				break
@@ -695,6 +834,9 @@ func (impl *OSBuildJobImpl) Run(job worker.Job) error {
 			}
 
 			bucket := targetOptions.Bucket
+			if bucket == "" && creds != nil {
+				bucket = creds.AWS.Bucket
+			}
 			if bucket == "" {
 				bucket = impl.AWSBucket
 				if bucket == "" {
@@ -752,7 +894,12 @@ func (impl *OSBuildJobImpl) Run(job worker.Job) error {
 
 		case *target.AWSS3TargetOptions:
 			targetResult = target.NewAWSS3TargetResult(nil, &artifact)
-			a, bucket, err := impl.getAWSForS3Target(targetOptions)
+			creds, err := resolveCloudCredentials(job, targetOptions.CloudCredentials, cloudcredentials.TypeAWS)
+			if err != nil {
+				targetResult.TargetError = clienterrors.New(clienterrors.ErrorInvalidTargetConfig, err.Error(), nil)
+				break
+			}
+			a, bucket, err := impl.getAWSForS3Target(targetOptions, creds, job.Id())
 			if err != nil {
 				targetResult.TargetError = clienterrors.New(clienterrors.ErrorInvalidConfig, err.Error(), nil)
 				break
@@ -802,7 +949,17 @@ func (impl *OSBuildJobImpl) Run(job worker.Job) error {
 			targetResult = target.NewGCPTargetResult(nil, &artifact)
 			ctx := context.Background()
 
-			g, err := impl.getGCP(targetOptions.Credentials)
+			creds, err := resolveCloudCredentials(job, targetOptions.CloudCredentials, cloudcredentials.TypeGCP)
+			if err != nil {
+				targetResult.TargetError = clienterrors.New(clienterrors.ErrorInvalidTargetConfig, err.Error(), nil)
+				break
+			}
+			var g *gcp.GCP
+			if creds != nil {
+				g, err = impl.getGCPForCredentials(creds)
+			} else {
+				g, err = impl.getGCP(targetOptions.Credentials)
+			}
 			if err != nil {
 				targetResult.TargetError = clienterrors.New(clienterrors.ErrorInvalidConfig, err.Error(), nil)
 				break
@@ -814,6 +971,9 @@ func (impl *OSBuildJobImpl) Run(job worker.Job) error {
 			}
 
 			bucket := targetOptions.Bucket
+			if bucket == "" && creds != nil {
+				bucket = creds.GCP.Bucket
+			}
 			if bucket == "" {
 				bucket = impl.GCPConfig.Bucket
 				if bucket == "" {
@@ -875,12 +1035,22 @@ func (impl *OSBuildJobImpl) Run(job worker.Job) error {
 			targetResult = target.NewAzureImageTargetResult(nil, &artifact)
 			ctx := context.Background()
 
-			if impl.AzureConfig.Creds == nil {
+			creds, err := resolveCloudCredentials(job, targetOptions.CloudCredentials, cloudcredentials.TypeAzure)
+			if err != nil {
+				targetResult.TargetError = clienterrors.New(clienterrors.ErrorInvalidTargetConfig, err.Error(), nil)
+				break
+			}
+			if creds == nil && impl.AzureConfig.Creds == nil {
 				targetResult.TargetError = clienterrors.New(clienterrors.ErrorSharingTarget, "osbuild job has org.osbuild.azure.image target but this worker doesn't have azure credentials", nil)
 				break
 			}
 
-			c, err := azure.NewClient(*impl.AzureConfig.Creds, targetOptions.TenantID, targetOptions.SubscriptionID)
+			var c *azure.Client
+			if creds != nil {
+				c, err = getAzureForCredentials(creds, targetOptions.TenantID, targetOptions.SubscriptionID)
+			} else {
+				c, err = azure.NewClient(*impl.AzureConfig.Creds, targetOptions.TenantID, targetOptions.SubscriptionID)
+			}
 			if err != nil {
 				targetResult.TargetError = clienterrors.New(clienterrors.ErrorInvalidTargetConfig, err.Error(), nil)
 				break
@@ -1330,6 +1500,7 @@ func (impl *OSBuildJobImpl) Run(job worker.Job) error {
 			// TODO: we may not want to return completely here with multiple targets, because then no TargetErrors will be added to the JobError details
 			// Nevertheless, all target errors will be still in the OSBuildJobResult.
 			osbuildJobResult.JobError = clienterrors.New(clienterrors.ErrorInvalidTarget, fmt.Sprintf("invalid target type: %s", jobTarget.Name), nil)
+			tracing.End(uploadSpan, errors.New(osbuildJobResult.JobError.String()))
 			return nil
 		}
 
@@ -1337,6 +1508,20 @@ func (impl *OSBuildJobImpl) Run(job worker.Job) error {
 		if targetResult == nil {
 			panic("target results object not created by the target handling code")
 		}
+		if targetResult.TargetError != nil {
+			tracing.End(uploadSpan, errors.New(targetResult.TargetError.String()))
+		} else {
+			tracing.End(uploadSpan, nil)
+		}
+		uploadTiming := worker.OSBuildUploadTiming{
+			Target:  string(jobTarget.Name),
+			Seconds: time.Since(uploadStart).Seconds(),
+			Success: targetResult.TargetError == nil,
+		}
+		if info, err := os.Stat(path.Join(outputDirectory, jobTarget.OsbuildArtifact.ExportName, jobTarget.OsbuildArtifact.ExportFilename)); err == nil {
+			uploadTiming.Bytes = info.Size()
+		}
+		osbuildJobResult.Timings.Uploads = append(osbuildJobResult.Timings.Uploads, uploadTiming)
 		osbuildJobResult.TargetResults = append(osbuildJobResult.TargetResults, targetResult)
 	}
 
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/osbuild/osbuild-composer/internal/common"
+	"github.com/osbuild/osbuild-composer/internal/repocheck"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+	"github.com/osbuild/osbuild-composer/internal/worker/clienterrors"
+)
+
+type RepositoryCheckJobImpl struct {
+	RepositoryMTLSConfig *RepositoryMTLSConfig
+}
+
+func (impl *RepositoryCheckJobImpl) Run(job worker.Job) error {
+	logWithId := logrus.WithField("jobId", job.Id())
+
+	result := worker.RepositoryCheckJobResult{
+		Results: []worker.RepositoryCheckResultItem{},
+	}
+
+	defer func() {
+		if result.JobError != nil {
+			logWithId.Errorf("repository check job failed: %s", result.JobError.Reason)
+		}
+
+		err := job.Finish(&result)
+		if err != nil {
+			logWithId.Errorf("Error reporting job result: %v", err)
+		}
+	}()
+
+	var args worker.RepositoryCheckJob
+	err := job.Args(&args)
+	if err != nil {
+		result.JobError = clienterrors.New(clienterrors.ErrorParsingJobArgs, fmt.Sprintf("Error parsing arguments: %v", err), nil)
+		return err
+	}
+
+	checker := repocheck.NewChecker()
+	if impl.RepositoryMTLSConfig != nil {
+		checker.Proxy = impl.RepositoryMTLSConfig.Proxy
+	}
+
+	logWithId.Infof("Checking %d repositories", len(args.Repositories))
+	for _, repo := range args.Repositories {
+		if impl.RepositoryMTLSConfig != nil {
+			for _, baseurlstr := range repo.BaseURLs {
+				match, err := impl.RepositoryMTLSConfig.CompareBaseURL(baseurlstr)
+				if err != nil {
+					result.JobError = clienterrors.New(clienterrors.ErrorInvalidRepositoryURL, "Repository URL is malformed", err.Error())
+					return err
+				}
+				if match {
+					impl.RepositoryMTLSConfig.SetupRepoSSL(&repo)
+				}
+			}
+		}
+
+		res := checker.Check(context.Background(), repo)
+		item := worker.RepositoryCheckResultItem{
+			RepoID:       repo.Id,
+			URL:          res.URL,
+			Healthy:      res.Healthy(),
+			Revision:     res.Revision,
+			PackageCount: res.PackageCount,
+			TLSError:     res.TLSError,
+			GPGErrors:    res.GPGErrors,
+			Error:        res.Error,
+		}
+		if !res.LastUpdated.IsZero() {
+			item.LastUpdated = common.ToPtr(res.LastUpdated)
+		}
+		if !item.Healthy {
+			logWithId.Warnf("Repository %s is not healthy: %+v", res.URL, res)
+		}
+		result.Results = append(result.Results, item)
+	}
+
+	return nil
+}
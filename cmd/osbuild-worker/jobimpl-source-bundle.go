@@ -0,0 +1,236 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/osbuild/image-builder/pkg/osbuild"
+	"github.com/sirupsen/logrus"
+
+	"github.com/osbuild/osbuild-composer/internal/contentcache"
+	"github.com/osbuild/osbuild-composer/internal/osbuildstore"
+	"github.com/osbuild/osbuild-composer/internal/sourcebundle"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+	"github.com/osbuild/osbuild-composer/internal/worker/clienterrors"
+)
+
+// sourceBundlePath returns the path of the source bundle name in dir. Jobs
+// can only refer to the bundles in the directory.
+func sourceBundlePath(dir, name string) (string, error) {
+	if dir == "" {
+		return "", fmt.Errorf("the worker has no source bundle directory configured")
+	}
+	if name == "" || name == "." || name == ".." || name != filepath.Base(name) {
+		return "", fmt.Errorf("invalid source bundle name %q", name)
+	}
+	return filepath.Join(dir, name), nil
+}
+
+type SourceBundleExportJobImpl struct {
+	// osbuild store and output directory of the slot, empty if the slot
+	// doesn't run osbuild jobs
+	Store                  string
+	Output                 string
+	ContainersAuthFilePath string
+	RepositoryMTLSConfig   *RepositoryMTLSConfig
+	StoreManager           *osbuildstore.Manager
+	ContentCacheURL        string
+}
+
+func (impl *SourceBundleExportJobImpl) Run(job worker.Job) error {
+	logWithId := logrus.WithField("jobId", job.Id())
+	result := worker.SourceBundleExportJobResult{}
+
+	defer func() {
+		if result.JobError != nil {
+			logWithId.Errorf("source bundle export job failed: %s", result.JobError.Reason)
+		}
+
+		err := job.Finish(&result)
+		if err != nil {
+			logWithId.Errorf("Error reporting job result: %v", err)
+		}
+	}()
+
+	var args worker.SourceBundleExportJob
+	err := job.Args(&args)
+	if err != nil {
+		result.JobError = clienterrors.New(clienterrors.ErrorParsingJobArgs, fmt.Sprintf("Error parsing arguments: %v", err), nil)
+		return err
+	}
+
+	manifest := []byte(args.Manifest)
+	var manifestInfo worker.ManifestInfo
+	if len(manifest) == 0 {
+		if job.NDynamicArgs() != 1 {
+			result.JobError = clienterrors.New(clienterrors.ErrorNoDynamicArgs, "No manifest dependency", nil)
+			return nil
+		}
+		var manifestJR worker.ManifestJobByIDResult
+		err = job.DynamicArgs(0, &manifestJR)
+		if err != nil {
+			result.JobError = clienterrors.New(clienterrors.ErrorParsingDynamicArgs, "Error parsing dynamic args", nil)
+			return err
+		}
+		if manifestJR.JobError != nil {
+			result.JobError = clienterrors.New(clienterrors.ErrorManifestDependency, "Manifest dependency failed", nil)
+			return nil
+		}
+		manifest = manifestJR.Manifest
+		manifestInfo = manifestJR.ManifestInfo
+	}
+	if len(manifest) == 0 {
+		result.JobError = clienterrors.New(clienterrors.ErrorEmptyManifest, "Job has no manifest", nil)
+		return nil
+	}
+
+	// slots which don't run osbuild jobs fetch the sources into a store
+	// of their own
+	store := impl.Store
+	if store == "" {
+		store, err = os.MkdirTemp("/var/tmp", "osbuild-source-bundle-store-")
+		if err != nil {
+			result.JobError = clienterrors.New(clienterrors.ErrorBuildJob, "Unable to create a temporary osbuild store", err.Error())
+			return err
+		}
+		defer os.RemoveAll(store)
+	}
+	output, err := os.MkdirTemp(impl.Output, job.Id().String()+"-*")
+	if err != nil {
+		result.JobError = clienterrors.New(clienterrors.ErrorBuildJob, "Unable to create the output directory", err.Error())
+		return err
+	}
+	defer os.RemoveAll(output)
+
+	fetchManifest := manifest
+	if impl.ContentCacheURL != "" {
+		fetchManifest, err = contentcache.RewriteManifest(manifest, impl.ContentCacheURL, impl.RepositoryMTLSConfig != nil)
+		if err != nil {
+			result.JobError = clienterrors.New(clienterrors.ErrorBuildJob, "Unable to route the sources through the content cache", err.Error())
+			return err
+		}
+	}
+
+	// without exports osbuild only fetches the sources of the manifest
+	opts := &osbuild.OSBuildOptions{
+		StoreDir:   store,
+		OutputDir:  output,
+		ExtraEnv:   osbuildSourcesEnv(impl.ContainersAuthFilePath, impl.RepositoryMTLSConfig),
+		Stderr:     os.Stderr,
+		JSONOutput: true,
+	}
+	var storeBuild *osbuildstore.Build
+	if impl.StoreManager != nil {
+		storeBuild = prepareStore(impl.StoreManager, fetchManifest, opts, logWithId)
+	}
+	// the sources need to stay in the store until they are bundled
+	if storeBuild != nil {
+		defer finishStore(impl.StoreManager, storeBuild, logWithId)
+	}
+
+	logWithId.Infof("Fetching the sources of the manifest into %s", store)
+	osbuildResult, err := osbuild.RunOSBuild(fetchManifest, opts)
+	if err != nil {
+		result.JobError = clienterrors.New(clienterrors.ErrorBuildJob, "osbuild failed", err.Error())
+		return err
+	}
+	if !osbuildResult.Success {
+		result.JobError = clienterrors.New(clienterrors.ErrorBuildJob, "osbuild failed to fetch the sources", nil)
+		return nil
+	}
+
+	info := sourcebundle.Info{
+		ExportName:     args.ExportName,
+		ExportFilename: args.ExportFilename,
+	}
+	if manifestInfo.PipelineNames != nil {
+		info.BuildPipelines = manifestInfo.PipelineNames.Build
+		info.PayloadPipelines = manifestInfo.PipelineNames.Payload
+	}
+
+	bundlePath := filepath.Join(output, worker.SourceBundleArtifact)
+	f, err := os.Create(bundlePath)
+	if err != nil {
+		result.JobError = clienterrors.New(clienterrors.ErrorSourceBundle, "Unable to create the source bundle", err.Error())
+		return err
+	}
+	defer f.Close()
+	err = sourcebundle.Write(f, store, manifest, info)
+	if err != nil {
+		result.JobError = clienterrors.New(clienterrors.ErrorSourceBundle, "Unable to write the source bundle", err.Error())
+		return err
+	}
+	// rewind the bundle for the upload
+	size, err := f.Seek(0, io.SeekCurrent)
+	if err == nil {
+		_, err = f.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		result.JobError = clienterrors.New(clienterrors.ErrorSourceBundle, "Unable to read the source bundle", err.Error())
+		return err
+	}
+
+	err = job.UploadArtifact(worker.SourceBundleArtifact, f)
+	if err != nil {
+		result.JobError = clienterrors.New(clienterrors.ErrorUploadingImage, "Unable to upload the source bundle", err.Error())
+		return err
+	}
+
+	sources, err := osbuildstore.ManifestSources(manifest)
+	if err != nil {
+		return err
+	}
+	result.Sources = len(sources)
+	result.Size = size
+	logWithId.Infof("Exported a source bundle with %d sources (%d bytes)", result.Sources, result.Size)
+	return nil
+}
+
+type SourceBundleImportJobImpl struct {
+	// directory of the source bundles
+	Dir string
+	// osbuild stores of the worker
+	Stores []string
+}
+
+func (impl *SourceBundleImportJobImpl) Run(job worker.Job) error {
+	logWithId := logrus.WithField("jobId", job.Id())
+	result := worker.SourceBundleImportJobResult{}
+
+	defer func() {
+		if result.JobError != nil {
+			logWithId.Errorf("source bundle import job failed: %s", result.JobError.Reason)
+		}
+
+		err := job.Finish(&result)
+		if err != nil {
+			logWithId.Errorf("Error reporting job result: %v", err)
+		}
+	}()
+
+	var args worker.SourceBundleImportJob
+	err := job.Args(&args)
+	if err != nil {
+		result.JobError = clienterrors.New(clienterrors.ErrorParsingJobArgs, fmt.Sprintf("Error parsing arguments: %v", err), nil)
+		return err
+	}
+
+	path, err := sourceBundlePath(impl.Dir, args.Name)
+	if err != nil {
+		result.JobError = clienterrors.New(clienterrors.ErrorSourceBundle, err.Error(), nil)
+		return err
+	}
+
+	for _, store := range impl.Stores {
+		logWithId.Infof("Importing source bundle %s into %s", path, store)
+		bundle, err := sourcebundle.ImportFile(path, store)
+		if err != nil {
+			result.JobError = clienterrors.New(clienterrors.ErrorSourceBundle, "Unable to import the source bundle", err.Error())
+			return err
+		}
+		result.Sources = len(bundle.Info.Sources)
+	}
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"net/http"
 	"net/url"
 	"os"
 	"path"
@@ -17,11 +18,11 @@ import (
 	"time"
 
 	"github.com/coreos/go-systemd/v22/journal"
-	slogger "github.com/osbuild/osbuild-composer/pkg/splunk_logger"
 
 	"github.com/BurntSushi/toml"
 	"github.com/coreos/go-systemd/v22/dbus"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/osbuild/image-builder/pkg/arch"
 	"github.com/osbuild/image-builder/pkg/cloud/azure"
@@ -31,6 +32,10 @@ import (
 	"github.com/osbuild/image-builder/pkg/upload/oci"
 	"github.com/osbuild/osbuild-composer/internal/cloud/awscloud"
 	"github.com/osbuild/osbuild-composer/internal/common"
+	"github.com/osbuild/osbuild-composer/internal/logsink"
+	"github.com/osbuild/osbuild-composer/internal/osbuildexecutor"
+	"github.com/osbuild/osbuild-composer/internal/osbuildstore"
+	"github.com/osbuild/osbuild-composer/internal/tracing"
 	"github.com/osbuild/osbuild-composer/internal/worker"
 )
 
@@ -81,26 +86,6 @@ func createTLSConfig(config *connectionConfig) (*tls.Config, error) {
 	}, nil
 }
 
-// Regularly ask osbuild-composer if the compose we're currently working on was
-// canceled and exit the process if it was.
-// It would be cleaner to kill the osbuild process using (`exec.CommandContext`
-// or similar), but osbuild does not currently support this. Exiting here will
-// make systemd clean up the whole cgroup and restart this service.
-func WatchJob(ctx context.Context, job worker.Job) {
-	for {
-		select {
-		case <-time.After(15 * time.Second):
-			canceled, err := job.Canceled()
-			if err == nil && canceled {
-				logrus.Info("Job was canceled. Exiting.")
-				os.Exit(0)
-			}
-		case <-ctx.Done():
-			return
-		}
-	}
-}
-
 // Protect an AWS instance from scaling (terminating).
 func setProtection(protected bool) {
 	// This will fail if the worker isn't running in AWS, so just return with a debug message.
@@ -131,13 +116,17 @@ func setProtection(protected bool) {
 
 // Requests and runs 1 job of specified type(s)
 // Returning an error here will result in the worker backing off for a while and retrying
-func RequestAndRunJob(client *worker.Client, acceptedJobTypes []string, jobImpls map[string]JobImplementation) error {
+func RequestAndRunJob(slots *workerSlots, acceptedJobTypes []string, jobImpls map[string]JobImplementation) error {
 	logrus.Debug("Waiting for a new job...")
-	job, err := client.RequestJob(acceptedJobTypes, arch.Current().String())
+	job, err := slots.client.RequestJob(slots.requestCtx, acceptedJobTypes, arch.Current().String())
 	if err == worker.ErrClientRequestJobTimeout {
 		logrus.Debugf("Requesting job timed out: %v", err)
 		return nil
 	}
+	if err != nil && slots.isStopped() {
+		logrus.Debugf("Stopped waiting for a job: %v", err)
+		return nil
+	}
 	if err != nil {
 		logrus.Errorf("Requesting job failed: %v", err)
 		return err
@@ -152,16 +141,23 @@ func RequestAndRunJob(client *worker.Client, acceptedJobTypes []string, jobImpls
 	// Depsolve requests needs reactivity, since setting the protection can take up to 6s to timeout if the worker isn't
 	// in an AWS env, disable this setting for them.
 	if job.Type() != worker.JobTypeDepsolve {
-		setProtection(true)
-		defer setProtection(false)
+		slots.protect()
+		defer slots.unprotect()
 	}
 
 	logrus.Infof("Running job '%s' (%s)\n", job.Id(), job.Type()) // DO NOT EDIT/REMOVE: used for Splunk dashboard
 
+	slots.jobStarted(job.Id())
+	defer slots.jobDone(job.Id())
 	ctx, cancelWatcher := context.WithCancel(context.Background())
-	go WatchJob(ctx, job)
-
-	err = impl.Run(job)
+	go slots.watchJob(ctx, job)
+
+	// the span is part of the trace of the compose the job belongs to
+	jobCtx, span := tracing.Start(job.Context(), "run "+job.Type(),
+		attribute.String("composer.job.id", job.Id().String()),
+		attribute.String("composer.job.type", job.Type()))
+	err = impl.Run(job.WithContext(jobCtx))
+	tracing.End(span, err)
 	cancelWatcher()
 	if err != nil {
 		logrus.Warnf("Job '%s' (%s) failed: %v", job.Id(), job.Type(), err) // DO NOT EDIT/REMOVE: used for Splunk dashboard
@@ -205,17 +201,43 @@ var run = func() {
 	configWriter.Close()
 
 	if config.DeploymentChannel != "" {
-		logrus.AddHook(&slogger.EnvironmentHook{Channel: config.DeploymentChannel})
+		logrus.AddHook(&logsink.EnvironmentHook{Channel: config.DeploymentChannel})
+	}
+
+	for _, sinkConfig := range config.LogSinks {
+		hook, closeSink, err := logsink.Open(context.Background(), sinkConfig, "osbuild-worker")
+		if err != nil {
+			logrus.Fatalf("Could not set up %s log sink: %v", sinkConfig.Type, err)
+		}
+		logrus.AddHook(hook)
+		defer closeSink()
+	}
+
+	if config.Tracing != nil {
+		tracingConfig := tracing.Config{
+			Endpoint:    config.Tracing.Endpoint,
+			Headers:     config.Tracing.Headers,
+			SampleRatio: 1,
+		}
+		if config.Tracing.SampleRatio != nil {
+			tracingConfig.SampleRatio = *config.Tracing.SampleRatio
+		}
+		shutdownTracing, err := tracing.Init("osbuild-worker", tracingConfig)
+		if err != nil {
+			logrus.Fatalf("Could not set up tracing: %v", err)
+		}
+		defer func() {
+			if err := shutdownTracing(context.Background()); err != nil {
+				logrus.Errorf("Could not flush the remaining spans: %v", err)
+			}
+		}()
 	}
 
 	cacheDirectory, ok := os.LookupEnv("CACHE_DIRECTORY")
 	if !ok {
 		logrus.Fatal("CACHE_DIRECTORY is not set. Is the service file missing CacheDirectory=?")
 	}
-	store := path.Join(cacheDirectory, "osbuild-store")
 	rpmmd_cache := path.Join(cacheDirectory, "rpmmd")
-	output := path.Join(cacheDirectory, "output")
-	_ = os.Mkdir(output, os.ModeDir)
 
 	kojiServers := make(map[string]kojiServer)
 	for server, kojiConfig := range config.Koji {
@@ -232,11 +254,15 @@ var run = func() {
 		}
 	}
 
+	capabilities := config.capabilities()
+	logrus.Infof("Worker capabilities: %v", capabilities)
+
 	var client *worker.Client
 	if unix {
 		client = worker.NewClientUnix(worker.ClientConfig{
-			BaseURL:  address,
-			BasePath: config.BasePath,
+			BaseURL:      address,
+			BasePath:     config.BasePath,
+			Capabilities: capabilities,
 		})
 	} else if config.Authentication != nil {
 		var conf *tls.Config
@@ -282,6 +308,7 @@ var run = func() {
 			ClientSecret: clientSecret,
 			BasePath:     config.BasePath,
 			ProxyURL:     proxy,
+			Capabilities: capabilities,
 		})
 		if err != nil {
 			logrus.Fatalf("Error creating worker client: %v", err)
@@ -306,10 +333,11 @@ var run = func() {
 		}
 
 		client, err = worker.NewClient(worker.ClientConfig{
-			BaseURL:   fmt.Sprintf("https://%s", address),
-			TlsConfig: conf,
-			BasePath:  config.BasePath,
-			ProxyURL:  proxy,
+			BaseURL:      fmt.Sprintf("https://%s", address),
+			TlsConfig:    conf,
+			BasePath:     config.BasePath,
+			ProxyURL:     proxy,
+			Capabilities: capabilities,
 		})
 		if err != nil {
 			logrus.Fatalf("Error creating worker client: %v", err)
@@ -430,15 +458,84 @@ var run = func() {
 		}
 	}
 
-	// depsolve and search jobs can be done during other jobs
-	depsolveCtx, depsolveCtxCancel := context.WithCancel(context.Background())
 	solver := depsolvednf.NewBaseSolver(rpmmd_cache)
 	if config.DNFJson != "" {
 		solver.SetDepsolveDNFPath(config.DNFJson)
 	}
-	defer depsolveCtxCancel()
-	go func() {
-		jobImpls := map[string]JobImplementation{
+
+	var remotePool *osbuildexecutor.RemotePool
+	if config.OSBuildExecutor.Type == "remote" {
+		var conf *tls.Config
+		if config.OSBuildExecutor.CA != "" {
+			conf, err = createTLSConfig(&connectionConfig{
+				CACertFile:     config.OSBuildExecutor.CA,
+				ClientKeyFile:  config.OSBuildExecutor.ClientKey,
+				ClientCertFile: config.OSBuildExecutor.ClientCert,
+			})
+			if err != nil {
+				logrus.Fatalf("Error creating TLS config for the remote executors: %v", err)
+			}
+		}
+		token := ""
+		if config.OSBuildExecutor.TokenFile != "" {
+			t, err := os.ReadFile(config.OSBuildExecutor.TokenFile)
+			if err != nil {
+				logrus.Fatalf("Could not read remote executor token: %v", err)
+			}
+			token = strings.TrimSpace(string(t))
+		}
+		remotePool, err = osbuildexecutor.NewRemotePool(config.OSBuildExecutor.Endpoints, conf, token)
+		if err != nil {
+			logrus.Fatalf("Error creating the remote executor pool: %v", err)
+		}
+	}
+
+	var contentCacheURL string
+	if config.ContentCache != nil {
+		contentCacheURL = config.ContentCache.URL
+	}
+
+	var storeMaxSize int64
+	if config.Store != nil {
+		storeMaxSize, err = config.Store.maxSizeBytes()
+		if err != nil {
+			logrus.Fatalf("Invalid store configuration: %v", err)
+		}
+	}
+	storeManagers := make(map[string]*osbuildstore.Manager)
+
+	var sourceBundlesDir string
+	if config.SourceBundles != nil {
+		sourceBundlesDir = config.SourceBundles.Dir
+	}
+	stores, err := osbuildStores(config.Concurrency, cacheDirectory)
+	if err != nil {
+		logrus.Fatalf("Could not set up job slots: %v", err)
+	}
+
+	if config.Health != nil {
+		mux := http.NewServeMux()
+		mux.Handle("/healthz", newHealthChecker(config, stores, remotePool))
+		healthServer := &http.Server{
+			Addr:              config.Health.Address,
+			Handler:           mux,
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+		go func() {
+			err := healthServer.ListenAndServe()
+			if err != nil && err != http.ErrServerClosed {
+				logrus.Fatalf("Could not serve the health endpoint: %v", err)
+			}
+		}()
+	}
+
+	newJobImpls := func(store, output string) map[string]JobImplementation {
+		var storeManager *osbuildstore.Manager
+		if config.Store != nil && store != "" {
+			storeManager = osbuildstore.New(store, storeMaxSize)
+			storeManagers[store] = storeManager
+		}
+		return map[string]JobImplementation{
 			worker.JobTypeDepsolve: &DepsolveJobImpl{
 				Solver:               solver,
 				RepositoryMTLSConfig: repositoryMTLSConfig,
@@ -450,114 +547,97 @@ var run = func() {
 			worker.JobTypeImageBuilderManifest: &ImageBuilderManifestJobImpl{
 				RepositoryMTLSConfig: repositoryMTLSConfig,
 			},
-		}
-		acceptedJobTypes := []string{}
-		for jt := range jobImpls {
-			acceptedJobTypes = append(acceptedJobTypes, jt)
-		}
-
-		for {
-			err := RequestAndRunJob(client, acceptedJobTypes, jobImpls)
-			if err != nil {
-				logrus.Warn("Received error from RequestAndRunJob, backing off")
-				time.Sleep(backoffDuration)
-			}
-
-			select {
-			case <-depsolveCtx.Done():
-				return
-			default:
-				continue
-			}
-
-		}
-	}()
-
-	// non-depsolve job
-	jobImpls := map[string]JobImplementation{
-		worker.JobTypeOSBuild: &OSBuildJobImpl{
-			Store:  store,
-			Output: output,
-			OSBuildExecutor: ExecutorConfiguration{
-				Type:       config.OSBuildExecutor.Type,
-				IAMProfile: config.OSBuildExecutor.IAMProfile,
-				KeyName:    config.OSBuildExecutor.KeyName,
+			worker.JobTypeRepositoryCheck: &RepositoryCheckJobImpl{
+				RepositoryMTLSConfig: repositoryMTLSConfig,
+			},
+			worker.JobTypeOSBuild: &OSBuildJobImpl{
+				Store:  store,
+				Output: output,
+				OSBuildExecutor: ExecutorConfiguration{
+					Type:       config.OSBuildExecutor.Type,
+					IAMProfile: config.OSBuildExecutor.IAMProfile,
+					KeyName:    config.OSBuildExecutor.KeyName,
+					QEMUKVM: osbuildexecutor.QEMUKVMConfig{
+						Image:  config.OSBuildExecutor.Image,
+						Memory: config.OSBuildExecutor.Memory,
+						CPUs:   config.OSBuildExecutor.CPUs,
+					},
+					RemotePool: remotePool,
+				},
+				KojiServers: kojiServers,
+				GCPConfig:   gcpConfig,
+				AzureConfig: azureConfig,
+				OCIConfig:   ociConfig,
+				AWSCreds:    awsCredentials,
+				AWSS3Creds:  awsS3Credentials,
+				AWSBucket:   awsBucket,
+				S3Config: S3Configuration{
+					Creds:               genericS3Credentials,
+					Endpoint:            genericS3Endpoint,
+					Region:              genericS3Region,
+					Bucket:              genericS3Bucket,
+					CABundle:            genericS3CABundle,
+					SkipSSLVerification: genericS3SkipSSLVerification,
+				},
+				ContainersConfig: ContainersConfiguration{
+					AuthFilePath: containersAuthFilePath,
+					Domain:       containersDomain,
+					PathPrefix:   containersPathPrefix,
+					CertPath:     containersCertPath,
+					TLSVerify:    &containersTLSVerify,
+				},
+				RepositoryMTLSConfig: repositoryMTLSConfig,
+				StoreManager:         storeManager,
+				ContentCacheURL:      contentCacheURL,
+				SourceBundlesDir:     sourceBundlesDir,
 			},
-			KojiServers: kojiServers,
-			GCPConfig:   gcpConfig,
-			AzureConfig: azureConfig,
-			OCIConfig:   ociConfig,
-			AWSCreds:    awsCredentials,
-			AWSS3Creds:  awsS3Credentials,
-			AWSBucket:   awsBucket,
-			S3Config: S3Configuration{
-				Creds:               genericS3Credentials,
-				Endpoint:            genericS3Endpoint,
-				Region:              genericS3Region,
-				Bucket:              genericS3Bucket,
-				CABundle:            genericS3CABundle,
-				SkipSSLVerification: genericS3SkipSSLVerification,
+			worker.JobTypeKojiInit: &KojiInitJobImpl{
+				KojiServers: kojiServers,
 			},
-			ContainersConfig: ContainersConfiguration{
+			worker.JobTypeKojiFinalize: &KojiFinalizeJobImpl{
+				KojiServers: kojiServers,
+			},
+			worker.JobTypeContainerResolve: &ContainerResolveJobImpl{
 				AuthFilePath: containersAuthFilePath,
-				Domain:       containersDomain,
-				PathPrefix:   containersPathPrefix,
-				CertPath:     containersCertPath,
-				TLSVerify:    &containersTLSVerify,
 			},
-			RepositoryMTLSConfig: repositoryMTLSConfig,
-		},
-		worker.JobTypeKojiInit: &KojiInitJobImpl{
-			KojiServers: kojiServers,
-		},
-		worker.JobTypeKojiFinalize: &KojiFinalizeJobImpl{
-			KojiServers: kojiServers,
-		},
-		worker.JobTypeContainerResolve: &ContainerResolveJobImpl{
-			AuthFilePath: containersAuthFilePath,
-		},
-		worker.JobTypeOSTreeResolve: &OSTreeResolveJobImpl{
-			RepositoryMTLSConfig: repositoryMTLSConfig,
-		},
-		worker.JobTypeFileResolve: &FileResolveJobImpl{},
-		worker.JobTypeAWSEC2Copy: &AWSEC2CopyJobImpl{
-			AWSCreds: awsCredentials,
-		},
-		worker.JobTypeAWSEC2Share: &AWSEC2ShareJobImpl{
-			AWSCreds: awsCredentials,
-		},
-		worker.JobTypeBootcInfoResolve: &BootcInfoResolveJobImpl{
-			CleanupImages: config.BootcInfoResolve != nil && config.BootcInfoResolve.CleanupImages,
-		},
-	}
-
-	acceptedJobTypes := []string{}
-	for jt := range jobImpls {
-		acceptedJobTypes = append(acceptedJobTypes, jt)
-	}
-
-	for {
-		err = RequestAndRunJob(client, acceptedJobTypes, jobImpls)
-		if err != nil {
-			logrus.Warn("Received error from RequestAndRunJob, backing off")
-			time.Sleep(backoffDuration)
+			worker.JobTypeOSTreeResolve: &OSTreeResolveJobImpl{
+				RepositoryMTLSConfig: repositoryMTLSConfig,
+			},
+			worker.JobTypeFileResolve: &FileResolveJobImpl{},
+			worker.JobTypeAWSEC2Copy: &AWSEC2CopyJobImpl{
+				AWSCreds: awsCredentials,
+			},
+			worker.JobTypeAWSEC2Share: &AWSEC2ShareJobImpl{
+				AWSCreds: awsCredentials,
+			},
+			worker.JobTypeBootcInfoResolve: &BootcInfoResolveJobImpl{
+				CleanupImages: config.BootcInfoResolve != nil && config.BootcInfoResolve.CleanupImages,
+			},
+			worker.JobTypeSourceBundleExport: &SourceBundleExportJobImpl{
+				Store:                  store,
+				Output:                 output,
+				ContainersAuthFilePath: containersAuthFilePath,
+				RepositoryMTLSConfig:   repositoryMTLSConfig,
+				StoreManager:           storeManager,
+				ContentCacheURL:        contentCacheURL,
+			},
+			worker.JobTypeSourceBundleImport: &SourceBundleImportJobImpl{
+				Dir:    sourceBundlesDir,
+				Stores: stores,
+			},
 		}
-		if config.CleanStore {
-			err := os.RemoveAll(store)
-			if err != nil {
-				// Shut down the worker in case of failure, as sharing stores
-				// between jobs should be avoided at all costs.
-				logrus.Errorf("Unable to clean out store: %s", err)
-
-				err = awscloud.ShutdownSelf()
-				if err != nil {
-					logrus.Errorf("Unable to shut self down: %v", err)
-				}
-				stopSelf()
-				return
-			}
+	}
+
+	slots, err := newJobSlots(config.Concurrency, cacheDirectory, newJobImpls)
+	if err != nil {
+		logrus.Fatalf("Could not set up job slots: %v", err)
+	}
+	if config.Store != nil && len(config.Store.PrewarmManifests) > 0 {
+		for store, manager := range storeManagers {
+			prewarmStore(manager, store, config.Store.PrewarmManifests)
 		}
 	}
+	newWorkerSlots(client, config.CleanStore, config.MaxJobs, config.MaxLifetime).run(slots)
 }
 
 func stopSelf() {
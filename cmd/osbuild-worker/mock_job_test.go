@@ -1,6 +1,7 @@
 package main_test
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -8,6 +9,10 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/cloudcredentials"
+	"github.com/osbuild/osbuild-composer/internal/secrets"
+	"github.com/osbuild/osbuild-composer/internal/worker"
 )
 
 // mockJob is a minimal worker.Job implementation for unit-testing JobImpl.Run
@@ -31,6 +36,14 @@ func (j *mockJob) Type() string {
 	return j.jobType
 }
 
+func (j *mockJob) Context() context.Context {
+	return context.Background()
+}
+
+func (j *mockJob) WithContext(context.Context) worker.Job {
+	return j
+}
+
 func (j *mockJob) Args(args interface{}) error {
 	return json.Unmarshal(j.rawArgs, args)
 }
@@ -58,6 +71,14 @@ func (j *mockJob) UploadArtifact(string, io.ReadSeeker) error {
 	return nil
 }
 
+func (j *mockJob) CloudCredentials(uuid.UUID) (*cloudcredentials.Credentials, error) {
+	return nil, cloudcredentials.ErrNotFound
+}
+
+func (j *mockJob) Secret(ref string) (string, error) {
+	return "", secrets.ErrNotFound
+}
+
 func (j *mockJob) Finish(result interface{}) error {
 	j.finishCalled = true
 	if j.finishErr != nil {
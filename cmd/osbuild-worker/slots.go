@@ -0,0 +1,423 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/osbuild/osbuild-composer/internal/cloud/awscloud"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+)
+
+// jobs which are quick and need reactivity, they run in their own slot so
+// they can be done during other jobs
+var resolveJobTypes = []string{
+	worker.JobTypeDepsolve,
+	worker.JobTypeSearchPackages,
+	worker.JobTypeImageBuilderManifest,
+	worker.JobTypeRepositoryCheck,
+}
+
+var mainJobTypes = []string{
+	worker.JobTypeOSBuild,
+	worker.JobTypeKojiInit,
+	worker.JobTypeKojiFinalize,
+	worker.JobTypeContainerResolve,
+	worker.JobTypeOSTreeResolve,
+	worker.JobTypeFileResolve,
+	worker.JobTypeAWSEC2Copy,
+	worker.JobTypeAWSEC2Share,
+	worker.JobTypeBootcInfoResolve,
+	worker.JobTypeSourceBundleExport,
+	worker.JobTypeSourceBundleImport,
+}
+
+// A slot requests and runs one job after another, the slots of a worker run
+// in parallel.
+type jobSlot struct {
+	name             string
+	acceptedJobTypes []string
+	jobImpls         map[string]JobImplementation
+	// osbuild store of the slot, empty if it doesn't run osbuild jobs
+	store string
+}
+
+// planJobSlots returns the job types of every slot. Without a concurrency
+// configuration a worker has one slot for the resolve jobs and one for the
+// others. Job types with a configured concurrency get that many slots of
+// their own.
+func planJobSlots(concurrency map[string]int) ([][]string, error) {
+	var configured []string
+	for jt, n := range concurrency {
+		if !slices.Contains(resolveJobTypes, jt) && !slices.Contains(mainJobTypes, jt) {
+			return nil, fmt.Errorf("unknown job type in concurrency configuration: %s", jt)
+		}
+		if n < 1 {
+			return nil, fmt.Errorf("concurrency of %s jobs needs to be at least 1, got %d", jt, n)
+		}
+		configured = append(configured, jt)
+	}
+	sort.Strings(configured)
+
+	var slots [][]string
+	for _, group := range [][]string{resolveJobTypes, mainJobTypes} {
+		var types []string
+		for _, jt := range group {
+			if _, ok := concurrency[jt]; !ok {
+				types = append(types, jt)
+			}
+		}
+		if len(types) > 0 {
+			slots = append(slots, types)
+		}
+	}
+	for _, jt := range configured {
+		for i := 0; i < concurrency[jt]; i++ {
+			slots = append(slots, []string{jt})
+		}
+	}
+	return slots, nil
+}
+
+// newJobSlots creates the slots of the worker. Slots running osbuild jobs
+// get their own store and output dirs, the first one uses the ones of a
+// worker without concurrency.
+func newJobSlots(concurrency map[string]int, cacheDirectory string, newJobImpls func(store, output string) map[string]JobImplementation) ([]jobSlot, error) {
+	plan, err := planJobSlots(concurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	var slots []jobSlot
+	osbuildSlots := 0
+	for i, types := range plan {
+		store := ""
+		output := ""
+		if slices.Contains(types, worker.JobTypeOSBuild) {
+			store = osbuildSlotDir(cacheDirectory, "osbuild-store", osbuildSlots)
+			output = osbuildSlotDir(cacheDirectory, "output", osbuildSlots)
+			_ = os.Mkdir(output, os.ModeDir)
+			osbuildSlots++
+		}
+
+		allImpls := newJobImpls(store, output)
+		jobImpls := make(map[string]JobImplementation)
+		for _, jt := range types {
+			jobImpls[jt] = allImpls[jt]
+		}
+		slots = append(slots, jobSlot{
+			name:             fmt.Sprintf("slot-%d", i),
+			acceptedJobTypes: types,
+			jobImpls:         jobImpls,
+			store:            store,
+		})
+	}
+	return slots, nil
+}
+
+// osbuildSlotDir returns the directory of the nth slot running osbuild jobs
+func osbuildSlotDir(cacheDirectory, name string, n int) string {
+	dir := path.Join(cacheDirectory, name)
+	if n > 0 {
+		dir += "-" + strconv.Itoa(n)
+	}
+	return dir
+}
+
+// osbuildStores returns the stores of the slots running osbuild jobs
+func osbuildStores(concurrency map[string]int, cacheDirectory string) ([]string, error) {
+	plan, err := planJobSlots(concurrency)
+	if err != nil {
+		return nil, err
+	}
+	var stores []string
+	for _, types := range plan {
+		if slices.Contains(types, worker.JobTypeOSBuild) {
+			stores = append(stores, osbuildSlotDir(cacheDirectory, "osbuild-store", len(stores)))
+		}
+	}
+	return stores, nil
+}
+
+// workerSlots tracks the jobs running in the slots of a worker and handles
+// shutting the worker down for all of them.
+//
+// osbuild can't be stopped, so a worker exits when one of its jobs is
+// canceled and systemd cleans up and restarts it. With several slots that
+// would fail the jobs of the other slots, so the worker stops requesting
+// new jobs and exits once the jobs which weren't canceled are done.
+//
+// A worker is drained on SIGTERM or when it reached its maximum number of
+// jobs or its maximum lifetime. It finishes its running jobs, deregisters
+// from composer and exits.
+type workerSlots struct {
+	client     *worker.Client
+	cleanStore bool
+	// 0 means no limit
+	maxJobs     int
+	maxLifetime time.Duration
+
+	mu sync.Mutex
+	// running jobs, true if the job was canceled
+	running  map[uuid.UUID]bool
+	stopping bool
+	draining bool
+	jobsDone int
+	stopped  chan struct{}
+	stopOnce sync.Once
+	// canceled on stop, interrupts waiting for jobs
+	requestCtx    context.Context
+	cancelRequest context.CancelFunc
+
+	// number of running jobs which need the instance to be protected
+	protectedJobs int
+	protectionMu  sync.Mutex
+
+	// overridden in tests
+	exit          func()
+	setProtection func(bool)
+	watchInterval time.Duration
+}
+
+func newWorkerSlots(client *worker.Client, cleanStore bool, maxJobs int, maxLifetime time.Duration) *workerSlots {
+	requestCtx, cancelRequest := context.WithCancel(context.Background())
+	return &workerSlots{
+		client:        client,
+		cleanStore:    cleanStore,
+		maxJobs:       maxJobs,
+		maxLifetime:   maxLifetime,
+		running:       make(map[uuid.UUID]bool),
+		stopped:       make(chan struct{}),
+		requestCtx:    requestCtx,
+		cancelRequest: cancelRequest,
+		exit:          func() { os.Exit(0) },
+		setProtection: setProtection,
+		watchInterval: 15 * time.Second,
+	}
+}
+
+// stop makes all slots stop requesting jobs
+func (ws *workerSlots) stop() {
+	ws.stopOnce.Do(func() {
+		close(ws.stopped)
+		ws.cancelRequest()
+	})
+}
+
+// drain makes the worker finish its running jobs, deregister and exit
+func (ws *workerSlots) drain(reason string) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	ws.drainLocked(reason)
+}
+
+// drainLocked is drain with the lock already held
+func (ws *workerSlots) drainLocked(reason string) {
+	if ws.draining {
+		return
+	}
+	logrus.Infof("Draining worker: %s", reason)
+	ws.draining = true
+	ws.stop()
+}
+
+func (ws *workerSlots) isDraining() bool {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	return ws.draining
+}
+
+func (ws *workerSlots) isStopped() bool {
+	select {
+	case <-ws.stopped:
+		return true
+	default:
+		return false
+	}
+}
+
+func (ws *workerSlots) jobStarted(id uuid.UUID) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	ws.running[id] = false
+}
+
+func (ws *workerSlots) jobDone(id uuid.UUID) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	delete(ws.running, id)
+	ws.jobsDone++
+	if ws.maxJobs > 0 && ws.jobsDone >= ws.maxJobs {
+		ws.drainLocked(fmt.Sprintf("ran %d jobs", ws.jobsDone))
+	}
+	ws.exitIfDrained()
+}
+
+func (ws *workerSlots) jobCanceled(id uuid.UUID) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	ws.running[id] = true
+	if !ws.stopping {
+		ws.stopping = true
+		ws.stop()
+	}
+	ws.exitIfDrained()
+}
+
+// protect protects the instance from scaling while any slot runs a job which
+// needs it
+func (ws *workerSlots) protect() {
+	ws.protectionMu.Lock()
+	defer ws.protectionMu.Unlock()
+
+	if ws.protectedJobs == 0 {
+		ws.setProtection(true)
+	}
+	ws.protectedJobs++
+}
+
+func (ws *workerSlots) unprotect() {
+	ws.protectionMu.Lock()
+	defer ws.protectionMu.Unlock()
+
+	ws.protectedJobs--
+	if ws.protectedJobs == 0 {
+		ws.setProtection(false)
+	}
+}
+
+// exitIfDrained exits the worker if it is stopping because of a canceled
+// job and only canceled jobs are left running, the lock needs to be held
+func (ws *workerSlots) exitIfDrained() {
+	if !ws.stopping {
+		return
+	}
+	for id, canceled := range ws.running {
+		if !canceled {
+			logrus.Infof("Waiting for job '%s' before exiting", id)
+			return
+		}
+	}
+	logrus.Info("Job was canceled. Exiting.")
+	ws.exit()
+}
+
+// Regularly ask osbuild-composer if the job was canceled, this also keeps
+// the heartbeat of the job alive. A canceled job makes the worker exit as
+// soon as the jobs of its other slots are done.
+// It would be cleaner to kill the osbuild process using (`exec.CommandContext`
+// or similar), but osbuild does not currently support this. Exiting here will
+// make systemd clean up the whole cgroup and restart this service.
+func (ws *workerSlots) watchJob(ctx context.Context, job worker.Job) {
+	for {
+		select {
+		case <-time.After(ws.watchInterval):
+			canceled, err := job.Canceled()
+			if err == nil && canceled {
+				logrus.Infof("Job '%s' (%s) was canceled", job.Id(), job.Type())
+				ws.jobCanceled(job.Id())
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// runSlot requests and runs jobs until the worker stops. It returns false if
+// the worker needs to be shut down.
+func (ws *workerSlots) runSlot(slot jobSlot) bool {
+	for !ws.isStopped() {
+		err := RequestAndRunJob(ws, slot.acceptedJobTypes, slot.jobImpls)
+		if err != nil {
+			logrus.Warnf("Received error from RequestAndRunJob in %s, backing off", slot.name)
+			select {
+			case <-time.After(backoffDuration):
+			case <-ws.stopped:
+			}
+		}
+		if ws.cleanStore && slot.store != "" {
+			err := os.RemoveAll(slot.store)
+			if err != nil {
+				// Shut down the worker in case of failure, as sharing stores
+				// between jobs should be avoided at all costs.
+				logrus.Errorf("Unable to clean out store: %s", err)
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// handleSignals drains the worker on the first SIGTERM or SIGINT and exits
+// on the second one
+func (ws *workerSlots) handleSignals() {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT)
+	go func() {
+		sig := <-signals
+		ws.drain(fmt.Sprintf("received %s", sig))
+		sig = <-signals
+		logrus.Warnf("Received %s while draining, exiting without waiting for running jobs", sig)
+		ws.exit()
+	}()
+}
+
+// run runs all slots in parallel until the worker stops. A drained worker
+// deregisters before run returns.
+func (ws *workerSlots) run(slots []jobSlot) {
+	ws.handleSignals()
+	if ws.maxLifetime > 0 {
+		lifetime := time.AfterFunc(ws.maxLifetime, func() {
+			ws.drain(fmt.Sprintf("reached the maximum lifetime of %s", ws.maxLifetime))
+		})
+		defer lifetime.Stop()
+	}
+
+	var wg sync.WaitGroup
+	var shutdownOnce sync.Once
+	for _, slot := range slots {
+		logrus.Infof("Starting %s for job types %v", slot.name, slot.acceptedJobTypes)
+		wg.Add(1)
+		go func(slot jobSlot) {
+			defer wg.Done()
+			if ws.runSlot(slot) {
+				return
+			}
+			ws.stop()
+			shutdownOnce.Do(func() {
+				err := awscloud.ShutdownSelf()
+				if err != nil {
+					logrus.Errorf("Unable to shut self down: %v", err)
+				}
+				stopSelf()
+			})
+		}(slot)
+	}
+	wg.Wait()
+
+	if ws.isDraining() {
+		err := ws.client.Deregister()
+		if err != nil {
+			logrus.Errorf("Unable to deregister worker: %v", err)
+			return
+		}
+		logrus.Info("Worker drained and deregistered. Exiting.")
+	}
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/worker"
+)
+
+func TestPlanJobSlots(t *testing.T) {
+	slots, err := planJobSlots(nil)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{resolveJobTypes, mainJobTypes}, slots)
+
+	slots, err = planJobSlots(map[string]int{
+		worker.JobTypeDepsolve:         3,
+		worker.JobTypeOSBuild:          2,
+		worker.JobTypeContainerResolve: 1,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{worker.JobTypeSearchPackages, worker.JobTypeImageBuilderManifest, worker.JobTypeRepositoryCheck},
+		{
+			worker.JobTypeKojiInit,
+			worker.JobTypeKojiFinalize,
+			worker.JobTypeOSTreeResolve,
+			worker.JobTypeFileResolve,
+			worker.JobTypeAWSEC2Copy,
+			worker.JobTypeAWSEC2Share,
+			worker.JobTypeBootcInfoResolve,
+			worker.JobTypeSourceBundleExport,
+			worker.JobTypeSourceBundleImport,
+		},
+		{worker.JobTypeContainerResolve},
+		{worker.JobTypeDepsolve},
+		{worker.JobTypeDepsolve},
+		{worker.JobTypeDepsolve},
+		{worker.JobTypeOSBuild},
+		{worker.JobTypeOSBuild},
+	}, slots)
+
+	// all resolve jobs have their own slots
+	slots, err = planJobSlots(map[string]int{
+		worker.JobTypeDepsolve:             1,
+		worker.JobTypeSearchPackages:       1,
+		worker.JobTypeImageBuilderManifest: 1,
+		worker.JobTypeRepositoryCheck:      1,
+	})
+	require.NoError(t, err)
+	assert.Len(t, slots, 5)
+	assert.Equal(t, mainJobTypes, slots[0])
+}
+
+func TestNewJobSlotsStores(t *testing.T) {
+	cacheDir := t.TempDir()
+	var stores, outputs []string
+	newJobImpls := func(store, output string) map[string]JobImplementation {
+		stores = append(stores, store)
+		outputs = append(outputs, output)
+		return map[string]JobImplementation{
+			worker.JobTypeOSBuild:  &OSBuildJobImpl{Store: store, Output: output},
+			worker.JobTypeDepsolve: &DepsolveJobImpl{},
+		}
+	}
+
+	slots, err := newJobSlots(map[string]int{worker.JobTypeOSBuild: 3}, cacheDir, newJobImpls)
+	require.NoError(t, err)
+	require.Len(t, slots, 5)
+
+	assert.Equal(t, []string{
+		"",
+		"",
+		filepath.Join(cacheDir, "osbuild-store"),
+		filepath.Join(cacheDir, "osbuild-store-1"),
+		filepath.Join(cacheDir, "osbuild-store-2"),
+	}, stores)
+	assert.Equal(t, filepath.Join(cacheDir, "output-2"), outputs[4])
+	for i, slot := range slots {
+		assert.Equal(t, stores[i], slot.store)
+		if i < 2 {
+			assert.NotContains(t, slot.jobImpls, worker.JobTypeOSBuild)
+			continue
+		}
+		assert.DirExists(t, outputs[i])
+		assert.Equal(t, []string{worker.JobTypeOSBuild}, slot.acceptedJobTypes)
+		assert.Equal(t, stores[i], slot.jobImpls[worker.JobTypeOSBuild].(*OSBuildJobImpl).Store)
+	}
+	// only the resolve slot runs depsolve jobs
+	assert.Contains(t, slots[0].jobImpls, worker.JobTypeDepsolve)
+	assert.NotContains(t, slots[1].jobImpls, worker.JobTypeDepsolve)
+
+	// the stores source bundles are imported into
+	allStores, err := osbuildStores(map[string]int{worker.JobTypeOSBuild: 3}, cacheDir)
+	require.NoError(t, err)
+	assert.Equal(t, stores[2:], allStores)
+
+	allStores, err = osbuildStores(nil, cacheDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(cacheDir, "osbuild-store")}, allStores)
+}
+
+func newTestWorkerSlots() (*workerSlots, *int) {
+	exits := 0
+	ws := newWorkerSlots(nil, false, 0, 0)
+	ws.exit = func() { exits++ }
+	ws.setProtection = func(bool) {}
+	return ws, &exits
+}
+
+func TestWorkerSlotsCanceledJob(t *testing.T) {
+	ws, exits := newTestWorkerSlots()
+
+	canceled := uuid.New()
+	other := uuid.New()
+	ws.jobStarted(canceled)
+	ws.jobStarted(other)
+
+	// the job of the other slot is finished before exiting
+	ws.jobCanceled(canceled)
+	assert.True(t, ws.isStopped())
+	assert.Equal(t, 0, *exits)
+
+	ws.jobDone(other)
+	assert.Equal(t, 1, *exits)
+}
+
+func TestWorkerSlotsCanceledOnlyJob(t *testing.T) {
+	ws, exits := newTestWorkerSlots()
+
+	id := uuid.New()
+	ws.jobStarted(id)
+	ws.jobCanceled(id)
+	assert.Equal(t, 1, *exits)
+}
+
+func TestWorkerSlotsJobDone(t *testing.T) {
+	ws, exits := newTestWorkerSlots()
+
+	id := uuid.New()
+	ws.jobStarted(id)
+	ws.jobDone(id)
+	assert.False(t, ws.isStopped())
+	assert.Equal(t, 0, *exits)
+}
+
+func TestWorkerSlotsMaxJobs(t *testing.T) {
+	ws, exits := newTestWorkerSlots()
+	ws.maxJobs = 2
+
+	first := uuid.New()
+	ws.jobStarted(first)
+	ws.jobDone(first)
+	assert.False(t, ws.isDraining())
+
+	second := uuid.New()
+	ws.jobStarted(second)
+	ws.jobDone(second)
+	assert.True(t, ws.isDraining())
+	assert.True(t, ws.isStopped())
+	assert.Error(t, ws.requestCtx.Err())
+
+	// draining workers deregister before exiting
+	assert.Equal(t, 0, *exits)
+}
+
+func TestWorkerSlotsDrainCanceledJob(t *testing.T) {
+	ws, exits := newTestWorkerSlots()
+
+	canceled := uuid.New()
+	other := uuid.New()
+	ws.jobStarted(canceled)
+	ws.jobStarted(other)
+
+	ws.drain("test")
+	assert.True(t, ws.isStopped())
+
+	// a canceled job still makes the worker exit once the others are done
+	ws.jobCanceled(canceled)
+	assert.Equal(t, 0, *exits)
+	ws.jobDone(other)
+	assert.Equal(t, 1, *exits)
+}
+
+func TestWorkerSlotsProtection(t *testing.T) {
+	ws, _ := newTestWorkerSlots()
+	var calls []bool
+	ws.setProtection = func(protected bool) {
+		calls = append(calls, protected)
+	}
+
+	ws.protect()
+	ws.protect()
+	ws.unprotect()
+	assert.Equal(t, []bool{true}, calls)
+	ws.unprotect()
+	assert.Equal(t, []bool{true, false}, calls)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"os"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/osbuild/image-builder/pkg/osbuild"
+	"github.com/osbuild/osbuild-composer/internal/osbuildstore"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+)
+
+// prepareStore marks the sources of the manifest as used and sets the size
+// osbuild may use for its objects. Managing the store is best effort, it
+// doesn't fail the build.
+func prepareStore(manager *osbuildstore.Manager, manifest []byte, opts *osbuild.OSBuildOptions, logger logrus.FieldLogger) *osbuildstore.Build {
+	build, err := manager.Prepare(manifest)
+	if err != nil {
+		logger.Warnf("Unable to track the use of the osbuild store: %v", err)
+		return nil
+	}
+	opts.CacheMaxSize, err = manager.ObjectsMaxSize()
+	if err != nil {
+		logger.Warnf("Unable to compute the size of the osbuild store: %v", err)
+	}
+	return build
+}
+
+// finishStore evicts sources from the store if needed and returns how the
+// build used the store
+func finishStore(manager *osbuildstore.Manager, build *osbuildstore.Build, logger logrus.FieldLogger) *worker.OSBuildStoreStats {
+	stats, err := manager.Finish(build)
+	if err != nil {
+		logger.Warnf("Unable to evict sources from the osbuild store: %v", err)
+	}
+	logger.Infof("osbuild store: %d sources cached, %d fetched (%d bytes), %d evicted (%d bytes), %d bytes used",
+		stats.SourceHits, stats.SourceMisses, stats.DownloadedBytes, stats.EvictedSources, stats.EvictedBytes, stats.Size)
+	return &worker.OSBuildStoreStats{
+		SourceHits:      stats.SourceHits,
+		SourceMisses:    stats.SourceMisses,
+		DownloadedBytes: stats.DownloadedBytes,
+		EvictedSources:  stats.EvictedSources,
+		EvictedBytes:    stats.EvictedBytes,
+		StoreSize:       stats.Size,
+	}
+}
+
+// prewarmStore fetches the sources of the manifests into the store, for
+// example the packages of the distributions which are built most often. The
+// manifests are run without exports, so osbuild only fetches their sources
+// and builds their checkpoints.
+func prewarmStore(manager *osbuildstore.Manager, store string, manifests []string) {
+	for _, path := range manifests {
+		logger := logrus.WithField("manifest", path)
+		manifest, err := os.ReadFile(path)
+		if err != nil {
+			logger.Errorf("Unable to read manifest to pre-warm the osbuild store: %v", err)
+			continue
+		}
+
+		output, err := os.MkdirTemp("", "osbuild-prewarm-")
+		if err != nil {
+			logger.Errorf("Unable to create the output directory to pre-warm the osbuild store: %v", err)
+			continue
+		}
+
+		opts := &osbuild.OSBuildOptions{
+			StoreDir:   store,
+			OutputDir:  output,
+			Stderr:     os.Stderr,
+			JSONOutput: true,
+		}
+		build := prepareStore(manager, manifest, opts, logger)
+		logger.Infof("Pre-warming osbuild store %s", store)
+		result, err := osbuild.RunOSBuild(manifest, opts)
+		if err != nil {
+			logger.Errorf("Unable to pre-warm the osbuild store: %v", err)
+		} else if !result.Success {
+			logger.Errorf("osbuild failed to pre-warm the store")
+		}
+		if build != nil {
+			finishStore(manager, build, logger)
+		}
+		os.RemoveAll(output)
+	}
+}
@@ -0,0 +1,279 @@
+// Package audit records who called which mutating operation of the APIs of
+// composer, and with which outcome.
+//
+// Records are appended as JSON lines to a file, which is rotated once it
+// exceeds a size limit. They can additionally be forwarded to a logrus
+// logger, so that they reach the journal or Splunk through its hooks.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// Record is a single audited call
+type Record struct {
+	Time time.Time `json:"time"`
+	// API is the API which was called, "cloudapi", "weldr" or "worker"
+	API string `json:"api"`
+	// Principal is the caller, e.g. the JWT subject, the common name of the
+	// client certificate or the unix user of the weldr socket
+	Principal string `json:"principal"`
+	// Tenant is the tenant channel of the caller
+	Tenant string `json:"tenant"`
+	// OperationID identifies the request in the logs of composer
+	OperationID string `json:"operation_id,omitempty"`
+	// Action is the name of the operation, or its method and route if the
+	// API doesn't name its operations
+	Action string `json:"action"`
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	// Details summarize the request, e.g. the ID of the created compose
+	Details map[string]string `json:"details,omitempty"`
+	Status  int               `json:"status"`
+	Outcome string            `json:"outcome"`
+}
+
+// Options of a Log
+type Options struct {
+	// MaxSize is the size in bytes at which the file is rotated, zero
+	// disables the rotation
+	MaxSize int64
+	// MaxFiles is the number of rotated files which are kept
+	MaxFiles int
+	// Logger, if set, gets every record as an info entry
+	Logger *logrus.Logger
+}
+
+// Log appends records to a JSON-lines file
+type Log struct {
+	path    string
+	options Options
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// NewLog opens the log at path, creating it and its directory if necessary
+func NewLog(path string, options Options) (*Log, error) {
+	err := os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create audit log directory: %w", err)
+	}
+
+	l := &Log{
+		path:    path,
+		options: options,
+	}
+	err = l.open()
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *Log) open() error {
+	file, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return fmt.Errorf("cannot open audit log: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("cannot open audit log: %w", err)
+	}
+	l.file = file
+	l.size = info.Size()
+	return nil
+}
+
+// rotatedPath returns the path of the nth rotated file, n = 0 is the
+// current one
+func (l *Log) rotatedPath(n int) string {
+	if n == 0 {
+		return l.path
+	}
+	return fmt.Sprintf("%s.%d", l.path, n)
+}
+
+// rotate moves the current file to the first rotated one and starts a new
+// one, dropping the oldest rotated file
+func (l *Log) rotate() error {
+	err := l.file.Close()
+	if err != nil {
+		return err
+	}
+
+	if l.options.MaxFiles > 0 {
+		err = os.Remove(l.rotatedPath(l.options.MaxFiles))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		for n := l.options.MaxFiles - 1; n >= 0; n-- {
+			err = os.Rename(l.rotatedPath(n), l.rotatedPath(n+1))
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+		}
+	} else {
+		err = os.Remove(l.path)
+		if err != nil {
+			return err
+		}
+	}
+
+	return l.open()
+}
+
+// Write appends the record to the log, setting its time if it's unset
+func (l *Log) Write(record Record) error {
+	if record.Time.IsZero() {
+		record.Time = time.Now().UTC()
+	}
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.options.MaxSize > 0 && l.size > 0 && l.size+int64(len(line)) > l.options.MaxSize {
+		err = l.rotate()
+		if err != nil {
+			return fmt.Errorf("cannot rotate audit log: %w", err)
+		}
+	}
+
+	n, err := l.file.Write(line)
+	l.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("cannot write audit log: %w", err)
+	}
+
+	if l.options.Logger != nil {
+		l.options.Logger.WithFields(logrus.Fields{
+			"audit":        true,
+			"api":          record.API,
+			"principal":    record.Principal,
+			"tenant":       record.Tenant,
+			"operation_id": record.OperationID,
+			"action":       record.Action,
+			"path":         record.Path,
+			"status":       record.Status,
+			"outcome":      record.Outcome,
+		}).Infof("Audit: %s by %q: %s", record.Action, record.Principal, record.Outcome)
+	}
+
+	return nil
+}
+
+// Close closes the file of the log
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
+
+// Filter selects records in Query. Empty fields match all records.
+type Filter struct {
+	Tenant    *string
+	Principal string
+	Action    string
+	Since     time.Time
+	Until     time.Time
+	// Limit is the maximum number of records returned, the most recent
+	// ones are kept
+	Limit int
+}
+
+func (f *Filter) matches(record *Record) bool {
+	if f.Tenant != nil && record.Tenant != *f.Tenant {
+		return false
+	}
+	if f.Principal != "" && record.Principal != f.Principal {
+		return false
+	}
+	if f.Action != "" && record.Action != f.Action {
+		return false
+	}
+	if !f.Since.IsZero() && record.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !record.Time.Before(f.Until) {
+		return false
+	}
+	return true
+}
+
+// Query returns the records matching filter, including the ones in the
+// rotated files, oldest first
+func (l *Log) Query(filter Filter) ([]Record, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	records := []Record{}
+	for n := l.options.MaxFiles; n >= 0; n-- {
+		var err error
+		records, err = l.queryFile(l.rotatedPath(n), &filter, records)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if filter.Limit > 0 && len(records) > filter.Limit {
+		records = records[len(records)-filter.Limit:]
+	}
+	return records, nil
+}
+
+func (l *Log) queryFile(path string, filter *Filter, records []Record) ([]Record, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return records, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read audit log: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var record Record
+		err = json.Unmarshal([]byte(line), &record)
+		if err != nil {
+			// a partially written line, e.g. after a crash
+			logrus.Warnf("Skipping malformed line in audit log %s: %v", path, err)
+			continue
+		}
+		if filter.matches(&record) {
+			records = append(records, record)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read audit log: %w", err)
+	}
+	return records, nil
+}
@@ -0,0 +1,125 @@
+package audit_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	logrusTest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/audit"
+	"github.com/osbuild/osbuild-composer/internal/common"
+)
+
+func TestWriteAndQuery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit", "audit.jsonl")
+	log, err := audit.NewLog(path, audit.Options{})
+	require.NoError(t, err)
+	defer log.Close()
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	records := []audit.Record{
+		{Time: start, API: "cloudapi", Principal: "alice", Tenant: "org-1", Action: "postCompose", Status: 201, Outcome: audit.OutcomeSuccess},
+		{Time: start.Add(time.Hour), API: "cloudapi", Principal: "bob", Tenant: "org-2", Action: "postCompose", Status: 201, Outcome: audit.OutcomeSuccess},
+		{Time: start.Add(2 * time.Hour), API: "cloudapi", Principal: "alice", Tenant: "org-1", Action: "deleteCompose", Status: 403, Outcome: audit.OutcomeFailure},
+		{Time: start.Add(3 * time.Hour), API: "weldr", Principal: "uid=1000(alice)", Action: "POST /api/v1/compose", Status: 200, Outcome: audit.OutcomeSuccess},
+	}
+	for _, record := range records {
+		require.NoError(t, log.Write(record))
+	}
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	result, err := log.Query(audit.Filter{})
+	require.NoError(t, err)
+	assert.Equal(t, records, result)
+
+	result, err = log.Query(audit.Filter{Tenant: common.ToPtr("org-1")})
+	require.NoError(t, err)
+	assert.Equal(t, []audit.Record{records[0], records[2]}, result)
+
+	result, err = log.Query(audit.Filter{Tenant: common.ToPtr("")})
+	require.NoError(t, err)
+	assert.Equal(t, []audit.Record{records[3]}, result)
+
+	result, err = log.Query(audit.Filter{Principal: "alice", Action: "deleteCompose"})
+	require.NoError(t, err)
+	assert.Equal(t, []audit.Record{records[2]}, result)
+
+	result, err = log.Query(audit.Filter{Since: start.Add(time.Hour), Until: start.Add(3 * time.Hour)})
+	require.NoError(t, err)
+	assert.Equal(t, records[1:3], result)
+
+	// the most recent records are kept
+	result, err = log.Query(audit.Filter{Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, records[2:], result)
+
+	// records survive reopening the log
+	require.NoError(t, log.Close())
+	log, err = audit.NewLog(path, audit.Options{})
+	require.NoError(t, err)
+	require.NoError(t, log.Write(audit.Record{Principal: "carol"}))
+	result, err = log.Query(audit.Filter{})
+	require.NoError(t, err)
+	require.Len(t, result, 5)
+	assert.Equal(t, "carol", result[4].Principal)
+	assert.False(t, result[4].Time.IsZero())
+}
+
+func TestRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	log, err := audit.NewLog(path, audit.Options{MaxSize: 300, MaxFiles: 2})
+	require.NoError(t, err)
+	defer log.Close()
+
+	for i := 0; i < 20; i++ {
+		require.NoError(t, log.Write(audit.Record{Principal: "alice", Status: i}))
+	}
+
+	for _, p := range []string{path, path + ".1", path + ".2"} {
+		info, err := os.Stat(p)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, info.Size(), int64(300))
+	}
+	assert.NoFileExists(t, path+".3")
+
+	// the oldest records were dropped, the others are still in order
+	result, err := log.Query(audit.Filter{})
+	require.NoError(t, err)
+	require.NotEmpty(t, result)
+	require.Less(t, len(result), 20)
+	for i, record := range result {
+		assert.Equal(t, 20-len(result)+i, record.Status)
+	}
+}
+
+func TestForwardToLogger(t *testing.T) {
+	logger, hook := logrusTest.NewNullLogger()
+	log, err := audit.NewLog(filepath.Join(t.TempDir(), "audit.jsonl"), audit.Options{Logger: logger})
+	require.NoError(t, err)
+	defer log.Close()
+
+	require.NoError(t, log.Write(audit.Record{
+		API:       "cloudapi",
+		Principal: "alice",
+		Tenant:    "org-1",
+		Action:    "deleteCompose",
+		Status:    200,
+		Outcome:   audit.OutcomeSuccess,
+	}))
+
+	entry := hook.LastEntry()
+	require.NotNil(t, entry)
+	assert.Equal(t, logrus.InfoLevel, entry.Level)
+	assert.Equal(t, true, entry.Data["audit"])
+	assert.Equal(t, "alice", entry.Data["principal"])
+	assert.Equal(t, "org-1", entry.Data["tenant"])
+	assert.Equal(t, "deleteCompose", entry.Data["action"])
+}
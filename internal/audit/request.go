@@ -0,0 +1,163 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+
+	"github.com/osbuild/osbuild-composer/internal/auth"
+	"github.com/osbuild/osbuild-composer/internal/common"
+)
+
+// Mutating returns whether requests with the method change state and are
+// therefore audited
+func Mutating(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	default:
+		return true
+	}
+}
+
+// request is the part of a record the handlers of an audited request fill
+// in
+type request struct {
+	action  string
+	details map[string]string
+}
+
+type requestCtxKey struct{}
+
+func withRequest(r *http.Request) (*http.Request, *request) {
+	state := &request{}
+	return r.WithContext(context.WithValue(r.Context(), requestCtxKey{}, state)), state
+}
+
+func requestFromContext(ctx context.Context) *request {
+	state, _ := ctx.Value(requestCtxKey{}).(*request)
+	return state
+}
+
+// SetAction names the operation of an audited request. It does nothing if
+// the request isn't audited.
+func SetAction(ctx context.Context, action string) {
+	if state := requestFromContext(ctx); state != nil {
+		state.action = action
+	}
+}
+
+// Annotate adds a detail to the record of an audited request, e.g. the ID of
+// the compose the request created. It does nothing if the request isn't
+// audited.
+func Annotate(ctx context.Context, key, value string) {
+	if state := requestFromContext(ctx); state != nil {
+		if state.details == nil {
+			state.details = map[string]string{}
+		}
+		state.details[key] = value
+	}
+}
+
+func (l *Log) record(r *http.Request, state *request, api, principal, tenant, operationID, route string, status int) {
+	record := Record{
+		API:         api,
+		Principal:   principal,
+		Tenant:      tenant,
+		OperationID: operationID,
+		Action:      state.action,
+		Method:      r.Method,
+		Path:        r.URL.Path,
+		Details:     state.details,
+		Status:      status,
+		Outcome:     OutcomeSuccess,
+	}
+	if record.Action == "" {
+		record.Action = r.Method + " " + route
+	}
+	if status >= http.StatusBadRequest {
+		record.Outcome = OutcomeFailure
+	}
+
+	err := l.Write(record)
+	if err != nil {
+		logrus.Errorf("Error writing audit record of %s %s: %v", r.Method, r.URL.Path, err)
+	}
+}
+
+// Middleware records the mutating requests of an echo API. principal
+// identifies the caller, it's called after the request was handled so that
+// it can rely on the authentication middlewares. The tenant channel and
+// operation ID are taken from the context. If l is nil, nothing is recorded.
+func Middleware(l *Log, api string, principal func(echo.Context) string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if l == nil || !Mutating(c.Request().Method) {
+				return next(c)
+			}
+
+			request, state := withRequest(c.Request())
+			c.SetRequest(request)
+
+			err := next(c)
+			status := c.Response().Status
+			if err != nil {
+				// the response is written by the error handler later
+				status = http.StatusInternalServerError
+				var httpError *echo.HTTPError
+				if errors.As(err, &httpError) {
+					status = httpError.Code
+				}
+			}
+
+			tenant, _ := c.Get(auth.TenantCtxKey).(string)
+			operationID, _ := c.Get(common.OperationIDKey).(string)
+			l.record(request, state, api, principal(c), tenant, operationID, c.Path(), status)
+			return err
+		}
+	}
+}
+
+// statusRecorder remembers the status of a response
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusRecorder) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusRecorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Handler records the mutating requests of a plain http API. Callers
+// identified by principal have the default tenant channel. If l is nil,
+// nothing is recorded.
+func Handler(l *Log, api string, principal func(*http.Request) string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, r *http.Request) {
+		if l == nil || !Mutating(r.Method) {
+			next.ServeHTTP(writer, r)
+			return
+		}
+
+		request, state := withRequest(r)
+		recorder := &statusRecorder{ResponseWriter: writer}
+		next.ServeHTTP(recorder, request)
+		if recorder.status == 0 {
+			recorder.status = http.StatusOK
+		}
+
+		l.record(request, state, api, principal(request), "", "", r.URL.Path, recorder.status)
+	})
+}
@@ -0,0 +1,115 @@
+package audit_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/audit"
+	"github.com/osbuild/osbuild-composer/internal/auth"
+	"github.com/osbuild/osbuild-composer/internal/common"
+)
+
+func TestMiddleware(t *testing.T) {
+	log, err := audit.NewLog(filepath.Join(t.TempDir(), "audit.jsonl"), audit.Options{})
+	require.NoError(t, err)
+	defer log.Close()
+
+	e := echo.New()
+	e.Pre(common.OperationIDMiddleware)
+	tenant := func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set(auth.TenantCtxKey, "org-1")
+			return next(c)
+		}
+	}
+	g := e.Group("/api", audit.Middleware(log, "cloudapi", func(echo.Context) string { return "alice" }), tenant)
+	g.GET("/composes/:id", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	g.POST("/compose", func(c echo.Context) error {
+		audit.SetAction(c.Request().Context(), "postCompose")
+		audit.Annotate(c.Request().Context(), "compose_id", "42")
+		return c.NoContent(http.StatusCreated)
+	})
+	g.DELETE("/composes/:id", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusForbidden)
+	})
+
+	for _, r := range []struct {
+		method, path string
+		status       int
+	}{
+		{http.MethodGet, "/api/composes/42", http.StatusOK},
+		{http.MethodPost, "/api/compose", http.StatusCreated},
+		{http.MethodDelete, "/api/composes/42", http.StatusForbidden},
+	} {
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, httptest.NewRequest(r.method, r.path, nil))
+		require.Equal(t, r.status, recorder.Code)
+	}
+
+	records, err := log.Query(audit.Filter{})
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	assert.Equal(t, "cloudapi", records[0].API)
+	assert.Equal(t, "alice", records[0].Principal)
+	assert.Equal(t, "org-1", records[0].Tenant)
+	assert.NotEmpty(t, records[0].OperationID)
+	assert.Equal(t, "postCompose", records[0].Action)
+	assert.Equal(t, map[string]string{"compose_id": "42"}, records[0].Details)
+	assert.Equal(t, http.StatusCreated, records[0].Status)
+	assert.Equal(t, audit.OutcomeSuccess, records[0].Outcome)
+
+	assert.Equal(t, "DELETE /api/composes/:id", records[1].Action)
+	assert.Equal(t, "/api/composes/42", records[1].Path)
+	assert.Equal(t, http.StatusForbidden, records[1].Status)
+	assert.Equal(t, audit.OutcomeFailure, records[1].Outcome)
+}
+
+func TestHandler(t *testing.T) {
+	log, err := audit.NewLog(filepath.Join(t.TempDir(), "audit.jsonl"), audit.Options{})
+	require.NoError(t, err)
+	defer log.Close()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/blueprints/new", func(w http.ResponseWriter, r *http.Request) {
+		audit.Annotate(r.Context(), "blueprint", "base")
+		_, _ = w.Write([]byte(`{"status": true}`))
+	})
+	mux.HandleFunc("/api/v1/compose/cancel/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	})
+	handler := audit.Handler(log, "weldr", func(*http.Request) string { return "uid=1000(alice)" }, mux)
+
+	for _, r := range []struct {
+		method, path string
+	}{
+		{http.MethodGet, "/api/v1/blueprints/list"},
+		{http.MethodPost, "/api/v1/blueprints/new"},
+		{http.MethodDelete, "/api/v1/compose/cancel/42"},
+	} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(r.method, r.path, nil))
+	}
+
+	records, err := log.Query(audit.Filter{})
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	assert.Equal(t, "weldr", records[0].API)
+	assert.Equal(t, "uid=1000(alice)", records[0].Principal)
+	assert.Equal(t, "", records[0].Tenant)
+	assert.Equal(t, "POST /api/v1/blueprints/new", records[0].Action)
+	assert.Equal(t, map[string]string{"blueprint": "base"}, records[0].Details)
+	assert.Equal(t, http.StatusOK, records[0].Status)
+
+	assert.Equal(t, "DELETE /api/v1/compose/cancel/42", records[1].Action)
+	assert.Equal(t, http.StatusBadRequest, records[1].Status)
+	assert.Equal(t, audit.OutcomeFailure, records[1].Outcome)
+}
@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+const APIKeyCtxKey string = "api-key"
+
+// APIKey is a static token identifying a service account, for deployments
+// without an OIDC provider issuing JWTs
+type APIKey struct {
+	// Name of the service account
+	Name string
+	// Tenant of the service account, the tenant channel of its requests is
+	// derived from it like from the tenant claim of a JWT. Service accounts
+	// without a tenant use the default channel.
+	Tenant string
+	// Role of the service account, it's up to the API to interpret it
+	Role string
+}
+
+// HashAPIKey returns the hex encoded SHA-256 of key. API keys are configured
+// by their hash, so that the configuration doesn't contain them.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// APIKeyMiddleware authenticates callers passing an API key as bearer token
+// in the Authorization header. keys maps the hashes of the keys (see
+// HashAPIKey) to their service accounts. The APIKey and the tenant channel of
+// the caller are put into the context.
+//
+// Requests without an API key are passed on unchanged, requests with an
+// unknown one fail with onFail.
+func APIKeyMiddleware(keys map[string]APIKey, onFail error) func(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			header := ctx.Request().Header.Get("Authorization")
+			if header == "" {
+				return next(ctx)
+			}
+			token, found := strings.CutPrefix(header, "Bearer ")
+			if !found {
+				return onFail
+			}
+			key, ok := keys[HashAPIKey(token)]
+			if !ok {
+				return onFail
+			}
+
+			ctx.Set(APIKeyCtxKey, key)
+			channel := ""
+			if key.Tenant != "" {
+				channel = fmt.Sprintf("org-%s", key.Tenant)
+			}
+			ctx.Set(TenantCtxKey, channel)
+
+			return next(ctx)
+		}
+	}
+}
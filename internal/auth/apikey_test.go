@@ -0,0 +1,52 @@
+package auth_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/auth"
+)
+
+func TestAPIKeyMiddleware(t *testing.T) {
+	errInvalidKey := errors.New("invalid key")
+	keys := map[string]auth.APIKey{
+		auth.HashAPIKey("ci-secret"):    {Name: "ci", Tenant: "42", Role: "composer"},
+		auth.HashAPIKey("admin-secret"): {Name: "admin", Role: "admin"},
+	}
+	middleware := auth.APIKeyMiddleware(keys, errInvalidKey)
+
+	call := func(authorization string) (echo.Context, error) {
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		if authorization != "" {
+			request.Header.Set("Authorization", authorization)
+		}
+		ctx := echo.New().NewContext(request, httptest.NewRecorder())
+		return ctx, middleware(func(echo.Context) error { return nil })(ctx)
+	}
+
+	ctx, err := call("Bearer ci-secret")
+	require.NoError(t, err)
+	require.Equal(t, keys[auth.HashAPIKey("ci-secret")], ctx.Get(auth.APIKeyCtxKey))
+	require.Equal(t, "org-42", ctx.Get(auth.TenantCtxKey))
+
+	// service accounts without a tenant use the default channel
+	ctx, err = call("Bearer admin-secret")
+	require.NoError(t, err)
+	require.Equal(t, "", ctx.Get(auth.TenantCtxKey))
+
+	// requests without a key are left to the API
+	ctx, err = call("")
+	require.NoError(t, err)
+	require.Nil(t, ctx.Get(auth.APIKeyCtxKey))
+	require.Nil(t, ctx.Get(auth.TenantCtxKey))
+
+	for _, authorization := range []string{"Bearer unknown", "ci-secret", "Basic Y2k6c2VjcmV0"} {
+		_, err = call(authorization)
+		require.ErrorIs(t, err, errInvalidKey, authorization)
+	}
+}
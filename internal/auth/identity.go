@@ -0,0 +1,24 @@
+package auth
+
+import (
+	"net/http"
+)
+
+// CallerIdentity returns the name of the caller of a request, which is either
+// the common name of its TLS client certificate or the value of the first
+// non-empty JWT claim in jwtUserFields. An empty string is returned if the
+// caller cannot be identified.
+func CallerIdentity(request *http.Request, jwtUserFields []string) string {
+	if request.TLS != nil && len(request.TLS.PeerCertificates) > 0 {
+		return request.TLS.PeerCertificates[0].Subject.CommonName
+	}
+
+	if len(jwtUserFields) > 0 {
+		user, err := GetFromClaims(request.Context(), jwtUserFields)
+		if err == nil {
+			return user
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+// CheckJWKS verifies that the JSON web key set at keysURL can be fetched and
+// holds at least one key, the same way BuildJWTAuthHandler fetches it.
+// caFile is the optional CA of the server.
+func CheckJWKS(ctx context.Context, keysURL, caFile string) error {
+	client := http.DefaultClient
+	if caFile != "" {
+		caPEM, err := os.ReadFile(caFile)
+		if err != nil {
+			return err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return fmt.Errorf("Unable to load jwt ca cert %s.", caFile)
+		}
+		client = &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					RootCAs:    pool,
+					MinVersion: tls.VersionTLS12,
+				},
+			},
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, keysURL, nil)
+	if err != nil {
+		return err
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetching %s returned status %d", keysURL, res.StatusCode)
+	}
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	var keys struct {
+		Keys []json.RawMessage `json:"keys"`
+	}
+	if err := json.Unmarshal(body, &keys); err != nil {
+		return fmt.Errorf("%s is not a JSON web key set: %v", keysURL, err)
+	}
+	if len(keys.Keys) == 0 {
+		return fmt.Errorf("%s has no keys", keysURL)
+	}
+	return nil
+}
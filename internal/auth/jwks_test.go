@@ -0,0 +1,41 @@
+package auth_test
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/auth"
+)
+
+func TestCheckJWKS(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/certs", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"keys": [{"kty": "RSA", "kid": "key", "n": "AQAB", "e": "AQAB"}]}`))
+	})
+	mux.HandleFunc("/empty", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"keys": []}`))
+	})
+	server := httptest.NewTLSServer(mux)
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: server.Certificate().Raw,
+	}), 0600))
+
+	ctx := context.Background()
+	require.NoError(t, auth.CheckJWKS(ctx, server.URL+"/certs", caFile))
+	require.EqualError(t, auth.CheckJWKS(ctx, server.URL+"/empty", caFile), server.URL+"/empty has no keys")
+	require.EqualError(t, auth.CheckJWKS(ctx, server.URL+"/missing", caFile), "fetching "+server.URL+"/missing returned status 404")
+
+	// the server isn't trusted without its CA
+	require.Error(t, auth.CheckJWKS(ctx, server.URL+"/certs", ""))
+}
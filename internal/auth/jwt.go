@@ -37,3 +37,39 @@ func GetFromClaims(ctx context.Context, keys []string) (string, error) {
 
 	return "", ErrNoKey
 }
+
+// GetListFromClaims returns the values of all the JWT claims with the
+// specified keys. A claim may either be a string or a list of strings, other
+// values are ignored.
+//
+// If no claim is found, NoKeyError is returned
+func GetListFromClaims(ctx context.Context, keys []string) ([]string, error) {
+	token, err := authentication.TokenFromContext(ctx)
+	if err != nil {
+		return nil, err
+	} else if token == nil {
+		return nil, ErrNoJWT
+	}
+
+	claims := token.Claims.(jwt.MapClaims)
+	var values []string
+	for _, f := range keys {
+		switch value := claims[f].(type) {
+		case string:
+			if value != "" {
+				values = append(values, value)
+			}
+		case []interface{}:
+			for _, v := range value {
+				if s, ok := v.(string); ok && s != "" {
+					values = append(values, s)
+				}
+			}
+		}
+	}
+
+	if len(values) == 0 {
+		return nil, ErrNoKey
+	}
+	return values, nil
+}
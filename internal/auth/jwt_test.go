@@ -64,3 +64,23 @@ func TestChannelFromContext(t *testing.T) {
 		require.Equal(t, "", channel)
 	})
 }
+
+func TestGetListFromClaims(t *testing.T) {
+	ctx := authentication.ContextWithToken(context.Background(), &jwt.Token{
+		Claims: jwt.MapClaims{
+			"role":   "composer",
+			"groups": []interface{}{"viewers", 42, "", "admins"},
+			"empty":  "",
+		},
+	})
+
+	values, err := auth.GetListFromClaims(ctx, []string{"groups", "missing", "role"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"viewers", "admins", "composer"}, values)
+
+	_, err = auth.GetListFromClaims(ctx, []string{"empty", "missing"})
+	require.ErrorIs(t, err, auth.ErrNoKey)
+
+	_, err = auth.GetListFromClaims(context.Background(), []string{"role"})
+	require.ErrorIs(t, err, auth.ErrNoJWT)
+}
@@ -47,7 +47,7 @@ func executeTests(m *testing.M) int {
 	fixture := rpmmd_mock.BaseFixture(path.Join(tmpdir, "/jobs"), test_distro.TestDistro1Name, test_distro.TestArchName)
 	defer fixture.StoreFixture.Cleanup()
 
-	_, err = fixture.Workers.RegisterWorker("", fixture.StoreFixture.HostArchName)
+	_, err = fixture.Workers.RegisterWorker("", fixture.StoreFixture.HostArchName, "", nil)
 	if err != nil {
 		panic(err)
 	}
@@ -11,10 +11,12 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/feature/ec2/imds"
 	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 	images_awscloud "github.com/osbuild/image-builder/pkg/cloud/awscloud"
 )
 
@@ -118,6 +120,40 @@ func NewDefault(region string) (*AWS, error) {
 	return aws, nil
 }
 
+// NewFromAssumedRole initializes a new AWS object with temporary credentials
+// of the role roleARN. The role is assumed with the credentials found at
+// filename's location, or with the defaults if filename is empty. The
+// temporary credentials expire after the maximum session duration of the
+// role, one hour by default.
+func NewFromAssumedRole(filename, region, roleARN, externalID, sessionName string) (*AWS, error) {
+	optFns := []func(*config.LoadOptions) error{
+		config.WithRegion(region),
+	}
+	if filename != "" {
+		optFns = append(optFns, config.WithSharedCredentialsFiles([]string{
+			filename,
+			"default",
+		}))
+	}
+	cfg, err := config.LoadDefaultConfig(context.Background(), optFns...)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), roleARN, func(o *stscreds.AssumeRoleOptions) {
+		o.RoleSessionName = sessionName
+		if externalID != "" {
+			o.ExternalID = &externalID
+		}
+	})
+	creds, err := provider.Retrieve(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("cannot assume role %s: %w", roleARN, err)
+	}
+
+	return New(region, creds.AccessKeyID, creds.SecretAccessKey, creds.SessionToken)
+}
+
 func RegionFromInstanceMetadata() (string, error) {
 	identity, err := imds.New(imds.Options{}).GetInstanceIdentityDocument(
 		context.Background(),
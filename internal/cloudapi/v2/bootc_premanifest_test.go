@@ -273,7 +273,7 @@ func TestHandleBootcPreManifest_Errors(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			ws := newTestWorkerServer(t)
-			preManifestJobID, err := ws.EnqueueBootcPreManifestJob(tt.job, nil, "")
+			preManifestJobID, err := ws.EnqueueBootcPreManifestJob(context.Background(), tt.job, nil, "")
 			require.NoError(t, err)
 			jobID, token, _, _, _, err := ws.RequestJob(
 				context.Background(), "",
@@ -317,7 +317,7 @@ func enqueuePreManifestWithResolvedDep(t *testing.T, ws *worker.Server, arch str
 	infoResolveJob := &worker.BootcInfoResolveJob{
 		Specs: specs,
 	}
-	infoResolveJobID, err := ws.EnqueueBootcInfoResolveJob(arch, infoResolveJob, "")
+	infoResolveJobID, err := ws.EnqueueBootcInfoResolveJob(context.Background(), arch, infoResolveJob, "")
 	require.NoError(t, err)
 
 	preManifestJob := &worker.BootcPreManifestJob{
@@ -329,7 +329,7 @@ func enqueuePreManifestWithResolvedDep(t *testing.T, ws *worker.Server, arch str
 		UploadTargets:              uploadTargets,
 	}
 	preManifestJobID, err := ws.EnqueueBootcPreManifestJob(
-		preManifestJob, []uuid.UUID{infoResolveJobID}, "",
+		context.Background(), preManifestJob, []uuid.UUID{infoResolveJobID}, "",
 	)
 	require.NoError(t, err)
 
@@ -756,7 +756,7 @@ func TestSerializeManifest_PreManifestErrors(t *testing.T) {
 				Seed:                       42,
 				BootcInfoResolveDynArgsIdx: common.ToPtr(0),
 			}
-			preManifestJobID, err := workerServer.EnqueueBootcPreManifestJob(preManifestJob, nil, "")
+			preManifestJobID, err := workerServer.EnqueueBootcPreManifestJob(context.Background(), preManifestJob, nil, "")
 			require.NoError(t, err)
 
 			jobID, token, _, _, _, err := workerServer.RequestJob(
@@ -773,6 +773,7 @@ func TestSerializeManifest_PreManifestErrors(t *testing.T) {
 			require.NoError(t, err)
 
 			manifestJobID, err := workerServer.EnqueueManifestJobByID(
+				context.Background(),
 				&worker.ManifestJobByID{},
 				[]uuid.UUID{preManifestJobID},
 				"",
@@ -0,0 +1,239 @@
+package v2
+
+// Handlers of the cloud credentials tenants register to upload their images
+// to their own accounts
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/osbuild/osbuild-composer/internal/audit"
+	"github.com/osbuild/osbuild-composer/internal/cloudcredentials"
+	"github.com/osbuild/osbuild-composer/internal/common"
+	"github.com/osbuild/osbuild-composer/internal/target"
+)
+
+func cloudCredentialsFromAPI(request CloudCredentialsRequest) cloudcredentials.Credentials {
+	creds := cloudcredentials.Credentials{
+		Name: request.Name,
+	}
+	if request.Aws != nil {
+		creds.AWS = &cloudcredentials.AWSRole{
+			RoleARN: request.Aws.RoleArn,
+			Bucket:  request.Aws.Bucket,
+		}
+		if request.Aws.ExternalId != nil {
+			creds.AWS.ExternalID = *request.Aws.ExternalId
+		}
+	}
+	if request.Gcp != nil {
+		creds.GCP = &cloudcredentials.GCPServiceAccount{
+			ServiceAccount: request.Gcp.ServiceAccount,
+			ProjectID:      request.Gcp.ProjectId,
+		}
+		if request.Gcp.Bucket != nil {
+			creds.GCP.Bucket = *request.Gcp.Bucket
+		}
+	}
+	if request.Azure != nil {
+		creds.Azure = &cloudcredentials.AzureServicePrincipal{
+			ClientID: request.Azure.ClientId,
+		}
+		if request.Azure.ClientSecret != nil {
+			creds.Azure.ClientSecret = *request.Azure.ClientSecret
+		}
+	}
+	return creds
+}
+
+// cloudCredentialsToAPI converts the credentials for the API, their secrets
+// are never returned
+func cloudCredentialsToAPI(creds cloudcredentials.Credentials) CloudCredentials {
+	creds = creds.Redacted()
+	result := CloudCredentials{
+		Href:      fmt.Sprintf("/api/image-builder-composer/v2/cloud-credentials/%v", creds.ID),
+		Id:        creds.ID.String(),
+		Kind:      "CloudCredentials",
+		Name:      creds.Name,
+		Type:      CloudCredentialsType(creds.Type()),
+		CreatedAt: creds.Created,
+	}
+	if creds.AWS != nil {
+		result.Aws = &AWSCloudCredentials{
+			RoleArn: creds.AWS.RoleARN,
+			Bucket:  creds.AWS.Bucket,
+		}
+		if creds.AWS.ExternalID != "" {
+			result.Aws.ExternalId = common.ToPtr(creds.AWS.ExternalID)
+		}
+	}
+	if creds.GCP != nil {
+		result.Gcp = &GCPCloudCredentials{
+			ServiceAccount: creds.GCP.ServiceAccount,
+			ProjectId:      creds.GCP.ProjectID,
+		}
+		if creds.GCP.Bucket != "" {
+			result.Gcp.Bucket = common.ToPtr(creds.GCP.Bucket)
+		}
+	}
+	if creds.Azure != nil {
+		result.Azure = &AzureCloudCredentials{
+			ClientId: creds.Azure.ClientID,
+		}
+	}
+	return result
+}
+
+// cloudCredentialsRegistry returns the registry of the worker server, or an
+// error if tenants can't register credentials
+func (s *Server) cloudCredentialsRegistry() (*cloudcredentials.Registry, error) {
+	registry := s.workers.CloudCredentials()
+	if registry == nil {
+		return nil, HTTPError(ErrorCloudCredentialsUnavailable)
+	}
+	return registry, nil
+}
+
+// getCloudCredentials returns the credentials with id of channel
+func (s *Server) getCloudCredentials(channel string, id uuid.UUID) (cloudcredentials.Credentials, error) {
+	registry, err := s.cloudCredentialsRegistry()
+	if err != nil {
+		return cloudcredentials.Credentials{}, err
+	}
+	creds, err := registry.Get(channel, id)
+	if errors.Is(err, cloudcredentials.ErrNotFound) {
+		return cloudcredentials.Credentials{}, HTTPError(ErrorCloudCredentialsNotFound)
+	}
+	if err != nil {
+		return cloudcredentials.Credentials{}, HTTPErrorWithInternal(ErrorCloudCredentialsNotFound, err)
+	}
+	return creds, nil
+}
+
+// checkCloudCredentials makes sure the cloud credentials the targets refer
+// to belong to channel and are for the cloud of the target. The worker only
+// resolves the credentials of the tenant of the job, checking them here
+// fails the request instead of the upload.
+func (s *Server) checkCloudCredentials(channel string, targets []*target.Target) error {
+	for _, t := range targets {
+		var id *uuid.UUID
+		var cloud cloudcredentials.Type
+		switch options := t.Options.(type) {
+		case *target.AWSTargetOptions:
+			id, cloud = options.CloudCredentials, cloudcredentials.TypeAWS
+		case *target.AWSS3TargetOptions:
+			id, cloud = options.CloudCredentials, cloudcredentials.TypeAWS
+		case *target.GCPTargetOptions:
+			id, cloud = options.CloudCredentials, cloudcredentials.TypeGCP
+		case *target.AzureImageTargetOptions:
+			id, cloud = options.CloudCredentials, cloudcredentials.TypeAzure
+		}
+		if id == nil {
+			continue
+		}
+
+		creds, err := s.getCloudCredentials(channel, *id)
+		if err != nil {
+			return err
+		}
+		if creds.Type() != cloud {
+			return HTTPErrorWithInternal(ErrorCloudCredentialsTypeMismatch,
+				fmt.Errorf("cloud credentials %s are for %s, the target uploads to %s", creds.ID, creds.Type(), cloud))
+		}
+	}
+	return nil
+}
+
+func (h *apiHandlers) GetCloudCredentialsList(ctx echo.Context) error {
+	channel, err := h.server.getTenantChannel(ctx)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorTenantNotFound, err)
+	}
+	registry, err := h.server.cloudCredentialsRegistry()
+	if err != nil {
+		return err
+	}
+
+	items := []CloudCredentials{}
+	for _, creds := range registry.List(channel) {
+		items = append(items, cloudCredentialsToAPI(creds))
+	}
+	return ctx.JSON(http.StatusOK, CloudCredentialsList{
+		Kind:  "CloudCredentialsList",
+		Page:  0,
+		Size:  len(items),
+		Total: len(items),
+		Items: items,
+	})
+}
+
+func (h *apiHandlers) PostCloudCredentials(ctx echo.Context) error {
+	channel, err := h.server.getTenantChannel(ctx)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorTenantNotFound, err)
+	}
+	registry, err := h.server.cloudCredentialsRegistry()
+	if err != nil {
+		return err
+	}
+
+	var request CloudCredentialsRequest
+	err = ctx.Bind(&request)
+	if err != nil {
+		return err
+	}
+
+	creds := cloudCredentialsFromAPI(request)
+	err = creds.Validate()
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorInvalidCloudCredentials, err)
+	}
+	creds, err = registry.Add(channel, creds)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorFailedToStoreCloudCredentials, err)
+	}
+	audit.Annotate(ctx.Request().Context(), "cloud_credentials_id", creds.ID.String())
+
+	return ctx.JSON(http.StatusCreated, cloudCredentialsToAPI(creds))
+}
+
+func (h *apiHandlers) GetCloudCredentials(ctx echo.Context, id uuid.UUID) error {
+	channel, err := h.server.getTenantChannel(ctx)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorTenantNotFound, err)
+	}
+	creds, err := h.server.getCloudCredentials(channel, id)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, cloudCredentialsToAPI(creds))
+}
+
+func (h *apiHandlers) DeleteCloudCredentials(ctx echo.Context, id uuid.UUID) error {
+	channel, err := h.server.getTenantChannel(ctx)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorTenantNotFound, err)
+	}
+	creds, err := h.server.getCloudCredentials(channel, id)
+	if err != nil {
+		return err
+	}
+
+	registry, err := h.server.cloudCredentialsRegistry()
+	if err != nil {
+		return err
+	}
+	err = registry.Delete(channel, id)
+	if errors.Is(err, cloudcredentials.ErrNotFound) {
+		return HTTPError(ErrorCloudCredentialsNotFound)
+	}
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorFailedToStoreCloudCredentials, err)
+	}
+
+	return ctx.JSON(http.StatusOK, cloudCredentialsToAPI(creds))
+}
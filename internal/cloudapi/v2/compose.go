@@ -20,6 +20,7 @@ import (
 	"github.com/osbuild/image-builder/pkg/reporegistry"
 	"github.com/osbuild/image-builder/pkg/rhsm/facts"
 	"github.com/osbuild/osbuild-composer/internal/common"
+	"github.com/osbuild/osbuild-composer/internal/lockfile"
 	"github.com/osbuild/osbuild-composer/internal/target"
 )
 
@@ -1234,6 +1235,14 @@ func (request *ComposeRequest) GetImageRequests(distroFactory *distrofactory.Fac
 	}
 	manifestSeed := bigSeed.Int64()
 
+	var lf *lockfile.Lockfile
+	if request.Lockfile != nil {
+		lf, err = lockfileFromAPI(*request.Lockfile)
+		if err != nil {
+			return nil, HTTPErrorWithInternal(ErrorInvalidLockfile, err)
+		}
+	}
+
 	// For backwards compatibility, we support both a single image request
 	// as well as an array of requests in the API. Exactly one must be
 	// specified.
@@ -1339,6 +1348,8 @@ func (request *ComposeRequest) GetImageRequests(distroFactory *distrofactory.Fac
 			targets:      irTargets,
 			blueprint:    bp,
 			manifestSeed: manifestSeed,
+			lockfile:     lf,
+			workerLabels: common.DerefOrDefault(ir.WorkerLabels),
 		})
 	}
 	return irs, nil
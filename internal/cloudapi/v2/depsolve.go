@@ -17,7 +17,7 @@ import (
 	"github.com/osbuild/osbuild-composer/internal/worker"
 )
 
-func (request *DepsolveRequest) Depsolve(df *distrofactory.Factory, rr *reporegistry.RepoRegistry, workers *worker.Server) (rpmmd.PackageList, error) {
+func (request *DepsolveRequest) Depsolve(ctx context.Context, df *distrofactory.Factory, rr *reporegistry.RepoRegistry, workers *worker.Server) (rpmmd.PackageList, error) {
 	// Convert the requested blueprint to a composer blueprint
 	bp, err := ConvertRequestBP(request.Blueprint)
 	if err != nil {
@@ -106,11 +106,12 @@ func (request *DepsolveRequest) Depsolve(df *distrofactory.Factory, rr *reporegi
 		}
 	}
 
-	depsolveJobID, err := workers.EnqueueDepsolve(&worker.DepsolveJob{
+	depsolveJobID, err := workers.EnqueueDepsolve(ctx, &worker.DepsolveJob{
 		PackageSets:      packageSet,
 		ModulePlatformID: distro.ModulePlatformID(),
 		Arch:             distroArch.Name(),
 		Releasever:       distro.Releasever(),
+		Distro:           distro.Name(),
 		SbomType:         sbom.StandardTypeNone,
 	}, "")
 	if err != nil {
@@ -0,0 +1,194 @@
+package v2
+
+// Compose diff handler and helpers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/osbuild/osbuild-composer/internal/common"
+	"github.com/osbuild/osbuild-composer/internal/composediff"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+)
+
+func (h *apiHandlers) GetComposeDiff(ctx echo.Context, jobId uuid.UUID, otherId uuid.UUID) error {
+	// both composes need to belong to the tenant
+	return h.server.EnsureJobChannel(func(ctx echo.Context, jobId uuid.UUID) error {
+		return h.server.EnsureJobChannel(func(ctx echo.Context, otherId uuid.UUID) error {
+			return h.getComposeDiffImpl(ctx, jobId, otherId)
+		})(ctx, otherId)
+	})(ctx, jobId)
+}
+
+func (h *apiHandlers) getComposeDiffImpl(ctx echo.Context, jobId uuid.UUID, otherId uuid.UUID) error {
+	compose, err := h.composeDiffSource(ctx, jobId)
+	if err != nil {
+		return err
+	}
+	other, err := h.composeDiffSource(ctx, otherId)
+	if err != nil {
+		return err
+	}
+
+	diff, err := composediff.Compare(*compose, *other)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorJSONUnMarshallingError, err)
+	}
+
+	resp := &ComposeDiff{
+		Href:           fmt.Sprintf("/api/image-builder-composer/v2/composes/%v/diff/%v", jobId, otherId),
+		Id:             jobId.String(),
+		Kind:           "ComposeDiff",
+		OtherId:        otherId,
+		Packages:       packageDiffToAPI(diff.Packages),
+		Repositories:   RepositoryDiff(diff.Repositories),
+		Customizations: make([]CustomizationChange, len(diff.Customizations)),
+		Pipelines: PipelineDiff{
+			Added:   diff.Pipelines.Added,
+			Removed: diff.Pipelines.Removed,
+			Changed: make([]PipelineChange, len(diff.Pipelines.Changed)),
+		},
+	}
+	for i, c := range diff.Customizations {
+		resp.Customizations[i] = CustomizationChange{Name: c.Name}
+		if c.Old != nil {
+			resp.Customizations[i].Old = common.ToPtr[interface{}](c.Old)
+		}
+		if c.New != nil {
+			resp.Customizations[i].New = common.ToPtr[interface{}](c.New)
+		}
+	}
+	for i, c := range diff.Pipelines.Changed {
+		resp.Pipelines.Changed[i] = PipelineChange{
+			Name:          c.Name,
+			AddedStages:   c.AddedStages,
+			RemovedStages: c.RemovedStages,
+			ChangedStages: c.ChangedStages,
+		}
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+func packageVersionsToAPI(pkgs []composediff.PackageVersion) []PackageVersion {
+	result := make([]PackageVersion, len(pkgs))
+	for i, p := range pkgs {
+		result[i] = PackageVersion{
+			Name:    p.Name,
+			Arch:    p.Arch,
+			Version: p.Version,
+		}
+	}
+	return result
+}
+
+func packageChangesToAPI(changes []composediff.PackageChange) []PackageVersionChange {
+	result := make([]PackageVersionChange, len(changes))
+	for i, c := range changes {
+		result[i] = PackageVersionChange{
+			Name:       c.Name,
+			Arch:       c.Arch,
+			OldVersion: c.OldVersion,
+			NewVersion: c.NewVersion,
+		}
+	}
+	return result
+}
+
+func packageDiffToAPI(diff composediff.PackageDiff) PackageDiff {
+	return PackageDiff{
+		Added:      packageVersionsToAPI(diff.Added),
+		Removed:    packageVersionsToAPI(diff.Removed),
+		Upgraded:   packageChangesToAPI(diff.Upgraded),
+		Downgraded: packageChangesToAPI(diff.Downgraded),
+	}
+}
+
+// isPayloadPipeline returns whether the packages depsolved for the named
+// pipeline are installed into the image
+func isPayloadPipeline(name string, pipelineNames *worker.PipelineNames) bool {
+	if pipelineNames == nil {
+		return name != "build"
+	}
+	return slices.Contains(pipelineNames.Payload, name)
+}
+
+// composeDiffSource collects the content of a successfully finished compose
+// to compare it to another one
+func (h *apiHandlers) composeDiffSource(ctx echo.Context, jobId uuid.UUID) (*composediff.Compose, error) {
+	jobType, err := h.server.workers.JobType(jobId)
+	if err != nil {
+		return nil, HTTPError(ErrorComposeNotFound)
+	}
+
+	// koji composes contain several images
+	if jobType != worker.JobTypeOSBuild {
+		return nil, HTTPError(ErrorInvalidJobType)
+	}
+
+	var osbuildResult worker.OSBuildJobResult
+	osbuildInfo, err := h.server.workers.OSBuildJobInfo(jobId, &osbuildResult)
+	if err != nil {
+		return nil, HTTPErrorWithInternal(ErrorGettingOSBuildJobStatus, err)
+	}
+
+	if osbuildInfo.JobStatus.Finished.IsZero() || !osbuildResult.Success {
+		return nil, HTTPError(ErrorComposeBadState)
+	}
+
+	var compose composediff.Compose
+
+	var buildJob worker.OSBuildJob
+	err = h.server.workers.OSBuildJob(jobId, &buildJob)
+	if err != nil {
+		return nil, HTTPErrorWithInternal(ErrorComposeNotFound, err)
+	}
+	if len(buildJob.Manifest) != 0 {
+		compose.Manifest = buildJob.Manifest
+	} else {
+		_, manifestResult, err := manifestJobResultsFromJobDeps(h.server.workers, osbuildInfo.Deps)
+		if err != nil {
+			return nil, HTTPErrorWithInternal(ErrorComposeNotFound, fmt.Errorf("job %q: %v", jobId, err))
+		}
+		compose.Manifest = manifestResult.Manifest
+	}
+
+	// composes with a static manifest have no depsolve job, their packages
+	// and repositories can't be compared
+	var depsolveResult worker.DepsolveJobResult
+	_, err = h.server.workers.OSBuildJobDepsolveInfo(jobId, &depsolveResult)
+	if err != nil && !errors.Is(err, worker.ErrNoDepsolveJob) {
+		return nil, HTTPErrorWithInternal(ErrorGettingDepsolveJobStatus, err)
+	} else if err == nil {
+		depsolved, err := depsolveResult.ToDepsolvednfResult()
+		if err != nil {
+			return nil, HTTPErrorWithInternal(ErrorJSONUnMarshallingError, err)
+		}
+		for name, result := range depsolved {
+			if !isPayloadPipeline(name, osbuildResult.PipelineNames) {
+				continue
+			}
+			compose.Packages = append(compose.Packages, composediff.PackagesFromRPMMD(result.Transactions.AllPackages())...)
+			compose.Repositories = append(compose.Repositories, result.Repos...)
+		}
+	}
+
+	request, err := readComposeRequest(h.server.workers.ArtifactsDir(), jobId)
+	if err != nil {
+		ctx.Logger().Warnf("Failed to read compose request: %v", err)
+	}
+	if request != nil {
+		bp, err := request.GetBlueprint()
+		if err != nil {
+			return nil, HTTPErrorWithInternal(ErrorFailedToInitializeBlueprint, err)
+		}
+		compose.Customizations = bp.Customizations
+	}
+
+	return &compose, nil
+}
@@ -58,6 +58,17 @@ const (
 	ErrorDistroMissing                ServiceErrorCode = 45
 	ErrorIsoPayloadReferenceForbidden ServiceErrorCode = 46
 	ErrorBootcOnlyImageType           ServiceErrorCode = 47
+	ErrorInvalidLockfile              ServiceErrorCode = 48
+	ErrorLockfileUnavailable          ServiceErrorCode = 49
+	ErrorInvalidSourceBundleName      ServiceErrorCode = 50
+	ErrorInvalidCloudCredentials      ServiceErrorCode = 51
+	ErrorCloudCredentialsNotFound     ServiceErrorCode = 52
+	ErrorCloudCredentialsUnavailable  ServiceErrorCode = 53
+	ErrorCloudCredentialsTypeMismatch ServiceErrorCode = 54
+	ErrorAuditLogUnavailable          ServiceErrorCode = 55
+	ErrorInvalidComposeShare          ServiceErrorCode = 56
+	ErrorComposeShareNotFound         ServiceErrorCode = 57
+	ErrorCloneCloudCredentials        ServiceErrorCode = 58
 
 	// Internal errors, these are bugs
 	ErrorFailedToInitializeBlueprint              ServiceErrorCode = 1000
@@ -86,6 +97,11 @@ const (
 	ErrorDeletingJob                              ServiceErrorCode = 1023
 	ErrorDeletingArtifacts                        ServiceErrorCode = 1024
 	ErrorGettingImageTypes                        ServiceErrorCode = 1025
+	ErrorFailedToCheckRepositories                ServiceErrorCode = 1026
+	ErrorGettingSourceBundleJobStatus             ServiceErrorCode = 1027
+	ErrorFailedToStoreCloudCredentials            ServiceErrorCode = 1028
+	ErrorReadingAuditLog                          ServiceErrorCode = 1029
+	ErrorFailedToStoreComposeShare                ServiceErrorCode = 1030
 
 	// Errors contained within this file
 	ErrorUnspecified          ServiceErrorCode = 10000
@@ -153,6 +169,17 @@ func getServiceErrors() serviceErrors {
 		serviceError{ErrorDistroMissing, http.StatusBadRequest, "Invalid request, distribution is required for this compose request"},
 		serviceError{ErrorIsoPayloadReferenceForbidden, http.StatusBadRequest, "iso_payload_reference must not be set for non-ISO bootc image types"},
 		serviceError{ErrorBootcOnlyImageType, http.StatusBadRequest, "bootable-container-iso image type requires a bootc compose request (use 'bootc' instead of 'distribution')"},
+		serviceError{ErrorInvalidLockfile, http.StatusBadRequest, "Invalid lockfile, it doesn't match the requested image"},
+		serviceError{ErrorLockfileUnavailable, http.StatusBadRequest, "No lockfile can be created for this compose"},
+		serviceError{ErrorInvalidSourceBundleName, http.StatusBadRequest, "Invalid source bundle name, it needs to be a file name"},
+		serviceError{ErrorInvalidCloudCredentials, http.StatusBadRequest, "Invalid cloud credentials"},
+		serviceError{ErrorCloudCredentialsNotFound, http.StatusNotFound, "Cloud credentials with given id not found"},
+		serviceError{ErrorCloudCredentialsUnavailable, http.StatusBadRequest, "Cloud credentials are not enabled on this server"},
+		serviceError{ErrorCloudCredentialsTypeMismatch, http.StatusBadRequest, "Cloud credentials are for a different cloud than the upload target"},
+		serviceError{ErrorAuditLogUnavailable, http.StatusBadRequest, "The audit log is not enabled on this server"},
+		serviceError{ErrorInvalidComposeShare, http.StatusBadRequest, "Invalid share, composes can only be shared with other tenants"},
+		serviceError{ErrorComposeShareNotFound, http.StatusNotFound, "Compose is not shared with the given tenant"},
+		serviceError{ErrorCloneCloudCredentials, http.StatusBadRequest, "Composes uploaded with the cloud credentials of a tenant can only be cloned by that tenant"},
 
 		serviceError{ErrorFailedToInitializeBlueprint, http.StatusInternalServerError, "Failed to initialize blueprint"},
 		serviceError{ErrorFailedToGenerateManifestSeed, http.StatusInternalServerError, "Failed to generate manifest seed"},
@@ -179,6 +206,11 @@ func getServiceErrors() serviceErrors {
 		serviceError{ErrorDeletingJob, http.StatusBadRequest, "Unable to delete job"},
 		serviceError{ErrorDeletingArtifacts, http.StatusInternalServerError, "Unable to delete job artifacts"},
 		serviceError{ErrorGettingImageTypes, http.StatusInternalServerError, "Unable to get list of image types"},
+		serviceError{ErrorFailedToCheckRepositories, http.StatusInternalServerError, "Failed to check repositories"},
+		serviceError{ErrorGettingSourceBundleJobStatus, http.StatusInternalServerError, "Unable to get source bundle job status"},
+		serviceError{ErrorFailedToStoreCloudCredentials, http.StatusInternalServerError, "Unable to store cloud credentials"},
+		serviceError{ErrorReadingAuditLog, http.StatusInternalServerError, "Unable to read the audit log"},
+		serviceError{ErrorFailedToStoreComposeShare, http.StatusInternalServerError, "Unable to store the shares of the compose"},
 
 		serviceError{ErrorUnspecified, http.StatusInternalServerError, "Unspecified internal error "},
 		serviceError{ErrorNotHTTPError, http.StatusInternalServerError, "Error is not an instance of HTTPError"},
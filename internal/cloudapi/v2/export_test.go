@@ -44,10 +44,10 @@ func NewManifestJobDependencies(
 	bootcInfoResolveJobID, bootcPreManifestJobID uuid.UUID,
 ) ManifestJobDependencies {
 	return manifestJobDependencies{
-		depsolveJobID:         depsolveJobID,
-		containerResolveJobID: containerResolveJobID,
-		ostreeResolveJobID:    ostreeResolveJobID,
-		bootcInfoResolveJobID: bootcInfoResolveJobID,
-		bootcPreManifestJobID: bootcPreManifestJobID,
+		DepsolveJobID:         depsolveJobID,
+		ContainerResolveJobID: containerResolveJobID,
+		OSTreeResolveJobID:    ostreeResolveJobID,
+		BootcInfoResolveJobID: bootcInfoResolveJobID,
+		BootcPreManifestJobID: bootcPreManifestJobID,
 	}
 }
@@ -17,6 +17,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/osbuild/blueprint/pkg/blueprint"
 	"github.com/osbuild/image-builder/pkg/disk"
@@ -26,9 +27,12 @@ import (
 	"github.com/osbuild/image-builder/pkg/osbuild"
 	"github.com/osbuild/image-builder/pkg/rpmmd"
 	"github.com/osbuild/image-builder/pkg/sbom"
+	"github.com/osbuild/osbuild-composer/internal/audit"
 	"github.com/osbuild/osbuild-composer/internal/common"
 	"github.com/osbuild/osbuild-composer/internal/jsondb"
+	"github.com/osbuild/osbuild-composer/internal/lockfile"
 	"github.com/osbuild/osbuild-composer/internal/target"
+	"github.com/osbuild/osbuild-composer/internal/tracing"
 	"github.com/osbuild/osbuild-composer/internal/worker"
 	"github.com/osbuild/osbuild-composer/internal/worker/clienterrors"
 )
@@ -121,6 +125,8 @@ type imageRequest struct {
 	targets      []*target.Target
 	blueprint    blueprint.Blueprint
 	manifestSeed int64
+	lockfile     *lockfile.Lockfile
+	workerLabels []string
 }
 
 func (h *apiHandlers) PostCompose(ctx echo.Context) error {
@@ -152,6 +158,21 @@ func (h *apiHandlers) PostCompose(ctx echo.Context) error {
 		if err != nil {
 			return err
 		}
+		for _, ir := range irs {
+			err = h.server.checkCloudCredentials(channel, ir.targets)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if request.Lockfile != nil {
+		if request.Koji != nil || request.Bootc != nil {
+			return HTTPErrorWithInternal(ErrorInvalidLockfile, fmt.Errorf("lockfiles are not supported for koji and bootc composes"))
+		}
+		if h.server.config.ImageBuilderManifestGeneration {
+			return HTTPErrorWithInternal(ErrorInvalidLockfile, fmt.Errorf("lockfiles are not supported when manifests are generated by image-builder"))
+		}
 	}
 
 	var id uuid.UUID
@@ -159,28 +180,33 @@ func (h *apiHandlers) PostCompose(ctx echo.Context) error {
 		if request.Koji.TaskId < 0 {
 			return fmt.Errorf("invalid Koji task ID: %d", request.Koji.TaskId)
 		}
-		id, err = h.server.enqueueKojiCompose(uint64(request.Koji.TaskId), request.Koji.Server, request.Koji.Name, request.Koji.Version, request.Koji.Release, irs, channel) // nolint: gosec
+		id, err = h.server.enqueueKojiCompose(ctx.Request().Context(), uint64(request.Koji.TaskId), request.Koji.Server, request.Koji.Name, request.Koji.Version, request.Koji.Release, irs, channel) // nolint: gosec
 		if err != nil {
 			return err
 		}
 	} else if h.server.config.ImageBuilderManifestGeneration {
-		id, err = h.server.enqueueComposeIBCLI(irs, channel)
+		id, err = h.server.enqueueComposeIBCLI(ctx.Request().Context(), irs, channel)
 		if err != nil {
 			return err
 		}
 	} else if request.Bootc != nil {
-		id, err = h.server.enqueueBootcCompose(request, channel)
+		id, err = h.server.enqueueBootcCompose(ctx.Request().Context(), request, channel)
 		if err != nil {
 			return err
 		}
 	} else {
-		id, err = h.server.enqueueCompose(irs, channel)
+		id, err = h.server.enqueueCompose(ctx.Request().Context(), irs, channel)
 		if err != nil {
 			return err
 		}
 	}
 
 	ctx.Logger().Infof("Job ID %s enqueued for operationID %s", id, ctx.Get(common.OperationIDKey))
+	audit.Annotate(ctx.Request().Context(), "compose_id", id.String())
+	tracing.SetAttributes(ctx.Request().Context(), attribute.String("composer.compose.id", id.String()))
+	if request.Distribution != nil {
+		audit.Annotate(ctx.Request().Context(), "distribution", *request.Distribution)
+	}
 
 	// Save the request in the artifacts directory, log errors but continue
 	if err := saveComposeRequest(h.server.workers.ArtifactsDir(), id, request); err != nil {
@@ -395,7 +421,7 @@ func (h *apiHandlers) deleteComposeImpl(ctx echo.Context, jobId uuid.UUID) error
 }
 
 func (h *apiHandlers) GetComposeStatus(ctx echo.Context, jobId uuid.UUID) error {
-	return h.server.EnsureJobChannel(h.getComposeStatusImpl)(ctx, jobId)
+	return h.server.EnsureJobChannelOrShare(Read, h.getComposeStatusImpl)(ctx, jobId)
 }
 
 func (h *apiHandlers) getComposeStatusImpl(ctx echo.Context, jobId uuid.UUID) error {
@@ -676,7 +702,7 @@ func composeStatusFromKojiJobStatus(js *worker.JobStatus, initResult *worker.Koj
 
 // ComposeMetadata handles a /composes/{id}/metadata GET request
 func (h *apiHandlers) GetComposeMetadata(ctx echo.Context, jobId uuid.UUID) error {
-	return h.server.EnsureJobChannel(h.getComposeMetadataImpl)(ctx, jobId)
+	return h.server.EnsureJobChannelOrShare(Read, h.getComposeMetadataImpl)(ctx, jobId)
 }
 
 func (h *apiHandlers) getComposeMetadataImpl(ctx echo.Context, jobId uuid.UUID) error {
@@ -1081,7 +1107,7 @@ func sbomsFromOSBuildJob(w *worker.Server, osbuildJobUUID uuid.UUID) ([]ImageSBO
 
 // GetComposeSBOMs returns the SBOM documents for a given Compose (multiple SBOMs for each image).
 func (h *apiHandlers) GetComposeSBOMs(ctx echo.Context, jobId uuid.UUID) error {
-	return h.server.EnsureJobChannel(h.getComposeSBOMsImpl)(ctx, jobId)
+	return h.server.EnsureJobChannelOrShare(Read, h.getComposeSBOMsImpl)(ctx, jobId)
 }
 
 func (h *apiHandlers) getComposeSBOMsImpl(ctx echo.Context, jobId uuid.UUID) error {
@@ -1148,6 +1174,46 @@ func (h *apiHandlers) getComposeSBOMsImpl(ctx echo.Context, jobId uuid.UUID) err
 	return ctx.JSON(http.StatusOK, resp)
 }
 
+func (h *apiHandlers) GetComposeLockfile(ctx echo.Context, jobId uuid.UUID) error {
+	return h.server.EnsureJobChannel(h.getComposeLockfileImpl)(ctx, jobId)
+}
+
+func (h *apiHandlers) getComposeLockfileImpl(ctx echo.Context, jobId uuid.UUID) error {
+	jobType, err := h.server.workers.JobType(jobId)
+	if err != nil {
+		return HTTPError(ErrorComposeNotFound)
+	}
+
+	// koji composes contain several images, which can't share a lockfile
+	if jobType != worker.JobTypeOSBuild {
+		return HTTPError(ErrorInvalidJobType)
+	}
+
+	var osbuildResult worker.OSBuildJobResult
+	osbuildInfo, err := h.server.workers.OSBuildJobInfo(jobId, &osbuildResult)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorGettingOSBuildJobStatus, err)
+	}
+
+	if osbuildInfo.JobStatus.Finished.IsZero() || !osbuildResult.Success {
+		return HTTPError(ErrorComposeBadState)
+	}
+
+	lf, err := lockfileFromOSBuildJob(h.server.workers, jobId)
+	if err != nil {
+		return err
+	}
+
+	resp := &ComposeLockfile{
+		Href:     fmt.Sprintf("/api/image-builder-composer/v2/composes/%v/lockfile", jobId),
+		Id:       jobId.String(),
+		Kind:     "ComposeLockfile",
+		Lockfile: lockfileToAPI(lf),
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
 // Converts repositories in the request to the internal rpmmd.RepoConfig representation
 func convertRepos(irRepos, payloadRepositories []Repository, payloadPackageSets []string) ([]rpmmd.RepoConfig, error) {
 	repos := make([]rpmmd.RepoConfig, 0, len(irRepos)+len(payloadRepositories))
@@ -1226,7 +1292,7 @@ func genRepoConfig(repo Repository) (*rpmmd.RepoConfig, error) {
 }
 
 func (h *apiHandlers) PostCloneCompose(ctx echo.Context, jobId uuid.UUID) error {
-	return h.server.EnsureJobChannel(h.postCloneComposeImpl)(ctx, jobId)
+	return h.server.EnsureJobChannelOrShare(Clone, h.postCloneComposeImpl)(ctx, jobId)
 }
 
 func (h *apiHandlers) postCloneComposeImpl(ctx echo.Context, jobId uuid.UUID) error {
@@ -1291,6 +1357,22 @@ func (h *apiHandlers) postCloneComposeImpl(ctx echo.Context, jobId uuid.UUID) er
 			return err
 		}
 
+		awsT, ok := (osbuildJob.Targets[0].Options).(*target.AWSTargetOptions)
+		if !ok {
+			return HTTPError(ErrorUnknownUploadTarget)
+		}
+		// the copy and share jobs need the tenant's credentials to see
+		// the image, they are only revealed to jobs of the tenant
+		if awsT.CloudCredentials != nil {
+			composeChannel, err := h.server.workers.JobChannel(jobId)
+			if err != nil {
+				return HTTPErrorWithInternal(ErrorComposeNotFound, err)
+			}
+			if composeChannel != channel {
+				return HTTPError(ErrorCloneCloudCredentials)
+			}
+		}
+
 		shareAmi := options.Ami
 		shareRegion := img.Region
 		if img.Region != options.Region {
@@ -1322,12 +1404,13 @@ func (h *apiHandlers) postCloneComposeImpl(ctx echo.Context, jobId uuid.UUID) er
 
 			if !foundDep {
 				copyJob := &worker.AWSEC2CopyJob{
-					Ami:          options.Ami,
-					SourceRegion: options.Region,
-					TargetRegion: img.Region,
-					TargetName:   fmt.Sprintf("composer-api-%s", uuid.New().String()),
+					Ami:              options.Ami,
+					SourceRegion:     options.Region,
+					TargetRegion:     img.Region,
+					TargetName:       fmt.Sprintf("composer-api-%s", uuid.New().String()),
+					CloudCredentials: awsT.CloudCredentials,
 				}
-				finalJob, err = h.server.workers.EnqueueAWSEC2CopyJob(copyJob, finalJob, channel)
+				finalJob, err = h.server.workers.EnqueueAWSEC2CopyJob(ctx.Request().Context(), copyJob, finalJob, channel)
 				if err != nil {
 					return HTTPErrorWithInternal(ErrorEnqueueingJob, err)
 				}
@@ -1335,10 +1418,6 @@ func (h *apiHandlers) postCloneComposeImpl(ctx echo.Context, jobId uuid.UUID) er
 		}
 
 		var shares []string
-		awsT, ok := (osbuildJob.Targets[0].Options).(*target.AWSTargetOptions)
-		if !ok {
-			return HTTPError(ErrorUnknownUploadTarget)
-		}
 		if len(awsT.ShareWithAccounts) > 0 {
 			shares = append(shares, awsT.ShareWithAccounts...)
 		}
@@ -1350,8 +1429,9 @@ func (h *apiHandlers) postCloneComposeImpl(ctx echo.Context, jobId uuid.UUID) er
 				Ami:               shareAmi,
 				Region:            shareRegion,
 				ShareWithAccounts: shares,
+				CloudCredentials:  awsT.CloudCredentials,
 			}
-			finalJob, err = h.server.workers.EnqueueAWSEC2ShareJob(shareJob, finalJob, channel)
+			finalJob, err = h.server.workers.EnqueueAWSEC2ShareJob(ctx.Request().Context(), shareJob, finalJob, channel)
 			if err != nil {
 				return HTTPErrorWithInternal(ErrorEnqueueingJob, err)
 			}
@@ -1360,6 +1440,7 @@ func (h *apiHandlers) postCloneComposeImpl(ctx echo.Context, jobId uuid.UUID) er
 		return HTTPError(ErrorUnsupportedImage)
 	}
 
+	audit.Annotate(ctx.Request().Context(), "clone_id", finalJob.String())
 	return ctx.JSON(http.StatusCreated, CloneComposeResponse{
 		Href: fmt.Sprintf("/api/image-builder-composer/v2/composes/%v/clone", jobId),
 		Id:   finalJob,
@@ -1454,7 +1535,7 @@ func (h *apiHandlers) PostDepsolveBlueprint(ctx echo.Context) error {
 
 	// Depsolve the requested blueprint
 	// Any errors returned are suitable as a response
-	deps, err := request.Depsolve(h.server.distros, h.server.repos, h.server.workers)
+	deps, err := request.Depsolve(ctx.Request().Context(), h.server.distros, h.server.repos, h.server.workers)
 	if err != nil {
 		return err
 	}
@@ -1541,7 +1622,7 @@ func (h *apiHandlers) PostSearchPackages(ctx echo.Context) error {
 
 	// Search for the listed packages
 	// Any errors returned are suitable as a response
-	packages, err := request.Search(h.server.distros, h.server.repos, h.server.workers)
+	packages, err := request.Search(ctx.Request().Context(), h.server.distros, h.server.repos, h.server.workers)
 	if err != nil {
 		return err
 	}
@@ -1552,6 +1633,27 @@ func (h *apiHandlers) PostSearchPackages(ctx echo.Context) error {
 		})
 }
 
+// PostRepositoriesCheck fetches the metadata of the listed repositories
+// and reports their health.
+func (h *apiHandlers) PostRepositoriesCheck(ctx echo.Context) error {
+	var request RepositoryCheckRequest
+	err := ctx.Bind(&request)
+	if err != nil {
+		return err
+	}
+
+	// Any errors returned are suitable as a response
+	results, err := request.Check(ctx.Request().Context(), h.server.workers)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK,
+		RepositoryCheckResponse{
+			Results: repositoryCheckResultsToHealth(results),
+		})
+}
+
 // GetDistributionList returns the list of all supported distribution repositories
 // It is arranged by distro name -> architecture -> image type
 func (h *apiHandlers) GetDistributionList(ctx echo.Context) error {
@@ -1807,7 +1909,7 @@ func partitionTableToDisk(partitionTable *disk.PartitionTable) (*Disk, error) {
 
 // GetComposeDownload downloads a compose artifact
 func (h *apiHandlers) GetComposeDownload(ctx echo.Context, jobId uuid.UUID) error {
-	return h.server.EnsureJobChannel(h.getComposeDownloadImpl)(ctx, jobId)
+	return h.server.EnsureJobChannelOrShare(Read, h.getComposeDownloadImpl)(ctx, jobId)
 }
 
 func (h *apiHandlers) getComposeDownloadImpl(ctx echo.Context, jobId uuid.UUID) error {
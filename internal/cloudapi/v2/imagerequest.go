@@ -47,6 +47,7 @@ func newAWSTarget(options UploadOptions, imageType distro.ImageType) (*target.Ta
 		Key:               key,
 		ShareWithAccounts: awsUploadOptions.ShareWithAccounts,
 		BootMode:          amiBootMode,
+		CloudCredentials:  awsUploadOptions.CredentialsId,
 	})
 	if awsUploadOptions.SnapshotName != nil {
 		t.ImageName = *awsUploadOptions.SnapshotName
@@ -74,9 +75,10 @@ func newAWSS3Target(options UploadOptions, imageType distro.ImageType) (*target.
 
 	key := fmt.Sprintf("composer-api-%s", uuid.New().String())
 	t := target.NewAWSS3Target(&target.AWSS3TargetOptions{
-		Region: awsS3UploadOptions.Region,
-		Key:    key,
-		Public: public,
+		Region:           awsS3UploadOptions.Region,
+		Key:              key,
+		Public:           public,
+		CloudCredentials: awsS3UploadOptions.CredentialsId,
 	})
 	t.ImageName = key
 	return t, nil
@@ -137,6 +139,7 @@ func newGCPTarget(options UploadOptions, imageType distro.ImageType) (*target.Ta
 		Object:            fmt.Sprintf("%s.tar.gz", imageName),
 		ShareWithAccounts: share,
 		GuestOsFeatures:   gcp.GuestOsFeaturesByDistro(osName), // not exposed in cloudapi
+		CloudCredentials:  gcpUploadOptions.CredentialsId,
 	})
 	// Import will fail if an image with this name already exists
 	if gcpUploadOptions.ImageName != nil {
@@ -174,6 +177,7 @@ func newAzureTarget(options UploadOptions, imageType distro.ImageType) (*target.
 		SubscriptionID:   azureUploadOptions.SubscriptionId,
 		ResourceGroup:    azureUploadOptions.ResourceGroup,
 		HyperVGeneration: hypvgen,
+		CloudCredentials: azureUploadOptions.CredentialsId,
 	})
 
 	if azureUploadOptions.ImageName != nil {
@@ -0,0 +1,111 @@
+package v2
+
+// Lockfile conversions between the API and the internal lockfile package
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/osbuild/osbuild-composer/internal/common"
+	"github.com/osbuild/osbuild-composer/internal/lockfile"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+)
+
+func lockfileFromAPI(lf Lockfile) (*lockfile.Lockfile, error) {
+	result := &lockfile.Lockfile{
+		Version:   lf.Version,
+		Pipelines: make(map[string][][]lockfile.Package, len(lf.Pipelines)),
+	}
+
+	for name, transactions := range lf.Pipelines {
+		pipeline := make([][]lockfile.Package, len(transactions))
+		for i, transaction := range transactions {
+			pipeline[i] = make([]lockfile.Package, len(transaction))
+			for j, pkg := range transaction {
+				if pkg.Epoch < 0 {
+					return nil, fmt.Errorf("package %q in pipeline %q has a negative epoch", pkg.Name, name)
+				}
+				p := lockfile.Package{
+					Name:     pkg.Name,
+					Epoch:    uint(pkg.Epoch),
+					Version:  pkg.Version,
+					Release:  pkg.Release,
+					Arch:     pkg.Arch,
+					Checksum: pkg.Checksum,
+				}
+				if pkg.RemoteLocations != nil {
+					p.RemoteLocations = *pkg.RemoteLocations
+				}
+				if pkg.RepoUrl != nil {
+					p.RepoURL = *pkg.RepoUrl
+				}
+				pipeline[i][j] = p
+			}
+		}
+		result.Pipelines[name] = pipeline
+	}
+
+	if err := result.Validate(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func lockfileToAPI(lf *lockfile.Lockfile) Lockfile {
+	result := Lockfile{
+		Version:   lf.Version,
+		Pipelines: make(map[string][][]LockfilePackage, len(lf.Pipelines)),
+	}
+
+	for name, transactions := range lf.Pipelines {
+		pipeline := make([][]LockfilePackage, len(transactions))
+		for i, transaction := range transactions {
+			pipeline[i] = make([]LockfilePackage, len(transaction))
+			for j, pkg := range transaction {
+				p := LockfilePackage{
+					Name:     pkg.Name,
+					Epoch:    int(pkg.Epoch), // nolint: gosec
+					Version:  pkg.Version,
+					Release:  pkg.Release,
+					Arch:     pkg.Arch,
+					Checksum: pkg.Checksum,
+				}
+				if len(pkg.RemoteLocations) > 0 {
+					p.RemoteLocations = common.ToPtr(pkg.RemoteLocations)
+				}
+				if pkg.RepoURL != "" {
+					p.RepoUrl = common.ToPtr(pkg.RepoURL)
+				}
+				pipeline[i][j] = p
+			}
+		}
+		result.Pipelines[name] = pipeline
+	}
+
+	return result
+}
+
+// lockfileFromOSBuildJob creates a lockfile from the depsolve job the manifest
+// of an osbuild job was serialized from
+func lockfileFromOSBuildJob(w *worker.Server, osbuildJobUUID uuid.UUID) (*lockfile.Lockfile, error) {
+	var result worker.DepsolveJobResult
+	info, err := w.OSBuildJobDepsolveInfo(osbuildJobUUID, &result)
+	if errors.Is(err, worker.ErrNoDepsolveJob) {
+		return nil, HTTPErrorWithInternal(ErrorLockfileUnavailable, err)
+	} else if err != nil {
+		return nil, HTTPErrorWithInternal(ErrorComposeNotFound, err)
+	}
+	if result.JobError != nil || info.JobStatus.Finished.IsZero() {
+		return nil, HTTPErrorWithInternal(ErrorLockfileUnavailable,
+			fmt.Errorf("depsolve job of OSBuild job %q didn't finish successfully", osbuildJobUUID))
+	}
+
+	depsolved, err := result.ToDepsolvednfResult()
+	if err != nil {
+		return nil, HTTPErrorWithInternal(ErrorJSONUnMarshallingError, err)
+	}
+
+	return lockfile.New(depsolved), nil
+}
@@ -14,14 +14,14 @@ const TenantCtxKey string = "tenant"
 
 // getTenantChannel returns the tenant channel for the provided request context
 func (s *Server) getTenantChannel(ctx echo.Context) (string, error) {
-	if s.config.JWTEnabled {
+	if s.config.JWTEnabled || len(s.config.APIKeys) > 0 {
 		tenant, ok := ctx.Get(auth.TenantCtxKey).(string)
 		if !ok {
 			return "", HTTPError(ErrorTenantNotInContext)
 		}
 		return tenant, nil
 	}
-	// channel is empty if JWT is not enabled
+	// channel is empty if neither JWT nor API keys are enabled
 	return "", nil
 }
 
@@ -29,6 +29,17 @@ type ComposeHandlerFunc func(ctx echo.Context, jobId uuid.UUID) error
 
 // Ensures that the job's channel matches the JWT cannel set in the echo.Context
 func (s *Server) EnsureJobChannel(next ComposeHandlerFunc) ComposeHandlerFunc {
+	return s.ensureJobAccess(nil, next)
+}
+
+// Ensures that the job's channel matches the JWT channel set in the
+// echo.Context, or that the job is shared with that channel with a grant
+// which includes the permission
+func (s *Server) EnsureJobChannelOrShare(permission ComposeSharePermission, next ComposeHandlerFunc) ComposeHandlerFunc {
+	return s.ensureJobAccess(&permission, next)
+}
+
+func (s *Server) ensureJobAccess(permission *ComposeSharePermission, next ComposeHandlerFunc) ComposeHandlerFunc {
 	return func(c echo.Context, jobId uuid.UUID) error {
 		ctxChannel, err := s.getTenantChannel(c)
 		if err != nil {
@@ -41,11 +52,23 @@ func (s *Server) EnsureJobChannel(next ComposeHandlerFunc) ComposeHandlerFunc {
 			return HTTPErrorWithInternal(ErrorComposeNotFound, err)
 		}
 
-		if jobChannel != ctxChannel {
+		if jobChannel == ctxChannel {
+			return next(c, jobId)
+		}
+		if permission == nil {
 			return HTTPError(ErrorComposeNotFound)
 		}
 
-		return next(c, jobId)
+		shares, err := s.workers.JobShares(jobId)
+		if err != nil {
+			return HTTPErrorWithInternal(ErrorComposeNotFound, err)
+		}
+		for _, share := range shares {
+			if share.Channel == ctxChannel && ComposeSharePermission(share.Permission).includes(*permission) {
+				return next(c, jobId)
+			}
+		}
+		return HTTPError(ErrorComposeNotFound)
 	}
 }
 
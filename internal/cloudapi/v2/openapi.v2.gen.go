@@ -13,6 +13,7 @@ import (
 	"net/url"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/labstack/echo/v4"
@@ -78,6 +79,45 @@ func (e BtrfsVolumeType) Valid() bool {
 	}
 }
 
+// Defines values for CloudCredentialsType.
+const (
+	CloudCredentialsTypeAws   CloudCredentialsType = "aws"
+	CloudCredentialsTypeAzure CloudCredentialsType = "azure"
+	CloudCredentialsTypeGcp   CloudCredentialsType = "gcp"
+)
+
+// Valid indicates whether the value is a known member of the CloudCredentialsType enum.
+func (e CloudCredentialsType) Valid() bool {
+	switch e {
+	case CloudCredentialsTypeAws:
+		return true
+	case CloudCredentialsTypeAzure:
+		return true
+	case CloudCredentialsTypeGcp:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defines values for ComposeSharePermission.
+const (
+	Clone ComposeSharePermission = "clone"
+	Read  ComposeSharePermission = "read"
+)
+
+// Valid indicates whether the value is a known member of the ComposeSharePermission enum.
+func (e ComposeSharePermission) Valid() bool {
+	switch e {
+	case Clone:
+		return true
+	case Read:
+		return true
+	default:
+		return false
+	}
+}
+
 // Defines values for ComposeStatusValue.
 const (
 	ComposeStatusValueFailure ComposeStatusValue = "failure"
@@ -492,6 +532,19 @@ func (e VolumeGroupType) Valid() bool {
 	}
 }
 
+// AWSCloudCredentials defines model for AWSCloudCredentials.
+type AWSCloudCredentials struct {
+	// Bucket Bucket the role can write to, the image is uploaded there before it is imported
+	Bucket string `json:"bucket"`
+
+	// ExternalId External ID required by the trust policy of the role
+	ExternalId *string `json:"external_id,omitempty"`
+
+	// RoleArn The IAM role the service assumes to upload and register the image.
+	// Its trust policy needs to allow the service to assume it.
+	RoleArn string `json:"role_arn"`
+}
+
 // AWSEC2CloneCompose defines model for AWSEC2CloneCompose.
 type AWSEC2CloneCompose struct {
 	Region            string    `json:"region"`
@@ -500,9 +553,12 @@ type AWSEC2CloneCompose struct {
 
 // AWSEC2UploadOptions defines model for AWSEC2UploadOptions.
 type AWSEC2UploadOptions struct {
-	Region            string   `json:"region"`
-	ShareWithAccounts []string `json:"share_with_accounts"`
-	SnapshotName      *string  `json:"snapshot_name,omitempty"`
+	// CredentialsId ID of registered cloud credentials of the tenant to upload the
+	// image with, instead of the credentials of the service.
+	CredentialsId     *openapi_types.UUID `json:"credentials_id,omitempty"`
+	Region            string              `json:"region"`
+	ShareWithAccounts []string            `json:"share_with_accounts"`
+	SnapshotName      *string             `json:"snapshot_name,omitempty"`
 }
 
 // AWSEC2UploadStatus defines model for AWSEC2UploadStatus.
@@ -513,6 +569,10 @@ type AWSEC2UploadStatus struct {
 
 // AWSS3UploadOptions defines model for AWSS3UploadOptions.
 type AWSS3UploadOptions struct {
+	// CredentialsId ID of registered cloud credentials of the tenant to upload the
+	// image with, instead of the credentials of the service.
+	CredentialsId *openapi_types.UUID `json:"credentials_id,omitempty"`
+
 	// Public If set to false (the default value), a long, obfuscated URL
 	// is returned. Its expiration might be sooner than for other upload
 	// targets.
@@ -537,8 +597,57 @@ type ArchitectureInfo struct {
 	Name string `json:"name"`
 }
 
+// AuditLog defines model for AuditLog.
+type AuditLog struct {
+	Items []AuditRecord `json:"items"`
+	Kind  string        `json:"kind"`
+	Page  int           `json:"page"`
+	Size  int           `json:"size"`
+	Total int           `json:"total"`
+}
+
+// AuditRecord defines model for AuditRecord.
+type AuditRecord struct {
+	Action string `json:"action"`
+
+	// Api The API which was called, cloudapi, weldr or worker
+	Api string `json:"api"`
+
+	// Details Summary of the request, e.g. the ID of the created compose
+	Details *map[string]string `json:"details,omitempty"`
+	Method  string             `json:"method"`
+
+	// OperationId Identifies the request in the logs of the service
+	OperationId *string `json:"operation_id,omitempty"`
+
+	// Outcome Whether the call succeeded, success or failure
+	Outcome string `json:"outcome"`
+	Path    string `json:"path"`
+
+	// Principal The caller, e.g. the subject of its token
+	Principal string `json:"principal"`
+
+	// Status HTTP status of the response
+	Status int       `json:"status"`
+	Time   time.Time `json:"time"`
+}
+
+// AzureCloudCredentials defines model for AzureCloudCredentials.
+type AzureCloudCredentials struct {
+	// ClientId Application ID of the service principal
+	ClientId string `json:"client_id"`
+
+	// ClientSecret Secret of the service principal. It's required to register the
+	// credentials and never returned.
+	ClientSecret *string `json:"client_secret,omitempty"`
+}
+
 // AzureUploadOptions defines model for AzureUploadOptions.
 type AzureUploadOptions struct {
+	// CredentialsId ID of registered cloud credentials of the tenant to upload the
+	// image with, instead of the credentials of the service.
+	CredentialsId *openapi_types.UUID `json:"credentials_id,omitempty"`
+
 	// HyperVGeneration Choose the VM Image HyperV generation, different features on Azure are available
 	// depending on the HyperV generation.
 	HyperVGeneration *AzureUploadOptionsHyperVGeneration `json:"hyper_v_generation,omitempty"`
@@ -858,9 +967,57 @@ type CloneStatus_Options struct {
 	union json.RawMessage
 }
 
+// CloudCredentials defines model for CloudCredentials.
+type CloudCredentials struct {
+	Aws       *AWSCloudCredentials   `json:"aws,omitempty"`
+	Azure     *AzureCloudCredentials `json:"azure,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+	Gcp       *GCPCloudCredentials   `json:"gcp,omitempty"`
+	Href      string                 `json:"href"`
+	Id        string                 `json:"id"`
+	Kind      string                 `json:"kind"`
+	Name      string                 `json:"name"`
+	Type      CloudCredentialsType   `json:"type"`
+}
+
+// CloudCredentialsType defines model for CloudCredentials.Type.
+type CloudCredentialsType string
+
+// CloudCredentialsList defines model for CloudCredentialsList.
+type CloudCredentialsList struct {
+	Items []CloudCredentials `json:"items"`
+	Kind  string             `json:"kind"`
+	Page  int                `json:"page"`
+	Size  int                `json:"size"`
+	Total int                `json:"total"`
+}
+
+// CloudCredentialsRequest Exactly one of aws, gcp and azure needs to be set.
+type CloudCredentialsRequest struct {
+	Aws   *AWSCloudCredentials   `json:"aws,omitempty"`
+	Azure *AzureCloudCredentials `json:"azure,omitempty"`
+	Gcp   *GCPCloudCredentials   `json:"gcp,omitempty"`
+	Name  string                 `json:"name"`
+}
+
 // ComposeDeleteStatus defines model for ComposeDeleteStatus.
 type ComposeDeleteStatus = ObjectReference
 
+// ComposeDiff defines model for ComposeDiff.
+type ComposeDiff struct {
+	// Customizations The blueprint customizations which differ
+	Customizations []CustomizationChange `json:"customizations"`
+	Href           string                `json:"href"`
+	Id             string                `json:"id"`
+	Kind           string                `json:"kind"`
+
+	// OtherId ID of the compose compared to
+	OtherId      openapi_types.UUID `json:"other_id"`
+	Packages     PackageDiff        `json:"packages"`
+	Pipelines    PipelineDiff       `json:"pipelines"`
+	Repositories RepositoryDiff     `json:"repositories"`
+}
+
 // ComposeId defines model for ComposeId.
 type ComposeId struct {
 	Href string             `json:"href"`
@@ -877,6 +1034,14 @@ type ComposeList struct {
 	Total int             `json:"total"`
 }
 
+// ComposeLockfile defines model for ComposeLockfile.
+type ComposeLockfile struct {
+	Href     string   `json:"href"`
+	Id       string   `json:"id"`
+	Kind     string   `json:"kind"`
+	Lockfile Lockfile `json:"lockfile"`
+}
+
 // ComposeLogs defines model for ComposeLogs.
 type ComposeLogs struct {
 	Href        string        `json:"href"`
@@ -921,6 +1086,7 @@ type ComposeRequest struct {
 	ImageRequest   *ImageRequest       `json:"image_request,omitempty"`
 	ImageRequests  *[]ImageRequest     `json:"image_requests,omitempty"`
 	Koji           *Koji               `json:"koji,omitempty"`
+	Lockfile       *Lockfile           `json:"lockfile,omitempty"`
 }
 
 // ComposeSBOMs defines model for ComposeSBOMs.
@@ -933,6 +1099,42 @@ type ComposeSBOMs struct {
 	Kind  string        `json:"kind"`
 }
 
+// ComposeShare defines model for ComposeShare.
+type ComposeShare struct {
+	Channel   string    `json:"channel"`
+	CreatedAt time.Time `json:"created_at"`
+	Href      string    `json:"href"`
+	Id        string    `json:"id"`
+	Kind      string    `json:"kind"`
+
+	// Permission read allows getting the status, metadata and SBOMs of the compose
+	// and downloading its artifact, clone additionally allows cloning it.
+	Permission ComposeSharePermission `json:"permission"`
+}
+
+// ComposeShareList defines model for ComposeShareList.
+type ComposeShareList struct {
+	Items []ComposeShare `json:"items"`
+	Kind  string         `json:"kind"`
+	Page  int            `json:"page"`
+	Size  int            `json:"size"`
+	Total int            `json:"total"`
+}
+
+// ComposeSharePermission read allows getting the status, metadata and SBOMs of the compose
+// and downloading its artifact, clone additionally allows cloning it.
+type ComposeSharePermission string
+
+// ComposeShareRequest defines model for ComposeShareRequest.
+type ComposeShareRequest struct {
+	// Channel Tenant to share the compose with
+	Channel string `json:"channel"`
+
+	// Permission read allows getting the status, metadata and SBOMs of the compose
+	// and downloading its artifact, clone additionally allows cloning it.
+	Permission ComposeSharePermission `json:"permission"`
+}
+
 // ComposeStatus defines model for ComposeStatus.
 type ComposeStatus struct {
 	Href          string             `json:"href"`
@@ -1001,6 +1203,17 @@ type CustomRepository struct {
 	SslVerify      *bool     `json:"ssl_verify,omitempty"`
 }
 
+// CustomizationChange defines model for CustomizationChange.
+type CustomizationChange struct {
+	Name string `json:"name"`
+
+	// New The value of the customization, unset if it isn't set in the other compose
+	New *interface{} `json:"new,omitempty"`
+
+	// Old The value of the customization, unset if it isn't set in the compose
+	Old *interface{} `json:"old,omitempty"`
+}
+
 // Customizations defines model for Customizations.
 type Customizations struct {
 	Cacerts    *CACertsCustomization `json:"cacerts,omitempty"`
@@ -1326,11 +1539,29 @@ type FirewallZones struct {
 	Sources *[]string `json:"sources,omitempty"`
 }
 
+// GCPCloudCredentials defines model for GCPCloudCredentials.
+type GCPCloudCredentials struct {
+	// Bucket Bucket the service account can write to. The bucket of the upload
+	// options takes precedence.
+	Bucket *string `json:"bucket,omitempty"`
+
+	// ProjectId Project the image is imported to
+	ProjectId string `json:"project_id"`
+
+	// ServiceAccount The service account the service impersonates to import the image.
+	// The service needs the Service Account Token Creator role on it.
+	ServiceAccount string `json:"service_account"`
+}
+
 // GCPUploadOptions defines model for GCPUploadOptions.
 type GCPUploadOptions struct {
 	// Bucket Name of an existing STANDARD Storage class Bucket.
 	Bucket *string `json:"bucket,omitempty"`
 
+	// CredentialsId ID of registered cloud credentials of the tenant to upload the
+	// image with, instead of the credentials of the service.
+	CredentialsId *openapi_types.UUID `json:"credentials_id,omitempty"`
+
 	// ImageName The name to use for the imported and shared Compute Engine image.
 	// The image name must be unique within the GCP project, which is used
 	// for the OS image upload and import. If not specified a random
@@ -1414,6 +1645,10 @@ type ImageRequest struct {
 	// different targets as well as multiple targets of the same kind are
 	// supported.
 	UploadTargets *[]UploadTarget `json:"upload_targets,omitempty"`
+
+	// WorkerLabels Labels the worker building the image needs to be configured
+	// with. The image is only built by workers having all of them.
+	WorkerLabels *[]string `json:"worker_labels,omitempty"`
 }
 
 // ImageSBOM defines model for ImageSBOM.
@@ -1575,6 +1810,30 @@ type Locale struct {
 	Languages *[]string `json:"languages,omitempty"`
 }
 
+// Lockfile defines model for Lockfile.
+type Lockfile struct {
+	// Pipelines The locked packages of each depsolve transaction of each pipeline
+	// of the image, keyed by the name of the pipeline.
+	Pipelines map[string][][]LockfilePackage `json:"pipelines"`
+	Version   int                            `json:"version"`
+}
+
+// LockfilePackage defines model for LockfilePackage.
+type LockfilePackage struct {
+	Arch string `json:"arch"`
+
+	// Checksum Checksum of the package in the "type:value" format
+	Checksum        string    `json:"checksum"`
+	Epoch           int       `json:"epoch"`
+	Name            string    `json:"name"`
+	Release         string    `json:"release"`
+	RemoteLocations *[]string `json:"remote_locations,omitempty"`
+
+	// RepoUrl Base URL, metalink or mirrorlist of the repository providing the package
+	RepoUrl *string `json:"repo_url,omitempty"`
+	Version string  `json:"version"`
+}
+
 // LogicalVolume defines model for LogicalVolume.
 type LogicalVolume struct {
 	// FsType The filesystem type for the logical volume. Swap LVs must have an empty mountpoint.
@@ -1682,6 +1941,14 @@ type PackageDetails struct {
 	Version     string  `json:"version"`
 }
 
+// PackageDiff defines model for PackageDiff.
+type PackageDiff struct {
+	Added      []PackageVersion       `json:"added"`
+	Downgraded []PackageVersionChange `json:"downgraded"`
+	Removed    []PackageVersion       `json:"removed"`
+	Upgraded   []PackageVersionChange `json:"upgraded"`
+}
+
 // PackageGroup defines model for PackageGroup.
 type PackageGroup struct {
 	// Name Package group name
@@ -1717,11 +1984,45 @@ type PackageMetadataCommon struct {
 	Version   string  `json:"version"`
 }
 
+// PackageVersion defines model for PackageVersion.
+type PackageVersion struct {
+	Arch string `json:"arch"`
+	Name string `json:"name"`
+
+	// Version Epoch:Version-Release of the package, the epoch is omitted if it is 0
+	Version string `json:"version"`
+}
+
+// PackageVersionChange defines model for PackageVersionChange.
+type PackageVersionChange struct {
+	Arch       string `json:"arch"`
+	Name       string `json:"name"`
+	NewVersion string `json:"new_version"`
+	OldVersion string `json:"old_version"`
+}
+
 // Partition defines model for Partition.
 type Partition struct {
 	union json.RawMessage
 }
 
+// PipelineChange defines model for PipelineChange.
+type PipelineChange struct {
+	AddedStages []string `json:"added_stages"`
+
+	// ChangedStages Types of the stages with different options
+	ChangedStages []string `json:"changed_stages"`
+	Name          string   `json:"name"`
+	RemovedStages []string `json:"removed_stages"`
+}
+
+// PipelineDiff defines model for PipelineDiff.
+type PipelineDiff struct {
+	Added   []string         `json:"added"`
+	Changed []PipelineChange `json:"changed"`
+	Removed []string         `json:"removed"`
+}
+
 // Progress defines model for Progress.
 type Progress struct {
 	// Done Amount of completed steps in the build.
@@ -1791,6 +2092,44 @@ type Repository struct {
 	Rhsm *bool `json:"rhsm,omitempty"`
 }
 
+// RepositoryCheckRequest defines model for RepositoryCheckRequest.
+type RepositoryCheckRequest struct {
+	Repositories []Repository `json:"repositories"`
+}
+
+// RepositoryCheckResponse defines model for RepositoryCheckResponse.
+type RepositoryCheckResponse struct {
+	// Results Health of each repository, in the order of the request
+	Results []RepositoryHealth `json:"results"`
+}
+
+// RepositoryDiff defines model for RepositoryDiff.
+type RepositoryDiff struct {
+	// Added URLs of the repositories only the other compose installed packages from
+	Added []string `json:"added"`
+
+	// Removed URLs of the repositories only the compose installed packages from
+	Removed []string `json:"removed"`
+}
+
+// RepositoryHealth defines model for RepositoryHealth.
+type RepositoryHealth struct {
+	Error     *string   `json:"error,omitempty"`
+	GpgErrors *[]string `json:"gpg_errors,omitempty"`
+
+	// Healthy True when no problems were found
+	Healthy bool `json:"healthy"`
+
+	// LastUpdated Newest timestamp found in repomd.xml
+	LastUpdated  *time.Time `json:"last_updated,omitempty"`
+	PackageCount int        `json:"package_count"`
+	Revision     *string    `json:"revision,omitempty"`
+	TlsError     *string    `json:"tls_error,omitempty"`
+
+	// Url Base URL the metadata was fetched from
+	Url *string `json:"url,omitempty"`
+}
+
 // SSHKey defines model for SSHKey.
 type SSHKey struct {
 	// Key Adds the key to the user's authorized_keys file
@@ -1829,6 +2168,41 @@ type Services struct {
 	Masked *[]string `json:"masked,omitempty"`
 }
 
+// SourceBundleComposeRequest defines model for SourceBundleComposeRequest.
+type SourceBundleComposeRequest struct {
+	Architecture string `json:"architecture"`
+
+	// Name File name of the bundle in the source bundle directory of the worker
+	Name string `json:"name"`
+}
+
+// SourceBundleId defines model for SourceBundleId.
+type SourceBundleId struct {
+	Href string             `json:"href"`
+	Id   openapi_types.UUID `json:"id"`
+	Kind string             `json:"kind"`
+}
+
+// SourceBundleImportRequest defines model for SourceBundleImportRequest.
+type SourceBundleImportRequest struct {
+	// Name File name of the bundle in the source bundle directory of the worker
+	Name string `json:"name"`
+}
+
+// SourceBundleStatus defines model for SourceBundleStatus.
+type SourceBundleStatus struct {
+	Href string `json:"href"`
+	Id   string `json:"id"`
+	Kind string `json:"kind"`
+
+	// Size Size of an exported bundle in bytes
+	Size *int64 `json:"size,omitempty"`
+
+	// Sources Number of sources in the bundle
+	Sources *int              `json:"sources,omitempty"`
+	Status  UploadStatusValue `json:"status"`
+}
+
 // SubManConfig defines model for SubManConfig.
 type SubManConfig struct {
 	Rhsm      *SubManRHSMConfig      `json:"rhsm,omitempty"`
@@ -1977,6 +2351,24 @@ type Page = string
 // Size defines model for size.
 type Size = string
 
+// GetAuditLogParams defines parameters for GetAuditLog.
+type GetAuditLogParams struct {
+	// Principal Only list the calls of this principal
+	Principal *string `form:"principal,omitempty" json:"principal,omitempty"`
+
+	// Action Only list the calls of this operation
+	Action *string `form:"action,omitempty" json:"action,omitempty"`
+
+	// Since Only list the calls made at or after this time
+	Since *time.Time `form:"since,omitempty" json:"since,omitempty"`
+
+	// Until Only list the calls made before this time
+	Until *time.Time `form:"until,omitempty" json:"until,omitempty"`
+
+	// Limit Only list the most recent calls, at most this many
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
 // GetDistributionParams defines parameters for GetDistribution.
 type GetDistributionParams struct {
 	// ImageType Filter by image type. Multiple values can be specified.
@@ -1995,18 +2387,33 @@ type GetErrorListParams struct {
 	Size *Size `form:"size,omitempty" json:"size,omitempty"`
 }
 
+// PostCloudCredentialsJSONRequestBody defines body for PostCloudCredentials for application/json ContentType.
+type PostCloudCredentialsJSONRequestBody = CloudCredentialsRequest
+
 // PostComposeJSONRequestBody defines body for PostCompose for application/json ContentType.
 type PostComposeJSONRequestBody = ComposeRequest
 
 // PostCloneComposeJSONRequestBody defines body for PostCloneCompose for application/json ContentType.
 type PostCloneComposeJSONRequestBody = CloneComposeBody
 
+// PostComposeShareJSONRequestBody defines body for PostComposeShare for application/json ContentType.
+type PostComposeShareJSONRequestBody = ComposeShareRequest
+
 // PostDepsolveBlueprintJSONRequestBody defines body for PostDepsolveBlueprint for application/json ContentType.
 type PostDepsolveBlueprintJSONRequestBody = DepsolveRequest
 
+// PostRepositoriesCheckJSONRequestBody defines body for PostRepositoriesCheck for application/json ContentType.
+type PostRepositoriesCheckJSONRequestBody = RepositoryCheckRequest
+
 // PostSearchPackagesJSONRequestBody defines body for PostSearchPackages for application/json ContentType.
 type PostSearchPackagesJSONRequestBody = SearchPackagesRequest
 
+// PostSourceBundleComposeJSONRequestBody defines body for PostSourceBundleCompose for application/json ContentType.
+type PostSourceBundleComposeJSONRequestBody = SourceBundleComposeRequest
+
+// PostSourceBundleImportJSONRequestBody defines body for PostSourceBundleImport for application/json ContentType.
+type PostSourceBundleImportJSONRequestBody = SourceBundleImportRequest
+
 // AsBlueprintFileGroup0 returns the union data inside the BlueprintFile_Group as a BlueprintFileGroup0
 func (t BlueprintFile_Group) AsBlueprintFileGroup0() (BlueprintFileGroup0, error) {
 	var body BlueprintFileGroup0
@@ -3159,9 +3566,24 @@ func (t *UploadStatus_Options) UnmarshalJSON(b []byte) error {
 
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
+	// The audit log of the tenant
+	// (GET /audit)
+	GetAuditLog(ctx echo.Context, params GetAuditLogParams) error
 	// The status of a cloned compose
 	// (GET /clones/{id})
 	GetCloneStatus(ctx echo.Context, id openapi_types.UUID) error
+	// The cloud credentials of the tenant
+	// (GET /cloud-credentials)
+	GetCloudCredentialsList(ctx echo.Context) error
+	// Register cloud credentials
+	// (POST /cloud-credentials)
+	PostCloudCredentials(ctx echo.Context) error
+	// Delete cloud credentials
+	// (DELETE /cloud-credentials/{id})
+	DeleteCloudCredentials(ctx echo.Context, id openapi_types.UUID) error
+	// Get cloud credentials
+	// (GET /cloud-credentials/{id})
+	GetCloudCredentials(ctx echo.Context, id openapi_types.UUID) error
 	// Create compose
 	// (POST /compose)
 	PostCompose(ctx echo.Context) error
@@ -3177,9 +3599,15 @@ type ServerInterface interface {
 	// Clone an existing compose
 	// (POST /composes/{id}/clone)
 	PostCloneCompose(ctx echo.Context, id openapi_types.UUID) error
+	// Compare two composes.
+	// (GET /composes/{id}/diff/{other})
+	GetComposeDiff(ctx echo.Context, id openapi_types.UUID, other openapi_types.UUID) error
 	// Download the artifact for a compose.
 	// (GET /composes/{id}/download)
 	GetComposeDownload(ctx echo.Context, id openapi_types.UUID) error
+	// Get a lockfile of the packages a compose was built from.
+	// (GET /composes/{id}/lockfile)
+	GetComposeLockfile(ctx echo.Context, id openapi_types.UUID) error
 	// Get logs for a compose.
 	// (GET /composes/{id}/logs)
 	GetComposeLogs(ctx echo.Context, id openapi_types.UUID) error
@@ -3192,6 +3620,18 @@ type ServerInterface interface {
 	// Get the SBOMs for a compose.
 	// (GET /composes/{id}/sboms)
 	GetComposeSBOMs(ctx echo.Context, id openapi_types.UUID) error
+	// The grants of other tenants on a compose
+	// (GET /composes/{id}/shares)
+	GetComposeShares(ctx echo.Context, id openapi_types.UUID) error
+	// Share a compose with another tenant
+	// (POST /composes/{id}/shares)
+	PostComposeShare(ctx echo.Context, id openapi_types.UUID) error
+	// Revoke the grant of a tenant on a compose
+	// (DELETE /composes/{id}/shares/{channel})
+	DeleteComposeShare(ctx echo.Context, id openapi_types.UUID, channel string) error
+	// Export the sources of a compose as a source bundle
+	// (POST /composes/{id}/source-bundle)
+	PostComposeSourceBundle(ctx echo.Context, id openapi_types.UUID) error
 	// Depsolve one or more blueprints
 	// (POST /depsolve/blueprint)
 	PostDepsolveBlueprint(ctx echo.Context) error
@@ -3210,9 +3650,24 @@ type ServerInterface interface {
 	// Get the openapi spec in json format
 	// (GET /openapi)
 	GetOpenapi(ctx echo.Context) error
+	// Check the health of a list of repositories
+	// (POST /repositories/check)
+	PostRepositoriesCheck(ctx echo.Context) error
 	// Search for detailed information on a list of package names
 	// (POST /search/packages)
 	PostSearchPackages(ctx echo.Context) error
+	// Build the image of a source bundle without fetching any sources
+	// (POST /source-bundles/compose)
+	PostSourceBundleCompose(ctx echo.Context) error
+	// Import a source bundle into the osbuild stores of a worker
+	// (POST /source-bundles/import)
+	PostSourceBundleImport(ctx echo.Context) error
+	// The status of a source bundle export or import
+	// (GET /source-bundles/{id})
+	GetSourceBundleStatus(ctx echo.Context, id openapi_types.UUID) error
+	// Download an exported source bundle
+	// (GET /source-bundles/{id}/download)
+	GetSourceBundleDownload(ctx echo.Context, id openapi_types.UUID) error
 }
 
 // ServerInterfaceWrapper converts echo contexts to parameters.
@@ -3220,6 +3675,54 @@ type ServerInterfaceWrapper struct {
 	Handler ServerInterface
 }
 
+// GetAuditLog converts echo context to params.
+func (w *ServerInterfaceWrapper) GetAuditLog(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetAuditLogParams
+	// ------------- Optional query parameter "principal" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "principal", ctx.QueryParams(), &params.Principal, runtime.BindQueryParameterOptions{Type: "string", Format: ""})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter principal: %s", err))
+	}
+
+	// ------------- Optional query parameter "action" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "action", ctx.QueryParams(), &params.Action, runtime.BindQueryParameterOptions{Type: "string", Format: ""})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter action: %s", err))
+	}
+
+	// ------------- Optional query parameter "since" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "since", ctx.QueryParams(), &params.Since, runtime.BindQueryParameterOptions{Type: "string", Format: "date-time"})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter since: %s", err))
+	}
+
+	// ------------- Optional query parameter "until" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "until", ctx.QueryParams(), &params.Until, runtime.BindQueryParameterOptions{Type: "string", Format: "date-time"})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter until: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "limit", ctx.QueryParams(), &params.Limit, runtime.BindQueryParameterOptions{Type: "integer", Format: ""})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetAuditLog(ctx, params)
+	return err
+}
+
 // GetCloneStatus converts echo context to params.
 func (w *ServerInterfaceWrapper) GetCloneStatus(ctx echo.Context) error {
 	var err error
@@ -3238,6 +3741,64 @@ func (w *ServerInterfaceWrapper) GetCloneStatus(ctx echo.Context) error {
 	return err
 }
 
+// GetCloudCredentialsList converts echo context to params.
+func (w *ServerInterfaceWrapper) GetCloudCredentialsList(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetCloudCredentialsList(ctx)
+	return err
+}
+
+// PostCloudCredentials converts echo context to params.
+func (w *ServerInterfaceWrapper) PostCloudCredentials(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostCloudCredentials(ctx)
+	return err
+}
+
+// DeleteCloudCredentials converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteCloudCredentials(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(BearerScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteCloudCredentials(ctx, id)
+	return err
+}
+
+// GetCloudCredentials converts echo context to params.
+func (w *ServerInterfaceWrapper) GetCloudCredentials(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(BearerScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetCloudCredentials(ctx, id)
+	return err
+}
+
 // PostCompose converts echo context to params.
 func (w *ServerInterfaceWrapper) PostCompose(ctx echo.Context) error {
 	var err error
@@ -3312,6 +3873,30 @@ func (w *ServerInterfaceWrapper) PostCloneCompose(ctx echo.Context) error {
 	return err
 }
 
+// GetComposeDiff converts echo context to params.
+func (w *ServerInterfaceWrapper) GetComposeDiff(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// ------------- Path parameter "other" -------------
+	var other openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "other", ctx.Param("other"), &other, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter other: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetComposeDiff(ctx, id, other)
+	return err
+}
+
 // GetComposeDownload converts echo context to params.
 func (w *ServerInterfaceWrapper) GetComposeDownload(ctx echo.Context) error {
 	var err error
@@ -3330,6 +3915,22 @@ func (w *ServerInterfaceWrapper) GetComposeDownload(ctx echo.Context) error {
 	return err
 }
 
+// GetComposeLockfile converts echo context to params.
+func (w *ServerInterfaceWrapper) GetComposeLockfile(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetComposeLockfile(ctx, id)
+	return err
+}
+
 // GetComposeLogs converts echo context to params.
 func (w *ServerInterfaceWrapper) GetComposeLogs(ctx echo.Context) error {
 	var err error
@@ -3396,6 +3997,86 @@ func (w *ServerInterfaceWrapper) GetComposeSBOMs(ctx echo.Context) error {
 	return err
 }
 
+// GetComposeShares converts echo context to params.
+func (w *ServerInterfaceWrapper) GetComposeShares(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(BearerScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetComposeShares(ctx, id)
+	return err
+}
+
+// PostComposeShare converts echo context to params.
+func (w *ServerInterfaceWrapper) PostComposeShare(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(BearerScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostComposeShare(ctx, id)
+	return err
+}
+
+// DeleteComposeShare converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteComposeShare(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// ------------- Path parameter "channel" -------------
+	var channel string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "channel", ctx.Param("channel"), &channel, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: ""})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter channel: %s", err))
+	}
+
+	ctx.Set(BearerScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteComposeShare(ctx, id, channel)
+	return err
+}
+
+// PostComposeSourceBundle converts echo context to params.
+func (w *ServerInterfaceWrapper) PostComposeSourceBundle(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(BearerScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostComposeSourceBundle(ctx, id)
+	return err
+}
+
 // PostDepsolveBlueprint converts echo context to params.
 func (w *ServerInterfaceWrapper) PostDepsolveBlueprint(ctx echo.Context) error {
 	var err error
@@ -3508,6 +4189,17 @@ func (w *ServerInterfaceWrapper) GetOpenapi(ctx echo.Context) error {
 	return err
 }
 
+// PostRepositoriesCheck converts echo context to params.
+func (w *ServerInterfaceWrapper) PostRepositoriesCheck(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostRepositoriesCheck(ctx)
+	return err
+}
+
 // PostSearchPackages converts echo context to params.
 func (w *ServerInterfaceWrapper) PostSearchPackages(ctx echo.Context) error {
 	var err error
@@ -3519,6 +4211,64 @@ func (w *ServerInterfaceWrapper) PostSearchPackages(ctx echo.Context) error {
 	return err
 }
 
+// PostSourceBundleCompose converts echo context to params.
+func (w *ServerInterfaceWrapper) PostSourceBundleCompose(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostSourceBundleCompose(ctx)
+	return err
+}
+
+// PostSourceBundleImport converts echo context to params.
+func (w *ServerInterfaceWrapper) PostSourceBundleImport(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostSourceBundleImport(ctx)
+	return err
+}
+
+// GetSourceBundleStatus converts echo context to params.
+func (w *ServerInterfaceWrapper) GetSourceBundleStatus(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(BearerScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetSourceBundleStatus(ctx, id)
+	return err
+}
+
+// GetSourceBundleDownload converts echo context to params.
+func (w *ServerInterfaceWrapper) GetSourceBundleDownload(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(BearerScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetSourceBundleDownload(ctx, id)
+	return err
+}
+
 // This is a simple interface which specifies echo.Route addition functions which
 // are present on both echo.Echo and echo.Group, since we want to allow using
 // either of them for path registration
@@ -3547,266 +4297,350 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 		Handler: si,
 	}
 
+	router.GET(baseURL+"/audit", wrapper.GetAuditLog)
 	router.GET(baseURL+"/clones/:id", wrapper.GetCloneStatus)
+	router.GET(baseURL+"/cloud-credentials", wrapper.GetCloudCredentialsList)
+	router.POST(baseURL+"/cloud-credentials", wrapper.PostCloudCredentials)
+	router.DELETE(baseURL+"/cloud-credentials/:id", wrapper.DeleteCloudCredentials)
+	router.GET(baseURL+"/cloud-credentials/:id", wrapper.GetCloudCredentials)
 	router.POST(baseURL+"/compose", wrapper.PostCompose)
 	router.GET(baseURL+"/composes/", wrapper.GetComposeList)
 	router.DELETE(baseURL+"/composes/:id", wrapper.DeleteCompose)
 	router.GET(baseURL+"/composes/:id", wrapper.GetComposeStatus)
 	router.POST(baseURL+"/composes/:id/clone", wrapper.PostCloneCompose)
+	router.GET(baseURL+"/composes/:id/diff/:other", wrapper.GetComposeDiff)
 	router.GET(baseURL+"/composes/:id/download", wrapper.GetComposeDownload)
+	router.GET(baseURL+"/composes/:id/lockfile", wrapper.GetComposeLockfile)
 	router.GET(baseURL+"/composes/:id/logs", wrapper.GetComposeLogs)
 	router.GET(baseURL+"/composes/:id/manifests", wrapper.GetComposeManifests)
 	router.GET(baseURL+"/composes/:id/metadata", wrapper.GetComposeMetadata)
 	router.GET(baseURL+"/composes/:id/sboms", wrapper.GetComposeSBOMs)
+	router.GET(baseURL+"/composes/:id/shares", wrapper.GetComposeShares)
+	router.POST(baseURL+"/composes/:id/shares", wrapper.PostComposeShare)
+	router.DELETE(baseURL+"/composes/:id/shares/:channel", wrapper.DeleteComposeShare)
+	router.POST(baseURL+"/composes/:id/source-bundle", wrapper.PostComposeSourceBundle)
 	router.POST(baseURL+"/depsolve/blueprint", wrapper.PostDepsolveBlueprint)
 	router.GET(baseURL+"/distributions", wrapper.GetDistributionList)
 	router.GET(baseURL+"/distributions/:distro", wrapper.GetDistribution)
 	router.GET(baseURL+"/errors", wrapper.GetErrorList)
 	router.GET(baseURL+"/errors/:id", wrapper.GetError)
 	router.GET(baseURL+"/openapi", wrapper.GetOpenapi)
+	router.POST(baseURL+"/repositories/check", wrapper.PostRepositoriesCheck)
 	router.POST(baseURL+"/search/packages", wrapper.PostSearchPackages)
+	router.POST(baseURL+"/source-bundles/compose", wrapper.PostSourceBundleCompose)
+	router.POST(baseURL+"/source-bundles/import", wrapper.PostSourceBundleImport)
+	router.GET(baseURL+"/source-bundles/:id", wrapper.GetSourceBundleStatus)
+	router.GET(baseURL+"/source-bundles/:id/download", wrapper.GetSourceBundleDownload)
 
 }
 
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+y993MbObI4/q+g+Nlvef3MJAYFV129R1GJyhYVLB1dOnAGJCHNAGMAQ4ra5//9WwiT",
-	"SAyDbO/t3vMPd2txEBvdje5Ghz8KDvUDShARvPDxj0IAGfSRQMz8NUTyvy7iDsOBwJQUPhYu4RABTFz0",
-	"UigW0Av0Aw9lmo+hF6LCx8JG4du3YgHLPl9DxKaFYoFAX35RLYsF7oyQD2UXMQ3k71wwTIaqG8evlrnP",
-	"Q7+PGKADgAXyOcAEIOiMgBkwvZpogHg11WruelTbRev5Fn1UQ7fuuvvtWtujBLUl+LiaCLoulsuE3iWj",
-	"AWICy4UMoMdRsRCkfvqjwNBQ7WduomKBjyBDjxMsRo/QcWhoDsbsrPDxn4WNWr3R3Nza3qlu1ApfigUF",
-	"CetY5gfIGJyqvTP0NcQMuXIYs4YvcTPaf0KOkP30/m4Cj0L3QoGev3mD8cILKCxNEBeljULxz9x2scAJ",
-	"DPiIikd92uk1+dNS9HV+VXaA2de6DIxdAUWoqSQDKOjj7Iqgj0tVZ7te3dqpb201mztNt9G3QWxNEM9s",
-	"Rs5bXIID3fr3oEAQ9j3saBIewNATcbssSXcGgCMBBAXqM/hdjBAwXYAi3vdFAIFHybAIaH8QcgcK5IKb",
-	"q9MewRwwJEJGkFsGHcEBegkwg3Jo4OPhSIA+ApxSghgQI0jAgDJAxQgxEKq99YiAbIgEL/dIjyRrESxE",
-	"clo+okwgJmcDqckAJG6P4OyEmAO5dg59BCBXU8m/09OBZLbkiPqUegiS7z/U1Y4zDxVD5tlZcXoK2cg6",
-	"PnNGWCBHhAx1yIAuRZYsEqS7Ax8J6EIBwYBRH2AfDhEHHu4zqHh2dtXq86NczwIE/aPwG0ODwsfC/6sk",
-	"913FcPRKRw5xPQ30wr/Nru0MBurCka2AnAhIPsIVlowQZsBFAmKPFyxgiTjOgt2qJsXUeb9sbz5uNpYe",
-	"tupnPYrXkKHvodzRNEDscfw4RARp1M5QceFWYmJ2R+0RpRwpdL89Awqg4EgOcwuSUYrAxYMBYogIMEBQ",
-	"7p4DSoBaMIDyf2OIPdj3UI+4KEDExWQoW8hx54bTNIRI6EtwqEXd1lIQSVilxhH7WZxLaqUDNYWmUeTq",
-	"s5YMBfghVzwkJPhrKMUe1XCIx4gAhjgNmYPAkNEwKCv2ISeRjID6WEgupVBYdpFHh7iQPIVB4lIfUIJA",
-	"H3Lkyh1CcHPT2QOY94jZIXLNBtOXlVqY7TbwqJM6qfQGT82XaJMBo2MsNxkt/1EtvwgmI8T0EWpU5yMa",
-	"eq7afAQXSGS3IeYCMbW+IzqRdOBhLgD0PBAtg3/skZEQAf9YqbjU4WUfO4xyOhBlh/oVREohrzgerkB5",
-	"9hVzjf73GKPJP9RPJcfDJQ8KxMX/g6/RPfsoJ3qMJ3mnQC5XHP0kQU+oADxADh5g5BYBFvJHF7mhkzmQ",
-	"HDjMAl2yXhRK+rBfwum+i7Eriy4rgHt2Kdc0dCC5MsMcqhltolTYj5fwiN35RXX25JLSzd6wmAZqutv9",
-	"mlOC/Vqj1Ghs1Es7VadZ2tyo1aubaLu6g2q21QlEIBEL1iUXoRuttiqDggNMXHXWmkI1T7mkTEBvFVyM",
-	"8FDgMSq5mCFHUDatDELiQh8RAT0+97U0opOSoCU5dUkveQZITWcLDZr9zdKGUx+UGi6sluBmrVaq9qub",
-	"1Vp9x91yt5Yy+gRi82c7h4FLLoS8uz/LIVdhOTOLTA1gW8KuF6KAYSLWvIocSgTExOijM3dO9C0SEQQF",
-	"yO9L9k303SyRAnoAMjGAjhTwY51hkTgQj2vTJZyQC+rjVxhfrIuGirfdznablTEsSoyLuWB0ftfXUjqW",
-	"33A/VKQrKAg5iqVNRyukZdAZAA8NBEB+IKbq04hy0SN6YDDBnqcoic/T9gC5lMFSfcdGwIjIC9p99Kkb",
-	"GlV7JbCeqfY2mCrM5TZDg/MsyV5/lxvtyxuYC+h5yF31OM0oml1aZk/tY0ZKIwB62AjygR6FF6UKILHD",
-	"VT/3ofM8gczlCu5QwD72sJgqeK6zOtvCImqcO4FoLbkQ+15Y2VYzRoxb5YsW4MgfIwZMC0CUjSaDUFvl",
-	"rfJW9e0ibR4drclMoIOYWE7/rbZslplKU6Tm+9gG+b3kowS+wxAUsbgYsyG8Dh+KhpzajsPF/Hn5APxZ",
-	"tSWDpU3PD2TLgUuXtTzYu1AtsZVmDuTPPwoA8anLUW1AUIuYcoF8i9grRVI6AEkb4EsRMqCYiNQS37QY",
-	"M6l1STZOtq94JjjoXHaBT11k1f0HmKEJ9Lw1VmI6RDw0HwoJC11v17lcU94ldoWqTckAD5VuF106RsWd",
-	"18uGBEcX4EIFPWqnzLeKpymqfHTRGDtLlLp0B6A7FIETMqmGelNAiTeVl+Ag9OI7FLlDVOLYDzylQ5Qi",
-	"PsqU+j9zWVZcNK5wF1o3GHVcusO44bdi4RkxgpaiwYluZXQ/Dy1rf6pbfSsWaIAId2CwMqJdBIh0261L",
-	"ffkwoQ4Dk+GjwuWMbQCGgpa8sT9nIegiDzkCjKS0rkWYZyPVR5JIPDJyy+BdNNA7/V2KOAxOQEg8xHmP",
-	"CKUaQIaUGk0Z8ClDGQrHUqvBzgg4kCOpGcTjnN6elcE7NTb0JnDKeyTkiMvfiwBJzX4yQopxmSkIBehF",
-	"MJgevwzeMTh5B1RPubJ4+bxHbIPkrDNrxWBwUigWNPxiUH6xKp4B5TjvNrpKfZVEP2FYIPmPChJOZRr6",
-	"ZdW/7FayHNrYPc6pQBLEUMhvPAKCUMIigAL0Q+y5QGAflVcXdWJ0ildnvdnYiPvLhro66p7N3c8sWN7v",
-	"cr4bR0zyhKXL70btZB8+ekbTfHbL+Qg8oylfFTTd7tEJskJDwviVkqXUfR21+1YshFwzHPva5Nfvuf9u",
-	"uE0z+rZIalP3t0Vw1MqUuqKXyQwaz7LynAsFtKuFcuUR/1ejQw4CD8qR0Yuwceqc+1Pdf7MjQTDErqRl",
-	"aEw5cyZcRtV7EiXoYlD4+M95GT7+BROBhkpafikNaSn5dbNR+PZFqye2N1jEfMyluM2BHjS+vNQqMQHU",
-	"EVBdaT4UmcVVNxsNGwgCKEY2lUKMQKxOe9l9KnbiT83vcyPaEfFiQvQTbhamYQRT2esngnRG51C7/rIM",
-	"exMpM4uCPibRO/Mi4omaqfOMWH/W0lIZQ7ZUQUp1LsZzL1l8IlSu8R4TdXOBY8Q5zS/nHvmoUajsvEZ9",
-	"Br9L/ZkyARgkQ8TfKzNywKigDvUUK5ISSfq0/1mo1T4KJygUC9tV8w/sw0D9c7233xW5e7ThNJeX/HR1",
-	"+0Y0woPqtR6DjAWsOQSTPI4LhqBv3e4Tp+RRQOxR9cuSJUbTHHcvzq/jTpL0qYedqdUoexkKSZ2xQR3o",
-	"tqCzFzFqeRkDyaN5EXDJKKAAkEy14E0cKR7FTwZA0B6ReDscCR5LflLS8aHADvS8qcQ4gpSt3rAduRMP",
-	"y6Giyc3MDiWcekYGMZzuYyEMlWF0nr8xKrmN2eU85qwLxRQEZ3lKMtNC4kwJQnMH34cchczL4l/CLiKD",
-	"tuOSMkPuCGpjtqMvv4qLuaiwEfK2K9sV/aBYkSNSXqG8koEWw1Yz/QwdGatfCnIZzdVDudaqYTB0Rsh5",
-	"tncdBkMlKKV3uXQxOSfoIwE9TJ7tkPIxY5TxsjZuBozK4yhTNqxE/f5bCsX/iIyftV5YrdY2IXNG/4if",
-	"ZJeBTU/iYS7mFxGvQX4uO4gIytX8/82QhyBH/9guaVJPzQzl/2829C9qfbuQo4vuKmtRhs3HERUD/GK3",
-	"WXF5qByolpBhMZX3sUApeUL5PERYmue1kG+pZJjKYVMfo9vZ6DCPi9GDc2+MGB5MbZ9nnyCWUNuNkUbW",
-	"sBguM9IPbRxTy4zYjSzzkg8i6EYST6QrFy0QybOEt/QLKx2AZPEpmw50Xf1+LSUnQdMifYKCqvnGKrQ+",
-	"oja7zrWZ4B0HsgGIn8FsQ1q1I6kVaa8gqRxlpDvORyXk1prNjR3QarVa7fr5K2xveA97nY3z6/2m/K1z",
-	"zg5P9tnZPf5wdnYzCY/gVevYvzqlnderQe3rXs3da75Wd69fKpsvtjXNv27J7WzYRWHOJ5TZ3ijNI7pp",
-	"ALiATN1kYgR+2/ytCH5r/laUcuxvtf5vsdWhjwAXVN5/kPcIJAARh00DecdFI5XBhRghNsEpY0UfAaF0",
-	"IleLyIkK0yNxvzRNpv3pkBb6Zp0BhpgA9dGgp1Vut6G1JJ+3YPXKNn5KhWO5B0PsuY8MKb8Rm61P+7hA",
-	"DzjZ90AQ9zFmC22P1KaLuG25R+5GiGinASSKug3k6e6Y6xHUg4/sLtkj5GCCPG/26exrCKdlTCuavZf6",
-	"clOZP0pqhI+a0Vsf2DCnjwGcehR+774HSp8yY6XaRQ+lUhRTG+50L97xVAOJrMoSpGATw2V+JCzROXLa",
-	"AVBE1taK3Ks2EIEL4k3BGHrYQJBSIVuX4lFKmEupMPavWhumi6CZgeAPGXPO6S6awIrVgg14N+yPqRdq",
-	"9jOjQGbUwRnHs/hbrNzzaCQ71dst8pJzk5RFPB6kaCykLhpgYuz1sSfN71Izfh95X0n1G+RPbSPyjK6b",
-	"C5vbPMCsrVkHkIlHPYkNArF9VvvwHd509hRYDy+vU7bbMjigDOxddFO/FbUcNMBIcg5IomdzSUfKXXSE",
-	"wO81MEIvwMVDLN7PzKXe4jMMRq3Arv3IAWOvMNk2ASIwSBCRYUIrNh8gfVir668zmGqzRRrYRsbqvuxh",
-	"sU7P+q9Mle9Uakk2ZLC+uq7pcYz8x/iFN2VLKJVKu/uHnXPQ3r+67hx02q3r/VKp1OuRs06nXd1rt1t9",
-	"PGxNOrutYeemUy6Xez1SKpX2z/dmunyHu32yOOvuU7EEu9RVwlNi6lp0bJZYBGU3TP9yhXhAiYlS8LwV",
-	"Rr1QK7uKWdu34pzHkJtlqBu1Omo0N7dKaHunX9qoufUSbDQ3S43a5maz2WhUq9Xqci19FZE+3l3izPT2",
-	"TS1qn3GZ0tNqeO4hDwmU50s1UkNa8CNHbX3GxF3ueK2gpZoW9QxWNNLr67j/QSett3RqVOrVNqVaW3YS",
-	"ke6KfmBq5uj8l9C3HnLxHuiQ/9CDUT53Sp60GlDMEubs4IgNoIP++Gbj8c/0CS99eaZPWO3F7gRoFrQQ",
-	"FGeQ4AHi4ofCw08P+v3AmLW9x6Mv3pkJXviRG6NSFkWPDvV9LKx+s7+PIJfC2iDWcQQwzYtvcCDT5gZM",
-	"HC9Uys75/u1Va00nshgQtjde7Qy/IgVemdYWG3oK8FfJmAtlBkKFjlBLjnbGqbNY6Mfuql++zUoZ/bQr",
-	"60ovpnLHcS+rkT3W5eJmoLMnxUuGHMpcgEnKyl4G11IcxVzJihnpsUeU04H2AVVGPEZ9AFPDjjHUaqLW",
-	"Q5UCvIrxvB9p5wt3rBqt7SVrcY5NObhmLx82Ql5pu5Ab5LEiZqkIlRivZjqvfkXMDvNWbqojTKMLZ/fi",
-	"7MfeE9FW5pUiORdwqRP66slc6kMqnlYr45qPxI8t2rE4zQZWGjAJmDHuJfvJDCEPldYzUnYxATwEuQBi",
-	"QtVAvKi8ZqJBtOkGkTFmlMjx1UNiqkWPQEeExiQiv0cuYGreVfmXOlQ5fb4O9HYZ4EfIrDYpgMfjLt9a",
-	"LNCku6I1sT5PLNJIv+J6JO4nA63WJwPIWxXcPXsOZqDsBlc5l33GKLM8wpoAv49/zErxmdcMyK3PBDZB",
-	"3jSeW4DeT0rH5qHjIC73MoDYC5nUpE1gnNxQyoIeN5zjjElAw9zOFsTEzcUVRDa/OIIqNxhNR6TY3NIi",
-	"y2T8sjtrksz6fKh3YzYtm5/UE6ea9aOAQ+tThscfkwejeccgRj1wfdoFqg0eYCdya4gnVYG/y56azAbt",
-	"KpjZ0vdEYC44lvg8zMPAjPV3xnRKuWKaVlDBoYWFw+GaM+gYPat+tww2KV64xpMcHpr7ffYxU/4ecfxI",
-	"WJ+L7Ew2Exk2DY7Z7WgmJnrGOebT3rk9ZDTHcO1PTfxixZzHxwVQm422LkZbtmKbEp1W8CP4i7gRqKfe",
-	"x2EwtD/36s/Ru7C9zXd5Iph3yV+uBj/d1eCHeQlw7j1+rw/AvzNsKBvC+KMiEB8XO4DvK3f1dJtMFFvK",
-	"nQsTkNXWpHqJOOqRTO90uKC8rF0UcOqNkQkJFwyjMYrHL4NWDF9vWlTu+jz5nDyKw7GJKsd+QFnK5+tf",
-	"c57q/0o8DnrEMO+E6a4G11luaQ2sykR5/VUjtX58FOYbYr9W9ItcJXhr5aGWh14tHKFz2V0n1ipy6pyj",
-	"6jxPnb9UwFU6jvtXHNbfNg4rG36VmG5TT5wB5WLI9NPq6sLNr1iuv0QsV+Ls8+df6YrsVr7XeyQizYsu",
-	"wIIjb6DydU31YISqXDmJQ1DWcqd8SCjrEUimJiuWBHT61ULFBTiI8/dqzdHEjxyJyAHDjDm3HcwBHhLK",
-	"onQGK7Hb/4BQtFRGkKX90m2/I7hs9ct/9WAxKdfMKa86+GQFkUjfgZaRzXufyfNohKekw9yMHIlHoyON",
-	"o+ClRdnrusb/J+kD9s4PwBgyLCmgCMQUk6F2u9JR6IIm4RRO1E/SwNXR/qnVwToHXJdeOMQkbyML1GTr",
-	"eIbuV31Fm8limEpoltVG85KZFd/4jvb2l6HIe2mlTHDcFmP73RxlRllNIDCzr2IWoF8y55O48sx4P/2Z",
-	"j7pt6vuGhyx0eorWZFPKE60pPzQ0VvneEh+KCA8Zegwgi9LbLqblfdUeRHHPQHcEKY0QoBecNtulA1lW",
-	"CCBNdqOjSOPgURNMit2/TBRpstSFoaRbzebbQknT0QNz8aQuZm8MJ52BcBxKaiJLfxaAV40p3TO2gB/h",
-	"6opjW9aKBGy6LPLsnHkMmAax9pbyaNX5GVOC7jBQHIyu4AuaWngOfGIuuBc/un3HU+kaIa9tKrFCpNOP",
-	"KqSJr+pM1q1VUpOmmfibk5POJVbNy08KZ5OKrpah1KEuyrMr6C8JbWWuqISMFliEAw8KyTesXi/aFgWi",
-	"NsA4ZEv5KYlIy8wUNf2IvJ3V/d/PV9mEEh92ypu2Yal6VLSnvzoIPU9qQ1H2q+R+9TGhcVaszFy50yg3",
-	"L+M+OvO8ZRIiX3SvGUrHeAjkS6iguc1Udir/H68g1/5EGzDqho7lHe1Sf5gPwL9CLjiCAuwTgVjAsFS+",
-	"MQlf7EEXWQk6+wisJd4IYErzxERptjpbUxZWb80a9mWGn8QepDlEmPd7TmaVaRITpKIDdFq9j5IKP2oH",
-	"K+P3/iNzoywl/BTNp6XXTJRAQvvW4dIUkhouPUvOcLH3xI9ybXGM1DKfHDHtkCF7wFSyUws2ruaZ4ejM",
-	"XKb5zMB2BFNb/jf4JmtQf48/0sHexbo5ITp7F8ZwCyjpU8iWZYdw8aM/GD5qcCsF7NGHzqMU2HPOFYfk",
-	"MQj7j89o+jiCfLS8FSYcOUbtXNxSSntJfMj8jQVJKDWJUC2WIzZG7DE3J/oc8quXhfUA2tUGgTgrHOBI",
-	"qJS+uZr8Mv1Fh3OnjA2LMs5Zd/HXz9TzE7W6JU5Av7IE/coSZCOYBcmBHu1lbOSv6b0ZasUE9KciKwDV",
-	"Nhpbje36ZmM7u9LQLPUHZxR6zE0plOxU6oXu/HYHfEH0ZWqXOiSyO4FB6p1FJ9ofQfXyYHIWJ2vLPqyg",
-	"FyFR82UgATUeKMTlExhYH1c82EeeneF/Z+4mC2n8CkDNPjUmfqyKpy+3D0Q4ZEdA21v8r7xWa+a1+rYA",
-	"tN3UqG+CarQs5c+rk95SJlUK+U+LfMhToo01kWJqvGSUFDwF8ggS68EuI1Atm1U3zk46EPLgiAjWjAfO",
-	"hftDlGlsDaDvYuICGCeGIUhMKHsG2jVZp4UBr5QgHUYkV+UIIBgcDLCjzFc9IkaUo7hHhug5EgKTYSyb",
-	"yZFskp3d4pI2G8meRYDnstFH0youBIPAm6rkYOnqT8mkOS7mC0g0Gj4SeNT7ZW7oSi+sVuuO7qP+jf5Z",
-	"0b/5kD/rX778r/7lrNXWP/wvDjgSH/Wv6t/69+WOsDZcOGxffo/LeD90npHIN37JW1WKuVII7F63zvda",
-	"V3ugq/N8AMeDnINdNUR5tuaM+aNkZlizvk6cfGImniB2+JNMU1VUc0Gb+kEoENgnQ0yisJ0euY4LgKiB",
-	"ZkryTLAYGUXksH0JjLdtlNrC5G7JOi7o0CNdkCtxPkxuydiTIqrV0yPvTPgTK8EAl/SRhyF29Ym/i8Tr",
-	"KFWMSTaTrHqdWj5JEbB5UMot6u+p6ijxnqIbPe1NmYKvpHoDT1VYLQYlNPlZ5OhRApAy6CIEYgdxj4Zu",
-	"eUjp0IRhmBQxqqJKJa7IY4ogZSvwKCEi9AQumZXHOUYcj3LERaQ5GPojv5tCORF6asSMu72XYHYk7yJZ",
-	"2WUWyChco9SgnY0YuKh9g6i5krvkKFlMtqGvQs9yj6iYN4MkCurGLTiVWjHWdsw0RnS7jTLn+FBwABn6",
-	"2CMAlMA7qQF9/AP5EHvY/fbuI2hJwRliD0DXZYhzrfMyFDDElZ6dPGbIIcDMtrTkaaBXBO+ghx30P6nQ",
-	"m3dlM7O5H1u635pr0FObIfLm9qcl5SBUgkHwPzAIeEBFeWg6RX3SS1Iq9rrQMPuP6j7Jdc2AwJXSvxUG",
-	"LvUhJh//0P+VEyryBN0QCwT0r+D3gGEfsun7+ck9T08Y5W0zNy0Upu8sRBLSeydFqncza7JT3WLUjGpl",
-	"aeagUphBMu2RCL69GdlVIdwcVhRiYTTCh1UPr2AMKh/nwazeExWA0z/+lGKn8b3742ojqbtZjv84mzsD",
-	"cgcRFxJR6jOI3VK9Wm9u1Jdq6anhistKLR1GNqo1hIfFeQwNW9JWrMT69zs1UevvrbkMl7/FzQz49vIw",
-	"nZT/8hoSdNRtiS6o4hJdrS+s4h29H7XXfuZc9CkVq3Y+iDtYhcS5OdYup2WcxZa9hKh2i2B9kN7ZGkuw",
-	"RtRdMjrGXDsig5ur05UC46yrSwfk/3wftrd6lumX3qV+3+qt96d4oqXraxvjeXXuecIYKdUmi7FxMkkK",
-	"KEm3mi7PJztgebH6mGA/9HtEZ5ZzQX+aamfJ8deo7TR2NrdqO5t5Vk4trj/SYKWUEllNKuluyu/aZWtl",
-	"FFMpBXQ/pasowTXw0GwBX5PFQCA/Sp/XIxBwFEAmmaNp7SKpcWlhV12wWHBAJySaogzOzPg9khRHNXNE",
-	"OSblf+NlRN+iXH6Snz4rUwBDPcLDQN/4a/hAa1hdq3GXXqQZKskQwAyWfomoUWVSmPdWxAHyMFmqNZpt",
-	"mrhZEHUz2t3I6FmxE7weJZ3aUiWkiAuhla2XdbSWIGRRMXeL+VZ/jB2iTCftp/4vtTxGqfhXao0wSRyq",
-	"DRvzGSzcEEX+wUnODdcMqn5JBlQO75EAqRWF/GwXYC+Mw+yJqrUL6KBHOPXTZKhMy1KN9KEKA4jRLJoz",
-	"g2g9YoBQTlnj451H6GA1w/M+9VfIGBI9Kb6T7RVevTOqT7lQXCeVU9x/AambnWUWUAbtbEhS93Lvs8q1",
-	"E1NWau88cF+WG7XV3tNLKs6gvwUFE/LJkUpR5Gyxcq6M2GcgYHQoNaKlLoNRu5Vzc6RWbDJzxJx3tQGy",
-	"KfdmOq9x982Os5CnRflBsiBfKxVHsRAlBi5Ei9b/jkofmHwdc3SRrT2+pvyaVCRfq2S6qmpprXjpCcTk",
-	"bTWOPAhj96aEZdizi8MJXyX+vw+5errXj1OPypd01eBYKWw+2h+6dykV2oUiMS9qzSw6PA8NoSNBEaIB",
-	"LhQLo2mfKW2KUGLnWEYwynnBjfzx0pKP5fV2o7pV32psbNca6SB6LdTYlCb0kvPydK6Ogw4AF7rA5wgK",
-	"oJ34UCp6ioYiCIX9iHKVVVtkbo5LKCSUYAd6IGozD/DsfGUdBWdNch0/zc6gdfcCqE/gd8WB5Qzyt9St",
-	"JTVOEnqeRp+Mt0b6fddHOVfAWedsP3MHzK8eBoFn0sNUqCOQMAkTVnc7TZHnnJ8C9PH3e4DmUOdix9wU",
-	"8VlBc5l19I4HXcHXO4lkTIft5cejSBVCsxkOBxqTjJ9RLM+NMYx+U9KlHbPTgTRLsTvi/I9JSr6UUjGD",
-	"7+mn5lgtiEbQWk0uc1z+hhuJFG9fSjyEfS0p452pJ52KIJ5N01eOEl3MfTDl7t5ufsu33CRqceqm1ZcJ",
-	"nPCSo6NnJ7w0giU2CrH5K/VPDoP4z1d9K6v/Rn3VvxEMtjKtsn9wGMAg4HM/Rj/Yc9ZLALtD+aNJtGn+",
-	"ipJ3mB+S4PNiYaie/IdOPPIwRFzEdkTtOJzugKlIxtd/JMPLv2cbMzhJhqPCGj5fKBY8PM5OpFR26JU0",
-	"vzavyZkWaIzYVIwwGZZsn7XHpPUTdeRWgxdUEpCVXl4LxcKYB1LvSP5VomNYKBYm3MuRkySen5hSQTOO",
-	"R3P5KN7w/NpJpwiYiQkNXVoiVFXccNdLHxQSKAQi7uqBmCdx0oF1bFeBFEQtAp36nQPIhiYho9EIJUKr",
-	"xFUM6CwHKlssJC6QWkjmEiGU++IfA8oc9LaQCzNBXG4kGVp/KbmoHw5XyyB2YhJqviGXWjLtgU671PZo",
-	"6JZ2IUcLQhiyPWvVWrW6U90qV60Pi4oC7CmhnukTtuSDkj+Pwv4qmbQgf559TmjUbDJkKlQlWUd9Y6lN",
-	"1Sw/maoY1XFIYlgiqHzJOZsoxfXsC4okXpM+kagUxnOu6kRzON0yb/g8ZVgXiVkBOjacitz3s0PmZGWX",
-	"9+cQ5WSqMprC/BdBBfRsn2agYPK7B5p9q/GizsVcb/5iQaUUWc95ZNEYeVCOPLwfIx/gxfiUbZ67brSm",
-	"1qs7LXmzeUZTFaAwz5m6yBjPoibAg1MaZp2fQ6sy60EyDO0h1pG7gE4Bo9hsHyVmx6Lx9GWyFUGgjxwq",
-	"5V7zPFwEPOxz9DVUVjyC1DM/4MihxIUmNWFKlEPk8aZbvrk+KG1/rwPaKR1KdS6vCMs6Lr2xJujpMU21",
-	"GOPpe3r7d3TxXVqPJ7vXxUV53u75apJ2/bAcolGSTB1/GTs5zrrPEOqiJyslJBVJZ4hL/Z4/Yq22aukg",
-	"M4MNGhftznfyuniEPE6XG/azyhukebazJTMRiAjrA2gL3Fyd6kcN5SupHLXTdccGSDhS9I6M/WXQkXJ9",
-	"ZAn6V8i8f8Up4fWzUbFH9CtJJnefetOKrIWUeNMcB0sdJmM1AcmxEFb5e6CpMgB+N4f8EVRrm9VGv+bC",
-	"TbTTbPTdeqO/3d+uwe16EzXh1pZb629WBwP4vqgDOfoMEmdU8vBzOqw1GU/FssbZR6VG9b43H7qbbZFT",
-	"CGw+YcgK3UwOoMVBRntIIOar95LJCBnQaN+xdIIe4EMCh4iB3x1IXA8FmLwH2EVEYDEFOGVaEFT54Wi/",
-	"pWwRTtCmhIc+YkCq6zqJ8WyGRsiB42F5nWTbjBDpkRiXYjyQgn+EWDk1PlePhJuN6/wrFcjJL7T8q5Ly",
-	"37CSsv0YrAaCHJl1yWbyl1NMRl20sgWr4iqLHlrbovCWfjY6NWbYHy5WGAOjxDkjAJfBAfYQGHq03zde",
-	"0rHhstgjaFgG71QyRD4q/de7Ge4ufHuOgdyMDHExlSjDwIJ1dUxYQt+D5FmXkNCpuVNJ7KJh0gy2DO6w",
-	"5zqQuUZWj7ZjdtMob2yU57ZSL9fh273czHml8qLMOzBZUUFpxwL7ebH0i2sco4DmjOthB5lUVKsKvRm7",
-	"iqVqoS/VIes3+/2TQYOVBMt5W4bOtrUI5G9xqbTTSfT0kRMRDAlUOl9JUOrx70aV9WtR5aX5muNdeOi7",
-	"zeVAN+3sKQjsk62O1ypbPA/9BSwgovmoKQhVWaTW6eHFx6NW90g5l2QrUY9grbn5sVlrbm1vu6juuo1G",
-	"Y2fLqW25jY2tWnNzu7652a9V69tVuNnf3KpuDapwY2er2tiqo4Yr/7EJGwNr5HouJb2NWvBQO/Ys4P/f",
-	"QzDmDWkZ3RTjQ1ZHGmWZWrmA5Wxc8bKqjOmqscva6mYmp7KVUFKuMKtRSDfsp/xi5p8K+qt612QGslc2",
-	"uwy9QGuT3xUZBjmyR+3vmi9KKUzyvhh/tETlsKtT6ZoTuUly5GWrbLrGjU8whCKdUVWqz7GiP5qoDSW+",
-	"LTSNzyZVjHZrPe4ZgObp/6oCxUpGgLilbTqVGzYnP6dLBo+ByuC5CqacQRJn/ORRstNs8tdHo1uuNlpu",
-	"wtRo2bNB1m9JzJrav32iy2XzaNx5fDbvfot9z+IXQvtkqyFsxq5c7pFWVJRM5XzWkuQ7U1TlXRG8S+ps",
-	"qL9MfY93INmHsnv2SB8lqp8SPFX2aD2ir4XIbPAOZa6OCQsYcpBrStFrR1Ll9gu5iv6V6n6fjq3huanq",
-	"L39e0Ze1i7ysltZmGAxN3SYTPWhOI+FEsUEjx4aRFICZiXS5PATPaJrkVMBDkvigKBU5a4LJSAylUlxg",
-	"+vLwElze7J522uBk/x7snl60T9TnHukR/1PnfPew5XQdurvf2jsdbN8fPaPX403oemf3ky14eNjxjqEn",
-	"to+fai+V3drJh1Fn0AlfDkVw+7SFeuT0arh3s7X5BK+bwe1e0z84O64Hz4igq4pz7X/9+un5fPqJjz7X",
-	"6KfPk/3Xm25/o31+1h60D4fPn7c/1Xrk9eGZdZw2O6h+qk3YSd+DoTu6+YBvIWntcX9j+37/K+83Wzf1",
-	"LVfcsLP6p3v3brhz9eEzvhzcbl/1yMnu03W1Pr7dvXDPuvy+vnMK22SzE2xcjIPtzj6tdND+7f3GV799",
-	"cdmCJ9X+8VE9HAwb7RA98w/X3R6ZfLq7Ru3Tl/DhdPPi7DO9uDyZjM8+DV76w43Pe9vj8KF6Ip4qzvlR",
-	"7QWG1Reft8Kdo+MAPY8vLq9evB6ZfhVP04cBo7cYHUyDycNw/GkiCDnbrgy7+2Hl+Paa3VebNX//5nqr",
-	"7fS3Gs/O0cH1weDs2SPPh5UeqQ5uGq0r2Kw2juovT9Vn0Uf18Ylz+ZleXoQnu7f8qDuuVm8O71vTSxRO",
-	"P2xvOTeV+/3R2dZzvXt78tQjm6jzMJzis4vqxNu4P9y7OnFCb/LMd1ofQu95uEGv+w1ef/UfxpfVrUN6",
-	"/XLXqD3Bk+Zd98P56AGhHtnerH6mt6O+s3ESdD88DR7oE2f74mH7sn/z8OF+fLB9FTD3rsWejvrHz7Xj",
-	"4Oqk9XI9euGfWnx3dLjRI9XT8KV2B892q8Nap3npnLnHFefrE61uOw572v0c4pc7hps43Dn7HGx/va4M",
-	"uq/nPnc7Q7Jd+fpw0iN4+1PoDcKtrfDr6K4yEbW+IFgMr/jXp9HLWfh0f9N46DdGz+Jge3RyU/n8eatR",
-	"+zo6bZ5MWletT63dHhF7B4cPd1djx98fnuydbZx0W9sP/u1zv348Or0+2zj9vDuFdxsjh3it6Hfn6HgM",
-	"/dsnt90c94jjOx/wp+OL3d2z3Xar1TjA+/voaNNno4OjrfCWfzo9O6tV75vOw4i83G8ftHxFQ+3DyfZB",
-	"e/Lc6ZHdSefw4BM9brd4e3f3vt2a7LePhvvtg0ar1R4+f0p6fzi/b1W2du+DoTftth7uj0ZP05NRj1Q+",
-	"DDZfLwe34/5Rrbr/tf7c2bo42D2vktPPH3ZvNvxw3P3w9Trs1u9O2W7drx+GnghOrvaPT06F39zf65EN",
-	"dvj6uUWvN6bBzn1n+7S155612xfTp9YTp3c321v3N2H7Q6VPntg1uqqdXl20B9PL9tbm3c52E1/c9ojf",
-	"7H7o8097k6127ZR5buuscbYX0unDRheLQ/jQOPl0eis+XO/DjQbm993D9tMr3bq8376tH188N6s9Mvx6",
-	"N9yunVf6fm3/tbt1vV2/29/rb3jjp0bHG78MO19P0HBj4/Xz/YvP7rsPx8ftwfh18ME7726GL8OjHnl6",
-	"qRxXp95D7RT3D9nmYas1vdi5uWOth+6ke1bdd56utyf7bfLy3N0Lp1/9u8nt+Hz3c7jfud2+QPX7HjnD",
-	"NxuD4/Nt7m7tBfzgpXn24bNLzsin7ocj9nR9ebJX9++Y13LJ/vXIvb/dfnp4Du5Ge1Ner+zsoIseGT1X",
-	"2SmZVp/OJ88wHFTwzfaFs/l5fPb8dHp1djxs3uzcnkyPw7s78Tr5TJ7Ozpt3Vwe7X08a/IH6Z2c9MhD9",
-	"66OND81p/+qu0qqPd/vw5equJrZuXs+fnFf03H3Yx/D0fOe0cuQctztXG58Otje3a3tuy9s/2HF75Lk2",
-	"/ITvu59aEB5Xj49br0fjq+er49PT4Unt/tM9Pjq/ndZE/Xh6MOAM+s1Jt313MRhdos70dPf64bhHxiw4",
-	"9y77aMCvd5pb14Pa7nknHL4+sHbz9mWve/L8MLwabdwejrudT6Q9fX3+NN3cv6l9vQzwXXNH8qjRZefz",
-	"Azuhzkn95LS7U8Gvx5+urzzxdNb6R4/843JwvdUj6nbZP99bdPXklHyhDD1y7tkv6V8FzpYVOFvyIKST",
-	"1fBUJlswwJ6OBEgcd1MyRY7MstiV9hz6crwg8ajlJm1xSiqCXAo0HCiVK53ROIBM9MjvUfDJe2sljrkw",
-	"8KiiJF2z2syPfUnLPpaBnLeyFdMedrtHJ1owXEOvtoqSLdeN3UuiV5eQI/aOAxiKEWX4FblKn5nPk8f5",
-	"qITcWrO5sQNarVarXT9/he0N72Gvs3F+vd+Uv3Va3Tssni+OGjfbW419l+/ekKno1/uT8dVweOR98vr3",
-	"n70tslEd7+SUtLSm27vhughrpP7ooALOR2ojA8qydkrXx2R5kC5X7mTGdXge6EjSozEC8j8vaPctJSTy",
-	"iyu0JHorLc3QYJx6m6v9SdiVQVe/D3DwX2ASPxyosEDVvAj6oVDJGAZJymg+E8O5gmf7z61XkfIcX1au",
-	"YvZs1y9aoZ85kBvDFRPNpOPs63vnB2tWr4ieTr6rbMXKOdJ+QK4z0J9GybDskQ9RHTvXfoGSju6y8UOS",
-	"oC1dDRkoL3m+9mJ8yJ9XXYtsu3QlOi3culCxXg1pc9m8oXCFClF6hLQtTN+FDmLCXaOzbL7ImpZjJpyn",
-	"OZ3h3Tx4LymslCky9EaL49ww+auf3ej8Y1Ao6KOp2Qxn3iwXX/Gzp2AfWmP64zT009ZZi3ystq5Ltq2x",
-	"hPR7wwx/MHW4Zm4X5Y8oScAx5SlcwAUKeOS4ocPFrbGEsdPxjOum/BnAeODVhpvhkK7Opqen+LK46Fb2",
-	"xaTQnSlYNnMIjsBjnXTdyFeZhDscOQyJkvyUEtpVZAZlVnJXEadWE+i8BXQV26Y2kuaI4/G7Y5SVMSWF",
-	"d/bSrFQlg0gTUylyDZPXm3IU7lMqZkSAZANmHSXl+1raWCXwLXIWygyUl+k6avyo3cgeA0Zfpot8LVQ+",
-	"OZOPVjU2MWq6dGIqDHqmFlrHTDTjeWSHPmVDSFKPBenIjEa1XsvLWO2MHq1ulzPLj+2/SkGaGq8R4YxU",
-	"UdWFO1HnGe0lx6WSjZzl+k+8pIEHh1HSRDZydBpPPXdq4ijPIfQ4NRUwDYrxmeUsPfJsyvuUWpLC0rK8",
-	"uFIks0rgiilQkpPpYx6D4l3qjGRxhRMNf/2Anw+QlU4iXpPycPvuNb0ZJ2a4aga9i7O8MHNCKcaWomyb",
-	"vHqdKvK4RphF1G1JoAURgV7VgqAIIgIQNcpYEqplQpkYlaCPGHZgOaDUKxMRlCkbFoqFjUWf1zI9pAtd",
-	"5juuRa2KkWypGPbNdTuj+950K/tQnjZZLWRt/umeTFfwM2jddffbtdk8Rkv7dOvrdZnLOrt0jteQofW6",
-	"xOXs1+tmiWpa1mUuNGBZhzwPi29f7Hw5sq4N8VjyorkkTyq7KuaAj2jouYAh5YfbVxWDLwZKy58/JJ0z",
-	"S4UACZWkx3L2ZaDG9REkxuUfeh6wNAQa83iPQIb0taCtZ3PzwrituUPGmCrnR/36LBfcIyz0kK4fzNCA",
-	"MlQEE6RjeMzVpLAZqBxCcnd9BOAERvU2sACYk3eiRwLKOe7raBMfvyiPc19dreoZ3JwHEHSobH6SW8a0",
-	"k+elkYqVX83VKA2uODfMyiS1Yo/ZfJJrENSKPWboacVes0Ev65LGit3m4wiVK9P66X/iBEKrpNczOcx0",
-	"fj17ep9i5NAWoc2XGQRbM+EPCwnJy+qTSak2h7drb+g7s9/Z/fpmhvySe3Xlp2co83qc0yDKvZDOT0Ad",
-	"XDYsRmdyLpgi+XagGcP0OnlLGQ2DrK0zuajVx5U0ozlNcyVL/Dk7PNlnZ/f4w9nZzSQ8gletY//qlHZe",
-	"rwa1r3s1d6/5Wt29fqlsviwKC0xHnSK2YddgjH47n2Mmcm3XDQAXkKlwEDECv23+VgS/NX9TcVa/1fq/",
-	"xYXz+wjIA1FxQj0CCUDEYdNAKGurHqkMLiQfnuBUvf0+AkJlanV1LZ6kTlOPxP2yely+Zr6qK3PahXOO",
-	"kkz45aMOv1zd6J0Ne7VgxPqBo3btRs+QrvT7uz1UZ4gIYgq0eACoj4VA7vvcyLxfZWZyysx4Y395Pj7D",
-	"AGeRx4Z9KTywVHBSZKYC6EKCRbZuZQ0c4l0r2qt6dVhMuxKJNNLuIsg08+urfx1E9HN8dy31PtVSauu6",
-	"XTzqSIig8O2bstfo9HGzwe46y7qg5k1XVTvQwS8mS1y5kAnkMDa7VgCdEQI1lehCWQTit//JZFKG6rN6",
-	"cDd9eeW0094/7+6XauVqeSR8T+tdQgHjorurpjf5CBlQ5QQADHDKs/xjoVbQ5RyJ/PCxUC9XyxsFXXJM",
-	"ganieJQgXvkDu98U+7cVvDg0mKqvfVX6Api7WiJWYkfVmZLUU5IKY1WJLY38rqu4pt6gKVOYneTpVDmr",
-	"JeYrKQG5OjFkXDKy4+qltOWKu5EEEkAGfSSUtvzPOV6+F2fbjRYvKBiqkhuYKGYqRpFD/kcd75igtbbq",
-	"aMaUvVY2anXUaG5uldD2Tr+0UXPrJdhobpYatc3NZrPRqFar1eVRf1IjYuYdTx1GrVpNRTWbLDRxyrQn",
-	"U3EzWdBCiTYFJYXOWcikYSJRpPEDpzZZMecn7RCtNxnMANjVU2/8/Klboaqm94yUmwPWC9Gz13/+7Dck",
-	"8VSQGBggpsozx7itV9L4M1byTOiEzBxB8884/RuCXgIdO6syrQLqOCGTlJZm4YqKI+b9zy+SRuIYNnUZ",
-	"p5mQYl4xPqlxKtEfquQYt4Xb61z7EBA0iboWQUDl1nEU5MtNXR/1UjpGDEbMXfF7Y6VA0BmlK4LHNgs+",
-	"z7guKReGVxsmg7jYpe70x1G8Hj3y+viWvaMlM/s2x282fvTsHdd29OajSi6t5Gnk/tuYDovg84vz/OI8",
-	"K3MewzRsnIZXlgpO0dtl1ENZ/6ZRhvBYfioaxqJCuJVWL6V6OJZfKAMD5b1jl4n0wKe66O7PEypS01jg",
-	"PLvNXzT2i8bWvN3nUShDaZGa4iJJMDYXN/l7onyo21pqZlINEBI1XBQg4iIiwBPtW+5pPUJyU6+gX0Rz",
-	"CQrMuv7ztQu9ZQ2sfC0jgowGyy914xdD+lsxpFluogIev8tAsoZNJALZEmNIuhzFeuzq/5pBJAOpBczq",
-	"F5f6xaX+1kYRq44iJSdt7E1bRiw2CtlkLfEnxaz+QlzkJ9hXUpBRA//ZFpbU/HH4hwWlVKEvNEkqkvZV",
-	"PSxdETLH7iLQi6iox8fsemZBuzL3avyoCWy0+S2jmUuwZGpxLyAAl06I1LBzNfY900BhNYjyLWvCGmCC",
-	"+Sh1iy+4kKNx1tcgko5/uws5U9Qlc8zxPH1MoC37jR2NkxpM5v1SO4nF8P91R/+6o/8emkSarcRcRTs+",
-	"Jtg8z688k/z/LVrHHLsCC3UOLBJVo2jsJpwqEswECsI+DYXJEsNDTyy0R8rl/1JKlhtUJZxyeKBEATv/",
-	"UwVkCdUuI07oQWYKO4LfxYiGw5FxUz3uXpy/L//HXfwS/WPgLCYjHxI8QFwsp6W45QrkdIVEyAhXaayi",
-	"fmox6l3QiF/EkIqSR02J27ixQxVhxTVkzPFFJX6hAGkXEVOxVSeFgKRi/i5Fw5WbC0jxLAbBL3pcSo8J",
-	"sPIEk/RxryqY/M1pLUseKxBdKlXqYpqLc+dbpewekRBHL/LGTF9ETJEfcoE26fPI683QWuyOpHzdFlFG",
-	"tM5fhLGcMCJY/RLYfwns/8kC+xxvWs7veJ/6+QJGJCxAoEOSsjWy+RK5oUdmmkMWt1HltJOK3rlPBLsX",
-	"Z2te/nJNOo5JszkQjfF/5KlA7TaH06mP/9eu/2TTs6TgooBTb4wqSeHYhWbmPdN+N1Vn9mcYbaN51vKK",
-	"q/6E6fPttVGbJC+PStD2Z1+V0Qn+cpCbvzD/Pg/W5gxVZmWmA0FjijTONOlEU+n7au7i2Es1/NmeZXNz",
-	"2Qgl1QZkMnP9zQQL45CkbHdxtWnXurspcKMMW3NnV/lDV6D+tuohLrv905H7M+nILDd+XP56lVtf5YHb",
-	"KW/awndml3GgkjqC/jStwIGz0BM48JDOIMGjaKokAXi0yq8hUi8MkWAix3g0AUqWpf3TVI6HE75e6cf8",
-	"Zadzt7194ZkMcDlLj1PymUSca+3gy59Ez3GOuCUk7Sa55P4UDSUzuc4UGJK/nZZioGaksjgRaYZ+Fe9Q",
-	"kyxk+GqphtPPMArbDpMmFVVeNy+OPdVOh3b+TMRL9mATNWL/UgOMXzLOv8cooBH+72cSgDECyTs8zsAR",
-	"YVNCZssDKiGJixBGd65eWVLNUN6Ark2l19tc2TsHmebfpbbX/2QlPPco1QeQ/u0XFf+i4nWoGM1jkKTc",
-	"OEw6/4a8ME2+E+9nguLnN2qWongBwATIIYyN7+9oRV24HQl6ndi5ks5dnG87ymZC/kmGI3sq7T/ZfJST",
-	"89lyWLoliFai8yhE9qSMYP0nmpR4tKhfBqW/qUGpGydcN0iE3MwbLCUpkSiTrl0vKE5kOCednEFMwO8m",
-	"cTKm5L3JZziXHAMGuKwq2Y7wQOeUhQGuKK2+pPwfECsZWzSrjGuFecW8K+AQk+GiCbiAQ/Sd0yjYEgFc",
-	"6kOVyl9Ps2ycL9/+/wAAAP//Md6BgTIWAQA=",
+	"H4sIAAAAAAAC/+y9B3MbObYo/FdQfPOVZ56ZRGVVbd1LBcuyokXJaenSgt0gCakbaANoUfQ8//evkDqi",
+	"GWTZM97Rrbo7Fhvh4AA4OPn8WfNoGFGCiOC1nT9rEWQwRAIx89cIyf/6iHsMRwJTUtupXcARApj46KFW",
+	"r6EHGEYByjW/h0GMaju1ldq3b/Ualn2+xIhNa/UagaH8olrWa9wboxDKLmIayd+5YJiMVDeOvzrmPovD",
+	"AWKADgEWKOQAE4CgNwZmwCw0doAEmna7Eh7VdhY83+xHNXT3fW8voLG/x5CPiMAwUD9D38cSThhcMBoh",
+	"JrCEZAgDjuq1KPPTn7VB7N0hUV7frvodiDECjAYIeJCACcMCAUHr6mccKvRzEEcBhT7y5a8MgQEaUoYA",
+	"FvIbDiPKBPJTlNR2auG0oTo3dE9eqxeXKVsLxAgMbrBfBu7AfARH+4ChLzFmyAeDqQJLsJgLENEAe1O5",
+	"PXYFrknk7zeQkfIMV2MEjrqneu1yCI7YPfYQgJzHIeJAULNuAIkPGBphLhBLEdPskyPB89AQhHzVEwYB",
+	"neSGFdSMDLBo9kkOXZCRHTjhOxiGOzsrndW19Y3Nre32SmdHQtfSqBzEOPARK69SLtOgqLbz73TJdbv1",
+	"n5MudHCLPCER033fO9jr7AWUoD15LTla8lhJhFDiOMD1Gh9Dhm4mWIxvoOfR2Fz4ZL3/rmXXKMFTN8w5",
+	"lvkBMgan5aVqGKrXd6028Fzt+bL3xktvnPOIHu3Lw2fPBfKBJ+8pyHSzh1MgAonIHCgxRn2ib5fEUh1g",
+	"wgWCvu3gGMMco+LJWemsIonJBtraHjRWOv5qA66tbzTWOhsb6+tra+12u12r14aUhVDUdmpxjH3nPUl2",
+	"Mx0bxY0J4qKxUqv/zD2u1ziBER9TcaNJ5p95wmK/zr8Iek1uWOedmZ6AItZPTe5UwBDnIYIhbrS9rdX2",
+	"5vbq5ub6+va6vzZ4AhQXFiPnrc858L3V5/O+4HmP4kGAPb3IIYwDkSClsOgh4EgtRX0Gv0vYTBegnvs/",
+	"6gCCgJJRHdDBMOYeFMgH15cnfYI5YEjEjCC/CeRbgR4izKAcGoR4NBZggACnlKhnBRIwpAxQ+cYaxPWJ",
+	"gGyEBG/2SZ+ksAgWIzktH1Mm36TryxOQmUw+WH2C8xNirvEKQ/nEqank39npQDpbirMBpQGC5PtP8GJn",
+	"t+rexSxwM2/ZKWQj5/jMG2OBPBEzdESGdO7NyB+CbHcQIgF9KCAYMhpqToCDAA8YVFxeHmr1+UbCM+M2",
+	"/ln7jaFhbaf2f1oph9wyPGDrSA5xNY004N+KsJ3CSLGo6nLJiYAkmooHEWOEGfCRgDjgNQdaLHmdsVrV",
+	"JHsBH7Y2bjbW5m626ufcitjH4oSOFDqC4HxY2/n3bAScYC57Fg9E8pwk/5g1iJr2EnmU+XO5Cj1gGfjP",
+	"FnwzTvlt8ETpfkSUC8tgOegQjLCbNe1eHIHJGHtjMIEceDAIkF/XJBdGuA4mKPAZoAxMKLtTXGE6p23l",
+	"mtAehxmn0dEnC10vDkPIUs4bfYkRF3WAmqOm+kU/FIauK2roFRGQnocQiTH18zi7OO9duWCXQCpi5n6f",
+	"1BMyxIhn4ZJim/wzoKPi2+KcIhYedd2K92OkKKVaFgwCwGPPQ8iXu6L+ybncjSHEQczyV8Z8dj5DUIzz",
+	"a2/BCOcZ/obBHmvdd1pe9VGKGCYejmDgPlDqCLHMNvFYbYIWcCXBuEPENS5PKHJ+0NdXVxdAf0zPAo8o",
+	"yYKHiUAjxNSdwxqvydvsQ4Ea6td51MQ00kc6XWfd3rjkGBmMJkCnG+qkRV9jhr5TuvYCjIhwHshuFAXY",
+	"06/v0X7h8IHsMtLt3/S3hhtoc9DoDFdhY81b8RvbcB012oMVr+OvorXhOnRtkgGDI4+5ZP2e+r0SBMmd",
+	"vOCpoC1oTt7tkyyDJqVhgu4RS5mbPpm7hSmeKnfimXldkHkdTyPEbu5vRogYgphjZGvvJDOWX//emFKu",
+	"9RzvToHiKcBrOcw7kI5SBz4eDhFDRIAhgpIB4IASoHYHQPn/9xAHcBCgPvFRhIiPyUi2EGPHcGb5JA7l",
+	"AVBAvevUPjsWpNkkNztyJhlWg9dED6UVMOBIgFBqXwYIxAR/iZGl9iN8j4gkRzRmHgIjRuOoqThoOYnk",
+	"hWmIhXyaFBeXe8cgYJD4NASUIDCAHPlyhRBcXx/tA8z7xKwQ+cX9tVov154F1MvsVHaBJ+aLXWTE6D2W",
+	"i7Tg3yjw62CilG+pYo6PaRz4YJDBS1ZTpeB7LbVQFASYC6mTAhYMvtMnYyEivtNq+dTjzRB7jHI6FE2P",
+	"hi1EGjFveQFuQbn3LSM2/889RpN/qZ8aXoAbARSIi/8Dv1q5+kZOdJNM8kKhXD+/XiKGECoAj5An32q/",
+	"btSIPvJjL7chFXgoIl1KHyiWxMAtdGf7zj5d+eOyALqLoFzR2IPk0gxzqGZ0PajxIAFhBrHKNnsEMGto",
+	"3d8adLwGHHTWGmtrK6uN7ba33thY6ay2N9BWext1XNBp+jcDrgyRXAgqcwSHmPhqr/UN1TTlgjIBg0XO",
+	"oj2HAt+jho8Z8gRl09YwJj4MEREw4KWvjTGdNARtyKkbGuQCkta9TTRcH2w0VrzVYWPNh+0G3Oh0Gu1B",
+	"e6PdWd32N/3N+dxJgrHy3pZO4JzXr0r8zVPIRUhOAcjMAC4QdoMYSXZALPvuUiIgJsaIU3hz7DcrJQsK",
+	"UDiQ5Jto8VQeChgAyMQQeqJWX0yWS8Z16Q69mAsa4q8w4SJmDZUsey/frSj0uIQizAWjbl5bfcODWP6k",
+	"OAmOEoWL4eCb4GgIAjQUAIWR0IaNMeWiT/TAYIKDQN0kXr7bQ+RTBhur2067CpEPtH8TUj8O0OIi8qlq",
+	"78KpOrncZZ3z7uS119/lQgdIcUdKWl10O80omlw6Zs+so8BfEwADbHRZkR6F1wFD6nT46ucB9O4mkPlc",
+	"4R0KOMABFtM+WRI6F2D2NjqkOg1LJca+F1cuaO4R407+ogs4CiW7bloAogybebGjudncbD9eq1N1j5Yk",
+	"JtBDTMy//9092Sw3lb6Rmu5jF+b3048S+VozYR+jhAzhZeiQHXLq2g4f87v5A/A71ZYM5zY9eyVbDn06",
+	"r+Wr/XPVEjvvzCscPB0Ckl2Xo7qQoICYcoFCB9uLuRJI0zYglCxkRDERGRAfBYyZ1AmSi5IdKJoJXh1d",
+	"9EBIfeRUfw8xQxMYBEtAYjpYGlqNhZSELrfqSqop3xK3QLVHyRCPlGxnHx2j5S3LZSOC7QM4U0dt28k+",
+	"mqZpPZ2PlKJtJtud7QB0hzrwYsYQEcEUUBJM5SM4jIPkDUX+CDU4DqNAyRANMwRiSgNeeCxbPrpvcd+p",
+	"Nkk6zl1h0vBbvXaHGEFzj8GxbmVkvwDNa3+iW2k9J+EejBY+aOcRIr297oV+fJhQm4HJ6Ead5ZxuAMaC",
+	"NoL7sKQh6KEAeQKMjduCpF/Aaj00J5KMLM1ZL+xAL/R3yeIwOAExCRDnfaI9RSBDSoymDISUodwNx8Qo",
+	"uD3IlTNJMs7Ju9MmeKHGhsEETnmfxBxx+XsdICnZT8aIgHQKQgF6EAxmx2+CFwxOXgDVU0KWgM/7xDVI",
+	"BZx5LQaDk1q9pvGXoPKzU/CMKMdVr9Fl5qu89NbvBrSQ8FrTOGyq/k2/lafQRu9xRgWSKIbKfYdbJAjF",
+	"LAIogFIdA4FDo4NajoIm0DlfNjbm4byhLl/3TkvvM4vm97sodzPKtLng92w72YeP79C0mtxyPgZ3aMoX",
+	"RU2v9/oYObEhcfyVkrm3+8q2+1avxRyxatjk1+95/665SzL6NotrU++3g3HUwpR6oufxDPqc5fk5aSZ1",
+	"i4USckv/1eiQgyiAcmT0IFyUuuL9VO9fcSQIRtiXdxkaVU7JiskolbNQgowBsjjfn0UDRr320BjRRvrr",
+	"xpq2CKYkNsfoIxZiziW1AXrQ5PFSUGICqCdgAIyyNwtce2NtbZaxqDATFGOQiNNBfp2KnIRT83tpRPdB",
+	"PJ8Q7feYx2lscSp7/UCUFmQOterP805vymXmj2CIiXXOnHV5bDO1n5b0Fwxz93C+712mcz2Zew7wKVO5",
+	"hEuC7SZtq5qd0/SyeAUjagQqN61Rn8HvUn6mTEjF9wjxP5QaOWJUUI8GihRJjiS72/+udTo7wotq9dpW",
+	"2/wDhzBS/1zO12tB6m4XnKXykp4urt+wI3xSvZYjkAmDtfOng8ZxwRAMncu95ZTcSIs7Vb/MAdFO86Z3",
+	"fnaVdJJXX7mXOpWyF7GQtzNRqFtX1KN9S6jlYwwkjeZ1wCWhgAJAMtWMN/EQz9rFBO0TeW5HY8ETzk9y",
+	"OiEUWNqQp/LEEaR09YbsyJUEWA5lJzcze5RwGhgeZL5PFqOS2phVlj4vjcUMBos0JZ1p5uXMMEKljZeW",
+	"oZgF+fOXkgur0PZ80mTIH0OtzPb049fyMRctNkbBVmurpX1qWnJEyluUt3LYYk4/juI9Mlq/DOZykmuA",
+	"KrVVo2jkjZF35+46ikaKUcquci4wFTsYIgEDTO7cmAoxY5TxplZuRozK7WhSNmrZfv/DUET/ZZWfnX7c",
+	"bnc2IPPG/0q8kuahTU8SYC7KQCQwyM9NDxFBuZr/fxgKEOToX1sNfdUzM0P5vxtr+hcF3y7k6Ly3CCxK",
+	"sXkzpmKIH9w6Ky43lQPVEjIspvI9FijDTyi3P3tKqxz3qjWVDFM5bG2n9DobGeZm9vHgPLhHDA+nrs9F",
+	"E8Sc23ZtuJElNIbzlPQj7FfxjNi3mvmCPd/Kyi4flipNeFdbWOkQpMBndDrQV24EmnMSNMvSp0dQNV9Z",
+	"5K6PnR5KV2aCFxzIBiAxg7mGdEpHUirSjrFSOMr7MPFxA/md9fWVbdDtdrt7q2df4d5K8Gn/aOXs6mBd",
+	"/nZ0xg6PD9jpR/zy9PR6Er+Gl9034eUJPfp6Oex82e/4++tf27tXD62NBxdMZeuWXM6KmxXmfGJ88EoO",
+	"u8p4bBoALiBTL5kYg982fquD39Z/q0s+9rfO4LdE6yD9cAWV7x/kfQIJQMRj00i+cXakJjgXY8QmOKOs",
+	"GCAgjIubYpFTEaZPkn59t1fVGAWByxlghAlQH83xdHWOXcdaXp/HnOqFdfyUCs/xDkpVww1Dym/EpevT",
+	"Dj0wAF7eHgiSPkZtofWRary0bbNP3ks9jXIaQEIHJsmXMtMdcz2CMvjI7pI8Qi59JIOi6exLDKdNTFua",
+	"vDcGclG5P7Tb3Y4m9E4DG+b0JoJTaa79znUPlTxlxsq0s4ZSyYppl8re+QueaSAPq9IEKdwkeCmPJP1V",
+	"EqcdqRkyGs+WXKtWEIFzqWG9hwE2GKRUyNaNZJQG5tS6QElsLI3TWdjMYfBJxiz5ndsJnKdasCHvxYN7",
+	"GsQhKh/vvDhY8L1OviXCPbcjuW89gVWUm2Q04skgdaMh9dEQE6OvTzxpfpeS8R/W+4rJ/aye2nXJc7Ju",
+	"JW7eVSFmack6gkzc6ElcGEj0s9qN/VC6W0m0Hl5cpd94E7yiDOyf9zK/1TUfNMRIUg5IrNlc3iNuQhx/",
+	"74AxegA+HmHxR2EuZYvPERgFgVv6kQMmXmGybYpEQFnuGqZ3xeUDpDdrcfm1cFJdukiDW6usHsgetc/z",
+	"DoP6mgPJdRicVtfluLUIhTeJhTejS2g0GrsHh0dnYO/g8uro1dFe9+qg0Wj0++T06Givvb+31x3gUXdy",
+	"tNsdHV0fNZvNfp80Go2Ds/1Cl++IJUyBc64+Eyi5S33FPKWqrpmRB+VAS6U3zP5yaf2mFw6KOFeQXSak",
+	"zREfUXCqfxrn00VY+mR1qTPT4xc1q33OZcpOW3bmfiKEwsn8KBNHsPa3ek35rs3t63RG/1avmSCKGygW",
+	"9Z6v10beXOPh4d6Fa7YyEx4xKl0zJbE0Xp7VMkpKeyS2NBx2/Z8XfJQMOcosu/KI5aA/MUL9T4wqciHw",
+	"0aFFxcEutUPykorhgwfoabO5YifghNfByIuUSlftQxqjLoUe5DTc/FVH/Wcf20VFH0Om91GABKpy0Rwr",
+	"qB3PToU27A4Tf35IoyLCqmldzzALPjwcPiXFK/tSlrm2gdXegHxry7mqgIKFPTuzQ+yNpTXCxeeomNU5",
+	"HsrGzVL9F2rF9kI66Izr3gKOeArjshuOUIDJAv1MQ9ux6CYw0zhu205178JJSbCSWUVhgpJ7bBbyCqqk",
+	"8Xjk/xfxJnpJf8V7oWe2HMv3PBZmDdS7Gxrj/VNtTpAZc46/km5XhDsZYA7ooydlz7SDu1LeOK0VBoSS",
+	"0RmxIfTQn99chOaO3uK5bl70Fqu1uD3uDUAzUXEKCR4iLp4UH2F20O9HRmFx6eizV2aC5Z9yYZQLhtCN",
+	"R8MQC+cT8PsY8vEf9iWQOyCAaV5/hLe21u1j4gWx0iyeHby77C7psZ0gwnHKWMroLUA8LFv47dssxC/K",
+	"PBKq2mS3tvREJE987fO3UnKpbNzIQu5JcsVJL+cjnihOk2bSmK1iUj2p0sckY9Jugiup+8FcKWZyqpo+",
+	"UR5+ChiuLGaMhgBmhr3HEORirRe0VA+sKnzmilWjpUNSHJEomWiS/LspDciNrVplROWCJ0uFgybnqtB5",
+	"8detOMxjqalxmV32EUoPf2/3/PRp3xa7/DL/K+cCPvXiUI6pFJYqS5zWlmvak3hD6MifWn3JAdOIVuP/",
+	"eZDOEPNYqSXHynAlgDRUCyAmVA3E60oQtINo2woi95hRIsdXYmGmRZ9AT8TGZiG/Wx9tNW+tvsRBkNNX",
+	"Kykfz/L0xpA9Kb/jjSEx7tzpzaJs1Fhb33CG2j9CHxMl7oCLsohylakTYQlRFujc0IsoTTLD/4UMsNrD",
+	"pzoMFznk5m8UkyZQlYyPgxESQj4DysSiWPB6ms1HXgR1YwoCpLQG+8CnEyJ1jbI7FjzxelUZWQjKWP2D",
+	"qZ1OftHtCy7kCMpHRXV0uo5n17boO155pAsEJkluoHKi5SRlaSKv1Re7Az/nPM9QdTyFatklP6TJTuYT",
+	"uESKy3ZFS76XVbKgfi4XhEe+mulAi/XJIfKdSlxa3BQzUH6Bcy6kanPAGGUOX8kk91BRK5ZzOoLc6c3j",
+	"UoyZxiUA9Hoy6ug0B0+aocfkr6h9zh76Gcl60rjj0spmpK4ohf+aQdJEB5U5I3TguCt6xDoQCFoY1HoO",
+	"5C6ydu9k06b5SXkiqll3BBy5ZhYBv0n9usr++4wG4OqkB1QbPDRZbrKTqhR18zzCzALd99ws6XuywszY",
+	"lmQ/khRVOSeNgocD5Yp1cqIKjhx0Fo6WnEGn0nAqtebhJkMLl/CcwyPzrhR9DuXv9hG0Yn4pAUu6GOt/",
+	"YM6Y29xtsvcVfNjf7p+5M7tU+JeEU6PJb5n92JmBtWJewLpdsvO0KaFrAXffv4m3r/LIlK6Zbq9M/dm6",
+	"b7rbfJfDsHEffPYI/uEewU/mzMt5cPO9rrou+0zlW5hB5YwQY4ImbjlYJVdNmPHsxHUQE44EwEOdO4i8",
+	"UJ47VszWGU3TRHkzVJw08J948gWmXdjo+BemUsindXmqrCw3s4NiD1QIb7ZNLrNHJsQFk/ymKC0gksJa",
+	"rnc2hYrkjHwUcRrcI5MmSzCM7lEyfhN0M1JcXYUw8/RzMhqH9ybTlk19b9mw/5Sid/+TemH3iXkp0xdu",
+	"CZvo7JDcQuaLv2v2iqfPTPOIfBgLxootktBi4aHmp6OYOcLRRW+Z/BM20K10q6uiF/5WSSiyua2ec1P8",
+	"srkp8ikpUgtbxu0zolyMmPY8WJyTfM5v8bfIb5EGQPz8J11du4Xf9T6xV/O8B7DgKBiqNP5TPRihKn9o",
+	"GiSRN5Yov3rKpBp4alhLieiscVnFSnuI8z8UzHbiG46EdUo3Y5aWgznAI0KZTfG2ELn9L0jPkcmSOLdf",
+	"tu13JNxY/PFfPIGG5GtK8o8OyF+AJdJvoGNkIzzol7NmmKe0Q2lGjsSNEUjvEcvRQ6ePaM+WfUr6gP2z",
+	"V+AeMixvQB2IaWIn0Zm5BE1DzD3bT96By9cHJ86g0wp0XQTxCJOqhczQSTjHM/f+cUYSmKlzkJdXq2oc",
+	"1B/p7vB4A77+ecECEdzlUPjdFKUgrqYYKKyrnkfo59z+pOEN+T34qb43ezQMKZm7wgQml1CeSk3V6XIS",
+	"ke8xOXMQ4TFDNxFktk7e7Lt8oNoDmwsK6I4gIxEC9ICzOtJscP8CSXXS1ejMOklCHZNgB/t/m8w6Kagz",
+	"0+tsrq8/Lr1ONqK6lGPHx+yRKXYKGE7S62gExz8KwYvm2dk3uoCnCP/DiS5rwQtsusyKdito72TkHrVB",
+	"4EmUn85Zn2F0R5GiYHSB+LgM4BX4Sajgfqa6yqPt0ktEe0ijZ4BEtioRZdmnOpeJeJGKRVki/uiaRaV6",
+	"S1Vli2Cx1tBihYs86qMqvYL+kt6t3BOVXqMZ6vcogELSDadzotZFAdsGmCBVyT+lWTpyM9mmOyjYXjwm",
+	"+GyRRSj2YbvpdNGgyoLr9op5FQeBlIZMg8z7GmJCk0zBubkqp1HeuCb2pWBLNHXSzntXDGXj3gUKJVZQ",
+	"aTGt7db/x1tShVKRIEiG9DiItP5QTkp2iXzwGgpwQARiEcNS+MYkfnAHouc56LzFXX1LEKYkT0yUZKsz",
+	"2OZx9dhQo88FepK4aFVcwqrfK7JNTtM8CSpiWh0suiNv4Y72gzXBd0+ZL3Luxc/c+Sz3moucTu++c7js",
+	"DckMl52lYrjEVeXJ3AkN1zKj8pajlJXjNC7mBqOmS5oXBnYfMLXkv8D5T6P6e7z+pC5/yTx5R/vnRnEL",
+	"KBlQyOZlzPPxTTgc3Wh0KwHsJoTejWTYK/YVx+Qmigc3d2h6IyMP5rfChCPPiJ2zWzJKRRozX2obQhJL",
+	"SSJWwEpVDGI3laUSS4dfWRaWQ2hPKwSSTNmAIxFHJSxmJPl58gtUKa4yyoZZWbidq/j7Zy/9gVLdHI+r",
+	"58ypz5lTXRdmRsLUG3c9fPlrdm3mtmICBlORZ4A6K2uba1urG2tbeUhjA+oTZ1m9qUyzmq5UyoWOkp1D",
+	"PiMjTWaVOk1MbwKjjJ1FFx8bQ2V5MHVcUtjyhhX0IOTRfBhKRN0P1cHlExg5jSsBHKDATfC/M5+t42o8",
+	"J+WpymGhaPp8/YA9Q+4D6LLFP+f6XTLX77cZqO1lRn0UVi1YcvGab5FnxtfJNx38Ic+wNi5EZ8dLR8ng",
+	"U6CAILEc7hBZYlZEypMOhdw4IqIlcyRV4v0TJUsjfRfLNCRJskyChCxfDLQfONdmJmm0AyraU0LlCSAY",
+	"HEpdllRfScM75Sjpkbv0XAf4JLyZHMnF2bk1Llm1kexZB7hUoctOq6gQjKJgqhImZ4vCp5NW+PPPuKJ2",
+	"eMvwyLGqowWlh+mqp/uof6N/t/RvIeR3+pfP/0//ctrd0z/8PxxxJHb0r+rf+vda/TFnwZWNZTkr3CD2",
+	"7lwVa3fV77lytcb5Wz0mtmCGtrjrQfK+5H1CI6MOgHeIg4ghTwJZLstqa+g1dEdeoXqSa3Zn4NbfMnUQ",
+	"Mc/6BhYnM2M5T4heqi2n6X6Ti/jI4giHEWKcEij0ddJgZGI4+yQ7hMkFJMNOzS9dM+iVLAcN9hiCgjLA",
+	"qCSKJI1lS9aTC5/+33R1TQzD5sjMYyCVPvFzmbsiCnK4/+w+g98TI1J1AK0CFhItaklBpHfVPdvvXu6D",
+	"ns6/CbwAcg70WW0W99n80TAzuENJ/zlVi2cV+U0yYBaipZJbJLkUFbLoA2nziAUCB2SESf5Y6/WqgQp1",
+	"gSUOjOR/uHcBzJGy+TVNAtm8p5Aay2Az9fZN2dLEdckWDO6TF7ZUewNGuKFprESH+hd6YeVZM53NeJtC",
+	"vUxBYXkqXLr9K7NE/T1TojVZk2Whs+7LGfzKZ9bgU7vBW1RCkyRWjm6zkDZBDyGQhL/I49kcUToyQWYm",
+	"T60q69qyfbipxJwvA6y49jgQuGEgt83loeeICyuqmweP/K7/kdxF82LYbn9INHuSWSB5YaGIZBS78zND",
+	"hm7ksbGUaMa7bfCi1m3JMs9H2SbIdh1fdTybfaLi+s0hUVg3fviZ+g6JeiGh/kpWemfT94ZQhSejnT4B",
+	"oAFexByxnT9RCHGA/W8vdkBXSqoQBzJmmSHOtZKJoYghrhRbyVyeHAIUlqVFPYO9OngBA+yh/80EFr5o",
+	"mpkNOTEPypIw6KkLL11x7nDaUB55DRhF/wujiEdUuN6cBCSl01oWG2b9tvi0hKuAAl+K204c+DSEmOz8",
+	"qf8rJ1TXE/RiLBDQv4LfI4ZDyKZ/lCcPAj2hTR5vaDUUpm8RI+nVeyFlmBcFmNy3bvbRtAW7NXGQB1UW",
+	"D+kTi99+QVhUB650Kmr1WuE8LLp5NaPB3CmjWRnwFYKzPz4+BawhqTOZjKcr0FxkKtM+kHuI+JCIxoBB",
+	"7DdW26vrK6tzOafMcPV59Z4PrVJ4CU5pdjEFQ5a02jhVt/9OTTafP5wFFeYbvwsDPj6J41EmYGAJkdV2",
+	"m6N8UVHXPvLn6TbscAe2vQ7s4GJAqVi086ukg1MqK82xdE1v4505z/So2s3C9avsypYAwRkvfMHoPeba",
+	"8x9cX54sFPbrhC6bqOjHO40+1pVTu1bMDbRQzhU/xPWzXkstAcZa1S7ZA41VQC2ynlgD0soE8uq2M3yu",
+	"HFI+KhCEmOAwDvtEp7f3wWCaaecoNLDW2V7b3tjsbG9UmRU0u35Do4VSbeXFxrS7gGyEREV+JjmnTpuk",
+	"+ylZRTGuUZDIC2YEm6lJoNDm8O8TCDiKIIMiae0jLjDRzK56YLHggE6InaIJTs34faITqiIi7By20IX8",
+	"bwKG/WYlO0lP75TujaE+4XGkX/wlgg40rq7UuK6TIvV4iN0oy4WLU1a/K2B0y7RQR0YEymQmttRWSmWK",
+	"OwZXWfWKDgFR2bUGUzMkl6YYOSIMArPysMSeqIC7ujLueogIxP4FQ/4dPEOOIOTueuFCfraERyXGKntC",
+	"mzysN4uViDAJEIDtZgTZsREpkwAbPUq2lIicPi0833TyJRaWKGYqmtkJjvloIbKd9Eb9R4HHKBX/ycAI",
+	"00ItWmlaTkjmxyh/KFQTM6j6JR2wTzK8spaJqpOXgf04yZdCoNwuQId9wmmYpTjKbIUYAiFUIUbJjbJz",
+	"5u5UnxgkNDOWvmTl9jg4TXx8QMMFEsBZd4UXsr06Vy+MlNes1ZfJ5pn0n0HVzMpyADTBXj7csXex/0He",
+	"wJSIZNbOI/9hvsFMrT0LUr1w/B1HML0+FQw4so5cCyc9SvyRIkZHDPH57si23cJJljIQmxRLySOz2AD5",
+	"EgeFzks888VxZtI0m+gpj/KlcirVa5a+1yzQ+t9WmWkSL5XuRcICKb/hJVn15Bpn/KEX8XkOMOQuu0w3",
+	"EIjJh/neeicnrpMpyXBXc9P1D+aa9aT96iYxfN8oP/VFA+8lX33jdqKRuUe1e1aqSdVCqN28AI2gJ1ER",
+	"oyGu1Wvj6YApwZFUpaUzPGCFd4j19c0yeQ7PkJX25urm2spWZy2rMdb8m0s+RA8VVu0ztR3SZibU3iql",
+	"iHYQRpnITBqLKBbuLaqUy11R/xXu5pBQItWKwLYpIzw/X1NH2DqLiiVuH4Vj3TsH6hP4XVFgOYP8LfNq",
+	"SeGaxEEAByVPsKzvSIgqnoDTo9OD3BtQhl5aO02erxb1BBIm883iLu2Z61nygYIh/n7v8orbOdvpP3P5",
+	"nKi5yAeRJIMuEEeSRklnQ4KrY904EobMcDjUJ8n4MCb8nMxYbH5T3KX7ZGeD9Oaebkv5b5JeWfmpcN6z",
+	"biyJBGRH0AJcJXGcC0nCUjwelGQINywZQUD7vueLJRQi0G0SndIHzscyA9bjpYZqJVWqASgV04ET3vB0",
+	"ZP6EN8awwcYxNn9l/slhlPz5Vb/K6r+2r/o3gtFmrlX+Dw4jqZIt/Wh/cNcIlAiW2S+SXOvmL9PE/pAm",
+	"trAFgkZeMvIoRlwkKlP131wHTEU6vv4jHV7+XWzM4CQdjgpnao5avRbg+/xESjsBg4am18ZTJddChopM",
+	"pVlx1HB91t7Yzk/Uk0uNHlBDQNZ4+CpdAnkk5Y70Xw16D2v12oQHFXySPOfHpjRzwamxlOvmEa4dR9n0",
+	"I/nxeezTBqGqwqm/zDz1WkygEIj4iwd5HycJTZZR00WSEXUwdOp3DiAbmfzaRiKUB1pSasSAzqCiCgZI",
+	"NY+UQnKPCKE8FP8aUuahx4VzmQmS8q7p0PpLw0eDeLRYKshjk1P9EUkx02lf6fx5ypOnIZPVzQiPyvfs",
+	"tDvt9nZ7s9mu8mZBLN/DmopldltHYj/58zgeLJISEfK7ouVkrePiITNhcCkcqysLeaKo62qnqtsSZWl8",
+	"nMXK54q9sVVOisYieXlNHlyiqlgUJ1c/123LquGrhGFdlHcB7LjOlA0Nyg9ZUa5Kvp8jVJFyEH+t+CKo",
+	"gIHrUwELalIzhRnPdq5XRgrVaypd0XJOQbPGqMKyjR65sfEFs89Tvnkl3GhJqVd3mmOeukNTFfxUpkw9",
+	"ZJRntgkI4JTG+cCK2CnMBpCMYnf6BusZodNLWaVuonasmygCJlsRBAbIo6Fy0lOW8LosgculgYao78qj",
+	"AXDkUeJDk2M2w8ohcnPda15fvWpsfa9za7agkltBOzPucrmAODuZ4f/nV0uoOzR3slKGKlNuJAQ61AUo",
+	"kqRIgkHCoa6EZz+mGtSsvFeXZyC1w2T1zYl21xVD6SKwK3MLjadEdFYNsnqtiCXHNfTGi9riVGZcHjtU",
+	"r3vmS7JiPZ1VwPbVWDvKI6pfcwUf8THsrG/srHlbg020PvA7aG3or3tbgw5s+4NVtAYH23DQ9tZWPR92",
+	"hnDV2xrALbSJ1gZr3orfQavDNbg+cEGNIlpYYnuWMT0jCEI+XvgxX22iYKXtbh5SgW4SB67leD6Vh9hp",
+	"0ZW8hrTk1oFN6KsylCUpd+1esDSAOZJG4MRuZDbJBbPz1V9vdpqdjUXLY2u0u557neymljlP7pM7krqh",
+	"qgray8QeJWqlQI9pSn2bkKSTd79iLNLcYur5tc6uqP74EB2TXfTJMsubM6uTSaTRGEW3Q0J9dOt8Vo1e",
+	"rfxSq9+rR+x0Fj3YZgYXNs73jr6TcUpGqGKbKuOTF/HdMO4OrqxrAhHhJDNdSWO0hVQFdaiIMpzaIsEQ",
+	"CU/K8dZy2ARHUklg1cr/iVnwn6TEmDa31/tEm1xzSYblYInpQSprKyJBdDyvU58sx0JYJRqEpmod+N1s",
+	"8g5odzbaa4OODzfQ9vrawF9dG2wNtjpwa3UdrcPNTb8z2GgPh/CPuo44HTBIvHEjwHfZ/BvpeCrpRpKT",
+	"Xqpn/uiXc4zkW7hfiGE5s9kC3UyywtnR0PtIIBYq4+tkjAxqtM9tNpMgCCGBI8TA7x4kfoAiLJ2AlZe7",
+	"mMrtS/SUMmYHKuOV1hGm70sT7FHC4xAx4MnDpUpbFFNJQw68AEveNN9mjEifJGcpV1rIHixnrrx6bfGQ",
+	"/WICir9TGeIk52sJqFuujFs4oGr0BXPHvumdn10lneS1oQH2pu4gnDjruiwZYtVW5ucxHFxaM7AOONU3",
+	"Wyb1VEIJ8RDPBlrIEyLfrdFY8IqYU48SgrxM+m+5kgDLoezkSVJ4wqml1/NLEDMqH/+qxCBLYzGDwbLD",
+	"qJ1p1nbmt8GpbawQgOcsphqcejrqLMhmQMVVul+0tHryMf1c9zQjrTwpW2HFEkGtNN0EMsodjAI6GJjo",
+	"ksQKUu8TNGqCFyprMx83/u+LAnUXoTsZUmXqqKQ4p2kxC64jEz85CCC50xFJumBLJtuuHSZLYJvgPQ58",
+	"DzLfCP52OWY1a82VlWZpKavNVfh472Bb0DtN4OYWMsspQaWqTeCwKulPLtFttWRX+hJgD5mcmYsyvTm5",
+	"rvSNx6HUrTi/ud+f3DFYiLGslJRmodxUrY+Kma0Kl3CBTJ/vzPQuxQmdkBGDjx+0uhw9QyG9f1Jg4+iH",
+	"gFrURyoUp/Bn5s3ha8bePSaMwE3jzIBVaWcggUr51xCUBvy7r/nydamrcsmW3h08Cv31+RfGtHPnuXJP",
+	"tjhNqtZyJeTb0mvbFMSqRHL35PB853W391ppuFz6rfXO+ubWlo9WfX9tbW170+ts+msrm531ja3VjY1B",
+	"p7261YYbg43N9uawDVe2N9trm6tozZf/2IBrw5n6rSeidHikPTxnvN3fQ+zU1/pcmldPNjnd0nfpHI9X",
+	"Ypb1fAQSutQzfiAxvmOgaVymCb0zb7lW0qu9US7XIRaS27WVj0A7dzy2miuNlebQW1tZVAthkGRhnHFd",
+	"83TtiXFXpSMlaHJTqURc7VQuVpWVupmhfnwcmrKD5oFzI85mzt35M5NZarFCNTpX0rf67Pa7gg250W3O",
+	"a6ubmToxTniNCaByi+VbdaN9/ZZj6T01YrZvOWFw6tQtTKknMQZppIX1QVrGl6l8ziivUq3fP2ppFacl",
+	"i6jS8CV0zNqLRRmzRbdgcW4mfxgWZLmWQ1eZ+bFQOlGS8RBfjF/oxYOMu3jZg2awqNN5bqBvbnYhDiKt",
+	"F/2uRBiQI3eivF3zRak301SrxgaTKs/c5ztbU7MyL20+VYl2+7TaT0GrnUtuTNx2rhS702OkWMfArta5",
+	"3QWEVmmys5at2dMlLV3TqXIsFSUxfDK8iVTRjEVOyikkSZENboYs1Fu5MVrSxUarrFFiwS7mNXtMLZTM",
+	"+t0TXcybR58dmTF1gZCMxHHOPdliBzbnbtHsk64AkofS7gzmNXlhisa+kLkKEqOm+suYO1+AdB3Kgtcn",
+	"A5QqMZUKRRVs0iOGWh2SD9+nzNdZIdIMRwDrClU68A9ylXBLKq4H9N6ZEStT3fbnFbVduojtYplkR9HI",
+	"1KU2+UPKRmSrmq/QxqcFbgux7heH0kMiTWOIRyR1vMCkZEzIMcgN+X+7B4dHZ+Di8AJcXO+eHO2B44OP",
+	"YPfkfO9Yfe6TPgnfHp3tHna9nkd3D7r7J8Otj6/v0Nc3G9APTj9ONuHh4VHwBgZi681t56G12zl+OT4a",
+	"HsUPhyJ6d7uJ+uTkcrR/vblxC6/Wo3f76+Gr0zer0R0i6LLlXYVfvry9O5u+5eMPHfr2w+Tg63VvsLJ3",
+	"dro33Dsc3X3Yetvpk6+f7tiRt8detd92Jux4EMDYH1+/xO8g6e7zcGXr48EXPljvXq9u+uKana6+/ei/",
+	"H21fvvyAL4bvti775Hj39qq9ev9u99w/7fGPq9sncI9sHEUr5/fR1tEBbR2hg3cfV76Ee+cXXXjcHrx5",
+	"vRoPR2t7MbrjL696fTJ5+/4K7Z08xJ9ONs5PP9Dzi+PJ/enb4cNgtPJhf+s+/tQ+Frct7+x15wHG7YeQ",
+	"d+Pt128idHd/fnH5EPTJ9Iu4nX4aMvoOo1fTaPJpdP92Igg53WqNegdx6827K/axvd4JD66vNve8weba",
+	"nff61dWr4eldQO4OW33SHl6vdS/henvt9erDbftODNDq/bF38YFenMfHu+/46959u319+LE7vUDx9OXW",
+	"pnfd+ngwPt28W+29O77tkw109Gk0xafn7Umw8vFw//LYi4PJHd/uvoyDu9EKvRqs8dWv4af7i/bmIb16",
+	"eL/WuYXH6+97L8/GnxDqk62N9gf6bjzwVo6j3svb4Sd6y9mB+LR1Mbj+9PLj/auty4j577vs9vXgzV3n",
+	"TXR53H24Gj/wt12+Oz5c6ZP2SfzQeQ9Pd9ujztH6hXfqv2l5X25pe8vz2O3uhxg/vGd4Hcfbpx+irS9X",
+	"rWHv61nI/aMR2Wp9+XTcJ3jrbRwM483N+Mv4fWsiOgNBsBhd8i+344fT+Pbj9dqnwdr4TrzaGh9ftz58",
+	"2FzrfBmfrB9Pupfdt93dPhH7rw4/vb+898KD0fH+6cpxr7v1KXx3N1h9Mz65Ol05+bA7he9Xxh4JuvZ3",
+	"7/Wbexi+u/X31u/7xAu9l/jtm/Pd3dPdvW537RU+OECvN0I2fvV6M37H356cnnbaH9e9T2Py8HHrVTdU",
+	"d2jvcLL1am9yd9Qnu5Ojw1dv6Zu9Lt/b3f24150c7L0eHey9Wut290Z3b9PeL88+dlubux+jUTDtdT99",
+	"fD2+nR6P+6T1crjx9WL47n7wutM++LJ6d7R5/mr3rE1OPrzcvV4J4/veyy9XcW/1/QnbXQ1XD+NARMeX",
+	"B2+OT0S4frDfJyvs8OuHLr1amUbbH4+2Trr7/une3vn0tnvL6fvrrc2P1/Hey9aA3LIrdNk5uTzfG04v",
+	"9jY33m9vrePzd30SrvdeDvjb/cnmXueEBX73dO10P6bTTys9LA7hp7XjtyfvxMurA7iyhvnH3uHe7Ve6",
+	"efFx693qm/O79XafjL68H211zlqDsHPwtbd5tbX6/mB/sBLc364dBfcPo6Mvx2i0svL1w8eHkH3sfXrz",
+	"Zm94/3X4MjjrbcQPo9d9cvvQetOeBp86J3hwyDYOu93p+fb1e9b91Jv0TtsH3u3V1uRgjzzc9fbj6Zfw",
+	"/eTd/dnuh/jg6N3WOVr92Cen+Hpl+OZsi/ub+xF/9bB++vKDT07J297L1+z26uJ4fzV8z4KuTw6uxv7H",
+	"d1u3n+6i9+P9KV9tbW+j8z4Z37XZCZm2b88mdzAetvD11rm38eH+9O725PL0zWj9evvd8fRN/P69+Dr5",
+	"QG5Pz9bfX77a/XK8xj/R8PS0T4ZicPV65eX6dHD5vtVdvd8dwIfL9x2xef317Nb7iu56nw4wPDnbPmm9",
+	"9t7sHV2uvH21tbHV2fe7wcGrbb9P7jqjt/hj720XwjftN2+6X1/fX95dvjk5GR13Pr79iF+fvZt2xOqb",
+	"6ashZzBcn/T23p8PxxfoaHqye/XpTZ/cs+gsuBigIb/aXt+8GnZ2z47i0ddPbG/93cN+7/ju0+hyvPLu",
+	"8L539JbsTb/evZ1uHFx3vlxE+P36tqRR44ujD5/YMfWOV49Petst/PXN26vLQNyedv/VJ/+6GF5t9ol6",
+	"XQ7O9mc9PRVVVilDN5wH7kf6uYD7vALuc1wbdH5YnikeI12/dIBsGs+W4SkqeJbZEWZnMJTjRWmgGTeV",
+	"gtKRAeSSoeFAiVzZIkIRZKJPfrdumn84i1+WMi2or7V6jS5Z4PVpfULybh+gwutjwUoDKf+vvEUfl0Lm",
+	"R1foK2WdKEFdVY2PIR4HruPzGsFAjBPv4RRx9aTovhVChOK0NV6Wrmeq51lghRrOzzO3aI76Kr/C68sT",
+	"XhIUMDKJRtQK1RkzCR5TT/aU/x8yGi6lIswosZYF5unAmKMWm41is1/VuRhKk4+i0Y36uqQWd6xmcshk",
+	"V0xm9xwjAgiVpGgQoFB66DEEhjQmvpNYBpCLmzjyoXCh/wxNVLpJHCIuYBjpgWy5p9BvPoRB9i2QwzRk",
+	"a7enn6bLST7fsts2QzqtVf75WtlYW+tsrG6vOIsVioDfVGN5prO19k61zmkyJ4tyWTOZP+cqzuxOFJfm",
+	"Oim93utjNF2SPjpF767vJ1Eq1t8q5oi94NIra0wZ/op8pf8pl/KQji/I76yvr2yDbrfb3Vs9+wr3VoJP",
+	"+0crZ1cH6/K3o27vPRZ356/Xrrc21w58vntNpmKwOpjcX45Gr4O3weDjh2CTrLTvt92ckrsiiKwlLOFN",
+	"0hkpyDkfq4UMKctBqlIczt0BNVO9ZiKQy0hHkn8xljP+89KcPabKbXX91668+UqrpZuk1QG5Wp/EXRP0",
+	"tGcQB/9XOhAZlyGVXUg1r4NBLFT6ymFa1Y4Xsl4tFL/wIx/sTAD6vIq6xb1dvq6udnBKHwyAiSZkSYFI",
+	"WW96uQK7ekz+fZV1Fy7j8ATlGGSEk2UvnQkUhrY4hFvgIEe6y8qT1GmYCw0ZqmB7vjQwsg7AorDItnMh",
+	"0ZUrlsWKi5XuqeTNu7F01DZZmX4eqXK7ICk3ymzQ20BBZ1lck27a/JgW7TWNdQ46R3nOxhePTjpNsUBh",
+	"o4zFP732c5B35D9l9cJiKtqnSf5ediZ3+z3l1qXsNI87E3/r/Z23oamx8ak21Z2xyWbuVAUObNKUBCM2",
+	"c1Oys9U5mirLmpzF4UBLhaaJRbWexj3Y0mnRTGK1ihxm7nOWtW2WxWCjgJhvHc0aLrXiwkNM+Et0ls1n",
+	"mT4rbLrlB19XwL3BcydPxvo+83BpmGroiwsty+SxoDc6+ILBgqv0TH1MaRfcQ+tn9mYah1lTukOZqZau",
+	"TI58CRCyziEF5oQSV2VKFQYpr4Vnynf7gAsUZa6HTHnpvB5J4oSCFCx/BjAZeLHhClfG19WG9BSfK7JL",
+	"atJay7u3yD9zfu6FTfAEvtdFaY1wl8uPzpHHkGjITxl6o7LLUObkNVTWPKe9umyuXsQQrS3aFbrTxGXW",
+	"RiRnVKZH+1k+TrmNZS9Tw0akUWKSHQwoFQX5I12AgaOhQm4bK4sk77IxSrmBqiqB2sY3OnrtJmL0YTor",
+	"xEOV/zD1+lRj4wqnFS6ZVI7Z8q2CgiMzUZ8sgH3KRpBkPDuy2WXW2qudqoqe3tgdVF4APzHWK2321Di4",
+	"Cpksl3IxcyVqP+1aKiI52dibr6xOQBoGcGRr3LCxBwRN5s5MbMvSwIBTAIMJnHJzxHgBnLlbni8JnDTP",
+	"kfymfLgyV2aBPbMF3CuyFZdPULJKhVOYVIDX+Ne+59UIWWgnEpgUD/rdMD36TBSoau5414u0MLdDGcKW",
+	"udkufvEKh+ireVyWSBVju81JFkNEpKGakdiFiAjYRjmzT7tJKBPjBgwRwx5sRpQGTSIiaXar1Wsrsz4v",
+	"ZScSGRxUx8vZVnUr2CqCfX21l4W6dt1rHUC522SxtFtlP0syXYBV777vHex1imnn5/bprS7XpVQRbe4c",
+	"Mr/fcl32bNq95bo5MjPN61LKSDCvQ5U7rHQ8d9EEawod4XtJi0o5+VUxLMwBH9M48AFDKvx3gIDyq1cq",
+	"xvIm6RIHksyqwoR94th7mdUccxAiSEymAZm53tEQ6JMniwcwpJ8FbeoszQuTtuYNucc00MUAxwbgPmFx",
+	"gNTkiKEhZagOJkinDjFPkzrNQH5Wq5OhzxNo65Gr4A/yQvRJRDnHA53kIsQPKtA9VE+r8lk0+wEEHSkD",
+	"raSWyd2pcqnN5PtcLGwhi64kv/XCV2rBHsXyP0tcqAV7FO7Tgr2KuTaWvRoLdivnQlNi9KNl9TQIa35H",
+	"Uw7FLd7XbSyWPTafCwdsyaTlLCakKjN5rgJG6dwuvaDvLFbiDkkrDPm58umqTjHb5KtJXlabPzabY5V6",
+	"uKlHM8X9avWayvLmRpqxii1TZorROMobWtKHWn1cSDIqSZoLmQHP2OHxATv9iF+enl5P4tfwsvsmvDyh",
+	"R18vh50v+x1/f/1re/fqobXxsFh0WcwRW3FLMEa+LefJtlF4ugHgAjJhgpJ+2/itDn5b/02ld/mtM/hN",
+	"kmMbOy83RKUn6RNIACIem0YC+clITXAu6fAEc5TtJlRhLdkPQKBqngOBHiR9t/3ycly1ZL6oujMbDla6",
+	"SSbr043O+rRMNr1sti3HiVg+X5VbutEzZCKVwe/uDCEjRBCDJmbShE/+UZkQ6LkMf0UZ/uA+nF9TxBDA",
+	"4uFxnb7MOchjWv5qYv+ggCAmWPB8hi1wiHedx54jL5bOez15iPSh3UWQaeI3UP96Ze/Pm/dXtXpNHTcl",
+	"ret2yahSjVX79k3pa4a0DKWt1Cyo1uzp4rTaEchUumjWcvkj9DGudSPojRHoqGS9SiOQOGpOJpMmVJ+V",
+	"d6Tpy1snR3sHZ72DRqfZbo5FGGi5SyhknPd21fTGesdMcWIY4UxQ706tI/vQCBH5QWbpaDdXlPOIGCs0",
+	"tWDsqwy4tRESFfKmdoTyKPORPMSBrlkcxgKqKDRJPaBhlLPlkOuABpKP7xOlfGuCc+k/petI5luqcj8M",
+	"6brJQEEks881FWdhBpeGttohEl359YSO1CIYDJFQgvK/SwoHOVlgwU+gVtcxYph4ONJpbGXjLzFS8XNm",
+	"q7LfNV1yhpgtM2WyjoopdYbQ3HzpwfdRgIS109bqj4MkhD4CUFECONQKHsyBcZxygcQx8VAOokV8rpYA",
+	"ZqDEn7lwxETg4MnhCCkX8lSrPGISprpEjvpVARRCMq0AKMA6/30KkKml5867+lmSSu0qoq5cp93OpMwz",
+	"+dKT4h63XOth08FnSjr2NiiKVX651F2yt1aSgrUnnNxUcCrPfES0fGw9UdW8Kz9+3m4sxkCo0vmYA6yh",
+	"0LOv/vjZr0nqCyefhwgxeVAzV/9bvbb+M/B/TdBDpJOjKS9FQD0vZvKpzj6WimjaZ/Lfn799ziQpql1l",
+	"CXGeWqtBZLFxgnjrT+x/q3w9Dg2Ho8VFOQoERsaTZCi1v+kqIcr/CVrvWqv3wcQLYj/jaE6Z4ojSGnWq",
+	"NC2mBCjpEvm6KFrp5diTEPes5Drz8dCV//XoBnhBgVyjoQjy/UwJAvZrWXZIWwNchPyJ/Dl+JEXJYslx",
+	"tLI4+en0JPG79p9JSkpS1tprPwOSOyJroua34FeiZlkipIhXcp4Sghb7DY8hlbIU6ix0s5li1QVkumR5",
+	"2jSdZV1JNTRWnTDrE21y51VUKvb30iHlZLUfe9/L8zl2wZrfS2t+5ir+aSTAdQJ+JUpQvrYF/qZeiygX",
+	"rgQUxmRd2Vephbrve3VwuHchORVlimgCrfbtk7Rm21BHJqi0ErriA2bgaL+e8jVmaEltDaHiuoC0VjMj",
+	"XytL1AiYcCHlZwOMAz7jbFymOReUl4iOYWcQF7vUn/4wWmPdS799+1bkn76VSN7KDwOjSngqnxMV2JQS",
+	"9mfS90z6fh3Sl1Kv8iqcDFAi3Gn9kyuaRf4+j542rY7SGsW5kOprRQL7xNJAaQ9UBnBTwl/Rw1SfmqdZ",
+	"emIH1VpAqnMybv8Iue57iJ4+As8U75ni/UIUr4o+qVDoKoXVHGpWEObAMrLcM4H6TgL1LHw+06NfmB45",
+	"yYthvowqaufPCtlzT7mJAAgImlh5sA4iKvQwuiIKx1ybZGVw6T1i0JqklZXasFYqiYhmuzDLelryCuEw",
+	"sTv+EJkwH336s0VBPfuR79px8xGMIddeQH8B/2P1rc9051nvvTzBMUQjr+fWf/DWXLNdovI1PZTP8lQ5",
+	"awxQar2rA6uQqvdJQI0vEryXXyhTgl2VRU4P/MNV3JlpZmm2zTKf79jzHVtSo1w+QrmbtqAeJTF9q9da",
+	"+pNJI7SQR8NHESKSYwC3dFCtEEle6gXEDDuXoEa4/weIGHrJGlnVNm6LGY2WZ2P3M0Hyf0GtB0yf/fp3",
+	"uucs4ZFjUTbHFUe3WsYZJxn4H+aOk8PUDGL1TKWeqdQv7ZLjlFEk56RdDbOaEacBmyzH/mSI1d+IivwQ",
+	"m3uCGTXwzze2kzTFmJ6kyvYkVVvawRBzMECqJLiOjnLTNRkn1VIhU3l4iqhdmHqtPdUErrv5LSeZS7To",
+	"5E9GZzfjAshaWK0/VQrcamfbSyRiRrQLmi6mpLPRqpIoQ0wwH6dPuUrGTKh+vQna0eVhM8U0bGJbbAy0",
+	"el/q5aS4QqoEPBpq9926LnwyCGIUMUwE8LKVY7QaUjawyawT40oICR4iLmaxCyql8LKXW6f/DCPIUJKc",
+	"96+/7PWl4BbUDbXav6cDfOVvIp7Jba6yTpuDPUBigpCOctOJAxIl1Q8nFZLdzZQZshPrsPzkpv10gmLP",
+	"yoRmceEgJnRCpLqukpDsmwZqdQAygWXZdv1KF+nIzOtqJ1paHZF2/OW4e+oJJExRg/wWJ/MMMIGuam3u",
+	"E5+kih5SVj7uzWeG/5nh/0XUElmyklAVnfslPc1lehVQ704lFV+E8UEPclDLx9QTD1nMklLLmgnJ8jB1",
+	"zf1oztNJ5lSedhnuKxQP0QQXkOsJLXQABpSM1Hx9kjPSWtNdGjGcRi7JwUBSKS5hv+AIYjLTYmJxshRp",
+	"lbjWHnhae5JbwD9GlZLgroLgJju6DMF9YvYiywdK6zOh6T79ZL5CKglhipR8jWoOYNUVcV/lEX9ssGCJ",
+	"8wAzdZFYpCrIurGncKpe01zOdTgwrlxAlzWZfetGz8rKhW7YiFffrhF33yxVzIZQnQDDk9WYgF4L+F36",
+	"243GJunWm9752R/N/zqFgDz+CXJmv4hWVp5/l5KWC1wn+4xSkvZTwCh/IaOWIdkKQ01wID8ljT2qLha3",
+	"FULM9vloiAnyARQgm/CCcp0ZV9Ujg6Rl/m4kmoD1GVfxNEHB832cex9TZFXJGNnt/guevL/iruWvxwKX",
+	"zshh8++caVghMEsbmuVTsw8RU9cP+UCb+rnN4WPuWhIkrzL3zLoZFs7nizH/YlhcPcvez7L3f7PsXaJN",
+	"8+kdH9CQz5W5IdAJVkFv9/wU+NSLQ7moOXxDnxSaQ5a06V3sfzCcw0zXgd3zU/6d4q8d4x/iQqBWW0Hp",
+	"1Md/2vOfLnrOVRhDhhZIB6EDhHi+RCQHqrsJ1paC6GQMhTabqWhuGAR0osmbT3UrPPvoa3B+YSv3Dz7o",
+	"Ej/znH3VpoARkxv2HET0/Ij/Ih4z+sDKA6xt95bkUJL393PHEB3K3ondX/dNajLTLLFogvdGc67yMvZJ",
+	"hFiIOceU1LPpLjxIEk2yliHqKZMhiZ2msZoS9UnWv9la+ZSHs7UHqBADnZ4HpFOCNH9xMDUUk6tWtjxE",
+	"Ara8/PLHjF7SlpdQAKv06XIIuaxpjjYzFAXQM7na08lnBkXJ6Z4djiqp8F8b1aU3p8qTIKO21qfg+Rl4",
+	"fgZ+gWdAneoSfcvR9GomtvWn9KAhKCjEo8yIJvmVSVzJzepKvwLVHLobboO0BYGnbNRYW9/4C7nfKqKn",
+	"2AdF8hi6p3fPNO8fTPOKdmbtvpZliPIp0X6dNEfyZAORHHelhzfsX55PdlBJVSm1YSqkVgbjv0LC0/i5",
+	"vDjldZBU6MiG21MuGFL4DbEwKd8Q8ZBv8r31SWJ3yXrAq74aAJ0ZKdmLxDKmmGTdpE9MVQHLTqvs/x7S",
+	"jC7QXK4cEYe2yOw0U1BXGwb7hGOBjHLMg/IcMJ0loODtO5sXzlTR/a/RTjwdiSpUja6g0PkyyIkbvC0R",
+	"/Eyv/2H0unAefilO9UAd2kx1b14gdMp+kF2gJsk+ijgN7lErCSOYHfyzb9rvJs1/jGRr51lKqm3/gOmr",
+	"o2hsG996aKnQ9J9ONewOPpOPX7cCgD1KOtaB6aKCyY00KQ58zAXDgzipoWYsJCXbxX6m4Y/O91Gay3VR",
+	"Mm1yLM6vZtY1aSIUkY0jw+P5ztVNgY8ExIFr71p/qj/pt0U3cR6Hl60C6+f7ORg9PfmCzB4bo6Cx3dxY",
+	"ROB/hQOBmGR6M+4z4DQOBI4CpKsRc1uZSxepxjpo3VX5RY1xY4pdOUD7dw2GWBfyW6aa7iywIfPGWCBP",
+	"xOw7AM+OUgX6w9bGzcaahF623lhbagWff9J93k9O8OwrnZz0n8Sv5SYnVLrSxeSX8xExWDM2cXOqvPz9",
+	"VbRDTTKT4CtQDaUvEArXCtMmrQiOUGVN1Ew7XSbwRx68dA0uViPJ+mOQ8czj/DUikj7wv55DFkwOkHzD",
+	"k2rO9jSl12x+kSVIdMg28ZI3V0OWPAzqBfRdXiV6mQvra5Bp/l3amtWfrBuv3Er1AWR/e77Fz7d4mVuM",
+	"yidI3tyk5Gb1C3lumnznuS8UWC0v1ICiaAHABMghjIflr+jDOnM5EvVZaa6loj7nKPKNy0fWcV9pvzOC",
+	"kw0ZZYIDrcy/x8oZJkmaATwaE1EHVyc91frw4hBEjA4CFHJdabesuLrMQLqnAP0xiqtknqma5S/SX5Wg",
+	"qFZjvUYwEGP75KQhEEVB/SerwpPjoE7Vs3Lr11VuqROoDtc4OWopP5Y/ZpKmcCRl4pa563y2PrqnGl/Y",
+	"tj/mTucn+YuudBGI6hutWwILiTZqWtKZE9Z/4o3mFqjne/yL3mNzrIaUmUOkUlalUXWUZK61PW5SXLH3",
+	"Omvv5/Oz8F8llnNjUNesg+6v0zlI9iBvtfMxQ556NzARtE9kCxPtCrigzJr8ceDznKHfPoB6HG32VypI",
+	"l9U/ySVhFpF1A7BRiG77fdYy/WOT/Ttmek78/2x4/y8jSruFLC+Kt8iTBFtDaCjlD+WvTqamiZsyaWoz",
+	"mzCZsQlCvvLnHyRBdVX0yNAXTdGafXI+HAaYZHOahdaEj1nWKYmj4B7xuvkuF4AFgGMVKiAHxSECPIJM",
+	"i1ahQYZsO58EHeml/ngKpCf6iwjQd7kDWWeuZ6r0TJUWpUr6tJcoUZLgM8eRGE8dTRicBKmgky5pt7Ln",
+	"e5k83yVy9d8ft+hAleMI5LftL0r3/Xz/n90Bnyrjd34t2sMVUCtYVRIdVwLTmdRnuTyk/xX056EhIHuS",
+	"9KN5VDyTm2dy88vlG1VJ1o1jXNHbWA3G7t0E4RRiAn6PGPVjT/70B9Bta/VazILaTs2mM4MRbtIIET7G",
+	"Q9H0aCh/aSnhr6GYKsQaRqRirftOrezv1RNwJBn7GRNwAUfoO6dROCQC+DSEmCTTzBvn87f/fwDUJyxL",
+	"PpQBAA==",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -0,0 +1,95 @@
+package v2
+
+// RepositoryCheckRequest methods
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/osbuild/osbuild-composer/internal/common"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+)
+
+func (request *RepositoryCheckRequest) Check(ctx context.Context, workers *worker.Server) ([]worker.RepositoryCheckResultItem, error) {
+	if len(request.Repositories) == 0 {
+		return []worker.RepositoryCheckResultItem{}, nil
+	}
+
+	repos, err := convertRepos(request.Repositories, []Repository{}, []string{})
+	if err != nil {
+		// Error comes from genRepoConfig and is already an HTTPError
+		return nil, err
+	}
+
+	checkJobID, err := workers.EnqueueRepositoryCheckJob(ctx, &worker.RepositoryCheckJob{
+		Repositories: repos,
+	}, "")
+	if err != nil {
+		return nil, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
+	}
+
+	// Limit how long a check can take
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*maxJobTimeoutMinutes)
+	defer cancel()
+
+	// Wait until the check job is finished, fails, or is canceled
+	var result worker.RepositoryCheckJobResult
+	for {
+		time.Sleep(time.Millisecond * 50)
+		info, err := workers.RepositoryCheckJobInfo(checkJobID, &result)
+		if err != nil {
+			return nil, HTTPErrorWithInternal(ErrorFailedToCheckRepositories, err)
+		}
+		if result.JobError != nil {
+			return nil, HTTPErrorWithInternal(ErrorFailedToCheckRepositories, fmt.Errorf("Repository check job %q failed: %s", checkJobID, result.JobError.Reason))
+		}
+		if info.JobStatus != nil {
+			if info.JobStatus.Canceled {
+				return nil, HTTPErrorWithInternal(ErrorFailedToCheckRepositories, fmt.Errorf("Repository check job %q was canceled", checkJobID))
+			}
+
+			if !info.JobStatus.Finished.IsZero() {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, HTTPErrorWithInternal(ErrorFailedToCheckRepositories, fmt.Errorf("Repository check job %q timed out", checkJobID))
+		default:
+		}
+	}
+
+	return result.Results, nil
+}
+
+// repositoryCheckResultsToHealth converts the worker results into the API
+// response type, leaving empty fields unset
+func repositoryCheckResultsToHealth(results []worker.RepositoryCheckResultItem) []RepositoryHealth {
+	health := make([]RepositoryHealth, 0, len(results))
+	for _, r := range results {
+		h := RepositoryHealth{
+			Healthy:      r.Healthy,
+			PackageCount: r.PackageCount,
+			LastUpdated:  r.LastUpdated,
+		}
+		if r.URL != "" {
+			h.Url = common.ToPtr(r.URL)
+		}
+		if r.Revision != "" {
+			h.Revision = common.ToPtr(r.Revision)
+		}
+		if r.TLSError != "" {
+			h.TlsError = common.ToPtr(r.TLSError)
+		}
+		if len(r.GPGErrors) > 0 {
+			h.GpgErrors = common.ToPtr(r.GPGErrors)
+		}
+		if r.Error != "" {
+			h.Error = common.ToPtr(r.Error)
+		}
+		health = append(health, h)
+	}
+	return health
+}
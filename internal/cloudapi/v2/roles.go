@@ -0,0 +1,189 @@
+package v2
+
+import (
+	"fmt"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/osbuild/osbuild-composer/internal/audit"
+	"github.com/osbuild/osbuild-composer/internal/auth"
+)
+
+// Role is the level of access a caller of the API has within its tenant.
+// Each role includes all the permissions of the roles before it.
+type Role int
+
+const (
+	// RoleNone may only call the public routes
+	RoleNone Role = iota
+	// RoleViewer may read the status, logs and results of composes
+	RoleViewer
+	// RoleComposer may additionally start composes and depsolve
+	RoleComposer
+	// RoleAdmin may additionally delete and share composes and manage the
+	// cloud credentials of the tenant
+	RoleAdmin
+)
+
+func (r Role) String() string {
+	switch r {
+	case RoleViewer:
+		return "viewer"
+	case RoleComposer:
+		return "composer"
+	case RoleAdmin:
+		return "admin"
+	default:
+		return "none"
+	}
+}
+
+// ParseRole converts the name of a role, as used in the configuration file,
+// into a Role
+func ParseRole(name string) (Role, error) {
+	switch name {
+	case "none":
+		return RoleNone, nil
+	case "viewer":
+		return RoleViewer, nil
+	case "composer":
+		return RoleComposer, nil
+	case "admin":
+		return RoleAdmin, nil
+	default:
+		return RoleNone, fmt.Errorf("unknown role %q", name)
+	}
+}
+
+// operationRoles is the role each operation of the API requires. Operations
+// which aren't listed require RoleAdmin.
+var operationRoles = map[string]Role{
+	"getOpenapi":   RoleNone,
+	"getError":     RoleNone,
+	"getErrorList": RoleNone,
+
+	"getComposeList":          RoleViewer,
+	"getComposeStatus":        RoleViewer,
+	"getComposeMetadata":      RoleViewer,
+	"getComposeLogs":          RoleViewer,
+	"getComposeManifests":     RoleViewer,
+	"getComposeSBOMs":         RoleViewer,
+	"getComposeLockfile":      RoleViewer,
+	"getComposeDiff":          RoleViewer,
+	"getComposeDownload":      RoleViewer,
+	"getComposeShares":        RoleViewer,
+	"getCloneStatus":          RoleViewer,
+	"getSourceBundleStatus":   RoleViewer,
+	"getSourceBundleDownload": RoleViewer,
+	"getCloudCredentialsList": RoleViewer,
+	"getCloudCredentials":     RoleViewer,
+	"getDistributionList":     RoleViewer,
+	"getDistribution":         RoleViewer,
+
+	"postCompose":             RoleComposer,
+	"postCloneCompose":        RoleComposer,
+	"postComposeSourceBundle": RoleComposer,
+	"postSourceBundleImport":  RoleComposer,
+	"postSourceBundleCompose": RoleComposer,
+	"postDepsolveBlueprint":   RoleComposer,
+	"postSearchPackages":      RoleComposer,
+	"postRepositoriesCheck":   RoleComposer,
+
+	"deleteCompose":          RoleAdmin,
+	"postComposeShare":       RoleAdmin,
+	"deleteComposeShare":     RoleAdmin,
+	"postCloudCredentials":   RoleAdmin,
+	"deleteCloudCredentials": RoleAdmin,
+	"getAuditLog":            RoleAdmin,
+}
+
+// requiredRole returns the role needed to call the operation
+func requiredRole(operationID string) Role {
+	role, ok := operationRoles[operationID]
+	if !ok {
+		return RoleAdmin
+	}
+	return role
+}
+
+// RoleMapping derives the roles of callers authenticated by a JWT from its
+// claims
+type RoleMapping struct {
+	// JWTRoleFields are the claims holding the roles of the caller, each of
+	// them may be a string or a list of strings
+	JWTRoleFields []string
+	// Roles maps the values of the claims to roles, a caller gets the
+	// highest role any of its values maps to
+	Roles map[string]Role
+	// DefaultRole is the role of callers none of whose values are in Roles
+	DefaultRole Role
+}
+
+// role returns the role of the caller of ctx
+func (m *RoleMapping) role(ctx echo.Context) Role {
+	values, err := auth.GetListFromClaims(ctx.Request().Context(), m.JWTRoleFields)
+	if err != nil {
+		return m.DefaultRole
+	}
+	role := RoleNone
+	found := false
+	for _, value := range values {
+		if r, ok := m.Roles[value]; ok {
+			found = true
+			if r > role {
+				role = r
+			}
+		}
+	}
+	if !found {
+		return m.DefaultRole
+	}
+	return role
+}
+
+// callerRole returns the role of the caller of ctx. Callers are only
+// restricted if roles or API keys are configured, otherwise authorization is
+// left to the TLS client certificates or the ACL of the JWT handler.
+func (s *Server) callerRole(ctx echo.Context) (Role, error) {
+	if key, ok := ctx.Get(auth.APIKeyCtxKey).(auth.APIKey); ok {
+		role, err := ParseRole(key.Role)
+		if err != nil {
+			return RoleNone, HTTPErrorWithInternal(ErrorUnauthorized, err)
+		}
+		return role, nil
+	}
+	if s.config.JWTEnabled && s.config.Roles != nil {
+		return s.config.Roles.role(ctx), nil
+	}
+	if !s.config.JWTEnabled && len(s.config.APIKeys) > 0 {
+		return RoleNone, HTTPError(ErrorUnauthenticated)
+	}
+	return RoleAdmin, nil
+}
+
+// Authorize makes sure the caller has the role the operation of the request
+// requires
+func (s *Server) Authorize(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		route, _, err := s.router.FindRoute(c.Request())
+		if err != nil {
+			// unknown routes are rejected by ValidateRequest
+			return next(c)
+		}
+		audit.SetAction(c.Request().Context(), route.Operation.OperationID)
+		required := requiredRole(route.Operation.OperationID)
+		if required == RoleNone {
+			return next(c)
+		}
+
+		role, err := s.callerRole(c)
+		if err != nil {
+			return err
+		}
+		if role < required {
+			return HTTPErrorWithInternal(ErrorUnauthorized,
+				fmt.Errorf("%s requires the %s role, the caller has %s", route.Operation.OperationID, required, role))
+		}
+		return next(c)
+	}
+}
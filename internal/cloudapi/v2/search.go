@@ -13,7 +13,7 @@ import (
 	"github.com/osbuild/osbuild-composer/internal/worker"
 )
 
-func (request *SearchPackagesRequest) Search(df *distrofactory.Factory, rr *reporegistry.RepoRegistry, workers *worker.Server) (rpmmd.PackageList, error) {
+func (request *SearchPackagesRequest) Search(ctx context.Context, df *distrofactory.Factory, rr *reporegistry.RepoRegistry, workers *worker.Server) (rpmmd.PackageList, error) {
 	distro := df.GetDistro(request.Distribution)
 	if distro == nil {
 		return nil, HTTPError(ErrorUnsupportedDistribution)
@@ -38,7 +38,7 @@ func (request *SearchPackagesRequest) Search(df *distrofactory.Factory, rr *repo
 	}
 
 	// Send the search request to the worker
-	searchJobID, err := workers.EnqueueSearchPackages(&worker.SearchPackagesJob{
+	searchJobID, err := workers.EnqueueSearchPackages(ctx, &worker.SearchPackagesJob{
 		Packages:         request.Packages,
 		Repositories:     repos,
 		ModulePlatformID: distro.ModulePlatformID(),
@@ -9,8 +9,6 @@ import (
 	"math"
 	"math/big"
 	"net/http"
-	"slices"
-	"strings"
 	"sync"
 	"time"
 
@@ -29,19 +27,20 @@ import (
 
 	"github.com/osbuild/image-builder/pkg/arch"
 	"github.com/osbuild/image-builder/pkg/bootc"
-	"github.com/osbuild/image-builder/pkg/container"
-	"github.com/osbuild/image-builder/pkg/depsolvednf"
 	"github.com/osbuild/image-builder/pkg/distro"
 	"github.com/osbuild/image-builder/pkg/distro/generic"
 	"github.com/osbuild/image-builder/pkg/distrofactory"
 	"github.com/osbuild/image-builder/pkg/manifest"
-	"github.com/osbuild/image-builder/pkg/ostree"
 	"github.com/osbuild/image-builder/pkg/reporegistry"
 	"github.com/osbuild/image-builder/pkg/sbom"
+	"github.com/osbuild/osbuild-composer/internal/audit"
 	"github.com/osbuild/osbuild-composer/internal/auth"
 	"github.com/osbuild/osbuild-composer/internal/common"
+	"github.com/osbuild/osbuild-composer/internal/lockfile"
+	"github.com/osbuild/osbuild-composer/internal/manifestjob"
 	"github.com/osbuild/osbuild-composer/internal/prometheus"
 	"github.com/osbuild/osbuild-composer/internal/target"
+	"github.com/osbuild/osbuild-composer/internal/tracing"
 	"github.com/osbuild/osbuild-composer/internal/worker"
 	"github.com/osbuild/osbuild-composer/internal/worker/clienterrors"
 )
@@ -49,10 +48,9 @@ import (
 // maxJobTimeoutMinutes is the maximum timeout in minutes for a job to finish
 const maxJobTimeoutMinutes = 5
 
-// manifestSourceFunc is a factory function that produces a "manifest source" object.
-// For the standard (package-based) flow it simply returns a pre-built *manifest.Manifest.
-// For bootc it reconstructs the manifest from BootcInfoResolve results.
-type manifestSourceFunc func() (*manifest.Manifest, error)
+// manifestSourceFunc produces the manifest source a manifest job is
+// serialized from, see [manifestjob.SourceFunc]
+type manifestSourceFunc = manifestjob.SourceFunc
 
 // serializeManifestFunc is used to serialize the manifest
 // it can be overridden for testing
@@ -75,6 +73,17 @@ type ServerConfig struct {
 	TenantProviderFields []string
 	JWTEnabled           bool
 
+	// Roles restricts the operations callers authenticated by a JWT may
+	// call, nil allows them all operations
+	Roles *RoleMapping
+	// APIKeys authenticate service accounts when JWT is disabled, keyed by
+	// the hash of the key (see auth.HashAPIKey). If set, callers need one
+	// of them.
+	APIKeys map[string]auth.APIKey
+
+	// Audit records the calls of mutating operations, nil disables it
+	Audit *audit.Log
+
 	// Experimental configuration option. Can only be set through the
 	// IMAGE_BUILDER_EXPERIMENTAL environment variable using:
 	//
@@ -170,14 +179,18 @@ func (s *Server) Handler(path string) http.Handler {
 	}
 
 	mws := []echo.MiddlewareFunc{
+		tracing.Middleware("cloudapi"),
+		audit.Middleware(s.config.Audit, "cloudapi", s.auditPrincipal),
 		prometheus.StatusMiddleware(prometheus.ComposerSubsystem),
 	}
 	if s.config.JWTEnabled {
 		mws = append(mws, auth.TenantChannelMiddleware(s.config.TenantProviderFields, HTTPError(ErrorTenantNotFound)))
+	} else if len(s.config.APIKeys) > 0 {
+		mws = append(mws, auth.APIKeyMiddleware(s.config.APIKeys, HTTPError(ErrorUnauthenticated)))
 	}
 	mws = append(mws,
 		prometheus.HTTPDurationMiddleware(prometheus.ComposerSubsystem),
-		prometheus.MetricsMiddleware, s.ValidateRequest)
+		prometheus.MetricsMiddleware, s.Authorize, s.ValidateRequest)
 	RegisterHandlers(e.Group(path, mws...), &handler)
 
 	return e
@@ -188,116 +201,32 @@ func (s *Server) Shutdown() {
 	s.goroutinesGroup.Wait()
 }
 
-type manifestJobDependencies struct {
-	depsolveJobID         uuid.UUID
-	containerResolveJobID uuid.UUID
-	ostreeResolveJobID    uuid.UUID
-	bootcInfoResolveJobID uuid.UUID
-	bootcPreManifestJobID uuid.UUID
-}
-
-// IDs returns a slice of the non-nil job IDs.
-func (mjd manifestJobDependencies) IDs() []uuid.UUID {
-	var ids []uuid.UUID
-	if mjd.depsolveJobID != uuid.Nil {
-		ids = append(ids, mjd.depsolveJobID)
-	}
-	if mjd.containerResolveJobID != uuid.Nil {
-		ids = append(ids, mjd.containerResolveJobID)
-	}
-	if mjd.ostreeResolveJobID != uuid.Nil {
-		ids = append(ids, mjd.ostreeResolveJobID)
-	}
-	if mjd.bootcInfoResolveJobID != uuid.Nil {
-		ids = append(ids, mjd.bootcInfoResolveJobID)
-	}
-	if mjd.bootcPreManifestJobID != uuid.Nil {
-		ids = append(ids, mjd.bootcPreManifestJobID)
-	}
-	return ids
-}
+// manifestJobDependencies holds the IDs of the jobs a manifest job depends on
+type manifestJobDependencies = manifestjob.Dependencies
 
 // enqueueResolveJobs adds all the necessary content resolve jobs for the
 // manifest to the queue and returns a [manifestJobDependencies] that holds
-// resolve job IDs by type.
-func (s *Server) enqueueResolveJobs(manifestSource *manifest.Manifest, it distro.ImageType, channel string) (manifestJobDependencies, error) {
-	var jobDependencies manifestJobDependencies
-
-	arch := it.Arch()
-	distribution := arch.Distro()
-
+// resolve job IDs by type. If a lockfile is given, the package sets are pinned
+// to the locked packages.
+func (s *Server) enqueueResolveJobs(ctx context.Context, manifestSource *manifest.Manifest, it distro.ImageType, lf *lockfile.Lockfile, channel string) (manifestJobDependencies, error) {
 	pkgSetChains, err := manifestSource.GetPackageSetChains()
 	if err != nil {
-		return jobDependencies, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
-	}
-	depsolveJobID, err := s.workers.EnqueueDepsolve(&worker.DepsolveJob{
-		PackageSets:      pkgSetChains,
-		ModulePlatformID: distribution.ModulePlatformID(),
-		Arch:             arch.Name(),
-		Releasever:       distribution.Releasever(),
-		SbomType:         sbom.StandardTypeSpdx,
-	}, channel)
-	if err != nil {
-		return jobDependencies, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
+		return manifestJobDependencies{}, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
 	}
-	jobDependencies.depsolveJobID = depsolveJobID
-
-	containerSources := manifestSource.GetContainerSourceSpecs()
-	if len(containerSources) > 0 {
-		pipelineSpecs := make(map[string][]worker.ContainerSpec, len(containerSources))
-		for name, sources := range containerSources {
-			specs := make([]worker.ContainerSpec, len(sources))
-			for idx, source := range sources {
-				specs[idx] = worker.ContainerSpecFromVendorSourceSpec(source)
-			}
-			pipelineSpecs[name] = specs
-		}
-
-		job := worker.ContainerResolveJob{
-			Arch:          arch.Name(),
-			PipelineSpecs: pipelineSpecs,
-		}
-
-		containerResolveJobID, err := s.workers.EnqueueContainerResolveJob(&job, nil, channel)
-		if err != nil {
-			return jobDependencies, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
+	if lf != nil {
+		if err := lf.Pin(pkgSetChains); err != nil {
+			return manifestJobDependencies{}, HTTPErrorWithInternal(ErrorInvalidLockfile, err)
 		}
-		jobDependencies.containerResolveJobID = containerResolveJobID
 	}
 
-	commitSources := manifestSource.GetOSTreeSourceSpecs()
-	if len(commitSources) > 1 {
-		// only one pipeline can specify an ostree commit for content
-		pipelines := make([]string, 0, len(commitSources))
-		for name := range commitSources {
-			pipelines = append(pipelines, name)
-		}
-		return jobDependencies, HTTPErrorWithInternal(ErrorEnqueueingJob, fmt.Errorf("manifest returned %d pipelines with ostree commits (at most 1 is supported): %s", len(commitSources), strings.Join(pipelines, ", ")))
-	}
-	for _, sources := range commitSources {
-		workerResolveSpecs := make([]worker.OSTreeResolveSpec, len(sources))
-		for idx, source := range sources {
-			// ostree.SourceSpec is directly convertible to worker.OSTreeResolveSpec
-			workerResolveSpecs[idx] = worker.OSTreeResolveSpec{
-				URL:  source.URL,
-				Ref:  source.Ref,
-				RHSM: source.RHSM,
-			}
-
-		}
-		ostreeResolveJobID, err := s.workers.EnqueueOSTreeResolveJob(&worker.OSTreeResolveJob{Specs: workerResolveSpecs}, channel)
-		if err != nil {
-			return jobDependencies, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
-		}
-
-		jobDependencies.ostreeResolveJobID = ostreeResolveJobID
-		break // there can be only one
+	jobDependencies, err := manifestjob.EnqueueResolveJobs(ctx, s.workers, manifestSource, pkgSetChains, lf, it.Arch(), sbom.StandardTypeSpdx, channel)
+	if err != nil {
+		return jobDependencies, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
 	}
-
 	return jobDependencies, nil
 }
 
-func (s *Server) enqueueCompose(irs []imageRequest, channel string) (uuid.UUID, error) {
+func (s *Server) enqueueCompose(ctx context.Context, irs []imageRequest, channel string) (uuid.UUID, error) {
 	var id uuid.UUID
 	if len(irs) != 1 {
 		return id, HTTPError(ErrorInvalidNumberOfImageBuilds)
@@ -310,20 +239,20 @@ func (s *Server) enqueueCompose(irs []imageRequest, channel string) (uuid.UUID,
 		return id, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
 	}
 
-	dependencies, err := s.enqueueResolveJobs(manifestSource, ir.imageType, channel)
+	dependencies, err := s.enqueueResolveJobs(ctx, manifestSource, ir.imageType, ir.lockfile, channel)
 	if err != nil {
 		logrus.Warningf("ErrorEnqueueingJob, failed creating resolve jobs: %v", err)
 		return id, err
 	}
 
-	manifestJobID, err := s.workers.EnqueueManifestJobByID(&worker.ManifestJobByID{}, dependencies.IDs(), channel)
+	manifestJobID, err := s.workers.EnqueueManifestJobByID(ctx, &worker.ManifestJobByID{}, dependencies.IDs(), channel)
 	if err != nil {
 		logrus.Warningf("ErrorEnqueueingJob, failed creating manifest job (ByID): %v", err)
 		return id, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
 	}
 
 	id, err = s.workers.EnqueueOSBuildAsDependency(
-		ir.imageType.Arch().Name(), &worker.OSBuildJob{Targets: ir.targets}, []uuid.UUID{manifestJobID}, channel,
+		ctx, ir.imageType.Arch().Name(), &worker.OSBuildJob{Targets: ir.targets, WorkerLabels: ir.workerLabels, ImageType: ir.imageType.Name()}, []uuid.UUID{manifestJobID}, channel,
 	)
 	if err != nil {
 		logrus.Warningf("ErrorEnqueueingJob, failed creating osbuild job: %v", err)
@@ -342,7 +271,7 @@ func (s *Server) enqueueCompose(irs []imageRequest, channel string) (uuid.UUID,
 	return id, nil
 }
 
-func (s *Server) enqueueComposeIBCLI(irs []imageRequest, channel string) (uuid.UUID, error) {
+func (s *Server) enqueueComposeIBCLI(ctx context.Context, irs []imageRequest, channel string) (uuid.UUID, error) {
 	logrus.Warnf("using experimental job type: %s", worker.JobTypeImageBuilderManifest)
 	var osbuildJobID uuid.UUID
 	if len(irs) != 1 {
@@ -381,14 +310,14 @@ func (s *Server) enqueueComposeIBCLI(irs []imageRequest, channel string) (uuid.U
 		ExtraEnv: []string{"XDG_CACHE_HOME=/var/cache/osbuild-composer/rpmmd"},
 	}
 
-	manifestJobID, err := s.workers.EnqueueImageBuilderManifestJob(&manifestJob, channel)
+	manifestJobID, err := s.workers.EnqueueImageBuilderManifestJob(ctx, &manifestJob, channel)
 	if err != nil {
 		return osbuildJobID, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
 	}
 	logrus.Debugf("manifest job enqueued: %v", manifestJobID)
 
 	osbuildJobID, err = s.workers.EnqueueOSBuildAsDependency(
-		arch.Name(), &worker.OSBuildJob{Targets: ir.targets}, []uuid.UUID{manifestJobID}, channel,
+		ctx, arch.Name(), &worker.OSBuildJob{Targets: ir.targets, WorkerLabels: ir.workerLabels, ImageType: imageType.Name()}, []uuid.UUID{manifestJobID}, channel,
 	)
 	if err != nil {
 		return osbuildJobID, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
@@ -398,11 +327,11 @@ func (s *Server) enqueueComposeIBCLI(irs []imageRequest, channel string) (uuid.U
 	return osbuildJobID, nil
 }
 
-func (s *Server) enqueueKojiCompose(taskID uint64, server, name, version, release string, irs []imageRequest, channel string) (uuid.UUID, error) {
+func (s *Server) enqueueKojiCompose(ctx context.Context, taskID uint64, server, name, version, release string, irs []imageRequest, channel string) (uuid.UUID, error) {
 	var id uuid.UUID
 	kojiDirectory := "osbuild-cg/osbuild-composer-koji-" + uuid.New().String()
 
-	initID, err := s.workers.EnqueueKojiInit(&worker.KojiInitJob{
+	initID, err := s.workers.EnqueueKojiInit(ctx, &worker.KojiInitJob{
 		Server:  server,
 		Name:    name,
 		Version: version,
@@ -422,13 +351,13 @@ func (s *Server) enqueueKojiCompose(taskID uint64, server, name, version, releas
 			return id, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
 		}
 
-		dependencies, err := s.enqueueResolveJobs(manifestSource, ir.imageType, channel)
+		dependencies, err := s.enqueueResolveJobs(ctx, manifestSource, ir.imageType, ir.lockfile, channel)
 		if err != nil {
 			logrus.Warningf("ErrorEnqueueingJob, failed creating resolve jobs: %v", err)
 			return id, err
 		}
 
-		manifestJobID, err := s.workers.EnqueueManifestJobByID(&worker.ManifestJobByID{}, dependencies.IDs(), channel)
+		manifestJobID, err := s.workers.EnqueueManifestJobByID(ctx, &worker.ManifestJobByID{}, dependencies.IDs(), channel)
 		if err != nil {
 			return id, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
 		}
@@ -457,12 +386,14 @@ func (s *Server) enqueueKojiCompose(taskID uint64, server, name, version, releas
 			targets = append(targets, ir.targets...)
 		}
 
-		buildID, err := s.workers.EnqueueOSBuildAsDependency(archName, &worker.OSBuildJob{
+		buildID, err := s.workers.EnqueueOSBuildAsDependency(ctx, archName, &worker.OSBuildJob{
 			Targets:            targets,
 			ManifestDynArgsIdx: common.ToPtr(1),
 			DepsolveDynArgsIdx: common.ToPtr(2),
 			ImageBootMode:      ir.imageType.BootMode().String(),
-		}, []uuid.UUID{initID, manifestJobID, dependencies.depsolveJobID}, channel)
+			WorkerLabels:       ir.workerLabels,
+			ImageType:          ir.imageType.Name(),
+		}, []uuid.UUID{initID, manifestJobID, dependencies.DepsolveJobID}, channel)
 		if err != nil {
 			return id, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
 		}
@@ -479,7 +410,7 @@ func (s *Server) enqueueKojiCompose(taskID uint64, server, name, version, releas
 			serializeManifestFunc(s.goroutinesCtx, getManifestSource, s.workers, dependencies, manifestJobID, ir.manifestSeed)
 		}(ir)
 	}
-	id, err = s.workers.EnqueueKojiFinalize(&worker.KojiFinalizeJob{
+	id, err = s.workers.EnqueueKojiFinalize(ctx, &worker.KojiFinalizeJob{
 		Server:        server,
 		Name:          name,
 		Version:       version,
@@ -562,7 +493,7 @@ func buildBootcManifestSource(
 	return manifestSource, imgType, nil
 }
 
-func (s *Server) enqueueBootcCompose(request ComposeRequest, channel string) (uuid.UUID, error) {
+func (s *Server) enqueueBootcCompose(ctx context.Context, request ComposeRequest, channel string) (uuid.UUID, error) {
 	var ir ImageRequest
 	if request.ImageRequest != nil {
 		ir = *request.ImageRequest
@@ -673,7 +604,7 @@ func (s *Server) enqueueBootcCompose(request ComposeRequest, channel string) (uu
 		})
 	}
 
-	bootcInfoResolveJobID, err := s.workers.EnqueueBootcInfoResolveJob(ir.Architecture, &worker.BootcInfoResolveJob{
+	bootcInfoResolveJobID, err := s.workers.EnqueueBootcInfoResolveJob(ctx, ir.Architecture, &worker.BootcInfoResolveJob{
 		Specs: bootcInfoResolveSpecs,
 	}, channel)
 	if err != nil {
@@ -694,7 +625,7 @@ func (s *Server) enqueueBootcCompose(request ComposeRequest, channel string) (uu
 	if request.Bootc.BuildReference != nil {
 		preManifestArgs.BuildInfoIdx = common.ToPtr(1)
 	}
-	preManifestJobID, err := s.workers.EnqueueBootcPreManifestJob(preManifestArgs, preManifestDeps, channel)
+	preManifestJobID, err := s.workers.EnqueueBootcPreManifestJob(ctx, preManifestArgs, preManifestDeps, channel)
 	if err != nil {
 		return uuid.Nil, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
 	}
@@ -702,6 +633,7 @@ func (s *Server) enqueueBootcCompose(request ComposeRequest, channel string) (uu
 	// 3. Enqueue container resolve job with empty args, depending on BootcPreManifest.
 	//    The container specs come from BootcPreManifest result via dynArgs[0].
 	containerResolveJobID, err := s.workers.EnqueueContainerResolveJob(
+		ctx,
 		&worker.ContainerResolveJob{
 			PreManifestDynArgsIdx: common.ToPtr(0),
 		},
@@ -716,15 +648,16 @@ func (s *Server) enqueueBootcCompose(request ComposeRequest, channel string) (uu
 
 	// 4. Enqueue ManifestByID (server-side job)
 	//    Dependencies: [containerResolve, bootcInfoResolve, bootcPreManifest]
-	//    Bootc mode is detected by dependencies.bootcInfoResolveJobID != uuid.Nil.
+	//    Bootc mode is detected by dependencies.BootcInfoResolveJobID != uuid.Nil.
 	dependencies := manifestJobDependencies{
-		// depsolveJobID: uuid.Nil — no depsolve for bootc
-		containerResolveJobID: containerResolveJobID,
-		bootcInfoResolveJobID: bootcInfoResolveJobID,
-		bootcPreManifestJobID: preManifestJobID,
+		// DepsolveJobID: uuid.Nil — no depsolve for bootc
+		ContainerResolveJobID: containerResolveJobID,
+		BootcInfoResolveJobID: bootcInfoResolveJobID,
+		BootcPreManifestJobID: preManifestJobID,
 	}
 
 	manifestJobID, err := s.workers.EnqueueManifestJobByID(
+		ctx,
 		&worker.ManifestJobByID{},
 		dependencies.IDs(),
 		channel,
@@ -734,10 +667,12 @@ func (s *Server) enqueueBootcCompose(request ComposeRequest, channel string) (uu
 	}
 
 	// 5. Enqueue OSBuild (worker job, depends on ManifestByID and BootcPreManifest)
-	osbuildJobID, err := s.workers.EnqueueOSBuildAsDependency(ir.Architecture, &worker.OSBuildJob{
+	osbuildJobID, err := s.workers.EnqueueOSBuildAsDependency(ctx, ir.Architecture, &worker.OSBuildJob{
 		// Targets are empty — filled by worker from BootcPreManifest dynargs.
 		ManifestDynArgsIdx:    common.ToPtr(0), // dynArgs[0] = ManifestByID result
 		PreManifestDynArgsIdx: common.ToPtr(1), // dynArgs[1] = BootcPreManifest result
+		WorkerLabels:          common.DerefOrDefault(ir.WorkerLabels),
+		ImageType:             imageTypeName,
 	}, []uuid.UUID{manifestJobID, preManifestJobID}, channel)
 	if err != nil {
 		return uuid.Nil, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
@@ -748,7 +683,7 @@ func (s *Server) enqueueBootcCompose(request ComposeRequest, channel string) (uu
 	buildInfoIdx := preManifestArgs.BuildInfoIdx
 	getManifestSource := func() (*manifest.Manifest, error) {
 		var bootcInfoResult worker.BootcInfoResolveJobResult
-		_, err := s.workers.BootcInfoResolveJobInfo(dependencies.bootcInfoResolveJobID, &bootcInfoResult)
+		_, err := s.workers.BootcInfoResolveJobInfo(dependencies.BootcInfoResolveJobID, &bootcInfoResult)
 		if err != nil {
 			return nil, fmt.Errorf("failed to read bootc info resolve job result: %w", err)
 		}
@@ -767,257 +702,7 @@ func (s *Server) enqueueBootcCompose(request ComposeRequest, channel string) (uu
 
 func serializeManifest(ctx context.Context, getManifestSource manifestSourceFunc, workers *worker.Server, dependencies manifestJobDependencies, manifestJobID uuid.UUID, seed int64) {
 	// prepared to become a config variable
-	ctx, cancel := context.WithTimeout(ctx, time.Minute*maxJobTimeoutMinutes)
-	defer cancel()
-
-	jobResult := &worker.ManifestJobByIDResult{
-		Manifest: nil,
-		ManifestInfo: worker.ManifestInfo{
-			OSBuildComposerVersion: common.BuildVersion(),
-		},
-	}
-
-	var dynArgs []json.RawMessage
-	var err error
-	token := uuid.Nil
-	logWithId := logrus.WithField("jobId", manifestJobID)
-
-	defer func() {
-		if r := recover(); r != nil {
-			logWithId.Errorf("Recovered from panic in serializeManifest: %v", r)
-			jobResult.JobError = clienterrors.New(clienterrors.ErrorManifestGeneration, "Error serializing manifest", fmt.Sprintf("%v", r))
-		}
-
-		// token == uuid.Nil indicates that no worker even started processing
-		if token == uuid.Nil {
-			if jobResult.JobError != nil {
-				// set all jobs to "failed"
-				// osbuild job will fail as dependency
-				allJobIDs := slices.Concat(dependencies.IDs(), []uuid.UUID{manifestJobID})
-				for _, jobID := range allJobIDs {
-					err := workers.SetFailed(jobID, jobResult.JobError)
-					if err != nil {
-						logWithId.Errorf("Error failing job %s: %v", jobID, err)
-					}
-				}
-
-			} else {
-				logWithId.Errorf("Internal error, no worker started processing dependencies but we didn't get a reason.")
-			}
-		} else {
-			result, err := json.Marshal(jobResult)
-			if err != nil {
-				logWithId.Errorf("Error marshalling manifest job results: %v", err)
-			}
-			err = workers.FinishJob(token, result)
-			if err != nil {
-				logWithId.Errorf("Error finishing manifest job: %v", err)
-			}
-			if jobResult.JobError != nil {
-				logWithId.Errorf("Error in manifest job %v: %v", jobResult.JobError.Reason, err)
-			}
-		}
-	}()
-
-	// wait until job is in a pending state
-	for {
-		_, token, _, _, dynArgs, err = workers.RequestJobById(ctx, "", manifestJobID)
-		if errors.Is(err, jobqueue.ErrNotPending) {
-			logWithId.Debug("Manifest job not pending, waiting for dependencies to finish")
-			time.Sleep(time.Millisecond * 50)
-			select {
-			case <-ctx.Done():
-				logWithId.Warning(fmt.Sprintf("Manifest job dependencies took longer than %d minutes to finish,"+
-					" or the server is shutting down, returning to avoid dangling routines", maxJobTimeoutMinutes))
-
-				jobResult.JobError = clienterrors.New(clienterrors.ErrorJobDependency,
-					"Timeout while waiting for dependencies to finish",
-					"There may be a temporary issue with compute resources.",
-				)
-				break
-			default:
-				continue
-			}
-		}
-		if err != nil {
-			logWithId.Errorf("Error requesting manifest job: %v", err)
-			return
-		}
-		break
-	}
-
-	// add osbuild/image-builder dependency info to job result
-	osbuildImagesDep, err := common.GetDepModuleInfoByPath(common.OSBuildImagesModulePath)
-	if err != nil {
-		// do not fail here and just log the error, because the module info is not available in tests.
-		// Failing here would make the unit tests fail. See https://github.com/golang/go/issues/33976
-		logWithId.Errorf("Error getting %s dependency info: %v", common.OSBuildImagesModulePath, err)
-	} else {
-		osbuildImagesDepModule := worker.ComposerDepModuleFromDebugModule(osbuildImagesDep)
-		jobResult.ManifestInfo.OSBuildComposerDeps = append(jobResult.ManifestInfo.OSBuildComposerDeps, osbuildImagesDepModule)
-	}
-
-	// If a pre-manifest job ran upstream (bootc flow), compare its ManifestInfo against
-	// the local ManifestInfo to detect version mismatches between composer instances.
-	if dependencies.bootcPreManifestJobID != uuid.Nil {
-		var preManifestResult worker.BootcPreManifestJobResult
-		_, err := workers.BootcPreManifestJobInfo(dependencies.bootcPreManifestJobID, &preManifestResult)
-		if err != nil {
-			reason := "Error reading pre-manifest job result for ManifestInfo build version check"
-			jobResult.JobError = clienterrors.New(clienterrors.ErrorReadingJobStatus, reason, err.Error())
-			return
-		}
-
-		if jobErr := preManifestResult.JobError; jobErr != nil {
-			jobResult.JobError = clienterrors.New(clienterrors.ErrorJobDependency, "Error in bootc pre-manifest job dependency", jobErr.Details)
-			return
-		}
-
-		if mismatchErr := worker.CompareManifestInfos(preManifestResult.ManifestInfo, jobResult.ManifestInfo); mismatchErr != nil {
-			logWithId.Errorf("ManifestInfo build version mismatch between pre-manifest and manifest jobs: %v", mismatchErr)
-			jobResult.JobError = mismatchErr
-			return
-		}
-	}
-
-	// Obtain the manifest source via the factory function.
-	// For the standard flow this simply returns the pre-built manifest.
-	// For bootc this reconstructs it from BootcInfoResolve results.
-	manifestSource, err := getManifestSource()
-	if err != nil {
-		reason := "Error obtaining manifest source"
-		jobResult.JobError = clienterrors.New(clienterrors.ErrorManifestGeneration, reason, err.Error())
-		return
-	}
-
-	if len(dynArgs) == 0 {
-		reason := "No dynamic arguments"
-		jobResult.JobError = clienterrors.New(clienterrors.ErrorNoDynamicArgs, reason, nil)
-		return
-	}
-
-	var depsolveResult map[string]depsolvednf.DepsolveResult
-	if dependencies.depsolveJobID != uuid.Nil {
-		var depsolveJobResult worker.DepsolveJobResult
-		_, err = workers.DepsolveJobInfo(dependencies.depsolveJobID, &depsolveJobResult)
-		if err != nil {
-			reason := "Error reading depsolve status"
-			jobResult.JobError = clienterrors.New(clienterrors.ErrorReadingJobStatus, reason, nil)
-			return
-		}
-
-		if jobErr := depsolveJobResult.JobError; jobErr != nil {
-			if jobErr.ID == clienterrors.ErrorDNFDepsolveError || jobErr.ID == clienterrors.ErrorDNFMarkingErrors {
-				jobResult.JobError = clienterrors.New(clienterrors.ErrorDepsolveDependency, "Error in depsolve job dependency input, bad package set requested", jobErr.Details)
-				return
-			}
-			jobResult.JobError = clienterrors.New(clienterrors.ErrorDepsolveDependency, "Error in depsolve job dependency", jobErr.Details)
-			return
-		}
-
-		if len(depsolveJobResult.PackageSpecs) == 0 {
-			jobResult.JobError = clienterrors.New(clienterrors.ErrorEmptyPackageSpecs, "Received empty package specs", nil)
-			return
-		}
-
-		depsolveResult, err = depsolveJobResult.ToDepsolvednfResult()
-		if err != nil {
-			reason := "Error converting depsolve result"
-			jobResult.JobError = clienterrors.New(clienterrors.ErrorManifestGeneration, reason, err.Error())
-			return
-		}
-	}
-
-	var containerSpecs map[string][]container.Spec
-	if dependencies.containerResolveJobID != uuid.Nil {
-		// Container resolve job
-		var result worker.ContainerResolveJobResult
-		_, err := workers.ContainerResolveJobInfo(dependencies.containerResolveJobID, &result)
-
-		if err != nil {
-			reason := "Error reading container resolve job status"
-			jobResult.JobError = clienterrors.New(clienterrors.ErrorReadingJobStatus, reason, nil)
-			return
-		}
-
-		if jobErr := result.JobError; jobErr != nil {
-			jobResult.JobError = clienterrors.New(clienterrors.ErrorContainerDependency, "Error in container resolve job dependency", nil)
-			return
-		}
-
-		if result.PipelineSpecs != nil {
-			containerSpecs = make(map[string][]container.Spec, len(result.PipelineSpecs))
-			for name, specs := range result.PipelineSpecs {
-				vendorSpecs := make([]container.Spec, len(specs))
-				for i, s := range specs {
-					vendorSpecs[i] = s.ToVendorSpec()
-				}
-				containerSpecs[name] = vendorSpecs
-			}
-		} else if result.Specs != nil {
-			// TODO (2026-03-30, thozza): remove this once all workers are migrated to the new format.
-			// Old worker fallback: flat result, reconstruct pipeline mapping using manifest source specs.
-			containerSpecs, err = matchContainerSpecsToPipelines(result.Specs, manifestSource.GetContainerSourceSpecs())
-			if err != nil {
-				reason := "Error matching container specs to pipelines"
-				jobResult.JobError = clienterrors.New(clienterrors.ErrorContainerDependency, reason, err.Error())
-				return
-			}
-		}
-	}
-
-	var ostreeCommitSpecs map[string][]ostree.CommitSpec
-	if dependencies.ostreeResolveJobID != uuid.Nil {
-		var result worker.OSTreeResolveJobResult
-		_, err := workers.OSTreeResolveJobInfo(dependencies.ostreeResolveJobID, &result)
-
-		if err != nil {
-			reason := "Error reading ostree resolve job status"
-			logrus.Errorf("%s: %v", reason, err)
-			jobResult.JobError = clienterrors.New(clienterrors.ErrorReadingJobStatus, reason, nil)
-			return
-		}
-
-		if jobErr := result.JobError; jobErr != nil {
-			jobResult.JobError = clienterrors.New(clienterrors.ErrorOSTreeDependency, "Error in ostree resolve job dependency", nil)
-			return
-		}
-
-		// NOTE: The ostree resolve job doesn't hold the pipeline name for the
-		// ostree commits, so we need to get it from the manifest content
-		// field. There should be only one.
-		var ostreeCommitPipeline string
-		for name := range manifestSource.GetOSTreeSourceSpecs() {
-			ostreeCommitPipeline = name
-			break
-		}
-
-		commitSpecs := make([]ostree.CommitSpec, len(result.Specs))
-		for idx, resultSpec := range result.Specs {
-			commitSpecs[idx] = ostree.CommitSpec{
-				Ref:      resultSpec.Ref,
-				URL:      resultSpec.URL,
-				Checksum: resultSpec.Checksum,
-				Secrets:  resultSpec.Secrets,
-			}
-		}
-		ostreeCommitSpecs = map[string][]ostree.CommitSpec{
-			ostreeCommitPipeline: commitSpecs,
-		}
-	}
-
-	ms, err := manifestSource.Serialize(depsolveResult, containerSpecs, ostreeCommitSpecs, nil, nil)
-	if err != nil {
-		reason := "Error serializing manifest"
-		jobResult.JobError = clienterrors.New(clienterrors.ErrorManifestGeneration, reason, err.Error())
-		return
-	}
-
-	jobResult.Manifest = ms
-	jobResult.ManifestInfo.PipelineNames = &worker.PipelineNames{
-		Build:   manifestSource.BuildPipelines(),
-		Payload: manifestSource.PayloadPipelines(),
-	}
+	manifestjob.Serialize(ctx, workers, getManifestSource, dependencies, manifestJobID, time.Minute*maxJobTimeoutMinutes, nil)
 }
 
 // bootcPreManifestLoop is a long-running goroutine started at server init
@@ -0,0 +1,135 @@
+package v2
+
+// Handlers of the grants which share composes with other tenants
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/osbuild/osbuild-composer/pkg/jobqueue"
+
+	"github.com/osbuild/osbuild-composer/internal/audit"
+)
+
+// includes returns whether a grant with the permission allows what needs the
+// required permission, cloning a compose includes reading it
+func (p ComposeSharePermission) includes(required ComposeSharePermission) bool {
+	return p == required || p == Clone
+}
+
+func composeShareToAPI(jobId uuid.UUID, share jobqueue.Share) ComposeShare {
+	return ComposeShare{
+		Href:       fmt.Sprintf("/api/image-builder-composer/v2/composes/%v/shares/%s", jobId, url.PathEscape(share.Channel)),
+		Id:         share.Channel,
+		Kind:       "ComposeShare",
+		Channel:    share.Channel,
+		Permission: ComposeSharePermission(share.Permission),
+		CreatedAt:  share.Created,
+	}
+}
+
+// getComposeShare returns the grant of the channel on the compose
+func (s *Server) getComposeShare(jobId uuid.UUID, channel string) (jobqueue.Share, error) {
+	shares, err := s.workers.JobShares(jobId)
+	if err != nil {
+		return jobqueue.Share{}, HTTPErrorWithInternal(ErrorComposeNotFound, err)
+	}
+	for _, share := range shares {
+		if share.Channel == channel {
+			return share, nil
+		}
+	}
+	return jobqueue.Share{}, HTTPError(ErrorComposeShareNotFound)
+}
+
+func (h *apiHandlers) GetComposeShares(ctx echo.Context, jobId uuid.UUID) error {
+	return h.server.EnsureJobChannel(h.getComposeSharesImpl)(ctx, jobId)
+}
+
+func (h *apiHandlers) getComposeSharesImpl(ctx echo.Context, jobId uuid.UUID) error {
+	shares, err := h.server.workers.JobShares(jobId)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorComposeNotFound, err)
+	}
+
+	items := []ComposeShare{}
+	for _, share := range shares {
+		items = append(items, composeShareToAPI(jobId, share))
+	}
+	return ctx.JSON(http.StatusOK, ComposeShareList{
+		Kind:  "ComposeShareList",
+		Page:  0,
+		Size:  len(items),
+		Total: len(items),
+		Items: items,
+	})
+}
+
+func (h *apiHandlers) PostComposeShare(ctx echo.Context, jobId uuid.UUID) error {
+	return h.server.EnsureJobChannel(h.postComposeShareImpl)(ctx, jobId)
+}
+
+func (h *apiHandlers) postComposeShareImpl(ctx echo.Context, jobId uuid.UUID) error {
+	channel, err := h.server.getTenantChannel(ctx)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorTenantNotFound, err)
+	}
+
+	var request ComposeShareRequest
+	err = ctx.Bind(&request)
+	if err != nil {
+		return err
+	}
+	if request.Channel == "" || request.Channel == channel {
+		return HTTPError(ErrorInvalidComposeShare)
+	}
+	if !request.Permission.Valid() {
+		return HTTPErrorWithInternal(ErrorInvalidComposeShare,
+			fmt.Errorf("unknown permission %q", request.Permission))
+	}
+
+	err = h.server.workers.ShareJob(jobId, request.Channel, string(request.Permission))
+	if errors.Is(err, jobqueue.ErrNotExist) {
+		return HTTPErrorWithInternal(ErrorComposeNotFound, err)
+	}
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorFailedToStoreComposeShare, err)
+	}
+	audit.Annotate(ctx.Request().Context(), "shared_with", request.Channel)
+	audit.Annotate(ctx.Request().Context(), "permission", string(request.Permission))
+
+	share, err := h.server.getComposeShare(jobId, request.Channel)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusCreated, composeShareToAPI(jobId, share))
+}
+
+func (h *apiHandlers) DeleteComposeShare(ctx echo.Context, jobId uuid.UUID, channel string) error {
+	return h.server.EnsureJobChannel(func(ctx echo.Context, jobId uuid.UUID) error {
+		return h.deleteComposeShareImpl(ctx, jobId, channel)
+	})(ctx, jobId)
+}
+
+func (h *apiHandlers) deleteComposeShareImpl(ctx echo.Context, jobId uuid.UUID, channel string) error {
+	share, err := h.server.getComposeShare(jobId, channel)
+	if err != nil {
+		return err
+	}
+
+	err = h.server.workers.UnshareJob(jobId, channel)
+	if errors.Is(err, jobqueue.ErrShareNotExist) {
+		return HTTPError(ErrorComposeShareNotFound)
+	}
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorFailedToStoreComposeShare, err)
+	}
+	audit.Annotate(ctx.Request().Context(), "shared_with", channel)
+
+	return ctx.JSON(http.StatusOK, composeShareToAPI(jobId, share))
+}
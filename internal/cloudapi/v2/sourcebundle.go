@@ -0,0 +1,227 @@
+package v2
+
+// Source bundle handlers, they export the sources of a compose for sites
+// without access to the repositories and build composes from them there.
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/osbuild/image-builder/pkg/arch"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+)
+
+// validSourceBundleName checks that name is a file name, workers only read
+// the bundles in their source bundle directory
+func validSourceBundleName(name string) bool {
+	return name != "" && name != "." && name != ".." && path.Base(name) == name
+}
+
+func (h *apiHandlers) PostComposeSourceBundle(ctx echo.Context, jobId uuid.UUID) error {
+	return h.server.EnsureJobChannel(h.postComposeSourceBundleImpl)(ctx, jobId)
+}
+
+func (h *apiHandlers) postComposeSourceBundleImpl(ctx echo.Context, jobId uuid.UUID) error {
+	channel, err := h.server.getTenantChannel(ctx)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorTenantNotFound, err)
+	}
+
+	jobType, err := h.server.workers.JobType(jobId)
+	if err != nil {
+		return HTTPError(ErrorComposeNotFound)
+	}
+	if jobType != worker.JobTypeOSBuild {
+		return HTTPError(ErrorInvalidJobType)
+	}
+
+	var buildJob worker.OSBuildJob
+	err = h.server.workers.OSBuildJob(jobId, &buildJob)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorComposeNotFound, err)
+	}
+	if len(buildJob.Targets) != 1 {
+		return HTTPError(ErrorSeveralUploadTargets)
+	}
+
+	exportJob := &worker.SourceBundleExportJob{
+		Manifest:       buildJob.Manifest,
+		ExportName:     buildJob.Targets[0].OsbuildArtifact.ExportName,
+		ExportFilename: buildJob.Targets[0].OsbuildArtifact.ExportFilename,
+	}
+
+	// the export job reads the manifest from the manifest job of the
+	// compose if the build job doesn't have it
+	var deps []uuid.UUID
+	if len(buildJob.Manifest) == 0 {
+		var buildResult worker.OSBuildJobResult
+		buildInfo, err := h.server.workers.OSBuildJobInfo(jobId, &buildResult)
+		if err != nil {
+			return HTTPErrorWithInternal(ErrorGettingOSBuildJobStatus, err)
+		}
+		for _, dep := range buildInfo.Deps {
+			depType, err := h.server.workers.JobType(dep)
+			if err != nil {
+				return HTTPErrorWithInternal(ErrorGettingJobType, err)
+			}
+			if depType == worker.JobTypeManifestIDOnly || depType == worker.JobTypeImageBuilderManifest {
+				deps = []uuid.UUID{dep}
+				break
+			}
+		}
+		if len(deps) == 0 {
+			return HTTPErrorWithInternal(ErrorComposeNotFound, fmt.Errorf("job %q has no manifest job", jobId))
+		}
+	}
+
+	id, err := h.server.workers.EnqueueSourceBundleExportJob(ctx.Request().Context(), exportJob, deps, channel)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorEnqueueingJob, err)
+	}
+
+	return ctx.JSON(http.StatusCreated, SourceBundleId{
+		Href: fmt.Sprintf("/api/image-builder-composer/v2/source-bundles/%v", id),
+		Id:   id,
+		Kind: "SourceBundleId",
+	})
+}
+
+func (h *apiHandlers) GetSourceBundleStatus(ctx echo.Context, jobId uuid.UUID) error {
+	return h.server.EnsureJobChannel(h.getSourceBundleStatusImpl)(ctx, jobId)
+}
+
+func (h *apiHandlers) getSourceBundleStatusImpl(ctx echo.Context, jobId uuid.UUID) error {
+	jobType, err := h.server.workers.JobType(jobId)
+	if err != nil {
+		return HTTPError(ErrorComposeNotFound)
+	}
+
+	status := SourceBundleStatus{
+		Href: fmt.Sprintf("/api/image-builder-composer/v2/source-bundles/%v", jobId),
+		Id:   jobId.String(),
+		Kind: "SourceBundleStatus",
+	}
+	switch jobType {
+	case worker.JobTypeSourceBundleExport:
+		var result worker.SourceBundleExportJobResult
+		info, err := h.server.workers.SourceBundleExportJobInfo(jobId, &result)
+		if err != nil {
+			return HTTPErrorWithInternal(ErrorGettingSourceBundleJobStatus, err)
+		}
+		status.Status = uploadStatusFromJobStatus(info.JobStatus, result.JobError)
+		if status.Status == Success {
+			status.Sources = &result.Sources
+			status.Size = &result.Size
+		}
+	case worker.JobTypeSourceBundleImport:
+		var result worker.SourceBundleImportJobResult
+		info, err := h.server.workers.SourceBundleImportJobInfo(jobId, &result)
+		if err != nil {
+			return HTTPErrorWithInternal(ErrorGettingSourceBundleJobStatus, err)
+		}
+		status.Status = uploadStatusFromJobStatus(info.JobStatus, result.JobError)
+		if status.Status == Success {
+			status.Sources = &result.Sources
+		}
+	default:
+		return HTTPError(ErrorInvalidJobType)
+	}
+
+	return ctx.JSON(http.StatusOK, status)
+}
+
+func (h *apiHandlers) GetSourceBundleDownload(ctx echo.Context, jobId uuid.UUID) error {
+	return h.server.EnsureJobChannel(h.getSourceBundleDownloadImpl)(ctx, jobId)
+}
+
+func (h *apiHandlers) getSourceBundleDownloadImpl(ctx echo.Context, jobId uuid.UUID) error {
+	jobType, err := h.server.workers.JobType(jobId)
+	if err != nil {
+		return HTTPError(ErrorComposeNotFound)
+	}
+	if jobType != worker.JobTypeSourceBundleExport {
+		return HTTPError(ErrorInvalidJobType)
+	}
+
+	var result worker.SourceBundleExportJobResult
+	info, err := h.server.workers.SourceBundleExportJobInfo(jobId, &result)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorGettingSourceBundleJobStatus, err)
+	}
+	if uploadStatusFromJobStatus(info.JobStatus, result.JobError) != Success {
+		return HTTPError(ErrorComposeBadState)
+	}
+
+	file, err := h.server.workers.JobArtifactLocation(jobId, worker.SourceBundleArtifact)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorArtifactNotFound, err)
+	}
+	return ctx.Attachment(file, fmt.Sprintf("%s-%s", jobId, worker.SourceBundleArtifact))
+}
+
+func (h *apiHandlers) PostSourceBundleImport(ctx echo.Context) error {
+	channel, err := h.server.getTenantChannel(ctx)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorTenantNotFound, err)
+	}
+
+	var request SourceBundleImportRequest
+	err = ctx.Bind(&request)
+	if err != nil {
+		return err
+	}
+	if !validSourceBundleName(request.Name) {
+		return HTTPError(ErrorInvalidSourceBundleName)
+	}
+
+	id, err := h.server.workers.EnqueueSourceBundleImportJob(ctx.Request().Context(), &worker.SourceBundleImportJob{
+		Name: request.Name,
+	}, channel)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorEnqueueingJob, err)
+	}
+
+	return ctx.JSON(http.StatusCreated, SourceBundleId{
+		Href: fmt.Sprintf("/api/image-builder-composer/v2/source-bundles/%v", id),
+		Id:   id,
+		Kind: "SourceBundleId",
+	})
+}
+
+func (h *apiHandlers) PostSourceBundleCompose(ctx echo.Context) error {
+	channel, err := h.server.getTenantChannel(ctx)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorTenantNotFound, err)
+	}
+
+	var request SourceBundleComposeRequest
+	err = ctx.Bind(&request)
+	if err != nil {
+		return err
+	}
+	if !validSourceBundleName(request.Name) {
+		return HTTPError(ErrorInvalidSourceBundleName)
+	}
+	canonicalArch, err := arch.FromString(request.Architecture)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorUnsupportedArchitecture, err)
+	}
+
+	// the worker takes the manifest and the image from the bundle
+	id, err := h.server.workers.EnqueueOSBuild(ctx.Request().Context(), canonicalArch.String(), &worker.OSBuildJob{
+		SourceBundle: request.Name,
+	}, channel)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorEnqueueingJob, err)
+	}
+
+	return ctx.JSON(http.StatusCreated, &ComposeId{
+		Href: "/api/image-builder-composer/v2/source-bundles/compose",
+		Id:   id,
+		Kind: "ComposeId",
+	})
+}
@@ -0,0 +1,134 @@
+package v2_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/audit"
+	v2 "github.com/osbuild/osbuild-composer/internal/cloudapi/v2"
+	"github.com/osbuild/osbuild-composer/internal/test"
+)
+
+func TestAuditLog(t *testing.T) {
+	dir := t.TempDir()
+	auditLog, err := audit.NewLog(filepath.Join(dir, "audit.jsonl"), audit.Options{})
+	require.NoError(t, err)
+	defer auditLog.Close()
+
+	apiServer, _, _, cancel := newV2Server(t, dir, &v2ServerOpts{
+		enableJWT: true,
+		roles: &v2.RoleMapping{
+			JWTRoleFields: []string{"groups"},
+			Roles: map[string]v2.Role{
+				"image-builder-users":  v2.RoleComposer,
+				"image-builder-admins": v2.RoleAdmin,
+			},
+			DefaultRole: v2.RoleViewer,
+		},
+		audit: auditLog,
+	})
+	handler := apiServer.Handler("/api/image-builder-composer/v2")
+	defer cancel()
+
+	composer := roleContext("42", "image-builder-users")
+	admin := roleContext("42", "image-builder-admins")
+
+	body := test.APICall{
+		Handler:        handler,
+		Context:        composer,
+		Method:         http.MethodPost,
+		Path:           "/api/image-builder-composer/v2/compose",
+		RequestBody:    test.JSONRequestBody(s3Request()),
+		ExpectedStatus: http.StatusCreated,
+	}.Do(t).Body
+	var composeId v2.ComposeId
+	require.NoError(t, json.Unmarshal(body, &composeId))
+
+	// reads aren't audited, denied calls are
+	test.APICall{
+		Handler:        handler,
+		Context:        composer,
+		Method:         http.MethodGet,
+		Path:           "/api/image-builder-composer/v2/composes/" + composeId.Id.String(),
+		ExpectedStatus: http.StatusOK,
+	}.Do(t)
+	test.APICall{
+		Handler:        handler,
+		Context:        composer,
+		Method:         http.MethodDelete,
+		Path:           "/api/image-builder-composer/v2/composes/" + composeId.Id.String(),
+		ExpectedStatus: http.StatusForbidden,
+	}.Do(t)
+
+	// calls of other tenants are not visible
+	test.APICall{
+		Handler:        handler,
+		Context:        roleContext("43", "image-builder-users"),
+		Method:         http.MethodPost,
+		Path:           "/api/image-builder-composer/v2/compose",
+		RequestBody:    test.JSONRequestBody(s3Request()),
+		ExpectedStatus: http.StatusCreated,
+	}.Do(t)
+
+	// only admins may read the audit log
+	test.APICall{
+		Handler:        handler,
+		Context:        composer,
+		Method:         http.MethodGet,
+		Path:           "/api/image-builder-composer/v2/audit",
+		ExpectedStatus: http.StatusForbidden,
+	}.Do(t)
+
+	body = test.APICall{
+		Handler:        handler,
+		Context:        admin,
+		Method:         http.MethodGet,
+		Path:           "/api/image-builder-composer/v2/audit",
+		ExpectedStatus: http.StatusOK,
+	}.Do(t).Body
+	var auditLogReply v2.AuditLog
+	require.NoError(t, json.Unmarshal(body, &auditLogReply))
+	require.Len(t, auditLogReply.Items, 2)
+
+	created := auditLogReply.Items[0]
+	require.Equal(t, "cloudapi", created.Api)
+	require.Equal(t, "postCompose", created.Action)
+	require.Equal(t, http.StatusCreated, created.Status)
+	require.Equal(t, audit.OutcomeSuccess, created.Outcome)
+	require.NotNil(t, created.OperationId)
+	require.NotNil(t, created.Details)
+	require.Equal(t, composeId.Id.String(), (*created.Details)["compose_id"])
+
+	denied := auditLogReply.Items[1]
+	require.Equal(t, "deleteCompose", denied.Action)
+	require.Equal(t, http.StatusForbidden, denied.Status)
+	require.Equal(t, audit.OutcomeFailure, denied.Outcome)
+
+	body = test.APICall{
+		Handler:        handler,
+		Context:        admin,
+		Method:         http.MethodGet,
+		Path:           "/api/image-builder-composer/v2/audit?action=deleteCompose",
+		ExpectedStatus: http.StatusOK,
+	}.Do(t).Body
+	require.NoError(t, json.Unmarshal(body, &auditLogReply))
+	require.Len(t, auditLogReply.Items, 1)
+	require.Equal(t, "deleteCompose", auditLogReply.Items[0].Action)
+}
+
+func TestAuditLogUnavailable(t *testing.T) {
+	apiServer, _, _, cancel := newV2Server(t, t.TempDir(), nil)
+	handler := apiServer.Handler("/api/image-builder-composer/v2")
+	defer cancel()
+
+	test.TestRoute(t, handler, false, "GET", "/api/image-builder-composer/v2/audit", ``, http.StatusBadRequest, `
+	{
+		"kind": "Error",
+		"id": "55",
+		"code": "IMAGE-BUILDER-COMPOSER-55"
+	}`, "operation_id", "reason", "href", "details")
+}
@@ -360,3 +360,34 @@ func TestStagesToPackageMetadata(t *testing.T) {
 		assert.Equal(tc.pkgs, stagesToPackageMetadata(tc.stages), "mismatch in test case %d", idx)
 	}
 }
+
+func TestOperationRoles(t *testing.T) {
+	spec, err := GetSwagger()
+	require.NoError(t, err)
+
+	// every operation needs an explicit role, so that new routes don't
+	// silently become admin only
+	operations := map[string]bool{}
+	for path, item := range spec.Paths.Map() {
+		for method, operation := range item.Operations() {
+			_, ok := operationRoles[operation.OperationID]
+			assert.Truef(t, ok, "%s %s (%s) has no role", method, path, operation.OperationID)
+			operations[operation.OperationID] = true
+		}
+	}
+	for operationID := range operationRoles {
+		assert.Truef(t, operations[operationID], "%s is not an operation of the API", operationID)
+	}
+
+	assert.Equal(t, RoleAdmin, requiredRole("unknownOperation"))
+}
+
+func TestParseRole(t *testing.T) {
+	for _, role := range []Role{RoleNone, RoleViewer, RoleComposer, RoleAdmin} {
+		parsed, err := ParseRole(role.String())
+		require.NoError(t, err)
+		assert.Equal(t, role, parsed)
+	}
+	_, err := ParseRole("root")
+	assert.Error(t, err)
+}
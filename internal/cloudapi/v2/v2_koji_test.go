@@ -602,7 +602,7 @@ func TestKojiJobTypeValidation(t *testing.T) {
 		Version: "42",
 		Release: "1",
 	}
-	initID, err := workers.EnqueueKojiInit(&initJob, "")
+	initID, err := workers.EnqueueKojiInit(context.Background(), &initJob, "")
 	require.NoError(t, err)
 
 	manifest, err := json.Marshal(osbuild.Manifest{})
@@ -627,7 +627,7 @@ func TestKojiJobTypeValidation(t *testing.T) {
 			// TODO: use dependent depsolve and manifests jobs instead
 			Manifest: manifest,
 		}
-		buildID, err := workers.EnqueueOSBuildAsDependency(fmt.Sprintf("fake-arch-%d", idx), &buildJob, []uuid.UUID{initID}, "")
+		buildID, err := workers.EnqueueOSBuildAsDependency(context.Background(), fmt.Sprintf("fake-arch-%d", idx), &buildJob, []uuid.UUID{initID}, "")
 		require.NoError(t, err)
 
 		buildJobs[idx] = buildJob
@@ -645,7 +645,7 @@ func TestKojiJobTypeValidation(t *testing.T) {
 		TaskID:        0,
 		StartTime:     uint64(time.Now().Unix()), // nolint: gosec
 	}
-	finalizeID, err := workers.EnqueueKojiFinalize(&finalizeJob, initID, buildJobIDs, "")
+	finalizeID, err := workers.EnqueueKojiFinalize(context.Background(), &finalizeJob, initID, buildJobIDs, "")
 	require.NoError(t, err)
 
 	// ----- Jobs queued - Test API endpoints (status, manifests, logs) ----- //
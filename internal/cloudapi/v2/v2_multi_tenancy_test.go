@@ -18,7 +18,9 @@ import (
 	"github.com/osbuild/osbuild-composer/pkg/jobqueue"
 
 	"github.com/osbuild/image-builder/pkg/distro/test_distro"
+	"github.com/osbuild/image-builder/pkg/osbuild"
 	v2 "github.com/osbuild/osbuild-composer/internal/cloudapi/v2"
+	"github.com/osbuild/osbuild-composer/internal/target"
 	"github.com/osbuild/osbuild-composer/internal/test"
 	"github.com/osbuild/osbuild-composer/internal/worker"
 	"github.com/osbuild/osbuild-composer/internal/worker/api"
@@ -446,3 +448,165 @@ func TestBootcMultitenancyPreManifestProcessed(t *testing.T) {
 		"BootcPreManifest job should have been started by bootcPreManifestLoop via RequestJobAnyChannel",
 	)
 }
+
+func TestComposeShares(t *testing.T) {
+	apiServer, workerServer, q, cancel := newV2Server(t, t.TempDir(), &v2ServerOpts{enableJWT: true})
+	handler := apiServer.Handler("/api/image-builder-composer/v2")
+	defer cancel()
+
+	id := scheduleRequest(t, handler, "42", s3Request())
+	jobIDs := getAllJobsOfCompose(t, q, id)
+	// depsolve and osbuild
+	for i := 0; i < 2; i++ {
+		runNextJob(t, jobIDs, workerServer, "42")
+	}
+
+	composePath := "/api/image-builder-composer/v2/composes/" + id.String()
+	call := func(orgID, method, path, body string, status int) []byte {
+		c := test.APICall{
+			Handler:        handler,
+			Method:         method,
+			Context:        reqContext(orgID),
+			Path:           path,
+			ExpectedStatus: status,
+		}
+		if body != "" {
+			c.RequestBody = test.JSONRequestBody(body)
+		}
+		return c.Do(t).Body
+	}
+
+	// composes aren't shared by default
+	call("123", http.MethodGet, composePath, "", http.StatusNotFound)
+
+	// only the owner can share a compose, and only with other tenants
+	call("123", http.MethodPost, composePath+"/shares", `{"channel": "org-123", "permission": "read"}`, http.StatusNotFound)
+	call("42", http.MethodPost, composePath+"/shares", `{"channel": "org-42", "permission": "read"}`, http.StatusBadRequest)
+	call("42", http.MethodPost, composePath+"/shares", `{"channel": "org-123", "permission": "write"}`, http.StatusBadRequest)
+
+	body := call("42", http.MethodPost, composePath+"/shares", `{"channel": "org-123", "permission": "read"}`, http.StatusCreated)
+	var share v2.ComposeShare
+	require.NoError(t, json.Unmarshal(body, &share))
+	require.Equal(t, "org-123", share.Channel)
+	require.Equal(t, v2.Read, share.Permission)
+	require.Equal(t, composePath+"/shares/org-123", share.Href)
+
+	// read grants the status, metadata, SBOMs and download
+	call("123", http.MethodGet, composePath, "", http.StatusOK)
+	call("123", http.MethodGet, composePath+"/metadata", "", http.StatusOK)
+	// but nothing else
+	call("123", http.MethodGet, composePath+"/logs", "", http.StatusNotFound)
+	call("123", http.MethodGet, composePath+"/manifests", "", http.StatusNotFound)
+	call("123", http.MethodGet, composePath+"/shares", "", http.StatusNotFound)
+	call("123", http.MethodPost, composePath+"/clone", `{"region": "us-east-2"}`, http.StatusNotFound)
+	call("123", http.MethodDelete, composePath+"/shares/org-123", "", http.StatusNotFound)
+	call("123", http.MethodDelete, composePath, "", http.StatusNotFound)
+	// other tenants still can't see the compose
+	call("2022", http.MethodGet, composePath, "", http.StatusNotFound)
+
+	// sharing again replaces the permission
+	call("42", http.MethodPost, composePath+"/shares", `{"channel": "org-123", "permission": "clone"}`, http.StatusCreated)
+	call("42", http.MethodPost, composePath+"/shares", `{"channel": "org-2022", "permission": "read"}`, http.StatusCreated)
+	body = call("42", http.MethodGet, composePath+"/shares", "", http.StatusOK)
+	var shares v2.ComposeShareList
+	require.NoError(t, json.Unmarshal(body, &shares))
+	require.Equal(t, 2, shares.Total)
+	require.Equal(t, "org-123", shares.Items[0].Channel)
+	require.Equal(t, v2.Clone, shares.Items[0].Permission)
+	require.Equal(t, "org-2022", shares.Items[1].Channel)
+	require.Equal(t, v2.Read, shares.Items[1].Permission)
+
+	// clone includes read
+	call("123", http.MethodGet, composePath, "", http.StatusOK)
+	call("2022", http.MethodGet, composePath, "", http.StatusOK)
+
+	call("42", http.MethodDelete, composePath+"/shares/org-123", "", http.StatusOK)
+	call("42", http.MethodDelete, composePath+"/shares/org-123", "", http.StatusNotFound)
+	call("123", http.MethodGet, composePath, "", http.StatusNotFound)
+	call("2022", http.MethodGet, composePath, "", http.StatusOK)
+}
+
+func TestCloneComposeCloudCredentials(t *testing.T) {
+	apiServer, workerServer, q, cancel := newV2Server(t, t.TempDir(), &v2ServerOpts{enableJWT: true})
+	handler := apiServer.Handler("/api/image-builder-composer/v2")
+	defer cancel()
+
+	call := func(orgID, method, path, body string, status int) []byte {
+		return test.APICall{
+			Handler:        handler,
+			Method:         method,
+			Context:        reqContext(orgID),
+			Path:           "/api/image-builder-composer/v2" + path,
+			RequestBody:    test.JSONRequestBody(body),
+			ExpectedStatus: status,
+		}.Do(t).Body
+	}
+
+	body := call("42", http.MethodPost, "/cloud-credentials", `
+	{
+		"name": "tenant-aws",
+		"aws": {
+			"role_arn": "arn:aws:iam::123456789012:role/image-builder",
+			"bucket": "images"
+		}
+	}`, http.StatusCreated)
+	var creds v2.CloudCredentials
+	require.NoError(t, json.Unmarshal(body, &creds))
+
+	id := scheduleRequest(t, handler, "42", fmt.Sprintf(`
+		{
+			"distribution": "%s",
+			"image_request": {
+				"architecture": "%s",
+				"image_type": "aws",
+				"repositories": [{"baseurl": "https://repo.example.com/"}],
+				"upload_options": {
+					"region": "eu-central-1",
+					"credentials_id": "%s"
+				}
+			}
+		}`, test_distro.TestDistro1Name, test_distro.TestArch3Name, creds.Id))
+	runNextJob(t, getAllJobsOfCompose(t, q, id), workerServer, "42")
+
+	_, token, _, _, _, err := workerServer.RequestJob(context.Background(), test_distro.TestArch3Name, []string{worker.JobTypeOSBuild}, []string{"org-42"}, uuid.Nil)
+	require.NoError(t, err)
+	res, err := json.Marshal(&worker.OSBuildJobResult{
+		Success:       true,
+		OSBuildOutput: &osbuild.Result{Success: true},
+		TargetResults: []*target.TargetResult{
+			target.NewAWSTargetResult(&target.AWSTargetResultOptions{
+				Ami:    "ami-abc123",
+				Region: "eu-central-1",
+			}, &target.OsbuildArtifact{ExportFilename: "image.raw", ExportName: "image"}),
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, workerServer.FinishJob(token, res))
+
+	// the image is in the tenant's account, other tenants can't clone it
+	// even if the compose is shared with them
+	composePath := "/composes/" + id.String()
+	call("42", http.MethodPost, composePath+"/shares", `{"channel": "org-123", "permission": "clone"}`, http.StatusCreated)
+	body = call("123", http.MethodPost, composePath+"/clone", `{"region": "eu-central-2"}`, http.StatusBadRequest)
+	require.Contains(t, string(body), "IMAGE-BUILDER-COMPOSER-58")
+
+	// the copy and share jobs of the tenant get the credentials
+	call("42", http.MethodPost, composePath+"/clone", `{"region": "eu-central-2", "share_with_accounts": ["123456789012"]}`, http.StatusCreated)
+
+	_, token, _, args, _, err := workerServer.RequestJob(context.Background(), test_distro.TestArch3Name, []string{worker.JobTypeAWSEC2Copy}, []string{"org-42"}, uuid.Nil)
+	require.NoError(t, err)
+	var copyJob worker.AWSEC2CopyJob
+	require.NoError(t, json.Unmarshal(args, &copyJob))
+	require.NotNil(t, copyJob.CloudCredentials)
+	require.Equal(t, creds.Id, copyJob.CloudCredentials.String())
+	res, err = json.Marshal(&worker.AWSEC2CopyJobResult{Ami: "ami-def456", Region: "eu-central-2"})
+	require.NoError(t, err)
+	require.NoError(t, workerServer.FinishJob(token, res))
+
+	_, _, _, args, _, err = workerServer.RequestJob(context.Background(), test_distro.TestArch3Name, []string{worker.JobTypeAWSEC2Share}, []string{"org-42"}, uuid.Nil)
+	require.NoError(t, err)
+	var shareJob worker.AWSEC2ShareJob
+	require.NoError(t, json.Unmarshal(args, &shareJob))
+	require.NotNil(t, shareJob.CloudCredentials)
+	require.Equal(t, creds.Id, shareJob.CloudCredentials.String())
+}
@@ -0,0 +1,125 @@
+package v2_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/openshift-online/ocm-sdk-go/authentication"
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/auth"
+	v2 "github.com/osbuild/osbuild-composer/internal/cloudapi/v2"
+	"github.com/osbuild/osbuild-composer/internal/test"
+)
+
+func roleContext(orgID string, groups ...interface{}) context.Context {
+	return authentication.ContextWithToken(context.Background(), &jwt.Token{
+		Claims: jwt.MapClaims{
+			"rh-org-id": orgID,
+			"groups":    groups,
+		},
+	})
+}
+
+func TestRoles(t *testing.T) {
+	apiServer, _, _, cancel := newV2Server(t, t.TempDir(), &v2ServerOpts{
+		enableJWT: true,
+		roles: &v2.RoleMapping{
+			JWTRoleFields: []string{"groups"},
+			Roles: map[string]v2.Role{
+				"image-builder-viewers":  v2.RoleViewer,
+				"image-builder-users":    v2.RoleComposer,
+				"image-builder-admins":   v2.RoleAdmin,
+				"image-builder-disabled": v2.RoleNone,
+			},
+			DefaultRole: v2.RoleViewer,
+		},
+	})
+	handler := apiServer.Handler("/api/image-builder-composer/v2")
+	defer cancel()
+
+	forbidden := `{"kind": "Error", "id": "2", "code": "IMAGE-BUILDER-COMPOSER-2"}`
+	call := func(ctx context.Context, method, path, body string, status int, expected string) []byte {
+		result := test.APICall{
+			Handler:        handler,
+			Context:        ctx,
+			Method:         method,
+			Path:           "/api/image-builder-composer/v2" + path,
+			RequestBody:    test.JSONRequestBody(body),
+			ExpectedStatus: status,
+		}
+		if expected != "" {
+			result.ExpectedBody = test.JSONValidator{Content: expected, IgnoreFields: []string{"href", "reason", "operation_id", "details"}}
+		}
+		return result.Do(t).Body
+	}
+
+	// viewers may read, but not compose
+	viewer := roleContext("42", "image-builder-viewers")
+	call(viewer, http.MethodGet, "/composes/", "", http.StatusOK, "")
+	call(viewer, http.MethodPost, "/compose", s3Request(), http.StatusForbidden, forbidden)
+	// callers without a known group get the default role
+	call(roleContext("42", "unknown"), http.MethodPost, "/compose", s3Request(), http.StatusForbidden, forbidden)
+	call(roleContext("42", "unknown"), http.MethodGet, "/composes/", "", http.StatusOK, "")
+	// the highest role of a caller applies
+	disabled := roleContext("42", "image-builder-disabled")
+	call(disabled, http.MethodGet, "/composes/", "", http.StatusForbidden, forbidden)
+	call(roleContext("42", "image-builder-disabled", "image-builder-users"), http.MethodGet, "/composes/", "", http.StatusOK, "")
+	// public routes don't need any role
+	call(disabled, http.MethodGet, "/openapi", "", http.StatusOK, "")
+
+	composer := roleContext("42", "image-builder-users", "image-builder-viewers")
+	body := call(composer, http.MethodPost, "/compose", s3Request(), http.StatusCreated, "")
+	var composeId v2.ComposeId
+	require.NoError(t, json.Unmarshal(body, &composeId))
+
+	// only admins may delete composes
+	call(composer, http.MethodDelete, "/composes/"+composeId.Id.String(), "", http.StatusForbidden, forbidden)
+	call(roleContext("42", "image-builder-admins"), http.MethodDelete, "/composes/"+uuid.NewString(), "", http.StatusNotFound, "")
+}
+
+func TestAPIKeys(t *testing.T) {
+	apiServer, _, q, cancel := newV2Server(t, t.TempDir(), &v2ServerOpts{
+		apiKeys: map[string]auth.APIKey{
+			auth.HashAPIKey("ci-key"):     {Name: "ci", Tenant: "42", Role: "composer"},
+			auth.HashAPIKey("viewer-key"): {Name: "dashboard", Tenant: "42", Role: "viewer"},
+		},
+	})
+	handler := apiServer.Handler("/api/image-builder-composer/v2")
+	defer cancel()
+
+	call := func(key, method, path, body string, status int) []byte {
+		header := http.Header{}
+		if key != "" {
+			header.Set("Authorization", "Bearer "+key)
+		}
+		return test.APICall{
+			Handler:        handler,
+			Header:         header,
+			Method:         method,
+			Path:           "/api/image-builder-composer/v2" + path,
+			RequestBody:    test.JSONRequestBody(body),
+			ExpectedStatus: status,
+		}.Do(t).Body
+	}
+
+	call("", http.MethodGet, "/openapi", "", http.StatusOK)
+	call("", http.MethodGet, "/composes/", "", http.StatusUnauthorized)
+	call("unknown-key", http.MethodGet, "/composes/", "", http.StatusUnauthorized)
+	call("viewer-key", http.MethodGet, "/composes/", "", http.StatusOK)
+	call("viewer-key", http.MethodPost, "/compose", s3Request(), http.StatusForbidden)
+
+	body := call("ci-key", http.MethodPost, "/compose", s3Request(), http.StatusCreated)
+	var composeId v2.ComposeId
+	require.NoError(t, json.Unmarshal(body, &composeId))
+
+	// the compose belongs to the tenant of the service account
+	_, _, _, channel, err := q.Job(composeId.Id)
+	require.NoError(t, err)
+	require.Equal(t, "org-42", channel)
+	call("viewer-key", http.MethodGet, "/composes/"+composeId.Id.String(), "", http.StatusOK)
+}
@@ -27,7 +27,10 @@ import (
 	"github.com/osbuild/image-builder/pkg/reporegistry"
 	"github.com/osbuild/image-builder/pkg/rpmmd"
 	"github.com/osbuild/image-builder/pkg/sbom"
+	"github.com/osbuild/osbuild-composer/internal/audit"
+	"github.com/osbuild/osbuild-composer/internal/auth"
 	v2 "github.com/osbuild/osbuild-composer/internal/cloudapi/v2"
+	"github.com/osbuild/osbuild-composer/internal/cloudcredentials"
 	"github.com/osbuild/osbuild-composer/internal/common"
 	"github.com/osbuild/osbuild-composer/internal/jobqueue/fsjobqueue"
 	"github.com/osbuild/osbuild-composer/internal/target"
@@ -329,11 +332,64 @@ func mockSearch(t *testing.T, workerServer *worker.Server, wg *sync.WaitGroup, f
 	return cancel
 }
 
+func mockRepositoryCheck(t *testing.T, workerServer *worker.Server, wg *sync.WaitGroup, fail bool) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			_, token, _, rawArgs, _, err := workerServer.RequestJob(ctx, test_distro.TestArchName, []string{worker.JobTypeRepositoryCheck}, []string{""}, uuid.Nil)
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+			if err != nil {
+				continue
+			}
+			var args worker.RepositoryCheckJob
+			require.NoError(t, json.Unmarshal(rawArgs, &args))
+
+			result := &worker.RepositoryCheckJobResult{
+				Results: []worker.RepositoryCheckResultItem{},
+			}
+			for _, repo := range args.Repositories {
+				item := worker.RepositoryCheckResultItem{
+					URL:          repo.BaseURLs[0],
+					Healthy:      true,
+					Revision:     "1644263915",
+					LastUpdated:  common.ToPtr(time.Unix(1644263915, 0).UTC()),
+					PackageCount: 1125,
+				}
+				// fail reports every repository as unhealthy
+				if fail {
+					item = worker.RepositoryCheckResultItem{
+						URL:      repo.BaseURLs[0],
+						TLSError: "x509: certificate signed by unknown authority",
+					}
+				}
+				result.Results = append(result.Results, item)
+			}
+
+			rawMsg, err := json.Marshal(result)
+			require.NoError(t, err)
+			err = workerServer.FinishJob(token, rawMsg)
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return cancel
+}
+
 type v2ServerOpts struct {
 	enableJWT                     bool
 	fail                          bool
 	ibManifest                    bool // use image-builder-manifest job instead of manifest-id-only
 	bootcUseRemoteContainerSource bool
+	roles                         *v2.RoleMapping
+	apiKeys                       map[string]auth.APIKey
+	audit                         *audit.Log
 }
 
 func newV2Server(t *testing.T, dir string, opts *v2ServerOpts) (*v2.Server, *worker.Server, jobqueue.JobQueue, context.CancelFunc) {
@@ -351,12 +407,16 @@ func newV2Server(t *testing.T, dir string, opts *v2ServerOpts) (*v2.Server, *wor
 	err = os.Mkdir(artifactsDir, 0755)
 	require.NoError(t, err)
 
+	cloudCredentials, err := cloudcredentials.NewRegistry("", nil)
+	require.NoError(t, err)
+
 	workerServer := worker.NewServer(nil, q,
 		worker.Config{
 			ArtifactsDir:         artifactsDir,
 			BasePath:             "/api/worker/v1",
 			JWTEnabled:           opts.enableJWT,
 			TenantProviderFields: []string{"rh-org-id", "account_id"},
+			CloudCredentials:     cloudCredentials,
 		})
 
 	distros := distrofactory.NewTestDefault()
@@ -372,13 +432,16 @@ func newV2Server(t *testing.T, dir string, opts *v2ServerOpts) (*v2.Server, *wor
 		TenantProviderFields:           []string{"rh-org-id", "account_id"},
 		ImageBuilderManifestGeneration: opts.ibManifest,
 		BootcUseRemoteContainerSource:  opts.bootcUseRemoteContainerSource,
+		Roles:                          opts.roles,
+		APIKeys:                        opts.apiKeys,
+		Audit:                          opts.audit,
 	}
 	v2Server := v2.NewServer(workerServer, distros, repos, config)
 	require.NotNil(t, v2Server)
 	t.Cleanup(v2Server.Shutdown)
 
 	// Setup the content resolve job handlers (depsolver, search, ostree commit
-	// resolver, and image-builder manifest).
+	// resolver, image-builder manifest, and repository check).
 	// These are mocked functions that return a static set of results for testing.
 	var wg sync.WaitGroup
 	var cancelFuncs []context.CancelFunc
@@ -387,6 +450,7 @@ func newV2Server(t *testing.T, dir string, opts *v2ServerOpts) (*v2.Server, *wor
 	cancelFuncs = append(cancelFuncs, mockOSTreeResolve(t, workerServer, &wg, opts.fail))
 	cancelFuncs = append(cancelFuncs, mockSearch(t, workerServer, &wg, opts.fail))
 	cancelFuncs = append(cancelFuncs, mockIBManifest(t, workerServer, &wg, opts.fail))
+	cancelFuncs = append(cancelFuncs, mockRepositoryCheck(t, workerServer, &wg, opts.fail))
 
 	cancelWithWait := func() {
 		for _, cancel := range cancelFuncs {
@@ -3483,3 +3547,602 @@ func TestComposeBootc(t *testing.T) {
 		})
 	}
 }
+
+func TestRepositoriesCheck(t *testing.T) {
+	srv, _, _, cancel := newV2Server(t, t.TempDir(), nil)
+	defer cancel()
+
+	test.TestRoute(t, srv.Handler("/api/image-builder-composer/v2"), false, "POST",
+		"/api/image-builder-composer/v2/repositories/check", `
+		{
+			"repositories": [{"baseurl": "https://example.com/repo"}]
+		}`,
+		http.StatusOK, `
+		{
+			"results": [
+				{
+					"healthy": true,
+					"url": "https://example.com/repo",
+					"revision": "1644263915",
+					"last_updated": "2022-02-07T19:58:35Z",
+					"package_count": 1125
+				}
+			]
+		}`)
+}
+
+func TestRepositoriesCheckUnhealthy(t *testing.T) {
+	srv, _, _, cancel := newV2Server(t, t.TempDir(), &v2ServerOpts{fail: true})
+	defer cancel()
+
+	test.TestRoute(t, srv.Handler("/api/image-builder-composer/v2"), false, "POST",
+		"/api/image-builder-composer/v2/repositories/check", `
+		{
+			"repositories": [{"baseurl": "https://example.com/repo"}]
+		}`,
+		http.StatusOK, `
+		{
+			"results": [
+				{
+					"healthy": false,
+					"url": "https://example.com/repo",
+					"package_count": 0,
+					"tls_error": "x509: certificate signed by unknown authority"
+				}
+			]
+		}`)
+}
+
+func TestRepositoriesCheckErrors(t *testing.T) {
+	srv, _, _, cancel := newV2Server(t, t.TempDir(), nil)
+	defer cancel()
+
+	// Repository without any url
+	test.TestRoute(t, srv.Handler("/api/image-builder-composer/v2"), false, "POST",
+		"/api/image-builder-composer/v2/repositories/check", `
+		{
+			"repositories": [{"check_gpg": true}]
+		}`,
+		http.StatusBadRequest, `
+		{
+			"href": "/api/image-builder-composer/v2/errors/7",
+			"id": "7",
+			"kind": "Error",
+			"code": "IMAGE-BUILDER-COMPOSER-7",
+			"reason": "Must specify baseurl, mirrorlist, or metalink"
+		}`, "operation_id", "details")
+}
+
+func TestComposeLockfile(t *testing.T) {
+	srv, wrksrv, q, cancel := newV2Server(t, t.TempDir(), nil)
+	defer cancel()
+
+	composeRequest := func(lockfile string) string {
+		return fmt.Sprintf(`
+		{
+			"distribution": "%s",
+			"image_request":{
+				"architecture": "%s",
+				"image_type": "aws",
+				"repositories": [{
+					"baseurl": "somerepo.org",
+					"rhsm": false
+				}],
+				"upload_options": {
+					"region": "eu-central-1"
+				}
+			}%s
+		}`, test_distro.TestDistro1Name, test_distro.TestArch3Name, lockfile)
+	}
+
+	test.TestRoute(t, srv.Handler("/api/image-builder-composer/v2"), false, "POST", "/api/image-builder-composer/v2/compose", composeRequest(""), http.StatusCreated, `
+	{
+		"href": "/api/image-builder-composer/v2/compose",
+		"kind": "ComposeId"
+	}`, "id")
+
+	jobId, token, _, _, _, err := wrksrv.RequestJob(context.Background(), test_distro.TestArch3Name, []string{worker.JobTypeOSBuild}, []string{""}, uuid.Nil)
+	require.NoError(t, err)
+
+	// the compose needs to finish successfully first
+	test.TestRoute(t, srv.Handler("/api/image-builder-composer/v2"), false, "GET", fmt.Sprintf("/api/image-builder-composer/v2/composes/%v/lockfile", jobId), ``, http.StatusBadRequest, `
+	{
+		"href": "/api/image-builder-composer/v2/errors/31",
+		"id": "31",
+		"kind": "Error",
+		"code": "IMAGE-BUILDER-COMPOSER-31",
+		"reason": "Compose is running or has failed"
+	}`, "operation_id", "details")
+
+	res, err := json.Marshal(&worker.OSBuildJobResult{
+		Success:       true,
+		OSBuildOutput: &osbuild.Result{Success: true},
+	})
+	require.NoError(t, err)
+	require.NoError(t, wrksrv.FinishJob(token, res))
+
+	pkg1 := `{
+		"name": "pkg1",
+		"epoch": 0,
+		"version": "1.33",
+		"release": "2.fc30",
+		"arch": "x86_64",
+		"checksum": "sha256:e50ddb78a37f5851d1a5c37a4c77d59123153c156e628e064b9daa378f45a2fe",
+		"remote_locations": ["https://pkg1.example.com/1.33-2.fc30.x86_64.rpm"],
+		"repo_url": "https://example.com/repo"
+	}`
+	pkg2 := `{
+		"name": "pkg2",
+		"epoch": 0,
+		"version": "1.34",
+		"release": "3.fc30",
+		"arch": "x86_64",
+		"checksum": "sha256:ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+		"remote_locations": ["https://pkg2.example.com/1.34-3.fc30.x86_64.rpm"],
+		"repo_url": "https://example.com/repo"
+	}`
+	lockfile := fmt.Sprintf(`{
+		"version": 1,
+		"pipelines": {
+			"build": [[%[1]s]],
+			"os": [[%[1]s], [%[2]s]]
+		}
+	}`, pkg1, pkg2)
+	test.TestRoute(t, srv.Handler("/api/image-builder-composer/v2"), false, "GET", fmt.Sprintf("/api/image-builder-composer/v2/composes/%v/lockfile", jobId), ``, http.StatusOK, fmt.Sprintf(`
+	{
+		"href": "/api/image-builder-composer/v2/composes/%v/lockfile",
+		"id": "%v",
+		"kind": "ComposeLockfile",
+		"lockfile": %s
+	}`, jobId, jobId, lockfile))
+
+	// a compose with the lockfile depsolves exactly the locked packages
+	reply := test.TestRouteWithReply(t, srv.Handler("/api/image-builder-composer/v2"), false, "POST", "/api/image-builder-composer/v2/compose", composeRequest(`, "lockfile": `+lockfile), http.StatusCreated, `
+	{
+		"href": "/api/image-builder-composer/v2/compose",
+		"kind": "ComposeId"
+	}`, "id")
+	var composeId v2.ComposeId
+	require.NoError(t, json.Unmarshal(reply, &composeId))
+
+	_, _, deps, _, err := q.Job(composeId.Id)
+	require.NoError(t, err)
+	require.Len(t, deps, 1)
+	_, _, deps, _, err = q.Job(deps[0])
+	require.NoError(t, err)
+	require.Len(t, deps, 1)
+	_, rawArgs, _, _, err := q.Job(deps[0])
+	require.NoError(t, err)
+	var depsolveJob worker.DepsolveJob
+	require.NoError(t, json.Unmarshal(rawArgs, &depsolveJob))
+	require.NotNil(t, depsolveJob.Lockfile)
+	require.Equal(t, []string{"pkg1-1.33-2.fc30.x86_64"}, depsolveJob.PackageSets["build"][0].Include)
+	require.Len(t, depsolveJob.PackageSets["os"], 2)
+	require.Equal(t, []string{"pkg1-1.33-2.fc30.x86_64"}, depsolveJob.PackageSets["os"][0].Include)
+	require.Equal(t, []string{"pkg2-1.34-3.fc30.x86_64"}, depsolveJob.PackageSets["os"][1].Include)
+	require.Nil(t, depsolveJob.PackageSets["os"][0].Exclude)
+
+	// the lockfile needs to lock all pipelines of the image
+	partialLockfile := fmt.Sprintf(`{"version": 1, "pipelines": {"os": [[%s], [%s]]}}`, pkg1, pkg2)
+	test.TestRoute(t, srv.Handler("/api/image-builder-composer/v2"), false, "POST", "/api/image-builder-composer/v2/compose", composeRequest(`, "lockfile": `+partialLockfile), http.StatusBadRequest, `
+	{
+		"href": "/api/image-builder-composer/v2/errors/48",
+		"id": "48",
+		"kind": "Error",
+		"code": "IMAGE-BUILDER-COMPOSER-48",
+		"reason": "Invalid lockfile, it doesn't match the requested image"
+	}`, "operation_id", "details")
+
+	test.TestRoute(t, srv.Handler("/api/image-builder-composer/v2"), false, "POST", "/api/image-builder-composer/v2/compose", composeRequest(`, "lockfile": {"version": 2, "pipelines": {}}`), http.StatusBadRequest, `
+	{
+		"href": "/api/image-builder-composer/v2/errors/48",
+		"id": "48",
+		"kind": "Error",
+		"code": "IMAGE-BUILDER-COMPOSER-48",
+		"reason": "Invalid lockfile, it doesn't match the requested image"
+	}`, "operation_id", "details")
+}
+
+func TestComposeDiff(t *testing.T) {
+	srv, wrksrv, _, cancel := newV2Server(t, t.TempDir(), nil)
+	defer cancel()
+
+	compose := func(hostname string) uuid.UUID {
+		test.TestRoute(t, srv.Handler("/api/image-builder-composer/v2"), false, "POST", "/api/image-builder-composer/v2/compose", fmt.Sprintf(`
+		{
+			"distribution": "%s",
+			"customizations": {
+				"hostname": "%s"
+			},
+			"image_request":{
+				"architecture": "%s",
+				"image_type": "aws",
+				"repositories": [{
+					"baseurl": "somerepo.org",
+					"rhsm": false
+				}],
+				"upload_options": {
+					"region": "eu-central-1"
+				}
+			}
+		}`, test_distro.TestDistro1Name, hostname, test_distro.TestArch3Name), http.StatusCreated, `
+		{
+			"href": "/api/image-builder-composer/v2/compose",
+			"kind": "ComposeId"
+		}`, "id")
+
+		jobId, token, _, _, _, err := wrksrv.RequestJob(context.Background(), test_distro.TestArch3Name, []string{worker.JobTypeOSBuild}, []string{""}, uuid.Nil)
+		require.NoError(t, err)
+		res, err := json.Marshal(&worker.OSBuildJobResult{
+			Success:       true,
+			OSBuildOutput: &osbuild.Result{Success: true},
+			PipelineNames: &worker.PipelineNames{
+				Build:   []string{"build"},
+				Payload: []string{"os"},
+			},
+		})
+		require.NoError(t, err)
+		require.NoError(t, wrksrv.FinishJob(token, res))
+		return jobId
+	}
+
+	jobId := compose("host-a")
+	otherId := compose("host-b")
+
+	test.TestRoute(t, srv.Handler("/api/image-builder-composer/v2"), false, "GET", fmt.Sprintf("/api/image-builder-composer/v2/composes/%v/diff/%v", jobId, otherId), ``, http.StatusOK, fmt.Sprintf(`
+	{
+		"href": "/api/image-builder-composer/v2/composes/%[1]v/diff/%[2]v",
+		"id": "%[1]v",
+		"kind": "ComposeDiff",
+		"other_id": "%[2]v",
+		"packages": {"added": [], "removed": [], "upgraded": [], "downgraded": []},
+		"repositories": {"added": [], "removed": []},
+		"customizations": [
+			{"name": "hostname", "old": "host-a", "new": "host-b"}
+		],
+		"pipelines": {"added": [], "removed": [], "changed": []}
+	}`, jobId, otherId))
+
+	// both composes need to be finished
+	test.TestRoute(t, srv.Handler("/api/image-builder-composer/v2"), false, "POST", "/api/image-builder-composer/v2/compose", fmt.Sprintf(`
+	{
+		"distribution": "%s",
+		"image_request":{
+			"architecture": "%s",
+			"image_type": "aws",
+			"repositories": [{
+				"baseurl": "somerepo.org",
+				"rhsm": false
+			}],
+			"upload_options": {
+				"region": "eu-central-1"
+			}
+		}
+	}`, test_distro.TestDistro1Name, test_distro.TestArch3Name), http.StatusCreated, `
+	{
+		"href": "/api/image-builder-composer/v2/compose",
+		"kind": "ComposeId"
+	}`, "id")
+	pendingId, _, _, _, _, err := wrksrv.RequestJob(context.Background(), test_distro.TestArch3Name, []string{worker.JobTypeOSBuild}, []string{""}, uuid.Nil)
+	require.NoError(t, err)
+
+	test.TestRoute(t, srv.Handler("/api/image-builder-composer/v2"), false, "GET", fmt.Sprintf("/api/image-builder-composer/v2/composes/%v/diff/%v", jobId, pendingId), ``, http.StatusBadRequest, `
+	{
+		"href": "/api/image-builder-composer/v2/errors/31",
+		"id": "31",
+		"kind": "Error",
+		"code": "IMAGE-BUILDER-COMPOSER-31",
+		"reason": "Compose is running or has failed"
+	}`, "operation_id", "details")
+
+	test.TestRoute(t, srv.Handler("/api/image-builder-composer/v2"), false, "GET", fmt.Sprintf("/api/image-builder-composer/v2/composes/%v/diff/%v", jobId, uuid.New()), ``, http.StatusNotFound, `
+	{
+		"href": "/api/image-builder-composer/v2/errors/15",
+		"id": "15",
+		"kind": "Error",
+		"code": "IMAGE-BUILDER-COMPOSER-15",
+		"reason": "Compose with given id not found"
+	}`, "operation_id", "details")
+}
+
+func TestSourceBundle(t *testing.T) {
+	srv, wrksrv, _, cancel := newV2Server(t, t.TempDir(), nil)
+	defer cancel()
+
+	test.TestRoute(t, srv.Handler("/api/image-builder-composer/v2"), false, "POST", "/api/image-builder-composer/v2/compose", fmt.Sprintf(`
+	{
+		"distribution": "%s",
+		"image_requests": [{
+			"architecture": "%s",
+			"image_type": "guest-image",
+			"repositories": [{
+				"baseurl": "somerepo.org",
+				"rhsm": false
+			}],
+			"upload_targets": [{
+				"type": "local",
+				"upload_options": {}
+			}]
+		}]
+	}`, test_distro.TestDistro1Name, test_distro.TestArch3Name), http.StatusCreated, `
+	{
+		"href": "/api/image-builder-composer/v2/compose",
+		"kind": "ComposeId"
+	}`, "id")
+
+	composeId, _, _, _, _, err := wrksrv.RequestJob(context.Background(), test_distro.TestArch3Name, []string{worker.JobTypeOSBuild}, []string{""}, uuid.Nil)
+	require.NoError(t, err)
+
+	reply := test.TestRouteWithReply(t, srv.Handler("/api/image-builder-composer/v2"), false, "POST",
+		fmt.Sprintf("/api/image-builder-composer/v2/composes/%v/source-bundle", composeId), ``, http.StatusCreated, `
+	{
+		"kind": "SourceBundleId"
+	}`, "id", "href")
+	var bundleId v2.SourceBundleId
+	require.NoError(t, json.Unmarshal(reply, &bundleId))
+
+	test.TestRoute(t, srv.Handler("/api/image-builder-composer/v2"), false, "GET",
+		fmt.Sprintf("/api/image-builder-composer/v2/source-bundles/%v", bundleId.Id), ``, http.StatusOK, fmt.Sprintf(`
+	{
+		"href": "/api/image-builder-composer/v2/source-bundles/%v",
+		"id": "%v",
+		"kind": "SourceBundleStatus",
+		"status": "pending"
+	}`, bundleId.Id, bundleId.Id))
+
+	// the export job takes the manifest from the manifest job of the compose
+	jobId, token, jobType, args, dynArgs, err := wrksrv.RequestJob(context.Background(), test_distro.TestArch3Name, []string{worker.JobTypeSourceBundleExport}, []string{""}, uuid.Nil)
+	require.NoError(t, err)
+	require.Equal(t, bundleId.Id, jobId)
+	require.Equal(t, worker.JobTypeSourceBundleExport, jobType)
+	var exportJob worker.SourceBundleExportJob
+	require.NoError(t, json.Unmarshal(args, &exportJob))
+	require.Empty(t, exportJob.Manifest)
+	require.NotEmpty(t, exportJob.ExportName)
+	require.NotEmpty(t, exportJob.ExportFilename)
+	require.Len(t, dynArgs, 1)
+
+	res, err := json.Marshal(&worker.SourceBundleExportJobResult{
+		Sources: 3,
+		Size:    51,
+	})
+	require.NoError(t, err)
+	require.NoError(t, wrksrv.FinishJob(token, res))
+
+	file, err := wrksrv.JobArtifactLocation(jobId, worker.SourceBundleArtifact)
+	require.Error(t, err)
+	// the dummy bundle is json to make TestRoute happy
+	require.NoError(t, os.WriteFile(file, []byte(`{"msg":"This is the source bundle"}`), 0600))
+
+	test.TestRoute(t, srv.Handler("/api/image-builder-composer/v2"), false, "GET",
+		fmt.Sprintf("/api/image-builder-composer/v2/source-bundles/%v", jobId), ``, http.StatusOK, fmt.Sprintf(`
+	{
+		"href": "/api/image-builder-composer/v2/source-bundles/%v",
+		"id": "%v",
+		"kind": "SourceBundleStatus",
+		"status": "success",
+		"sources": 3,
+		"size": 51
+	}`, jobId, jobId))
+
+	test.TestRoute(t, srv.Handler("/api/image-builder-composer/v2"), false, "GET",
+		fmt.Sprintf("/api/image-builder-composer/v2/source-bundles/%v/download", jobId), ``, http.StatusOK, `
+	{
+		"msg": "This is the source bundle"
+	}`)
+
+	// only exports can be downloaded
+	test.TestRoute(t, srv.Handler("/api/image-builder-composer/v2"), false, "GET",
+		fmt.Sprintf("/api/image-builder-composer/v2/source-bundles/%v/download", composeId), ``, http.StatusNotFound, `
+	{
+		"href": "/api/image-builder-composer/v2/errors/26",
+		"id": "26",
+		"kind": "Error",
+		"code": "IMAGE-BUILDER-COMPOSER-26",
+		"reason": "Job with given id has an invalid type"
+	}`, "operation_id", "details")
+}
+
+func TestSourceBundleImportAndCompose(t *testing.T) {
+	srv, wrksrv, _, cancel := newV2Server(t, t.TempDir(), nil)
+	defer cancel()
+
+	for _, route := range []string{"import", "compose"} {
+		test.TestRoute(t, srv.Handler("/api/image-builder-composer/v2"), false, "POST",
+			"/api/image-builder-composer/v2/source-bundles/"+route, fmt.Sprintf(`
+		{
+			"name": "../rhel-9.tar",
+			"architecture": "%s"
+		}`, test_distro.TestArch3Name), http.StatusBadRequest, `
+		{
+			"href": "/api/image-builder-composer/v2/errors/50",
+			"id": "50",
+			"kind": "Error",
+			"code": "IMAGE-BUILDER-COMPOSER-50",
+			"reason": "Invalid source bundle name, it needs to be a file name"
+		}`, "operation_id", "details")
+	}
+
+	test.TestRoute(t, srv.Handler("/api/image-builder-composer/v2"), false, "POST",
+		"/api/image-builder-composer/v2/source-bundles/import", `
+	{
+		"name": "rhel-9.tar"
+	}`, http.StatusCreated, `
+	{
+		"kind": "SourceBundleId"
+	}`, "id", "href")
+
+	_, _, jobType, args, _, err := wrksrv.RequestJob(context.Background(), test_distro.TestArch3Name, []string{worker.JobTypeSourceBundleImport}, []string{""}, uuid.Nil)
+	require.NoError(t, err)
+	require.Equal(t, worker.JobTypeSourceBundleImport, jobType)
+	var importJob worker.SourceBundleImportJob
+	require.NoError(t, json.Unmarshal(args, &importJob))
+	require.Equal(t, "rhel-9.tar", importJob.Name)
+
+	// the worker builds the manifest of the bundle
+	test.TestRoute(t, srv.Handler("/api/image-builder-composer/v2"), false, "POST",
+		"/api/image-builder-composer/v2/source-bundles/compose", fmt.Sprintf(`
+	{
+		"name": "rhel-9.tar",
+		"architecture": "%s"
+	}`, test_distro.TestArch3Name), http.StatusCreated, `
+	{
+		"href": "/api/image-builder-composer/v2/source-bundles/compose",
+		"kind": "ComposeId"
+	}`, "id")
+
+	_, _, jobType, args, dynArgs, err := wrksrv.RequestJob(context.Background(), test_distro.TestArch3Name, []string{worker.JobTypeOSBuild}, []string{""}, uuid.Nil)
+	require.NoError(t, err)
+	require.Equal(t, worker.JobTypeOSBuild, jobType)
+	var osbuildJob worker.OSBuildJob
+	require.NoError(t, json.Unmarshal(args, &osbuildJob))
+	require.Equal(t, "rhel-9.tar", osbuildJob.SourceBundle)
+	require.Empty(t, osbuildJob.Manifest)
+	require.Empty(t, osbuildJob.Targets)
+	require.Empty(t, dynArgs)
+}
+
+func TestCloudCredentials(t *testing.T) {
+	srv, wrksrv, _, cancel := newV2Server(t, t.TempDir(), nil)
+	defer cancel()
+
+	test.TestRoute(t, srv.Handler("/api/image-builder-composer/v2"), false, "POST",
+		"/api/image-builder-composer/v2/cloud-credentials", `
+	{
+		"name": "invalid",
+		"aws": {
+			"role_arn": "image-builder",
+			"bucket": "images"
+		}
+	}`, http.StatusBadRequest, `
+	{
+		"href": "/api/image-builder-composer/v2/errors/51",
+		"id": "51",
+		"kind": "Error",
+		"code": "IMAGE-BUILDER-COMPOSER-51",
+		"reason": "Invalid cloud credentials"
+	}`, "operation_id", "details")
+
+	reply := test.TestRouteWithReply(t, srv.Handler("/api/image-builder-composer/v2"), false, "POST",
+		"/api/image-builder-composer/v2/cloud-credentials", `
+	{
+		"name": "tenant-aws",
+		"aws": {
+			"role_arn": "arn:aws:iam::123456789012:role/image-builder",
+			"bucket": "images"
+		}
+	}`, http.StatusCreated, `
+	{
+		"kind": "CloudCredentials",
+		"name": "tenant-aws",
+		"type": "aws",
+		"aws": {
+			"role_arn": "arn:aws:iam::123456789012:role/image-builder",
+			"bucket": "images"
+		}
+	}`, "id", "href", "created_at")
+	var awsCreds v2.CloudCredentials
+	require.NoError(t, json.Unmarshal(reply, &awsCreds))
+
+	// the secret of the service principal is never returned
+	reply = test.TestRouteWithReply(t, srv.Handler("/api/image-builder-composer/v2"), false, "POST",
+		"/api/image-builder-composer/v2/cloud-credentials", `
+	{
+		"name": "tenant-azure",
+		"azure": {
+			"client_id": "client",
+			"client_secret": "secret"
+		}
+	}`, http.StatusCreated, `
+	{
+		"kind": "CloudCredentials",
+		"name": "tenant-azure",
+		"type": "azure",
+		"azure": {
+			"client_id": "client"
+		}
+	}`, "id", "href", "created_at")
+	require.NotContains(t, string(reply), "secret")
+	var azureCreds v2.CloudCredentials
+	require.NoError(t, json.Unmarshal(reply, &azureCreds))
+
+	test.TestRoute(t, srv.Handler("/api/image-builder-composer/v2"), false, "GET",
+		"/api/image-builder-composer/v2/cloud-credentials", ``, http.StatusOK, `
+	{
+		"kind": "CloudCredentialsList",
+		"page": 0,
+		"size": 2,
+		"total": 2,
+		"items": [
+			{"kind": "CloudCredentials", "name": "tenant-aws", "type": "aws"},
+			{"kind": "CloudCredentials", "name": "tenant-azure", "type": "azure"}
+		]
+	}`, "id", "href", "created_at", "aws", "azure")
+
+	compose := func(credentialsId string, status int, expected string) {
+		test.TestRoute(t, srv.Handler("/api/image-builder-composer/v2"), false, "POST", "/api/image-builder-composer/v2/compose", fmt.Sprintf(`
+		{
+			"distribution": "%s",
+			"image_request":{
+				"architecture": "%s",
+				"image_type": "aws",
+				"repositories": [{
+					"baseurl": "somerepo.org",
+					"rhsm": false
+				}],
+				"upload_options": {
+					"region": "eu-central-1",
+					"credentials_id": "%s"
+				}
+			}
+		}`, test_distro.TestDistro1Name, test_distro.TestArch3Name, credentialsId), status, expected, "id", "operation_id", "details")
+	}
+
+	compose(uuid.NewString(), http.StatusNotFound, `
+	{
+		"href": "/api/image-builder-composer/v2/errors/52",
+		"kind": "Error",
+		"code": "IMAGE-BUILDER-COMPOSER-52",
+		"reason": "Cloud credentials with given id not found"
+	}`)
+	compose(azureCreds.Id, http.StatusBadRequest, `
+	{
+		"href": "/api/image-builder-composer/v2/errors/54",
+		"kind": "Error",
+		"code": "IMAGE-BUILDER-COMPOSER-54",
+		"reason": "Cloud credentials are for a different cloud than the upload target"
+	}`)
+	compose(awsCreds.Id, http.StatusCreated, `
+	{
+		"href": "/api/image-builder-composer/v2/compose",
+		"kind": "ComposeId"
+	}`)
+
+	// the build job refers to the credentials, not their content
+	_, _, _, args, _, err := wrksrv.RequestJob(context.Background(), test_distro.TestArch3Name, []string{worker.JobTypeOSBuild}, []string{""}, uuid.Nil)
+	require.NoError(t, err)
+	var osbuildJob worker.OSBuildJob
+	require.NoError(t, json.Unmarshal(args, &osbuildJob))
+	require.Len(t, osbuildJob.Targets, 1)
+	awsOptions, ok := osbuildJob.Targets[0].Options.(*target.AWSTargetOptions)
+	require.True(t, ok)
+	require.NotNil(t, awsOptions.CloudCredentials)
+	require.Equal(t, awsCreds.Id, awsOptions.CloudCredentials.String())
+
+	test.TestRoute(t, srv.Handler("/api/image-builder-composer/v2"), false, "DELETE",
+		"/api/image-builder-composer/v2/cloud-credentials/"+awsCreds.Id, ``, http.StatusOK, `
+	{
+		"kind": "CloudCredentials",
+		"name": "tenant-aws",
+		"type": "aws"
+	}`, "id", "href", "created_at", "aws")
+	test.TestRoute(t, srv.Handler("/api/image-builder-composer/v2"), false, "GET",
+		"/api/image-builder-composer/v2/cloud-credentials/"+awsCreds.Id, ``, http.StatusNotFound, `
+	{
+		"href": "/api/image-builder-composer/v2/errors/52",
+		"id": "52",
+		"kind": "Error",
+		"code": "IMAGE-BUILDER-COMPOSER-52",
+		"reason": "Cloud credentials with given id not found"
+	}`, "operation_id", "details")
+}
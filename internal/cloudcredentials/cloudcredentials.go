@@ -0,0 +1,291 @@
+// Package cloudcredentials keeps the cloud credentials tenants register with
+// composer. Composes refer to them by their ID in the upload options and the
+// worker resolves them when it uploads the image, so the image lands in the
+// account of the tenant instead of the one the workers are configured with.
+//
+// The credentials don't carry long-lived keys for AWS and GCP: the worker
+// assumes an AWS role or impersonates a GCP service account with its own
+// credentials, the tenant grants it the permission to do so.
+package cloudcredentials
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/osbuild/osbuild-composer/internal/jsondb"
+	"github.com/osbuild/osbuild-composer/internal/secrets"
+)
+
+type Type string
+
+const (
+	TypeAWS   Type = "aws"
+	TypeGCP   Type = "gcp"
+	TypeAzure Type = "azure"
+)
+
+var ErrNotFound = errors.New("cloud credentials not found")
+
+// AWSRole is an IAM role the worker assumes with STS
+type AWSRole struct {
+	RoleARN string `json:"role_arn"`
+	// optional, passed to STS if the trust policy of the role requires it
+	ExternalID string `json:"external_id,omitempty"`
+	// bucket the role can write to, images are uploaded there before they
+	// are imported
+	Bucket string `json:"bucket"`
+}
+
+// GCPServiceAccount is a service account the worker impersonates
+type GCPServiceAccount struct {
+	ServiceAccount string `json:"service_account"`
+	ProjectID      string `json:"project_id"`
+	// optional, the bucket of the upload options or of the worker
+	// configuration is used if it isn't set
+	Bucket string `json:"bucket,omitempty"`
+}
+
+// AzureServicePrincipal is a service principal of the Azure tenant of the
+// upload target
+type AzureServicePrincipal struct {
+	ClientID string `json:"client_id"`
+	// a reference to the secret in the vault of the registry, if it has one
+	ClientSecret string `json:"client_secret,omitempty"`
+}
+
+// Credentials are the credentials of a tenant for one of the clouds, exactly
+// one of AWS, GCP and Azure is set.
+type Credentials struct {
+	ID      uuid.UUID `json:"id"`
+	Channel string    `json:"channel"`
+	Name    string    `json:"name"`
+	Created time.Time `json:"created"`
+
+	AWS   *AWSRole               `json:"aws,omitempty"`
+	GCP   *GCPServiceAccount     `json:"gcp,omitempty"`
+	Azure *AzureServicePrincipal `json:"azure,omitempty"`
+}
+
+// Type returns the cloud of the credentials
+func (c *Credentials) Type() Type {
+	switch {
+	case c.AWS != nil:
+		return TypeAWS
+	case c.GCP != nil:
+		return TypeGCP
+	case c.Azure != nil:
+		return TypeAzure
+	}
+	return ""
+}
+
+// Validate checks that exactly one cloud is set and that it has all the
+// required fields
+func (c *Credentials) Validate() error {
+	n := 0
+	for _, set := range []bool{c.AWS != nil, c.GCP != nil, c.Azure != nil} {
+		if set {
+			n++
+		}
+	}
+	if n != 1 {
+		return fmt.Errorf("exactly one of aws, gcp and azure needs to be set")
+	}
+	if c.Name == "" {
+		return fmt.Errorf("cloud credentials need a name")
+	}
+
+	switch {
+	case c.AWS != nil:
+		if !strings.HasPrefix(c.AWS.RoleARN, "arn:") {
+			return fmt.Errorf("invalid AWS role ARN %q", c.AWS.RoleARN)
+		}
+		if c.AWS.Bucket == "" {
+			return fmt.Errorf("AWS credentials need a bucket")
+		}
+	case c.GCP != nil:
+		if !strings.Contains(c.GCP.ServiceAccount, "@") {
+			return fmt.Errorf("invalid GCP service account %q", c.GCP.ServiceAccount)
+		}
+		if c.GCP.ProjectID == "" {
+			return fmt.Errorf("GCP credentials need a project ID")
+		}
+	case c.Azure != nil:
+		if c.Azure.ClientID == "" || c.Azure.ClientSecret == "" {
+			return fmt.Errorf("Azure credentials need a client ID and secret")
+		}
+	}
+	return nil
+}
+
+// Redacted returns a copy of the credentials without any secret, to be shown
+// to users
+func (c Credentials) Redacted() Credentials {
+	if c.Azure != nil {
+		azure := *c.Azure
+		azure.ClientSecret = ""
+		c.Azure = &azure
+	}
+	return c
+}
+
+// Registry holds the credentials of all tenants. Tenants only see their own
+// credentials, they are looked up by the channel of the tenant.
+type Registry struct {
+	mu    sync.RWMutex
+	db    *jsondb.JSONDatabase
+	vault *secrets.Vault
+	creds map[uuid.UUID]Credentials
+}
+
+// NewRegistry returns a registry which keeps the credentials in dir. If dir
+// is empty, the credentials are only kept in memory. If vault is set, the
+// secrets of the credentials are sealed in it.
+func NewRegistry(dir string, vault *secrets.Vault) (*Registry, error) {
+	r := &Registry{
+		vault: vault,
+		creds: make(map[uuid.UUID]Credentials),
+	}
+	if dir == "" {
+		return r, nil
+	}
+
+	r.db = jsondb.New(dir, 0600)
+	names, err := r.db.List()
+	if err != nil {
+		return nil, fmt.Errorf("cannot list cloud credentials: %w", err)
+	}
+	for _, name := range names {
+		// skip leftovers of interrupted writes
+		id, err := uuid.Parse(name)
+		if err != nil {
+			continue
+		}
+		var c Credentials
+		exists, err := r.db.Read(name, &c)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read cloud credentials %s: %w", name, err)
+		}
+		if exists {
+			r.creds[id] = c
+		}
+	}
+	return r, nil
+}
+
+// Add registers the credentials for channel and returns them with their new
+// ID
+func (r *Registry) Add(channel string, c Credentials) (Credentials, error) {
+	err := c.Validate()
+	if err != nil {
+		return Credentials{}, err
+	}
+	c.ID = uuid.New()
+	c.Channel = channel
+	c.Created = time.Now().UTC()
+	if c.Azure != nil && r.vault != nil {
+		azure := *c.Azure
+		azure.ClientSecret, err = r.vault.Seal(channel, azure.ClientSecret)
+		if err != nil {
+			return Credentials{}, err
+		}
+		c.Azure = &azure
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.db != nil {
+		err = r.db.Write(c.ID.String(), c)
+		if err != nil {
+			r.deleteSecrets(c)
+			return Credentials{}, fmt.Errorf("cannot store cloud credentials: %w", err)
+		}
+	}
+	r.creds[c.ID] = c
+	return c, nil
+}
+
+// Get returns the credentials with id if they belong to channel. Their
+// secrets are references to the vault, see Reveal.
+func (r *Registry) Get(channel string, id uuid.UUID) (Credentials, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c, ok := r.creds[id]
+	if !ok || c.Channel != channel {
+		return Credentials{}, ErrNotFound
+	}
+	return c, nil
+}
+
+// Reveal returns the credentials with id if they belong to channel, with
+// their secrets opened. Only the worker which uploads with them should ever
+// see those.
+func (r *Registry) Reveal(channel string, id uuid.UUID) (Credentials, error) {
+	c, err := r.Get(channel, id)
+	if err != nil {
+		return Credentials{}, err
+	}
+	if c.Azure != nil && r.vault != nil && secrets.IsRef(c.Azure.ClientSecret) {
+		azure := *c.Azure
+		azure.ClientSecret, err = r.vault.Open(channel, azure.ClientSecret)
+		if err != nil {
+			return Credentials{}, fmt.Errorf("cannot open the secret of cloud credentials %s: %w", id, err)
+		}
+		c.Azure = &azure
+	}
+	return c, nil
+}
+
+// List returns the credentials of channel sorted by name
+func (r *Registry) List(channel string) []Credentials {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := []Credentials{}
+	for _, c := range r.creds {
+		if c.Channel == channel {
+			result = append(result, c)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].ID.String() < result[j].ID.String()
+	})
+	return result
+}
+
+// Delete removes the credentials with id if they belong to channel. Composes
+// which still refer to them fail to upload.
+func (r *Registry) Delete(channel string, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.creds[id]
+	if !ok || c.Channel != channel {
+		return ErrNotFound
+	}
+	if r.db != nil {
+		err := r.db.Delete(id.String())
+		if err != nil {
+			return fmt.Errorf("cannot delete cloud credentials: %w", err)
+		}
+	}
+	delete(r.creds, id)
+	r.deleteSecrets(c)
+	return nil
+}
+
+// deleteSecrets removes the secrets of c from the vault, credentials which
+// can't be deleted only leave an orphaned secret behind
+func (r *Registry) deleteSecrets(c Credentials) {
+	if c.Azure == nil || r.vault == nil {
+		return
+	}
+	_ = r.vault.Delete(c.Azure.ClientSecret)
+}
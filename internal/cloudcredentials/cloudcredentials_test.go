@@ -0,0 +1,187 @@
+package cloudcredentials
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/secrets"
+)
+
+func awsCredentials(name string) Credentials {
+	return Credentials{
+		Name: name,
+		AWS: &AWSRole{
+			RoleARN: "arn:aws:iam::123456789012:role/image-builder",
+			Bucket:  "images",
+		},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for name, tc := range map[string]struct {
+		creds Credentials
+		err   string
+	}{
+		"aws": {
+			creds: awsCredentials("aws"),
+		},
+		"gcp": {
+			creds: Credentials{
+				Name: "gcp",
+				GCP:  &GCPServiceAccount{ServiceAccount: "uploader@project.iam.gserviceaccount.com", ProjectID: "project"},
+			},
+		},
+		"azure": {
+			creds: Credentials{
+				Name:  "azure",
+				Azure: &AzureServicePrincipal{ClientID: "id", ClientSecret: "secret"},
+			},
+		},
+		"none": {
+			creds: Credentials{Name: "none"},
+			err:   "exactly one of aws, gcp and azure needs to be set",
+		},
+		"two clouds": {
+			creds: Credentials{
+				Name:  "two",
+				AWS:   awsCredentials("").AWS,
+				Azure: &AzureServicePrincipal{ClientID: "id", ClientSecret: "secret"},
+			},
+			err: "exactly one of aws, gcp and azure needs to be set",
+		},
+		"no name": {
+			creds: awsCredentials(""),
+			err:   "cloud credentials need a name",
+		},
+		"invalid arn": {
+			creds: Credentials{Name: "aws", AWS: &AWSRole{RoleARN: "image-builder", Bucket: "images"}},
+			err:   `invalid AWS role ARN "image-builder"`,
+		},
+		"no bucket": {
+			creds: Credentials{Name: "aws", AWS: &AWSRole{RoleARN: "arn:aws:iam::123456789012:role/image-builder"}},
+			err:   "AWS credentials need a bucket",
+		},
+		"no project": {
+			creds: Credentials{Name: "gcp", GCP: &GCPServiceAccount{ServiceAccount: "uploader@project.iam.gserviceaccount.com"}},
+			err:   "GCP credentials need a project ID",
+		},
+		"no secret": {
+			creds: Credentials{Name: "azure", Azure: &AzureServicePrincipal{ClientID: "id"}},
+			err:   "Azure credentials need a client ID and secret",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := tc.creds.Validate()
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	c := Credentials{
+		Name:  "azure",
+		Azure: &AzureServicePrincipal{ClientID: "id", ClientSecret: "secret"},
+	}
+	redacted := c.Redacted()
+	assert.Equal(t, "id", redacted.Azure.ClientID)
+	assert.Empty(t, redacted.Azure.ClientSecret)
+	// the original keeps its secret
+	assert.Equal(t, "secret", c.Azure.ClientSecret)
+}
+
+func TestRegistry(t *testing.T) {
+	dir := t.TempDir()
+	r, err := NewRegistry(dir, nil)
+	require.NoError(t, err)
+
+	b, err := r.Add("org-1", awsCredentials("b"))
+	require.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, b.ID)
+	assert.Equal(t, "org-1", b.Channel)
+	assert.Equal(t, TypeAWS, b.Type())
+	a, err := r.Add("org-1", awsCredentials("a"))
+	require.NoError(t, err)
+	other, err := r.Add("org-2", awsCredentials("other"))
+	require.NoError(t, err)
+
+	_, err = r.Add("org-1", Credentials{Name: "invalid"})
+	assert.Error(t, err)
+
+	got, err := r.Get("org-1", a.ID)
+	require.NoError(t, err)
+	assert.Equal(t, a, got)
+
+	// tenants don't see the credentials of other tenants
+	_, err = r.Get("org-1", other.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, r.Delete("org-1", other.ID), ErrNotFound)
+
+	list := r.List("org-1")
+	require.Len(t, list, 2)
+	assert.Equal(t, "a", list[0].Name)
+	assert.Equal(t, "b", list[1].Name)
+	assert.Empty(t, r.List("org-3"))
+
+	// the credentials survive a restart
+	r, err = NewRegistry(dir, nil)
+	require.NoError(t, err)
+	assert.Len(t, r.List("org-1"), 2)
+	got, err = r.Get("org-2", other.ID)
+	require.NoError(t, err)
+	assert.Equal(t, other.AWS, got.AWS)
+
+	require.NoError(t, r.Delete("org-1", a.ID))
+	_, err = r.Get("org-1", a.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+	r, err = NewRegistry(dir, nil)
+	require.NoError(t, err)
+	assert.Len(t, r.List("org-1"), 1)
+}
+
+func TestRegistryInMemory(t *testing.T) {
+	r, err := NewRegistry("", nil)
+	require.NoError(t, err)
+	c, err := r.Add("", awsCredentials("a"))
+	require.NoError(t, err)
+	got, err := r.Get("", c.ID)
+	require.NoError(t, err)
+	assert.Equal(t, c, got)
+	require.NoError(t, r.Delete("", c.ID))
+	assert.Empty(t, r.List(""))
+}
+
+func TestRegistrySealsSecrets(t *testing.T) {
+	vault, err := secrets.NewVault(make([]byte, secrets.KeySize), secrets.NewMemoryBackend())
+	require.NoError(t, err)
+	r, err := NewRegistry(t.TempDir(), vault)
+	require.NoError(t, err)
+
+	c, err := r.Add("org-1", Credentials{
+		Name:  "azure",
+		Azure: &AzureServicePrincipal{ClientID: "id", ClientSecret: "secret"},
+	})
+	require.NoError(t, err)
+	assert.True(t, secrets.IsRef(c.Azure.ClientSecret))
+
+	got, err := r.Get("org-1", c.ID)
+	require.NoError(t, err)
+	assert.Equal(t, c.Azure.ClientSecret, got.Azure.ClientSecret)
+
+	revealed, err := r.Reveal("org-1", c.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", revealed.Azure.ClientSecret)
+	_, err = r.Reveal("org-2", c.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// deleting the credentials deletes their secret
+	require.NoError(t, r.Delete("org-1", c.ID))
+	_, err = vault.Open("org-1", c.Azure.ClientSecret)
+	assert.ErrorIs(t, err, secrets.ErrNotFound)
+}
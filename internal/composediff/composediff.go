@@ -0,0 +1,404 @@
+// Package composediff compares the content of two composes: the packages
+// installed into the image, the repositories they come from, the blueprint
+// customizations and the pipelines of the manifest.
+//
+// The diff is structured to be usable for generating changelogs between two
+// builds of the same image.
+package composediff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/osbuild/blueprint/pkg/blueprint"
+	"github.com/osbuild/image-builder/pkg/rpmmd"
+)
+
+// Package is a package installed into an image
+type Package struct {
+	Name    string
+	Epoch   uint
+	Version string
+	Release string
+	Arch    string
+}
+
+// EVR returns the Epoch:Version-Release of the package, omitting the epoch if
+// it is 0.
+func (p Package) EVR() string {
+	if p.Epoch == 0 {
+		return fmt.Sprintf("%s-%s", p.Version, p.Release)
+	}
+	return fmt.Sprintf("%d:%s-%s", p.Epoch, p.Version, p.Release)
+}
+
+func (p Package) key() string {
+	return p.Name + "." + p.Arch
+}
+
+// PackagesFromRPMMD converts a package list to the packages to compare
+func PackagesFromRPMMD(pkgs rpmmd.PackageList) []Package {
+	packages := make([]Package, len(pkgs))
+	for i, pkg := range pkgs {
+		packages[i] = Package{
+			Name:    pkg.Name,
+			Epoch:   pkg.Epoch,
+			Version: pkg.Version,
+			Release: pkg.Release,
+			Arch:    pkg.Arch,
+		}
+	}
+	return packages
+}
+
+// Compose is the content of a compose to compare
+type Compose struct {
+	Packages       []Package
+	Repositories   []rpmmd.RepoConfig
+	Customizations *blueprint.Customizations
+
+	// Manifest is the serialized osbuild manifest of the compose
+	Manifest []byte
+}
+
+// PackageVersion is a package which was added or removed
+type PackageVersion struct {
+	Name    string `json:"name"`
+	Arch    string `json:"arch"`
+	Version string `json:"version"`
+}
+
+// PackageChange is a package which was upgraded or downgraded
+type PackageChange struct {
+	Name       string `json:"name"`
+	Arch       string `json:"arch"`
+	OldVersion string `json:"old_version"`
+	NewVersion string `json:"new_version"`
+}
+
+// PackageDiff holds the package changes between two composes
+type PackageDiff struct {
+	Added      []PackageVersion `json:"added"`
+	Removed    []PackageVersion `json:"removed"`
+	Upgraded   []PackageChange  `json:"upgraded"`
+	Downgraded []PackageChange  `json:"downgraded"`
+}
+
+// RepositoryDiff holds the URLs of the repositories which only one of the
+// composes installed packages from
+type RepositoryDiff struct {
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+}
+
+// CustomizationChange is a blueprint customization which differs between two
+// composes. Old or New is empty if the customization isn't set in one of them.
+type CustomizationChange struct {
+	Name string          `json:"name"`
+	Old  json.RawMessage `json:"old,omitempty"`
+	New  json.RawMessage `json:"new,omitempty"`
+}
+
+// PipelineChange lists the types of the stages of a manifest pipeline which
+// were added, removed or have different options
+type PipelineChange struct {
+	Name          string   `json:"name"`
+	AddedStages   []string `json:"added_stages"`
+	RemovedStages []string `json:"removed_stages"`
+	ChangedStages []string `json:"changed_stages"`
+}
+
+// PipelineDiff holds the manifest pipeline changes between two composes
+type PipelineDiff struct {
+	Added   []string         `json:"added"`
+	Removed []string         `json:"removed"`
+	Changed []PipelineChange `json:"changed"`
+}
+
+// Diff holds the changes from one compose to another one
+type Diff struct {
+	Packages       PackageDiff           `json:"packages"`
+	Repositories   RepositoryDiff        `json:"repositories"`
+	Customizations []CustomizationChange `json:"customizations"`
+	Pipelines      PipelineDiff          `json:"pipelines"`
+}
+
+// Compare returns the changes from compose a to compose b
+func Compare(a, b Compose) (*Diff, error) {
+	customizations, err := compareCustomizations(a.Customizations, b.Customizations)
+	if err != nil {
+		return nil, err
+	}
+
+	pipelines, err := comparePipelines(a.Manifest, b.Manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Diff{
+		Packages:       comparePackages(a.Packages, b.Packages),
+		Repositories:   compareRepositories(a.Repositories, b.Repositories),
+		Customizations: customizations,
+		Pipelines:      *pipelines,
+	}, nil
+}
+
+// groupPackages groups the versions of each package by name and arch,
+// dropping duplicates of packages installed into several pipelines
+func groupPackages(pkgs []Package) map[string][]Package {
+	groups := make(map[string][]Package)
+	for _, pkg := range pkgs {
+		if !containsVersion(groups[pkg.key()], pkg) {
+			groups[pkg.key()] = append(groups[pkg.key()], pkg)
+		}
+	}
+	return groups
+}
+
+func sortedKeys[V any](maps ...map[string]V) []string {
+	var keys []string
+	seen := make(map[string]bool)
+	for _, m := range maps {
+		for k := range m {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func comparePackages(a, b []Package) PackageDiff {
+	diff := PackageDiff{
+		Added:      []PackageVersion{},
+		Removed:    []PackageVersion{},
+		Upgraded:   []PackageChange{},
+		Downgraded: []PackageChange{},
+	}
+
+	oldPkgs := groupPackages(a)
+	newPkgs := groupPackages(b)
+	for _, key := range sortedKeys(oldPkgs, newPkgs) {
+		oldVersions := oldPkgs[key]
+		newVersions := newPkgs[key]
+
+		// packages installed in a single version on both sides changed
+		// their version, install-only packages like the kernel are
+		// compared version by version
+		if len(oldVersions) == 1 && len(newVersions) == 1 {
+			oldPkg, newPkg := oldVersions[0], newVersions[0]
+			change := PackageChange{
+				Name:       newPkg.Name,
+				Arch:       newPkg.Arch,
+				OldVersion: oldPkg.EVR(),
+				NewVersion: newPkg.EVR(),
+			}
+			switch c := compareEVR(oldPkg, newPkg); {
+			case c < 0:
+				diff.Upgraded = append(diff.Upgraded, change)
+			case c > 0:
+				diff.Downgraded = append(diff.Downgraded, change)
+			}
+			continue
+		}
+
+		for _, pkg := range oldVersions {
+			if !containsVersion(newVersions, pkg) {
+				diff.Removed = append(diff.Removed, PackageVersion{pkg.Name, pkg.Arch, pkg.EVR()})
+			}
+		}
+		for _, pkg := range newVersions {
+			if !containsVersion(oldVersions, pkg) {
+				diff.Added = append(diff.Added, PackageVersion{pkg.Name, pkg.Arch, pkg.EVR()})
+			}
+		}
+	}
+
+	return diff
+}
+
+func containsVersion(pkgs []Package, pkg Package) bool {
+	for _, p := range pkgs {
+		if compareEVR(p, pkg) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// repoURL returns the base URL, metalink or mirrorlist of a repository,
+// falling back to its id
+func repoURL(repo rpmmd.RepoConfig) string {
+	switch {
+	case len(repo.BaseURLs) > 0:
+		return repo.BaseURLs[0]
+	case repo.Metalink != "":
+		return repo.Metalink
+	case repo.MirrorList != "":
+		return repo.MirrorList
+	default:
+		return repo.Id
+	}
+}
+
+func compareRepositories(a, b []rpmmd.RepoConfig) RepositoryDiff {
+	diff := RepositoryDiff{
+		Added:   []string{},
+		Removed: []string{},
+	}
+
+	oldRepos := make(map[string]bool, len(a))
+	for _, repo := range a {
+		oldRepos[repoURL(repo)] = true
+	}
+	newRepos := make(map[string]bool, len(b))
+	for _, repo := range b {
+		newRepos[repoURL(repo)] = true
+	}
+
+	for _, url := range sortedKeys(oldRepos, newRepos) {
+		switch {
+		case !oldRepos[url]:
+			diff.Added = append(diff.Added, url)
+		case !newRepos[url]:
+			diff.Removed = append(diff.Removed, url)
+		}
+	}
+
+	return diff
+}
+
+func customizationFields(c *blueprint.Customizations) (map[string]json.RawMessage, error) {
+	fields := make(map[string]json.RawMessage)
+	if c == nil {
+		return fields, nil
+	}
+
+	raw, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func compareCustomizations(a, b *blueprint.Customizations) ([]CustomizationChange, error) {
+	oldFields, err := customizationFields(a)
+	if err != nil {
+		return nil, fmt.Errorf("cannot compare customizations: %w", err)
+	}
+	newFields, err := customizationFields(b)
+	if err != nil {
+		return nil, fmt.Errorf("cannot compare customizations: %w", err)
+	}
+
+	changes := []CustomizationChange{}
+	for _, name := range sortedKeys(oldFields, newFields) {
+		if !bytes.Equal(oldFields[name], newFields[name]) {
+			changes = append(changes, CustomizationChange{
+				Name: name,
+				Old:  oldFields[name],
+				New:  newFields[name],
+			})
+		}
+	}
+	return changes, nil
+}
+
+type pipeline struct {
+	Name   string            `json:"name"`
+	Stages []json.RawMessage `json:"stages"`
+}
+
+// pipelines returns the pipelines of a manifest and the stages of each
+// pipeline grouped by their type, in order
+func pipelines(mf []byte) ([]string, map[string]map[string][]json.RawMessage, error) {
+	var parsed struct {
+		Pipelines []pipeline `json:"pipelines"`
+	}
+	if len(mf) > 0 {
+		if err := json.Unmarshal(mf, &parsed); err != nil {
+			return nil, nil, fmt.Errorf("cannot parse manifest: %w", err)
+		}
+	}
+
+	names := make([]string, len(parsed.Pipelines))
+	stages := make(map[string]map[string][]json.RawMessage, len(parsed.Pipelines))
+	for i, p := range parsed.Pipelines {
+		names[i] = p.Name
+		stages[p.Name] = make(map[string][]json.RawMessage)
+		for _, raw := range p.Stages {
+			var stage struct {
+				Type string `json:"type"`
+			}
+			if err := json.Unmarshal(raw, &stage); err != nil {
+				return nil, nil, fmt.Errorf("cannot parse stage of pipeline %q: %w", p.Name, err)
+			}
+			stages[p.Name][stage.Type] = append(stages[p.Name][stage.Type], raw)
+		}
+	}
+	return names, stages, nil
+}
+
+func comparePipelines(a, b []byte) (*PipelineDiff, error) {
+	oldNames, oldStages, err := pipelines(a)
+	if err != nil {
+		return nil, err
+	}
+	newNames, newStages, err := pipelines(b)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &PipelineDiff{
+		Added:   []string{},
+		Removed: []string{},
+		Changed: []PipelineChange{},
+	}
+	for _, name := range oldNames {
+		if _, ok := newStages[name]; !ok {
+			diff.Removed = append(diff.Removed, name)
+		}
+	}
+	for _, name := range newNames {
+		if _, ok := oldStages[name]; !ok {
+			diff.Added = append(diff.Added, name)
+			continue
+		}
+
+		change := PipelineChange{
+			Name:          name,
+			AddedStages:   []string{},
+			RemovedStages: []string{},
+			ChangedStages: []string{},
+		}
+		// stages of the same type are compared in the order they appear in
+		for _, stageType := range sortedKeys(oldStages[name], newStages[name]) {
+			oldOfType := oldStages[name][stageType]
+			newOfType := newStages[name][stageType]
+			for i := 0; i < len(oldOfType) && i < len(newOfType); i++ {
+				if !bytes.Equal(oldOfType[i], newOfType[i]) {
+					change.ChangedStages = append(change.ChangedStages, stageType)
+					break
+				}
+			}
+			for i := len(newOfType); i < len(oldOfType); i++ {
+				change.RemovedStages = append(change.RemovedStages, stageType)
+			}
+			for i := len(oldOfType); i < len(newOfType); i++ {
+				change.AddedStages = append(change.AddedStages, stageType)
+			}
+		}
+		if len(change.AddedStages) > 0 || len(change.RemovedStages) > 0 || len(change.ChangedStages) > 0 {
+			diff.Changed = append(diff.Changed, change)
+		}
+	}
+
+	return diff, nil
+}
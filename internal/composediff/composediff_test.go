@@ -0,0 +1,157 @@
+package composediff
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/blueprint/pkg/blueprint"
+	"github.com/osbuild/image-builder/pkg/rpmmd"
+	"github.com/osbuild/osbuild-composer/internal/common"
+)
+
+func TestRPMVerCmp(t *testing.T) {
+	testCases := []struct {
+		a, b     string
+		expected int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.0", "2.0", -1},
+		{"2.0", "1.0", 1},
+		{"2.0.1", "2.0", 1},
+		{"2.0", "2.0.1", -1},
+		{"1.10", "1.9", 1},
+		{"1.010", "1.10", 0},
+		{"1.0a", "1.0", 1},
+		{"1.0", "1.0a", -1},
+		{"1a", "1.1", -1},
+		{"1.fc42", "1.fc41", 1},
+		{"1.0~rc1", "1.0", -1},
+		{"1.0~rc1", "1.0~rc2", -1},
+		{"1.0^20240101", "1.0", 1},
+		{"1.0^20240101", "1.0.1", -1},
+		{"1_0", "1.0", 0},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, rpmvercmp(tc.a, tc.b), "%s <=> %s", tc.a, tc.b)
+	}
+}
+
+func TestComparePackages(t *testing.T) {
+	pkg := func(name string, epoch uint, version, release string) Package {
+		return Package{Name: name, Epoch: epoch, Version: version, Release: release, Arch: "x86_64"}
+	}
+
+	a := []Package{
+		pkg("bash", 0, "5.2.26", "1.fc41"),
+		pkg("curl", 0, "8.9.1", "2.fc41"),
+		pkg("dhcp-client", 12, "4.4.3", "14.fc41"),
+		pkg("kernel", 0, "6.11.4", "301.fc41"),
+		pkg("kernel", 0, "6.11.5", "300.fc41"),
+		pkg("nano", 0, "8.1", "1.fc41"),
+	}
+	b := []Package{
+		pkg("bash", 0, "5.2.32", "1.fc41"),
+		pkg("bash", 0, "5.2.32", "1.fc41"),
+		pkg("curl", 0, "8.9.1", "2.fc41"),
+		pkg("dhcp-client", 11, "4.4.4", "1.fc41"),
+		pkg("kernel", 0, "6.11.5", "300.fc41"),
+		pkg("kernel", 0, "6.11.6", "300.fc41"),
+		pkg("vim-minimal", 2, "9.1.785", "1.fc41"),
+	}
+
+	assert.Equal(t, PackageDiff{
+		Added: []PackageVersion{
+			{"kernel", "x86_64", "6.11.6-300.fc41"},
+			{"vim-minimal", "x86_64", "2:9.1.785-1.fc41"},
+		},
+		Removed: []PackageVersion{
+			{"kernel", "x86_64", "6.11.4-301.fc41"},
+			{"nano", "x86_64", "8.1-1.fc41"},
+		},
+		Upgraded: []PackageChange{
+			{"bash", "x86_64", "5.2.26-1.fc41", "5.2.32-1.fc41"},
+		},
+		Downgraded: []PackageChange{
+			{"dhcp-client", "x86_64", "12:4.4.3-14.fc41", "11:4.4.4-1.fc41"},
+		},
+	}, comparePackages(a, b))
+}
+
+func TestCompare(t *testing.T) {
+	a := Compose{
+		Packages: PackagesFromRPMMD(rpmmd.PackageList{
+			{Name: "bash", Version: "5.2.26", Release: "1.fc41", Arch: "x86_64"},
+		}),
+		Repositories: []rpmmd.RepoConfig{
+			{Id: "baseos", BaseURLs: []string{"https://example.com/baseos"}},
+			{Id: "appstream", Metalink: "https://example.com/metalink?repo=appstream"},
+		},
+		Customizations: &blueprint.Customizations{
+			Hostname: common.ToPtr("old-host"),
+			Kernel:   &blueprint.KernelCustomization{Append: "quiet"},
+		},
+		Manifest: []byte(`{"version":"2","pipelines":[
+			{"name":"build","stages":[{"type":"org.osbuild.rpm","options":{}}]},
+			{"name":"os","stages":[{"type":"org.osbuild.rpm","options":{}},{"type":"org.osbuild.hostname","options":{"hostname":"old-host"}}]},
+			{"name":"qcow2","stages":[{"type":"org.osbuild.qemu","options":{}}]}
+		]}`),
+	}
+	b := Compose{
+		Packages: PackagesFromRPMMD(rpmmd.PackageList{
+			{Name: "bash", Version: "5.2.32", Release: "1.fc41", Arch: "x86_64"},
+		}),
+		Repositories: []rpmmd.RepoConfig{
+			{Id: "baseos", BaseURLs: []string{"https://example.com/baseos"}},
+			{Id: "extras", MirrorList: "https://example.com/mirrorlist?repo=extras"},
+		},
+		Customizations: &blueprint.Customizations{
+			Hostname: common.ToPtr("new-host"),
+			Kernel:   &blueprint.KernelCustomization{Append: "quiet"},
+			Timezone: &blueprint.TimezoneCustomization{Timezone: common.ToPtr("UTC")},
+		},
+		Manifest: []byte(`{"version":"2","pipelines":[
+			{"name":"build","stages":[{"type":"org.osbuild.rpm","options":{}}]},
+			{"name":"os","stages":[{"type":"org.osbuild.rpm","options":{}},{"type":"org.osbuild.hostname","options":{"hostname":"new-host"}},{"type":"org.osbuild.timezone","options":{"zone":"UTC"}}]},
+			{"name":"vmdk","stages":[{"type":"org.osbuild.qemu","options":{}}]}
+		]}`),
+	}
+
+	diff, err := Compare(a, b)
+	require.NoError(t, err)
+
+	assert.Equal(t, []PackageChange{{"bash", "x86_64", "5.2.26-1.fc41", "5.2.32-1.fc41"}}, diff.Packages.Upgraded)
+	assert.Equal(t, RepositoryDiff{
+		Added:   []string{"https://example.com/mirrorlist?repo=extras"},
+		Removed: []string{"https://example.com/metalink?repo=appstream"},
+	}, diff.Repositories)
+	assert.Equal(t, []CustomizationChange{
+		{Name: "hostname", Old: json.RawMessage(`"old-host"`), New: json.RawMessage(`"new-host"`)},
+		{Name: "timezone", New: json.RawMessage(`{"timezone":"UTC"}`)},
+	}, diff.Customizations)
+	assert.Equal(t, PipelineDiff{
+		Added:   []string{"vmdk"},
+		Removed: []string{"qcow2"},
+		Changed: []PipelineChange{
+			{
+				Name:          "os",
+				AddedStages:   []string{"org.osbuild.timezone"},
+				RemovedStages: []string{},
+				ChangedStages: []string{"org.osbuild.hostname"},
+			},
+		},
+	}, diff.Pipelines)
+
+	// a compose doesn't differ from itself
+	diff, err = Compare(b, b)
+	require.NoError(t, err)
+	assert.Equal(t, &Diff{
+		Packages:       PackageDiff{Added: []PackageVersion{}, Removed: []PackageVersion{}, Upgraded: []PackageChange{}, Downgraded: []PackageChange{}},
+		Repositories:   RepositoryDiff{Added: []string{}, Removed: []string{}},
+		Customizations: []CustomizationChange{},
+		Pipelines:      PipelineDiff{Added: []string{}, Removed: []string{}, Changed: []PipelineChange{}},
+	}, diff)
+}
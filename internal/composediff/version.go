@@ -0,0 +1,130 @@
+package composediff
+
+import (
+	"strings"
+)
+
+// compareEVR compares the epoch, version and release of two packages like rpm
+// does. It returns a negative number if a is older than b, 0 if they're the
+// same and a positive number if a is newer than b.
+func compareEVR(a, b Package) int {
+	switch {
+	case a.Epoch < b.Epoch:
+		return -1
+	case a.Epoch > b.Epoch:
+		return 1
+	}
+	if c := rpmvercmp(a.Version, b.Version); c != 0 {
+		return c
+	}
+	return rpmvercmp(a.Release, b.Release)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isAlpha(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// splitSegment splits the leading characters matching the predicate off s
+func splitSegment(s string, pred func(byte) bool) (string, string) {
+	i := 0
+	for i < len(s) && pred(s[i]) {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+// rpmvercmp is a port of the version segment comparison of rpm (rpmvercmp.c).
+// Versions are split into alternating numeric and alphabetic segments, which
+// are compared one by one. A tilde sorts before anything, even the end of the
+// version, a caret sorts after the end of the version but before anything else.
+func rpmvercmp(a, b string) int {
+	if a == b {
+		return 0
+	}
+
+	isSeparator := func(c byte) bool {
+		return !isDigit(c) && !isAlpha(c) && c != '~' && c != '^'
+	}
+
+	for {
+		_, a = splitSegment(a, isSeparator)
+		_, b = splitSegment(b, isSeparator)
+
+		if strings.HasPrefix(a, "~") || strings.HasPrefix(b, "~") {
+			if !strings.HasPrefix(a, "~") {
+				return 1
+			}
+			if !strings.HasPrefix(b, "~") {
+				return -1
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+
+		if strings.HasPrefix(a, "^") || strings.HasPrefix(b, "^") {
+			if a == "" {
+				return -1
+			}
+			if b == "" {
+				return 1
+			}
+			if !strings.HasPrefix(a, "^") {
+				return 1
+			}
+			if !strings.HasPrefix(b, "^") {
+				return -1
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+
+		if a == "" || b == "" {
+			break
+		}
+
+		numeric := isDigit(a[0])
+		pred := isAlpha
+		if numeric {
+			pred = isDigit
+		}
+		var segA, segB string
+		segA, a = splitSegment(a, pred)
+		segB, b = splitSegment(b, pred)
+
+		// segments of different types, numeric ones are newer
+		if segB == "" {
+			if numeric {
+				return 1
+			}
+			return -1
+		}
+
+		if numeric {
+			segA = strings.TrimLeft(segA, "0")
+			segB = strings.TrimLeft(segB, "0")
+			if len(segA) != len(segB) {
+				if len(segA) > len(segB) {
+					return 1
+				}
+				return -1
+			}
+		}
+
+		if c := strings.Compare(segA, segB); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case a == "" && b == "":
+		return 0
+	case a == "":
+		return -1
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,371 @@
+// Package contentcache implements a cache for the content workers fetch for
+// their builds, so that packages and container images aren't downloaded
+// from the CDNs again by every worker.
+//
+// Files, like the RPMs of org.osbuild.curl sources, are fetched with
+//
+//	GET /files/<checksum>?url=<upstream url>
+//
+// and cached by their checksum. Container images are fetched through a
+// registry API, /v2/<registry>/<repository>/manifests|blobs/<reference>.
+// Manifests and blobs referenced by digest are cached, everything else
+// is passed through to the registry, including its authentication
+// challenges, so clients authenticate with the registry like they would
+// without the cache.
+package contentcache
+
+import (
+	"crypto/md5"  // #nosec G501 -- osbuild sources may use md5 checksums
+	"crypto/sha1" // #nosec G505 -- osbuild sources may use sha1 checksums
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/osbuild/osbuild-composer/internal/prometheus"
+)
+
+const (
+	kindFile     = "file"
+	kindBlob     = "blob"
+	kindManifest = "manifest"
+
+	mediaTypeSuffix = ".mediatype"
+)
+
+var hashes = map[string]func() hash.Hash{
+	"md5":    md5.New,
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha384": sha512.New384,
+	"sha512": sha512.New,
+}
+
+var hexRegexp = regexp.MustCompile(`^[a-f0-9]+$`)
+
+// registries whose API isn't served on the host of their image names
+var registryHosts = map[string]string{
+	"docker.io": "registry-1.docker.io",
+}
+
+type Config struct {
+	// directory the content is cached in
+	Dir string
+	// size budget of the cache, the least recently used content is evicted
+	// when it is exceeded. 0 means no limit.
+	MaxSize int64
+
+	// client fetching files, for example with the certificates of the
+	// repositories, see NewUpstreamClient
+	FileClient *http.Client
+	// client fetching from container registries
+	RegistryClient *http.Client
+	// URL prefixes files may be fetched from, no URLs if empty
+	AllowedUpstreams []string
+
+	// scheme used to talk to registries, only changed in tests
+	RegistryScheme string
+}
+
+type Cache struct {
+	config Config
+	mux    *http.ServeMux
+
+	// size of the cache, only known with a size budget
+	sizeMu sync.Mutex
+	size   int64
+}
+
+func New(config Config) (*Cache, error) {
+	if config.FileClient == nil {
+		config.FileClient = http.DefaultClient
+	}
+	if config.RegistryClient == nil {
+		config.RegistryClient = http.DefaultClient
+	}
+	if config.RegistryScheme == "" {
+		config.RegistryScheme = "https"
+	}
+	for _, kind := range []string{kindFile, kindBlob, kindManifest} {
+		err := os.MkdirAll(filepath.Join(config.Dir, kind), 0700)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create cache directory: %w", err)
+		}
+	}
+
+	c := &Cache{
+		config: config,
+		mux:    http.NewServeMux(),
+	}
+	if config.MaxSize > 0 {
+		entries, err := c.entries()
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			c.size += e.size
+		}
+	}
+
+	c.mux.HandleFunc("GET /files/{checksum}", c.handleFile)
+	c.mux.HandleFunc("GET /v2/{$}", func(w http.ResponseWriter, r *http.Request) {
+		// clients check that registries support the v2 API first
+		w.Header().Set("Docker-Distribution-API-Version", "registry/2.0")
+		w.WriteHeader(http.StatusOK)
+	})
+	c.mux.HandleFunc("GET /v2/{path...}", c.handleRegistry)
+	return c, nil
+}
+
+func (c *Cache) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mux.ServeHTTP(w, r)
+}
+
+// parseChecksum splits "sha256:abc" into the algorithm and the digest
+func parseChecksum(checksum string) (string, string, error) {
+	algorithm, digest, ok := strings.Cut(checksum, ":")
+	if !ok {
+		return "", "", fmt.Errorf("checksum %q has no algorithm", checksum)
+	}
+	if _, ok := hashes[algorithm]; !ok {
+		return "", "", fmt.Errorf("unsupported checksum algorithm %q", algorithm)
+	}
+	if !hexRegexp.MatchString(digest) {
+		return "", "", fmt.Errorf("invalid checksum %q", checksum)
+	}
+	return algorithm, digest, nil
+}
+
+func (c *Cache) path(kind, algorithm, digest string) string {
+	return filepath.Join(c.config.Dir, kind, algorithm+"-"+digest)
+}
+
+func (c *Cache) allowedUpstream(upstream string) bool {
+	for _, prefix := range c.config.AllowedUpstreams {
+		if strings.HasPrefix(upstream, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Cache) handleFile(w http.ResponseWriter, r *http.Request) {
+	algorithm, digest, err := parseChecksum(r.PathValue("checksum"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	path := c.path(kindFile, algorithm, digest)
+	if c.serveCached(w, r, kindFile, path, "application/octet-stream") {
+		return
+	}
+
+	upstream := r.URL.Query().Get("url")
+	u, err := url.Parse(upstream)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		http.Error(w, "file isn't cached and has no valid upstream url", http.StatusBadRequest)
+		return
+	}
+	if !c.allowedUpstream(upstream) {
+		http.Error(w, "upstream url not allowed", http.StatusForbidden)
+		return
+	}
+
+	req, err := http.NewRequestWithContext(r.Context(), r.Method, upstream, nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resp, err := c.config.FileClient.Do(req)
+	if err != nil {
+		logrus.Warnf("Unable to fetch %s: %v", upstream, err)
+		prometheus.ContentCacheRequest(kindFile, "error", 0)
+		http.Error(w, "cannot fetch upstream file", http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		prometheus.ContentCacheRequest(kindFile, "error", 0)
+		http.Error(w, fmt.Sprintf("upstream returned %s", resp.Status), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	copyHeaders(w.Header(), resp.Header, "Content-Length")
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodHead {
+		return
+	}
+	n := c.fill(w, resp.Body, path, algorithm, digest, "")
+	prometheus.ContentCacheRequest(kindFile, "miss", n)
+}
+
+func (c *Cache) handleRegistry(w http.ResponseWriter, r *http.Request) {
+	// <registry>/<repository>/(manifests|blobs)/<reference>
+	parts := strings.Split(r.PathValue("path"), "/")
+	if len(parts) < 4 {
+		http.Error(w, "invalid registry path", http.StatusNotFound)
+		return
+	}
+	registry := parts[0]
+	repository := strings.Join(parts[1:len(parts)-2], "/")
+	reference := parts[len(parts)-1]
+
+	var kind string
+	switch parts[len(parts)-2] {
+	case "manifests":
+		kind = kindManifest
+	case "blobs":
+		kind = kindBlob
+	default:
+		http.Error(w, "invalid registry path", http.StatusNotFound)
+		return
+	}
+
+	// only content referenced by digest can be cached, tags move
+	algorithm, digest, err := parseChecksum(reference)
+	cacheable := err == nil && r.Header.Get("Range") == ""
+	path := ""
+	if cacheable {
+		path = c.path(kind, algorithm, digest)
+		if c.serveCached(w, r, kind, path, "application/octet-stream") {
+			return
+		}
+	}
+
+	host := registry
+	if h, ok := registryHosts[registry]; ok {
+		host = h
+	}
+	upstream := url.URL{
+		Scheme: c.config.RegistryScheme,
+		Host:   host,
+		Path:   fmt.Sprintf("/v2/%s/%s/%s", repository, parts[len(parts)-2], reference),
+	}
+	req, err := http.NewRequestWithContext(r.Context(), r.Method, upstream.String(), nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	// the client authenticates with the registry itself
+	copyHeaders(req.Header, r.Header, "Accept", "Authorization", "Range")
+
+	resp, err := c.config.RegistryClient.Do(req)
+	if err != nil {
+		logrus.Warnf("Unable to fetch %s: %v", upstream.String(), err)
+		prometheus.ContentCacheRequest(kind, "error", 0)
+		http.Error(w, "cannot reach registry", http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	copyHeaders(w.Header(), resp.Header,
+		"Content-Type", "Content-Length", "Content-Range", "Docker-Content-Digest",
+		"Docker-Distribution-API-Version", "WWW-Authenticate", "ETag")
+	w.WriteHeader(resp.StatusCode)
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	if !cacheable || resp.StatusCode != http.StatusOK {
+		n, _ := io.Copy(w, resp.Body)
+		prometheus.ContentCacheRequest(kind, "passthrough", n)
+		return
+	}
+	mediaType := ""
+	if kind == kindManifest {
+		mediaType = resp.Header.Get("Content-Type")
+	}
+	n := c.fill(w, resp.Body, path, algorithm, digest, mediaType)
+	prometheus.ContentCacheRequest(kind, "miss", n)
+}
+
+// serveCached serves the content at path if it's cached
+func (c *Cache) serveCached(w http.ResponseWriter, r *http.Request, kind, path, contentType string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	if mediaType, err := os.ReadFile(path + mediaTypeSuffix); err == nil {
+		contentType = string(mediaType)
+	}
+	c.touch(path)
+
+	w.Header().Set("Content-Type", contentType)
+	if kind != kindFile {
+		w.Header().Set("Docker-Content-Digest", strings.Replace(filepath.Base(path), "-", ":", 1))
+	}
+	http.ServeContent(w, r, "", info.ModTime(), f)
+	prometheus.ContentCacheRequest(kind, "hit", info.Size())
+	return true
+}
+
+// fill streams body to the client and into the cache, it is only kept if
+// its checksum matches. It returns the number of bytes copied.
+func (c *Cache) fill(w io.Writer, body io.Reader, path, algorithm, digest, mediaType string) int64 {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-")
+	if err != nil {
+		logrus.Errorf("Unable to cache %s: %v", path, err)
+		n, _ := io.Copy(w, body)
+		return n
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	h := hashes[algorithm]()
+	n, err := io.Copy(io.MultiWriter(tmp, h), io.TeeReader(body, w))
+	if err != nil {
+		// the client or the upstream went away
+		logrus.Debugf("Not caching %s: %v", path, err)
+		return n
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); got != digest {
+		logrus.Warnf("Not caching %s, its checksum is %s:%s", path, algorithm, got)
+		return n
+	}
+
+	if mediaType != "" {
+		err = os.WriteFile(path+mediaTypeSuffix, []byte(mediaType), 0600)
+		if err != nil {
+			logrus.Errorf("Unable to cache %s: %v", path, err)
+			return n
+		}
+	}
+	err = tmp.Close()
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		logrus.Errorf("Unable to cache %s: %v", path, err)
+		return n
+	}
+	c.added(n)
+	return n
+}
+
+func copyHeaders(dst, src http.Header, keys ...string) {
+	for _, key := range keys {
+		for _, value := range src.Values(key) {
+			dst.Add(key, value)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package contentcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func checksum(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// upstream serves files and a registry, it counts the requests it gets
+type upstream struct {
+	*httptest.Server
+	requests int
+}
+
+func newUpstream(t *testing.T) *upstream {
+	u := &upstream{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repo/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "content of %s", filepath.Base(r.URL.Path))
+	})
+	mux.HandleFunc("/v2/library/fedora/blobs/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="https://auth.example.com/token"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, "layer")
+	})
+	mux.HandleFunc("/v2/library/fedora/manifests/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.oci.image.manifest.v1+json")
+		fmt.Fprint(w, `{"schemaVersion": 2}`)
+	})
+	u.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u.requests++
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(u.Close)
+	return u
+}
+
+func newTestCache(t *testing.T, config Config) *httptest.Server {
+	if config.Dir == "" {
+		config.Dir = t.TempDir()
+	}
+	config.RegistryScheme = "http"
+	c, err := New(config)
+	require.NoError(t, err)
+	srv := httptest.NewServer(c)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func get(t *testing.T, url string, headers map[string]string) (*http.Response, string) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(body)
+}
+
+func fileURL(cache, sum, upstream string) string {
+	return fmt.Sprintf("%s/files/%s?url=%s", cache, sum, url.QueryEscape(upstream))
+}
+
+func TestFiles(t *testing.T) {
+	up := newUpstream(t)
+	cache := newTestCache(t, Config{AllowedUpstreams: []string{up.URL + "/repo/"}})
+	sum := checksum("content of a.rpm")
+
+	resp, body := get(t, fileURL(cache.URL, sum, up.URL+"/repo/a.rpm"), nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "content of a.rpm", body)
+	assert.Equal(t, 1, up.requests)
+
+	// cached, the url isn't needed anymore
+	resp, body = get(t, cache.URL+"/files/"+sum, nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "content of a.rpm", body)
+	assert.Equal(t, 1, up.requests)
+}
+
+func TestFilesChecksumMismatch(t *testing.T) {
+	up := newUpstream(t)
+	cache := newTestCache(t, Config{AllowedUpstreams: []string{up.URL + "/repo/"}})
+	sum := checksum("something else")
+
+	resp, body := get(t, fileURL(cache.URL, sum, up.URL+"/repo/a.rpm"), nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "content of a.rpm", body)
+
+	// not cached, the client verifies the checksum and fails
+	resp, _ = get(t, cache.URL+"/files/"+sum, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestFilesInvalid(t *testing.T) {
+	up := newUpstream(t)
+	cache := newTestCache(t, Config{AllowedUpstreams: []string{"https://cdn.example.com/"}})
+
+	resp, _ := get(t, fileURL(cache.URL, "sha256:../../etc", up.URL+"/repo/a.rpm"), nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, _ = get(t, fileURL(cache.URL, "crc32:abcd", up.URL+"/repo/a.rpm"), nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, _ = get(t, fileURL(cache.URL, checksum("content of a.rpm"), up.URL+"/repo/a.rpm"), nil)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	// without allowed upstreams no files are fetched
+	cache = newTestCache(t, Config{})
+	resp, _ = get(t, fileURL(cache.URL, checksum("content of a.rpm"), up.URL+"/repo/a.rpm"), nil)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, 0, up.requests)
+}
+
+func TestRegistryBlobs(t *testing.T) {
+	up := newUpstream(t)
+	cache := newTestCache(t, Config{})
+	host := strings.TrimPrefix(up.URL, "http://")
+	blobURL := fmt.Sprintf("%s/v2/%s/library/fedora/blobs/%s", cache.URL, host, checksum("layer"))
+
+	resp, _ := get(t, cache.URL+"/v2/", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// the authentication challenge of the registry is passed through
+	resp, _ = get(t, blobURL, nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, `Bearer realm="https://auth.example.com/token"`, resp.Header.Get("WWW-Authenticate"))
+
+	resp, body := get(t, blobURL, map[string]string{"Authorization": "Bearer token"})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "layer", body)
+	assert.Equal(t, 2, up.requests)
+
+	resp, body = get(t, blobURL, nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "layer", body)
+	assert.Equal(t, checksum("layer"), resp.Header.Get("Docker-Content-Digest"))
+	assert.Equal(t, 2, up.requests)
+}
+
+func TestRegistryManifests(t *testing.T) {
+	up := newUpstream(t)
+	cache := newTestCache(t, Config{})
+	host := strings.TrimPrefix(up.URL, "http://")
+	manifest := `{"schemaVersion": 2}`
+
+	// tags aren't cached
+	for i := 1; i <= 2; i++ {
+		resp, body := get(t, fmt.Sprintf("%s/v2/%s/library/fedora/manifests/latest", cache.URL, host), nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, manifest, body)
+		assert.Equal(t, i, up.requests)
+	}
+
+	digestURL := fmt.Sprintf("%s/v2/%s/library/fedora/manifests/%s", cache.URL, host, checksum(manifest))
+	for i := 0; i < 2; i++ {
+		resp, body := get(t, digestURL, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, manifest, body)
+		assert.Equal(t, "application/vnd.oci.image.manifest.v1+json", resp.Header.Get("Content-Type"))
+		assert.Equal(t, 3, up.requests)
+	}
+}
+
+func TestEviction(t *testing.T) {
+	up := newUpstream(t)
+	dir := t.TempDir()
+	// fits two of the files
+	cache := newTestCache(t, Config{Dir: dir, MaxSize: 40, AllowedUpstreams: []string{up.URL + "/repo/"}})
+
+	for _, name := range []string{"a.rpm", "b.rpm", "a.rpm", "c.rpm"} {
+		content := "content of " + name
+		resp, body := get(t, fileURL(cache.URL, checksum(content), up.URL+"/repo/"+name), nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, content, body)
+	}
+
+	// b.rpm is the least recently used
+	_, err := os.Stat(filepath.Join(dir, kindFile, strings.Replace(checksum("content of b.rpm"), ":", "-", 1)))
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(dir, kindFile, strings.Replace(checksum("content of a.rpm"), ":", "-", 1)))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, up.requests)
+}
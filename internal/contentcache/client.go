@@ -0,0 +1,49 @@
+package contentcache
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// NewUpstreamClient returns a client fetching files from repositories with
+// the same settings as the RepositoryMTLSConfig of the workers: the CA of the
+// repositories, a client certificate and a proxy. All of them are optional.
+func NewUpstreamClient(ca, clientCert, clientKey string, proxy *url.URL) (*http.Client, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if ca != "" {
+		caCertPEM, err := os.ReadFile(ca)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read upstream CA: %w", err)
+		}
+		roots, err := x509.SystemCertPool()
+		if err != nil {
+			roots = x509.NewCertPool()
+		}
+		if !roots.AppendCertsFromPEM(caCertPEM) {
+			return nil, fmt.Errorf("cannot append upstream CA %s", ca)
+		}
+		tlsConfig.RootCAs = roots
+	}
+
+	if clientCert != "" || clientKey != "" {
+		cert, err := tls.LoadX509KeyPair(clientCert, clientKey)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load upstream client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	if proxy != nil {
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+	return &http.Client{Transport: transport}, nil
+}
@@ -0,0 +1,96 @@
+package contentcache
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type entry struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// entries lists the cached content
+func (c *Cache) entries() ([]entry, error) {
+	var entries []entry
+	for _, kind := range []string{kindFile, kindBlob, kindManifest} {
+		files, err := os.ReadDir(filepath.Join(c.config.Dir, kind))
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			name := f.Name()
+			if strings.HasPrefix(name, ".tmp-") || strings.HasSuffix(name, mediaTypeSuffix) {
+				continue
+			}
+			info, err := f.Info()
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, entry{
+				path:    filepath.Join(c.config.Dir, kind, name),
+				size:    info.Size(),
+				modTime: info.ModTime(),
+			})
+		}
+	}
+	return entries, nil
+}
+
+// touch marks content as used, eviction goes by the modification time as
+// access times aren't reliable with relatime or noatime mounts
+func (c *Cache) touch(path string) {
+	now := time.Now()
+	err := os.Chtimes(path, now, now)
+	if err != nil {
+		logrus.Warnf("Unable to mark %s as used: %v", path, err)
+	}
+}
+
+// added accounts content added to the cache and evicts the least recently
+// used content if the cache exceeds its budget
+func (c *Cache) added(size int64) {
+	if c.config.MaxSize == 0 {
+		return
+	}
+
+	c.sizeMu.Lock()
+	defer c.sizeMu.Unlock()
+
+	c.size += size
+	if c.size <= c.config.MaxSize {
+		return
+	}
+
+	entries, err := c.entries()
+	if err != nil {
+		logrus.Errorf("Unable to list the cached content for eviction: %v", err)
+		return
+	}
+	c.size = 0
+	for _, e := range entries {
+		c.size += e.size
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].modTime.Before(entries[j].modTime)
+	})
+	for _, e := range entries {
+		if c.size <= c.config.MaxSize {
+			break
+		}
+		err := os.Remove(e.path)
+		if err != nil {
+			logrus.Errorf("Unable to evict %s: %v", e.path, err)
+			continue
+		}
+		_ = os.Remove(e.path + mediaTypeSuffix)
+		logrus.Debugf("Evicted %s (%d bytes)", e.path, e.size)
+		c.size -= e.size
+	}
+}
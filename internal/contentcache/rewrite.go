@@ -0,0 +1,139 @@
+package contentcache
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// source types whose items carry container image names
+var containerSources = []string{
+	"org.osbuild.skopeo",
+	"org.osbuild.skopeo-index",
+}
+
+// RewriteManifest routes the org.osbuild.curl and container sources of an
+// osbuild manifest through the cache at cacheURL. Curl items which need
+// other secrets than the repository client certificate are left alone, the
+// cache can't fetch them on behalf of the worker.
+//
+// With mtls the cache requires client certificates and the worker presents
+// the one of its RepositoryMTLSConfig, so all rewritten curl items use the
+// org.osbuild.mtls secrets.
+func RewriteManifest(manifest []byte, cacheURL string, mtls bool) ([]byte, error) {
+	cache, err := url.Parse(cacheURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid content cache url: %w", err)
+	}
+
+	var m map[string]json.RawMessage
+	err = json.Unmarshal(manifest, &m)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read manifest: %w", err)
+	}
+	rawSources, ok := m["sources"]
+	if !ok {
+		return manifest, nil
+	}
+	var sources map[string]map[string]json.RawMessage
+	err = json.Unmarshal(rawSources, &sources)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read manifest sources: %w", err)
+	}
+
+	if curl, ok := sources["org.osbuild.curl"]; ok {
+		err = rewriteItems(curl, func(checksum string, item map[string]any) bool {
+			return rewriteCurlItem(cache, checksum, item, mtls)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range containerSources {
+		if source, ok := sources[name]; ok {
+			err = rewriteItems(source, func(_ string, item map[string]any) bool {
+				return rewriteContainerItem(cache, item)
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	m["sources"], err = json.Marshal(sources)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(m)
+}
+
+// rewriteItems calls rewrite on every item of a source. Items which are only
+// a url string are passed as {"url": ...}.
+func rewriteItems(source map[string]json.RawMessage, rewrite func(checksum string, item map[string]any) bool) error {
+	rawItems, ok := source["items"]
+	if !ok {
+		return nil
+	}
+	var items map[string]json.RawMessage
+	err := json.Unmarshal(rawItems, &items)
+	if err != nil {
+		return fmt.Errorf("cannot read source items: %w", err)
+	}
+
+	for checksum, rawItem := range items {
+		item := map[string]any{}
+		var u string
+		if json.Unmarshal(rawItem, &u) == nil {
+			item["url"] = u
+		} else if err := json.Unmarshal(rawItem, &item); err != nil {
+			return fmt.Errorf("cannot read source item %s: %w", checksum, err)
+		}
+		if !rewrite(checksum, item) {
+			continue
+		}
+		items[checksum], err = json.Marshal(item)
+		if err != nil {
+			return err
+		}
+	}
+
+	source["items"], err = json.Marshal(items)
+	return err
+}
+
+func rewriteCurlItem(cache *url.URL, checksum string, item map[string]any, mtls bool) bool {
+	upstream, ok := item["url"].(string)
+	if !ok || strings.HasPrefix(upstream, cache.JoinPath("files").String()+"/") {
+		return false
+	}
+	if _, _, err := parseChecksum(checksum); err != nil {
+		return false
+	}
+	if secrets, ok := item["secrets"].(map[string]any); ok && secrets["name"] != "org.osbuild.mtls" {
+		return false
+	}
+
+	u := cache.JoinPath("files", checksum)
+	u.RawQuery = url.Values{"url": {upstream}}.Encode()
+	item["url"] = u.String()
+	if mtls {
+		item["secrets"] = map[string]any{"name": "org.osbuild.mtls"}
+	} else {
+		delete(item, "secrets")
+	}
+	return true
+}
+
+func rewriteContainerItem(cache *url.URL, item map[string]any) bool {
+	image, ok := item["image"].(map[string]any)
+	if !ok {
+		return false
+	}
+	name, ok := image["name"].(string)
+	if !ok || strings.HasPrefix(name, cache.Host+"/") {
+		return false
+	}
+	image["name"] = cache.Host + "/" + name
+	return true
+}
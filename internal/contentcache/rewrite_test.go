@@ -0,0 +1,123 @@
+package contentcache
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const rewriteManifest = `{
+	"version": "2",
+	"pipelines": [],
+	"sources": {
+		"org.osbuild.curl": {
+			"items": {
+				"sha256:aaaa": "https://cdn.example.com/a.rpm",
+				"sha256:bbbb": {
+					"url": "https://cdn.example.com/b.rpm",
+					"secrets": {"name": "org.osbuild.mtls"}
+				},
+				"sha256:cccc": {
+					"url": "https://cdn.redhat.com/c.rpm",
+					"secrets": {"name": "org.osbuild.rhsm"}
+				}
+			}
+		},
+		"org.osbuild.skopeo": {
+			"items": {
+				"sha256:dddd": {
+					"image": {
+						"name": "registry.example.com/fedora",
+						"digest": "sha256:eeee"
+					}
+				}
+			}
+		},
+		"org.osbuild.inline": {
+			"items": {
+				"sha256:ffff": {"encoding": "base64", "data": ""}
+			}
+		}
+	}
+}`
+
+func TestRewriteManifest(t *testing.T) {
+	rewritten, err := RewriteManifest([]byte(rewriteManifest), "https://cache.example.com:8443", false)
+	require.NoError(t, err)
+
+	expected := `{
+		"version": "2",
+		"pipelines": [],
+		"sources": {
+			"org.osbuild.curl": {
+				"items": {
+					"sha256:aaaa": {"url": "https://cache.example.com:8443/files/sha256:aaaa?url=https%3A%2F%2Fcdn.example.com%2Fa.rpm"},
+					"sha256:bbbb": {"url": "https://cache.example.com:8443/files/sha256:bbbb?url=https%3A%2F%2Fcdn.example.com%2Fb.rpm"},
+					"sha256:cccc": {
+						"url": "https://cdn.redhat.com/c.rpm",
+						"secrets": {"name": "org.osbuild.rhsm"}
+					}
+				}
+			},
+			"org.osbuild.skopeo": {
+				"items": {
+					"sha256:dddd": {
+						"image": {
+							"name": "cache.example.com:8443/registry.example.com/fedora",
+							"digest": "sha256:eeee"
+						}
+					}
+				}
+			},
+			"org.osbuild.inline": {
+				"items": {
+					"sha256:ffff": {"encoding": "base64", "data": ""}
+				}
+			}
+		}
+	}`
+	assert.JSONEq(t, expected, string(rewritten))
+
+	// rewriting twice doesn't route the sources through the cache twice
+	twice, err := RewriteManifest(rewritten, "https://cache.example.com:8443", false)
+	require.NoError(t, err)
+	assert.JSONEq(t, expected, string(twice))
+}
+
+func TestRewriteManifestMTLS(t *testing.T) {
+	rewritten, err := RewriteManifest([]byte(rewriteManifest), "https://cache.example.com", true)
+	require.NoError(t, err)
+
+	var m struct {
+		Sources struct {
+			Curl struct {
+				Items map[string]struct {
+					URL     string            `json:"url"`
+					Secrets map[string]string `json:"secrets"`
+				} `json:"items"`
+			} `json:"org.osbuild.curl"`
+		} `json:"sources"`
+	}
+	require.NoError(t, json.Unmarshal(rewritten, &m))
+
+	// the worker authenticates with the cache using its repository client
+	// certificate
+	for _, checksum := range []string{"sha256:aaaa", "sha256:bbbb"} {
+		item := m.Sources.Curl.Items[checksum]
+		assert.Contains(t, item.URL, "https://cache.example.com/files/"+checksum)
+		assert.Equal(t, map[string]string{"name": "org.osbuild.mtls"}, item.Secrets)
+	}
+	assert.Equal(t, "https://cdn.redhat.com/c.rpm", m.Sources.Curl.Items["sha256:cccc"].URL)
+}
+
+func TestRewriteManifestWithoutSources(t *testing.T) {
+	manifest := []byte(`{"version": "2", "pipelines": []}`)
+	rewritten, err := RewriteManifest(manifest, "https://cache.example.com", false)
+	require.NoError(t, err)
+	assert.Equal(t, manifest, rewritten)
+
+	_, err = RewriteManifest([]byte(`not json`), "https://cache.example.com", false)
+	assert.Error(t, err)
+}
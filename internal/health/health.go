@@ -0,0 +1,167 @@
+// Package health serves the health and readiness endpoints of composer and
+// the workers.
+//
+// A Checker runs a set of checks concurrently and reports their results as
+// JSON. Failing checks make the endpoint return 503, except for advisory
+// checks, which only mark the report as degraded. They are meant for
+// conditions which restarting or taking the service out of rotation doesn't
+// fix, such as no workers being connected.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+const DefaultTimeout = 5 * time.Second
+
+type Status string
+
+const (
+	StatusOK       Status = "ok"
+	StatusDegraded Status = "degraded"
+	StatusFailed   Status = "failed"
+)
+
+// Check is a single check of a dependency
+type Check struct {
+	Name string
+	// Run returns an error if the check fails
+	Run func(ctx context.Context) error
+	// Advisory checks don't fail the report
+	Advisory bool
+}
+
+type Result struct {
+	Status Status `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type Report struct {
+	Status Status            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Checker runs checks, it is an http.Handler serving the report
+type Checker struct {
+	// Timeout of each check, DefaultTimeout if zero
+	Timeout time.Duration
+
+	mu     sync.Mutex
+	checks []Check
+}
+
+func NewChecker(checks ...Check) *Checker {
+	return &Checker{checks: checks}
+}
+
+// Add adds a check, the names of the checks must be unique
+func (c *Checker) Add(check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks = append(c.checks, check)
+}
+
+// Run runs all checks concurrently
+func (c *Checker) Run(ctx context.Context) Report {
+	c.mu.Lock()
+	checks := append([]Check{}, c.checks...)
+	c.mu.Unlock()
+
+	timeout := c.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+
+	results := make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			results[i] = run(checkCtx, check)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{
+		Status: StatusOK,
+		Checks: make(map[string]Result, len(checks)),
+	}
+	for i, check := range checks {
+		report.Checks[check.Name] = results[i]
+		if results[i].Status == StatusOK {
+			continue
+		}
+		if !check.Advisory {
+			report.Status = StatusFailed
+		} else if report.Status == StatusOK {
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+// run runs a check, a check which doesn't return in time fails
+func run(ctx context.Context, check Check) Result {
+	done := make(chan error, 1)
+	go func() {
+		done <- check.Run(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("check timed out: %w", ctx.Err())
+	}
+	if err != nil {
+		return Result{Status: StatusFailed, Error: err.Error()}
+	}
+	return Result{Status: StatusOK}
+}
+
+func (c *Checker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	report := c.Run(r.Context())
+	status := http.StatusOK
+	if report.Status == StatusFailed {
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	// nothing can be done about the client going away
+	_ = json.NewEncoder(w).Encode(report)
+}
+
+// Writable checks that files can be created in dir
+func Writable(dir string) error {
+	f, err := os.CreateTemp(dir, ".health-*")
+	if err != nil {
+		return err
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+// Readable checks that the file at path can be read
+func Readable(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
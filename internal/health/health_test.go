@@ -0,0 +1,113 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/health"
+)
+
+func ok(context.Context) error { return nil }
+
+func failing(context.Context) error { return errors.New("unreachable") }
+
+func TestChecker(t *testing.T) {
+	checker := health.NewChecker(
+		health.Check{Name: "jobqueue", Run: ok},
+		health.Check{Name: "workers/x86_64", Run: ok, Advisory: true},
+	)
+	assert.Equal(t, health.Report{
+		Status: health.StatusOK,
+		Checks: map[string]health.Result{
+			"jobqueue":       {Status: health.StatusOK},
+			"workers/x86_64": {Status: health.StatusOK},
+		},
+	}, checker.Run(context.Background()))
+
+	// failing advisory checks degrade the report
+	checker.Add(health.Check{Name: "workers/aarch64", Run: failing, Advisory: true})
+	report := checker.Run(context.Background())
+	assert.Equal(t, health.StatusDegraded, report.Status)
+	assert.Equal(t, health.Result{Status: health.StatusFailed, Error: "unreachable"}, report.Checks["workers/aarch64"])
+
+	// other failing checks fail it
+	checker.Add(health.Check{Name: "jwks", Run: failing})
+	report = checker.Run(context.Background())
+	assert.Equal(t, health.StatusFailed, report.Status)
+	assert.Len(t, report.Checks, 4)
+}
+
+func TestTimeout(t *testing.T) {
+	checker := health.NewChecker(health.Check{
+		Name: "stuck",
+		Run: func(ctx context.Context) error {
+			// ignores the context
+			time.Sleep(time.Second)
+			return nil
+		},
+	})
+	checker.Timeout = 10 * time.Millisecond
+
+	report := checker.Run(context.Background())
+	assert.Equal(t, health.StatusFailed, report.Status)
+	assert.Contains(t, report.Checks["stuck"].Error, "check timed out")
+}
+
+func TestHandler(t *testing.T) {
+	checker := health.NewChecker(health.Check{Name: "jobqueue", Run: ok})
+
+	serve := func(method string) (*httptest.ResponseRecorder, health.Report) {
+		recorder := httptest.NewRecorder()
+		checker.ServeHTTP(recorder, httptest.NewRequest(method, "/readyz", nil))
+		var report health.Report
+		if method == http.MethodGet {
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &report))
+		}
+		return recorder, report
+	}
+
+	recorder, report := serve(http.MethodGet)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, health.StatusOK, report.Status)
+
+	checker.Add(health.Check{Name: "workers/x86_64", Run: failing, Advisory: true})
+	recorder, report = serve(http.MethodGet)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, health.StatusDegraded, report.Status)
+
+	checker.Add(health.Check{Name: "jwks", Run: failing})
+	recorder, report = serve(http.MethodGet)
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Equal(t, health.StatusFailed, report.Status)
+
+	recorder, _ = serve(http.MethodHead)
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+	recorder, _ = serve(http.MethodPost)
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
+
+func TestFiles(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, health.Writable(dir))
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+	assert.Error(t, health.Writable(filepath.Join(dir, "missing")))
+
+	file := filepath.Join(dir, "credentials")
+	require.NoError(t, os.WriteFile(file, []byte("secret"), 0600))
+	assert.NoError(t, health.Readable(file))
+	assert.Error(t, health.Readable(filepath.Join(dir, "missing")))
+}
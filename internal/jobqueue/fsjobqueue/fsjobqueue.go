@@ -17,6 +17,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -24,6 +25,7 @@ import (
 
 	"github.com/osbuild/osbuild-composer/pkg/jobqueue"
 
+	"github.com/osbuild/osbuild-composer/internal/health"
 	"github.com/osbuild/osbuild-composer/internal/jsondb"
 )
 
@@ -34,7 +36,8 @@ type fsJobQueue struct {
 	// briefly while waiting on pending channels.
 	mu sync.Mutex
 
-	db *jsondb.JSONDatabase
+	db  *jsondb.JSONDatabase
+	dir string
 
 	// List of pending job
 	pending *list.List
@@ -63,10 +66,13 @@ type fsJobQueue struct {
 }
 
 type worker struct {
-	Channel   string    `json:"channel"`
-	Arch      string    `json:"arch"`
-	Heartbeat time.Time `json:"heartbeat"`
-	Tokens    map[uuid.UUID]struct{}
+	Channel      string    `json:"channel"`
+	Arch         string    `json:"arch"`
+	Version      string    `json:"version"`
+	Capabilities []string  `json:"capabilities"`
+	Heartbeat    time.Time `json:"heartbeat"`
+	Draining     bool      `json:"draining"`
+	Tokens       map[uuid.UUID]struct{}
 }
 
 // On-disk job struct. Contains all necessary (but non-redundant) information
@@ -81,6 +87,8 @@ type job struct {
 	Dependents   []uuid.UUID     `json:"dependents"`
 	Result       json.RawMessage `json:"result,omitempty"`
 	Channel      string          `json:"channel"`
+	Requirements []string        `json:"requirements,omitempty"`
+	Shares       []share         `json:"shares,omitempty"`
 
 	QueuedAt   time.Time `json:"queued_at,omitempty"`
 	StartedAt  time.Time `json:"started_at,omitempty"`
@@ -91,12 +99,20 @@ type job struct {
 	Canceled bool   `json:"canceled,omitempty"`
 }
 
+// On-disk grant of another channel on a job, ordered by channel in the job
+type share struct {
+	Channel    string    `json:"channel"`
+	Permission string    `json:"permission"`
+	Created    time.Time `json:"created"`
+}
+
 // Create a new fsJobQueue object for `dir`. This object must have exclusive
 // access to `dir`. If `dir` contains jobs created from previous runs, they are
 // loaded and rescheduled to run if necessary.
 func New(dir string) (*fsJobQueue, error) {
 	q := &fsJobQueue{
 		db:              jsondb.New(dir, 0600),
+		dir:             dir,
 		pending:         list.New(),
 		dependants:      make(map[uuid.UUID][]uuid.UUID),
 		jobIdByToken:    make(map[uuid.UUID]uuid.UUID),
@@ -146,7 +162,19 @@ func New(dir string) (*fsJobQueue, error) {
 	return q, nil
 }
 
+// CheckHealth verifies that jobs can be written to the directory of the queue
+func (q *fsJobQueue) CheckHealth(ctx context.Context) error {
+	if err := health.Writable(q.dir); err != nil {
+		return fmt.Errorf("job queue directory is not writable: %w", err)
+	}
+	return nil
+}
+
 func (q *fsJobQueue) Enqueue(jobType string, args interface{}, dependencies []uuid.UUID, channel string) (uuid.UUID, error) {
+	return q.EnqueueWithRequirements(jobType, args, dependencies, channel, nil)
+}
+
+func (q *fsJobQueue) EnqueueWithRequirements(jobType string, args interface{}, dependencies []uuid.UUID, channel string, requirements []string) (uuid.UUID, error) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
@@ -157,6 +185,7 @@ func (q *fsJobQueue) Enqueue(jobType string, args interface{}, dependencies []uu
 		Dependencies: dependencies,
 		QueuedAt:     time.Now(),
 		Channel:      channel,
+		Requirements: requirements,
 	}
 
 	var err error
@@ -201,8 +230,8 @@ func (q *fsJobQueue) Enqueue(jobType string, args interface{}, dependencies []uu
 
 // dequeueLoop blocks until a job matching the given criteria is available or
 // ctx is canceled. The matches function determines whether a pending job is
-// eligible for dequeuing. This is the shared implementation for Dequeue and
-// DequeueAnyChannel.
+// eligible for dequeuing. This is the shared implementation for Dequeue,
+// DequeueAnyChannel and DequeueRoutes.
 func (q *fsJobQueue) dequeueLoop(ctx context.Context, wID uuid.UUID, matches func(*job) bool) (uuid.UUID, uuid.UUID, []uuid.UUID, string, json.RawMessage, error) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
@@ -263,13 +292,19 @@ func (q *fsJobQueue) dequeueLoop(ctx context.Context, wID uuid.UUID, matches fun
 
 func (q *fsJobQueue) Dequeue(ctx context.Context, wID uuid.UUID, jobTypes, channels []string) (uuid.UUID, uuid.UUID, []uuid.UUID, string, json.RawMessage, error) {
 	return q.dequeueLoop(ctx, wID, func(j *job) bool {
-		return jobMatchesCriteria(j, jobTypes, channels)
+		return jobMatchesCriteria(j, jobTypes, channels) && q.workerCanRun(wID, j)
 	})
 }
 
 func (q *fsJobQueue) DequeueAnyChannel(ctx context.Context, wID uuid.UUID, jobTypes []string) (uuid.UUID, uuid.UUID, []uuid.UUID, string, json.RawMessage, error) {
 	return q.dequeueLoop(ctx, wID, func(j *job) bool {
-		return jobMatchesType(j, jobTypes)
+		return jobMatchesType(j, jobTypes) && q.workerCanRun(wID, j)
+	})
+}
+
+func (q *fsJobQueue) DequeueRoutes(ctx context.Context, wID uuid.UUID, routes []jobqueue.Route) (uuid.UUID, uuid.UUID, []uuid.UUID, string, json.RawMessage, error) {
+	return q.dequeueLoop(ctx, wID, func(j *job) bool {
+		return jobMatchesRoutes(j, routes) && q.workerCanRun(wID, j)
 	})
 }
 
@@ -517,6 +552,78 @@ func (q *fsJobQueue) Job(id uuid.UUID) (jobType string, args json.RawMessage, de
 	return
 }
 
+func (q *fsJobQueue) ShareJob(id uuid.UUID, channel, permission string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	j, err := q.readJob(id)
+	if err != nil {
+		return err
+	}
+
+	i, found := slices.BinarySearchFunc(j.Shares, channel, func(s share, channel string) int {
+		return strings.Compare(s.Channel, channel)
+	})
+	if found {
+		j.Shares[i].Permission = permission
+	} else {
+		j.Shares = slices.Insert(j.Shares, i, share{
+			Channel:    channel,
+			Permission: permission,
+			Created:    time.Now(),
+		})
+	}
+
+	err = q.db.Write(id.String(), j)
+	if err != nil {
+		return fmt.Errorf("error writing job %s: %v", id, err)
+	}
+
+	return nil
+}
+
+func (q *fsJobQueue) UnshareJob(id uuid.UUID, channel string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	j, err := q.readJob(id)
+	if err != nil {
+		return err
+	}
+
+	i := slices.IndexFunc(j.Shares, func(s share) bool {
+		return s.Channel == channel
+	})
+	if i == -1 {
+		return jobqueue.ErrShareNotExist
+	}
+	j.Shares = slices.Delete(j.Shares, i, i+1)
+
+	err = q.db.Write(id.String(), j)
+	if err != nil {
+		return fmt.Errorf("error writing job %s: %v", id, err)
+	}
+
+	return nil
+}
+
+func (q *fsJobQueue) JobShares(id uuid.UUID) ([]jobqueue.Share, error) {
+	j, err := q.readJob(id)
+	if err != nil {
+		return nil, err
+	}
+
+	shares := make([]jobqueue.Share, 0, len(j.Shares))
+	for _, s := range j.Shares {
+		shares = append(shares, jobqueue.Share{
+			Channel:    s.Channel,
+			Permission: s.Permission,
+			Created:    s.Created,
+		})
+	}
+	return shares, nil
+}
+
 func (q *fsJobQueue) IdFromToken(token uuid.UUID) (id uuid.UUID, err error) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
@@ -549,16 +656,18 @@ func (q *fsJobQueue) RefreshHeartbeat(token uuid.UUID) {
 	}
 }
 
-func (q *fsJobQueue) InsertWorker(channel, arch string) (uuid.UUID, error) {
+func (q *fsJobQueue) InsertWorker(channel, arch, version string, capabilities []string) (uuid.UUID, error) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
 	wID := uuid.New()
 	q.workers[wID] = worker{
-		Channel:   channel,
-		Arch:      arch,
-		Heartbeat: time.Now(),
-		Tokens:    make(map[uuid.UUID]struct{}),
+		Channel:      channel,
+		Arch:         arch,
+		Version:      version,
+		Capabilities: capabilities,
+		Heartbeat:    time.Now(),
+		Tokens:       make(map[uuid.UUID]struct{}),
 	}
 	return wID, nil
 }
@@ -573,6 +682,21 @@ func (q *fsJobQueue) UpdateWorkerStatus(wID uuid.UUID) error {
 	}
 
 	worker.Heartbeat = time.Now()
+	q.workers[wID] = worker
+	return nil
+}
+
+func (q *fsJobQueue) DrainWorker(wID uuid.UUID) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	worker, ok := q.workers[wID]
+	if !ok {
+		return jobqueue.ErrWorkerNotExist
+	}
+
+	worker.Draining = true
+	q.workers[wID] = worker
 	return nil
 }
 
@@ -584,10 +708,19 @@ func (q *fsJobQueue) Workers(olderThan time.Duration) ([]jobqueue.Worker, error)
 	workers := []jobqueue.Worker{}
 	for wID, w := range q.workers {
 		if now.Sub(w.Heartbeat) > olderThan {
+			tokens := []uuid.UUID{}
+			for token := range w.Tokens {
+				tokens = append(tokens, token)
+			}
 			workers = append(workers, jobqueue.Worker{
-				ID:      wID,
-				Channel: w.Channel,
-				Arch:    w.Arch,
+				ID:           wID,
+				Channel:      w.Channel,
+				Arch:         w.Arch,
+				Version:      w.Version,
+				Capabilities: w.Capabilities,
+				Heartbeat:    w.Heartbeat,
+				Draining:     w.Draining,
+				Tokens:       tokens,
 			})
 		}
 	}
@@ -736,6 +869,24 @@ func jobMatchesCriteria(j *job, acceptedJobTypes []string, acceptedChannels []st
 	return slices.Contains(acceptedJobTypes, j.Type) && slices.Contains(acceptedChannels, j.Channel)
 }
 
+// jobMatchesRoutes returns true if any of the routes selects the job
+func jobMatchesRoutes(j *job, routes []jobqueue.Route) bool {
+	return slices.ContainsFunc(routes, func(r jobqueue.Route) bool {
+		return r.Matches(j.Type, j.Channel, j.Requirements)
+	})
+}
+
+// workerCanRun returns true if the worker isn't draining and has the
+// capabilities the job requires. Unknown workers can run any job. `q.mu`
+// must be locked when this method is called.
+func (q *fsJobQueue) workerCanRun(wID uuid.UUID, j *job) bool {
+	w, ok := q.workers[wID]
+	if !ok {
+		return true
+	}
+	return !w.Draining && jobqueue.HasCapabilities(w.Capabilities, j.Requirements)
+}
+
 // jobMatchesType returns true if it matches criteria defined in parameters
 //
 // Criteria:
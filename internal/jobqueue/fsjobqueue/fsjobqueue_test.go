@@ -47,6 +47,24 @@ func TestJobQueueBadJSON(t *testing.T) {
 	require.NotNil(t, q)
 }
 
+func TestCheckHealth(t *testing.T) {
+	dir := t.TempDir()
+	q, err := fsjobqueue.New(dir)
+	require.NoError(t, err)
+
+	checker, ok := interface{}(q).(jobqueue.HealthChecker)
+	require.True(t, ok)
+	require.NoError(t, checker.CheckHealth(context.Background()))
+
+	// the check doesn't leave anything behind
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	require.NoError(t, os.RemoveAll(dir))
+	require.Error(t, checker.CheckHealth(context.Background()))
+}
+
 func sortUUIDs(entries []uuid.UUID) {
 	sort.Slice(entries, func(i, j int) bool {
 		return entries[i].String() < entries[j].String()
@@ -65,9 +65,13 @@ func TestJobQueue(t *testing.T, makeJobQueue MakeJobQueue) {
 	t.Run("dequeue-any-channel", wrap(testDequeueAnyChannel))
 	t.Run("100-dequeuers", wrap(test100dequeuers))
 	t.Run("workers", wrap(testWorkers))
+	t.Run("requirements", wrap(testRequirements))
+	t.Run("routes", wrap(testRoutes))
+	t.Run("drain-worker", wrap(testDrainWorker))
 	t.Run("fail", wrap(testFail))
 	t.Run("all-root-jobs", wrap(testAllRootJobs))
 	t.Run("delete-jobs", wrap(testDeleteJobs))
+	t.Run("shares", wrap(testShares))
 }
 
 func pushTestJob(t *testing.T, q jobqueue.JobQueue, jobType string, args interface{}, dependencies []uuid.UUID, channel string) uuid.UUID {
@@ -877,22 +881,44 @@ func test100dequeuers(t *testing.T, q jobqueue.JobQueue) {
 func testWorkers(t *testing.T, q jobqueue.JobQueue) {
 	one := pushTestJob(t, q, "octopus", nil, nil, "chan")
 
-	w1, err := q.InsertWorker("chan", "x86_64")
+	w1, err := q.InsertWorker("chan", "x86_64", "", nil)
 	require.NoError(t, err)
-	w2, err := q.InsertWorker("chan", "aarch64")
+	w2, err := q.InsertWorker("chan", "aarch64", "v42", []string{"label:fips"})
 	require.NoError(t, err)
 
 	workers, err := q.Workers(0)
 	require.NoError(t, err)
 	require.Len(t, workers, 2)
 	require.Equal(t, "chan", workers[0].Channel)
+	for _, w := range workers {
+		require.False(t, w.Draining)
+		require.Empty(t, w.Tokens)
+		require.WithinDuration(t, time.Now(), w.Heartbeat, time.Minute)
+		if w.ID == w2 {
+			require.Equal(t, "v42", w.Version)
+			require.Equal(t, []string{"label:fips"}, w.Capabilities)
+		} else {
+			require.Equal(t, "", w.Version)
+			require.Nil(t, w.Capabilities)
+		}
+	}
 
 	workers, err = q.Workers(time.Hour * 24)
 	require.NoError(t, err)
 	require.Len(t, workers, 0)
 
-	_, _, _, _, _, err = q.Dequeue(context.Background(), w1, []string{"octopus"}, []string{"chan"})
+	_, token, _, _, _, err := q.Dequeue(context.Background(), w1, []string{"octopus"}, []string{"chan"})
+	require.NoError(t, err)
+
+	workers, err = q.Workers(0)
 	require.NoError(t, err)
+	for _, w := range workers {
+		if w.ID == w1 {
+			require.Equal(t, []uuid.UUID{token}, w.Tokens)
+		} else {
+			require.Empty(t, w.Tokens)
+		}
+	}
 
 	err = q.DeleteWorker(w1)
 	require.Equal(t, err, jobqueue.ErrActiveJobs)
@@ -914,6 +940,169 @@ func testWorkers(t *testing.T, q jobqueue.JobQueue) {
 	require.NoError(t, err)
 }
 
+// Only hands out jobs to workers having the capabilities the jobs require
+func testRequirements(t *testing.T, q jobqueue.JobQueue) {
+	fips, err := q.EnqueueWithRequirements("octopus", nil, nil, "chan", []string{"label:fips"})
+	require.NoError(t, err)
+	koji, err := q.EnqueueWithRequirements("octopus", nil, nil, "chan", []string{"label:fips", "target:koji"})
+	require.NoError(t, err)
+
+	plain, err := q.InsertWorker("chan", "x86_64", "", []string{})
+	require.NoError(t, err)
+	fipsWorker, err := q.InsertWorker("chan", "x86_64", "", []string{"label:fips"})
+	require.NoError(t, err)
+	kojiWorker, err := q.InsertWorker("chan", "x86_64", "", []string{"label:fips", "target:koji", "label:gpu"})
+	require.NoError(t, err)
+	legacy, err := q.InsertWorker("chan", "x86_64", "", nil)
+	require.NoError(t, err)
+
+	// no job has requirements the plain worker can satisfy
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+	_, _, _, _, _, err = q.Dequeue(ctx, plain, []string{"octopus"}, []string{"chan"})
+	require.Equal(t, jobqueue.ErrDequeueTimeout, err)
+
+	// the fips worker can't run the koji job even though it was queued first
+	id, _, _, _, _, err := q.Dequeue(context.Background(), fipsWorker, []string{"octopus"}, []string{"chan"})
+	require.NoError(t, err)
+	require.Equal(t, fips, id)
+
+	ctx2, cancel2 := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel2()
+	_, _, _, _, _, err = q.Dequeue(ctx2, fipsWorker, []string{"octopus"}, []string{"chan"})
+	require.Equal(t, jobqueue.ErrDequeueTimeout, err)
+
+	id, _, _, _, _, err = q.Dequeue(context.Background(), kojiWorker, []string{"octopus"}, []string{"chan"})
+	require.NoError(t, err)
+	require.Equal(t, koji, id)
+
+	// workers without capabilities can run any job
+	anyJob, err := q.EnqueueWithRequirements("octopus", nil, nil, "chan", []string{"target:koji"})
+	require.NoError(t, err)
+	id, _, _, _, _, err = q.Dequeue(context.Background(), legacy, []string{"octopus"}, []string{"chan"})
+	require.NoError(t, err)
+	require.Equal(t, anyJob, id)
+
+	// jobs without requirements can be run by any worker
+	none := pushTestJob(t, q, "octopus", nil, nil, "chan")
+	id, _, _, _, _, err = q.Dequeue(context.Background(), plain, []string{"octopus"}, []string{"chan"})
+	require.NoError(t, err)
+	require.Equal(t, none, id)
+}
+
+// Hands out the jobs matching any of the routes, oldest first
+func testRoutes(t *testing.T, q jobqueue.JobQueue) {
+	timeout := func() context.Context {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+		t.Cleanup(cancel)
+		return ctx
+	}
+
+	kingfisher := pushTestJob(t, q, "octopus", nil, nil, "kingfisher")
+	toucan := pushTestJob(t, q, "octopus", nil, nil, "toucan")
+	fips, err := q.EnqueueWithRequirements("clownfish", nil, nil, "toucan", []string{"label:fips"})
+	require.NoError(t, err)
+	penguin := pushTestJob(t, q, "octopus", nil, nil, "penguin")
+	plain := pushTestJob(t, q, "clownfish", nil, nil, "penguin")
+
+	// no routes match nothing
+	_, _, _, _, _, err = q.DequeueRoutes(timeout(), uuid.Nil, nil)
+	require.Equal(t, jobqueue.ErrDequeueTimeout, err)
+	_, _, _, _, _, err = q.DequeueRoutes(timeout(), uuid.Nil, []jobqueue.Route{
+		{JobTypes: []string{"octopus"}, Channels: []string{"pelican"}},
+		{JobTypes: []string{"zebra"}, AnyChannel: true},
+	})
+	require.Equal(t, jobqueue.ErrDequeueTimeout, err)
+
+	// jobs are matched by type and channel of the same route
+	id, _, _, typ, _, err := q.DequeueRoutes(context.Background(), uuid.Nil, []jobqueue.Route{
+		{JobTypes: []string{"clownfish"}, Channels: []string{"kingfisher"}},
+		{JobTypes: []string{"octopus"}, Channels: []string{"toucan"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, toucan, id)
+	require.Equal(t, "octopus", typ)
+
+	// routes with requirements only match jobs requiring all of them
+	route := jobqueue.Route{JobTypes: []string{"clownfish"}, AnyChannel: true, Requirements: []string{"label:fips"}}
+	id, _, _, _, _, err = q.DequeueRoutes(context.Background(), uuid.Nil, []jobqueue.Route{route})
+	require.NoError(t, err)
+	require.Equal(t, fips, id)
+	_, _, _, _, _, err = q.DequeueRoutes(timeout(), uuid.Nil, []jobqueue.Route{route})
+	require.Equal(t, jobqueue.ErrDequeueTimeout, err)
+
+	// excluded channels aren't matched, not even by routes of any channel
+	excluded := []jobqueue.Route{
+		{JobTypes: []string{"octopus"}, AnyChannel: true, ExcludeChannels: []string{"kingfisher", "penguin"}},
+	}
+	_, _, _, _, _, err = q.DequeueRoutes(timeout(), uuid.Nil, excluded)
+	require.Equal(t, jobqueue.ErrDequeueTimeout, err)
+
+	// the oldest job matching any route is dequeued first
+	routes := []jobqueue.Route{
+		{JobTypes: []string{"clownfish"}, Channels: []string{"penguin"}},
+		{JobTypes: []string{"octopus"}, AnyChannel: true},
+	}
+	for _, expected := range []uuid.UUID{kingfisher, penguin, plain} {
+		id, _, _, _, _, err = q.DequeueRoutes(context.Background(), uuid.Nil, routes)
+		require.NoError(t, err)
+		require.Equal(t, expected, id)
+	}
+
+	// the capabilities of the worker still apply
+	w, err := q.InsertWorker("toucan", "x86_64", "", []string{})
+	require.NoError(t, err)
+	_, err = q.EnqueueWithRequirements("clownfish", nil, nil, "toucan", []string{"label:fips"})
+	require.NoError(t, err)
+	_, _, _, _, _, err = q.DequeueRoutes(timeout(), w, []jobqueue.Route{route})
+	require.Equal(t, jobqueue.ErrDequeueTimeout, err)
+}
+
+// Draining workers don't get new jobs, but can finish the ones they run
+func testDrainWorker(t *testing.T, q jobqueue.JobQueue) {
+	err := q.DrainWorker(uuid.New())
+	require.Equal(t, jobqueue.ErrWorkerNotExist, err)
+
+	running := pushTestJob(t, q, "octopus", nil, nil, "chan")
+	pending := pushTestJob(t, q, "octopus", nil, nil, "chan")
+
+	w, err := q.InsertWorker("chan", "x86_64", "", nil)
+	require.NoError(t, err)
+	other, err := q.InsertWorker("chan", "x86_64", "", nil)
+	require.NoError(t, err)
+
+	id, _, _, _, _, err := q.Dequeue(context.Background(), w, []string{"octopus"}, []string{"chan"})
+	require.NoError(t, err)
+	require.Equal(t, running, id)
+
+	err = q.DrainWorker(w)
+	require.NoError(t, err)
+
+	workers, err := q.Workers(0)
+	require.NoError(t, err)
+	for _, worker := range workers {
+		require.Equal(t, worker.ID == w, worker.Draining)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+	_, _, _, _, _, err = q.Dequeue(ctx, w, []string{"octopus"}, []string{"chan"})
+	require.Equal(t, jobqueue.ErrDequeueTimeout, err)
+
+	ctx2, cancel2 := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel2()
+	_, _, _, _, _, err = q.DequeueAnyChannel(ctx2, w, []string{"octopus"})
+	require.Equal(t, jobqueue.ErrDequeueTimeout, err)
+
+	requeued, err := q.RequeueOrFinishJob(running, 0, &TestResult{})
+	require.NoError(t, err)
+	require.False(t, requeued)
+
+	id, _, _, _, _, err = q.Dequeue(context.Background(), other, []string{"octopus"}, []string{"chan"})
+	require.NoError(t, err)
+	require.Equal(t, pending, id)
+}
+
 func testFail(t *testing.T, q jobqueue.JobQueue) {
 	startTime := time.Now()
 
@@ -1082,6 +1271,55 @@ func testDeleteJobs(t *testing.T, q jobqueue.JobQueue) {
 	})
 }
 
+func testShares(t *testing.T, q jobqueue.JobQueue) {
+	err := q.ShareJob(uuid.New(), "toucan", "read")
+	require.Equal(t, jobqueue.ErrNotExist, err)
+	_, err = q.JobShares(uuid.New())
+	require.Equal(t, jobqueue.ErrNotExist, err)
+	err = q.UnshareJob(uuid.New(), "toucan")
+	require.Equal(t, jobqueue.ErrNotExist, err)
+
+	id := pushTestJob(t, q, "octopus", nil, nil, "kingfisher")
+	shares, err := q.JobShares(id)
+	require.NoError(t, err)
+	require.Empty(t, shares)
+
+	require.NoError(t, q.ShareJob(id, "toucan", "read"))
+	require.NoError(t, q.ShareJob(id, "penguin", "clone"))
+	shares, err = q.JobShares(id)
+	require.NoError(t, err)
+	require.Len(t, shares, 2)
+	require.Equal(t, "penguin", shares[0].Channel)
+	require.Equal(t, "clone", shares[0].Permission)
+	require.False(t, shares[0].Created.IsZero())
+	require.Equal(t, "toucan", shares[1].Channel)
+	require.Equal(t, "read", shares[1].Permission)
+
+	// sharing again replaces the permission, but keeps the grant
+	require.NoError(t, q.ShareJob(id, "toucan", "clone"))
+	updated, err := q.JobShares(id)
+	require.NoError(t, err)
+	require.Len(t, updated, 2)
+	require.Equal(t, "clone", updated[1].Permission)
+	require.True(t, shares[1].Created.Equal(updated[1].Created))
+
+	require.NoError(t, q.UnshareJob(id, "penguin"))
+	require.Equal(t, jobqueue.ErrShareNotExist, q.UnshareJob(id, "penguin"))
+	shares, err = q.JobShares(id)
+	require.NoError(t, err)
+	require.Len(t, shares, 1)
+	require.Equal(t, "toucan", shares[0].Channel)
+
+	// shares don't change the channel of the job
+	_, _, _, channel, err := q.Job(id)
+	require.NoError(t, err)
+	require.Equal(t, "kingfisher", channel)
+
+	require.NoError(t, q.DeleteJob(context.Background(), id))
+	_, err = q.JobShares(id)
+	require.Equal(t, jobqueue.ErrNotExist, err)
+}
+
 func testDequeueNilAndEmptyChannels(t *testing.T, q jobqueue.JobQueue) {
 	// Enqueue a job on a specific channel
 	_ = pushTestJob(t, q, "octopus", nil, nil, "toucan")
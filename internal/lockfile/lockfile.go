@@ -0,0 +1,206 @@
+// Package lockfile records the exact packages an image was built from, so
+// that the image can be rebuilt from the same packages later on.
+//
+// A lockfile is generated from the depsolve result of a finished compose.
+// When submitting a compose with a lockfile, the package sets of the image
+// are replaced by the locked NEVRAs and the depsolve result is verified to
+// contain exactly the locked packages.
+package lockfile
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/osbuild/image-builder/pkg/depsolvednf"
+	"github.com/osbuild/image-builder/pkg/rpmmd"
+)
+
+// Version is the version of the lockfile format
+const Version = 1
+
+// Package is a package locked to an exact NEVRA and checksum
+type Package struct {
+	Name    string `json:"name"`
+	Epoch   uint   `json:"epoch"`
+	Version string `json:"version"`
+	Release string `json:"release"`
+	Arch    string `json:"arch"`
+
+	// Checksum of the package in the "type:value" format
+	Checksum string `json:"checksum"`
+
+	// RemoteLocations are the URLs the package was downloaded from
+	RemoteLocations []string `json:"remote_locations,omitempty"`
+
+	// RepoURL is the base URL, metalink or mirrorlist of the repository
+	// providing the package
+	RepoURL string `json:"repo_url,omitempty"`
+}
+
+// NEVRA returns the Name-Epoch:Version-Release.Arch of the package, omitting
+// the epoch if it is 0.
+func (p Package) NEVRA() string {
+	if p.Epoch == 0 {
+		return fmt.Sprintf("%s-%s-%s.%s", p.Name, p.Version, p.Release, p.Arch)
+	}
+	return fmt.Sprintf("%s-%d:%s-%s.%s", p.Name, p.Epoch, p.Version, p.Release, p.Arch)
+}
+
+// Lockfile holds the locked packages of each pipeline of an image
+type Lockfile struct {
+	Version int `json:"version"`
+
+	// Pipelines maps the name of each pipeline to the packages of each of
+	// its depsolve transactions, in order
+	Pipelines map[string][][]Package `json:"pipelines"`
+}
+
+func packageFromRPMMD(pkg rpmmd.Package) Package {
+	p := Package{
+		Name:            pkg.Name,
+		Epoch:           pkg.Epoch,
+		Version:         pkg.Version,
+		Release:         pkg.Release,
+		Arch:            pkg.Arch,
+		Checksum:        pkg.Checksum.String(),
+		RemoteLocations: pkg.RemoteLocations,
+	}
+
+	if repo := pkg.Repo; repo != nil {
+		switch {
+		case len(repo.BaseURLs) > 0:
+			p.RepoURL = repo.BaseURLs[0]
+		case repo.Metalink != "":
+			p.RepoURL = repo.Metalink
+		case repo.MirrorList != "":
+			p.RepoURL = repo.MirrorList
+		}
+	}
+
+	return p
+}
+
+// New creates a lockfile from the depsolve results of the pipelines of an
+// image
+func New(depsolved map[string]depsolvednf.DepsolveResult) *Lockfile {
+	lf := &Lockfile{
+		Version:   Version,
+		Pipelines: make(map[string][][]Package, len(depsolved)),
+	}
+
+	for name, result := range depsolved {
+		transactions := make([][]Package, len(result.Transactions))
+		for i, transaction := range result.Transactions {
+			transactions[i] = make([]Package, len(transaction))
+			for j, pkg := range transaction {
+				transactions[i][j] = packageFromRPMMD(pkg)
+			}
+			sort.Slice(transactions[i], func(a, b int) bool {
+				return transactions[i][a].NEVRA() < transactions[i][b].NEVRA()
+			})
+		}
+		lf.Pipelines[name] = transactions
+	}
+
+	return lf
+}
+
+// Validate checks that the lockfile is in a supported format and that each
+// package is fully specified
+func (lf *Lockfile) Validate() error {
+	if lf.Version != Version {
+		return fmt.Errorf("unsupported lockfile version %d", lf.Version)
+	}
+	if len(lf.Pipelines) == 0 {
+		return fmt.Errorf("lockfile doesn't lock any pipelines")
+	}
+
+	for name, transactions := range lf.Pipelines {
+		for _, transaction := range transactions {
+			for _, pkg := range transaction {
+				if pkg.Name == "" || pkg.Version == "" || pkg.Release == "" || pkg.Arch == "" {
+					return fmt.Errorf("package %q in pipeline %q is missing its name, version, release or arch", pkg.NEVRA(), name)
+				}
+				if pkg.Checksum == "" {
+					return fmt.Errorf("package %q in pipeline %q has no checksum", pkg.NEVRA(), name)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Pin replaces the packages of each package set with the locked packages of
+// the corresponding transaction. The package sets of each pipeline must match
+// the transactions of the lockfile one to one.
+func (lf *Lockfile) Pin(packageSets map[string][]rpmmd.PackageSet) error {
+	for name, chain := range packageSets {
+		transactions, ok := lf.Pipelines[name]
+		if !ok {
+			return fmt.Errorf("lockfile has no packages for pipeline %q", name)
+		}
+		if len(transactions) != len(chain) {
+			return fmt.Errorf("lockfile has %d transactions for pipeline %q, the image has %d", len(transactions), name, len(chain))
+		}
+
+		for i := range chain {
+			include := make([]string, len(transactions[i]))
+			for j, pkg := range transactions[i] {
+				include[j] = pkg.NEVRA()
+			}
+			chain[i].Include = include
+			chain[i].Exclude = nil
+			// weak dependencies are part of the locked packages already
+			chain[i].InstallWeakDeps = false
+		}
+	}
+
+	return nil
+}
+
+// Verify checks that the depsolved transactions of a pipeline consist of
+// exactly the locked packages, with matching checksums
+func (lf *Lockfile) Verify(pipeline string, transactions depsolvednf.TransactionList) error {
+	locked, ok := lf.Pipelines[pipeline]
+	if !ok {
+		return fmt.Errorf("lockfile has no packages for pipeline %q", pipeline)
+	}
+	if len(locked) != len(transactions) {
+		return fmt.Errorf("lockfile has %d transactions for pipeline %q, depsolving returned %d", len(locked), pipeline, len(transactions))
+	}
+
+	var problems []string
+	for i, transaction := range transactions {
+		depsolved := make(map[string]Package, len(transaction))
+		for _, pkg := range transaction {
+			p := packageFromRPMMD(pkg)
+			depsolved[p.NEVRA()] = p
+		}
+
+		for _, pkg := range locked[i] {
+			p, ok := depsolved[pkg.NEVRA()]
+			if !ok {
+				problems = append(problems, fmt.Sprintf("locked package %s is not available", pkg.NEVRA()))
+				continue
+			}
+			if p.Checksum != pkg.Checksum {
+				problems = append(problems, fmt.Sprintf("locked package %s has checksum %s, the repositories provide %s", pkg.NEVRA(), pkg.Checksum, p.Checksum))
+			}
+			delete(depsolved, pkg.NEVRA())
+		}
+
+		for nevra := range depsolved {
+			problems = append(problems, fmt.Sprintf("package %s is not locked", nevra))
+		}
+	}
+
+	if len(problems) > 0 {
+		slices.Sort(problems)
+		return fmt.Errorf("packages of pipeline %q don't match the lockfile: %s", pipeline, strings.Join(problems, "; "))
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package lockfile_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/image-builder/pkg/depsolvednf"
+	"github.com/osbuild/image-builder/pkg/rpmmd"
+
+	"github.com/osbuild/osbuild-composer/internal/lockfile"
+)
+
+var testRepo = rpmmd.RepoConfig{
+	Id:       "baseos",
+	BaseURLs: []string{"https://example.com/baseos"},
+}
+
+func testPackage(name string, epoch uint, version, checksum string) rpmmd.Package {
+	return rpmmd.Package{
+		Name:            name,
+		Epoch:           epoch,
+		Version:         version,
+		Release:         "1.fc42",
+		Arch:            "x86_64",
+		Checksum:        rpmmd.Checksum{Type: "sha256", Value: checksum},
+		RemoteLocations: []string{"https://example.com/baseos/" + name + ".rpm"},
+		RepoID:          testRepo.Id,
+		Repo:            &testRepo,
+	}
+}
+
+func testDepsolved() map[string]depsolvednf.DepsolveResult {
+	return map[string]depsolvednf.DepsolveResult{
+		"build": {
+			Transactions: depsolvednf.TransactionList{
+				{testPackage("rpm", 0, "4.20", "aa")},
+			},
+		},
+		"os": {
+			Transactions: depsolvednf.TransactionList{
+				{testPackage("kernel", 0, "6.14", "bb"), testPackage("bash", 0, "5.2", "cc")},
+				{testPackage("dhcp-client", 12, "4.4.3", "dd")},
+			},
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	lf := lockfile.New(testDepsolved())
+	require.NoError(t, lf.Validate())
+
+	assert.Equal(t, &lockfile.Lockfile{
+		Version: lockfile.Version,
+		Pipelines: map[string][][]lockfile.Package{
+			"build": {
+				{
+					{Name: "rpm", Version: "4.20", Release: "1.fc42", Arch: "x86_64", Checksum: "sha256:aa", RemoteLocations: []string{"https://example.com/baseos/rpm.rpm"}, RepoURL: "https://example.com/baseos"},
+				},
+			},
+			"os": {
+				{
+					{Name: "bash", Version: "5.2", Release: "1.fc42", Arch: "x86_64", Checksum: "sha256:cc", RemoteLocations: []string{"https://example.com/baseos/bash.rpm"}, RepoURL: "https://example.com/baseos"},
+					{Name: "kernel", Version: "6.14", Release: "1.fc42", Arch: "x86_64", Checksum: "sha256:bb", RemoteLocations: []string{"https://example.com/baseos/kernel.rpm"}, RepoURL: "https://example.com/baseos"},
+				},
+				{
+					{Name: "dhcp-client", Epoch: 12, Version: "4.4.3", Release: "1.fc42", Arch: "x86_64", Checksum: "sha256:dd", RemoteLocations: []string{"https://example.com/baseos/dhcp-client.rpm"}, RepoURL: "https://example.com/baseos"},
+				},
+			},
+		},
+	}, lf)
+}
+
+func TestValidate(t *testing.T) {
+	lf := lockfile.New(testDepsolved())
+	lf.Version = 2
+	assert.EqualError(t, lf.Validate(), "unsupported lockfile version 2")
+
+	lf = lockfile.New(testDepsolved())
+	lf.Pipelines["build"][0][0].Checksum = ""
+	assert.EqualError(t, lf.Validate(), `package "rpm-4.20-1.fc42.x86_64" in pipeline "build" has no checksum`)
+
+	assert.EqualError(t, (&lockfile.Lockfile{Version: lockfile.Version}).Validate(), "lockfile doesn't lock any pipelines")
+}
+
+func TestPin(t *testing.T) {
+	lf := lockfile.New(testDepsolved())
+
+	packageSets := map[string][]rpmmd.PackageSet{
+		"build": {
+			{Include: []string{"rpm"}, Repositories: []rpmmd.RepoConfig{testRepo}, InstallWeakDeps: true},
+		},
+		"os": {
+			{Include: []string{"kernel", "@core"}, Exclude: []string{"dracut-config-rescue"}, Repositories: []rpmmd.RepoConfig{testRepo}},
+			{Include: []string{"dhcp-client"}, Repositories: []rpmmd.RepoConfig{testRepo}},
+		},
+	}
+	require.NoError(t, lf.Pin(packageSets))
+
+	assert.Equal(t, map[string][]rpmmd.PackageSet{
+		"build": {
+			{Include: []string{"rpm-4.20-1.fc42.x86_64"}, Repositories: []rpmmd.RepoConfig{testRepo}},
+		},
+		"os": {
+			{Include: []string{"bash-5.2-1.fc42.x86_64", "kernel-6.14-1.fc42.x86_64"}, Repositories: []rpmmd.RepoConfig{testRepo}},
+			{Include: []string{"dhcp-client-12:4.4.3-1.fc42.x86_64"}, Repositories: []rpmmd.RepoConfig{testRepo}},
+		},
+	}, packageSets)
+
+	err := lf.Pin(map[string][]rpmmd.PackageSet{"installer": {{Include: []string{"anaconda"}}}})
+	assert.EqualError(t, err, `lockfile has no packages for pipeline "installer"`)
+
+	err = lf.Pin(map[string][]rpmmd.PackageSet{"os": {{Include: []string{"kernel"}}}})
+	assert.EqualError(t, err, `lockfile has 2 transactions for pipeline "os", the image has 1`)
+}
+
+func TestVerify(t *testing.T) {
+	lf := lockfile.New(testDepsolved())
+
+	for name, result := range testDepsolved() {
+		assert.NoError(t, lf.Verify(name, result.Transactions))
+	}
+
+	// bash got updated and the kernel was rebuilt with the same NEVRA
+	changed := depsolvednf.TransactionList{
+		{testPackage("kernel", 0, "6.14", "ee"), testPackage("bash", 0, "5.3", "ff")},
+		{testPackage("dhcp-client", 12, "4.4.3", "dd")},
+	}
+	err := lf.Verify("os", changed)
+	assert.EqualError(t, err, `packages of pipeline "os" don't match the lockfile: `+
+		`locked package bash-5.2-1.fc42.x86_64 is not available; `+
+		`locked package kernel-6.14-1.fc42.x86_64 has checksum sha256:bb, the repositories provide sha256:ee; `+
+		`package bash-5.3-1.fc42.x86_64 is not locked`)
+
+	err = lf.Verify("os", changed[:1])
+	assert.EqualError(t, err, `lockfile has 2 transactions for pipeline "os", depsolving returned 1`)
+}
@@ -0,0 +1,275 @@
+package logsink
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Splunk sends entries to the event endpoint of a Splunk HTTP Event
+// Collector, e.g. "https://splunk.example.com:8088/services/collector/event"
+type Splunk struct {
+	URL   string
+	Token string
+	// Source and Host identify the sender of the entries
+	Source string
+	Host   string
+}
+
+type splunkPayload struct {
+	// splunk expects unix time in seconds
+	Time  int64       `json:"time"`
+	Host  string      `json:"host"`
+	Event splunkEvent `json:"event"`
+}
+
+type splunkEvent struct {
+	Message string            `json:"message"`
+	Ident   string            `json:"ident"`
+	Host    string            `json:"host"`
+	Level   string            `json:"level"`
+	Fields  map[string]string `json:"fields,omitempty"`
+}
+
+func (b *Splunk) NewRequest(entries []Entry) (*http.Request, error) {
+	// the collector takes a stream of events instead of an array
+	var body []byte
+	for _, entry := range entries {
+		data, err := json.Marshal(splunkPayload{
+			Time: entry.Time.Unix(),
+			Host: b.Host,
+			Event: splunkEvent{
+				Message: entry.Message,
+				Ident:   b.Source,
+				Host:    b.Host,
+				Level:   entry.Level,
+				Fields:  entry.Fields,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		body = append(body, data...)
+	}
+	return newJSONRequest(b.URL, body, map[string]string{
+		"Authorization": "Splunk " + b.Token,
+	})
+}
+
+// Loki sends entries to the push API of Loki, e.g.
+// "https://loki.example.com/loki/api/v1/push". The entries are put into a
+// stream per level, labeled with the source, the host and Labels.
+type Loki struct {
+	URL     string
+	Headers map[string]string
+	Labels  map[string]string
+	Source  string
+	Host    string
+}
+
+type lokiPush struct {
+	Streams []lokiStream `json:"streams"`
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	// pairs of the timestamp in nanoseconds and the line
+	Values [][2]string `json:"values"`
+}
+
+type lokiLine struct {
+	Message string            `json:"message"`
+	Fields  map[string]string `json:"fields,omitempty"`
+}
+
+func (b *Loki) NewRequest(entries []Entry) (*http.Request, error) {
+	streams := map[string]*lokiStream{}
+	var levels []string
+	for _, entry := range entries {
+		stream, ok := streams[entry.Level]
+		if !ok {
+			labels := map[string]string{
+				"source": b.Source,
+				"host":   b.Host,
+				"level":  entry.Level,
+			}
+			for key, value := range b.Labels {
+				labels[key] = value
+			}
+			stream = &lokiStream{Stream: labels}
+			streams[entry.Level] = stream
+			levels = append(levels, entry.Level)
+		}
+
+		line, err := json.Marshal(lokiLine{
+			Message: entry.Message,
+			Fields:  entry.Fields,
+		})
+		if err != nil {
+			return nil, err
+		}
+		stream.Values = append(stream.Values, [2]string{strconv.FormatInt(entry.Time.UnixNano(), 10), string(line)})
+	}
+
+	var push lokiPush
+	sort.Strings(levels)
+	for _, level := range levels {
+		push.Streams = append(push.Streams, *streams[level])
+	}
+	body, err := json.Marshal(push)
+	if err != nil {
+		return nil, err
+	}
+	return newJSONRequest(b.URL, body, b.Headers)
+}
+
+// HTTP posts the entries as a JSON array of objects to URL, for log stores
+// without a dedicated backend
+type HTTP struct {
+	URL     string
+	Headers map[string]string
+	Source  string
+	Host    string
+}
+
+type httpEntry struct {
+	Time    time.Time         `json:"time"`
+	Level   string            `json:"level"`
+	Message string            `json:"message"`
+	Source  string            `json:"source"`
+	Host    string            `json:"host"`
+	Fields  map[string]string `json:"fields,omitempty"`
+}
+
+func (b *HTTP) NewRequest(entries []Entry) (*http.Request, error) {
+	payload := make([]httpEntry, 0, len(entries))
+	for _, entry := range entries {
+		payload = append(payload, httpEntry{
+			Time:    entry.Time,
+			Level:   entry.Level,
+			Message: entry.Message,
+			Source:  b.Source,
+			Host:    b.Host,
+			Fields:  entry.Fields,
+		})
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return newJSONRequest(b.URL, body, b.Headers)
+}
+
+// OTLP sends entries as OpenTelemetry log records to an OTLP/HTTP endpoint,
+// e.g. "https://collector.example.com:4318/v1/logs", using the JSON encoding
+type OTLP struct {
+	URL     string
+	Headers map[string]string
+	// Source is the service.name and Host the host.name of the resource
+	Source string
+	Host   string
+}
+
+type otlpLogs struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+type otlpResourceLogs struct {
+	Resource  otlpResource    `json:"resource"`
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeLogs struct {
+	Scope      otlpScope       `json:"scope"`
+	LogRecords []otlpLogRecord `json:"logRecords"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpLogRecord struct {
+	// 64 bit integers are strings in the JSON encoding
+	TimeUnixNano   string          `json:"timeUnixNano"`
+	SeverityNumber int             `json:"severityNumber"`
+	SeverityText   string          `json:"severityText"`
+	Body           otlpValue       `json:"body"`
+	Attributes     []otlpAttribute `json:"attributes,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+// otlpSeverities maps the logrus levels to the severity numbers of the
+// OpenTelemetry log data model
+var otlpSeverities = map[string]int{
+	"trace":   1,
+	"debug":   5,
+	"info":    9,
+	"warning": 13,
+	"error":   17,
+	"fatal":   21,
+	"panic":   24,
+}
+
+func otlpAttributes(fields map[string]string) []otlpAttribute {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var attributes []otlpAttribute
+	for _, key := range keys {
+		attributes = append(attributes, otlpAttribute{Key: key, Value: otlpValue{StringValue: fields[key]}})
+	}
+	return attributes
+}
+
+func (b *OTLP) NewRequest(entries []Entry) (*http.Request, error) {
+	records := make([]otlpLogRecord, 0, len(entries))
+	for _, entry := range entries {
+		records = append(records, otlpLogRecord{
+			TimeUnixNano:   strconv.FormatInt(entry.Time.UnixNano(), 10),
+			SeverityNumber: otlpSeverities[entry.Level],
+			SeverityText:   entry.Level,
+			Body:           otlpValue{StringValue: entry.Message},
+			Attributes:     otlpAttributes(entry.Fields),
+		})
+	}
+
+	body, err := json.Marshal(otlpLogs{
+		ResourceLogs: []otlpResourceLogs{
+			{
+				Resource: otlpResource{
+					Attributes: otlpAttributes(map[string]string{
+						"service.name": b.Source,
+						"host.name":    b.Host,
+					}),
+				},
+				ScopeLogs: []otlpScopeLogs{
+					{
+						Scope:      otlpScope{Name: "github.com/osbuild/osbuild-composer"},
+						LogRecords: records,
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newJSONRequest(b.URL, body, b.Headers)
+}
@@ -0,0 +1,185 @@
+package logsink
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	sentrylogrus "github.com/getsentry/sentry-go/logrus"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	TypeSplunk    = "splunk"
+	TypeLoki      = "loki"
+	TypeHTTP      = "http"
+	TypeOTLP      = "otlp"
+	TypeGlitchTip = "glitchtip"
+)
+
+// Config of a sink, it's shared by the configuration files of composer and
+// the workers
+type Config struct {
+	// Type is one of "splunk", "loki", "http", "otlp" or "glitchtip"
+	Type string `toml:"type"`
+	// URL the entries are sent to, the DSN for GlitchTip
+	URL string `toml:"url"`
+	// Token is sent as the HEC token to Splunk and as a bearer token to
+	// the other backends
+	Token   string            `toml:"token"`
+	Headers map[string]string `toml:"headers"`
+	// Labels are added to the streams of Loki
+	Labels map[string]string `toml:"labels"`
+	// Level is the lowest level which is sent, defaults to "debug" and to
+	// "error" for GlitchTip
+	Level         string        `toml:"level"`
+	BatchSize     int           `toml:"batch_size"`
+	FlushInterval time.Duration `toml:"flush_interval"`
+	// Formatted sends the entries as formatted by logrus instead of the
+	// message and the fields apart, the sink of the SPLUNK_HEC_*
+	// environment variables always sent them like this
+	Formatted bool `toml:"-"`
+}
+
+func (c Config) level() (logrus.Level, error) {
+	if c.Level != "" {
+		return logrus.ParseLevel(c.Level)
+	}
+	if c.Type == TypeGlitchTip {
+		return logrus.ErrorLevel, nil
+	}
+	return logrus.DebugLevel, nil
+}
+
+// Validate checks the configuration without contacting the log store
+func (c Config) Validate() error {
+	switch c.Type {
+	case TypeSplunk, TypeLoki, TypeHTTP, TypeOTLP:
+		u, err := url.Parse(c.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%s log sink needs an http or https url, got %q", c.Type, c.URL)
+		}
+		if c.Type == TypeSplunk && c.Token == "" {
+			return fmt.Errorf("splunk log sink needs a token")
+		}
+	case TypeGlitchTip:
+		if _, err := sentry.NewDsn(c.URL); err != nil {
+			return fmt.Errorf("glitchtip log sink needs a DSN as url: %v", err)
+		}
+	default:
+		return fmt.Errorf("unknown log sink type %q", c.Type)
+	}
+
+	if _, err := c.level(); err != nil {
+		return fmt.Errorf("%s log sink: %v", c.Type, err)
+	}
+	if c.BatchSize < 0 {
+		return fmt.Errorf("%s log sink: batch_size must not be negative, got %d", c.Type, c.BatchSize)
+	}
+	if c.FlushInterval < 0 {
+		return fmt.Errorf("%s log sink: flush_interval must not be negative, got %s", c.Type, c.FlushInterval)
+	}
+	return nil
+}
+
+// Open starts the configured sink for the logs of source, e.g.
+// "osbuild-composer". The returned hook must be added to logrus and the
+// returned function called before exiting to send the remaining entries.
+func Open(ctx context.Context, config Config, source string) (logrus.Hook, func(), error) {
+	if err := config.Validate(); err != nil {
+		return nil, nil, err
+	}
+	level, err := config.level()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if config.Type == TypeGlitchTip {
+		// GlitchTip speaks the sentry protocol, which has a client of its
+		// own. The client is installed globally for the echo middleware.
+		err := sentry.Init(sentry.ClientOptions{
+			Dsn: config.URL,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		hook := sentrylogrus.NewLogHookFromClient(logrus.AllLevels[:level+1], sentry.CurrentHub().Client())
+		return hook, func() { hook.Flush(5 * time.Second) }, nil
+	}
+
+	host, err := os.Hostname()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	headers := map[string]string{}
+	for key, value := range config.Headers {
+		headers[key] = value
+	}
+	if config.Token != "" && config.Type != TypeSplunk {
+		headers["Authorization"] = "Bearer " + config.Token
+	}
+
+	var backend Backend
+	switch config.Type {
+	case TypeSplunk:
+		backend = &Splunk{URL: config.URL, Token: config.Token, Source: source, Host: host}
+	case TypeLoki:
+		backend = &Loki{URL: config.URL, Headers: headers, Labels: config.Labels, Source: source, Host: host}
+	case TypeHTTP:
+		backend = &HTTP{URL: config.URL, Headers: headers, Source: source, Host: host}
+	case TypeOTLP:
+		backend = &OTLP{URL: config.URL, Headers: headers, Source: source, Host: host}
+	}
+
+	sink := New(ctx, backend, Options{
+		BatchSize:     config.BatchSize,
+		FlushInterval: config.FlushInterval,
+	})
+	return &Hook{Sink: sink, LogLevels: logrus.AllLevels[:level+1], Formatted: config.Formatted}, sink.Close, nil
+}
+
+// Hook queues the entries of logrus in a sink
+type Hook struct {
+	Sink      *Sink
+	LogLevels []logrus.Level
+	// Formatted sends the entries as formatted by the logger, with the
+	// fields in the message
+	Formatted bool
+}
+
+func (h *Hook) Levels() []logrus.Level {
+	return h.LogLevels
+}
+
+func (h *Hook) Fire(entry *logrus.Entry) error {
+	if h.Formatted {
+		message, err := entry.String()
+		if err != nil {
+			return err
+		}
+		return h.Sink.Log(Entry{
+			Time:    entry.Time,
+			Level:   entry.Level.String(),
+			Message: message,
+		})
+	}
+
+	var fields map[string]string
+	if len(entry.Data) > 0 {
+		fields = make(map[string]string, len(entry.Data))
+		for key, value := range entry.Data {
+			fields[key] = fmt.Sprint(value)
+		}
+	}
+	return h.Sink.Log(Entry{
+		Time:    entry.Time,
+		Level:   entry.Level.String(),
+		Message: strings.TrimSuffix(entry.Message, "\n"),
+		Fields:  fields,
+	})
+}
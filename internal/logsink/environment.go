@@ -1,9 +1,10 @@
-package logger
+package logsink
 
 import (
 	"github.com/sirupsen/logrus"
 )
 
+// EnvironmentHook adds the deployment channel to every entry
 type EnvironmentHook struct {
 	Channel string
 }
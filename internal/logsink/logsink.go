@@ -0,0 +1,194 @@
+// Package logsink forwards the log entries of composer and the workers to
+// external log stores.
+//
+// A Sink buffers the entries and sends them in batches, either when a batch
+// is full or periodically. The protocol of the store is implemented by a
+// Backend, which turns a batch into an HTTP request. Sinks are configured
+// with a Config and attached to logrus as a hook.
+package logsink
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+const (
+	DefaultBatchSize     = 1000
+	DefaultBufferSize    = 10000
+	DefaultFlushInterval = 5 * time.Second
+)
+
+// Entry is a single log entry
+type Entry struct {
+	Time time.Time
+	// Level is the name of the logrus level, e.g. "info"
+	Level   string
+	Message string
+	Fields  map[string]string
+}
+
+// Backend implements the protocol of a log store
+type Backend interface {
+	// NewRequest returns the request delivering a batch of entries
+	NewRequest(entries []Entry) (*http.Request, error)
+}
+
+// Options of a sink, zero values are replaced by the defaults
+type Options struct {
+	// BatchSize is the maximum number of entries sent at once
+	BatchSize int
+	// BufferSize is the number of entries waiting to be sent, further
+	// entries are dropped
+	BufferSize int
+	// FlushInterval is how often the buffered entries are sent
+	FlushInterval time.Duration
+	// Client sends the requests, by default a client retrying failed
+	// requests is used
+	Client *http.Client
+}
+
+// Sink sends entries to a backend in batches
+type Sink struct {
+	backend Backend
+	client  *http.Client
+	options Options
+
+	entries chan Entry
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// New starts a sink sending to backend. The remaining entries are sent when
+// ctx is canceled or the sink is closed.
+func New(ctx context.Context, backend Backend, options Options) *Sink {
+	if options.BatchSize <= 0 {
+		options.BatchSize = DefaultBatchSize
+	}
+	if options.BufferSize <= 0 {
+		options.BufferSize = DefaultBufferSize
+	}
+	if options.FlushInterval <= 0 {
+		options.FlushInterval = DefaultFlushInterval
+	}
+	client := options.Client
+	if client == nil {
+		rc := retryablehttp.NewClient()
+		rc.Logger = nil
+		client = rc.StandardClient()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s := &Sink{
+		backend: backend,
+		client:  client,
+		options: options,
+		entries: make(chan Entry, options.BufferSize),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	go s.run(ctx)
+	return s
+}
+
+// Log queues an entry. It fails instead of blocking if the buffer is full,
+// e.g. because the log store can't be reached.
+func (s *Sink) Log(entry Entry) error {
+	select {
+	case s.entries <- entry:
+		return nil
+	default:
+		return fmt.Errorf("log sink buffer is full, dropping entry")
+	}
+}
+
+// Close sends the remaining entries and stops the sink
+func (s *Sink) Close() {
+	s.cancel()
+	<-s.done
+}
+
+func (s *Sink) run(ctx context.Context) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.options.FlushInterval)
+	defer ticker.Stop()
+
+	var batch []Entry
+	flush := func() {
+		// the sink can't log its own errors through logrus
+		if err := s.send(batch); err != nil {
+			fmt.Fprintf(os.Stderr, "Log sink unable to send %d entries: %v\n", len(batch), err)
+		}
+		batch = nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			for {
+				select {
+				case entry := <-s.entries:
+					batch = append(batch, entry)
+					if len(batch) == s.options.BatchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		case entry := <-s.entries:
+			batch = append(batch, entry)
+			if len(batch) == s.options.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+func (s *Sink) send(entries []Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	req, err := s.backend.NewRequest(entries)
+	if err != nil {
+		return err
+	}
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			return fmt.Errorf("unexpected status %d, reading the response failed: %v", res.StatusCode, err)
+		}
+		return fmt.Errorf("unexpected status %d: %s", res.StatusCode, body)
+	}
+	return nil
+}
+
+// newJSONRequest returns a POST request with a JSON body
+func newJSONRequest(url string, body []byte, headers map[string]string) (*http.Request, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	return req, nil
+}
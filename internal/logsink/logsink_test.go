@@ -0,0 +1,365 @@
+package logsink_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/logsink"
+)
+
+// standIn records the requests a log store would receive
+type standIn struct {
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func newStandIn(t *testing.T) (*standIn, *httptest.Server) {
+	s := &standIn{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.requests = append(s.requests, r)
+		s.bodies = append(s.bodies, body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+	return s, server
+}
+
+func (s *standIn) received() ([]*http.Request, [][]byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*http.Request{}, s.requests...), append([][]byte{}, s.bodies...)
+}
+
+var entries = []logsink.Entry{
+	{
+		Time:    time.Unix(1700000000, 500),
+		Level:   "info",
+		Message: "Running job",
+		Fields:  map[string]string{"job": "42"},
+	},
+	{
+		Time:    time.Unix(1700000001, 0),
+		Level:   "error",
+		Message: "Job failed",
+	},
+}
+
+func TestBatching(t *testing.T) {
+	s, server := newStandIn(t)
+
+	sink := logsink.New(context.Background(), &logsink.HTTP{URL: server.URL}, logsink.Options{
+		BatchSize:     2,
+		FlushInterval: time.Hour,
+	})
+	for i := 0; i < 3; i++ {
+		require.NoError(t, sink.Log(entries[0]))
+	}
+
+	// a full batch is sent right away
+	require.Eventually(t, func() bool {
+		requests, _ := s.received()
+		return len(requests) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the rest when the sink is closed
+	sink.Close()
+	_, bodies := s.received()
+	require.Len(t, bodies, 2)
+
+	var batch []interface{}
+	require.NoError(t, json.Unmarshal(bodies[0], &batch))
+	assert.Len(t, batch, 2)
+	require.NoError(t, json.Unmarshal(bodies[1], &batch))
+	assert.Len(t, batch, 1)
+}
+
+func TestFlushInterval(t *testing.T) {
+	s, server := newStandIn(t)
+
+	sink := logsink.New(context.Background(), &logsink.HTTP{URL: server.URL}, logsink.Options{
+		FlushInterval: 10 * time.Millisecond,
+	})
+	defer sink.Close()
+	require.NoError(t, sink.Log(entries[0]))
+
+	require.Eventually(t, func() bool {
+		requests, _ := s.received()
+		return len(requests) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestFullBuffer(t *testing.T) {
+	// the stand-in blocks the first batch, so the entries pile up
+	block := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-block
+	}))
+	defer server.Close()
+
+	sink := logsink.New(context.Background(), &logsink.HTTP{URL: server.URL}, logsink.Options{
+		BatchSize:     1,
+		BufferSize:    1,
+		FlushInterval: time.Hour,
+	})
+	require.NoError(t, sink.Log(entries[0]))
+	require.Eventually(t, func() bool {
+		return sink.Log(entries[0]) != nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	close(block)
+	sink.Close()
+}
+
+func TestSplunk(t *testing.T) {
+	s, server := newStandIn(t)
+
+	sink := logsink.New(context.Background(), &logsink.Splunk{
+		URL:    server.URL + "/services/collector/event",
+		Token:  "hec-token",
+		Source: "osbuild-composer",
+		Host:   "composer-1",
+	}, logsink.Options{})
+	for _, entry := range entries {
+		require.NoError(t, sink.Log(entry))
+	}
+	sink.Close()
+
+	requests, bodies := s.received()
+	require.Len(t, requests, 1)
+	assert.Equal(t, "/services/collector/event", requests[0].URL.Path)
+	assert.Equal(t, "Splunk hec-token", requests[0].Header.Get("Authorization"))
+
+	// the events are concatenated
+	decoder := json.NewDecoder(bytes.NewReader(bodies[0]))
+	var events []map[string]interface{}
+	for decoder.More() {
+		var event map[string]interface{}
+		require.NoError(t, decoder.Decode(&event))
+		events = append(events, event)
+	}
+	require.Len(t, events, 2)
+	assert.EqualValues(t, 1700000000, events[0]["time"])
+	assert.Equal(t, "composer-1", events[0]["host"])
+	assert.Equal(t, map[string]interface{}{
+		"message": "Running job",
+		"ident":   "osbuild-composer",
+		"host":    "composer-1",
+		"level":   "info",
+		"fields":  map[string]interface{}{"job": "42"},
+	}, events[0]["event"])
+}
+
+func TestLoki(t *testing.T) {
+	s, server := newStandIn(t)
+
+	sink := logsink.New(context.Background(), &logsink.Loki{
+		URL:     server.URL + "/loki/api/v1/push",
+		Headers: map[string]string{"X-Scope-OrgID": "composer"},
+		Labels:  map[string]string{"env": "staging"},
+		Source:  "osbuild-worker",
+		Host:    "worker-1",
+	}, logsink.Options{})
+	for _, entry := range entries {
+		require.NoError(t, sink.Log(entry))
+	}
+	sink.Close()
+
+	requests, bodies := s.received()
+	require.Len(t, requests, 1)
+	assert.Equal(t, "/loki/api/v1/push", requests[0].URL.Path)
+	assert.Equal(t, "composer", requests[0].Header.Get("X-Scope-OrgID"))
+	assert.JSONEq(t, `{
+		"streams": [
+			{
+				"stream": {"source": "osbuild-worker", "host": "worker-1", "level": "error", "env": "staging"},
+				"values": [["1700000001000000000", "{\"message\":\"Job failed\"}"]]
+			},
+			{
+				"stream": {"source": "osbuild-worker", "host": "worker-1", "level": "info", "env": "staging"},
+				"values": [["1700000000000000500", "{\"message\":\"Running job\",\"fields\":{\"job\":\"42\"}}"]]
+			}
+		]
+	}`, string(bodies[0]))
+}
+
+func TestHTTP(t *testing.T) {
+	s, server := newStandIn(t)
+
+	sink := logsink.New(context.Background(), &logsink.HTTP{
+		URL:     server.URL + "/logs",
+		Headers: map[string]string{"Authorization": "Bearer token"},
+		Source:  "osbuild-composer",
+		Host:    "composer-1",
+	}, logsink.Options{})
+	require.NoError(t, sink.Log(entries[0]))
+	sink.Close()
+
+	requests, bodies := s.received()
+	require.Len(t, requests, 1)
+	assert.Equal(t, "Bearer token", requests[0].Header.Get("Authorization"))
+	assert.Equal(t, "application/json", requests[0].Header.Get("Content-Type"))
+	assert.JSONEq(t, `[{
+		"time": "`+entries[0].Time.Format(time.RFC3339Nano)+`",
+		"level": "info",
+		"message": "Running job",
+		"source": "osbuild-composer",
+		"host": "composer-1",
+		"fields": {"job": "42"}
+	}]`, string(bodies[0]))
+}
+
+func TestOTLP(t *testing.T) {
+	s, server := newStandIn(t)
+
+	sink := logsink.New(context.Background(), &logsink.OTLP{
+		URL:    server.URL + "/v1/logs",
+		Source: "osbuild-composer",
+		Host:   "composer-1",
+	}, logsink.Options{})
+	for _, entry := range entries {
+		require.NoError(t, sink.Log(entry))
+	}
+	sink.Close()
+
+	requests, bodies := s.received()
+	require.Len(t, requests, 1)
+	assert.Equal(t, "/v1/logs", requests[0].URL.Path)
+	assert.JSONEq(t, `{
+		"resourceLogs": [{
+			"resource": {
+				"attributes": [
+					{"key": "host.name", "value": {"stringValue": "composer-1"}},
+					{"key": "service.name", "value": {"stringValue": "osbuild-composer"}}
+				]
+			},
+			"scopeLogs": [{
+				"scope": {"name": "github.com/osbuild/osbuild-composer"},
+				"logRecords": [
+					{
+						"timeUnixNano": "1700000000000000500",
+						"severityNumber": 9,
+						"severityText": "info",
+						"body": {"stringValue": "Running job"},
+						"attributes": [{"key": "job", "value": {"stringValue": "42"}}]
+					},
+					{
+						"timeUnixNano": "1700000001000000000",
+						"severityNumber": 17,
+						"severityText": "error",
+						"body": {"stringValue": "Job failed"}
+					}
+				]
+			}]
+		}]
+	}`, string(bodies[0]))
+}
+
+func TestOpen(t *testing.T) {
+	s, server := newStandIn(t)
+
+	hook, closeSink, err := logsink.Open(context.Background(), logsink.Config{
+		Type:  logsink.TypeLoki,
+		URL:   server.URL,
+		Token: "token",
+		Level: "warning",
+	}, "osbuild-worker")
+	require.NoError(t, err)
+	assert.Equal(t, []logrus.Level{logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel, logrus.WarnLevel}, hook.Levels())
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	logger.AddHook(hook)
+	logger.WithField("job", 42).Warn("Job failed")
+	closeSink()
+
+	requests, bodies := s.received()
+	require.Len(t, requests, 1)
+	assert.Equal(t, "Bearer token", requests[0].Header.Get("Authorization"))
+
+	var push struct {
+		Streams []struct {
+			Stream map[string]string `json:"stream"`
+			Values [][2]string       `json:"values"`
+		} `json:"streams"`
+	}
+	require.NoError(t, json.Unmarshal(bodies[0], &push))
+	require.Len(t, push.Streams, 1)
+	assert.Equal(t, "warning", push.Streams[0].Stream["level"])
+	assert.Equal(t, "osbuild-worker", push.Streams[0].Stream["source"])
+	require.Len(t, push.Streams[0].Values, 1)
+	assert.JSONEq(t, `{"message": "Job failed", "fields": {"job": "42"}}`, push.Streams[0].Values[0][1])
+}
+
+func TestOpenFormatted(t *testing.T) {
+	s, server := newStandIn(t)
+
+	hook, closeSink, err := logsink.Open(context.Background(), logsink.Config{
+		Type:      logsink.TypeSplunk,
+		URL:       server.URL,
+		Token:     "hec-token",
+		Formatted: true,
+	}, "osbuild-composer")
+	require.NoError(t, err)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	logger.SetFormatter(&logrus.TextFormatter{DisableTimestamp: true, DisableColors: true})
+	// the channel is added before the entry reaches the sink
+	logger.AddHook(&logsink.EnvironmentHook{Channel: "production"})
+	logger.AddHook(hook)
+	logger.WithField("job", 42).Info("Running job")
+	closeSink()
+
+	_, bodies := s.received()
+	require.Len(t, bodies, 1)
+	var payload struct {
+		Event map[string]interface{} `json:"event"`
+	}
+	require.NoError(t, json.Unmarshal(bodies[0], &payload))
+	assert.Equal(t, "level=info msg=\"Running job\" channel=production job=42\n", payload.Event["message"])
+	assert.NotContains(t, payload.Event, "fields")
+}
+
+func TestValidate(t *testing.T) {
+	valid := []logsink.Config{
+		{Type: logsink.TypeSplunk, URL: "https://splunk.example.com:8088/services/collector/event", Token: "token"},
+		{Type: logsink.TypeLoki, URL: "http://loki:3100/loki/api/v1/push", Level: "info"},
+		{Type: logsink.TypeHTTP, URL: "https://logs.example.com", BatchSize: 10, FlushInterval: time.Second},
+		{Type: logsink.TypeOTLP, URL: "http://collector:4318/v1/logs"},
+		{Type: logsink.TypeGlitchTip, URL: "https://key@glitchtip.example.com/1"},
+	}
+	for _, config := range valid {
+		assert.NoError(t, config.Validate(), config.Type)
+	}
+
+	invalid := map[string]logsink.Config{
+		`unknown log sink type "syslog"`:                              {Type: "syslog"},
+		`loki log sink needs an http or https url, got "loki:3100"`:   {Type: logsink.TypeLoki, URL: "loki:3100"},
+		`splunk log sink needs a token`:                               {Type: logsink.TypeSplunk, URL: "https://splunk.example.com"},
+		`http log sink: not a valid logrus Level: "loud"`:             {Type: logsink.TypeHTTP, URL: "https://logs.example.com", Level: "loud"},
+		`otlp log sink: batch_size must not be negative, got -1`:      {Type: logsink.TypeOTLP, URL: "https://collector", BatchSize: -1},
+		`otlp log sink: flush_interval must not be negative, got -1s`: {Type: logsink.TypeOTLP, URL: "https://collector", FlushInterval: -time.Second},
+	}
+	for message, config := range invalid {
+		assert.EqualError(t, config.Validate(), message)
+	}
+
+	// a DSN needs the public key of the project
+	assert.Error(t, logsink.Config{Type: logsink.TypeGlitchTip, URL: "https://glitchtip.example.com/1"}.Validate())
+}
@@ -0,0 +1,410 @@
+// Package manifestjob enqueues the content resolve jobs a manifest depends on
+// and serializes the manifest from their results once they are done. It is
+// shared by the cloud and weldr APIs, which both hand the manifest job of a
+// compose to composer itself instead of a worker.
+package manifestjob
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/osbuild/image-builder/pkg/container"
+	"github.com/osbuild/image-builder/pkg/depsolvednf"
+	"github.com/osbuild/image-builder/pkg/distro"
+	"github.com/osbuild/image-builder/pkg/manifest"
+	"github.com/osbuild/image-builder/pkg/ostree"
+	"github.com/osbuild/image-builder/pkg/rpmmd"
+	"github.com/osbuild/image-builder/pkg/sbom"
+	"github.com/osbuild/osbuild-composer/internal/common"
+	"github.com/osbuild/osbuild-composer/internal/lockfile"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+	"github.com/osbuild/osbuild-composer/internal/worker/clienterrors"
+	"github.com/osbuild/osbuild-composer/pkg/jobqueue"
+)
+
+// SourceFunc is a factory function that produces a "manifest source" object.
+// For the standard (package-based) flow it simply returns a pre-built *manifest.Manifest.
+// For bootc it reconstructs the manifest from BootcInfoResolve results.
+type SourceFunc func() (*manifest.Manifest, error)
+
+// SerializedFunc is called with the serialized manifest and the depsolve
+// results it was serialized from, before the manifest job is finished
+type SerializedFunc func(mf manifest.OSBuildManifest, depsolved map[string]depsolvednf.DepsolveResult)
+
+// Dependencies holds the IDs of the jobs a manifest job depends on
+type Dependencies struct {
+	DepsolveJobID         uuid.UUID
+	ContainerResolveJobID uuid.UUID
+	OSTreeResolveJobID    uuid.UUID
+	BootcInfoResolveJobID uuid.UUID
+	BootcPreManifestJobID uuid.UUID
+}
+
+// IDs returns a slice of the non-nil job IDs.
+func (d Dependencies) IDs() []uuid.UUID {
+	var ids []uuid.UUID
+	if d.DepsolveJobID != uuid.Nil {
+		ids = append(ids, d.DepsolveJobID)
+	}
+	if d.ContainerResolveJobID != uuid.Nil {
+		ids = append(ids, d.ContainerResolveJobID)
+	}
+	if d.OSTreeResolveJobID != uuid.Nil {
+		ids = append(ids, d.OSTreeResolveJobID)
+	}
+	if d.BootcInfoResolveJobID != uuid.Nil {
+		ids = append(ids, d.BootcInfoResolveJobID)
+	}
+	if d.BootcPreManifestJobID != uuid.Nil {
+		ids = append(ids, d.BootcPreManifestJobID)
+	}
+	return ids
+}
+
+// EnqueueResolveJobs adds all the necessary content resolve jobs for the
+// manifest to the queue and returns a [Dependencies] that holds resolve job
+// IDs by type. The package sets are expected to be pinned to the lockfile
+// already, if there is one.
+func EnqueueResolveJobs(ctx context.Context, workers *worker.Server, manifestSource *manifest.Manifest, pkgSetChains map[string][]rpmmd.PackageSet, lf *lockfile.Lockfile, arch distro.Arch, sbomType sbom.StandardType, channel string) (Dependencies, error) {
+	var dependencies Dependencies
+	distribution := arch.Distro()
+
+	depsolveJobID, err := workers.EnqueueDepsolve(ctx, &worker.DepsolveJob{
+		PackageSets:      pkgSetChains,
+		ModulePlatformID: distribution.ModulePlatformID(),
+		Arch:             arch.Name(),
+		Releasever:       distribution.Releasever(),
+		Distro:           distribution.Name(),
+		SbomType:         sbomType,
+		Lockfile:         lf,
+	}, channel)
+	if err != nil {
+		return dependencies, err
+	}
+	dependencies.DepsolveJobID = depsolveJobID
+
+	containerSources := manifestSource.GetContainerSourceSpecs()
+	if len(containerSources) > 0 {
+		pipelineSpecs := make(map[string][]worker.ContainerSpec, len(containerSources))
+		for name, sources := range containerSources {
+			specs := make([]worker.ContainerSpec, len(sources))
+			for idx, source := range sources {
+				specs[idx] = worker.ContainerSpecFromVendorSourceSpec(source)
+			}
+			pipelineSpecs[name] = specs
+		}
+
+		job := worker.ContainerResolveJob{
+			Arch:          arch.Name(),
+			PipelineSpecs: pipelineSpecs,
+		}
+
+		containerResolveJobID, err := workers.EnqueueContainerResolveJob(ctx, &job, nil, channel)
+		if err != nil {
+			return dependencies, err
+		}
+		dependencies.ContainerResolveJobID = containerResolveJobID
+	}
+
+	commitSources := manifestSource.GetOSTreeSourceSpecs()
+	if len(commitSources) > 1 {
+		// only one pipeline can specify an ostree commit for content
+		pipelines := make([]string, 0, len(commitSources))
+		for name := range commitSources {
+			pipelines = append(pipelines, name)
+		}
+		return dependencies, fmt.Errorf("manifest returned %d pipelines with ostree commits (at most 1 is supported): %s", len(commitSources), strings.Join(pipelines, ", "))
+	}
+	for _, sources := range commitSources {
+		workerResolveSpecs := make([]worker.OSTreeResolveSpec, len(sources))
+		for idx, source := range sources {
+			// ostree.SourceSpec is directly convertible to worker.OSTreeResolveSpec
+			workerResolveSpecs[idx] = worker.OSTreeResolveSpec{
+				URL:  source.URL,
+				Ref:  source.Ref,
+				RHSM: source.RHSM,
+			}
+
+		}
+		ostreeResolveJobID, err := workers.EnqueueOSTreeResolveJob(ctx, &worker.OSTreeResolveJob{Specs: workerResolveSpecs}, channel)
+		if err != nil {
+			return dependencies, err
+		}
+
+		dependencies.OSTreeResolveJobID = ostreeResolveJobID
+		break // there can be only one
+	}
+
+	return dependencies, nil
+}
+
+// Serialize waits for the dependencies of the manifest job to finish, takes
+// over the manifest job and finishes it with the manifest serialized from
+// their results. If a dependency fails or doesn't finish within timeout, the
+// manifest job and the jobs depending on it are failed. If serialized isn't
+// nil, it is called with the manifest before the manifest job is finished.
+func Serialize(ctx context.Context, workers *worker.Server, getManifestSource SourceFunc, dependencies Dependencies, manifestJobID uuid.UUID, timeout time.Duration, serialized SerializedFunc) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	jobResult := &worker.ManifestJobByIDResult{
+		Manifest: nil,
+		ManifestInfo: worker.ManifestInfo{
+			OSBuildComposerVersion: common.BuildVersion(),
+		},
+	}
+
+	var dynArgs []json.RawMessage
+	var err error
+	token := uuid.Nil
+	logWithId := logrus.WithField("jobId", manifestJobID)
+
+	defer func() {
+		if r := recover(); r != nil {
+			logWithId.Errorf("Recovered from panic while serializing manifest: %v", r)
+			jobResult.JobError = clienterrors.New(clienterrors.ErrorManifestGeneration, "Error serializing manifest", fmt.Sprintf("%v", r))
+		}
+
+		// token == uuid.Nil indicates that no worker even started processing
+		if token == uuid.Nil {
+			if jobResult.JobError != nil {
+				// set all jobs to "failed"
+				// osbuild job will fail as dependency
+				allJobIDs := slices.Concat(dependencies.IDs(), []uuid.UUID{manifestJobID})
+				for _, jobID := range allJobIDs {
+					err := workers.SetFailed(jobID, jobResult.JobError)
+					if err != nil {
+						logWithId.Errorf("Error failing job %s: %v", jobID, err)
+					}
+				}
+
+			} else {
+				logWithId.Errorf("Internal error, no worker started processing dependencies but we didn't get a reason.")
+			}
+		} else {
+			result, err := json.Marshal(jobResult)
+			if err != nil {
+				logWithId.Errorf("Error marshalling manifest job results: %v", err)
+			}
+			err = workers.FinishJob(token, result)
+			if err != nil {
+				logWithId.Errorf("Error finishing manifest job: %v", err)
+			}
+			if jobResult.JobError != nil {
+				logWithId.Errorf("Error in manifest job %v: %v", jobResult.JobError.Reason, err)
+			}
+		}
+	}()
+
+	// wait until job is in a pending state
+	for {
+		_, token, _, _, dynArgs, err = workers.RequestJobById(ctx, "", manifestJobID)
+		if errors.Is(err, jobqueue.ErrNotPending) {
+			logWithId.Debug("Manifest job not pending, waiting for dependencies to finish")
+			time.Sleep(time.Millisecond * 50)
+			select {
+			case <-ctx.Done():
+				logWithId.Warning(fmt.Sprintf("Manifest job dependencies took longer than %v to finish,"+
+					" or the server is shutting down, returning to avoid dangling routines", timeout))
+
+				jobResult.JobError = clienterrors.New(clienterrors.ErrorJobDependency,
+					"Timeout while waiting for dependencies to finish",
+					"There may be a temporary issue with compute resources.",
+				)
+				break
+			default:
+				continue
+			}
+		}
+		if err != nil {
+			logWithId.Errorf("Error requesting manifest job: %v", err)
+			return
+		}
+		break
+	}
+
+	// add osbuild/image-builder dependency info to job result
+	osbuildImagesDep, err := common.GetDepModuleInfoByPath(common.OSBuildImagesModulePath)
+	if err != nil {
+		// do not fail here and just log the error, because the module info is not available in tests.
+		// Failing here would make the unit tests fail. See https://github.com/golang/go/issues/33976
+		logWithId.Errorf("Error getting %s dependency info: %v", common.OSBuildImagesModulePath, err)
+	} else {
+		osbuildImagesDepModule := worker.ComposerDepModuleFromDebugModule(osbuildImagesDep)
+		jobResult.ManifestInfo.OSBuildComposerDeps = append(jobResult.ManifestInfo.OSBuildComposerDeps, osbuildImagesDepModule)
+	}
+
+	// If a pre-manifest job ran upstream (bootc flow), compare its ManifestInfo against
+	// the local ManifestInfo to detect version mismatches between composer instances.
+	if dependencies.BootcPreManifestJobID != uuid.Nil {
+		var preManifestResult worker.BootcPreManifestJobResult
+		_, err := workers.BootcPreManifestJobInfo(dependencies.BootcPreManifestJobID, &preManifestResult)
+		if err != nil {
+			reason := "Error reading pre-manifest job result for ManifestInfo build version check"
+			jobResult.JobError = clienterrors.New(clienterrors.ErrorReadingJobStatus, reason, err.Error())
+			return
+		}
+
+		if jobErr := preManifestResult.JobError; jobErr != nil {
+			jobResult.JobError = clienterrors.New(clienterrors.ErrorJobDependency, "Error in bootc pre-manifest job dependency", jobErr.Details)
+			return
+		}
+
+		if mismatchErr := worker.CompareManifestInfos(preManifestResult.ManifestInfo, jobResult.ManifestInfo); mismatchErr != nil {
+			logWithId.Errorf("ManifestInfo build version mismatch between pre-manifest and manifest jobs: %v", mismatchErr)
+			jobResult.JobError = mismatchErr
+			return
+		}
+	}
+
+	// Obtain the manifest source via the factory function.
+	// For the standard flow this simply returns the pre-built manifest.
+	// For bootc this reconstructs it from BootcInfoResolve results.
+	manifestSource, err := getManifestSource()
+	if err != nil {
+		reason := "Error obtaining manifest source"
+		jobResult.JobError = clienterrors.New(clienterrors.ErrorManifestGeneration, reason, err.Error())
+		return
+	}
+
+	if len(dynArgs) == 0 {
+		reason := "No dynamic arguments"
+		jobResult.JobError = clienterrors.New(clienterrors.ErrorNoDynamicArgs, reason, nil)
+		return
+	}
+
+	var depsolveResult map[string]depsolvednf.DepsolveResult
+	if dependencies.DepsolveJobID != uuid.Nil {
+		var depsolveJobResult worker.DepsolveJobResult
+		_, err = workers.DepsolveJobInfo(dependencies.DepsolveJobID, &depsolveJobResult)
+		if err != nil {
+			reason := "Error reading depsolve status"
+			jobResult.JobError = clienterrors.New(clienterrors.ErrorReadingJobStatus, reason, nil)
+			return
+		}
+
+		if jobErr := depsolveJobResult.JobError; jobErr != nil {
+			if jobErr.ID == clienterrors.ErrorDNFDepsolveError || jobErr.ID == clienterrors.ErrorDNFMarkingErrors {
+				jobResult.JobError = clienterrors.New(clienterrors.ErrorDepsolveDependency, "Error in depsolve job dependency input, bad package set requested", jobErr.Details)
+				return
+			}
+			jobResult.JobError = clienterrors.New(clienterrors.ErrorDepsolveDependency, "Error in depsolve job dependency", jobErr.Details)
+			return
+		}
+
+		if len(depsolveJobResult.PackageSpecs) == 0 {
+			jobResult.JobError = clienterrors.New(clienterrors.ErrorEmptyPackageSpecs, "Received empty package specs", nil)
+			return
+		}
+
+		depsolveResult, err = depsolveJobResult.ToDepsolvednfResult()
+		if err != nil {
+			reason := "Error converting depsolve result"
+			jobResult.JobError = clienterrors.New(clienterrors.ErrorManifestGeneration, reason, err.Error())
+			return
+		}
+	}
+
+	var containerSpecs map[string][]container.Spec
+	if dependencies.ContainerResolveJobID != uuid.Nil {
+		// Container resolve job
+		var result worker.ContainerResolveJobResult
+		_, err := workers.ContainerResolveJobInfo(dependencies.ContainerResolveJobID, &result)
+
+		if err != nil {
+			reason := "Error reading container resolve job status"
+			jobResult.JobError = clienterrors.New(clienterrors.ErrorReadingJobStatus, reason, nil)
+			return
+		}
+
+		if jobErr := result.JobError; jobErr != nil {
+			jobResult.JobError = clienterrors.New(clienterrors.ErrorContainerDependency, "Error in container resolve job dependency", nil)
+			return
+		}
+
+		if result.PipelineSpecs != nil {
+			containerSpecs = make(map[string][]container.Spec, len(result.PipelineSpecs))
+			for name, specs := range result.PipelineSpecs {
+				vendorSpecs := make([]container.Spec, len(specs))
+				for i, s := range specs {
+					vendorSpecs[i] = s.ToVendorSpec()
+				}
+				containerSpecs[name] = vendorSpecs
+			}
+		} else if result.Specs != nil {
+			// TODO (2026-03-30, thozza): remove this once all workers are migrated to the new format.
+			// Old worker fallback: flat result, reconstruct pipeline mapping using manifest source specs.
+			containerSpecs, err = matchContainerSpecsToPipelines(result.Specs, manifestSource.GetContainerSourceSpecs())
+			if err != nil {
+				reason := "Error matching container specs to pipelines"
+				jobResult.JobError = clienterrors.New(clienterrors.ErrorContainerDependency, reason, err.Error())
+				return
+			}
+		}
+	}
+
+	var ostreeCommitSpecs map[string][]ostree.CommitSpec
+	if dependencies.OSTreeResolveJobID != uuid.Nil {
+		var result worker.OSTreeResolveJobResult
+		_, err := workers.OSTreeResolveJobInfo(dependencies.OSTreeResolveJobID, &result)
+
+		if err != nil {
+			reason := "Error reading ostree resolve job status"
+			logrus.Errorf("%s: %v", reason, err)
+			jobResult.JobError = clienterrors.New(clienterrors.ErrorReadingJobStatus, reason, nil)
+			return
+		}
+
+		if jobErr := result.JobError; jobErr != nil {
+			jobResult.JobError = clienterrors.New(clienterrors.ErrorOSTreeDependency, "Error in ostree resolve job dependency", nil)
+			return
+		}
+
+		// NOTE: The ostree resolve job doesn't hold the pipeline name for the
+		// ostree commits, so we need to get it from the manifest content
+		// field. There should be only one.
+		var ostreeCommitPipeline string
+		for name := range manifestSource.GetOSTreeSourceSpecs() {
+			ostreeCommitPipeline = name
+			break
+		}
+
+		commitSpecs := make([]ostree.CommitSpec, len(result.Specs))
+		for idx, resultSpec := range result.Specs {
+			commitSpecs[idx] = ostree.CommitSpec{
+				Ref:      resultSpec.Ref,
+				URL:      resultSpec.URL,
+				Checksum: resultSpec.Checksum,
+				Secrets:  resultSpec.Secrets,
+			}
+		}
+		ostreeCommitSpecs = map[string][]ostree.CommitSpec{
+			ostreeCommitPipeline: commitSpecs,
+		}
+	}
+
+	ms, err := manifestSource.Serialize(depsolveResult, containerSpecs, ostreeCommitSpecs, nil, nil)
+	if err != nil {
+		reason := "Error serializing manifest"
+		jobResult.JobError = clienterrors.New(clienterrors.ErrorManifestGeneration, reason, err.Error())
+		return
+	}
+
+	jobResult.Manifest = ms
+	jobResult.ManifestInfo.PipelineNames = &worker.PipelineNames{
+		Build:   manifestSource.BuildPipelines(),
+		Payload: manifestSource.PayloadPipelines(),
+	}
+
+	if serialized != nil {
+		serialized(ms, depsolveResult)
+	}
+}
@@ -0,0 +1,84 @@
+package manifestjob_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/image-builder/pkg/depsolvednf"
+	"github.com/osbuild/image-builder/pkg/manifest"
+	"github.com/osbuild/osbuild-composer/internal/jobqueue/fsjobqueue"
+	"github.com/osbuild/osbuild-composer/internal/manifestjob"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+	"github.com/osbuild/osbuild-composer/internal/worker/clienterrors"
+)
+
+func newTestWorkerServer(t *testing.T) *worker.Server {
+	t.Helper()
+	jobsDir := filepath.Join(t.TempDir(), "jobs")
+	require.NoError(t, os.Mkdir(jobsDir, 0755))
+
+	q, err := fsjobqueue.New(jobsDir)
+	require.NoError(t, err)
+
+	return worker.NewServer(nil, q, worker.Config{
+		BasePath: "/api/worker/v1",
+	})
+}
+
+// serializeAfterDepsolve finishes a depsolve job with result and serializes
+// the manifest of the manifest job depending on it
+func serializeAfterDepsolve(t *testing.T, result worker.DepsolveJobResult, getManifestSource manifestjob.SourceFunc) worker.ManifestJobByIDResult {
+	workers := newTestWorkerServer(t)
+
+	depsolveJobID, err := workers.EnqueueDepsolve(context.Background(), &worker.DepsolveJob{}, "")
+	require.NoError(t, err)
+	manifestJobID, err := workers.EnqueueManifestJobByID(context.Background(), &worker.ManifestJobByID{}, []uuid.UUID{depsolveJobID}, "")
+	require.NoError(t, err)
+
+	_, token, _, _, _, err := workers.RequestJob(context.Background(), "", []string{worker.JobTypeDepsolve}, []string{""}, uuid.Nil)
+	require.NoError(t, err)
+	rawResult, err := json.Marshal(result)
+	require.NoError(t, err)
+	require.NoError(t, workers.FinishJob(token, rawResult))
+
+	dependencies := manifestjob.Dependencies{DepsolveJobID: depsolveJobID}
+	manifestjob.Serialize(context.Background(), workers, getManifestSource, dependencies, manifestJobID, 10*time.Second, func(manifest.OSBuildManifest, map[string]depsolvednf.DepsolveResult) {
+		t.Fatal("manifest serialized although the manifest job failed")
+	})
+
+	var manifestResult worker.ManifestJobByIDResult
+	info, err := workers.ManifestJobInfo(manifestJobID, &manifestResult)
+	require.NoError(t, err)
+	require.False(t, info.JobStatus.Finished.IsZero())
+	return manifestResult
+}
+
+func TestSerializeEmptyPackageSpecs(t *testing.T) {
+	getManifestSource := func() (*manifest.Manifest, error) {
+		m := manifest.New()
+		return &m, nil
+	}
+
+	result := serializeAfterDepsolve(t, worker.DepsolveJobResult{}, getManifestSource)
+	require.NotNil(t, result.JobError)
+	require.Equal(t, clienterrors.ErrorEmptyPackageSpecs, result.JobError.ID)
+	require.Nil(t, result.Manifest)
+}
+
+func TestSerializeRecoversPanic(t *testing.T) {
+	getManifestSource := func() (*manifest.Manifest, error) {
+		panic("broken image type")
+	}
+
+	result := serializeAfterDepsolve(t, worker.DepsolveJobResult{}, getManifestSource)
+	require.NotNil(t, result.JobError)
+	require.Equal(t, clienterrors.ErrorManifestGeneration, result.JobError.ID)
+	require.Equal(t, "broken image type", result.JobError.Details)
+}
@@ -1,8 +1,33 @@
 package osbuildexecutor
 
+import (
+	"context"
+	"time"
+)
+
 var ExtractOutputArchive = extractOutputArchive
 var FetchOutputArchive = fetchOutputArchive
 var HandleBuild = handleBuild
+var ReleaseBuild = releaseBuild
 var ValidateOutputArchive = validateOutputArchive
 var WaitForSI = waitForSI
 var WriteInputArchive = writeInputArchive
+var KVMAvailable = kvmAvailable
+var QEMUArgs = qemuArgs
+
+func NewTestQEMUKVMExecutor(config QEMUKVMConfig, tmpDir, qemuImg, qemuSystem, kvmDevice string) Executor {
+	executor := NewQEMUKVMExecutor(config, tmpDir).(*qemuKVMExecutor)
+	executor.qemuImg = qemuImg
+	executor.qemuSystem = qemuSystem
+	executor.kvmDevice = kvmDevice
+	return executor
+}
+
+func (p *RemotePool) Acquire(ctx context.Context, exclude map[string]bool) (string, error) {
+	p.retryInterval = time.Millisecond * 10
+	return p.acquire(ctx, exclude)
+}
+
+func (p *RemotePool) Release(endpoint string) {
+	p.release(endpoint)
+}
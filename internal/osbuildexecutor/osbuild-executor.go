@@ -14,5 +14,8 @@ const (
 )
 
 type Executor interface {
-	RunOSBuild(manifest []byte, logger logrus.FieldLogger, job worker.Job, opts *osbuild.OSBuildOptions) (*osbuild.Result, error)
+	// RunOSBuild builds manifest and reports the progress to job. The
+	// durations of the steps of the build are added to timings if it isn't
+	// nil.
+	RunOSBuild(manifest []byte, logger logrus.FieldLogger, job worker.Job, opts *osbuild.OSBuildOptions, timings *worker.OSBuildTimings) (*osbuild.Result, error)
 }
@@ -1,20 +1,50 @@
 package osbuildexecutor
 
 import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/slices"
 
 	"github.com/osbuild/image-builder/pkg/osbuild"
+	"github.com/osbuild/osbuild-composer/internal/tracing"
 	"github.com/osbuild/osbuild-composer/internal/worker"
 )
 
-func handleProgress(osbuildStatus *osbuild.StatusScanner, logger logrus.FieldLogger, job worker.Job) error {
+const OSBuildResultFilename = "osbuild-result.json"
+
+// BuildIDHeader passes the ID of a build, executors send it in the response
+// to a new build and need it to hand out the result and release the build
+const BuildIDHeader = "X-Build-Id"
+
+// handleProgress logs the progress of osbuild and reports it to the job. The
+// durations of the pipelines and stages are added to timings if it isn't nil.
+func handleProgress(osbuildStatus *osbuild.StatusScanner, logger logrus.FieldLogger, job worker.Job, timings *worker.OSBuildTimings) error {
 	if osbuildStatus == nil {
 		return fmt.Errorf("status scanner is required to handle osbuild progress")
 	}
 
+	stages := &stageTracer{timings: timings}
+	if job != nil {
+		stages.ctx = job.Context()
+	}
+	defer func() {
+		stages.end(time.Now())
+	}()
+
 	var lastUpdated time.Time
 	for {
 		st, err := osbuildStatus.Status()
@@ -24,6 +54,7 @@ func handleProgress(osbuildStatus *osbuild.StatusScanner, logger logrus.FieldLog
 		if st == nil {
 			break
 		}
+		stages.update(st)
 
 		progress := logrus.Fields{}
 		if st.Progress != nil {
@@ -69,3 +100,446 @@ func handleProgress(osbuildStatus *osbuild.StatusScanner, logger logrus.FieldLog
 	}
 	return nil
 }
+
+// stageTracer follows the pipelines and stages osbuild reports as running.
+// It records a span for each of them as part of the trace of the job, if
+// there is a job, and their durations.
+type stageTracer struct {
+	ctx     context.Context
+	timings *worker.OSBuildTimings
+
+	pipeline      string
+	pipelineStart time.Time
+	pipelineCtx   context.Context
+	pipelineSpan  trace.Span
+
+	stage      string
+	stageStart time.Time
+	stageSpan  trace.Span
+}
+
+func (t *stageTracer) update(st *osbuild.Status) {
+	if st.Pipeline == "" {
+		return
+	}
+	ts := st.Timestamp
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	var stage string
+	if st.Progress != nil && st.Progress.SubProgress != nil {
+		// the message is "Stage <type>"
+		stage = strings.TrimPrefix(st.Progress.SubProgress.Message, "Stage ")
+	}
+
+	if st.Pipeline != t.pipeline {
+		t.end(ts)
+		t.pipeline = st.Pipeline
+		t.pipelineStart = ts
+		if t.ctx != nil {
+			t.pipelineCtx, t.pipelineSpan = tracing.StartAt(t.ctx, "pipeline "+st.Pipeline, ts,
+				attribute.String("osbuild.pipeline", st.Pipeline))
+		}
+	}
+	if stage != t.stage {
+		t.endStage(ts)
+		t.stage = stage
+		t.stageStart = ts
+		if stage != "" && t.ctx != nil {
+			_, t.stageSpan = tracing.StartAt(t.pipelineCtx, "stage "+stage, ts,
+				attribute.String("osbuild.pipeline", st.Pipeline),
+				attribute.String("osbuild.stage", stage))
+		}
+	}
+}
+
+func (t *stageTracer) record(stage string, start, end time.Time) {
+	if t.timings == nil {
+		return
+	}
+	t.timings.Steps = append(t.timings.Steps, worker.OSBuildStepTiming{
+		Pipeline: t.pipeline,
+		Stage:    stage,
+		Seconds:  end.Sub(start).Seconds(),
+	})
+}
+
+func (t *stageTracer) endStage(ts time.Time) {
+	if t.stage != "" {
+		t.record(t.stage, t.stageStart, ts)
+	}
+	if t.stageSpan != nil {
+		tracing.EndAt(t.stageSpan, nil, ts)
+		t.stageSpan = nil
+	}
+	t.stage = ""
+}
+
+// end ends the running stage and pipeline
+func (t *stageTracer) end(ts time.Time) {
+	t.endStage(ts)
+	if t.pipeline != "" {
+		t.record("", t.pipelineStart, ts)
+	}
+	if t.pipelineSpan != nil {
+		tracing.EndAt(t.pipelineSpan, nil, ts)
+		t.pipelineSpan = nil
+	}
+	t.pipeline = ""
+}
+
+func prepareSources(manifest []byte, logger logrus.FieldLogger, opts *osbuild.OSBuildOptions) (*osbuild.Result, error) {
+	hostExecutor := NewHostExecutor()
+	return hostExecutor.RunOSBuild(manifest, logger, nil, &osbuild.OSBuildOptions{
+		StoreDir:   opts.StoreDir,
+		ExtraEnv:   opts.ExtraEnv,
+		Stderr:     opts.Stderr,
+		JSONOutput: true,
+	}, nil)
+}
+
+// ErrExecutorBusy is returned when an executor is already running a build
+var ErrExecutorBusy = errors.New("executor is already building")
+
+// checkExecutor checks whether the osbuild-worker-executor at host is up
+func checkExecutor(client *http.Client, host string) error {
+	resp, err := client.Get(fmt.Sprintf("%s/api/v1/", host))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("unable to read body: %w, http status: %d", err, resp.StatusCode)
+		}
+		return fmt.Errorf("http status: %d, body: %s", resp.StatusCode, body)
+	}
+	return nil
+}
+
+// TODO extract this, also used in the osbuild-worker-executor unit
+// tests.
+func waitForSI(ctx context.Context, host string, transport http.RoundTripper) bool {
+	client := http.Client{
+		Timeout:   time.Second * 1,
+		Transport: transport,
+	}
+
+	for {
+		err := checkExecutor(&client, host)
+		if err == nil {
+			return true
+		}
+		logrus.Debugf("Waiting for secure instance continues: %v", err)
+		select {
+		case <-ctx.Done():
+			logrus.Error("Timeout waiting for secure instance to spin up")
+			return false
+		default:
+			time.Sleep(time.Second)
+			continue
+		}
+	}
+}
+
+func writeInputArchive(cacheDir, store string, exports []string, manifestData []byte) (string, error) {
+	archive := filepath.Join(cacheDir, "input.tar")
+	control := filepath.Join(cacheDir, "control.json")
+	manifest := filepath.Join(cacheDir, "manifest.json")
+
+	controlData := struct {
+		Exports []string `json:"exports"`
+	}{
+		Exports: exports,
+	}
+	controlDataBytes, err := json.Marshal(controlData)
+	if err != nil {
+		return "", err
+	}
+	err = os.WriteFile(control, controlDataBytes, 0600)
+	if err != nil {
+		return "", err
+	}
+	err = os.WriteFile(manifest, manifestData, 0600)
+	if err != nil {
+		return "", err
+	}
+
+	cmd := exec.Command("tar",
+		"-C",
+		cacheDir,
+		"-cf",
+		archive,
+		filepath.Base(control),
+		filepath.Base(manifest),
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("Unable to create input tar: %w, %s", err, output)
+	}
+	// Separate tar call, as we need to switch to the store directory.
+	/* #nosec G204 */
+	cmd = exec.Command("tar",
+		"-C",
+		filepath.Dir(store),
+		"-rf",
+		archive,
+		filepath.Base(store),
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("Unable to create input tar: %w, %s", err, output)
+	}
+
+	return archive, nil
+}
+
+// handleBuild runs a build on the executor at host and returns its ID, which
+// is also returned if the build failed after the executor started it
+func handleBuild(inputArchive, host string, transport http.RoundTripper, logger logrus.FieldLogger, job worker.Job, timings *worker.OSBuildTimings) (string, error) {
+	client := http.Client{
+		Timeout:   time.Minute * 60,
+		Transport: transport,
+	}
+	inputFile, err := os.Open(inputArchive)
+	if err != nil {
+		return "", fmt.Errorf("unable to open inputArchive (%s): %w", inputArchive, err)
+	}
+	defer inputFile.Close()
+
+	resp, err := client.Post(fmt.Sprintf("%s/api/v1/build", host), "application/x-tar", inputFile)
+	if err != nil {
+		return "", fmt.Errorf("unable to request build from executor instance: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusConflict {
+		return "", fmt.Errorf("%w: %s", ErrExecutorBusy, host)
+	}
+	buildID := resp.Header.Get(BuildIDHeader)
+	if resp.StatusCode != 201 {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return buildID, fmt.Errorf("unable to read body waiting for build to run: %w,  http status: %d", err, resp.StatusCode)
+		}
+		return buildID, fmt.Errorf("something went wrong during executor build: http status: %v, %d, %s", err, resp.StatusCode, body)
+	}
+
+	osbuildStatus := osbuild.NewStatusScanner(resp.Body)
+	return buildID, handleProgress(osbuildStatus, logger, job, timings)
+}
+
+// newBuildRequest returns a request for the build with the given ID
+func newBuildRequest(method, url, buildID string) (*http.Request, error) {
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if buildID != "" {
+		req.Header.Set(BuildIDHeader, buildID)
+	}
+	return req, nil
+}
+
+func fetchLog(host string, transport http.RoundTripper, buildID string) (string, error) {
+	client := http.Client{
+		Timeout:   time.Minute,
+		Transport: transport,
+	}
+	req, err := newBuildRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/log", host), buildID)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return "", fmt.Errorf("cannot fetch output archive: %w, http status: %d", err, resp.StatusCode)
+		}
+		return "", fmt.Errorf("cannot fetch output archive: %w, http status: %d, body: %s", err, resp.StatusCode, body)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("cannot read log response: %w, http status: %d", err, resp.StatusCode)
+	}
+	return string(body), nil
+}
+
+// releaseBuild cancels the build of the executor at host if it is still
+// running and releases it, so executors accepting several builds can run the
+// next build
+func releaseBuild(host string, transport http.RoundTripper, buildID string) error {
+	client := http.Client{
+		Timeout:   time.Minute,
+		Transport: transport,
+	}
+	req, err := newBuildRequest(http.MethodDelete, fmt.Sprintf("%s/api/v1/build", host), buildID)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("cannot release build: %w, http status: %d", err, resp.StatusCode)
+		}
+		return fmt.Errorf("cannot release build: http status: %d, body: %s", resp.StatusCode, body)
+	}
+	return nil
+}
+
+func fetchOutputArchive(cacheDir, host string, transport http.RoundTripper, buildID string) (string, error) {
+	client := http.Client{
+		Timeout:   time.Minute * 30,
+		Transport: transport,
+	}
+
+	req, err := newBuildRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/result/output.tar", host), buildID)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return "", fmt.Errorf("cannot fetch output archive: %w, http status: %d", err, resp.StatusCode)
+		}
+		return "", fmt.Errorf("cannot fetch output archive: %w, http status: %d, body: %s", err, resp.StatusCode, body)
+	}
+	file, err := os.Create(filepath.Join(cacheDir, "output.tar"))
+	if err != nil {
+		return "", fmt.Errorf("Unable to write executor result tarball: %w", err)
+	}
+	defer file.Close()
+	_, err = io.Copy(file, resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("Unable to write executor result tarball: %w", err)
+	}
+	return file.Name(), nil
+}
+
+func validateOutputArchive(outputTarPath string) error {
+	f, err := os.Open(outputTarPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		// check for directory traversal attacks
+		if filepath.Clean(hdr.Name) != strings.TrimSuffix(hdr.Name, "/") {
+			return fmt.Errorf("name %q not clean, got %q after cleaning", hdr.Name, filepath.Clean(hdr.Name))
+		}
+		if strings.HasPrefix(filepath.Clean(hdr.Name), "/") {
+			return fmt.Errorf("name %q must not start with an absolute path", hdr.Name)
+		}
+		// protect against someone smuggling in eg. device files
+		// XXX: should we support symlinks here?
+		if !slices.Contains([]byte{tar.TypeReg, tar.TypeDir, tar.TypeGNUSparse}, hdr.Typeflag) {
+			return fmt.Errorf("name %q must be a file/dir, is header type %q", hdr.Name, hdr.Typeflag)
+		}
+		// protect against executables, this implicitly protects
+		// against suid/sgid (XXX: or should we also check that?)
+		if hdr.Typeflag == tar.TypeReg && hdr.Mode&0111 != 0 {
+			return fmt.Errorf("name %q must not be executable (is mode 0%o)", hdr.Name, hdr.Mode)
+		}
+	}
+
+	return nil
+}
+
+func extractOutputArchive(outputDirectory, outputTar string) error {
+	// validate against directory traversal attacks
+	if err := validateOutputArchive(outputTar); err != nil {
+		return fmt.Errorf("unable to validate output tar: %w", err)
+	}
+
+	cmd := exec.Command("tar",
+		"--strip-components=1",
+		"-C",
+		outputDirectory,
+		"-Sxf",
+		outputTar,
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("Unable to create input tar: %w, %s", err, output)
+	}
+	return nil
+
+}
+
+// runOnExecutor builds the manifest on a running osbuild-worker-executor and
+// extracts its output into the output directory
+func runOnExecutor(executorHost string, transport http.RoundTripper, tmpDir string, manifest []byte, logger logrus.FieldLogger, job worker.Job, opts *osbuild.OSBuildOptions, timings *worker.OSBuildTimings) (*osbuild.Result, error) {
+	inputArchive, err := writeInputArchive(tmpDir, opts.StoreDir, opts.Exports, manifest)
+	if err != nil {
+		logrus.Errorf("Unable to write input archive: %v", err)
+		return nil, err
+	}
+
+	buildID, err := handleBuild(inputArchive, executorHost, transport, logger, job, timings)
+	if buildID != "" {
+		// the executor keeps the build until it is released, once the
+		// results were fetched or the build failed
+		defer func() {
+			if err := releaseBuild(executorHost, transport, buildID); err != nil {
+				logger.Warnf("Unable to release build %s on executor %s: %v", buildID, executorHost, err)
+			}
+		}()
+	}
+	if err != nil {
+		if errors.Is(err, ErrExecutorBusy) {
+			return nil, err
+		}
+		log, logErr := fetchLog(executorHost, transport, buildID)
+		if logErr != nil {
+			logrus.Errorf("something went wrong during the executor's build: %v, unable to fetch log: %v", err, logErr)
+			return nil, fmt.Errorf("something went wrong during the executor's build: %w, unable to fetch log: %w", err, logErr)
+		}
+		logrus.WithField("osbuild_output", string(log)).Errorf("something went wrong handling the executor's build: %v\nosbuild log: %v", err, log)
+		return nil, fmt.Errorf("osbuild failed: %s", log)
+	}
+
+	outputArchive, err := fetchOutputArchive(tmpDir, executorHost, transport, buildID)
+	if err != nil {
+		logrus.Errorf("Unable to fetch executor output: %v", err)
+		return nil, err
+	}
+
+	err = extractOutputArchive(opts.OutputDir, outputArchive)
+	if err != nil {
+		logrus.Errorf("Unable to extract executor output: %v", err)
+		return nil, err
+	}
+
+	resultData, err := os.ReadFile(filepath.Join(opts.OutputDir, OSBuildResultFilename))
+	if err != nil {
+		logrus.Errorf("Unable to find and read osbuild result: %v", err)
+		return nil, err
+	}
+	var result osbuild.Result
+	if err := json.Unmarshal(resultData, &result); err != nil {
+		logrus.Errorf("Unable to unmarshal json result: %v\nraw output:\n%s", err, resultData)
+		return nil, fmt.Errorf("error decoding osbuild output: %w\nraw output:\n%s", err, resultData)
+	}
+	return &result, nil
+}
@@ -3,6 +3,7 @@ package osbuildexecutor_test
 import (
 	"archive/tar"
 	"context"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -10,6 +11,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -18,8 +20,12 @@ import (
 	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 
+	"github.com/osbuild/osbuild-composer/internal/cloudcredentials"
 	"github.com/osbuild/osbuild-composer/internal/osbuildexecutor"
+	"github.com/osbuild/osbuild-composer/internal/secrets"
 	"github.com/osbuild/osbuild-composer/internal/worker"
 )
 
@@ -35,12 +41,12 @@ func TestWaitForSI(t *testing.T) {
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*1)
 	defer cancel()
-	require.False(t, osbuildexecutor.WaitForSI(ctx, server.URL))
+	require.False(t, osbuildexecutor.WaitForSI(ctx, server.URL, nil))
 
 	server.Start()
 	ctx2, cancel2 := context.WithTimeout(context.Background(), time.Second*1)
 	defer cancel2()
-	require.True(t, osbuildexecutor.WaitForSI(ctx2, server.URL))
+	require.True(t, osbuildexecutor.WaitForSI(ctx2, server.URL, nil))
 }
 
 func TestWriteInputArchive(t *testing.T) {
@@ -85,6 +91,14 @@ func (j *testJob) Type() string {
 	return "test-job"
 }
 
+func (j *testJob) Context() context.Context {
+	return context.Background()
+}
+
+func (j *testJob) WithContext(context.Context) worker.Job {
+	return j
+}
+
 func (j *testJob) Args(args interface{}) error {
 	return nil
 }
@@ -114,12 +128,43 @@ func (j *testJob) UploadArtifact(name string, readSeeker io.ReadSeeker) error {
 	return nil
 }
 
+func (j *testJob) CloudCredentials(id uuid.UUID) (*cloudcredentials.Credentials, error) {
+	return nil, cloudcredentials.ErrNotFound
+}
+
+func (j *testJob) Secret(ref string) (string, error) {
+	return "", secrets.ErrNotFound
+}
+
+// spanRecorder keeps the ended spans in memory
+type spanRecorder struct {
+	mu    sync.Mutex
+	spans []sdktrace.ReadOnlySpan
+}
+
+func (r *spanRecorder) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+func (r *spanRecorder) Shutdown(context.Context) error                  { return nil }
+func (r *spanRecorder) ForceFlush(context.Context) error                { return nil }
+
+func (r *spanRecorder) OnEnd(span sdktrace.ReadOnlySpan) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans = append(r.spans, span)
+}
+
+func (r *spanRecorder) ended() []sdktrace.ReadOnlySpan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]sdktrace.ReadOnlySpan{}, r.spans...)
+}
+
 func TestHandleBuild(t *testing.T) {
 	buildServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		input, err := io.ReadAll(r.Body)
 		require.NoError(t, err)
 		require.Equal(t, []byte("test"), input)
 
+		w.Header().Set(osbuildexecutor.BuildIDHeader, "build-id")
 		w.WriteHeader(http.StatusCreated)
 		_, err = w.Write([]byte(`{"message": "starting pipeline", "context": {"origin": "osbuild.monitor", "pipeline": {"name": "source", "id": "pipeline-id", "stage": {}}, "id": "context-id"}, "progress": {"name": "pipelines/sources", "total": 1, "done": 0}}
 {"message": "stage in pipeline", "context": {"id": "context-id"}, "progress": {"name": "source", "total": 1, "done": 0, "progress": {"name": "source/stage", "total": 2, "done": 0}}}
@@ -133,8 +178,10 @@ func TestHandleBuild(t *testing.T) {
 
 	entry, hook := makeMockEntry()
 	job := testJob{}
-	err := osbuildexecutor.HandleBuild(inputArchive, buildServer.URL, entry, &job)
+	var timings worker.OSBuildTimings
+	buildID, err := osbuildexecutor.HandleBuild(inputArchive, buildServer.URL, nil, entry, &job, &timings)
 	require.NoError(t, err)
+	require.Equal(t, "build-id", buildID)
 	require.Len(t, hook.Entries, 3)
 	require.Equal(t, "OSBuild status: starting pipeline", hook.Entries[0].Message)
 	require.Equal(t, logrus.Fields{
@@ -166,6 +213,66 @@ func TestHandleBuild(t *testing.T) {
 		},
 	}, partial)
 	require.Len(t, job.PartialUpdates, 1)
+
+	// the stage has no name, only the pipeline is timed
+	require.Len(t, timings.Steps, 1)
+	require.Equal(t, "source", timings.Steps[0].Pipeline)
+	require.Equal(t, "", timings.Steps[0].Stage)
+}
+
+func TestHandleBuildSpans(t *testing.T) {
+	recorder := &spanRecorder{}
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previous)
+
+	var timestamps []time.Time
+	status := func(w io.Writer, line string) {
+		ts := time.Now()
+		timestamps = append(timestamps, ts)
+		_, err := fmt.Fprintf(w, line+"\n", float64(ts.UnixMicro())/1e6)
+		require.NoError(t, err)
+		w.(http.Flusher).Flush()
+		time.Sleep(10 * time.Millisecond)
+	}
+	buildServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		// the build takes a while before osbuild reports anything
+		w.WriteHeader(http.StatusCreated)
+		w.(http.Flusher).Flush()
+		time.Sleep(50 * time.Millisecond)
+
+		status(w, `{"message": "rpm", "context": {"origin": "osbuild.monitor", "pipeline": {"name": "build", "id": "pipeline-id", "stage": {"name": "org.osbuild.rpm", "id": "rpm-id"}}, "id": "rpm-context"}, "progress": {"name": "pipelines", "total": 1, "done": 0, "progress": {"name": "build", "total": 2, "done": 0}}, "timestamp": %f}`)
+		status(w, `{"message": "selinux", "context": {"origin": "osbuild.monitor", "pipeline": {"name": "build", "id": "pipeline-id", "stage": {"name": "org.osbuild.selinux", "id": "selinux-id"}}, "id": "selinux-context"}, "progress": {"name": "pipelines", "total": 1, "done": 0, "progress": {"name": "build", "total": 2, "done": 1}}, "timestamp": %f}`)
+		status(w, `{"message": "finishing pipeline", "result": {"name": "build", "id": "pipeline-id", "success": true}, "context": {"id": "selinux-context"}, "progress": {"name": "pipelines", "total": 1, "done": 1}, "timestamp": %f}`)
+	}))
+
+	cacheDir := t.TempDir()
+	inputArchive := filepath.Join(cacheDir, "test.tar")
+	require.NoError(t, os.WriteFile(inputArchive, []byte("test"), 0600))
+
+	entry, _ := makeMockEntry()
+	_, err := osbuildexecutor.HandleBuild(inputArchive, buildServer.URL, nil, entry, &testJob{}, nil)
+	require.NoError(t, err)
+
+	spans := recorder.ended()
+	require.Len(t, spans, 3)
+	rpm, selinux, pipeline := spans[0], spans[1], spans[2]
+	require.Equal(t, "stage org.osbuild.rpm", rpm.Name())
+	require.Equal(t, "stage org.osbuild.selinux", selinux.Name())
+	require.Equal(t, "pipeline build", pipeline.Name())
+
+	// osbuild reports timestamps in milliseconds
+	require.WithinDuration(t, timestamps[0], rpm.StartTime(), 2*time.Millisecond)
+	require.WithinDuration(t, timestamps[1], rpm.EndTime(), 2*time.Millisecond)
+	require.WithinDuration(t, timestamps[1], selinux.StartTime(), 2*time.Millisecond)
+	require.WithinDuration(t, timestamps[2], selinux.EndTime(), 2*time.Millisecond)
+	require.WithinDuration(t, timestamps[0], pipeline.StartTime(), 2*time.Millisecond)
+	// the pipeline ends when the build does
+	require.False(t, pipeline.EndTime().Before(selinux.EndTime()))
 }
 
 func TestHandleBuildTraceDebug(t *testing.T) {
@@ -186,7 +293,7 @@ func TestHandleBuildTraceDebug(t *testing.T) {
 	require.NoError(t, os.WriteFile(inputArchive, []byte("test"), 0600))
 
 	entry, hook := makeMockEntry()
-	err := osbuildexecutor.HandleBuild(inputArchive, buildServer.URL, entry, nil)
+	_, err := osbuildexecutor.HandleBuild(inputArchive, buildServer.URL, nil, entry, nil, nil)
 	require.NoError(t, err)
 	require.Len(t, hook.Entries, 2)
 	require.Equal(t, "OSBuild status: starting pipeline", hook.Entries[0].Message)
@@ -216,7 +323,7 @@ func TestHandleBuildNoJSON(t *testing.T) {
 	require.NoError(t, os.WriteFile(inputArchive, []byte("test"), 0600))
 
 	entry, _ := makeMockEntry()
-	err := osbuildexecutor.HandleBuild(inputArchive, buildServer.URL, entry, nil)
+	_, err := osbuildexecutor.HandleBuild(inputArchive, buildServer.URL, nil, entry, nil, nil)
 	require.ErrorContains(t, err, `error parsing osbuild status, please report a bug: cannot scan line "bad non-json text": invalid character 'b' looking for beginning of value`)
 }
 
@@ -239,6 +346,7 @@ func TestHandleOutputArchive(t *testing.T) {
 	require.NoError(t, cmd.Run())
 
 	resultServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "build-id", r.Header.Get(osbuildexecutor.BuildIDHeader))
 		w.WriteHeader(http.StatusOK)
 		file, err := os.Open(serverOutput)
 		if err != nil {
@@ -250,7 +358,7 @@ func TestHandleOutputArchive(t *testing.T) {
 	}))
 
 	outputDir := t.TempDir()
-	archive, err := osbuildexecutor.FetchOutputArchive(outputDir, resultServer.URL)
+	archive, err := osbuildexecutor.FetchOutputArchive(outputDir, resultServer.URL, nil, "build-id")
 	require.NoError(t, err)
 
 	extractDir := filepath.Join(outputDir, "extracted")
@@ -349,3 +457,22 @@ func TestValidateOutputArchiveSadExecutable(t *testing.T) {
 	err := osbuildexecutor.ValidateOutputArchive(testTarPath)
 	assert.EqualError(t, err, `name "exe" must not be executable (is mode 0755)`)
 }
+
+func TestReleaseBuild(t *testing.T) {
+	var method string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		assert.Equal(t, "/api/v1/build", r.URL.Path)
+		assert.Equal(t, "build-id", r.Header.Get(osbuildexecutor.BuildIDHeader))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	require.NoError(t, osbuildexecutor.ReleaseBuild(server.URL, nil, "build-id"))
+	assert.Equal(t, http.MethodDelete, method)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unknown build", http.StatusNotFound)
+	}))
+	defer failing.Close()
+	require.EqualError(t, osbuildexecutor.ReleaseBuild(failing.URL, nil, "build-id"), "cannot release build: http status: 404, body: unknown build\n")
+}
@@ -15,7 +15,7 @@ import (
 
 type hostExecutor struct{}
 
-func (he *hostExecutor) RunOSBuild(manifest []byte, logger logrus.FieldLogger, job worker.Job, opts *osbuild.OSBuildOptions) (*osbuild.Result, error) {
+func (he *hostExecutor) RunOSBuild(manifest []byte, logger logrus.FieldLogger, job worker.Job, opts *osbuild.OSBuildOptions, timings *worker.OSBuildTimings) (*osbuild.Result, error) {
 	// MonitorFile needs an *os.File
 	rPipe, wPipe, err := os.Pipe()
 	if err != nil {
@@ -37,7 +37,7 @@ func (he *hostExecutor) RunOSBuild(manifest []byte, logger logrus.FieldLogger, j
 	}
 	wPipe.Close()
 
-	if err := handleProgress(osbuildStatus, logger, job); err != nil {
+	if err := handleProgress(osbuildStatus, logger, job, timings); err != nil {
 		return nil, fmt.Errorf("unable to construct osbuild result: %w", err)
 	}
 
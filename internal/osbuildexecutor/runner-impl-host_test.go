@@ -133,7 +133,7 @@ exit 2
 			hostExe := osbuildexecutor.NewHostExecutor()
 			result, err := hostExe.RunOSBuild(nil, logger, nil, &osbuild.OSBuildOptions{
 				JSONOutput: tt.json,
-			})
+			}, nil)
 			if tt.error != "" {
 				assert.Error(t, err)
 				assert.Equal(t, tt.error, err.Error())
@@ -0,0 +1,224 @@
+package osbuildexecutor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/osbuild/image-builder/pkg/arch"
+	"github.com/osbuild/image-builder/pkg/osbuild"
+
+	"github.com/osbuild/osbuild-composer/internal/worker"
+)
+
+const (
+	QEMUDefaultMemory = 4096
+	QEMUDefaultCPUs   = 2
+
+	// port osbuild-worker-executor listens on in the guest
+	qemuGuestExecutorPort = 8001
+)
+
+// QEMUKVMConfig configures the local virtual machines the qemu.kvm executor
+// runs osbuild in.
+type QEMUKVMConfig struct {
+	// Image is a qcow2 disk image which boots into osbuild-worker-executor
+	// listening on port 8001 of all interfaces. The image is never written
+	// to, each build gets a throwaway overlay of it.
+	Image string
+	// Memory of the guest in MiB
+	Memory int
+	CPUs   int
+}
+
+type qemuKVMExecutor struct {
+	config QEMUKVMConfig
+	tmpDir string
+
+	// overridden in tests
+	qemuImg    string
+	qemuSystem string
+	kvmDevice  string
+}
+
+// kvmAvailable returns whether the worker can use hardware virtualization
+func kvmAvailable(device string) bool {
+	f, err := os.OpenFile(device, os.O_RDWR, 0)
+	if err != nil {
+		return false
+	}
+	f.Close()
+	return true
+}
+
+// freeLocalPort returns a currently unused TCP port on the loopback interface
+// to forward the executor port of the guest to
+func freeLocalPort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+func qemuSystemBinary(a arch.Arch) string {
+	if a == arch.ARCH_PPC64LE {
+		return "qemu-system-ppc64"
+	}
+	return "qemu-system-" + a.String()
+}
+
+// qemuArgs returns the qemu arguments to boot the disk with the executor port
+// of the guest forwarded to hostPort
+func qemuArgs(config QEMUKVMConfig, disk, serialLog string, hostPort int, kvm bool) []string {
+	args := []string{
+		"-m", strconv.Itoa(config.Memory),
+		"-smp", strconv.Itoa(config.CPUs),
+		"-display", "none",
+		"-serial", "file:" + serialLog,
+		"-drive", fmt.Sprintf("file=%s,if=virtio,format=qcow2", disk),
+		"-netdev", fmt.Sprintf("user,id=net0,hostfwd=tcp:127.0.0.1:%d-:%d", hostPort, qemuGuestExecutorPort),
+		"-device", "virtio-net-pci,netdev=net0",
+	}
+
+	switch arch.Current() {
+	case arch.ARCH_X86_64:
+		args = append(args, "-machine", "q35")
+	case arch.ARCH_AARCH64:
+		args = append(args, "-machine", "virt", "-bios", "/usr/share/AAVMF/AAVMF_CODE.fd")
+	}
+
+	if kvm {
+		args = append(args, "-accel", "kvm", "-cpu", "host")
+	} else {
+		args = append(args, "-accel", "tcg", "-cpu", "max")
+	}
+
+	return args
+}
+
+// bootVM boots a throwaway guest from an overlay of the configured image. It
+// returns the URL of the executor in the guest and a function stopping the
+// guest.
+func (qe *qemuKVMExecutor) bootVM(logger logrus.FieldLogger) (string, <-chan struct{}, func(), error) {
+	disk := filepath.Join(qe.tmpDir, "overlay.qcow2")
+	image, err := filepath.Abs(qe.config.Image)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	/* #nosec G204 */
+	cmd := exec.Command(qe.qemuImg, "create", "-q", "-f", "qcow2", "-F", "qcow2", "-b", image, disk)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", nil, nil, fmt.Errorf("Unable to create overlay of %s: %w, %s", image, err, output)
+	}
+
+	port, err := freeLocalPort()
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("Unable to find a free port for the executor: %w", err)
+	}
+
+	kvm := kvmAvailable(qe.kvmDevice)
+	if !kvm {
+		logger.Warnf("%s is not available, falling back to emulating the executor VM", qe.kvmDevice)
+	}
+
+	/* #nosec G204 */
+	cmd = exec.Command(qe.qemuSystem, qemuArgs(qe.config, disk, filepath.Join(qe.tmpDir, "serial.log"), port, kvm)...)
+	if err := cmd.Start(); err != nil {
+		return "", nil, nil, fmt.Errorf("Unable to start executor VM: %w", err)
+	}
+
+	exited := make(chan struct{})
+	go func() {
+		err := cmd.Wait()
+		if err != nil {
+			logrus.Debugf("Executor VM exited: %v", err)
+		}
+		close(exited)
+	}()
+
+	stop := func() {
+		if err := cmd.Process.Kill(); err != nil {
+			logrus.Debugf("Error killing executor VM: %v", err)
+		}
+		<-exited
+	}
+
+	return fmt.Sprintf("http://127.0.0.1:%d", port), exited, stop, nil
+}
+
+// serialLog returns the console output of the guest to help debugging
+// guests which never come online
+func (qe *qemuKVMExecutor) serialLog() string {
+	data, err := os.ReadFile(filepath.Join(qe.tmpDir, "serial.log"))
+	if err != nil {
+		return fmt.Sprintf("unable to read serial log: %v", err)
+	}
+	return string(data)
+}
+
+func (qe *qemuKVMExecutor) RunOSBuild(manifest []byte, logger logrus.FieldLogger, job worker.Job, opts *osbuild.OSBuildOptions, timings *worker.OSBuildTimings) (*osbuild.Result, error) {
+	prepSrcRes, err := prepareSources(manifest, logger, opts)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to prepare sources: %w", err)
+	}
+	if !prepSrcRes.Success {
+		return prepSrcRes, nil
+	}
+
+	provisioningStart := time.Now()
+	executorHost, exited, stop, err := qe.bootVM(logger)
+	if err != nil {
+		return nil, err
+	}
+	defer stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*10)
+	defer cancel()
+	go func() {
+		// stop waiting if the guest dies while booting
+		select {
+		case <-exited:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	if !waitForSI(ctx, executorHost, nil) {
+		logrus.WithField("serial_log", qe.serialLog()).Error("Executor VM didn't come online")
+		return nil, fmt.Errorf("Timeout waiting for executor to come online")
+	}
+	if timings != nil {
+		timings.Executor = "qemu.kvm"
+		timings.ExecutorProvisioningSeconds = time.Since(provisioningStart).Seconds()
+	}
+
+	return runOnExecutor(executorHost, nil, qe.tmpDir, manifest, logger, job, opts, timings)
+}
+
+// NewQEMUKVMExecutor returns an executor running osbuild in a throwaway local
+// virtual machine, isolating the manifest from the worker host. It uses KVM
+// when the worker has access to it and falls back to emulation otherwise.
+func NewQEMUKVMExecutor(config QEMUKVMConfig, tmpDir string) Executor {
+	if config.Memory == 0 {
+		config.Memory = QEMUDefaultMemory
+	}
+	if config.CPUs == 0 {
+		config.CPUs = QEMUDefaultCPUs
+	}
+
+	return &qemuKVMExecutor{
+		config:     config,
+		tmpDir:     tmpDir,
+		qemuImg:    "qemu-img",
+		qemuSystem: qemuSystemBinary(arch.Current()),
+		kvmDevice:  "/dev/kvm",
+	}
+}
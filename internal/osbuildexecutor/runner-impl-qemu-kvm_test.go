@@ -0,0 +1,67 @@
+package osbuildexecutor_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/image-builder/pkg/osbuild"
+
+	"github.com/osbuild/osbuild-composer/internal/osbuildexecutor"
+)
+
+func TestQEMUArgs(t *testing.T) {
+	config := osbuildexecutor.QEMUKVMConfig{Image: "/var/lib/executor.qcow2", Memory: 2048, CPUs: 4}
+
+	args := strings.Join(osbuildexecutor.QEMUArgs(config, "/tmp/overlay.qcow2", "/tmp/serial.log", 12345, true), " ")
+	assert.Contains(t, args, "-m 2048 -smp 4")
+	assert.Contains(t, args, "-drive file=/tmp/overlay.qcow2,if=virtio,format=qcow2")
+	assert.Contains(t, args, "-serial file:/tmp/serial.log")
+	assert.Contains(t, args, "hostfwd=tcp:127.0.0.1:12345-:8001")
+	assert.Contains(t, args, "-accel kvm -cpu host")
+
+	args = strings.Join(osbuildexecutor.QEMUArgs(config, "/tmp/overlay.qcow2", "/tmp/serial.log", 12345, false), " ")
+	assert.Contains(t, args, "-accel tcg -cpu max")
+}
+
+func TestKVMAvailable(t *testing.T) {
+	assert.False(t, osbuildexecutor.KVMAvailable(filepath.Join(t.TempDir(), "kvm")))
+
+	device := filepath.Join(t.TempDir(), "kvm")
+	require.NoError(t, os.WriteFile(device, nil, 0600))
+	assert.True(t, osbuildexecutor.KVMAvailable(device))
+}
+
+func TestQEMUKVMGuestDies(t *testing.T) {
+	binDir := t.TempDir()
+	t.Setenv("PATH", binDir)
+	tmpDir := t.TempDir()
+
+	writeScript := func(name, script string) string {
+		path := filepath.Join(binDir, name)
+		//nolint:gosec
+		require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0700))
+		return path
+	}
+	writeScript("osbuild", `echo '{"success": true}'`)
+	// the overlay is the last argument
+	qemuImg := writeScript("qemu-img", `for arg; do :; done; : > "$arg"`)
+	qemuSystem := writeScript("qemu-system", fmt.Sprintf(`echo "$@" > %s/args; exit 1`, tmpDir))
+
+	logger, _ := makeMockEntry()
+	executor := osbuildexecutor.NewTestQEMUKVMExecutor(osbuildexecutor.QEMUKVMConfig{Image: "/var/lib/executor.qcow2"},
+		tmpDir, qemuImg, qemuSystem, filepath.Join(tmpDir, "kvm"))
+	_, err := executor.RunOSBuild(nil, logger, nil, &osbuild.OSBuildOptions{}, nil)
+	require.EqualError(t, err, "Timeout waiting for executor to come online")
+
+	require.FileExists(t, filepath.Join(tmpDir, "overlay.qcow2"))
+	args, err := os.ReadFile(filepath.Join(tmpDir, "args"))
+	require.NoError(t, err)
+	assert.Contains(t, string(args), fmt.Sprintf("-m %d -smp %d", osbuildexecutor.QEMUDefaultMemory, osbuildexecutor.QEMUDefaultCPUs))
+	assert.Contains(t, string(args), "-accel tcg")
+}
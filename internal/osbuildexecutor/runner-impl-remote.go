@@ -0,0 +1,218 @@
+package osbuildexecutor
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/osbuild/image-builder/pkg/osbuild"
+
+	"github.com/osbuild/osbuild-composer/internal/worker"
+)
+
+const (
+	// how long to wait for a healthy and idle executor of a pool
+	RemotePoolAcquireTimeout = time.Minute * 10
+	remotePoolRetryInterval  = time.Second * 5
+)
+
+// RemotePool is a pool of pre-provisioned osbuild-worker-executor endpoints,
+// for example on bare metal machines or in another cloud. Builds are spread
+// between the endpoints round-robin, skipping endpoints which are busy or
+// fail their health check. Executors are only reused if they run with
+// -multi-build, the others serve a single build.
+//
+// A pool is safe for concurrent use and should be shared between all builds
+// of a worker.
+type RemotePool struct {
+	endpoints []string
+	transport http.RoundTripper
+
+	mu   sync.Mutex
+	next int
+	busy map[string]bool
+
+	// overridden in tests
+	retryInterval time.Duration
+}
+
+// tokenTransport authenticates the requests to executors with a shared token
+type tokenTransport struct {
+	token string
+	next  http.RoundTripper
+}
+
+func (tt *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+tt.token)
+	return tt.next.RoundTrip(req)
+}
+
+// NewRemotePool creates a pool of the executors at the given URLs. The TLS
+// configuration is optional, it can hold a client certificate for mTLS. The
+// token is optional too, it is sent to executors requiring a shared token.
+func NewRemotePool(endpoints []string, tlsConfig *tls.Config, token string) (*RemotePool, error) {
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("remote executor pool needs at least one endpoint")
+	}
+
+	httpTransport := http.DefaultTransport.(*http.Transport).Clone()
+	httpTransport.TLSClientConfig = tlsConfig
+	var transport http.RoundTripper = httpTransport
+	if token != "" {
+		transport = &tokenTransport{token, httpTransport}
+	}
+
+	return &RemotePool{
+		endpoints:     endpoints,
+		transport:     transport,
+		busy:          make(map[string]bool),
+		retryInterval: remotePoolRetryInterval,
+	}, nil
+}
+
+// candidates returns the idle endpoints in the order they should be tried and
+// moves on the round-robin start
+func (p *RemotePool) candidates() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var candidates []string
+	for i := range p.endpoints {
+		endpoint := p.endpoints[(p.next+i)%len(p.endpoints)]
+		if !p.busy[endpoint] {
+			candidates = append(candidates, endpoint)
+		}
+	}
+	p.next = (p.next + 1) % len(p.endpoints)
+	return candidates
+}
+
+// claim marks an endpoint as busy, it returns false if another build of this
+// worker claimed it first
+func (p *RemotePool) claim(endpoint string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.busy[endpoint] {
+		return false
+	}
+	p.busy[endpoint] = true
+	return true
+}
+
+func (p *RemotePool) release(endpoint string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.busy, endpoint)
+}
+
+// acquire claims the next healthy and idle endpoint of the pool, skipping the
+// excluded ones. It waits until one is available or the context is done.
+func (p *RemotePool) acquire(ctx context.Context, exclude map[string]bool) (string, error) {
+	client := http.Client{
+		Timeout:   time.Second * 5,
+		Transport: p.transport,
+	}
+
+	for {
+		for _, endpoint := range p.candidates() {
+			if exclude[endpoint] {
+				continue
+			}
+			if err := checkExecutor(&client, endpoint); err != nil {
+				logrus.Warnf("Remote executor %s failed its health check: %v", endpoint, err)
+				continue
+			}
+			if p.claim(endpoint) {
+				return endpoint, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("no healthy remote executor available: %w", ctx.Err())
+		case <-time.After(p.retryInterval):
+			// executors which were busy with builds of other workers
+			// might be idle now
+			clear(exclude)
+		}
+	}
+}
+
+// CheckHealth returns an error if none of the executors of the pool passes
+// its health check, executors busy with builds are still healthy
+func (p *RemotePool) CheckHealth(ctx context.Context) error {
+	client := http.Client{
+		Timeout:   time.Second * 5,
+		Transport: p.transport,
+	}
+
+	var errs []error
+	for _, endpoint := range p.endpoints {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		err := checkExecutor(&client, endpoint)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", endpoint, err))
+	}
+	return fmt.Errorf("no remote executor is healthy: %w", errors.Join(errs...))
+}
+
+type remoteExecutor struct {
+	pool   *RemotePool
+	tmpDir string
+}
+
+func (re *remoteExecutor) RunOSBuild(manifest []byte, logger logrus.FieldLogger, job worker.Job, opts *osbuild.OSBuildOptions, timings *worker.OSBuildTimings) (*osbuild.Result, error) {
+	prepSrcRes, err := prepareSources(manifest, logger, opts)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to prepare sources: %w", err)
+	}
+	if !prepSrcRes.Success {
+		return prepSrcRes, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), RemotePoolAcquireTimeout)
+	defer cancel()
+
+	// executors of the pool can be shared with other workers, try the
+	// next one if an executor is already building
+	busy := make(map[string]bool)
+	for {
+		endpoint, err := re.pool.acquire(ctx, busy)
+		if err != nil {
+			return nil, err
+		}
+		logger.Infof("Building on remote executor %s", endpoint)
+
+		result, err := runOnExecutor(endpoint, re.pool.transport, re.tmpDir, manifest, logger, job, opts, timings)
+		if errors.Is(err, ErrExecutorBusy) {
+			re.pool.release(endpoint)
+			logger.Infof("Remote executor %s is busy", endpoint)
+			busy[endpoint] = true
+			continue
+		}
+		re.pool.release(endpoint)
+		return result, err
+	}
+}
+
+// NewRemoteExecutor returns an executor running osbuild on an executor of the
+// pool
+func NewRemoteExecutor(pool *RemotePool, tmpDir string) Executor {
+	return &remoteExecutor{
+		pool:   pool,
+		tmpDir: tmpDir,
+	}
+}
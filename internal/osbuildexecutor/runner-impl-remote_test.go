@@ -0,0 +1,89 @@
+package osbuildexecutor_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/osbuildexecutor"
+)
+
+func TestRemotePool(t *testing.T) {
+	_, err := osbuildexecutor.NewRemotePool(nil, nil, "")
+	require.Error(t, err)
+
+	newExecutor := func(status int) string {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+		}))
+		t.Cleanup(server.Close)
+		return server.URL
+	}
+	broken := newExecutor(http.StatusInternalServerError)
+	first := newExecutor(http.StatusOK)
+	second := newExecutor(http.StatusOK)
+
+	pool, err := osbuildexecutor.NewRemotePool([]string{broken, first, second}, nil, "")
+	require.NoError(t, err)
+
+	// unhealthy executors are skipped
+	endpoint, err := pool.Acquire(context.Background(), map[string]bool{})
+	require.NoError(t, err)
+	assert.Equal(t, first, endpoint)
+
+	// executors building for this worker are skipped
+	endpoint, err = pool.Acquire(context.Background(), map[string]bool{})
+	require.NoError(t, err)
+	assert.Equal(t, second, endpoint)
+
+	// no idle executor left
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+	_, err = pool.Acquire(ctx, map[string]bool{})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	pool.Release(first)
+	endpoint, err = pool.Acquire(context.Background(), map[string]bool{})
+	require.NoError(t, err)
+	assert.Equal(t, first, endpoint)
+
+	// executors which reported being busy are tried again later
+	pool.Release(first)
+	pool.Release(second)
+	endpoint, err = pool.Acquire(context.Background(), map[string]bool{first: true, second: true})
+	require.NoError(t, err)
+	assert.Contains(t, []string{first, second}, endpoint)
+
+	// the pool is healthy as long as one executor is
+	require.NoError(t, pool.CheckHealth(context.Background()))
+	pool, err = osbuildexecutor.NewRemotePool([]string{broken}, nil, "")
+	require.NoError(t, err)
+	require.ErrorContains(t, pool.CheckHealth(context.Background()), "no remote executor is healthy")
+}
+
+func TestRemotePoolToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	pool, err := osbuildexecutor.NewRemotePool([]string{server.URL}, nil, "secret")
+	require.NoError(t, err)
+	endpoint, err := pool.Acquire(context.Background(), map[string]bool{})
+	require.NoError(t, err)
+	assert.Equal(t, server.URL, endpoint)
+
+	pool, err = osbuildexecutor.NewRemotePool([]string{server.URL}, nil, "")
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+	_, err = pool.Acquire(ctx, map[string]bool{})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
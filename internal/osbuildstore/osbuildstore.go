@@ -0,0 +1,311 @@
+// Package osbuildstore manages the size of the osbuild store of a worker.
+//
+// osbuild keeps the objects of the builds in its store and evicts them
+// itself once they exceed the size given with --cache-max-size. Sources,
+// like the downloaded RPMs, are never evicted by osbuild. The Manager tracks
+// their use and evicts the least recently used ones to keep the whole store
+// within a size budget.
+package osbuildstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	sourcesDir = "sources"
+	objectsDir = "objects"
+)
+
+// Stats describe how the store was used by a build
+type Stats struct {
+	// sources of the manifest which were already in the store
+	SourceHits int
+	// sources of the manifest which needed to be fetched
+	SourceMisses int
+	// size of the fetched sources
+	DownloadedBytes int64
+	// sources evicted after the build
+	EvictedSources int
+	EvictedBytes   int64
+	// size of the store after the build
+	Size int64
+}
+
+// Manager manages one osbuild store. It is not safe for concurrent use, but
+// stores aren't shared between the slots of a worker.
+type Manager struct {
+	path string
+	// 0 means the store isn't limited
+	maxSize int64
+}
+
+// Build tracks the sources of one build
+type Build struct {
+	sources []string
+	present map[string]bool
+	stats   Stats
+}
+
+func New(path string, maxSize int64) *Manager {
+	return &Manager{
+		path:    path,
+		maxSize: maxSize,
+	}
+}
+
+// Prepare records which sources of the manifest are already in the store
+// and marks them as used.
+func (m *Manager) Prepare(manifest []byte) (*Build, error) {
+	sources, err := ManifestSources(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Build{
+		sources: sources,
+		present: make(map[string]bool),
+	}
+	for _, source := range sources {
+		if _, err := os.Stat(filepath.Join(m.path, source)); err != nil {
+			b.stats.SourceMisses++
+			continue
+		}
+		b.present[source] = true
+		b.stats.SourceHits++
+		m.touch(source)
+	}
+	return b, nil
+}
+
+// ObjectsMaxSize returns the size osbuild may use for its objects, it is
+// what's left of the budget after the sources. It returns 0 if the store
+// isn't limited.
+func (m *Manager) ObjectsMaxSize() (int64, error) {
+	if m.maxSize == 0 {
+		return 0, nil
+	}
+	sources, err := m.sourceEntries()
+	if err != nil {
+		return 0, err
+	}
+	var size int64
+	for _, e := range sources {
+		size += e.size
+	}
+	// osbuild uses its default size for 0, 1 byte disables caching objects
+	return max(m.maxSize-size, 1), nil
+}
+
+// Finish accounts the sources fetched by the build and evicts the least
+// recently used sources until the store fits into its size budget.
+func (m *Manager) Finish(b *Build) (Stats, error) {
+	stats := b.stats
+	for _, source := range b.sources {
+		if b.present[source] {
+			continue
+		}
+		size, err := dirSize(filepath.Join(m.path, source))
+		if err != nil {
+			// the build failed before fetching it
+			continue
+		}
+		stats.DownloadedBytes += size
+		m.touch(source)
+	}
+
+	size, evicted, evictedBytes, err := m.evict()
+	if err != nil {
+		return stats, err
+	}
+	stats.EvictedSources = evicted
+	stats.EvictedBytes = evictedBytes
+	stats.Size = size
+	return stats, nil
+}
+
+type entry struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// evict removes sources, least recently used first, until the store is
+// within its budget. It returns the size of the store afterwards.
+func (m *Manager) evict() (int64, int, int64, error) {
+	sources, err := m.sourceEntries()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	objects, err := dirSize(filepath.Join(m.path, objectsDir))
+	if err != nil && !os.IsNotExist(err) {
+		return 0, 0, 0, err
+	}
+
+	size := objects
+	for _, e := range sources {
+		size += e.size
+	}
+	if m.maxSize == 0 || size <= m.maxSize {
+		return size, 0, 0, nil
+	}
+
+	sort.Slice(sources, func(i, j int) bool {
+		return sources[i].modTime.Before(sources[j].modTime)
+	})
+
+	var evicted int
+	var evictedBytes int64
+	for _, e := range sources {
+		if size <= m.maxSize {
+			break
+		}
+		err := os.RemoveAll(filepath.Join(m.path, e.path))
+		if err != nil {
+			return size, evicted, evictedBytes, fmt.Errorf("cannot evict %s: %w", e.path, err)
+		}
+		logrus.Debugf("Evicted %s (%d bytes) from the osbuild store", e.path, e.size)
+		size -= e.size
+		evicted++
+		evictedBytes += e.size
+	}
+	if size > m.maxSize {
+		logrus.Warnf("osbuild store at %s uses %d bytes after evicting all sources, more than its budget of %d bytes", m.path, size, m.maxSize)
+	}
+	return size, evicted, evictedBytes, nil
+}
+
+// sourceEntries lists the sources in the store, they are kept in
+// sources/<source type>/<checksum>
+func (m *Manager) sourceEntries() ([]entry, error) {
+	types, err := os.ReadDir(filepath.Join(m.path, sourcesDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []entry
+	for _, t := range types {
+		if !t.IsDir() {
+			continue
+		}
+		items, err := os.ReadDir(filepath.Join(m.path, sourcesDir, t.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			info, err := item.Info()
+			if err != nil {
+				return nil, err
+			}
+			path := filepath.Join(sourcesDir, t.Name(), item.Name())
+			size, err := dirSize(filepath.Join(m.path, path))
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, entry{
+				path:    path,
+				size:    size,
+				modTime: info.ModTime(),
+			})
+		}
+	}
+	return entries, nil
+}
+
+// touch marks a source as used, the modification time is what eviction
+// goes by, access times aren't reliable with relatime or noatime mounts
+func (m *Manager) touch(source string) {
+	now := time.Now()
+	err := os.Chtimes(filepath.Join(m.path, source), now, now)
+	if err != nil && !os.IsNotExist(err) {
+		logrus.Warnf("Unable to mark %s as used: %v", source, err)
+	}
+}
+
+// dirSize returns the size of the regular files in path, or of path itself
+// if it is a file
+func dirSize(path string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
+
+// sourceContentDirs maps the source types to the directories osbuild keeps
+// their items in below sources/, several types share one directory. The
+// items of other types, like org.osbuild.containers-storage, aren't kept in
+// the store.
+var sourceContentDirs = map[string]string{
+	"org.osbuild.curl":         "org.osbuild.files",
+	"org.osbuild.inline":       "org.osbuild.files",
+	"org.osbuild.librepo":      "org.osbuild.files",
+	"org.osbuild.skopeo-index": "org.osbuild.files",
+	"org.osbuild.skopeo":       "org.osbuild.containers",
+}
+
+// all ostree commits are pulled into one repository
+const ostreeSource = "org.osbuild.ostree"
+
+var ostreeRepo = filepath.Join(sourcesDir, "org.osbuild.ostree", "repo")
+
+// ManifestSources returns the paths of the source items of an osbuild
+// manifest relative to the store. Items are kept by their checksum, except
+// for ostree commits which share one repository.
+func ManifestSources(manifest []byte) ([]string, error) {
+	var m struct {
+		Sources map[string]struct {
+			Items map[string]json.RawMessage `json:"items"`
+		} `json:"sources"`
+	}
+	err := json.Unmarshal(manifest, &m)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the sources of the manifest: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var sources []string
+	for sourceType, source := range m.Sources {
+		if sourceType == ostreeSource {
+			if len(source.Items) > 0 && !seen[ostreeRepo] {
+				seen[ostreeRepo] = true
+				sources = append(sources, ostreeRepo)
+			}
+			continue
+		}
+		dir, ok := sourceContentDirs[sourceType]
+		if !ok {
+			continue
+		}
+		for checksum := range source.Items {
+			path := filepath.Join(sourcesDir, dir, checksum)
+			if !seen[path] {
+				seen[path] = true
+				sources = append(sources, path)
+			}
+		}
+	}
+	sort.Strings(sources)
+	return sources, nil
+}
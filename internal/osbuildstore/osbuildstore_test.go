@@ -0,0 +1,161 @@
+package osbuildstore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testManifest = `{
+	"version": "2",
+	"sources": {
+		"org.osbuild.curl": {
+			"items": {
+				"sha256:aaaa": {"url": "https://example.com/a.rpm"},
+				"sha256:bbbb": {"url": "https://example.com/b.rpm"}
+			}
+		},
+		"org.osbuild.inline": {
+			"items": {
+				"sha256:cccc": {"encoding": "base64", "data": ""}
+			}
+		}
+	}
+}`
+
+func writeFile(t *testing.T, path string, size int, modTime time.Time) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, make([]byte, size), 0600))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func TestManifestSources(t *testing.T) {
+	manifest := `{
+		"version": "2",
+		"sources": {
+			"org.osbuild.curl": {
+				"items": {
+					"sha256:aaaa": {"url": "https://example.com/a.rpm"},
+					"sha256:bbbb": {"url": "https://example.com/b.rpm"}
+				}
+			},
+			"org.osbuild.inline": {
+				"items": {
+					"sha256:cccc": {"encoding": "base64", "data": ""}
+				}
+			},
+			"org.osbuild.skopeo": {
+				"items": {
+					"sha256:dddd": {"image": {"name": "registry.example.com/fedora", "digest": "sha256:eeee"}}
+				}
+			},
+			"org.osbuild.ostree": {
+				"items": {
+					"ffff": {"remote": {"url": "https://example.com/repo"}},
+					"0000": {"remote": {"url": "https://example.com/repo"}}
+				}
+			},
+			"org.osbuild.containers-storage": {
+				"items": {
+					"sha256:1111": {}
+				}
+			}
+		}
+	}`
+	sources, err := ManifestSources([]byte(manifest))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"sources/org.osbuild.containers/sha256:dddd",
+		"sources/org.osbuild.files/sha256:aaaa",
+		"sources/org.osbuild.files/sha256:bbbb",
+		"sources/org.osbuild.files/sha256:cccc",
+		"sources/org.osbuild.ostree/repo",
+	}, sources)
+
+	sources, err = ManifestSources([]byte(`{"version": "2"}`))
+	require.NoError(t, err)
+	assert.Empty(t, sources)
+
+	_, err = ManifestSources([]byte(`not a manifest`))
+	require.Error(t, err)
+}
+
+func TestBuildStats(t *testing.T) {
+	store := t.TempDir()
+	old := time.Now().Add(-time.Hour)
+	writeFile(t, filepath.Join(store, "sources/org.osbuild.files/sha256:aaaa"), 100, old)
+
+	m := New(store, 0)
+	b, err := m.Prepare([]byte(testManifest))
+	require.NoError(t, err)
+
+	// hits are marked as used
+	info, err := os.Stat(filepath.Join(store, "sources/org.osbuild.files/sha256:aaaa"))
+	require.NoError(t, err)
+	assert.True(t, info.ModTime().After(old))
+
+	// osbuild fetches the missing sources, one of them fails
+	writeFile(t, filepath.Join(store, "sources/org.osbuild.files/sha256:bbbb"), 200, old)
+	writeFile(t, filepath.Join(store, "objects/1234/data/tree/file"), 50, old)
+
+	stats, err := m.Finish(b)
+	require.NoError(t, err)
+	assert.Equal(t, Stats{
+		SourceHits:      1,
+		SourceMisses:    2,
+		DownloadedBytes: 200,
+		Size:            350,
+	}, stats)
+}
+
+func TestEvictLeastRecentlyUsed(t *testing.T) {
+	store := t.TempDir()
+	now := time.Now()
+	writeFile(t, filepath.Join(store, "sources/org.osbuild.files/sha256:old"), 100, now.Add(-3*time.Hour))
+	writeFile(t, filepath.Join(store, "sources/org.osbuild.files/sha256:older"), 100, now.Add(-4*time.Hour))
+	writeFile(t, filepath.Join(store, "sources/org.osbuild.files/sha256:aaaa"), 100, now.Add(-5*time.Hour))
+	writeFile(t, filepath.Join(store, "objects/1234/data/tree/file"), 100, now)
+
+	m := New(store, 300)
+	objectsMaxSize, err := m.ObjectsMaxSize()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), objectsMaxSize)
+
+	// aaaa is the oldest, but the build uses it
+	b, err := m.Prepare([]byte(testManifest))
+	require.NoError(t, err)
+	stats, err := m.Finish(b)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.EvictedSources)
+	assert.Equal(t, int64(100), stats.EvictedBytes)
+	assert.Equal(t, int64(300), stats.Size)
+
+	assert.NoFileExists(t, filepath.Join(store, "sources/org.osbuild.files/sha256:older"))
+	assert.FileExists(t, filepath.Join(store, "sources/org.osbuild.files/sha256:old"))
+	assert.FileExists(t, filepath.Join(store, "sources/org.osbuild.files/sha256:aaaa"))
+
+	objectsMaxSize, err = m.ObjectsMaxSize()
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), objectsMaxSize)
+}
+
+func TestUnlimitedStore(t *testing.T) {
+	store := t.TempDir()
+	writeFile(t, filepath.Join(store, "sources/org.osbuild.files/sha256:old"), 100, time.Now().Add(-time.Hour))
+
+	m := New(store, 0)
+	objectsMaxSize, err := m.ObjectsMaxSize()
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), objectsMaxSize)
+
+	b, err := m.Prepare([]byte(`{"version": "2"}`))
+	require.NoError(t, err)
+	stats, err := m.Finish(b)
+	require.NoError(t, err)
+	assert.Equal(t, Stats{Size: 100}, stats)
+	assert.FileExists(t, filepath.Join(store, "sources/org.osbuild.files/sha256:old"))
+}
@@ -0,0 +1,117 @@
+package prometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// The workers measure the steps of their jobs and report the timings in the
+// job results, composer collects them as they don't serve metrics themselves.
+
+var stepBuckets = []float64{.1, .2, .5, 1, 2.5, 5, 10, 20, 30, 60, 90, 120, 180, 240, 300, 420, 600, 900, 1200, 1800, 2700, 3600, 5400, 7200, 10800}
+
+var (
+	OSBuildPipelineDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:      "osbuild_pipeline_duration_seconds",
+		Namespace: Namespace,
+		Subsystem: WorkerSubsystem,
+		Help:      "Duration of the osbuild pipelines of builds.",
+		Buckets:   stepBuckets,
+	}, []string{"pipeline", "tenant", "arch", "image_type"})
+)
+
+var (
+	OSBuildStageDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:      "osbuild_stage_duration_seconds",
+		Namespace: Namespace,
+		Subsystem: WorkerSubsystem,
+		Help:      "Duration of the osbuild stages of builds.",
+		Buckets:   stepBuckets,
+	}, []string{"stage", "tenant", "arch", "image_type"})
+)
+
+var (
+	UploadDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:      "upload_duration_seconds",
+		Namespace: Namespace,
+		Subsystem: WorkerSubsystem,
+		Help:      "Duration of the uploads of images to their targets.",
+		Buckets:   stepBuckets,
+	}, []string{"target", "status", "tenant", "arch", "image_type"})
+)
+
+var (
+	UploadSize = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:      "upload_size_bytes",
+		Namespace: Namespace,
+		Subsystem: WorkerSubsystem,
+		Help:      "Size of the images uploaded to their targets.",
+		// 16 MiB to 128 GiB
+		Buckets: prometheus.ExponentialBuckets(16*1024*1024, 2, 14),
+	}, []string{"target", "tenant", "arch", "image_type"})
+)
+
+var (
+	ExecutorProvisioningDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:      "executor_provisioning_duration_seconds",
+		Namespace: Namespace,
+		Subsystem: WorkerSubsystem,
+		Help:      "Duration of provisioning the executors builds run on.",
+		Buckets:   stepBuckets,
+	}, []string{"executor", "tenant", "arch"})
+)
+
+var (
+	DepsolveDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:      "depsolve_duration_seconds",
+		Namespace: Namespace,
+		Subsystem: WorkerSubsystem,
+		Help:      "Duration of depsolving the package sets of composes.",
+		Buckets:   stepBuckets,
+	}, []string{"distro", "tenant", "arch"})
+)
+
+var (
+	ContainerResolveDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:      "container_resolve_duration_seconds",
+		Namespace: Namespace,
+		Subsystem: WorkerSubsystem,
+		Help:      "Duration of resolving the containers of composes.",
+		Buckets:   stepBuckets,
+	}, []string{"tenant", "arch"})
+)
+
+// OSBuildStepMetrics records the duration of a pipeline, or of a stage if
+// stage isn't empty
+func OSBuildStepMetrics(pipeline, stage string, seconds float64, tenant, arch, imageType string) {
+	if stage == "" {
+		OSBuildPipelineDuration.WithLabelValues(pipeline, tenant, arch, imageType).Observe(seconds)
+	} else {
+		OSBuildStageDuration.WithLabelValues(stage, tenant, arch, imageType).Observe(seconds)
+	}
+}
+
+// UploadMetrics records an upload to a target, bytes is zero if the size of
+// the artifact isn't known
+func UploadMetrics(target string, success bool, seconds float64, bytes int64, tenant, arch, imageType string) {
+	status := "success"
+	if !success {
+		status = "failure"
+	}
+	UploadDuration.WithLabelValues(target, status, tenant, arch, imageType).Observe(seconds)
+	if bytes > 0 {
+		UploadSize.WithLabelValues(target, tenant, arch, imageType).Observe(float64(bytes))
+	}
+}
+
+func ExecutorProvisioningMetrics(executor string, seconds float64, tenant, arch string) {
+	ExecutorProvisioningDuration.WithLabelValues(executor, tenant, arch).Observe(seconds)
+}
+
+func DepsolveMetrics(distro string, seconds float64, tenant, arch string) {
+	DepsolveDuration.WithLabelValues(distro, tenant, arch).Observe(seconds)
+}
+
+func ContainerResolveMetrics(seconds float64, tenant, arch string) {
+	ContainerResolveDuration.WithLabelValues(tenant, arch).Observe(seconds)
+}
@@ -4,4 +4,6 @@ const (
 	Namespace         = "image_builder"
 	ComposerSubsystem = "composer"
 	WorkerSubsystem   = "worker"
+
+	ContentCacheSubsystem = "content_cache"
 )
@@ -0,0 +1,31 @@
+package prometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	ContentCacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:      "requests_total",
+		Namespace: Namespace,
+		Subsystem: ContentCacheSubsystem,
+		Help:      "Requests for files, container blobs and manifests by whether they were cached",
+	}, []string{"kind", "result"})
+)
+
+var (
+	ContentCacheBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:      "bytes_total",
+		Namespace: Namespace,
+		Subsystem: ContentCacheSubsystem,
+		Help:      "Bytes served by the content cache by whether they were cached",
+	}, []string{"kind", "result"})
+)
+
+// ContentCacheRequest counts a request of the content cache, result is
+// "hit", "miss", "passthrough" for content which can't be cached or "error"
+func ContentCacheRequest(kind, result string, bytes int64) {
+	ContentCacheRequests.WithLabelValues(kind, result).Inc()
+	ContentCacheBytes.WithLabelValues(kind, result).Add(float64(bytes))
+}
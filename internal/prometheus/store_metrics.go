@@ -0,0 +1,52 @@
+package prometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// The workers report how their builds used their osbuild stores in the job
+// results, composer collects them as they don't serve metrics themselves.
+
+var (
+	StoreSourceHits = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:      "store_source_hits_total",
+		Namespace: Namespace,
+		Subsystem: WorkerSubsystem,
+		Help:      "Sources of builds which were already in the osbuild store of the worker",
+	}, []string{"arch"})
+)
+
+var (
+	StoreSourceMisses = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:      "store_source_misses_total",
+		Namespace: Namespace,
+		Subsystem: WorkerSubsystem,
+		Help:      "Sources of builds which needed to be fetched into the osbuild store of the worker",
+	}, []string{"arch"})
+)
+
+var (
+	StoreDownloadedBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:      "store_downloaded_bytes_total",
+		Namespace: Namespace,
+		Subsystem: WorkerSubsystem,
+		Help:      "Size of the sources fetched into the osbuild stores of the workers",
+	}, []string{"arch"})
+)
+
+var (
+	StoreEvictedBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:      "store_evicted_bytes_total",
+		Namespace: Namespace,
+		Subsystem: WorkerSubsystem,
+		Help:      "Size of the sources evicted from the osbuild stores of the workers",
+	}, []string{"arch"})
+)
+
+func StoreMetrics(arch string, hits, misses int, downloaded, evicted int64) {
+	StoreSourceHits.WithLabelValues(arch).Add(float64(hits))
+	StoreSourceMisses.WithLabelValues(arch).Add(float64(misses))
+	StoreDownloadedBytes.WithLabelValues(arch).Add(float64(downloaded))
+	StoreEvictedBytes.WithLabelValues(arch).Add(float64(evicted))
+}
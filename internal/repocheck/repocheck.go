@@ -0,0 +1,456 @@
+// Package repocheck probes rpm-md repositories without running a full
+// depsolve. It fetches repomd.xml, reads the package count from the primary
+// metadata and validates the configured GPG keys so that broken sources can
+// be reported before they are used in a compose.
+package repocheck
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+
+	"github.com/osbuild/image-builder/pkg/rpmmd"
+)
+
+const (
+	repomdPath    = "repodata/repomd.xml"
+	repomdAscPath = "repodata/repomd.xml.asc"
+
+	// maxMetadataSize limits how much of a single metadata file is read
+	maxMetadataSize = 64 * 1024 * 1024
+)
+
+// Result holds the outcome of probing a single repository
+type Result struct {
+	// URL is the base URL the metadata was fetched from
+	URL string
+
+	Revision     string
+	LastUpdated  time.Time
+	PackageCount int
+
+	// TLSError is set when the connection failed because of a TLS problem,
+	// e.g. an unknown certificate authority or a hostname mismatch
+	TLSError string
+
+	// GPGErrors lists problems with the configured GPG keys and the
+	// repository metadata signature
+	GPGErrors []string
+
+	// Error is set when the repository metadata could not be fetched or parsed
+	Error string
+}
+
+// Healthy returns true if no problem was found with the repository
+func (r *Result) Healthy() bool {
+	return r.Error == "" && r.TLSError == "" && len(r.GPGErrors) == 0
+}
+
+// Checker probes repositories over HTTP(S)
+type Checker struct {
+	// Proxy is used for all requests if set
+	Proxy *url.URL
+
+	// Timeout is the overall time limit for probing a single repository
+	Timeout time.Duration
+}
+
+// NewChecker returns a Checker with the default timeout
+func NewChecker() *Checker {
+	return &Checker{
+		Timeout: 2 * time.Minute,
+	}
+}
+
+// Check probes the given repository and returns the result. Errors are
+// reported in the result, they never abort the check of other repositories.
+func (c *Checker) Check(ctx context.Context, repo rpmmd.RepoConfig) Result {
+	var result Result
+
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	client, err := c.httpClient(repo)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	baseURL, err := resolveBaseURL(ctx, client, repo)
+	if err != nil {
+		result.setFetchError(err)
+		return result
+	}
+	result.URL = baseURL
+
+	repomdData, err := get(ctx, client, joinURL(baseURL, repomdPath))
+	if err != nil {
+		result.setFetchError(err)
+		return result
+	}
+
+	repomd, err := parseRepomd(repomdData)
+	if err != nil {
+		result.Error = fmt.Sprintf("cannot parse repomd.xml: %v", err)
+		return result
+	}
+	result.Revision = repomd.Revision
+	result.LastUpdated = repomd.lastUpdated()
+
+	if primary := repomd.data("primary"); primary == nil {
+		result.Error = "repomd.xml does not reference primary metadata"
+	} else {
+		count, err := packageCount(ctx, client, joinURL(baseURL, primary.Location.Href))
+		if err != nil {
+			result.setFetchError(fmt.Errorf("cannot read primary metadata: %w", err))
+		} else {
+			result.PackageCount = count
+		}
+	}
+
+	result.GPGErrors = checkGPG(ctx, client, repo, baseURL, repomdData)
+
+	return result
+}
+
+func (r *Result) setFetchError(err error) {
+	if tlsErr := tlsProblem(err); tlsErr != "" {
+		r.TLSError = tlsErr
+		return
+	}
+	r.Error = err.Error()
+}
+
+// tlsProblem returns a description of err if it was caused by a failed TLS
+// handshake or certificate verification, or an empty string otherwise
+func tlsProblem(err error) string {
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostname x509.HostnameError
+	var invalid x509.CertificateInvalidError
+	var verification *tls.CertificateVerificationError
+	var recordHeader tls.RecordHeaderError
+
+	switch {
+	case errors.As(err, &unknownAuthority):
+		return unknownAuthority.Error()
+	case errors.As(err, &hostname):
+		return hostname.Error()
+	case errors.As(err, &invalid):
+		return invalid.Error()
+	case errors.As(err, &verification):
+		return verification.Error()
+	case errors.As(err, &recordHeader):
+		return recordHeader.Error()
+	}
+	return ""
+}
+
+func (c *Checker) httpClient(repo rpmmd.RepoConfig) (*http.Client, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if repo.IgnoreSSL != nil && *repo.IgnoreSSL {
+		tlsConfig.InsecureSkipVerify = true // #nosec G402
+	}
+
+	if repo.SSLCACert != "" {
+		caCert, err := os.ReadFile(repo.SSLCACert)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read CA certificate: %w", err)
+		}
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("no certificates found in %s", repo.SSLCACert)
+		}
+		tlsConfig.RootCAs = roots
+	}
+
+	if repo.SSLClientCert != "" && repo.SSLClientKey != "" {
+		cert, err := tls.LoadX509KeyPair(repo.SSLClientCert, repo.SSLClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	if c.Proxy != nil {
+		transport.Proxy = http.ProxyURL(c.Proxy)
+	}
+
+	return &http.Client{Transport: transport}, nil
+}
+
+// resolveBaseURL returns the first base URL of the repository. Mirrorlists
+// and metalinks are fetched and the first mirror is used.
+func resolveBaseURL(ctx context.Context, client *http.Client, repo rpmmd.RepoConfig) (string, error) {
+	switch {
+	case len(repo.BaseURLs) > 0:
+		return repo.BaseURLs[0], nil
+	case repo.MirrorList != "":
+		data, err := get(ctx, client, repo.MirrorList)
+		if err != nil {
+			return "", err
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if line != "" && !strings.HasPrefix(line, "#") {
+				return line, nil
+			}
+		}
+		return "", fmt.Errorf("mirrorlist %s contains no mirrors", repo.MirrorList)
+	case repo.Metalink != "":
+		data, err := get(ctx, client, repo.Metalink)
+		if err != nil {
+			return "", err
+		}
+		return parseMetalink(data)
+	}
+
+	return "", errors.New("repository has no baseurl, mirrorlist or metalink")
+}
+
+func parseMetalink(data []byte) (string, error) {
+	var metalink struct {
+		URLs []string `xml:"files>file>resources>url"`
+	}
+	if err := xml.Unmarshal(data, &metalink); err != nil {
+		return "", fmt.Errorf("cannot parse metalink: %w", err)
+	}
+	for _, u := range metalink.URLs {
+		u = strings.TrimSpace(u)
+		if strings.HasSuffix(u, repomdPath) {
+			return strings.TrimSuffix(u, repomdPath), nil
+		}
+	}
+	return "", errors.New("metalink contains no repomd.xml location")
+}
+
+type repoMDEntry struct {
+	Type     string `xml:"type,attr"`
+	Location struct {
+		Href string `xml:"href,attr"`
+	} `xml:"location"`
+	Timestamp int64 `xml:"timestamp"`
+}
+
+type repoMD struct {
+	Revision string        `xml:"revision"`
+	Data     []repoMDEntry `xml:"data"`
+}
+
+func parseRepomd(data []byte) (*repoMD, error) {
+	var md repoMD
+	if err := xml.Unmarshal(data, &md); err != nil {
+		return nil, err
+	}
+	return &md, nil
+}
+
+func (md *repoMD) data(dataType string) *repoMDEntry {
+	for i := range md.Data {
+		if md.Data[i].Type == dataType {
+			return &md.Data[i]
+		}
+	}
+	return nil
+}
+
+// lastUpdated returns the newest timestamp of the metadata files, falling
+// back to the revision if it is a unix timestamp
+func (md *repoMD) lastUpdated() time.Time {
+	var newest int64
+	for _, d := range md.Data {
+		if d.Timestamp > newest {
+			newest = d.Timestamp
+		}
+	}
+	if newest == 0 {
+		if rev, err := strconv.ParseInt(md.Revision, 10, 64); err == nil {
+			newest = rev
+		}
+	}
+	if newest == 0 {
+		return time.Time{}
+	}
+	return time.Unix(newest, 0).UTC()
+}
+
+// packageCount reads the "packages" attribute of the root element of the
+// primary metadata. Only the beginning of the file is decompressed.
+func packageCount(ctx context.Context, client *http.Client, u string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("GET %s returned %s", u, resp.Status)
+	}
+
+	reader, err := decompress(path.Ext(req.URL.Path), io.LimitReader(resp.Body, maxMetadataSize))
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+
+	decoder := xml.NewDecoder(reader)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return 0, err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		for _, attr := range start.Attr {
+			if attr.Name.Local == "packages" {
+				return strconv.Atoi(attr.Value)
+			}
+		}
+		return 0, fmt.Errorf("%s element has no packages attribute", start.Name.Local)
+	}
+}
+
+func decompress(ext string, r io.Reader) (io.ReadCloser, error) {
+	switch ext {
+	case ".gz":
+		return gzip.NewReader(r)
+	case ".xz":
+		reader, err := xz.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return io.NopCloser(reader), nil
+	case ".zst":
+		decoder, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		return decoder.IOReadCloser(), nil
+	case ".xml":
+		return io.NopCloser(r), nil
+	}
+	return nil, fmt.Errorf("unsupported metadata compression %q", ext)
+}
+
+// checkGPG validates the configured keys and, if enabled, the signature of
+// repomd.xml
+func checkGPG(ctx context.Context, client *http.Client, repo rpmmd.RepoConfig, baseURL string, repomdData []byte) []string {
+	var problems []string
+
+	checkGPG := repo.CheckGPG != nil && *repo.CheckGPG
+	checkRepoGPG := repo.CheckRepoGPG != nil && *repo.CheckRepoGPG
+
+	if (checkGPG || checkRepoGPG) && len(repo.GPGKeys) == 0 {
+		problems = append(problems, "GPG checks are enabled but no gpgkeys are configured")
+	}
+
+	var keyring openpgp.EntityList
+	for _, key := range repo.GPGKeys {
+		data, err := loadKey(ctx, client, key)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("cannot load gpgkey %s: %v", keyName(key), err))
+			continue
+		}
+		entities, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(data))
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("invalid gpgkey %s: %v", keyName(key), err))
+			continue
+		}
+		keyring = append(keyring, entities...)
+	}
+
+	if !checkRepoGPG || len(keyring) == 0 {
+		return problems
+	}
+
+	signature, err := get(ctx, client, joinURL(baseURL, repomdAscPath))
+	if err != nil {
+		return append(problems, fmt.Sprintf("cannot fetch repomd.xml signature: %v", err))
+	}
+	_, err = openpgp.CheckArmoredDetachedSignature(keyring, bytes.NewReader(repomdData), bytes.NewReader(signature), nil)
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("repomd.xml signature is not valid: %v", err))
+	}
+
+	return problems
+}
+
+// loadKey returns the armored key data. Keys are either inline or
+// referenced by a http(s) or file URL.
+func loadKey(ctx context.Context, client *http.Client, key string) ([]byte, error) {
+	key = strings.TrimSpace(key)
+	if strings.HasPrefix(key, "-----BEGIN PGP PUBLIC KEY BLOCK-----") {
+		return []byte(key), nil
+	}
+
+	u, err := url.Parse(key)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "http", "https":
+		return get(ctx, client, key)
+	case "file":
+		return os.ReadFile(u.Path)
+	}
+	return nil, fmt.Errorf("unsupported key location %q", key)
+}
+
+// keyName returns a short identification of the key for error messages
+func keyName(key string) string {
+	if strings.HasPrefix(strings.TrimSpace(key), "-----BEGIN") {
+		return "(inline)"
+	}
+	return key
+}
+
+func joinURL(base, p string) string {
+	return strings.TrimSuffix(base, "/") + "/" + strings.TrimPrefix(p, "/")
+}
+
+func get(ctx context.Context, client *http.Client, u string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s returned %s", u, resp.Status)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, maxMetadataSize))
+}
@@ -0,0 +1,216 @@
+package repocheck
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/image-builder/pkg/rpmmd"
+	"github.com/osbuild/osbuild-composer/internal/common"
+)
+
+const testRepoPath = "../../test/data/testrepo/"
+
+func TestCheckHealthyRepo(t *testing.T) {
+	server := httptest.NewServer(http.FileServer(http.Dir(testRepoPath)))
+	defer server.Close()
+
+	result := NewChecker().Check(context.Background(), rpmmd.RepoConfig{
+		BaseURLs: []string{server.URL},
+	})
+
+	assert.True(t, result.Healthy(), "unexpected problems: %+v", result)
+	assert.Equal(t, server.URL, result.URL)
+	assert.Equal(t, "1644263915", result.Revision)
+	assert.Equal(t, time.Unix(1644263915, 0).UTC(), result.LastUpdated)
+	assert.Equal(t, 1125, result.PackageCount)
+}
+
+func TestCheckMirrorlist(t *testing.T) {
+	repo := httptest.NewServer(http.FileServer(http.Dir(testRepoPath)))
+	defer repo.Close()
+
+	mirrors := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "# mirrors\n\n%s/\n", repo.URL)
+	}))
+	defer mirrors.Close()
+
+	result := NewChecker().Check(context.Background(), rpmmd.RepoConfig{
+		MirrorList: mirrors.URL,
+	})
+
+	assert.True(t, result.Healthy(), "unexpected problems: %+v", result)
+	assert.Equal(t, repo.URL+"/", result.URL)
+	assert.Equal(t, 1125, result.PackageCount)
+}
+
+func TestCheckMissingRepo(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	result := NewChecker().Check(context.Background(), rpmmd.RepoConfig{
+		BaseURLs: []string{server.URL},
+	})
+
+	assert.False(t, result.Healthy())
+	assert.Contains(t, result.Error, "404")
+	assert.Empty(t, result.TLSError)
+}
+
+func TestCheckTLSError(t *testing.T) {
+	server := httptest.NewTLSServer(http.FileServer(http.Dir(testRepoPath)))
+	defer server.Close()
+
+	result := NewChecker().Check(context.Background(), rpmmd.RepoConfig{
+		BaseURLs: []string{server.URL},
+	})
+	assert.False(t, result.Healthy())
+	assert.NotEmpty(t, result.TLSError)
+	assert.Empty(t, result.Error)
+
+	result = NewChecker().Check(context.Background(), rpmmd.RepoConfig{
+		BaseURLs:  []string{server.URL},
+		IgnoreSSL: common.ToPtr(true),
+	})
+	assert.True(t, result.Healthy(), "unexpected problems: %+v", result)
+}
+
+// newSignedRepo copies repomd.xml of the test repository into a temporary
+// directory and signs it with a freshly generated key, which is returned in
+// armored form
+func newSignedRepo(t *testing.T) (string, string) {
+	entity, err := openpgp.NewEntity("repocheck", "", "repocheck@example.com", nil)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "repodata"), 0755))
+
+	src, err := os.ReadDir(filepath.Join(testRepoPath, "repodata"))
+	require.NoError(t, err)
+	for _, f := range src {
+		data, err := os.ReadFile(filepath.Join(testRepoPath, "repodata", f.Name()))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "repodata", f.Name()), data, 0600))
+	}
+
+	repomd, err := os.ReadFile(filepath.Join(dir, repomdPath))
+	require.NoError(t, err)
+
+	var signature bytes.Buffer
+	require.NoError(t, openpgp.ArmoredDetachSign(&signature, entity, bytes.NewReader(repomd), nil))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, repomdAscPath), signature.Bytes(), 0600))
+
+	var key bytes.Buffer
+	w, err := armor.Encode(&key, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.Serialize(w))
+	require.NoError(t, w.Close())
+
+	return dir, key.String()
+}
+
+func TestCheckGPG(t *testing.T) {
+	dir, key := newSignedRepo(t)
+	server := httptest.NewServer(http.FileServer(http.Dir(dir)))
+	defer server.Close()
+
+	otherDir, otherKey := newSignedRepo(t)
+	otherServer := httptest.NewServer(http.FileServer(http.Dir(otherDir)))
+	defer otherServer.Close()
+
+	keyServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, key)
+	}))
+	defer keyServer.Close()
+
+	tests := []struct {
+		name     string
+		repo     rpmmd.RepoConfig
+		problems []string
+	}{
+		{
+			name: "valid inline key",
+			repo: rpmmd.RepoConfig{
+				BaseURLs:     []string{server.URL},
+				CheckGPG:     common.ToPtr(true),
+				CheckRepoGPG: common.ToPtr(true),
+				GPGKeys:      []string{key},
+			},
+		},
+		{
+			name: "valid key url",
+			repo: rpmmd.RepoConfig{
+				BaseURLs:     []string{server.URL},
+				CheckRepoGPG: common.ToPtr(true),
+				GPGKeys:      []string{keyServer.URL},
+			},
+		},
+		{
+			name: "missing keys",
+			repo: rpmmd.RepoConfig{
+				BaseURLs: []string{server.URL},
+				CheckGPG: common.ToPtr(true),
+			},
+			problems: []string{"GPG checks are enabled but no gpgkeys are configured"},
+		},
+		{
+			name: "wrong key",
+			repo: rpmmd.RepoConfig{
+				BaseURLs:     []string{server.URL},
+				CheckRepoGPG: common.ToPtr(true),
+				GPGKeys:      []string{otherKey},
+			},
+			problems: []string{"repomd.xml signature is not valid"},
+		},
+		{
+			name: "invalid key",
+			repo: rpmmd.RepoConfig{
+				BaseURLs: []string{server.URL},
+				GPGKeys:  []string{"-----BEGIN PGP PUBLIC KEY BLOCK-----\n\nnot a key\n-----END PGP PUBLIC KEY BLOCK-----"},
+			},
+			problems: []string{"invalid gpgkey (inline)"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result := NewChecker().Check(context.Background(), tc.repo)
+			require.Empty(t, result.Error)
+			require.Len(t, result.GPGErrors, len(tc.problems))
+			for i := range tc.problems {
+				assert.Contains(t, result.GPGErrors[i], tc.problems[i])
+			}
+		})
+	}
+}
+
+func TestParseMetalink(t *testing.T) {
+	metalink := `<?xml version="1.0" encoding="utf-8"?>
+<metalink version="3.0" xmlns="http://www.metalinker.org/">
+ <files>
+  <file name="repomd.xml">
+   <resources maxconnections="1">
+    <url protocol="https" type="https">https://mirror.example.com/fedora/42/x86_64/os/repodata/repomd.xml</url>
+   </resources>
+  </file>
+ </files>
+</metalink>`
+
+	baseURL, err := parseMetalink([]byte(metalink))
+	require.NoError(t, err)
+	assert.Equal(t, "https://mirror.example.com/fedora/42/x86_64/os/", baseURL)
+
+	_, err = parseMetalink([]byte(`<metalink><files></files></metalink>`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,93 @@
+package secrets
+
+import (
+	"os"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/osbuild/osbuild-composer/internal/jsondb"
+)
+
+// Backend keeps the encrypted secrets of a vault. Get and Delete return
+// ErrNotFound for unknown secrets.
+type Backend interface {
+	Put(id uuid.UUID, data []byte) error
+	Get(id uuid.UUID) ([]byte, error)
+	Delete(id uuid.UUID) error
+}
+
+type memoryBackend struct {
+	mu      sync.Mutex
+	secrets map[uuid.UUID][]byte
+}
+
+// NewMemoryBackend returns a backend which keeps the secrets in memory only
+func NewMemoryBackend() Backend {
+	return &memoryBackend{
+		secrets: make(map[uuid.UUID][]byte),
+	}
+}
+
+func (b *memoryBackend) Put(id uuid.UUID, data []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.secrets[id] = append([]byte{}, data...)
+	return nil
+}
+
+func (b *memoryBackend) Get(id uuid.UUID) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	data, ok := b.secrets[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return data, nil
+}
+
+func (b *memoryBackend) Delete(id uuid.UUID) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.secrets[id]; !ok {
+		return ErrNotFound
+	}
+	delete(b.secrets, id)
+	return nil
+}
+
+type fileBackend struct {
+	db *jsondb.JSONDatabase
+}
+
+// NewFileBackend returns a backend which keeps every secret in a file of its
+// own in dir, only readable by the owner
+func NewFileBackend(dir string) Backend {
+	return &fileBackend{
+		db: jsondb.New(dir, 0600),
+	}
+}
+
+func (b *fileBackend) Put(id uuid.UUID, data []byte) error {
+	return b.db.Write(id.String(), data)
+}
+
+func (b *fileBackend) Get(id uuid.UUID) ([]byte, error) {
+	var data []byte
+	exists, err := b.db.Read(id.String(), &data)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrNotFound
+	}
+	return data, nil
+}
+
+func (b *fileBackend) Delete(id uuid.UUID) error {
+	err := b.db.Delete(id.String())
+	if os.IsNotExist(err) {
+		return ErrNotFound
+	}
+	return err
+}
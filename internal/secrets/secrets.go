@@ -0,0 +1,195 @@
+// Package secrets keeps the credentials users submit to composer, like the
+// passwords and access keys of upload targets, out of the job queue and the
+// weldr state. The values are encrypted with a key of the server and stored
+// in a backend, jobs only carry opaque references to them. Workers resolve
+// the references of the job they are running through the worker API.
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// KeySize is the size of the key of a vault, it's an AES-256 key
+const KeySize = 32
+
+// refPrefix marks a value as a reference to a secret
+const refPrefix = "osbuild-secret:"
+
+var ErrNotFound = errors.New("secret not found")
+
+// IsRef returns true if value is a reference to a secret
+func IsRef(value string) bool {
+	_, ok := ParseRef(value)
+	return ok
+}
+
+// ParseRef returns the ID of the secret value refers to
+func ParseRef(value string) (uuid.UUID, bool) {
+	if !strings.HasPrefix(value, refPrefix) {
+		return uuid.Nil, false
+	}
+	id, err := uuid.Parse(strings.TrimPrefix(value, refPrefix))
+	if err != nil {
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// Ref returns the reference to the secret with id
+func Ref(id uuid.UUID) string {
+	return refPrefix + id.String()
+}
+
+// sealed is a secret as it's kept in the backend
+type sealed struct {
+	Channel    string `json:"channel"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// Vault encrypts secrets and keeps them in a backend. Every secret belongs
+// to the channel of the tenant which submitted it, the channel is
+// authenticated together with the secret and it can't be opened for another
+// one.
+type Vault struct {
+	backend Backend
+	aead    cipher.AEAD
+}
+
+// NewVault returns a vault which encrypts the secrets with key and keeps
+// them in backend
+func NewVault(key []byte, backend Backend) (*Vault, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("secrets key needs to be %d bytes, got %d", KeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Vault{
+		backend: backend,
+		aead:    aead,
+	}, nil
+}
+
+// Seal encrypts value for channel and returns the reference to it
+func (v *Vault) Seal(channel string, value string) (string, error) {
+	s := sealed{
+		Channel: channel,
+		Nonce:   make([]byte, v.aead.NonceSize()),
+	}
+	_, err := rand.Read(s.Nonce)
+	if err != nil {
+		return "", fmt.Errorf("cannot generate nonce: %w", err)
+	}
+	s.Ciphertext = v.aead.Seal(nil, s.Nonce, []byte(value), []byte(channel))
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+	id := uuid.New()
+	err = v.backend.Put(id, data)
+	if err != nil {
+		return "", fmt.Errorf("cannot store secret: %w", err)
+	}
+	return Ref(id), nil
+}
+
+// Open returns the value of the secret ref refers to. It returns ErrNotFound
+// if the secret doesn't exist or belongs to another channel.
+func (v *Vault) Open(channel string, ref string) (string, error) {
+	id, ok := ParseRef(ref)
+	if !ok {
+		return "", fmt.Errorf("invalid secret reference %q", ref)
+	}
+	data, err := v.backend.Get(id)
+	if err != nil {
+		return "", err
+	}
+	var s sealed
+	err = json.Unmarshal(data, &s)
+	if err != nil {
+		return "", fmt.Errorf("cannot read secret %s: %w", id, err)
+	}
+	if s.Channel != channel {
+		return "", ErrNotFound
+	}
+	value, err := v.aead.Open(nil, s.Nonce, s.Ciphertext, []byte(s.Channel))
+	if err != nil {
+		return "", fmt.Errorf("cannot decrypt secret %s: %w", id, err)
+	}
+	return string(value), nil
+}
+
+// Delete removes the secret ref refers to, values which aren't references
+// are ignored
+func (v *Vault) Delete(ref string) error {
+	id, ok := ParseRef(ref)
+	if !ok {
+		return nil
+	}
+	err := v.backend.Delete(id)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// LoadOrCreateKey reads the key of a vault from path. If the file doesn't
+// exist, a new key is generated and written to it.
+func LoadOrCreateKey(path string) ([]byte, error) {
+	key, err := os.ReadFile(path)
+	if err == nil {
+		if len(key) != KeySize {
+			return nil, fmt.Errorf("secrets key %s needs to be %d bytes, got %d", path, KeySize, len(key))
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("cannot read secrets key: %w", err)
+	}
+
+	key = make([]byte, KeySize)
+	_, err = rand.Read(key)
+	if err != nil {
+		return nil, fmt.Errorf("cannot generate secrets key: %w", err)
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return nil, err
+	}
+	// O_EXCL: never overwrite a key another process just created, the
+	// secrets sealed with it couldn't be opened anymore
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if os.IsExist(err) {
+		return LoadOrCreateKey(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot create secrets key: %w", err)
+	}
+	_, err = f.Write(key)
+	if err == nil {
+		err = f.Close()
+	} else {
+		f.Close()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot write secrets key: %w", err)
+	}
+	return key, nil
+}
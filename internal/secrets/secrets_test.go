@@ -0,0 +1,124 @@
+package secrets
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestVault(t *testing.T, backend Backend) *Vault {
+	key, err := LoadOrCreateKey(filepath.Join(t.TempDir(), "key"))
+	require.NoError(t, err)
+	v, err := NewVault(key, backend)
+	require.NoError(t, err)
+	return v
+}
+
+func TestRef(t *testing.T) {
+	id := uuid.New()
+	ref := Ref(id)
+	assert.True(t, IsRef(ref))
+	parsed, ok := ParseRef(ref)
+	assert.True(t, ok)
+	assert.Equal(t, id, parsed)
+
+	for _, value := range []string{"", "password", "osbuild-secret:", "osbuild-secret:not-a-uuid", id.String()} {
+		assert.False(t, IsRef(value), value)
+	}
+}
+
+func TestVault(t *testing.T) {
+	backend := NewMemoryBackend()
+	v := newTestVault(t, backend)
+
+	ref, err := v.Seal("org-1", "hunter2")
+	require.NoError(t, err)
+	assert.True(t, IsRef(ref))
+
+	// the backend only sees the encrypted value
+	id, _ := ParseRef(ref)
+	data, err := backend.Get(id)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "hunter2")
+
+	value, err := v.Open("org-1", ref)
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", value)
+
+	// other tenants can't open the secret
+	_, err = v.Open("org-2", ref)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = v.Open("org-1", Ref(uuid.New()))
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = v.Open("org-1", "hunter2")
+	assert.Error(t, err)
+
+	require.NoError(t, v.Delete(ref))
+	_, err = v.Open("org-1", ref)
+	assert.ErrorIs(t, err, ErrNotFound)
+	// deleting twice or values which aren't references is fine
+	assert.NoError(t, v.Delete(ref))
+	assert.NoError(t, v.Delete("hunter2"))
+}
+
+func TestVaultWrongKey(t *testing.T) {
+	backend := NewMemoryBackend()
+	ref, err := newTestVault(t, backend).Seal("", "hunter2")
+	require.NoError(t, err)
+
+	_, err = newTestVault(t, backend).Open("", ref)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrNotFound)
+}
+
+func TestFileBackend(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(t.TempDir(), "key")
+	key, err := LoadOrCreateKey(keyFile)
+	require.NoError(t, err)
+	v, err := NewVault(key, NewFileBackend(dir))
+	require.NoError(t, err)
+
+	ref, err := v.Seal("org-1", "hunter2")
+	require.NoError(t, err)
+	id, _ := ParseRef(ref)
+	info, err := os.Stat(filepath.Join(dir, id.String()+".json"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// the key and the secrets survive a restart
+	key, err = LoadOrCreateKey(keyFile)
+	require.NoError(t, err)
+	v, err = NewVault(key, NewFileBackend(dir))
+	require.NoError(t, err)
+	value, err := v.Open("org-1", ref)
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", value)
+
+	require.NoError(t, v.Delete(ref))
+	_, err = v.Open("org-1", ref)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestLoadOrCreateKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets", "key")
+	key, err := LoadOrCreateKey(path)
+	require.NoError(t, err)
+	assert.Len(t, key, KeySize)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	again, err := LoadOrCreateKey(path)
+	require.NoError(t, err)
+	assert.Equal(t, key, again)
+
+	require.NoError(t, os.WriteFile(path, []byte("short"), 0600))
+	_, err = LoadOrCreateKey(path)
+	assert.Error(t, err)
+}
@@ -0,0 +1,357 @@
+// Package sourcebundle moves the sources of an osbuild manifest between
+// osbuild stores, so composes can be built at sites which can't reach any
+// repository or registry.
+//
+// A source bundle is a tarball with the serialized manifest, a description
+// of the image it builds and the sources of the manifest in the layout of
+// the osbuild store: the RPMs and other files, the container images and the
+// ostree repository with the commits. It is written from the store of a
+// worker which fetched the sources, and imported into the store of a worker
+// at the disconnected site. osbuild finds the sources there and doesn't try
+// to fetch them.
+package sourcebundle
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/osbuild/osbuild-composer/internal/osbuildstore"
+)
+
+const (
+	infoName     = "bundle.json"
+	manifestName = "manifest.json"
+	sourcesDir   = "sources"
+
+	// the files are kept by their checksum in this directory of the store
+	filesDir = "sources/org.osbuild.files"
+)
+
+// Info describes the image the manifest of a bundle builds
+type Info struct {
+	// the pipeline exporting the image and the name of the image file
+	ExportName     string `json:"export_name"`
+	ExportFilename string `json:"export_filename"`
+
+	// the build and payload pipelines of the manifest
+	BuildPipelines   []string `json:"build_pipelines,omitempty"`
+	PayloadPipelines []string `json:"payload_pipelines,omitempty"`
+
+	// the paths of the sources in the store
+	Sources []string `json:"sources"`
+}
+
+// Bundle is an imported source bundle
+type Bundle struct {
+	Info     Info
+	Manifest []byte
+}
+
+// Write writes a bundle with the manifest and its sources from store to w.
+// All sources of the manifest need to be in the store, run osbuild with the
+// manifest first to fetch them.
+func Write(w io.Writer, store string, manifest []byte, info Info) error {
+	sources, err := osbuildstore.ManifestSources(manifest)
+	if err != nil {
+		return err
+	}
+	for _, source := range sources {
+		if _, err := os.Stat(filepath.Join(store, source)); err != nil {
+			return fmt.Errorf("source %s is missing from the store: %w", source, err)
+		}
+	}
+	info.Sources = sources
+
+	rawInfo, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+
+	tw := tar.NewWriter(w)
+	err = writeFile(tw, infoName, rawInfo)
+	if err != nil {
+		return err
+	}
+	err = writeFile(tw, manifestName, manifest)
+	if err != nil {
+		return err
+	}
+	for _, source := range sources {
+		err = writeTree(tw, store, source)
+		if err != nil {
+			return fmt.Errorf("cannot write source %s: %w", source, err)
+		}
+	}
+	return tw.Close()
+}
+
+func writeFile(tw *tar.Writer, name string, data []byte) error {
+	err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     int64(len(data)),
+		Mode:     0644,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = tw.Write(data)
+	return err
+}
+
+// writeTree writes the file or directory tree at store/source to tw
+func writeTree(tw *tar.Writer, store, source string) error {
+	return filepath.WalkDir(filepath.Join(store, source), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(store, path)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() && !info.IsDir() {
+			return fmt.Errorf("%s is neither a regular file nor a directory", rel)
+		}
+
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+		err = tw.WriteHeader(hdr)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+}
+
+// Import reads a bundle from r and adds its sources to the store. The
+// sources are extracted next to the store first and moved into it once the
+// whole bundle was read, so osbuild never sees a partial source. Sources
+// which are in the store already are kept.
+func Import(r io.Reader, store string) (*Bundle, error) {
+	err := os.MkdirAll(store, 0755)
+	if err != nil {
+		return nil, err
+	}
+	staging, err := os.MkdirTemp(store, ".source-bundle-")
+	if err != nil {
+		return nil, fmt.Errorf("cannot create the staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	var bundle Bundle
+	var haveInfo bool
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot read source bundle: %w", err)
+		}
+
+		switch hdr.Name {
+		case infoName:
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return nil, err
+			}
+			err = json.Unmarshal(data, &bundle.Info)
+			if err != nil {
+				return nil, fmt.Errorf("cannot read %s of the source bundle: %w", infoName, err)
+			}
+			haveInfo = true
+			continue
+		case manifestName:
+			bundle.Manifest, err = io.ReadAll(tr)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		name, err := sourcePath(hdr.Name)
+		if err != nil {
+			return nil, err
+		}
+		err = extract(tr, hdr, filepath.Join(staging, name))
+		if err != nil {
+			return nil, fmt.Errorf("cannot extract %s: %w", hdr.Name, err)
+		}
+	}
+	if !haveInfo || len(bundle.Manifest) == 0 {
+		return nil, fmt.Errorf("source bundle lacks %s or %s", infoName, manifestName)
+	}
+
+	// only the sources of the manifest are added to the store
+	for _, source := range bundle.Info.Sources {
+		if _, err := sourcePath(source); err != nil {
+			return nil, err
+		}
+		if _, err := os.Stat(filepath.Join(staging, source)); err != nil {
+			return nil, fmt.Errorf("source %s is missing from the bundle", source)
+		}
+		if strings.HasPrefix(source, filesDir+"/") {
+			err = verifyFile(filepath.Join(staging, source))
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, source := range bundle.Info.Sources {
+		err = merge(filepath.Join(staging, source), filepath.Join(store, source))
+		if err != nil {
+			return nil, fmt.Errorf("cannot add %s to the store: %w", source, err)
+		}
+	}
+	return &bundle, nil
+}
+
+// ImportFile imports the bundle at path into the store
+func ImportFile(path, store string) (*Bundle, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Import(f, store)
+}
+
+// sourcePath returns the cleaned name of an entry of a bundle, it needs to
+// be below sources/
+func sourcePath(name string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(name))
+	if filepath.IsAbs(clean) || !strings.HasPrefix(clean, sourcesDir+string(filepath.Separator)) {
+		return "", fmt.Errorf("unexpected entry %q in source bundle", name)
+	}
+	return clean, nil
+}
+
+// extract writes a directory or regular file of the bundle. Links aren't
+// expected in the store and are rejected, they could point outside of it.
+func extract(tr *tar.Reader, hdr *tar.Header, path string) error {
+	switch hdr.Typeflag {
+	case tar.TypeDir:
+		return os.MkdirAll(path, 0755)
+	case tar.TypeReg:
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err != nil {
+			return err
+		}
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(f, tr)
+		if err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	default:
+		return fmt.Errorf("unsupported entry type %q", hdr.Typeflag)
+	}
+}
+
+// verifyFile checks the content of a file against the checksum it is named
+// by. Files with checksums of other algorithms are left to osbuild.
+func verifyFile(path string) error {
+	algorithm, expected, ok := strings.Cut(filepath.Base(path), ":")
+	if !ok {
+		return nil
+	}
+	var h hash.Hash
+	switch algorithm {
+	case "sha256":
+		h = sha256.New()
+	case "sha384":
+		h = sha512.New384()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(h, f)
+	if err != nil {
+		return err
+	}
+	if actual := hex.EncodeToString(h.Sum(nil)); actual != expected {
+		return fmt.Errorf("checksum of %s doesn't match its content", filepath.Base(path))
+	}
+	return nil
+}
+
+// merge moves src to dst. If dst is a directory which exists already, the
+// entries of src missing in dst are moved into it, this adds the commits of
+// a bundle to the ostree repository of the store.
+func merge(src, dst string) error {
+	dstInfo, err := os.Stat(dst)
+	if os.IsNotExist(err) {
+		err = os.MkdirAll(filepath.Dir(dst), 0755)
+		if err != nil {
+			return err
+		}
+		return os.Rename(src, dst)
+	}
+	if err != nil {
+		return err
+	}
+
+	srcInfo, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if !srcInfo.IsDir() || !dstInfo.IsDir() {
+		// sources are content addressed, the one in the store is the same
+		return nil
+	}
+
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		err = merge(filepath.Join(src, e.Name()), filepath.Join(dst, e.Name()))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
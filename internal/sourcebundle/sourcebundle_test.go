@@ -0,0 +1,171 @@
+package sourcebundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeStoreFile(t *testing.T, path string, data []byte) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, data, 0600))
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func testManifest(rpm string) []byte {
+	return []byte(fmt.Sprintf(`{
+		"version": "2",
+		"pipelines": [],
+		"sources": {
+			"org.osbuild.curl": {
+				"items": {
+					"%s": {"url": "https://example.com/a.rpm"}
+				}
+			},
+			"org.osbuild.skopeo": {
+				"items": {
+					"sha256:bbbb": {"image": {"name": "registry.example.com/fedora", "digest": "sha256:cccc"}}
+				}
+			},
+			"org.osbuild.ostree": {
+				"items": {
+					"dddd": {"remote": {"url": "https://example.com/repo"}}
+				}
+			}
+		}
+	}`, rpm))
+}
+
+func TestWriteImport(t *testing.T) {
+	rpm := []byte("rpm content")
+	manifest := testManifest(checksum(rpm))
+
+	store := t.TempDir()
+	writeStoreFile(t, filepath.Join(store, "sources/org.osbuild.files", checksum(rpm)), rpm)
+	writeStoreFile(t, filepath.Join(store, "sources/org.osbuild.containers/sha256:bbbb/manifest.json"), []byte("{}"))
+	writeStoreFile(t, filepath.Join(store, "sources/org.osbuild.ostree/repo/objects/dd/dd.commit"), []byte("commit"))
+	// not part of the manifest
+	writeStoreFile(t, filepath.Join(store, "sources/org.osbuild.files/sha256:eeee"), []byte("other"))
+
+	var buf bytes.Buffer
+	err := Write(&buf, store, manifest, Info{
+		ExportName:     "qcow2",
+		ExportFilename: "disk.qcow2",
+	})
+	require.NoError(t, err)
+
+	// the store at the disconnected site has another commit already
+	offline := t.TempDir()
+	writeStoreFile(t, filepath.Join(offline, "sources/org.osbuild.ostree/repo/objects/ff/ff.commit"), []byte("other commit"))
+
+	bundle, err := Import(&buf, offline)
+	require.NoError(t, err)
+	assert.Equal(t, manifest, bundle.Manifest)
+	assert.Equal(t, "qcow2", bundle.Info.ExportName)
+	assert.Equal(t, "disk.qcow2", bundle.Info.ExportFilename)
+	assert.Equal(t, []string{
+		"sources/org.osbuild.containers/sha256:bbbb",
+		"sources/org.osbuild.files/" + checksum(rpm),
+		"sources/org.osbuild.ostree/repo",
+	}, bundle.Info.Sources)
+
+	data, err := os.ReadFile(filepath.Join(offline, "sources/org.osbuild.files", checksum(rpm)))
+	require.NoError(t, err)
+	assert.Equal(t, rpm, data)
+	assert.FileExists(t, filepath.Join(offline, "sources/org.osbuild.containers/sha256:bbbb/manifest.json"))
+	assert.FileExists(t, filepath.Join(offline, "sources/org.osbuild.ostree/repo/objects/dd/dd.commit"))
+	assert.FileExists(t, filepath.Join(offline, "sources/org.osbuild.ostree/repo/objects/ff/ff.commit"))
+	assert.NoFileExists(t, filepath.Join(offline, "sources/org.osbuild.files/sha256:eeee"))
+
+	// the staging directory is gone
+	entries, err := os.ReadDir(offline)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestWriteMissingSource(t *testing.T) {
+	var buf bytes.Buffer
+	err := Write(&buf, t.TempDir(), testManifest("sha256:aaaa"), Info{})
+	assert.ErrorContains(t, err, "is missing from the store")
+}
+
+func writeBundle(t *testing.T, entries map[string]string) *bytes.Buffer {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for name, data := range entries {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     name,
+			Size:     int64(len(data)),
+			Mode:     0644,
+		}))
+		_, err := tw.Write([]byte(data))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	return &buf
+}
+
+func TestImportErrors(t *testing.T) {
+	for name, tc := range map[string]struct {
+		entries map[string]string
+		err     string
+	}{
+		"outside of sources": {
+			entries: map[string]string{"sources/../../etc/passwd": ""},
+			err:     `unexpected entry "sources/../../etc/passwd" in source bundle`,
+		},
+		"no manifest": {
+			entries: map[string]string{infoName: `{"sources": []}`},
+			err:     "source bundle lacks bundle.json or manifest.json",
+		},
+		"missing source": {
+			entries: map[string]string{
+				infoName:     `{"sources": ["sources/org.osbuild.files/sha256:aaaa"]}`,
+				manifestName: `{}`,
+			},
+			err: "source sources/org.osbuild.files/sha256:aaaa is missing from the bundle",
+		},
+		"corrupt file": {
+			entries: map[string]string{
+				infoName:     fmt.Sprintf(`{"sources": ["sources/org.osbuild.files/%s"]}`, checksum([]byte("rpm"))),
+				manifestName: `{}`,
+				"sources/org.osbuild.files/" + checksum([]byte("rpm")): "not the rpm",
+			},
+			err: fmt.Sprintf("checksum of %s doesn't match its content", checksum([]byte("rpm"))),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			store := t.TempDir()
+			_, err := Import(writeBundle(t, tc.entries), store)
+			assert.EqualError(t, err, tc.err)
+			assert.NoDirExists(t, filepath.Join(store, "sources"))
+		})
+	}
+}
+
+func TestImportRejectsLinks(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	require.NoError(t, tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeSymlink,
+		Name:     "sources/org.osbuild.files/sha256:aaaa",
+		Linkname: "/etc/passwd",
+	}))
+	require.NoError(t, tw.Close())
+
+	_, err := Import(&buf, t.TempDir())
+	assert.ErrorContains(t, err, "unsupported entry type")
+}
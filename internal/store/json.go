@@ -74,6 +74,8 @@ type sourceV0 struct {
 	CheckRepoGPG   bool     `json:"check_repogpg"`
 	GPGKeys        []string `json:"gpgkeys"`
 	ModuleHotfixes *bool    `json:"module_hotfixes,omitempty"`
+
+	Health *SourceHealth `json:"health,omitempty"`
 }
 
 type sourcesV0 map[string]sourceV0
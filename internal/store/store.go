@@ -69,6 +69,20 @@ type SourceConfig struct {
 	CheckRepoGPG   bool     `json:"check_repogpg" toml:"check_repogpg"`
 	GPGKeys        []string `json:"gpgkeys"`
 	ModuleHotfixes *bool    `json:"module_hotfixes,omitempty"`
+
+	// Health is the result of the last health check of the source, it is
+	// nil if the source has never been checked
+	Health *SourceHealth `json:"health,omitempty" toml:"health,omitempty"`
+}
+
+// SourceHealth holds the result of probing the metadata of a source
+type SourceHealth struct {
+	Checked      time.Time  `json:"checked" toml:"checked"`
+	Healthy      bool       `json:"healthy" toml:"healthy"`
+	URL          string     `json:"url,omitempty" toml:"url,omitempty"`
+	LastUpdated  *time.Time `json:"last_updated,omitempty" toml:"last_updated,omitempty"`
+	PackageCount int        `json:"package_count" toml:"package_count"`
+	Problems     []string   `json:"problems,omitempty" toml:"problems,omitempty"`
 }
 
 type NotFoundError struct {
@@ -402,6 +416,22 @@ func (s *Store) PushCompose(jobID uuid.UUID,
 	return nil
 }
 
+// SetComposeManifest records the manifest of a compose and the packages it
+// contains, once they have been resolved by the compose's manifest job
+func (s *Store) SetComposeManifest(id uuid.UUID, manifest manifest.OSBuildManifest, packages []weldrtypes.DepsolvedPackageInfo) error {
+	return s.change(func() error {
+		compose, exists := s.composes[id]
+		if !exists {
+			return &NotFoundError{"compose does not exist"}
+		}
+
+		compose.ImageBuild.Manifest = manifest
+		compose.Packages = packages
+		s.composes[id] = compose
+		return nil
+	})
+}
+
 // PushTestCompose is used for testing
 // Set testSuccess to create a fake successful compose, otherwise it will create a failed compose
 // It does not actually run a compose job
@@ -469,6 +499,22 @@ func (s *Store) PushSource(key string, source SourceConfig) {
 	})
 }
 
+// SetSourceHealth records the result of a health check of the source with
+// the given ID. Unknown sources, e.g. ones deleted while the check was
+// running, are ignored.
+func (s *Store) SetSourceHealth(key string, health SourceHealth) {
+	// FIXME: handle or comment this possible error
+	_ = s.change(func() error {
+		source, ok := s.sources[key]
+		if !ok {
+			return nil
+		}
+		source.Health = &health
+		s.sources[key] = source
+		return nil
+	})
+}
+
 // DeleteSourceByName removes a SourceConfig from store.Sources using the .Name field
 func (s *Store) DeleteSourceByName(name string) {
 	// FIXME: handle or comment this possible error
@@ -328,6 +328,20 @@ func (suite *storeTest) TestPushCompose() {
 	suite.NoError(err)
 }
 
+func (suite *storeTest) TestSetComposeManifest() {
+	testID := uuid.New()
+	err := suite.myStore.PushCompose(testID, nil, suite.myImageType, &suite.myBP, 123, nil, nil)
+	suite.NoError(err)
+
+	err = suite.myStore.SetComposeManifest(testID, suite.myManifest, suite.myPackages)
+	suite.NoError(err)
+	suite.Equal(suite.myManifest, suite.myStore.composes[testID].ImageBuild.Manifest)
+	suite.Equal(suite.myPackages, suite.myStore.composes[testID].Packages)
+
+	err = suite.myStore.SetComposeManifest(uuid.New(), suite.myManifest, suite.myPackages)
+	suite.Error(err)
+}
+
 func (suite *storeTest) TestPushTestCompose() {
 	ID := uuid.New()
 	err := suite.myStore.PushTestCompose(ID, suite.myManifest, suite.myImageType, &suite.myBP, 123, nil, true, []weldrtypes.DepsolvedPackageInfo{})
@@ -387,6 +401,28 @@ func (suite *storeTest) TestPushSource() {
 	suite.Equal(expectedSource, suite.myStore.sources)
 }
 
+func (suite *storeTest) TestSetSourceHealth() {
+	suite.myStore.sources = make(map[string]SourceConfig)
+	suite.myStore.sources["testSource"] = suite.mySourceConfig
+
+	health := SourceHealth{
+		Checked:      time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Healthy:      false,
+		PackageCount: 42,
+		Problems:     []string{"x509: certificate signed by unknown authority"},
+	}
+	suite.myStore.SetSourceHealth("testSource", health)
+	suite.Equal(&health, suite.myStore.sources["testSource"].Health)
+
+	// unknown sources are ignored
+	suite.myStore.SetSourceHealth("unknownSource", health)
+	suite.Len(suite.myStore.sources, 1)
+
+	// pushing a new config drops the stale health
+	suite.myStore.PushSource("testSource", suite.mySourceConfig)
+	suite.Nil(suite.myStore.sources["testSource"].Health)
+}
+
 func (suite *storeTest) TestListSourcesByName() {
 	suite.myStore.sources = make(map[string]SourceConfig)
 	suite.myStore.sources["testSource"] = suite.mySourceConfig
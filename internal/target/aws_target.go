@@ -1,5 +1,7 @@
 package target
 
+import "github.com/google/uuid"
+
 const (
 	TargetNameAWS   TargetName = "org.osbuild.aws"
 	TargetNameAWSS3 TargetName = "org.osbuild.aws.s3"
@@ -22,6 +24,10 @@ type AWSTargetOptions struct {
 	// If not provided, then the Boot mode will be determined by the default
 	// boot mode of the instance provisioned from the AMI.
 	BootMode *string `json:"bootMode,omitempty"`
+
+	// If set, the worker assumes the AWS role of these registered cloud
+	// credentials of the tenant instead of using its own credentials.
+	CloudCredentials *uuid.UUID `json:"cloudCredentials,omitempty"`
 }
 
 func (AWSTargetOptions) isTargetOptions() {}
@@ -52,6 +58,10 @@ type AWSS3TargetOptions struct {
 	CABundle            string `json:"ca_bundle"`
 	SkipSSLVerification bool   `json:"skip_ssl_verification"`
 	Public              bool   `json:"public,omitempty"`
+
+	// If set, the worker assumes the AWS role of these registered cloud
+	// credentials of the tenant instead of using its own credentials.
+	CloudCredentials *uuid.UUID `json:"cloudCredentials,omitempty"`
 }
 
 func (AWSS3TargetOptions) isTargetOptions() {}
@@ -1,5 +1,7 @@
 package target
 
+import "github.com/google/uuid"
+
 const TargetNameAzureImage TargetName = "org.osbuild.azure.image"
 
 type HyperVGenerationType string
@@ -15,6 +17,10 @@ type AzureImageTargetOptions struct {
 	SubscriptionID   string               `json:"subscription_id"`
 	ResourceGroup    string               `json:"resource_group"`
 	HyperVGeneration HyperVGenerationType `json:"hyperv_generation"`
+
+	// If set, the worker uses the service principal of these registered
+	// cloud credentials of the tenant instead of its own credentials.
+	CloudCredentials *uuid.UUID `json:"cloud_credentials,omitempty"`
 }
 
 func (AzureImageTargetOptions) isTargetOptions() {}
@@ -28,8 +34,9 @@ func (AzureImageTargetOptions) isTargetOptions() {}
 // a service principal for this purpose, see:
 // https://docs.microsoft.com/en-us/azure/active-directory/develop/app-objects-and-service-principals
 // The credentials are not passed in the target options, instead they are
-// defined in the worker. If the worker doesn't have Azure credentials
-// and gets a job with this target, the job will fail.
+// defined in the worker or registered by the tenant and referred to by
+// CloudCredentials. If the worker doesn't have Azure credentials and gets
+// a job with this target without CloudCredentials, the job will fail.
 //
 // The Tenant ID for the authorization process is specified in the target
 // options. This means that this target can be used for multi-tenant
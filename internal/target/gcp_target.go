@@ -1,6 +1,9 @@
 package target
 
-import "cloud.google.com/go/compute/apiv1/computepb"
+import (
+	"cloud.google.com/go/compute/apiv1/computepb"
+	"github.com/google/uuid"
+)
 
 const TargetNameGCP TargetName = "org.osbuild.gcp"
 
@@ -16,6 +19,10 @@ type GCPTargetOptions struct {
 	// credentials from worker's configuration.
 	Credentials []byte `json:"credentials,omitempty"`
 
+	// If set, the worker impersonates the service account of these
+	// registered cloud credentials of the tenant.
+	CloudCredentials *uuid.UUID `json:"cloudCredentials,omitempty"`
+
 	// The list of Guest OS Features to specify for the image being imported.
 	GuestOsFeatures []*computepb.GuestOsFeature `json:"guestOsFeatures,omitempty"`
 }
@@ -0,0 +1,86 @@
+package target
+
+import (
+	"github.com/osbuild/osbuild-composer/internal/secrets"
+)
+
+// secretFields returns the fields of options which hold secrets, like
+// passwords and access keys
+func secretFields(options TargetOptions) []*string {
+	switch o := options.(type) {
+	case *AWSTargetOptions:
+		return []*string{&o.SecretAccessKey, &o.SessionToken}
+	case *AWSS3TargetOptions:
+		return []*string{&o.SecretAccessKey, &o.SessionToken}
+	case *AzureTargetOptions:
+		return []*string{&o.StorageAccessKey}
+	case *ContainerTargetOptions:
+		return []*string{&o.Password}
+	case *VMWareTargetOptions:
+		return []*string{&o.Password}
+	case *OCITargetOptions:
+		return []*string{&o.PrivateKey}
+	case *OCIObjectStorageTargetOptions:
+		return []*string{&o.PrivateKey}
+	}
+	return nil
+}
+
+// mapSecrets replaces every non-empty secret of t with the value f returns
+// for it
+func mapSecrets(t *Target, f func(string) (string, error)) error {
+	for _, field := range secretFields(t.Options) {
+		if *field == "" {
+			continue
+		}
+		value, err := f(*field)
+		if err != nil {
+			return err
+		}
+		*field = value
+	}
+
+	// the GCP credentials are a whole credentials file
+	if o, ok := t.Options.(*GCPTargetOptions); ok && len(o.Credentials) > 0 {
+		value, err := f(string(o.Credentials))
+		if err != nil {
+			return err
+		}
+		o.Credentials = []byte(value)
+	}
+	return nil
+}
+
+// SealSecrets replaces the secrets in the options of t with the references
+// seal returns for them. Secrets which already are references are kept.
+func SealSecrets(t *Target, seal func(value string) (string, error)) error {
+	return mapSecrets(t, func(value string) (string, error) {
+		if secrets.IsRef(value) {
+			return value, nil
+		}
+		return seal(value)
+	})
+}
+
+// OpenSecrets replaces the secret references in the options of t with the
+// values open returns for them
+func OpenSecrets(t *Target, open func(ref string) (string, error)) error {
+	return mapSecrets(t, func(value string) (string, error) {
+		if !secrets.IsRef(value) {
+			return value, nil
+		}
+		return open(value)
+	})
+}
+
+// SecretRefs returns the secret references in the options of t
+func SecretRefs(t *Target) []string {
+	var refs []string
+	_ = mapSecrets(t, func(value string) (string, error) {
+		if secrets.IsRef(value) {
+			refs = append(refs, value)
+		}
+		return value, nil
+	})
+	return refs
+}
@@ -0,0 +1,67 @@
+package target
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/secrets"
+)
+
+func TestSealAndOpenSecrets(t *testing.T) {
+	vault, err := secrets.NewVault(make([]byte, secrets.KeySize), secrets.NewMemoryBackend())
+	require.NoError(t, err)
+	seal := func(value string) (string, error) {
+		return vault.Seal("org-1", value)
+	}
+	open := func(ref string) (string, error) {
+		return vault.Open("org-1", ref)
+	}
+
+	aws := NewAWSTarget(&AWSTargetOptions{
+		Region:          "eu-central-1",
+		AccessKeyID:     "id",
+		SecretAccessKey: "secret",
+	})
+	gcp := NewGCPTarget(&GCPTargetOptions{
+		Credentials: []byte(`{"type": "service_account"}`),
+	})
+	container := NewContainerTarget(&ContainerTargetOptions{
+		Username: "user",
+	})
+	local := NewWorkerServerTarget()
+
+	for _, target := range []*Target{aws, gcp, container, local} {
+		require.NoError(t, SealSecrets(target, seal))
+	}
+
+	awsOptions := aws.Options.(*AWSTargetOptions)
+	assert.True(t, secrets.IsRef(awsOptions.SecretAccessKey))
+	assert.Equal(t, "id", awsOptions.AccessKeyID)
+	// empty secrets are kept empty
+	assert.Empty(t, awsOptions.SessionToken)
+	assert.True(t, secrets.IsRef(string(gcp.Options.(*GCPTargetOptions).Credentials)))
+	assert.Empty(t, container.Options.(*ContainerTargetOptions).Password)
+
+	assert.Equal(t, []string{awsOptions.SecretAccessKey}, SecretRefs(aws))
+	assert.Empty(t, SecretRefs(container))
+	assert.Empty(t, SecretRefs(local))
+
+	// sealing twice keeps the references
+	ref := awsOptions.SecretAccessKey
+	require.NoError(t, SealSecrets(aws, seal))
+	assert.Equal(t, ref, awsOptions.SecretAccessKey)
+
+	require.NoError(t, OpenSecrets(aws, open))
+	assert.Equal(t, "secret", awsOptions.SecretAccessKey)
+	require.NoError(t, OpenSecrets(gcp, open))
+	assert.Equal(t, `{"type": "service_account"}`, string(gcp.Options.(*GCPTargetOptions).Credentials))
+
+	vmware := NewVMWareTarget(&VMWareTargetOptions{Password: ref})
+	err = OpenSecrets(vmware, func(string) (string, error) {
+		return "", fmt.Errorf("not allowed")
+	})
+	assert.EqualError(t, err, "not allowed")
+}
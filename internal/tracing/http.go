@@ -0,0 +1,81 @@
+package tracing
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/osbuild/osbuild-composer/internal/common"
+)
+
+// Middleware starts a server span for every request to an echo API,
+// continuing the trace of the caller if it sent a trace context. The span
+// is named after the method and the route of the request.
+func Middleware(api string) echo.MiddlewareFunc {
+	return middleware(api, false)
+}
+
+// ContinueMiddleware starts server spans like Middleware, but only for
+// requests which continue a trace. It's meant for APIs which are polled,
+// like the worker API, whose requests would otherwise each start a trace.
+func ContinueMiddleware(api string) echo.MiddlewareFunc {
+	return middleware(api, true)
+}
+
+func middleware(api string, continueOnly bool) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			request := c.Request()
+			ctx := propagator.Extract(request.Context(), propagation.HeaderCarrier(request.Header))
+			if continueOnly && !trace.SpanContextFromContext(ctx).IsValid() {
+				return next(c)
+			}
+			ctx, span := otel.Tracer(instrumentationName).Start(ctx, request.Method+" "+c.Path(),
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					attribute.String("composer.api", api),
+					semconv.HTTPRequestMethodKey.String(request.Method),
+					semconv.HTTPRoute(c.Path()),
+					semconv.URLPath(request.URL.Path),
+				))
+			defer span.End()
+			if operationID, ok := c.Get(common.OperationIDKey).(string); ok {
+				span.SetAttributes(attribute.String("composer.operation_id", operationID))
+			}
+			c.SetRequest(request.WithContext(ctx))
+
+			err := next(c)
+			status := c.Response().Status
+			if err != nil {
+				// the response is written by the error handler later
+				status = http.StatusInternalServerError
+				var httpError *echo.HTTPError
+				if errors.As(err, &httpError) {
+					status = httpError.Code
+				}
+			}
+			span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+			if status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(status))
+			}
+			return err
+		}
+	}
+}
+
+// Transport wraps an http transport to start a client span for every
+// request made as part of a trace and send the trace context along with it.
+// Requests outside of a trace, e.g. polling for jobs, aren't traced.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	return otelhttp.NewTransport(base, otelhttp.WithFilter(func(request *http.Request) bool {
+		return trace.SpanContextFromContext(request.Context()).IsValid()
+	}))
+}
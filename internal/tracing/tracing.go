@@ -0,0 +1,184 @@
+// Package tracing instruments composer and the workers with OpenTelemetry.
+//
+// Spans are exported via OTLP over HTTP if an endpoint is configured,
+// otherwise the global no-op tracer provider is kept and starting a span
+// costs next to nothing. A compose is a single trace: the trace context of
+// the request which created it is stored in the arguments of its jobs, from
+// where the server and the workers pick it up again.
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/osbuild/osbuild-composer"
+
+// ArgsField is the field of the job arguments holding the trace context of
+// the job
+const ArgsField = "trace_context"
+
+var propagator = propagation.TraceContext{}
+
+// Config of the exporter
+type Config struct {
+	// Endpoint is the URL the spans are sent to, e.g.
+	// "https://collector.example.com:4318/v1/traces". Tracing is disabled
+	// if it's empty.
+	Endpoint string
+	// Headers are sent with every export, e.g. for authentication
+	Headers map[string]string
+	// SampleRatio is the fraction of new traces which are recorded,
+	// traces continued from a caller follow its sampling decision
+	SampleRatio float64
+}
+
+// Init installs a tracer provider exporting the spans of service to the
+// configured endpoint. The returned function flushes the remaining spans
+// and must be called before exiting.
+func Init(service string, config Config) (func(context.Context) error, error) {
+	if config.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tracing endpoint: %w", err)
+	}
+	if (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid tracing endpoint %q: expected an http or https URL", config.Endpoint)
+	}
+	if config.SampleRatio < 0 || config.SampleRatio > 1 {
+		return nil, fmt.Errorf("invalid tracing sample ratio %v: expected a value between 0 and 1", config.SampleRatio)
+	}
+
+	options := []otlptracehttp.Option{
+		otlptracehttp.WithEndpointURL(config.Endpoint),
+	}
+	if len(config.Headers) > 0 {
+		options = append(options, otlptracehttp.WithHeaders(config.Headers))
+	}
+	exporter, err := otlptracehttp.New(context.Background(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create trace exporter: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(service))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagator)
+
+	return provider.Shutdown, nil
+}
+
+// Start starts a span, which is a child of the span in ctx, if there is one
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// StartAt starts a span like Start, which began at start
+func StartAt(ctx context.Context, name string, start time.Time, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...), trace.WithTimestamp(start))
+}
+
+// End ends span, marking it as failed if err isn't nil
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// EndAt ends span like End, which finished at end
+func EndAt(span trace.Span, err error, end time.Time) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End(trace.WithTimestamp(end))
+}
+
+// SetAttributes adds attributes to the span in ctx, e.g. the ID of the
+// compose a request created
+func SetAttributes(ctx context.Context, attrs ...attribute.KeyValue) {
+	trace.SpanFromContext(ctx).SetAttributes(attrs...)
+}
+
+// Inject returns the trace context of the span in ctx, or nil if ctx isn't
+// part of a trace
+func Inject(ctx context.Context) map[string]string {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return nil
+	}
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+	return carrier
+}
+
+// Extract returns a copy of ctx which continues the trace of carrier
+func Extract(ctx context.Context, carrier map[string]string) context.Context {
+	if len(carrier) == 0 {
+		return ctx
+	}
+	return propagator.Extract(ctx, propagation.MapCarrier(carrier))
+}
+
+// InjectArgs adds the trace context of ctx to the arguments of a job, which
+// must be a JSON object. The arguments are returned unchanged if ctx isn't
+// part of a trace.
+func InjectArgs(ctx context.Context, args interface{}) (interface{}, error) {
+	carrier := Inject(ctx)
+	if carrier == nil {
+		return args, nil
+	}
+
+	data, err := json.Marshal(args)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	err = json.Unmarshal(data, &fields)
+	if err != nil {
+		return nil, fmt.Errorf("cannot add trace context to job arguments: %w", err)
+	}
+	if fields == nil {
+		return nil, errors.New("cannot add trace context to job arguments: arguments are null")
+	}
+	fields[ArgsField], err = json.Marshal(carrier)
+	if err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// ExtractArgs returns a copy of ctx which continues the trace stored in the
+// arguments of a job. ctx is returned if the arguments have no trace
+// context.
+func ExtractArgs(ctx context.Context, args json.RawMessage) context.Context {
+	var fields struct {
+		TraceContext map[string]string `json:"trace_context"`
+	}
+	// arguments which aren't an object can't have a trace context
+	if json.Unmarshal(args, &fields) != nil {
+		return ctx
+	}
+	return Extract(ctx, fields.TraceContext)
+}
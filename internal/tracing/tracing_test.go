@@ -0,0 +1,146 @@
+package tracing_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/osbuild/osbuild-composer/internal/tracing"
+)
+
+// recorder keeps the ended spans in memory
+type recorder struct {
+	mu    sync.Mutex
+	spans []sdktrace.ReadOnlySpan
+}
+
+func (r *recorder) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+func (r *recorder) Shutdown(context.Context) error                  { return nil }
+func (r *recorder) ForceFlush(context.Context) error                { return nil }
+
+func (r *recorder) OnEnd(span sdktrace.ReadOnlySpan) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans = append(r.spans, span)
+}
+
+func (r *recorder) ended() []sdktrace.ReadOnlySpan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]sdktrace.ReadOnlySpan{}, r.spans...)
+}
+
+func setupRecorder(t *testing.T) *recorder {
+	r := &recorder{}
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(r))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+	})
+	return r
+}
+
+func TestInit(t *testing.T) {
+	shutdown, err := tracing.Init("test", tracing.Config{})
+	require.NoError(t, err)
+	require.NoError(t, shutdown(context.Background()))
+
+	_, err = tracing.Init("test", tracing.Config{Endpoint: "collector:4318"})
+	require.Error(t, err)
+
+	_, err = tracing.Init("test", tracing.Config{Endpoint: "http://collector:4318/v1/traces", SampleRatio: 1.5})
+	require.Error(t, err)
+}
+
+func TestArgs(t *testing.T) {
+	setupRecorder(t)
+
+	args := struct {
+		Manifest string `json:"manifest"`
+	}{
+		Manifest: "{}",
+	}
+
+	// outside of a trace the arguments are kept as they are
+	result, err := tracing.InjectArgs(context.Background(), args)
+	require.NoError(t, err)
+	assert.Equal(t, args, result)
+
+	ctx, span := tracing.Start(context.Background(), "compose")
+	defer span.End()
+	result, err = tracing.InjectArgs(ctx, args)
+	require.NoError(t, err)
+	data, err := json.Marshal(result)
+	require.NoError(t, err)
+
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &fields))
+	assert.Equal(t, "{}", fields["manifest"])
+	assert.Contains(t, fields, tracing.ArgsField)
+
+	jobCtx := tracing.ExtractArgs(context.Background(), data)
+	assert.Equal(t, span.SpanContext().TraceID(), trace.SpanContextFromContext(jobCtx).TraceID())
+	assert.Equal(t, span.SpanContext().SpanID(), trace.SpanContextFromContext(jobCtx).SpanID())
+
+	// arguments without a trace context don't continue a trace
+	jobCtx = tracing.ExtractArgs(context.Background(), json.RawMessage(`{"manifest": "{}"}`))
+	assert.False(t, trace.SpanContextFromContext(jobCtx).IsValid())
+	jobCtx = tracing.ExtractArgs(context.Background(), json.RawMessage(`null`))
+	assert.False(t, trace.SpanContextFromContext(jobCtx).IsValid())
+
+	_, err = tracing.InjectArgs(ctx, nil)
+	require.Error(t, err)
+}
+
+func TestMiddleware(t *testing.T) {
+	r := setupRecorder(t)
+
+	e := echo.New()
+	e.GET("/api/composes/:id", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}, tracing.Middleware("cloudapi"))
+	e.GET("/api/jobs", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}, tracing.ContinueMiddleware("worker"))
+
+	parentCtx, parent := tracing.Start(context.Background(), "client")
+	parent.End()
+
+	// a request continuing a trace
+	request := httptest.NewRequest(http.MethodGet, "/api/composes/42", nil)
+	for key, value := range tracing.Inject(parentCtx) {
+		request.Header.Set(key, value)
+	}
+	e.ServeHTTP(httptest.NewRecorder(), request)
+
+	// polling the worker API outside of a trace isn't traced
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/jobs", nil))
+
+	request = httptest.NewRequest(http.MethodGet, "/api/jobs", nil)
+	for key, value := range tracing.Inject(parentCtx) {
+		request.Header.Set(key, value)
+	}
+	e.ServeHTTP(httptest.NewRecorder(), request)
+
+	spans := r.ended()
+	require.Len(t, spans, 3)
+
+	assert.Equal(t, "GET /api/composes/:id", spans[1].Name())
+	assert.Equal(t, trace.SpanKindServer, spans[1].SpanKind())
+	assert.Equal(t, parent.SpanContext().TraceID(), spans[1].SpanContext().TraceID())
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[1].Parent().SpanID())
+
+	assert.Equal(t, "GET /api/jobs", spans[2].Name())
+	assert.Equal(t, "Error", spans[2].Status().Code.String())
+}
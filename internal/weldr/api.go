@@ -22,6 +22,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/BurntSushi/toml"
@@ -38,13 +39,18 @@ import (
 	"github.com/osbuild/image-builder/pkg/distro"
 	"github.com/osbuild/image-builder/pkg/distrofactory"
 	"github.com/osbuild/image-builder/pkg/distroidparser"
+	"github.com/osbuild/image-builder/pkg/manifest"
 	"github.com/osbuild/image-builder/pkg/osbuild"
 	"github.com/osbuild/image-builder/pkg/ostree"
 	"github.com/osbuild/image-builder/pkg/reporegistry"
 	"github.com/osbuild/image-builder/pkg/rhsm/facts"
 	"github.com/osbuild/image-builder/pkg/rpmmd"
 	"github.com/osbuild/image-builder/pkg/sbom"
+	"github.com/osbuild/osbuild-composer/internal/audit"
 	"github.com/osbuild/osbuild-composer/internal/common"
+	"github.com/osbuild/osbuild-composer/internal/composediff"
+	"github.com/osbuild/osbuild-composer/internal/lockfile"
+	"github.com/osbuild/osbuild-composer/internal/manifestjob"
 	"github.com/osbuild/osbuild-composer/internal/store"
 	"github.com/osbuild/osbuild-composer/internal/target"
 	"github.com/osbuild/osbuild-composer/internal/weldrtypes"
@@ -81,6 +87,14 @@ type API struct {
 
 	//  List of ImageType names, which should not be exposed by the API
 	distrosImageTypeDenylist map[string][]string
+
+	// audit records the calls of mutating routes, nil disables it
+	audit *audit.Log
+
+	// goroutines serializing the manifests of composes
+	goroutinesCtx       context.Context
+	goroutinesCtxCancel context.CancelFunc
+	goroutinesGroup     sync.WaitGroup
 }
 
 type ComposeState int
@@ -221,81 +235,95 @@ func New(rr *reporegistry.RepoRegistry, stateDir string, solver *depsolvednf.Bas
 }
 
 func setupRouter(api *API) *API {
+	api.goroutinesCtx, api.goroutinesCtxCancel = context.WithCancel(context.Background())
+
 	api.router = httprouter.New()
 	api.router.RedirectTrailingSlash = false
 	api.router.RedirectFixedPath = false
 	api.router.MethodNotAllowed = http.HandlerFunc(methodNotAllowedHandler)
 	api.router.NotFound = http.HandlerFunc(notFoundHandler)
 
-	api.router.GET("/api/status", api.statusHandler)
-	api.router.GET("/api/v:version/projects/source/list", api.sourceListHandler)
-	api.router.GET("/api/v:version/projects/source/info/", api.sourceEmptyInfoHandler)
-	api.router.GET("/api/v:version/projects/source/info/:sources", api.sourceInfoHandler)
-	api.router.POST("/api/v:version/projects/source/new", api.sourceNewHandler)
-	api.router.DELETE("/api/v:version/projects/source/delete/*source", api.sourceDeleteHandler)
+	// Routes are restricted to a minimum role, which only applies to
+	// requests coming in through a RoleMapping (see auth.go)
 
-	api.router.GET("/api/v:version/projects/depsolve", api.projectsDepsolveHandler)
-	api.router.GET("/api/v:version/projects/depsolve/*projects", api.projectsDepsolveHandler)
+	api.router.GET("/api/status", requireRole(RoleReadOnly, api.statusHandler))
+	api.router.GET("/api/v:version/projects/source/list", requireRole(RoleReadOnly, api.sourceListHandler))
+	api.router.GET("/api/v:version/projects/source/info/", requireRole(RoleReadOnly, api.sourceEmptyInfoHandler))
+	api.router.GET("/api/v:version/projects/source/info/:sources", requireRole(RoleReadOnly, api.sourceInfoHandler))
+	api.router.POST("/api/v:version/projects/source/new", requireRole(RoleAdmin, api.sourceNewHandler))
+	api.router.DELETE("/api/v:version/projects/source/delete/*source", requireRole(RoleAdmin, api.sourceDeleteHandler))
+	api.router.GET("/api/v:version/projects/source/check/:sources", requireRole(RoleComposer, api.sourceCheckHandler))
 
-	api.router.GET("/api/v:version/modules/list", api.modulesListHandler)
-	api.router.GET("/api/v:version/modules/list/*modules", api.modulesListHandler)
-	api.router.GET("/api/v:version/projects/list", api.projectsListHandler)
-	api.router.GET("/api/v:version/projects/list/", api.projectsListHandler)
+	api.router.GET("/api/v:version/projects/depsolve", requireRole(RoleReadOnly, api.projectsDepsolveHandler))
+	api.router.GET("/api/v:version/projects/depsolve/*projects", requireRole(RoleReadOnly, api.projectsDepsolveHandler))
+
+	api.router.GET("/api/v:version/modules/list", requireRole(RoleReadOnly, api.modulesListHandler))
+	api.router.GET("/api/v:version/modules/list/*modules", requireRole(RoleReadOnly, api.modulesListHandler))
+	api.router.GET("/api/v:version/projects/list", requireRole(RoleReadOnly, api.projectsListHandler))
+	api.router.GET("/api/v:version/projects/list/", requireRole(RoleReadOnly, api.projectsListHandler))
 
 	// these are the same, except that modules/info also includes dependencies
-	api.router.GET("/api/v:version/modules/info", api.modulesInfoHandler)
-	api.router.GET("/api/v:version/modules/info/*modules", api.modulesInfoHandler)
-	api.router.GET("/api/v:version/projects/info", api.modulesInfoHandler)
-	api.router.GET("/api/v:version/projects/info/*modules", api.modulesInfoHandler)
-
-	api.router.GET("/api/v:version/blueprints/list", api.blueprintsListHandler)
-	api.router.GET("/api/v:version/blueprints/info/*blueprints", api.blueprintsInfoHandler)
-	api.router.GET("/api/v:version/blueprints/depsolve/*blueprints", api.blueprintsDepsolveHandler)
-	api.router.GET("/api/v:version/blueprints/freeze/*blueprints", api.blueprintsFreezeHandler)
-	api.router.GET("/api/v:version/blueprints/diff/:blueprint/:from/:to", api.blueprintsDiffHandler)
-	api.router.GET("/api/v:version/blueprints/change/:blueprint/:commit", api.blueprintsChangeHandler)
-	api.router.GET("/api/v:version/blueprints/changes/*blueprints", api.blueprintsChangesHandler)
-	api.router.POST("/api/v:version/blueprints/new", api.blueprintsNewHandler)
-	api.router.POST("/api/v:version/blueprints/workspace", api.blueprintsWorkspaceHandler)
-	api.router.POST("/api/v:version/blueprints/undo/:blueprint/:commit", api.blueprintUndoHandler)
-	api.router.POST("/api/v:version/blueprints/tag/:blueprint", api.blueprintsTagHandler)
-	api.router.DELETE("/api/v:version/blueprints/delete/:blueprint", api.blueprintDeleteHandler)
-	api.router.DELETE("/api/v:version/blueprints/workspace/:blueprint", api.blueprintDeleteWorkspaceHandler)
-
-	api.router.POST("/api/v:version/compose", api.composeHandler)
-	api.router.DELETE("/api/v:version/compose/delete/:uuids", api.composeDeleteHandler)
-	api.router.GET("/api/v:version/compose/types", api.composeTypesHandler)
-	api.router.GET("/api/v:version/compose/queue", api.composeQueueHandler)
-	api.router.GET("/api/v:version/compose/status/:uuids", api.composeStatusHandler)
-	api.router.GET("/api/v:version/compose/info/:uuid", api.composeInfoHandler)
-	api.router.GET("/api/v:version/compose/finished", api.composeFinishedHandler)
-	api.router.GET("/api/v:version/compose/failed", api.composeFailedHandler)
-	api.router.GET("/api/v:version/compose/image/:uuid", api.composeImageHandler)
-	api.router.GET("/api/v:version/compose/metadata/:uuid", api.composeMetadataHandler)
-	api.router.GET("/api/v:version/compose/results/:uuid", api.composeResultsHandler)
-	api.router.GET("/api/v:version/compose/logs/:uuid", api.composeLogsHandler)
-	api.router.GET("/api/v:version/compose/log/:uuid", api.composeLogHandler)
-	api.router.POST("/api/v:version/compose/uploads/schedule/:uuid", api.uploadsScheduleHandler)
-	api.router.DELETE("/api/v:version/compose/cancel/:uuid", api.composeCancelHandler)
-
-	api.router.DELETE("/api/v:version/upload/delete/:uuid", api.uploadsDeleteHandler)
-	api.router.GET("/api/v:version/upload/info/:uuid", api.uploadsInfoHandler)
-	api.router.GET("/api/v:version/upload/log/:uuid", api.uploadsLogHandler)
-	api.router.POST("/api/v:version/upload/reset/:uuid", api.uploadsResetHandler)
-	api.router.DELETE("/api/v:version/upload/cancel/:uuid", api.uploadsCancelHandler)
-
-	api.router.GET("/api/v:version/upload/providers", api.providersHandler)
-	api.router.POST("/api/v:version/upload/providers/save", api.providersSaveHandler)
-	api.router.DELETE("/api/v:version/upload/providers/delete/:provider/:profile", api.providersDeleteHandler)
-
-	api.router.GET("/api/v:version/distros/list", api.distrosListHandler)
+	api.router.GET("/api/v:version/modules/info", requireRole(RoleReadOnly, api.modulesInfoHandler))
+	api.router.GET("/api/v:version/modules/info/*modules", requireRole(RoleReadOnly, api.modulesInfoHandler))
+	api.router.GET("/api/v:version/projects/info", requireRole(RoleReadOnly, api.modulesInfoHandler))
+	api.router.GET("/api/v:version/projects/info/*modules", requireRole(RoleReadOnly, api.modulesInfoHandler))
+
+	api.router.GET("/api/v:version/blueprints/list", requireRole(RoleReadOnly, api.blueprintsListHandler))
+	api.router.GET("/api/v:version/blueprints/info/*blueprints", requireRole(RoleReadOnly, api.blueprintsInfoHandler))
+	api.router.GET("/api/v:version/blueprints/depsolve/*blueprints", requireRole(RoleReadOnly, api.blueprintsDepsolveHandler))
+	api.router.GET("/api/v:version/blueprints/freeze/*blueprints", requireRole(RoleReadOnly, api.blueprintsFreezeHandler))
+	api.router.GET("/api/v:version/blueprints/diff/:blueprint/:from/:to", requireRole(RoleReadOnly, api.blueprintsDiffHandler))
+	api.router.GET("/api/v:version/blueprints/change/:blueprint/:commit", requireRole(RoleReadOnly, api.blueprintsChangeHandler))
+	api.router.GET("/api/v:version/blueprints/changes/*blueprints", requireRole(RoleReadOnly, api.blueprintsChangesHandler))
+	api.router.POST("/api/v:version/blueprints/new", requireRole(RoleComposer, api.blueprintsNewHandler))
+	api.router.POST("/api/v:version/blueprints/workspace", requireRole(RoleComposer, api.blueprintsWorkspaceHandler))
+	api.router.POST("/api/v:version/blueprints/undo/:blueprint/:commit", requireRole(RoleComposer, api.blueprintUndoHandler))
+	api.router.POST("/api/v:version/blueprints/tag/:blueprint", requireRole(RoleComposer, api.blueprintsTagHandler))
+	api.router.DELETE("/api/v:version/blueprints/delete/:blueprint", requireRole(RoleComposer, api.blueprintDeleteHandler))
+	api.router.DELETE("/api/v:version/blueprints/workspace/:blueprint", requireRole(RoleComposer, api.blueprintDeleteWorkspaceHandler))
+
+	api.router.POST("/api/v:version/compose", requireRole(RoleComposer, api.composeHandler))
+	api.router.DELETE("/api/v:version/compose/delete/:uuids", requireRole(RoleComposer, api.composeDeleteHandler))
+	api.router.GET("/api/v:version/compose/types", requireRole(RoleReadOnly, api.composeTypesHandler))
+	api.router.GET("/api/v:version/compose/queue", requireRole(RoleReadOnly, api.composeQueueHandler))
+	api.router.GET("/api/v:version/compose/status/:uuids", requireRole(RoleReadOnly, api.composeStatusHandler))
+	api.router.GET("/api/v:version/compose/info/:uuid", requireRole(RoleReadOnly, api.composeInfoHandler))
+	api.router.GET("/api/v:version/compose/finished", requireRole(RoleReadOnly, api.composeFinishedHandler))
+	api.router.GET("/api/v:version/compose/failed", requireRole(RoleReadOnly, api.composeFailedHandler))
+	api.router.GET("/api/v:version/compose/image/:uuid", requireRole(RoleReadOnly, api.composeImageHandler))
+	api.router.GET("/api/v:version/compose/metadata/:uuid", requireRole(RoleReadOnly, api.composeMetadataHandler))
+	api.router.GET("/api/v:version/compose/lockfile/:uuid", requireRole(RoleReadOnly, api.composeLockfileHandler))
+	api.router.GET("/api/v:version/compose/diff/:from/:to", requireRole(RoleReadOnly, api.composeDiffHandler))
+	api.router.GET("/api/v:version/compose/results/:uuid", requireRole(RoleReadOnly, api.composeResultsHandler))
+	api.router.GET("/api/v:version/compose/logs/:uuid", requireRole(RoleReadOnly, api.composeLogsHandler))
+	api.router.GET("/api/v:version/compose/log/:uuid", requireRole(RoleReadOnly, api.composeLogHandler))
+	api.router.POST("/api/v:version/compose/uploads/schedule/:uuid", requireRole(RoleComposer, api.uploadsScheduleHandler))
+	api.router.DELETE("/api/v:version/compose/cancel/:uuid", requireRole(RoleComposer, api.composeCancelHandler))
+
+	api.router.DELETE("/api/v:version/upload/delete/:uuid", requireRole(RoleComposer, api.uploadsDeleteHandler))
+	api.router.GET("/api/v:version/upload/info/:uuid", requireRole(RoleReadOnly, api.uploadsInfoHandler))
+	api.router.GET("/api/v:version/upload/log/:uuid", requireRole(RoleReadOnly, api.uploadsLogHandler))
+	api.router.POST("/api/v:version/upload/reset/:uuid", requireRole(RoleComposer, api.uploadsResetHandler))
+	api.router.DELETE("/api/v:version/upload/cancel/:uuid", requireRole(RoleComposer, api.uploadsCancelHandler))
+
+	api.router.GET("/api/v:version/upload/providers", requireRole(RoleReadOnly, api.providersHandler))
+	api.router.POST("/api/v:version/upload/providers/save", requireRole(RoleAdmin, api.providersSaveHandler))
+	api.router.DELETE("/api/v:version/upload/providers/delete/:provider/:profile", requireRole(RoleAdmin, api.providersDeleteHandler))
+
+	api.router.GET("/api/v:version/distros/list", requireRole(RoleReadOnly, api.distrosListHandler))
 	return api
 }
 
+// SetAuditLog makes the API record the calls of its mutating routes in l
+func (api *API) SetAuditLog(l *audit.Log) {
+	api.audit = l
+}
+
 func (api *API) Serve(listener net.Listener) error {
 	api.server = http.Server{
 		Handler:           api,
 		ReadHeaderTimeout: 5 * time.Second,
+		ConnContext:       contextWithPeerCredentials,
 	}
 
 	err := api.server.Serve(listener)
@@ -307,7 +335,10 @@ func (api *API) Serve(listener net.Listener) error {
 }
 
 func (api *API) Shutdown(ctx context.Context) error {
-	return api.server.Shutdown(ctx)
+	err := api.server.Shutdown(ctx)
+	api.goroutinesCtxCancel()
+	api.goroutinesGroup.Wait()
+	return err
 }
 
 func (api *API) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
@@ -316,7 +347,7 @@ func (api *API) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 	}
 
 	writer.Header().Set("Content-Type", "application/json; charset=utf-8")
-	api.router.ServeHTTP(writer, request)
+	audit.Handler(api.audit, "weldr", principal, api.router).ServeHTTP(writer, request)
 }
 
 type composeStatus struct {
@@ -325,6 +356,8 @@ type composeStatus struct {
 	Started  time.Time
 	Finished time.Time
 	Result   *osbuild.Result
+	// Jobs are the jobs preparing the manifest of the compose
+	Jobs []ComposeJobStatus
 }
 
 func composeStateFromJobStatus(js *worker.JobStatus, result *worker.OSBuildJobResult) ComposeState {
@@ -384,12 +417,18 @@ func (api *API) getComposeStatus(compose weldrtypes.Compose) (*composeStatus, er
 		return nil, err
 	}
 
+	jobs, err := api.composeJobStatuses(jobInfo)
+	if err != nil {
+		return nil, err
+	}
+
 	return &composeStatus{
 		State:    composeStateFromJobStatus(jobInfo.JobStatus, &result),
 		Queued:   jobInfo.JobStatus.Queued,
 		Started:  jobInfo.JobStatus.Started,
 		Finished: jobInfo.JobStatus.Finished,
 		Result:   result.OSBuildOutput,
+		Jobs:     jobs,
 	}, nil
 }
 
@@ -645,9 +684,7 @@ func (api *API) sourceEmptyInfoHandler(writer http.ResponseWriter, request *http
 
 // getSourceConfigs retrieves the list of sources from the system repos an store
 // Returning a list of store.SourceConfig entries indexed by the id of the source
-func (api *API) getSourceConfigs(params httprouter.Params) (map[string]store.SourceConfig, []responseError) {
-	names := params.ByName("sources")
-
+func (api *API) getSourceConfigs(names string) (map[string]store.SourceConfig, []responseError) {
 	sources := map[string]store.SourceConfig{}
 	errors := []responseError{}
 
@@ -716,7 +753,7 @@ func (api *API) sourceInfoHandler(writer http.ResponseWriter, request *http.Requ
 
 // sourceInfoHandlerV0 handles the API v0 response
 func (api *API) sourceInfoHandlerV0(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
-	sources, errors := api.getSourceConfigs(params)
+	sources, errors := api.getSourceConfigs(params.ByName("sources"))
 
 	// V0 responses use the source name as the key
 	v0Sources := make(map[string]SourceConfigV0, len(sources))
@@ -757,7 +794,7 @@ func (api *API) sourceInfoHandlerV0(writer http.ResponseWriter, request *http.Re
 
 // sourceInfoHandlerV1 handles the API v0 response
 func (api *API) sourceInfoHandlerV1(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
-	sources, errors := api.getSourceConfigs(params)
+	sources, errors := api.getSourceConfigs(params.ByName("sources"))
 
 	// V1 responses use the source id as the key
 	v1Sources := make(map[string]SourceConfigV1, len(sources))
@@ -1007,6 +1044,158 @@ func (api *API) sourceDeleteHandler(writer http.ResponseWriter, request *http.Re
 	statusResponseOK(writer)
 }
 
+// sourceCheckTimeout limits how long a source check request waits for the
+// repository check job to finish
+const sourceCheckTimeout = 5 * time.Minute
+
+// checkSources enqueues a repository check job for the given sources and
+// waits for its result. The health of sources in the store is updated, system
+// sources are only checked.
+func (api *API) checkSources(ctx context.Context, sources map[string]store.SourceConfig) (map[string]store.SourceHealth, error) {
+	ids := make([]string, 0, len(sources))
+	for id := range sources {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	job := worker.RepositoryCheckJob{}
+	for _, id := range ids {
+		source := sources[id]
+		repo := source.RepoConfig(id)
+		repo.Id = id
+		job.Repositories = append(job.Repositories, repo)
+	}
+
+	jobID, err := api.workers.EnqueueRepositoryCheckJob(ctx, &job, "")
+	if err != nil {
+		return nil, fmt.Errorf("cannot enqueue repository check job: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, sourceCheckTimeout)
+	defer cancel()
+
+	var result worker.RepositoryCheckJobResult
+	for {
+		info, err := api.workers.RepositoryCheckJobInfo(jobID, &result)
+		if err != nil {
+			return nil, err
+		}
+		if info.JobStatus.Canceled {
+			return nil, fmt.Errorf("repository check job %s was canceled", jobID)
+		}
+		if !info.JobStatus.Finished.IsZero() {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			err := api.workers.Cancel(jobID)
+			if err != nil && api.logger != nil {
+				api.logger.Printf("cannot cancel repository check job %s: %v", jobID, err)
+			}
+			return nil, fmt.Errorf("repository check job %s timed out", jobID)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	if result.JobError != nil {
+		return nil, fmt.Errorf("repository check failed: %s", result.JobError.Reason)
+	}
+	if len(result.Results) != len(ids) {
+		return nil, fmt.Errorf("repository check returned %d results for %d sources", len(result.Results), len(ids))
+	}
+
+	checked := time.Now().UTC()
+	health := make(map[string]store.SourceHealth, len(ids))
+	for i, id := range ids {
+		health[id] = newSourceHealth(checked, result.Results[i])
+		if !sources[id].System {
+			api.store.SetSourceHealth(id, health[id])
+		}
+	}
+
+	return health, nil
+}
+
+func newSourceHealth(checked time.Time, r worker.RepositoryCheckResultItem) store.SourceHealth {
+	health := store.SourceHealth{
+		Checked:      checked,
+		Healthy:      r.Healthy,
+		URL:          r.URL,
+		LastUpdated:  r.LastUpdated,
+		PackageCount: r.PackageCount,
+	}
+	if r.Error != "" {
+		health.Problems = append(health.Problems, r.Error)
+	}
+	if r.TLSError != "" {
+		health.Problems = append(health.Problems, "TLS error: "+r.TLSError)
+	}
+	health.Problems = append(health.Problems, r.GPGErrors...)
+	return health
+}
+
+// WatchSources periodically checks the health of all sources in the store
+// until the context is canceled
+func (api *API) WatchSources(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		sources := api.store.GetAllSourcesByID()
+		if len(sources) == 0 {
+			continue
+		}
+		_, err := api.checkSources(ctx, sources)
+		if err != nil && api.logger != nil {
+			api.logger.Printf("Error checking sources: %v", err)
+		}
+	}
+}
+
+func (api *API) sourceCheckHandler(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	if !verifyRequestVersion(writer, params, 0) {
+		return
+	}
+
+	sources, errors := api.getSourceConfigs(params.ByName("sources"))
+	if len(sources) == 0 {
+		statusResponseError(writer, http.StatusBadRequest, errors...)
+		return
+	}
+
+	health, err := api.checkSources(request.Context(), sources)
+	if err != nil {
+		errors := responseError{
+			ID:  "SourceCheckError",
+			Msg: err.Error(),
+		}
+		statusResponseError(writer, http.StatusInternalServerError, errors)
+		return
+	}
+
+	// V0 responses use the source name as the key
+	if !isRequestVersionAtLeast(params, 1) {
+		byName := make(map[string]store.SourceHealth, len(health))
+		for id, h := range health {
+			byName[sources[id].Name] = h
+		}
+		health = byName
+	}
+
+	err = json.NewEncoder(writer).Encode(SourceCheckResponse{
+		Sources: health,
+		Errors:  errors,
+	})
+	common.PanicOnError(err)
+}
+
 func (api *API) modulesListHandler(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
 	if !verifyRequestVersion(writer, params, 0) {
 		return
@@ -2290,7 +2479,7 @@ func (api *API) depsolve(packageSets map[string][]rpmmd.PackageSet, distroName s
 	return depsolvedSets, nil
 }
 
-func (api *API) resolveContainers(sourceSpecs map[string][]container.SourceSpec, archName string) (map[string][]container.Spec, error) {
+func (api *API) resolveContainers(ctx context.Context, sourceSpecs map[string][]container.SourceSpec, archName string) (map[string][]container.Spec, error) {
 
 	specs := make(map[string][]container.Spec, len(sourceSpecs))
 
@@ -2311,7 +2500,7 @@ func (api *API) resolveContainers(sourceSpecs map[string][]container.SourceSpec,
 		}
 	}
 
-	jobId, err := api.workers.EnqueueContainerResolveJob(&job, nil, "")
+	jobId, err := api.workers.EnqueueContainerResolveJob(ctx, &job, nil, "")
 	if err != nil {
 		return specs, err
 	}
@@ -2345,30 +2534,75 @@ func (api *API) resolveContainers(sourceSpecs map[string][]container.SourceSpec,
 	return specs, nil
 }
 
-func (api *API) resolveOSTreeCommits(sourceSpecs map[string][]ostree.SourceSpec, test bool) (map[string][]ostree.CommitSpec, error) {
+// resolveOSTreeCommits creates fake commit specs for test composes, with the
+// checksum derived from the URL and ref of each source
+func (api *API) resolveOSTreeCommits(sourceSpecs map[string][]ostree.SourceSpec) map[string][]ostree.CommitSpec {
 	commitSpecs := make(map[string][]ostree.CommitSpec, len(sourceSpecs))
 	for name, sources := range sourceSpecs {
 		commits := make([]ostree.CommitSpec, len(sources))
 		for idx, source := range sources {
-			if test {
-				checksum := fmt.Sprintf("%x", sha256.Sum256([]byte(source.URL+source.Ref)))
-				commits[idx] = ostree.CommitSpec{
-					Ref:      source.Ref,
-					URL:      source.URL,
-					Checksum: checksum,
-				}
-			} else {
-				// MTLS not supported on-prem
-				commit, err := ostree.Resolve(source)
-				if err != nil {
-					return nil, err
-				}
-				commits[idx] = commit
+			checksum := fmt.Sprintf("%x", sha256.Sum256([]byte(source.URL+source.Ref)))
+			commits[idx] = ostree.CommitSpec{
+				Ref:      source.Ref,
+				URL:      source.URL,
+				Checksum: checksum,
 			}
 		}
 		commitSpecs[name] = commits
 	}
-	return commitSpecs, nil
+	return commitSpecs
+}
+
+// resolveTestCompose depsolves and resolves the content of a test compose in
+// process and serializes its manifest. The package sets are expected to be
+// pinned to the lockfile, if there is one. On error, a response is written and
+// false returned.
+func (api *API) resolveTestCompose(ctx context.Context, writer http.ResponseWriter, manifestSource *manifest.Manifest, pkgSetChains map[string][]rpmmd.PackageSet, lf *lockfile.Lockfile, distroName string, arch distro.Arch) (manifest.OSBuildManifest, []weldrtypes.DepsolvedPackageInfo, bool) {
+	depsolved, err := api.depsolve(pkgSetChains, distroName, arch)
+	if err != nil {
+		errors := responseError{
+			ID:  "DepsolveError",
+			Msg: err.Error(),
+		}
+		statusResponseError(writer, http.StatusInternalServerError, errors)
+		return nil, nil, false
+	}
+
+	if lf != nil {
+		for name, result := range depsolved {
+			if err := lf.Verify(name, result.Transactions); err != nil {
+				errors := responseError{
+					ID:  "LockfileError",
+					Msg: err.Error(),
+				}
+				statusResponseError(writer, http.StatusBadRequest, errors)
+				return nil, nil, false
+			}
+		}
+	}
+
+	containerSpecs, err := api.resolveContainers(ctx, manifestSource.GetContainerSourceSpecs(), arch.Name())
+	if err != nil {
+		errors := responseError{
+			ID:  "ContainerResolveError",
+			Msg: err.Error(),
+		}
+		statusResponseError(writer, http.StatusInternalServerError, errors)
+		return nil, nil, false
+	}
+
+	ostreeCommitSpecs := api.resolveOSTreeCommits(manifestSource.GetOSTreeSourceSpecs())
+	mf, err := manifestSource.Serialize(depsolved, containerSpecs, ostreeCommitSpecs, nil, nil)
+	if err != nil {
+		errors := responseError{
+			ID:  "ManifestCreationFailed",
+			Msg: fmt.Sprintf("failed to serialize osbuild manifest: %v", err),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return nil, nil, false
+	}
+
+	return mf, weldrtypes.RPMMDPackageListToDepsolvedPackageInfoList(imagePackages(depsolved)), true
 }
 
 // Schedule new compose by first translating the appropriate blueprint into a pipeline and then
@@ -2386,6 +2620,7 @@ func (api *API) composeHandler(writer http.ResponseWriter, request *http.Request
 		OSTree        *ostree.ImageOptions `json:"ostree,omitempty"`
 		Branch        string               `json:"branch"`
 		Upload        *uploadRequest       `json:"upload"`
+		Lockfile      *lockfile.Lockfile   `json:"lockfile,omitempty"`
 	}
 	type ComposeReply struct {
 		BuildID  uuid.UUID `json:"build_id"`
@@ -2471,6 +2706,18 @@ func (api *API) composeHandler(writer http.ResponseWriter, request *http.Request
 		targets = append(targets, t)
 	}
 
+	// keep the passwords and keys of the upload out of the job queue and
+	// the store
+	err = api.workers.SealTargetSecrets("", targets)
+	if err != nil {
+		errors := responseError{
+			ID:  "ComposePushErrored",
+			Msg: fmt.Sprintf("cannot store the secrets of the upload: %v", err),
+		}
+		statusResponseError(writer, http.StatusInternalServerError, errors)
+		return
+	}
+
 	// Check for test parameter
 	q, err := url.ParseQuery(request.URL.RawQuery)
 	if err != nil {
@@ -2548,54 +2795,19 @@ func (api *API) composeHandler(writer http.ResponseWriter, request *http.Request
 		statusResponseError(writer, http.StatusBadRequest, errors)
 		return
 	}
-	depsolved, err := api.depsolve(pkgSetChains, distroName, imageType.Arch())
-	if err != nil {
-		errors := responseError{
-			ID:  "DepsolveError",
-			Msg: err.Error(),
-		}
-		statusResponseError(writer, http.StatusInternalServerError, errors)
-		return
-	}
-
-	containerSpecs, err := api.resolveContainers(manifest.GetContainerSourceSpecs(), archName)
-	if err != nil {
-		errors := responseError{
-			ID:  "ContainerResolveError",
-			Msg: err.Error(),
-		}
-		statusResponseError(writer, http.StatusInternalServerError, errors)
-		return
-	}
-
-	testMode := q.Get("test")
-
-	ostreeCommitSpecs, err := api.resolveOSTreeCommits(manifest.GetOSTreeSourceSpecs(), testMode == "1" || testMode == "2")
-	if err != nil {
-		errors := responseError{
-			ID:  "OSTreeOptionsError",
-			Msg: err.Error(),
-		}
-		statusResponseError(writer, http.StatusBadRequest, errors)
-		return
-	}
 
-	mf, err := manifest.Serialize(depsolved, containerSpecs, ostreeCommitSpecs, nil, nil)
-	if err != nil {
-		errors := responseError{
-			ID:  "ManifestCreationFailed",
-			Msg: fmt.Sprintf("failed to serialize osbuild manifest: %v", err),
+	if cr.Lockfile != nil {
+		err = cr.Lockfile.Validate()
+		if err == nil {
+			err = cr.Lockfile.Pin(pkgSetChains)
 		}
-		statusResponseError(writer, http.StatusBadRequest, errors)
-		return
-	}
-
-	var packages rpmmd.PackageList
-	// TODO: introduce a way to query these from the manifest / image type
-	// BUG: installer/container image types will have empty package sets
-	if packages = depsolved["packages"].Transactions.AllPackages(); len(packages) == 0 {
-		if packages = depsolved["os"].Transactions.AllPackages(); len(packages) == 0 {
-			packages = depsolved["ostree-tree"].Transactions.AllPackages()
+		if err != nil {
+			errors := responseError{
+				ID:  "LockfileError",
+				Msg: err.Error(),
+			}
+			statusResponseError(writer, http.StatusBadRequest, errors)
+			return
 		}
 	}
 
@@ -2619,27 +2831,47 @@ func (api *API) composeHandler(writer http.ResponseWriter, request *http.Request
 	}
 
 	var jobID uuid.UUID
-	weldrPackages := weldrtypes.RPMMDPackageListToDepsolvedPackageInfoList(packages)
-	if testMode == "1" {
-		jobID = uuid.New()
-		// Create a failed compose
-		err = api.store.PushTestCompose(jobID, mf, imageType, bp, size, targets, false, weldrPackages)
-	} else if testMode == "2" {
+	testMode := q.Get("test")
+	if testMode == "1" || testMode == "2" {
+		// Test composes never reach a worker, resolve their content in place
+		mf, packages, ok := api.resolveTestCompose(request.Context(), writer, manifest, pkgSetChains, cr.Lockfile, distroName, imageType.Arch())
+		if !ok {
+			return
+		}
 		jobID = uuid.New()
-		// Create a successful compose
-		err = api.store.PushTestCompose(jobID, mf, imageType, bp, size, targets, true, weldrPackages)
+		// Create a failed (1) or successful (2) compose
+		err = api.store.PushTestCompose(jobID, mf, imageType, bp, size, targets, testMode == "2", packages)
 	} else {
-		jobID, err = api.workers.EnqueueOSBuild(archName, &worker.OSBuildJob{
-			Manifest: mf,
-			Targets:  targets,
-			PipelineNames: &worker.PipelineNames{
-				Build:   manifest.BuildPipelines(),
-				Payload: manifest.PayloadPipelines(),
-			},
-			ImageBootMode: imageType.BootMode().String(),
-		}, "")
+		var jobs manifestjob.Dependencies
+		jobs, err = manifestjob.EnqueueResolveJobs(request.Context(), api.workers, manifest, pkgSetChains, cr.Lockfile, imageType.Arch(), sbom.StandardTypeNone, "")
+		if err != nil {
+			errors := responseError{
+				ID:  "DepsolveError",
+				Msg: err.Error(),
+			}
+			statusResponseError(writer, http.StatusInternalServerError, errors)
+			return
+		}
+
+		var manifestJobID uuid.UUID
+		manifestJobID, err = api.workers.EnqueueManifestJobByID(request.Context(), &worker.ManifestJobByID{}, jobs.IDs(), "")
 		if err == nil {
-			err = api.store.PushCompose(jobID, mf, imageType, bp, size, targets, weldrPackages)
+			jobID, err = api.workers.EnqueueOSBuildAsDependency(request.Context(), archName, &worker.OSBuildJob{
+				Targets:       targets,
+				ImageBootMode: imageType.BootMode().String(),
+				ImageType:     imageType.Name(),
+			}, []uuid.UUID{manifestJobID}, "")
+		}
+		if err == nil {
+			// The manifest and packages are set once the resolve jobs are done
+			err = api.store.PushCompose(jobID, nil, imageType, bp, size, targets, nil)
+		}
+		if err == nil {
+			api.goroutinesGroup.Add(1)
+			go func() {
+				defer api.goroutinesGroup.Done()
+				api.serializeManifest(api.goroutinesCtx, jobID, manifest, jobs, manifestJobID)
+			}()
 		}
 	}
 
@@ -2655,6 +2887,10 @@ func (api *API) composeHandler(writer http.ResponseWriter, request *http.Request
 		return
 	}
 
+	audit.Annotate(request.Context(), "compose_id", jobID.String())
+	audit.Annotate(request.Context(), "blueprint", cr.BlueprintName)
+	audit.Annotate(request.Context(), "compose_type", cr.ComposeType)
+
 	err = json.NewEncoder(writer).Encode(ComposeReply{
 		BuildID:  jobID,
 		Status:   true,
@@ -2735,6 +2971,7 @@ func (api *API) composeDeleteHandler(writer http.ResponseWriter, request *http.R
 		if err == jobqueue.ErrNotExist && api.compatOutputDir != "" {
 			_ = os.RemoveAll(path.Join(api.compatOutputDir, id.String()))
 		}
+		_ = api.workers.DeleteTargetSecrets(compose.ImageBuild.Targets)
 
 		results = append(results, composeDeleteStatus{id, true})
 	}
@@ -3211,6 +3448,139 @@ func (api *API) composeMetadataHandler(writer http.ResponseWriter, request *http
 	common.PanicOnError(err)
 }
 
+// composeLockfileHandler returns a lockfile of the packages a finished compose
+// was built from, to be passed along with a new compose to rebuild it
+func (api *API) composeLockfileHandler(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	if !verifyRequestVersion(writer, params, 1) {
+		return
+	}
+
+	uuidString := params.ByName("uuid")
+	id, err := uuid.Parse(uuidString)
+	if err != nil {
+		errors := responseError{
+			ID:  "UnknownUUID",
+			Msg: fmt.Sprintf("%s is not a valid build uuid", uuidString),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
+	compose, exists := api.store.GetCompose(id)
+	if !exists {
+		errors := responseError{
+			ID:  "UnknownUUID",
+			Msg: fmt.Sprintf("Compose %s doesn't exist", uuidString),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
+	composeStatus, err := api.getComposeStatus(compose)
+	if err != nil {
+		errors := responseError{
+			ID:  "ComposeStatusError",
+			Msg: fmt.Sprintf("Error getting status of compose %s: %s", id, err),
+		}
+		statusResponseError(writer, http.StatusInternalServerError, errors)
+		return
+	}
+	if composeStatus.State != ComposeFinished {
+		errors := responseError{
+			ID:  "BuildInWrongState",
+			Msg: fmt.Sprintf("Build %s is in wrong state: %s", uuidString, composeStatus.State.ToString()),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
+	lf, err := api.composeLockfile(compose)
+	if err != nil {
+		errors := responseError{
+			ID:  "LockfileError",
+			Msg: fmt.Sprintf("Error creating lockfile of compose %s: %s", id, err),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
+	err = json.NewEncoder(writer).Encode(lf)
+	common.PanicOnError(err)
+}
+
+// composeDiffHandler returns the changes between two finished composes
+func (api *API) composeDiffHandler(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	if !verifyRequestVersion(writer, params, 1) {
+		return
+	}
+
+	finishedCompose := func(uuidString string) (weldrtypes.Compose, bool) {
+		id, err := uuid.Parse(uuidString)
+		if err != nil {
+			errors := responseError{
+				ID:  "UnknownUUID",
+				Msg: fmt.Sprintf("%s is not a valid build uuid", uuidString),
+			}
+			statusResponseError(writer, http.StatusBadRequest, errors)
+			return weldrtypes.Compose{}, false
+		}
+
+		compose, exists := api.store.GetCompose(id)
+		if !exists {
+			errors := responseError{
+				ID:  "UnknownUUID",
+				Msg: fmt.Sprintf("Compose %s doesn't exist", uuidString),
+			}
+			statusResponseError(writer, http.StatusBadRequest, errors)
+			return weldrtypes.Compose{}, false
+		}
+
+		composeStatus, err := api.getComposeStatus(compose)
+		if err != nil {
+			errors := responseError{
+				ID:  "ComposeStatusError",
+				Msg: fmt.Sprintf("Error getting status of compose %s: %s", id, err),
+			}
+			statusResponseError(writer, http.StatusInternalServerError, errors)
+			return weldrtypes.Compose{}, false
+		}
+		if composeStatus.State != ComposeFinished {
+			errors := responseError{
+				ID:  "BuildInWrongState",
+				Msg: fmt.Sprintf("Build %s is in wrong state: %s", uuidString, composeStatus.State.ToString()),
+			}
+			statusResponseError(writer, http.StatusBadRequest, errors)
+			return weldrtypes.Compose{}, false
+		}
+
+		return compose, true
+	}
+
+	from, ok := finishedCompose(params.ByName("from"))
+	if !ok {
+		return
+	}
+	to, ok := finishedCompose(params.ByName("to"))
+	if !ok {
+		return
+	}
+
+	diff, err := api.composeDiff(from, to)
+	if err != nil {
+		errors := responseError{
+			ID:  "ComposeDiffError",
+			Msg: err.Error(),
+		}
+		statusResponseError(writer, http.StatusInternalServerError, errors)
+		return
+	}
+
+	err = json.NewEncoder(writer).Encode(struct {
+		Diff *composediff.Diff `json:"diff"`
+	}{diff})
+	common.PanicOnError(err)
+}
+
 // composeResultsHandler returns a tar of the metadata, logs, and image from a compose
 func (api *API) composeResultsHandler(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
 	if !verifyRequestVersion(writer, params, 0) {
@@ -3,15 +3,23 @@ package weldr
 import (
 	"archive/tar"
 	"bytes"
+	"context"
 	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
 	"fmt"
 	"io"
 	"math/rand"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"os/user"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -26,6 +34,7 @@ import (
 	"github.com/osbuild/image-builder/pkg/ostree/mock_ostree_repo"
 	"github.com/osbuild/image-builder/pkg/reporegistry"
 	"github.com/osbuild/image-builder/pkg/rpmmd"
+	"github.com/osbuild/osbuild-composer/internal/audit"
 	"github.com/osbuild/osbuild-composer/internal/common"
 	depsolvednf_mock "github.com/osbuild/osbuild-composer/internal/mocks/depsolvednf"
 	rpmmd_mock "github.com/osbuild/osbuild-composer/internal/mocks/rpmmd"
@@ -33,9 +42,11 @@ import (
 	"github.com/osbuild/osbuild-composer/internal/target"
 	"github.com/osbuild/osbuild-composer/internal/test"
 	"github.com/osbuild/osbuild-composer/internal/weldrtypes"
+	"github.com/osbuild/osbuild-composer/internal/worker"
 
 	"github.com/BurntSushi/toml"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -1372,39 +1383,39 @@ func TestCompose(t *testing.T) {
 			[]string{"build_id", "warnings"},
 			getSolverFn,
 		},
-		// Ref + invalid URL = error
+		// Ref + invalid URL = the ostree resolve job fails
 		"ostree-invalid-url": {
 			false,
 			"POST",
 			"/api/v1/compose",
 			fmt.Sprintf(`{"blueprint_name": "test","compose_type":"%s","branch":"master","ostree":{"ref":"whatever","parent":"","url":"invalid-url"}}`, test_distro.TestImageTypeOSTree),
-			http.StatusBadRequest,
-			`{"status":false,"errors":[{"id":"OSTreeOptionsError","msg":"error sending request to ostree repository \"invalid-url/refs/heads/whatever\": Get \"invalid-url/refs/heads/whatever\": unsupported protocol scheme \"\""}]}`,
+			http.StatusOK,
+			`{"status":true}`,
 			nil,
 			[]string{"build_id", "warnings"},
 			getSolverFn,
 		},
-		// Bad Ref + URL = error
+		// Bad Ref + URL = the ostree resolve job fails
 		"ostree-bad-ref": {
 			false,
 			"POST",
 			"/api/v1/compose",
 			fmt.Sprintf(`{"blueprint_name": "test","compose_type":"%s","branch":"master","ostree":{"ref":"/bad/ref","parent":"","url":"http://ostree/"}}`, test_distro.TestImageTypeOSTree),
-			http.StatusBadRequest,
-			`{"status":false,"errors":[{"id":"OSTreeOptionsError","msg":"Invalid ostree ref or commit \"/bad/ref\""}]}`,
-			expectedComposeOSTree,
+			http.StatusOK,
+			`{"status":true}`,
+			nil,
 			[]string{"build_id", "warnings"},
 			getSolverFn,
 		},
-		// Incorrect Ref + URL = the parameters are okay, but the ostree repo returns 404
+		// Incorrect Ref + URL = the parameters are okay, but the ostree repo returns 404 and the ostree resolve job fails
 		"ostree-404": {
 			false,
 			"POST",
 			"/api/v1/compose",
 			fmt.Sprintf(`{"blueprint_name": "test","compose_type":"%s","branch":"master","ostree":{"ref":"%s","parent":"","url":"%s"}}`, test_distro.TestImageTypeOSTree, "the/wrong/ref", ostreeRepoDefault.Server.URL),
-			http.StatusBadRequest,
-			fmt.Sprintf(`{"status":false,"errors":[{"id":"OSTreeOptionsError","msg":"ostree repository \"%s/refs/heads/the/wrong/ref\" returned status: 404 Not Found"}]}`, ostreeRepoDefault.Server.URL),
-			expectedComposeOSTree,
+			http.StatusOK,
+			`{"status":true}`,
+			nil,
 			[]string{"build_id", "warnings"},
 			getSolverFn,
 		},
@@ -1451,23 +1462,20 @@ func TestCompose(t *testing.T) {
 			api, sf := createTestWeldrAPI(t.TempDir(), test_distro.TestDistro1Name, test_distro.TestArchName, c.GetSolverFn, rpmmd_mock.NoComposesFixture, nil)
 			t.Cleanup(sf.Cleanup)
 
-			_, err = api.workers.RegisterWorker("", arch.Name())
+			_, err = api.workers.RegisterWorker("", arch.Name(), "", nil)
 			require.NoError(t, err)
+			serveResolveJobs(t, api, arch.Name())
 			test.TestRoute(t, api, c.External, c.Method, c.Path, c.Body, c.ExpectedStatus, c.ExpectedJSON, c.IgnoreFields...)
 
 			if c.ExpectedStatus != http.StatusOK {
 				return
 			}
 
-			composes := sf.Store.GetAllComposes()
-
-			require.Equalf(t, 1, len(composes), "%s: %s: bad compose count in store", name, c.Path)
-
-			// I have no idea how to get the compose in better way
-			var composeStruct weldrtypes.Compose
-			for _, c := range composes {
-				composeStruct = c
-				break
+			composeStruct, status := waitForComposeJobs(t, api, sf)
+			if c.ExpectedCompose == nil {
+				require.Nilf(t, composeStruct.ImageBuild.Manifest, "%s: %s: the compose in the store has a manifest", name, c.Path)
+				require.Equalf(t, common.IBFailed, status.Jobs[len(status.Jobs)-1].Status, "%s: %s: the manifest job didn't fail", name, c.Path)
+				return
 			}
 
 			require.NotNilf(t, composeStruct.ImageBuild.Manifest, "%s: %s: the compose in the store did not contain a blueprint", name, c.Path)
@@ -2049,6 +2057,186 @@ func TestSourcesDelete(t *testing.T) {
 	}
 }
 
+func TestRoleMapping(t *testing.T) {
+	if len(os.Getenv("OSBUILD_COMPOSER_TEST_EXTERNAL")) > 0 {
+		t.Skip("This test is for internal testing only")
+	}
+
+	api, sf := createTestWeldrAPI(t.TempDir(), test_distro.TestDistro1Name, test_distro.TestArchName, nil, rpmmd_mock.BaseFixture, nil)
+	t.Cleanup(sf.Cleanup)
+
+	mapping := RoleMapping{
+		Users: map[string]Role{
+			"reader": RoleReadOnly,
+			"ci":     RoleComposer,
+			"root":   RoleAdmin,
+		},
+		DefaultRole: RoleNone,
+	}
+
+	// asUser presents a client certificate with the given common name
+	asUser := func(user string) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			if user != "" {
+				request.TLS = &tls.ConnectionState{
+					PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: user}}},
+				}
+			}
+			mapping.Handler(api).ServeHTTP(writer, request)
+		})
+	}
+
+	blueprint := `{"name":"test","description":"Test","packages":[],"version":""}`
+	source := `{"id": "fish","name":"fish repo","url": "https://download.opensuse.org/repositories/shells:/fish:/release:/3/Fedora_29/","type": "yum-baseurl","check_ssl": true,"check_gpg": false}`
+
+	var cases = []struct {
+		User   string
+		Method string
+		Path   string
+		Body   string
+		Status int
+	}{
+		{"reader", "GET", "/api/v1/blueprints/list", "", http.StatusOK},
+		{"reader", "POST", "/api/v1/blueprints/new", blueprint, http.StatusForbidden},
+		{"ci", "POST", "/api/v1/blueprints/new", blueprint, http.StatusOK},
+		{"ci", "POST", "/api/v1/projects/source/new", source, http.StatusForbidden},
+		{"root", "POST", "/api/v1/projects/source/new", source, http.StatusOK},
+		{"root", "DELETE", "/api/v1/blueprints/delete/test", "", http.StatusOK},
+		{"stranger", "GET", "/api/v1/blueprints/list", "", http.StatusForbidden},
+		{"", "GET", "/api/status", "", http.StatusForbidden},
+	}
+
+	for _, c := range cases {
+		resp := test.SendHTTP(asUser(c.User), false, c.Method, c.Path, c.Body)
+		require.Equal(t, c.Status, resp.StatusCode, "%s %s as %q", c.Method, c.Path, c.User)
+	}
+
+	test.TestRoute(t, asUser("reader"), false, "DELETE", "/api/v1/blueprints/delete/test", ``, http.StatusForbidden,
+		`{"status":false,"errors":[{"code":403,"id":"Forbidden","msg":"this operation requires the composer role"}]}`)
+
+	// requests without a role, e.g. from the local socket, are not restricted
+	test.TestRoute(t, api, false, "POST", "/api/v1/blueprints/new", blueprint, http.StatusOK, `{"status":true}`)
+}
+
+func TestAuditLog(t *testing.T) {
+	if len(os.Getenv("OSBUILD_COMPOSER_TEST_EXTERNAL")) > 0 {
+		t.Skip("This test is for internal testing only")
+	}
+
+	api, sf := createTestWeldrAPI(t.TempDir(), test_distro.TestDistro1Name, test_distro.TestArchName, nil, rpmmd_mock.BaseFixture, nil)
+	t.Cleanup(sf.Cleanup)
+
+	dir := t.TempDir()
+	auditLog, err := audit.NewLog(filepath.Join(dir, "audit.jsonl"), audit.Options{})
+	require.NoError(t, err)
+	defer auditLog.Close()
+	api.SetAuditLog(auditLog)
+
+	// callers of the local socket are identified by their unix user
+	socket := filepath.Join(dir, "api.socket")
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		_ = api.Serve(listener)
+	}()
+	client := http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+			},
+		},
+	}
+
+	blueprint := `{"name":"test","description":"Test","packages":[],"version":""}`
+	resp, err := client.Post("http://localhost/api/v1/blueprints/new", "application/json", strings.NewReader(blueprint))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	resp, err = client.Get("http://localhost/api/v1/blueprints/list")
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// callers of the TCP socket are identified by the role mapping
+	mapping := RoleMapping{
+		Users:       map[string]Role{"ci": RoleComposer},
+		DefaultRole: RoleNone,
+	}
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		request.TLS = &tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: "ci"}}},
+		}
+		mapping.Handler(api).ServeHTTP(writer, request)
+	})
+	test.TestRoute(t, handler, false, "DELETE", "/api/v1/blueprints/delete/test", ``, http.StatusOK, `{"status":true}`)
+
+	records, err := auditLog.Query(audit.Filter{})
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	current, err := user.Current()
+	require.NoError(t, err)
+	require.Equal(t, "weldr", records[0].API)
+	require.Equal(t, fmt.Sprintf("uid=%s(%s)", current.Uid, current.Username), records[0].Principal)
+	require.Equal(t, "POST /api/v1/blueprints/new", records[0].Action)
+	require.Equal(t, audit.OutcomeSuccess, records[0].Outcome)
+
+	require.Equal(t, "ci", records[1].Principal)
+	require.Equal(t, "DELETE /api/v1/blueprints/delete/test", records[1].Action)
+}
+
+func TestSourcesCheck(t *testing.T) {
+	if len(os.Getenv("OSBUILD_COMPOSER_TEST_EXTERNAL")) > 0 {
+		t.Skip("This test is for internal testing only")
+	}
+
+	api, sf := createTestWeldrAPI(t.TempDir(), test_distro.TestDistro1Name, test_distro.TestArchName, nil, rpmmd_mock.BaseFixture, nil)
+	t.Cleanup(sf.Cleanup)
+
+	test.SendHTTP(api, true, "POST", "/api/v1/projects/source/new", `{"id": "fish","name":"fish repo","url": "https://download.opensuse.org/repositories/shells:/fish:/release:/3/Fedora_29/","type": "yum-baseurl","check_ssl": true,"check_gpg": false}`)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, token, jobType, rawArgs, _, err := api.workers.RequestJob(context.Background(), test_distro.TestArchName, []string{worker.JobTypeRepositoryCheck}, []string{""}, uuid.Nil)
+		require.NoError(t, err)
+		require.Equal(t, worker.JobTypeRepositoryCheck, jobType)
+
+		var args worker.RepositoryCheckJob
+		require.NoError(t, json.Unmarshal(rawArgs, &args))
+		require.Len(t, args.Repositories, 1)
+		require.Equal(t, "fish", args.Repositories[0].Id)
+
+		result, err := json.Marshal(worker.RepositoryCheckJobResult{
+			Results: []worker.RepositoryCheckResultItem{
+				{
+					RepoID:   "fish",
+					URL:      "https://download.opensuse.org/repositories/shells:/fish:/release:/3/Fedora_29/",
+					Healthy:  false,
+					TLSError: "x509: certificate signed by unknown authority",
+				},
+			},
+		})
+		require.NoError(t, err)
+		require.NoError(t, api.workers.FinishJob(token, result))
+	}()
+
+	test.TestRoute(t, api, true, "GET", "/api/v1/projects/source/check/fish", ``, http.StatusOK,
+		`{"sources":{"fish":{"healthy":false,"url":"https://download.opensuse.org/repositories/shells:/fish:/release:/3/Fedora_29/","package_count":0,"problems":["TLS error: x509: certificate signed by unknown authority"]}},"errors":[]}`,
+		"checked")
+	<-done
+
+	// the result is recorded in the store and returned by source info
+	source := api.store.GetSource("fish")
+	require.NotNil(t, source)
+	require.NotNil(t, source.Health)
+	require.False(t, source.Health.Healthy)
+
+	test.TestRoute(t, api, true, "GET", "/api/v1/projects/source/check/unknown", ``, http.StatusBadRequest,
+		`{"status":false,"errors":[{"id":"UnknownSource","msg":"unknown is not a valid source"}]}`)
+}
+
 func TestProjectsDepsolve(t *testing.T) {
 	var cases = []struct {
 		Fixture        rpmmd_mock.FixtureGenerator
@@ -2706,22 +2894,16 @@ func TestComposePOST_ImageTypeDenylist(t *testing.T) {
 		t.Run(fmt.Sprintf("case %d", idx), func(t *testing.T) {
 			api, sf := createTestWeldrAPI(t.TempDir(), distro2.Name(), arch.Name(), c.GetSolverFn, rpmmd_mock.NoComposesFixture, c.imageTypeDenylist)
 			t.Cleanup(sf.Cleanup)
-			_, err = api.workers.RegisterWorker("", arch.Name())
+			_, err = api.workers.RegisterWorker("", arch.Name(), "", nil)
 			require.NoError(t, err)
+			serveResolveJobs(t, api, arch.Name())
 			test.TestRoute(t, api, true, "POST", c.Path, c.Body, c.ExpectedStatus, c.ExpectedJSON, c.IgnoreFields...)
 
 			if c.ExpectedStatus != http.StatusOK {
 				return
 			}
 
-			composes := sf.Store.GetAllComposes()
-			require.Equalf(t, 1, len(composes), "%s: bad compose count in store", c.Path)
-
-			var composeStruct weldrtypes.Compose
-			for _, c := range composes {
-				composeStruct = c
-				break
-			}
+			composeStruct, _ := waitForComposeJobs(t, api, sf)
 
 			require.NotNilf(t, composeStruct.ImageBuild.Manifest, "%s: the compose in the store did not contain a blueprint", c.Path)
 
@@ -0,0 +1,168 @@
+package weldr
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os/user"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+	"golang.org/x/sys/unix"
+
+	"github.com/osbuild/osbuild-composer/internal/auth"
+)
+
+// Role is the level of access a caller of the weldr API has. Each role
+// includes all the permissions of the roles before it.
+type Role int
+
+const (
+	// RoleNone is not allowed to call any route
+	RoleNone Role = iota
+	// RoleReadOnly may only call routes which don't modify any state
+	RoleReadOnly
+	// RoleComposer may additionally manage blueprints and composes
+	RoleComposer
+	// RoleAdmin may additionally manage sources and upload providers
+	RoleAdmin
+)
+
+func (r Role) String() string {
+	switch r {
+	case RoleReadOnly:
+		return "read-only"
+	case RoleComposer:
+		return "composer"
+	case RoleAdmin:
+		return "admin"
+	default:
+		return "none"
+	}
+}
+
+// ParseRole converts the name of a role, as used in the configuration file,
+// into a Role
+func ParseRole(name string) (Role, error) {
+	switch name {
+	case "none":
+		return RoleNone, nil
+	case "read-only":
+		return RoleReadOnly, nil
+	case "composer":
+		return RoleComposer, nil
+	case "admin":
+		return RoleAdmin, nil
+	default:
+		return RoleNone, fmt.Errorf("unknown weldr role %q", name)
+	}
+}
+
+type roleCtxKey struct{}
+type userCtxKey struct{}
+type peerCredentialsCtxKey struct{}
+
+// ContextWithRole returns a copy of ctx carrying the role of the caller
+func ContextWithRole(ctx context.Context, role Role) context.Context {
+	return context.WithValue(ctx, roleCtxKey{}, role)
+}
+
+// roleFromContext returns the role of the caller. Requests which didn't pass
+// through a RoleMapping, i.e. the ones coming in on the local unix socket, are
+// authorized by the group ownership of the socket and don't carry a role.
+func roleFromContext(ctx context.Context) (Role, bool) {
+	role, ok := ctx.Value(roleCtxKey{}).(Role)
+	return role, ok
+}
+
+// requireRole wraps handle so that it is only called for callers having at
+// least the given role
+func requireRole(role Role, handle httprouter.Handle) httprouter.Handle {
+	return func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		if callerRole, ok := roleFromContext(request.Context()); ok && callerRole < role {
+			errors := responseError{
+				Code: http.StatusForbidden,
+				ID:   "Forbidden",
+				Msg:  fmt.Sprintf("this operation requires the %s role", role),
+			}
+			statusResponseError(writer, http.StatusForbidden, errors)
+			return
+		}
+		handle(writer, request, params)
+	}
+}
+
+// RoleMapping assigns roles to the callers of the weldr API listening on a
+// TCP socket. Callers are identified either by the common name of their TLS
+// client certificate or by the first non-empty JWT claim in JWTUserFields.
+type RoleMapping struct {
+	Users         map[string]Role
+	DefaultRole   Role
+	JWTUserFields []string
+}
+
+// identity returns the name of the caller, or an empty string if it cannot
+// be determined
+func (m *RoleMapping) identity(request *http.Request) string {
+	return auth.CallerIdentity(request, m.JWTUserFields)
+}
+
+// Handler attaches the role of the caller to each request before passing it
+// on to next. Unidentified callers get RoleNone.
+func (m *RoleMapping) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		role := RoleNone
+		user := m.identity(request)
+		if user != "" {
+			var ok bool
+			role, ok = m.Users[user]
+			if !ok {
+				role = m.DefaultRole
+			}
+		}
+
+		ctx := context.WithValue(ContextWithRole(request.Context(), role), userCtxKey{}, user)
+		next.ServeHTTP(writer, request.WithContext(ctx))
+	})
+}
+
+// contextWithPeerCredentials attaches the credentials of the process on the
+// other end of a unix socket connection to ctx
+func contextWithPeerCredentials(ctx context.Context, conn net.Conn) context.Context {
+	unixConn, ok := conn.(*net.UnixConn)
+	if !ok {
+		return ctx
+	}
+	rawConn, err := unixConn.SyscallConn()
+	if err != nil {
+		return ctx
+	}
+
+	var cred *unix.Ucred
+	var credErr error
+	err = rawConn.Control(func(fd uintptr) {
+		cred, credErr = unix.GetsockoptUcred(int(fd), unix.SOL_SOCKET, unix.SO_PEERCRED)
+	})
+	if err != nil || credErr != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, peerCredentialsCtxKey{}, cred)
+}
+
+// principal identifies the caller of a request in the audit log, either by
+// the user a RoleMapping identified or by the unix user connected to the
+// local socket
+func principal(request *http.Request) string {
+	if identity, ok := request.Context().Value(userCtxKey{}).(string); ok {
+		return identity
+	}
+	if cred, ok := request.Context().Value(peerCredentialsCtxKey{}).(*unix.Ucred); ok {
+		name := strconv.FormatUint(uint64(cred.Uid), 10)
+		if u, err := user.LookupId(name); err == nil {
+			name = u.Username
+		}
+		return fmt.Sprintf("uid=%d(%s)", cred.Uid, name)
+	}
+	return ""
+}
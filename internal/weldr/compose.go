@@ -1,12 +1,24 @@
 package weldr
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
 	"sort"
+	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/osbuild/image-builder/pkg/depsolvednf"
+	"github.com/osbuild/image-builder/pkg/manifest"
+	"github.com/osbuild/image-builder/pkg/rpmmd"
 	"github.com/osbuild/osbuild-composer/internal/common"
+	"github.com/osbuild/osbuild-composer/internal/composediff"
+	"github.com/osbuild/osbuild-composer/internal/lockfile"
+	"github.com/osbuild/osbuild-composer/internal/manifestjob"
 	"github.com/osbuild/osbuild-composer/internal/weldrtypes"
+	"github.com/osbuild/osbuild-composer/internal/worker"
 )
 
 type ComposeEntry struct {
@@ -20,6 +32,7 @@ type ComposeEntry struct {
 	JobStarted  float64                `json:"job_started,omitempty"`
 	JobFinished float64                `json:"job_finished,omitempty"`
 	Uploads     []uploadResponse       `json:"uploads,omitempty"`
+	Jobs        []ComposeJobStatus     `json:"jobs,omitempty"`
 }
 
 func composeToComposeEntry(id uuid.UUID, compose weldrtypes.Compose, status *composeStatus, includeUploads bool) *ComposeEntry {
@@ -33,6 +46,7 @@ func composeToComposeEntry(id uuid.UUID, compose weldrtypes.Compose, status *com
 	if includeUploads {
 		composeEntry.Uploads = targetsToUploadResponses(compose.ImageBuild.Targets, status.State)
 	}
+	composeEntry.Jobs = status.Jobs
 
 	switch status.State {
 	case ComposeWaiting:
@@ -68,3 +82,145 @@ func sortComposeEntries(entries []*ComposeEntry) {
 		return entries[i].ID.String() < entries[j].ID.String()
 	})
 }
+
+// composeJobTimeout is the time the content resolve jobs of a compose may take
+// before its manifest job is failed
+const composeJobTimeout = 5 * time.Minute
+
+// ComposeJobStatus is the state of one of the jobs a compose depends on, for
+// example the depsolve or manifest job
+type ComposeJobStatus struct {
+	Type   string                 `json:"type"`
+	Status common.ImageBuildState `json:"status"`
+}
+
+// serializeManifest serializes the manifest of a compose once its resolve
+// jobs are done, see [manifestjob.Serialize]. The manifest and the depsolved
+// packages are also recorded in the store.
+func (api *API) serializeManifest(ctx context.Context, composeID uuid.UUID, manifestSource *manifest.Manifest, jobs manifestjob.Dependencies, manifestJobID uuid.UUID) {
+	getManifestSource := func() (*manifest.Manifest, error) {
+		return manifestSource, nil
+	}
+	manifestjob.Serialize(ctx, api.workers, getManifestSource, jobs, manifestJobID, composeJobTimeout, func(mf manifest.OSBuildManifest, depsolved map[string]depsolvednf.DepsolveResult) {
+		err := api.store.SetComposeManifest(composeID, mf, weldrtypes.RPMMDPackageListToDepsolvedPackageInfoList(imagePackages(depsolved)))
+		if err != nil {
+			log.Printf("error storing manifest of compose %s: %v", composeID, err)
+		}
+	})
+}
+
+// imagePackages returns the packages installed into the image.
+// TODO: introduce a way to query these from the manifest / image type
+// BUG: installer/container image types will have empty package sets
+func imagePackages(depsolved map[string]depsolvednf.DepsolveResult) rpmmd.PackageList {
+	var packages rpmmd.PackageList
+	if packages = depsolved["packages"].Transactions.AllPackages(); len(packages) == 0 {
+		if packages = depsolved["os"].Transactions.AllPackages(); len(packages) == 0 {
+			packages = depsolved["ostree-tree"].Transactions.AllPackages()
+		}
+	}
+	return packages
+}
+
+// composeLockfile creates a lockfile from the depsolve job the manifest of a
+// compose was serialized from
+func (api *API) composeLockfile(compose weldrtypes.Compose) (*lockfile.Lockfile, error) {
+	if compose.ImageBuild.JobID == uuid.Nil {
+		return nil, fmt.Errorf("compose wasn't built by a worker")
+	}
+
+	var result worker.DepsolveJobResult
+	_, err := api.workers.OSBuildJobDepsolveInfo(compose.ImageBuild.JobID, &result)
+	if errors.Is(err, worker.ErrNoDepsolveJob) {
+		return nil, fmt.Errorf("compose wasn't built from depsolved packages")
+	} else if err != nil {
+		return nil, err
+	}
+
+	depsolved, err := result.ToDepsolvednfResult()
+	if err != nil {
+		return nil, err
+	}
+
+	return lockfile.New(depsolved), nil
+}
+
+// composeDiff returns the changes from one compose to another one. The
+// repositories are only known for composes built from a depsolve job.
+func (api *API) composeDiff(from, to weldrtypes.Compose) (*composediff.Diff, error) {
+	var sources [2]composediff.Compose
+	for i, compose := range []weldrtypes.Compose{from, to} {
+		sources[i].Manifest = compose.ImageBuild.Manifest
+		if compose.Blueprint != nil {
+			sources[i].Customizations = compose.Blueprint.Customizations
+		}
+		for _, pkg := range compose.Packages {
+			sources[i].Packages = append(sources[i].Packages, composediff.Package{
+				Name:    pkg.Name,
+				Epoch:   pkg.Epoch,
+				Version: pkg.Version,
+				Release: pkg.Release,
+				Arch:    pkg.Arch,
+			})
+		}
+
+		if compose.ImageBuild.JobID == uuid.Nil {
+			continue
+		}
+		var result worker.DepsolveJobResult
+		_, err := api.workers.OSBuildJobDepsolveInfo(compose.ImageBuild.JobID, &result)
+		if errors.Is(err, worker.ErrNoDepsolveJob) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		depsolved, err := result.ToDepsolvednfResult()
+		if err != nil {
+			return nil, err
+		}
+		for name, res := range depsolved {
+			if name != "build" {
+				sources[i].Repositories = append(sources[i].Repositories, res.Repos...)
+			}
+		}
+	}
+
+	return composediff.Compare(sources[0], sources[1])
+}
+
+// composeJobStatuses returns the state of the manifest job an osbuild job
+// depends on and of the content resolve jobs it depends on in turn
+func (api *API) composeJobStatuses(osbuildJobInfo *worker.JobInfo) ([]ComposeJobStatus, error) {
+	var statuses []ComposeJobStatus
+	for _, manifestJobID := range osbuildJobInfo.Deps {
+		var manifestResult worker.JobResult
+		manifestInfo, err := api.workers.GenericJobInfo(manifestJobID, &manifestResult)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, id := range manifestInfo.Deps {
+			var result worker.JobResult
+			info, err := api.workers.GenericJobInfo(id, &result)
+			if err != nil {
+				return nil, err
+			}
+			statuses = append(statuses, ComposeJobStatus{info.JobType, jobState(info, &result)})
+		}
+		statuses = append(statuses, ComposeJobStatus{manifestInfo.JobType, jobState(manifestInfo, &manifestResult)})
+	}
+	return statuses, nil
+}
+
+func jobState(info *worker.JobInfo, result *worker.JobResult) common.ImageBuildState {
+	switch {
+	case info.JobStatus.Canceled || result.JobError != nil:
+		return common.IBFailed
+	case !info.JobStatus.Finished.IsZero():
+		return common.IBFinished
+	case !info.JobStatus.Started.IsZero():
+		return common.IBRunning
+	default:
+		return common.IBWaiting
+	}
+}
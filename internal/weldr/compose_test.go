@@ -3,15 +3,31 @@ package weldr
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"net/http"
 	"os"
+	"slices"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
+	"github.com/osbuild/image-builder/pkg/depsolvednf"
 	"github.com/osbuild/image-builder/pkg/distro"
 	"github.com/osbuild/image-builder/pkg/distro/test_distro"
+	"github.com/osbuild/image-builder/pkg/ostree"
+	"github.com/osbuild/image-builder/pkg/rpmmd"
+	"github.com/osbuild/image-builder/pkg/sbom"
+	"github.com/osbuild/osbuild-composer/internal/common"
+	"github.com/osbuild/osbuild-composer/internal/composediff"
+	"github.com/osbuild/osbuild-composer/internal/lockfile"
+	depsolvednf_mock "github.com/osbuild/osbuild-composer/internal/mocks/depsolvednf"
 	rpmmd_mock "github.com/osbuild/osbuild-composer/internal/mocks/rpmmd"
+	"github.com/osbuild/osbuild-composer/internal/store"
+	"github.com/osbuild/osbuild-composer/internal/test"
+	"github.com/osbuild/osbuild-composer/internal/weldrtypes"
 	"github.com/osbuild/osbuild-composer/internal/worker"
 	"github.com/osbuild/osbuild-composer/internal/worker/clienterrors"
 )
@@ -43,9 +59,9 @@ func TestComposeStatusFromLegacyError(t *testing.T) {
 		t.Fatalf("error serializing osbuild manifest: %v", err)
 	}
 
-	_, err = api.workers.RegisterWorker("", arch.Name())
+	_, err = api.workers.RegisterWorker("", arch.Name(), "", nil)
 	require.NoError(t, err)
-	jobId, err := api.workers.EnqueueOSBuild(arch.Name(), &worker.OSBuildJob{Manifest: mf}, "")
+	jobId, err := api.workers.EnqueueOSBuild(context.Background(), arch.Name(), &worker.OSBuildJob{Manifest: mf}, "")
 	require.NoError(t, err)
 
 	j, token, _, _, _, err := api.workers.RequestJob(context.Background(), arch.Name(), []string{worker.JobTypeOSBuild}, []string{""}, uuid.Nil)
@@ -99,9 +115,9 @@ func TestComposeStatusFromJobError(t *testing.T) {
 		t.Fatalf("error serializing osbuild manifest: %v", err)
 	}
 
-	_, err = api.workers.RegisterWorker("", arch.Name())
+	_, err = api.workers.RegisterWorker("", arch.Name(), "", nil)
 	require.NoError(t, err)
-	jobId, err := api.workers.EnqueueOSBuild(arch.Name(), &worker.OSBuildJob{Manifest: mf}, "")
+	jobId, err := api.workers.EnqueueOSBuild(context.Background(), arch.Name(), &worker.OSBuildJob{Manifest: mf}, "")
 	require.NoError(t, err)
 
 	j, token, _, _, _, err := api.workers.RequestJob(context.Background(), arch.Name(), []string{worker.JobTypeOSBuild}, []string{""}, uuid.Nil)
@@ -121,3 +137,424 @@ func TestComposeStatusFromJobError(t *testing.T) {
 	state := composeStateFromJobStatus(jobInfo.JobStatus, &jobResult)
 	require.Equal(t, "FAILED", state.ToString())
 }
+
+// serveResolveJobs acts as a worker for the depsolve and ostree resolve jobs
+// the manifest of a compose depends on, until the test is done. Depsolving
+// uses the solver of the API, the results are converted like the worker does.
+func serveResolveJobs(t *testing.T, api *API, arch string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			_, token, jobType, rawArgs, _, err := api.workers.RequestJob(ctx, arch, []string{worker.JobTypeDepsolve, worker.JobTypeOSTreeResolve}, []string{""}, uuid.Nil)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				continue
+			}
+
+			var result interface{}
+			switch jobType {
+			case worker.JobTypeDepsolve:
+				var args worker.DepsolveJob
+				require.NoError(t, json.Unmarshal(rawArgs, &args))
+				result = mockDepsolveJob(api, &args)
+			case worker.JobTypeOSTreeResolve:
+				var args worker.OSTreeResolveJob
+				require.NoError(t, json.Unmarshal(rawArgs, &args))
+				result = mockOSTreeResolveJob(&args)
+			}
+
+			rawResult, err := json.Marshal(result)
+			require.NoError(t, err)
+			require.NoError(t, api.workers.FinishJob(token, rawResult))
+		}
+	}()
+	t.Cleanup(func() {
+		cancel()
+		wg.Wait()
+	})
+}
+
+func mockDepsolveJob(api *API, args *worker.DepsolveJob) *worker.DepsolveJobResult {
+	result := &worker.DepsolveJobResult{
+		PackageSpecs: make(map[string]worker.DepsolvedPackageList),
+		Transactions: make(map[string][]worker.DepsolvedPackageList),
+		RepoConfigs:  make(map[string][]worker.DepsolvedRepoConfig),
+	}
+
+	solver := api.getSolver(args.ModulePlatformID, args.Releasever, args.Arch, "")
+	for name, pkgSet := range args.PackageSets {
+		res, err := solver.Depsolve(pkgSet, args.SbomType)
+		if err != nil {
+			result.JobError = clienterrors.New(clienterrors.ErrorDNFOtherError, "depsolve failed", err.Error())
+			return result
+		}
+
+		// the mocked results don't include the repositories the packages
+		// come from, which are needed to convert the results back
+		repos := res.Repos
+		for _, pkg := range res.Transactions.AllPackages() {
+			if !slices.ContainsFunc(repos, func(r rpmmd.RepoConfig) bool { return r.Id == pkg.RepoID }) {
+				repos = append(repos, rpmmd.RepoConfig{Id: pkg.RepoID})
+			}
+		}
+
+		if args.Lockfile != nil {
+			if err := args.Lockfile.Verify(name, res.Transactions); err != nil {
+				result.JobError = clienterrors.New(clienterrors.ErrorLockfileMismatch, "Depsolved packages don't match the lockfile", err.Error())
+				return result
+			}
+		}
+
+		// like the worker, fill in the package specs for older servers
+		result.PackageSpecs[name] = worker.DepsolvedPackageListFromRPMMDList(res.Transactions.AllPackages())
+		result.Transactions[name] = worker.DepsolvedTransactionsFromRPMMD(res.Transactions)
+		result.RepoConfigs[name] = worker.DepsolvedRepoConfigListFromRPMMDList(repos)
+	}
+	return result
+}
+
+func mockOSTreeResolveJob(args *worker.OSTreeResolveJob) *worker.OSTreeResolveJobResult {
+	result := &worker.OSTreeResolveJobResult{
+		Specs: make([]worker.OSTreeResolveResultSpec, len(args.Specs)),
+	}
+	for i, spec := range args.Specs {
+		commit, err := ostree.Resolve(ostree.SourceSpec{URL: spec.URL, Ref: spec.Ref, RHSM: spec.RHSM})
+		if err != nil {
+			result.JobError = clienterrors.New(clienterrors.ErrorOSTreeRefResolution, "ostree ref resolution failed", err.Error())
+			return result
+		}
+		result.Specs[i] = worker.OSTreeResolveResultSpec{
+			URL:      commit.URL,
+			Ref:      commit.Ref,
+			Checksum: commit.Checksum,
+			Secrets:  commit.Secrets,
+		}
+	}
+	return result
+}
+
+// waitForComposeJobs waits until none of the jobs preparing the manifest of
+// the only compose in the store is waiting or running anymore and returns
+// the compose and its status
+func waitForComposeJobs(t *testing.T, api *API, sf *store.Fixture) (weldrtypes.Compose, *composeStatus) {
+	composes := sf.Store.GetAllComposes()
+	require.Equal(t, 1, len(composes), "bad compose count in store")
+
+	var id uuid.UUID
+	for id = range composes {
+		break
+	}
+
+	return waitForComposeJobsByID(t, api, sf, id)
+}
+
+// waitForComposeJobsByID is like waitForComposeJobs for the compose with the
+// given id
+func waitForComposeJobsByID(t *testing.T, api *API, sf *store.Fixture, id uuid.UUID) (weldrtypes.Compose, *composeStatus) {
+	var compose weldrtypes.Compose
+	var status *composeStatus
+	require.Eventually(t, func() bool {
+		var exists bool
+		compose, exists = sf.Store.GetCompose(id)
+		if !exists {
+			return false
+		}
+		var err error
+		status, err = api.getComposeStatus(compose)
+		if err != nil || len(status.Jobs) == 0 {
+			return false
+		}
+		for _, job := range status.Jobs {
+			if job.Status == common.IBWaiting || job.Status == common.IBRunning {
+				return false
+			}
+		}
+		// the store is updated after the manifest job is finished
+		return compose.ImageBuild.Manifest != nil || status.Jobs[len(status.Jobs)-1].Status == common.IBFailed
+	}, 10*time.Second, 50*time.Millisecond)
+
+	return compose, status
+}
+
+func TestComposeJobs(t *testing.T) {
+	if len(os.Getenv("OSBUILD_COMPOSER_TEST_EXTERNAL")) > 0 {
+		t.Skip("This test is for internal testing only")
+	}
+
+	api, sf := createTestWeldrAPI(t.TempDir(), test_distro.TestDistro1Name, test_distro.TestArchName, getBaseMockDepsolveDNFSolverFn(testRepoID), rpmmd_mock.NoComposesFixture, nil)
+	t.Cleanup(sf.Cleanup)
+	_, err := api.workers.RegisterWorker("", test_distro.TestArchName, "", nil)
+	require.NoError(t, err)
+
+	body := fmt.Sprintf(`{"blueprint_name": "test","compose_type": "%s","branch": "master"}`, test_distro.TestImageTypeName)
+	test.TestRoute(t, api, false, "POST", "/api/v1/compose", body, http.StatusOK, `{"status": true}`, "build_id", "warnings")
+
+	// nothing is resolved before a worker picks up the depsolve job
+	composes := sf.Store.GetAllComposes()
+	require.Equal(t, 1, len(composes))
+	for _, compose := range composes {
+		require.Nil(t, compose.ImageBuild.Manifest)
+		status, err := api.getComposeStatus(compose)
+		require.NoError(t, err)
+		require.Equal(t, ComposeWaiting, status.State)
+		require.Equal(t, []ComposeJobStatus{
+			{worker.JobTypeDepsolve, common.IBWaiting},
+			{worker.JobTypeManifestIDOnly, common.IBWaiting},
+		}, status.Jobs)
+	}
+
+	serveResolveJobs(t, api, test_distro.TestArchName)
+	compose, status := waitForComposeJobs(t, api, sf)
+	require.NotNil(t, compose.ImageBuild.Manifest)
+	require.Equal(t, weldrtypes.RPMMDPackageListToDepsolvedPackageInfoList(depsolvednf_mock.BaseDepsolveResult(testRepoID)), compose.Packages)
+	require.Equal(t, []ComposeJobStatus{
+		{worker.JobTypeDepsolve, common.IBFinished},
+		{worker.JobTypeManifestIDOnly, common.IBFinished},
+	}, status.Jobs)
+
+	// the osbuild job gets the serialized manifest
+	_, _, jobType, _, dynArgs, err := api.workers.RequestJob(context.Background(), test_distro.TestArchName, []string{worker.JobTypeOSBuild}, []string{""}, uuid.Nil)
+	require.NoError(t, err)
+	require.Equal(t, worker.JobTypeOSBuild, jobType)
+	require.Len(t, dynArgs, 1)
+	var manifestResult worker.ManifestJobByIDResult
+	require.NoError(t, json.Unmarshal(dynArgs[0], &manifestResult))
+	require.Equal(t, compose.ImageBuild.Manifest, manifestResult.Manifest)
+}
+
+func TestComposeJobsDepsolveFailure(t *testing.T) {
+	if len(os.Getenv("OSBUILD_COMPOSER_TEST_EXTERNAL")) > 0 {
+		t.Skip("This test is for internal testing only")
+	}
+
+	solverFn := getMockDepsolveDNFSolverFn(&depsolvednf_mock.MockDepsolveDNF{DepsolveErr: depsolvednf_mock.DepsolveBadError})
+	api, sf := createTestWeldrAPI(t.TempDir(), test_distro.TestDistro1Name, test_distro.TestArchName, solverFn, rpmmd_mock.NoComposesFixture, nil)
+	t.Cleanup(sf.Cleanup)
+	_, err := api.workers.RegisterWorker("", test_distro.TestArchName, "", nil)
+	require.NoError(t, err)
+	serveResolveJobs(t, api, test_distro.TestArchName)
+
+	body := fmt.Sprintf(`{"blueprint_name": "test","compose_type": "%s","branch": "master"}`, test_distro.TestImageTypeName)
+	test.TestRoute(t, api, false, "POST", "/api/v1/compose", body, http.StatusOK, `{"status": true}`, "build_id", "warnings")
+
+	compose, status := waitForComposeJobs(t, api, sf)
+	require.Nil(t, compose.ImageBuild.Manifest)
+	require.Equal(t, []ComposeJobStatus{
+		{worker.JobTypeDepsolve, common.IBFailed},
+		{worker.JobTypeManifestIDOnly, common.IBFailed},
+	}, status.Jobs)
+}
+
+// transactionSolver depsolves each package set into a transaction of its own,
+// containing the packages of the base depsolve result which are included by
+// name or NEVRA
+type transactionSolver struct {
+	depsolvednf_mock.MockDepsolveDNF
+}
+
+func (s *transactionSolver) Depsolve(pkgSets []rpmmd.PackageSet, sbomType sbom.StandardType) (*depsolvednf.DepsolveResult, error) {
+	var transactions depsolvednf.TransactionList
+	for _, pkgSet := range pkgSets {
+		transaction := rpmmd.PackageList{}
+		for _, pkg := range depsolvednf_mock.BaseDepsolveResult(testRepoID) {
+			if slices.Contains(pkgSet.Include, pkg.Name) || slices.Contains(pkgSet.Include, pkg.Name+"-"+pkg.EVRA()) {
+				transaction = append(transaction, pkg)
+			}
+		}
+		transactions = append(transactions, transaction)
+	}
+	return &depsolvednf.DepsolveResult{Transactions: transactions}, nil
+}
+
+// postCompose submits a compose of the test blueprint and returns its id
+func postCompose(t *testing.T, api *API, lf *lockfile.Lockfile) uuid.UUID {
+	cr := map[string]interface{}{
+		"blueprint_name": "test",
+		"compose_type":   test_distro.TestImageTypeName,
+		"branch":         "master",
+	}
+	if lf != nil {
+		cr["lockfile"] = lf
+	}
+	body, err := json.Marshal(cr)
+	require.NoError(t, err)
+
+	reply := test.TestRouteWithReply(t, api, false, "POST", "/api/v1/compose", string(body), http.StatusOK, `{"status": true}`, "build_id", "warnings")
+	var composeReply struct {
+		BuildID uuid.UUID `json:"build_id"`
+	}
+	require.NoError(t, json.Unmarshal(reply, &composeReply))
+	return composeReply.BuildID
+}
+
+func TestComposeLockfile(t *testing.T) {
+	if len(os.Getenv("OSBUILD_COMPOSER_TEST_EXTERNAL")) > 0 {
+		t.Skip("This test is for internal testing only")
+	}
+
+	solverFn := func(modulePlatformID, releaseVer, arch, distro string) Solver {
+		return &transactionSolver{}
+	}
+	api, sf := createTestWeldrAPI(t.TempDir(), test_distro.TestDistro1Name, test_distro.TestArchName, solverFn, rpmmd_mock.NoComposesFixture, nil)
+	t.Cleanup(sf.Cleanup)
+	_, err := api.workers.RegisterWorker("", test_distro.TestArchName, "", nil)
+	require.NoError(t, err)
+	serveResolveJobs(t, api, test_distro.TestArchName)
+
+	id := postCompose(t, api, nil)
+	waitForComposeJobsByID(t, api, sf, id)
+
+	lockfilePath := "/api/v1/compose/lockfile/" + id.String()
+	test.TestRoute(t, api, false, "GET", lockfilePath, "", http.StatusBadRequest,
+		fmt.Sprintf(`{"status":false,"errors":[{"id":"BuildInWrongState","msg":"Build %s is in wrong state: WAITING"}]}`, id))
+
+	_, token, _, _, _, err := api.workers.RequestJob(context.Background(), test_distro.TestArchName, []string{worker.JobTypeOSBuild}, []string{""}, uuid.Nil)
+	require.NoError(t, err)
+	rawResult, err := json.Marshal(worker.OSBuildJobResult{Success: true})
+	require.NoError(t, err)
+	require.NoError(t, api.workers.FinishJob(token, rawResult))
+
+	resp := test.SendHTTP(api, false, "GET", lockfilePath, "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var lf lockfile.Lockfile
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&lf))
+	require.NoError(t, lf.Validate())
+	require.Len(t, lf.Pipelines["build"], 1)
+	require.Len(t, lf.Pipelines["build"][0], 3)
+	require.Len(t, lf.Pipelines["os"], 2)
+	require.Len(t, lf.Pipelines["os"][0], 0)
+	require.Len(t, lf.Pipelines["os"][1], 3)
+
+	t.Run("pinned", func(t *testing.T) {
+		id := postCompose(t, api, &lf)
+		compose, status := waitForComposeJobsByID(t, api, sf, id)
+		require.NotNil(t, compose.ImageBuild.Manifest)
+		require.Equal(t, weldrtypes.RPMMDPackageListToDepsolvedPackageInfoList(depsolvednf_mock.BaseDepsolveResult(testRepoID)), compose.Packages)
+		require.Equal(t, []ComposeJobStatus{
+			{worker.JobTypeDepsolve, common.IBFinished},
+			{worker.JobTypeManifestIDOnly, common.IBFinished},
+		}, status.Jobs)
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		var changed lockfile.Lockfile
+		raw, err := json.Marshal(lf)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(raw, &changed))
+		changed.Pipelines["os"][1][0].Checksum = "sha256:0000"
+
+		id := postCompose(t, api, &changed)
+		compose, status := waitForComposeJobsByID(t, api, sf, id)
+		require.Nil(t, compose.ImageBuild.Manifest)
+		require.Equal(t, []ComposeJobStatus{
+			{worker.JobTypeDepsolve, common.IBFailed},
+			{worker.JobTypeManifestIDOnly, common.IBFailed},
+		}, status.Jobs)
+
+		var result worker.DepsolveJobResult
+		_, err = api.workers.OSBuildJobDepsolveInfo(compose.ImageBuild.JobID, &result)
+		require.NoError(t, err)
+		require.NotNil(t, result.JobError)
+		require.Equal(t, clienterrors.ErrorLockfileMismatch, result.JobError.ID)
+	})
+
+	t.Run("missing-pipeline", func(t *testing.T) {
+		partial := lockfile.Lockfile{
+			Version:   lockfile.Version,
+			Pipelines: map[string][][]lockfile.Package{"os": lf.Pipelines["os"]},
+		}
+		body, err := json.Marshal(map[string]interface{}{
+			"blueprint_name": "test",
+			"compose_type":   test_distro.TestImageTypeName,
+			"branch":         "master",
+			"lockfile":       partial,
+		})
+		require.NoError(t, err)
+		test.TestRoute(t, api, false, "POST", "/api/v1/compose", string(body), http.StatusBadRequest,
+			`{"status":false,"errors":[{"id":"LockfileError","msg":"lockfile has no packages for pipeline \"build\""}]}`)
+	})
+}
+
+func TestComposeLockfileTestCompose(t *testing.T) {
+	if len(os.Getenv("OSBUILD_COMPOSER_TEST_EXTERNAL")) > 0 {
+		t.Skip("This test is for internal testing only")
+	}
+
+	api, sf := createTestWeldrAPI(t.TempDir(), test_distro.TestDistro1Name, test_distro.TestArchName, getBaseMockDepsolveDNFSolverFn(testRepoID), rpmmd_mock.NoComposesFixture, nil)
+	t.Cleanup(sf.Cleanup)
+	_, err := api.workers.RegisterWorker("", test_distro.TestArchName, "", nil)
+	require.NoError(t, err)
+
+	body := fmt.Sprintf(`{"blueprint_name": "test","compose_type": "%s","branch": "master"}`, test_distro.TestImageTypeName)
+	reply := test.TestRouteWithReply(t, api, false, "POST", "/api/v1/compose?test=2", body, http.StatusOK, `{"status": true}`, "build_id", "warnings")
+	var composeReply struct {
+		BuildID uuid.UUID `json:"build_id"`
+	}
+	require.NoError(t, json.Unmarshal(reply, &composeReply))
+
+	test.TestRoute(t, api, false, "GET", "/api/v1/compose/lockfile/"+composeReply.BuildID.String(), "", http.StatusBadRequest,
+		fmt.Sprintf(`{"status":false,"errors":[{"id":"LockfileError","msg":"Error creating lockfile of compose %s: compose wasn't built by a worker"}]}`, composeReply.BuildID))
+	test.TestRoute(t, api, false, "GET", "/api/v1/compose/lockfile/badid", "", http.StatusBadRequest,
+		`{"status":false,"errors":[{"id":"UnknownUUID","msg":"badid is not a valid build uuid"}]}`)
+}
+
+func TestComposeDiff(t *testing.T) {
+	if len(os.Getenv("OSBUILD_COMPOSER_TEST_EXTERNAL")) > 0 {
+		t.Skip("This test is for internal testing only")
+	}
+
+	api, sf := createTestWeldrAPI(t.TempDir(), test_distro.TestDistro1Name, test_distro.TestArchName, getBaseMockDepsolveDNFSolverFn(testRepoID), rpmmd_mock.NoComposesFixture, nil)
+	t.Cleanup(sf.Cleanup)
+	_, err := api.workers.RegisterWorker("", test_distro.TestArchName, "", nil)
+	require.NoError(t, err)
+	serveResolveJobs(t, api, test_distro.TestArchName)
+
+	var ids []uuid.UUID
+	for _, hostname := range []string{"host-a", "host-b"} {
+		bp := fmt.Sprintf(`{"name":"test","description":"Test","packages":[],"version":"0.0.1","customizations":{"hostname":"%s"}}`, hostname)
+		test.TestRoute(t, api, false, "POST", "/api/v1/blueprints/new", bp, http.StatusOK, `{"status":true}`)
+		id := postCompose(t, api, nil)
+		waitForComposeJobsByID(t, api, sf, id)
+		ids = append(ids, id)
+	}
+
+	diffPath := fmt.Sprintf("/api/v1/compose/diff/%s/%s", ids[0], ids[1])
+	test.TestRoute(t, api, false, "GET", diffPath, "", http.StatusBadRequest,
+		fmt.Sprintf(`{"status":false,"errors":[{"id":"BuildInWrongState","msg":"Build %s is in wrong state: WAITING"}]}`, ids[0]))
+
+	for range ids {
+		_, token, _, _, _, err := api.workers.RequestJob(context.Background(), test_distro.TestArchName, []string{worker.JobTypeOSBuild}, []string{""}, uuid.Nil)
+		require.NoError(t, err)
+		rawResult, err := json.Marshal(worker.OSBuildJobResult{Success: true})
+		require.NoError(t, err)
+		require.NoError(t, api.workers.FinishJob(token, rawResult))
+	}
+
+	resp := test.SendHTTP(api, false, "GET", diffPath, "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var reply struct {
+		Diff composediff.Diff `json:"diff"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&reply))
+	require.Equal(t, composediff.PackageDiff{
+		Added:      []composediff.PackageVersion{},
+		Removed:    []composediff.PackageVersion{},
+		Upgraded:   []composediff.PackageChange{},
+		Downgraded: []composediff.PackageChange{},
+	}, reply.Diff.Packages)
+	require.Equal(t, composediff.RepositoryDiff{Added: []string{}, Removed: []string{}}, reply.Diff.Repositories)
+	require.Equal(t, []composediff.CustomizationChange{
+		{Name: "hostname", Old: json.RawMessage(`"host-a"`), New: json.RawMessage(`"host-b"`)},
+	}, reply.Diff.Customizations)
+
+	test.TestRoute(t, api, false, "GET", "/api/v1/compose/diff/badid/"+ids[1].String(), "", http.StatusBadRequest,
+		`{"status":false,"errors":[{"id":"UnknownUUID","msg":"badid is not a valid build uuid"}]}`)
+	missing := uuid.New()
+	test.TestRoute(t, api, false, "GET", fmt.Sprintf("/api/v1/compose/diff/%s/%s", ids[0], missing), "", http.StatusBadRequest,
+		fmt.Sprintf(`{"status":false,"errors":[{"id":"UnknownUUID","msg":"Compose %s doesn't exist"}]}`, missing))
+}
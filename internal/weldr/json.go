@@ -197,6 +197,7 @@ func NewSourceConfigV1(id string, s store.SourceConfig) SourceConfigV1 {
 	sc.RHSM = s.RHSM
 	sc.CheckRepoGPG = s.CheckRepoGPG
 	sc.GPGKeys = s.GPGKeys
+	sc.Health = s.Health
 
 	return sc
 }
@@ -216,6 +217,9 @@ type SourceConfigV1 struct {
 	RHSM           bool     `json:"rhsm" toml:"rhsm"`
 	CheckRepoGPG   bool     `json:"check_repogpg" toml:"check_repogpg"`
 	ModuleHotfixes *bool    `json:"module_hotfixes,omitempty" toml:"module_hotfixes,omitempty"`
+
+	// Health is only returned by the API, it is ignored in new sources
+	Health *store.SourceHealth `json:"health,omitempty" toml:"-"`
 }
 
 // Key returns the key, .ID in this case
@@ -255,6 +259,12 @@ type SourceInfoResponseV1 struct {
 	Errors  []responseError           `json:"errors"`
 }
 
+// SourceCheckResponse is the response to a projects/source/check request
+type SourceCheckResponse struct {
+	Sources map[string]store.SourceHealth `json:"sources"`
+	Errors  []responseError               `json:"errors"`
+}
+
 type PackageSource struct {
 	License   string   `json:"license"`
 	Version   string   `json:"version"`
@@ -0,0 +1,204 @@
+package worker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+
+	"github.com/osbuild/osbuild-composer/pkg/jobqueue"
+
+	"github.com/osbuild/osbuild-composer/internal/audit"
+	"github.com/osbuild/osbuild-composer/internal/auth"
+	"github.com/osbuild/osbuild-composer/internal/worker/api"
+	"github.com/osbuild/osbuild-composer/internal/worker/clienterrors"
+)
+
+// Workers returns all registered workers
+func (s *Server) Workers() ([]jobqueue.Worker, error) {
+	return s.jobs.Workers(0)
+}
+
+// DrainWorker stops handing out new jobs to the worker
+func (s *Server) DrainWorker(id uuid.UUID) error {
+	err := s.jobs.DrainWorker(id)
+	if err != nil {
+		return err
+	}
+	logrus.Infof("Draining worker %s", id)
+	return nil
+}
+
+// EvictWorker removes the worker and requeues the jobs it is running. Jobs
+// which were already retried too often are failed instead, like the ones of
+// workers which stopped responding.
+func (s *Server) EvictWorker(id uuid.UUID) error {
+	// drain the worker first, so it doesn't get new jobs while the ones it
+	// runs are requeued
+	err := s.jobs.DrainWorker(id)
+	if err != nil {
+		return err
+	}
+
+	w, err := s.worker(id)
+	if err != nil {
+		return err
+	}
+
+	evictedResult, err := json.Marshal(JobResult{
+		JobError: clienterrors.New(clienterrors.ErrorWorkerEvicted,
+			fmt.Sprintf("The worker running this job was evicted after the job was already retried %d times.", maxHeartbeatRetries),
+			nil),
+	})
+	if err != nil {
+		return fmt.Errorf("cannot marshal the eviction error: %v", err)
+	}
+
+	for _, token := range w.Tokens {
+		jobID, err := s.jobs.IdFromToken(token)
+		if err != nil {
+			// the job finished in the meantime
+			continue
+		}
+		logrus.Infof("Requeueing job %s of evicted worker %s", jobID, id)
+		err = s.RequeueOrFinishJob(token, maxHeartbeatRetries, evictedResult)
+		if err != nil && !errors.Is(err, ErrInvalidToken) && !errors.Is(err, ErrJobNotRunning) {
+			return fmt.Errorf("error requeueing job %s: %w", jobID, err)
+		}
+	}
+
+	err = s.jobs.DeleteWorker(id)
+	if err != nil {
+		return err
+	}
+	logrus.Infof("Evicted worker %s", id)
+	return nil
+}
+
+func (s *Server) worker(id uuid.UUID) (*jobqueue.Worker, error) {
+	workers, err := s.Workers()
+	if err != nil {
+		return nil, err
+	}
+	for i := range workers {
+		if workers[i].ID == id {
+			return &workers[i], nil
+		}
+	}
+	return nil, jobqueue.ErrWorkerNotExist
+}
+
+// auditAdmin records the calls of the mutating admin routes in the audit log
+func (s *Server) auditAdmin(next echo.HandlerFunc) echo.HandlerFunc {
+	audited := audit.Middleware(s.config.Audit, "worker", func(ctx echo.Context) string {
+		return auth.CallerIdentity(ctx.Request(), s.config.AdminJWTUserFields)
+	})(next)
+	return func(ctx echo.Context) error {
+		if strings.HasPrefix(ctx.Path(), api.BasePath+"/admin/") {
+			return audited(ctx)
+		}
+		return next(ctx)
+	}
+}
+
+// isAdmin returns whether the caller of the request is one of the configured
+// admin users
+func (s *Server) isAdmin(request *http.Request) bool {
+	user := auth.CallerIdentity(request, s.config.AdminJWTUserFields)
+	return user != "" && slices.Contains(s.config.AdminUsers, user)
+}
+
+func (s *Server) apiWorker(w *jobqueue.Worker) api.Worker {
+	jobs := []uuid.UUID{}
+	for _, token := range w.Tokens {
+		id, err := s.jobs.IdFromToken(token)
+		if err != nil {
+			// the job finished in the meantime
+			continue
+		}
+		jobs = append(jobs, id)
+	}
+
+	var capabilities *[]string
+	if w.Capabilities != nil {
+		capabilities = &w.Capabilities
+	}
+
+	return api.Worker{
+		Href:          fmt.Sprintf("%s/admin/workers/%v", api.BasePath, w.ID),
+		Id:            w.ID.String(),
+		Kind:          "Worker",
+		Channel:       w.Channel,
+		Arch:          w.Arch,
+		Version:       w.Version,
+		Capabilities:  capabilities,
+		LastHeartbeat: w.Heartbeat,
+		Draining:      w.Draining,
+		Jobs:          jobs,
+	}
+}
+
+func (h *apiHandlers) GetAdminWorkers(ctx echo.Context) error {
+	if !h.server.isAdmin(ctx.Request()) {
+		return api.HTTPError(api.ErrorNotAdmin)
+	}
+
+	workers, err := h.server.Workers()
+	if err != nil {
+		return api.HTTPErrorWithInternal(api.ErrorListingWorkers, err)
+	}
+
+	list := api.WorkerList{
+		Items: []api.Worker{},
+	}
+	for i := range workers {
+		list.Items = append(list.Items, h.server.apiWorker(&workers[i]))
+	}
+	return ctx.JSON(http.StatusOK, list)
+}
+
+func (h *apiHandlers) PostAdminWorkerDrain(ctx echo.Context, workerID uuid.UUID) error {
+	audit.SetAction(ctx.Request().Context(), "postAdminWorkerDrain")
+	if !h.server.isAdmin(ctx.Request()) {
+		return api.HTTPError(api.ErrorNotAdmin)
+	}
+
+	err := h.server.DrainWorker(workerID)
+	if err == jobqueue.ErrWorkerNotExist {
+		return api.HTTPErrorWithInternal(api.ErrorWorkerIdNotFound, err)
+	}
+	if err != nil {
+		return api.HTTPErrorWithInternal(api.ErrorDrainingWorker, err)
+	}
+
+	w, err := h.server.worker(workerID)
+	if err == jobqueue.ErrWorkerNotExist {
+		return api.HTTPErrorWithInternal(api.ErrorWorkerIdNotFound, err)
+	}
+	if err != nil {
+		return api.HTTPErrorWithInternal(api.ErrorListingWorkers, err)
+	}
+	return ctx.JSON(http.StatusOK, h.server.apiWorker(w))
+}
+
+func (h *apiHandlers) DeleteAdminWorker(ctx echo.Context, workerID uuid.UUID) error {
+	audit.SetAction(ctx.Request().Context(), "deleteAdminWorker")
+	if !h.server.isAdmin(ctx.Request()) {
+		return api.HTTPError(api.ErrorNotAdmin)
+	}
+
+	err := h.server.EvictWorker(workerID)
+	if err == jobqueue.ErrWorkerNotExist {
+		return api.HTTPErrorWithInternal(api.ErrorWorkerIdNotFound, err)
+	}
+	if err != nil {
+		return api.HTTPErrorWithInternal(api.ErrorEvictingWorker, err)
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
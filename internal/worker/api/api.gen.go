@@ -13,6 +13,7 @@ import (
 	"net/url"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/labstack/echo/v4"
@@ -37,6 +38,14 @@ type Error struct {
 	Reason      string `json:"reason"`
 }
 
+// GetJobCloudCredentialsResponse defines model for GetJobCloudCredentialsResponse.
+type GetJobCloudCredentialsResponse struct {
+	Credentials json.RawMessage `json:"credentials"`
+	Href        string          `json:"href"`
+	Id          string          `json:"id"`
+	Kind        string          `json:"kind"`
+}
+
 // GetJobResponse defines model for GetJobResponse.
 type GetJobResponse struct {
 	Canceled bool   `json:"canceled"`
@@ -45,6 +54,14 @@ type GetJobResponse struct {
 	Kind     string `json:"kind"`
 }
 
+// GetJobSecretResponse defines model for GetJobSecretResponse.
+type GetJobSecretResponse struct {
+	Href   string `json:"href"`
+	Id     string `json:"id"`
+	Kind   string `json:"kind"`
+	Secret string `json:"secret"`
+}
+
 // ObjectReference defines model for ObjectReference.
 type ObjectReference struct {
 	Href string `json:"href"`
@@ -55,6 +72,16 @@ type ObjectReference struct {
 // PostWorkersRequest defines model for PostWorkersRequest.
 type PostWorkersRequest struct {
 	Arch string `json:"arch"`
+
+	// Capabilities What the worker can do, for example the upload targets it has
+	// credentials for, its osbuild executor and its labels. Jobs
+	// requiring capabilities the worker doesn't have are not handed
+	// out to it. Workers which don't advertise their capabilities can
+	// run any job.
+	Capabilities *[]string `json:"capabilities,omitempty"`
+
+	// Version Version of osbuild-composer the worker was built from
+	Version *string `json:"version,omitempty"`
 }
 
 // PostWorkersResponse defines model for PostWorkersResponse.
@@ -110,6 +137,31 @@ type UpdateJobResult struct {
 	Result json.RawMessage `json:"result"`
 }
 
+// Worker defines model for Worker.
+type Worker struct {
+	Arch string `json:"arch"`
+
+	// Capabilities Not set for workers which didn't advertise their capabilities
+	Capabilities *[]string `json:"capabilities,omitempty"`
+	Channel      string    `json:"channel"`
+	Draining     bool      `json:"draining"`
+	Href         string    `json:"href"`
+	Id           string    `json:"id"`
+
+	// Jobs The jobs the worker is currently running
+	Jobs          []openapi_types.UUID `json:"jobs"`
+	Kind          string               `json:"kind"`
+	LastHeartbeat time.Time            `json:"last_heartbeat"`
+
+	// Version Empty for workers which didn't report their version
+	Version string `json:"version"`
+}
+
+// WorkerList defines model for WorkerList.
+type WorkerList struct {
+	Items []Worker `json:"items"`
+}
+
 // RequestJobJSONRequestBody defines body for RequestJob for application/json ContentType.
 type RequestJobJSONRequestBody = RequestJobRequest
 
@@ -183,6 +235,15 @@ func (t *UpdateJobRequest) UnmarshalJSON(b []byte) error {
 
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
+	// List the registered workers
+	// (GET /admin/workers)
+	GetAdminWorkers(ctx echo.Context) error
+	// Evict a worker
+	// (DELETE /admin/workers/{worker_id})
+	DeleteAdminWorker(ctx echo.Context, workerId openapi_types.UUID) error
+	// Drain a worker
+	// (POST /admin/workers/{worker_id}/drain)
+	PostAdminWorkerDrain(ctx echo.Context, workerId openapi_types.UUID) error
 	// Get error description
 	// (GET /errors/{id})
 	GetError(ctx echo.Context, id string) error
@@ -198,6 +259,12 @@ type ServerInterface interface {
 	// Upload an artifact
 	// (PUT /jobs/{token}/artifacts/{name})
 	UploadJobArtifact(ctx echo.Context, token string, name string) error
+	// Get cloud credentials of the tenant of a running job
+	// (GET /jobs/{token}/cloud-credentials/{id})
+	GetJobCloudCredentials(ctx echo.Context, token string, id openapi_types.UUID) error
+	// Get a secret of a running job
+	// (GET /jobs/{token}/secrets/{id})
+	GetJobSecret(ctx echo.Context, token string, id openapi_types.UUID) error
 	// Get the openapi spec in json format
 	// (GET /openapi)
 	GetOpenapi(ctx echo.Context) error
@@ -207,6 +274,9 @@ type ServerInterface interface {
 	// Create a new worker
 	// (POST /workers)
 	PostWorkers(ctx echo.Context) error
+	// Deregister a worker
+	// (DELETE /workers/{worker_id})
+	DeleteWorker(ctx echo.Context, workerId openapi_types.UUID) error
 	// Refresh worker status
 	// (POST /workers/{worker_id}/status)
 	PostWorkerStatus(ctx echo.Context, workerId openapi_types.UUID) error
@@ -217,6 +287,47 @@ type ServerInterfaceWrapper struct {
 	Handler ServerInterface
 }
 
+// GetAdminWorkers converts echo context to params.
+func (w *ServerInterfaceWrapper) GetAdminWorkers(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetAdminWorkers(ctx)
+	return err
+}
+
+// DeleteAdminWorker converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteAdminWorker(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "worker_id" -------------
+	var workerId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "worker_id", ctx.Param("worker_id"), &workerId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter worker_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteAdminWorker(ctx, workerId)
+	return err
+}
+
+// PostAdminWorkerDrain converts echo context to params.
+func (w *ServerInterfaceWrapper) PostAdminWorkerDrain(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "worker_id" -------------
+	var workerId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "worker_id", ctx.Param("worker_id"), &workerId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter worker_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostAdminWorkerDrain(ctx, workerId)
+	return err
+}
+
 // GetError converts echo context to params.
 func (w *ServerInterfaceWrapper) GetError(ctx echo.Context) error {
 	var err error
@@ -300,6 +411,54 @@ func (w *ServerInterfaceWrapper) UploadJobArtifact(ctx echo.Context) error {
 	return err
 }
 
+// GetJobCloudCredentials converts echo context to params.
+func (w *ServerInterfaceWrapper) GetJobCloudCredentials(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "token" -------------
+	var token string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "token", ctx.Param("token"), &token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: ""})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter token: %s", err))
+	}
+
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetJobCloudCredentials(ctx, token, id)
+	return err
+}
+
+// GetJobSecret converts echo context to params.
+func (w *ServerInterfaceWrapper) GetJobSecret(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "token" -------------
+	var token string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "token", ctx.Param("token"), &token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: ""})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter token: %s", err))
+	}
+
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetJobSecret(ctx, token, id)
+	return err
+}
+
 // GetOpenapi converts echo context to params.
 func (w *ServerInterfaceWrapper) GetOpenapi(ctx echo.Context) error {
 	var err error
@@ -327,6 +486,22 @@ func (w *ServerInterfaceWrapper) PostWorkers(ctx echo.Context) error {
 	return err
 }
 
+// DeleteWorker converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteWorker(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "worker_id" -------------
+	var workerId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "worker_id", ctx.Param("worker_id"), &workerId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter worker_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteWorker(ctx, workerId)
+	return err
+}
+
 // PostWorkerStatus converts echo context to params.
 func (w *ServerInterfaceWrapper) PostWorkerStatus(ctx echo.Context) error {
 	var err error
@@ -371,14 +546,20 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 		Handler: si,
 	}
 
+	router.GET(baseURL+"/admin/workers", wrapper.GetAdminWorkers)
+	router.DELETE(baseURL+"/admin/workers/:worker_id", wrapper.DeleteAdminWorker)
+	router.POST(baseURL+"/admin/workers/:worker_id/drain", wrapper.PostAdminWorkerDrain)
 	router.GET(baseURL+"/errors/:id", wrapper.GetError)
 	router.POST(baseURL+"/jobs", wrapper.RequestJob)
 	router.GET(baseURL+"/jobs/:token", wrapper.GetJob)
 	router.PATCH(baseURL+"/jobs/:token", wrapper.UpdateJob)
 	router.PUT(baseURL+"/jobs/:token/artifacts/:name", wrapper.UploadJobArtifact)
+	router.GET(baseURL+"/jobs/:token/cloud-credentials/:id", wrapper.GetJobCloudCredentials)
+	router.GET(baseURL+"/jobs/:token/secrets/:id", wrapper.GetJobSecret)
 	router.GET(baseURL+"/openapi", wrapper.GetOpenapi)
 	router.GET(baseURL+"/status", wrapper.GetStatus)
 	router.POST(baseURL+"/workers", wrapper.PostWorkers)
+	router.DELETE(baseURL+"/workers/:worker_id", wrapper.DeleteWorker)
 	router.POST(baseURL+"/workers/:worker_id/status", wrapper.PostWorkerStatus)
 
 }
@@ -386,32 +567,47 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/9xYX2/bOBL/KgTvgLsDFNtpei8G9qHpLop00U2RbLEFmqAYSWOLCUWq5DCuYfi7L0hK",
-	"si3JTgLEwLZPsqXh/PnNj8MZrnimy0orVGT5dMVtVmAJ4edvxmjjf4CUlzM+/bLi/zY441P+r/Fm0bhe",
-	"Mb5M7zCjK5yhQZUhXycrXhldoSGBQWGmc/RPWlbIp9ySEWrO1wkv0VqYh2852syIioRWfMrPIbtfgMmZ",
-	"twckUiEFLdlCUMEW2tyjsezGTSZn2S/s4ewsYfjNgbTMIFiteNI35f0Br/2ryAd9qZf2P4Vv35wwmPPp",
-	"lxhMK95RvAnptvVBB3z4+nad8HdI73V6hbbSyuKLYgwqQ4nbsaVaSwTVj6ARHfaxa2vaNVUERwcg3IPs",
-	"vVD547gG9IJoEi30vUv4R23pr5j/K/zm0FLfPTBZ8bi5IPWoiZfPU2RvTcKZNiUQn3LnQviHXd4sHU5c",
-	"jUgg2POwieqChCAs7V4RPuVgDCz9/51IDnsetSf7Qd92/eUxBzMPz+8nc31S276zWo2uYPGh3q9r7x2J",
-	"GWT0VeoMYiEagCFfKihF9rVR2gL2iPYufAeNxBdPgZVvaRoKYZgq1wTkjsJvGzQ/7nstN+zepyoHwvc6",
-	"/egDAtkncrX5cBD3jtVm2RAHW6Nbu0crfAIqWyutkxRQeZJ8E95u0Ntp+cdU3m6MPd9M+/45CalX9S16",
-	"QaFmut8b/FkIy4RloNibjxdspk3bEpBmJiaPgcpZASqXyO50ake+ugqS3sTl9bkTMmdvfWYsGnbCYsHn",
-	"CX9AY6OZ07prUFAJPuVno8lowhNeARUh3jH6NsmOVyJf+/9zpL6v79B7woSy5A9dpmeMCmRhKbMVZmIm",
-	"MGfpkoUktL3ERR4Xx1bMWzVQIqGxgYy7Ri5+3dHLPXB8GjzlCVdQ+qCD/g3yZBwmddPn3cbvUFYBndOz",
-	"gYPo1q+NrAzBv5pMYmOnCFWIG6pKilhzxnd1I7VRf2g3xBjXIeOvP38+it7/H0XvOuEWM2cELUNazhEM",
-	"Gj79cusBs64swSxrFsSUbyfOLx97boa9pO0AfepKZBl4Eo9YoH5LEpZKnd1b5hQJGUXCvngAISGVOOox",
-	"anPM1mRAS+c6X74YNv0WJMLUIc/pUQzWVTMY3MXxrUEgzP2OfjV5/WLGe6dh3/IfOqRlAVt5SRiZJYM5",
-	"CMV/NM534wss3jD9qqm+PuoNw8cr0veotutkr9Q1pDxSlelMXgOhXP7Of8gKtFNmjFNKqHmEv3duDJwL",
-	"ITEHj4aBs6ACinPEbhbbHuFI1aXXoQ0Wl8kx7P3EtIlRMtjlTnfrjpvRwo5XnjphL1eOhlggNeTvdfqm",
-	"XsGfwsPweA4Nk5ej89O4qjNCOrFkEMpd0Lsq95HypyOOT7TvbxtuRNq0TfP+Yn9ZizwFp1pdaJeZUMz7",
-	"zuqbEx/oMVrR7ib/pPB7hRlhXjdyOsuc8fzql2DfiB/02WO0GZMH54Zr4btxFqXqOcawRSGyghkkZ5Rl",
-	"Fs2DyBqhoenhuvlytArZuUf4GctjDW/IWj1n7m/YY6Pp+3WFi3osDaNokzRgzom8zqQttJM5S5E5i7nn",
-	"CUjJrEutL0iKWAZS2tGN6iV365LySGftwE3rkVv5oYvX/b38DsSdXRhF+hJN/sar9u5yvbUTHz+kNlee",
-	"h06Yx251fQO1Z9ybGbQF2lBDCgRDKQI10320ngQ+eQU2bH0vDJ4qqiUSaZb7OEqhkOkHNCDljarZWCBI",
-	"Kkb1lUe9OtdMaWIxtzlbCCnDixTZPVbEyEB27/2AGXlGMxIlakcjdjG7UbnRVYV5ew2zQINtNxFGgYSR",
-	"H13DKOR1p+htERjC/DC/D9awXfisyzK0MyflkrnQ1DQu/ceyrW28Pa4EwJuN2sqEwd48DF+3fACh2H8r",
-	"o3OX+Vf/Y1GWJ9wZyae8IKrsdDyGSoz8QWALMaNRpkv/ZixKmONJ6oTM0ZxEy+OH03Bp2DkECOYewQPq",
-	"LcEcn2kkanmO2NaH2/XfAQAA///1vRKCORwAAA==",
+	"H4sIAAAAAAAC/+xa3W/byBH/VwbbAtcCtOQ01xcBfUhyh0PS3jlwes0BkRGMyKG4MbnL7A6lqIb/92I/",
+	"SFEiLdmB1auDPsURd2fn4zcfO7M3ItVVrRUptmJ2I2xaUIX+zx+N0cb9gWV5kYvZhxvxR0O5mIk/TLeb",
+	"pnHH9GLxiVK+pJwMqZTEbXIjaqNrMizJE0x1Ru5f3tQkZsKykWopbhNRkbW49N8ysqmRNUutxEy8xPR6",
+	"jSYDdx6yXMhS8gbWkgtYa3NNxsK8OT9/nv4NVs+fJ0CfGywtGEKrlUiGRzl+0FH/KLNRXuLW4Sf/7XMj",
+	"DWVi9iEI0y3fI7wV6arjQXv9iNur20T8RPxGL16VusleGcpIscTSXpKttbL0qDrfknf//XK21GeRoU9W",
+	"q8klrn+OrA4k7G09JMZJ2EaVUkl9Ey20LgnVkM126SEe31FqiE/BqfWUj+Mlrhtncv+Y2f4phedxBK53",
+	"oPhaquw4Tx6pfmkSThhyl4i32vL74GuX9Lkhy0P20KTFKB8p1uidNi7cde/3BTJwQdGXIUUFmU4g1wbo",
+	"C1Z1Sf5zU5caM2A0S2ILkqFAO1c9gLotCUi2oO2ikWUG9IXShrUBVJn/UOKCSjuBN3ph5yqoQaol9Fns",
+	"M5Npsuo7d9SKAA2B0u4/KqNsrnTDwBokTyDqBtaFTAvItNuD2copx3r2pdk9I0U1V6ZRgGoDn/RiMlci",
+	"EVFeZ5Yg50yb5SRKM8F/N4YmsnJ+mohWtlmhLYtEeNFmuay9o0qmyo5aI/6AxuDG/X9Fxkqthob5V/gA",
+	"Om/1eeadw5Lpq2iNFtxXhtzoSiRH4OZRchRij++igdsY8HNtKmQxE03j4X+Y5e3WcceNHuGj4EN9w/3g",
+	"V9zfYDuSHOY8UE/uVnqf9cfXOZrl8YTjuGOZY8ofS50iy9HEm4hso7CS6ceWaKewI9T31XfwkPDDfdQq",
+	"epTGRBiHyjtGbk6Cb+spH+c9rhtn79c6Q6Y3evHWCYTlEMj19sNDyoh22xgGu0N73qMV3UMrvZ22Kdlr",
+	"5V7rW/F2he6b5X8m8+7LOODNdL8/xCBx19iJIQg/bhz4qsrgF81giX0lsN5NsDI7lmHFQ3JgWqBSVI7H",
+	"HYNSub9HqtBEfNKLEc7/WZBL6julhLSQNsaQ4nIDplGeZo/JIzlpJJCh5Y8FoeEFIe+QcJA5Y1nRGJ07",
+	"E/6PVc2bu7VtqNaGo6pbIsdyZ6vZmIO2pw/472k6qnU8RgV4/kOOpdlOmd0fh4AbKA1Vu++mntaQGbdO",
+	"qlyPmV9aZ29U8OLt6x2NsgYTwpyvSV0pWQasTJwuJZfuiIt3L339+qqtt85ihdlT4Ew8i3dZhbUUM/F8",
+	"cj45F4mokQsv+xSzSqppPNr9siQecutUaQHLEgwtpWUylHX86tx/iWa0E7hQ5QZSLEv3tXTLM3B1uD/L",
+	"glQe86lWuVw24TLsiHSVY4Vhu1/mN4HRDZMNFXB3g36diZm7t71wS95HEZxhQoz24vzl/Fz4loJiUl4y",
+	"rOtShgw8/RSv8MHc9wODh5W37NChh9px+v/+t98ejYfQbLn15//1JHRvE2GbqkKziYYHvkOy22QPP9Ob",
+	"rvC8DSAqiUfaNZdU6dXOLWoCXTyU7PwiRj9/ofLe0FCWgDaQoywpA5nPFRe0gTUZAiwNYbYBQ2wkZcBa",
+	"g86Z1ARedJcQH6ikBcuyLAFLuaK5auWygEuUKrSMEBStQWZjePvBy9SD3BBx349H+95liFYyZcqeODh+",
+	"dFIARsGcMDUarIh9KPlwI6QT3cUakQiFFYlZ72bSj6BsGkp6zBy7fF0dhN7UZ4lYiv53+ElEre1I4PwZ",
+	"zfVOikcLbQ5zfYiue7Ak9vd8Bzwf6vsOMVd7HqE0A+Y5ORCNgdRdlHsQ/cHr4+SR8a6ouK1uWtGfOPC9",
+	"PnvAd2gkt9ROb2LoG02jP3kjg1SWUaXkkp6Dht8KtqZU5i56LTYgs4FNfyIO7AzcbPeQ1z/s0BXJGOyP",
+	"4L1rMolnz8fhfjIo9U359CBCaWMkb7xZXhIaMmL24er2qo8eh4Jg8r7hPIjaq8J4MImXXwvo24Hga8gO",
+	"JLAodXptoVEsy7DEF5grlCUuSpoMELXt7EQwkOWXOts8mm6GXa+gpj3wPDvJgeGIsZj0yhDG7BuT9aMc",
+	"PrjkDk/+RXuzrLFnlwTYbEL18eTC4r58YjdOXrbXGCf1FuHTG9bXpPpxchDqWlCeKMrsTaRGRLn4+xNP",
+	"Ui7MtDWDV/99yjNvmIOpYaz0QU6LoRW7ttSJosugKTgaXM5Pcd43DJsgJeAudvZdd9p2s+30xkHH+3Ld",
+	"8BgK3EzujV68iDvEfXDo/3kIDJPHg/P9sKpTJj6zbAirXaXvk7wLlN8ccPzwFRW02BiBTeqeMpz1BrKH",
+	"C+ZLsrpsOwV+L/T2urN2Rr5t4etyrHF52M4V69iQ8iR6m+NaJoWKY3dAr5XtKKDx/QFujBq/ZY2/zhCn",
+	"ibPJzVfU8fe4t544xd75duVbTblDlO4CTedHY2t4AnJf10AI68G0lWfbYUWzbCrHd88vEijltR+GzFWN",
+	"1q61yTxPe67Uc5rIzp5nAeu5Ct25Yx4SHtX83y/6frH30Ohb9YYOnOOw7yYTd18ELuKS++TQSM63UpwT",
+	"ODEg2trJfIo2xb7ZflX0pfaNuXjJ16mf6WViqB3nUQd5djraTu1H48A76Z8/hVVxWNQ2u4NrWrBkVjJt",
+	"F4156bv2y8lgv/es4VsEfFSvt1pvojbezAlNCBtnDW13WGWd0RBccIqWtIVuygwWBI110w/lR262WVhX",
+	"rCr28zJ7Vyu4Pxt7/HvYyMO/E7d5xt6B3d3n2VHxnheGJcMVXzvRwuG0qWiYXdDL9FrtvwJ0+dOPo+aq",
+	"Fxv907/v2FnbeLoHBlFfP4OKpJ96K57aCd7vN4gaG0Fto/bvPoO6pNyQLeItqntN0RaF4fTExx5HwPo0",
+	"4RYj+5e2bdBhDZmTo5KKQK/IoMNtRFRBWHKxxbffnWk/qQrQzGDt5q7uhwXBNdUMbDC99qVBHgzIsiLd",
+	"8ARe53OVGV3XvXcGftLbTcFCMcuuBe5bqo62dxjLaA6MxQKDB/Pdrvpsk6Zk86YsN9D45kjL0ncWeiG/",
+	"3/b0Cm+drVvjFpFZjY9tfnYjpT/VRmdN6n76M4S1IhGNKcVMFMy1nU2nWMuJrknZQuY8SXXlfpn6J79n",
+	"/gkumbNw8nT1zL+02isYGJdOgwfIW8YlPfCQQOUhy3ofrm7/MwC6v2u9YDIAAA==",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
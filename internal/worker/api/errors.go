@@ -10,35 +10,45 @@ import (
 const (
 	ErrorCodePrefix = "IMAGE-BUILDER-WORKER-"
 
-	ErrorUnsupportedMediaType ServiceErrorCode = 3
-	ErrorJobNotFound          ServiceErrorCode = 5
-	ErrorJobNotRunning        ServiceErrorCode = 6
-	ErrorMalformedJobId       ServiceErrorCode = 7
-	ErrorMalformedJobToken    ServiceErrorCode = 8
-	ErrorInvalidErrorId       ServiceErrorCode = 9
-	ErrorBodyDecodingError    ServiceErrorCode = 10
-	ErrorResourceNotFound     ServiceErrorCode = 11
-	ErrorMethodNotAllowed     ServiceErrorCode = 12
-	ErrorNotAcceptable        ServiceErrorCode = 13
-	ErrorErrorNotFound        ServiceErrorCode = 14
-	ErrorInvalidJobType       ServiceErrorCode = 15
-	ErrorTenantNotFound       ServiceErrorCode = 16
-	ErrorMalformedWorkerId    ServiceErrorCode = 17
-	ErrorWorkerIdNotFound     ServiceErrorCode = 18
-	ErrorInvalidContent       ServiceErrorCode = 19
+	ErrorUnsupportedMediaType     ServiceErrorCode = 3
+	ErrorJobNotFound              ServiceErrorCode = 5
+	ErrorJobNotRunning            ServiceErrorCode = 6
+	ErrorMalformedJobId           ServiceErrorCode = 7
+	ErrorMalformedJobToken        ServiceErrorCode = 8
+	ErrorInvalidErrorId           ServiceErrorCode = 9
+	ErrorBodyDecodingError        ServiceErrorCode = 10
+	ErrorResourceNotFound         ServiceErrorCode = 11
+	ErrorMethodNotAllowed         ServiceErrorCode = 12
+	ErrorNotAcceptable            ServiceErrorCode = 13
+	ErrorErrorNotFound            ServiceErrorCode = 14
+	ErrorInvalidJobType           ServiceErrorCode = 15
+	ErrorTenantNotFound           ServiceErrorCode = 16
+	ErrorMalformedWorkerId        ServiceErrorCode = 17
+	ErrorWorkerIdNotFound         ServiceErrorCode = 18
+	ErrorInvalidContent           ServiceErrorCode = 19
+	ErrorNotAdmin                 ServiceErrorCode = 20
+	ErrorWorkerHasActiveJobs      ServiceErrorCode = 21
+	ErrorCloudCredentialsNotFound ServiceErrorCode = 22
+	ErrorSecretNotFound           ServiceErrorCode = 23
 
 	// internal errors
-	ErrorDiscardingArtifact       ServiceErrorCode = 1000
-	ErrorCreatingArtifact         ServiceErrorCode = 1001
-	ErrorWritingArtifact          ServiceErrorCode = 1002
-	ErrorResolvingJobId           ServiceErrorCode = 1003
-	ErrorFinishingJob             ServiceErrorCode = 1004
-	ErrorRetrievingJobStatus      ServiceErrorCode = 1005
-	ErrorRequestingJob            ServiceErrorCode = 1006
-	ErrorFailedLoadingOpenAPISpec ServiceErrorCode = 1007
-	ErrorInsertingWorker          ServiceErrorCode = 1008
-	ErrorUpdatingWorkerStatus     ServiceErrorCode = 1009
-	ErrorUpdatingJob              ServiceErrorCode = 1010
+	ErrorDiscardingArtifact        ServiceErrorCode = 1000
+	ErrorCreatingArtifact          ServiceErrorCode = 1001
+	ErrorWritingArtifact           ServiceErrorCode = 1002
+	ErrorResolvingJobId            ServiceErrorCode = 1003
+	ErrorFinishingJob              ServiceErrorCode = 1004
+	ErrorRetrievingJobStatus       ServiceErrorCode = 1005
+	ErrorRequestingJob             ServiceErrorCode = 1006
+	ErrorFailedLoadingOpenAPISpec  ServiceErrorCode = 1007
+	ErrorInsertingWorker           ServiceErrorCode = 1008
+	ErrorUpdatingWorkerStatus      ServiceErrorCode = 1009
+	ErrorUpdatingJob               ServiceErrorCode = 1010
+	ErrorListingWorkers            ServiceErrorCode = 1011
+	ErrorDrainingWorker            ServiceErrorCode = 1012
+	ErrorEvictingWorker            ServiceErrorCode = 1013
+	ErrorDeletingWorker            ServiceErrorCode = 1014
+	ErrorResolvingCloudCredentials ServiceErrorCode = 1015
+	ErrorResolvingSecret           ServiceErrorCode = 1016
 
 	// Errors contained within this file
 	ErrorUnspecified          ServiceErrorCode = 10000
@@ -75,6 +85,10 @@ func getServiceErrors() serviceErrors {
 		serviceError{ErrorMalformedWorkerId, http.StatusBadRequest, "Given worker id is not a uuidv4"},
 		serviceError{ErrorWorkerIdNotFound, http.StatusBadRequest, "Given worker id doesn't exist"},
 		serviceError{ErrorInvalidContent, http.StatusBadRequest, "Content of body is not valid"},
+		serviceError{ErrorNotAdmin, http.StatusForbidden, "Only admins may call this route"},
+		serviceError{ErrorWorkerHasActiveJobs, http.StatusConflict, "Worker is still running jobs"},
+		serviceError{ErrorCloudCredentialsNotFound, http.StatusNotFound, "Cloud credentials not found for the tenant of the job"},
+		serviceError{ErrorSecretNotFound, http.StatusNotFound, "Secret not found for the job"},
 
 		serviceError{ErrorDiscardingArtifact, http.StatusInternalServerError, "Error discarding artifact"},
 		serviceError{ErrorCreatingArtifact, http.StatusInternalServerError, "Error creating artifact"},
@@ -87,6 +101,12 @@ func getServiceErrors() serviceErrors {
 		serviceError{ErrorInsertingWorker, http.StatusInternalServerError, "Unable to register the worker"},
 		serviceError{ErrorUpdatingWorkerStatus, http.StatusInternalServerError, "Unable update worker status"},
 		serviceError{ErrorUpdatingJob, http.StatusInternalServerError, "Error updating job"},
+		serviceError{ErrorListingWorkers, http.StatusInternalServerError, "Unable to list workers"},
+		serviceError{ErrorDrainingWorker, http.StatusInternalServerError, "Unable to drain worker"},
+		serviceError{ErrorEvictingWorker, http.StatusInternalServerError, "Unable to evict worker"},
+		serviceError{ErrorDeletingWorker, http.StatusInternalServerError, "Unable to remove worker"},
+		serviceError{ErrorResolvingCloudCredentials, http.StatusInternalServerError, "Unable to resolve cloud credentials"},
+		serviceError{ErrorResolvingSecret, http.StatusInternalServerError, "Unable to resolve secret"},
 
 		serviceError{ErrorUnspecified, http.StatusInternalServerError, "Unspecified internal error "},
 		serviceError{ErrorNotHTTPError, http.StatusInternalServerError, "Error is not an instance of HTTPError"},
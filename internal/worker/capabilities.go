@@ -0,0 +1,109 @@
+package worker
+
+import (
+	"net/url"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/osbuild/osbuild-composer/internal/target"
+)
+
+// Capabilities are advertised by workers when they register, osbuild jobs
+// are only handed out to workers having all the capabilities the job
+// requires.
+const (
+	capabilityTargetPrefix   = "target:"
+	capabilityExecutorPrefix = "executor:"
+	capabilityLabelPrefix    = "label:"
+)
+
+// TargetCapability is the capability of a worker which has the credentials
+// to upload to a target
+func TargetCapability(name target.TargetName) string {
+	return capabilityTargetPrefix + string(name)
+}
+
+// KojiServerCapability is the capability of a worker which has credentials
+// for the Koji server with the given hostname
+func KojiServerCapability(hostname string) string {
+	return capabilityTargetPrefix + string(target.TargetNameKoji) + ":" + hostname
+}
+
+// ExecutorCapability is the capability of a worker running osbuild with the
+// given executor type, like "host" or "aws.ec2"
+func ExecutorCapability(executorType string) string {
+	return capabilityExecutorPrefix + executorType
+}
+
+// LabelCapability is the capability of a worker which was configured with a
+// free form label, like "fips" or "datacenter=ams"
+func LabelCapability(label string) string {
+	return capabilityLabelPrefix + label
+}
+
+// cloudCredentials returns the cloud credentials of the tenant the target
+// uploads with, nil if it uses the credentials of the worker
+func cloudCredentials(t *target.Target) *uuid.UUID {
+	switch options := t.Options.(type) {
+	case *target.AWSTargetOptions:
+		return options.CloudCredentials
+	case *target.AWSS3TargetOptions:
+		return options.CloudCredentials
+	case *target.AzureImageTargetOptions:
+		return options.CloudCredentials
+	case *target.GCPTargetOptions:
+		return options.CloudCredentials
+	}
+	return nil
+}
+
+// targetRequirement returns the capability a worker needs to upload to the
+// target. Most targets carry their credentials or fall back to the default
+// credentials of the environment, only the ones which need to be configured
+// on the worker have requirements. Targets uploading with the cloud
+// credentials of a tenant never do.
+func targetRequirement(t *target.Target) string {
+	if cloudCredentials(t) != nil {
+		return ""
+	}
+
+	switch options := t.Options.(type) {
+	case *target.KojiTargetOptions:
+		u, err := url.Parse(options.Server)
+		if err != nil {
+			// let the worker report the invalid URL
+			return ""
+		}
+		return KojiServerCapability(u.Hostname())
+	case *target.AzureImageTargetOptions:
+		return TargetCapability(target.TargetNameAzureImage)
+	case *target.OCITargetOptions:
+		if options.PrivateKey == "" {
+			return TargetCapability(target.TargetNameOCI)
+		}
+	case *target.OCIObjectStorageTargetOptions:
+		if options.PrivateKey == "" {
+			return TargetCapability(target.TargetNameOCIObjectStorage)
+		}
+	}
+	return ""
+}
+
+// Requirements returns the capabilities a worker needs to run the job
+func (j OSBuildJob) Requirements() []string {
+	var requirements []string
+	add := func(r string) {
+		if r != "" && !slices.Contains(requirements, r) {
+			requirements = append(requirements, r)
+		}
+	}
+
+	for _, t := range j.Targets {
+		add(targetRequirement(t))
+	}
+	for _, label := range j.WorkerLabels {
+		add(LabelCapability(label))
+	}
+	return requirements
+}
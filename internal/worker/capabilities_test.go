@@ -0,0 +1,74 @@
+package worker_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/osbuild/osbuild-composer/internal/common"
+	"github.com/osbuild/osbuild-composer/internal/target"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+)
+
+func TestOSBuildJobRequirements(t *testing.T) {
+	tests := []struct {
+		name string
+		job  worker.OSBuildJob
+		want []string
+	}{
+		{
+			name: "no-targets",
+			job:  worker.OSBuildJob{},
+			want: nil,
+		},
+		{
+			name: "credentials-in-target",
+			job: worker.OSBuildJob{
+				Targets: []*target.Target{
+					target.NewAWSTarget(&target.AWSTargetOptions{}),
+					target.NewGCPTarget(&target.GCPTargetOptions{}),
+					target.NewOCITarget(&target.OCITargetOptions{PrivateKey: "key"}),
+					target.NewWorkerServerTarget(),
+				},
+			},
+			want: nil,
+		},
+		{
+			name: "tenant-credentials",
+			job: worker.OSBuildJob{
+				Targets: []*target.Target{
+					target.NewAWSTarget(&target.AWSTargetOptions{CloudCredentials: common.ToPtr(uuid.New())}),
+					target.NewAWSS3Target(&target.AWSS3TargetOptions{CloudCredentials: common.ToPtr(uuid.New())}),
+					target.NewAzureImageTarget(&target.AzureImageTargetOptions{CloudCredentials: common.ToPtr(uuid.New())}),
+					target.NewGCPTarget(&target.GCPTargetOptions{CloudCredentials: common.ToPtr(uuid.New())}),
+				},
+			},
+			want: nil,
+		},
+		{
+			name: "worker-credentials",
+			job: worker.OSBuildJob{
+				Targets: []*target.Target{
+					target.NewKojiTarget(&target.KojiTargetOptions{Server: "https://koji.example.com/kojihub"}),
+					target.NewKojiTarget(&target.KojiTargetOptions{Server: "https://koji.example.com/kojihub"}),
+					target.NewAzureImageTarget(&target.AzureImageTargetOptions{}),
+					target.NewOCIObjectStorageTarget(&target.OCIObjectStorageTargetOptions{}),
+				},
+				WorkerLabels: []string{"fips", "datacenter=ams"},
+			},
+			want: []string{
+				"target:org.osbuild.koji:koji.example.com",
+				"target:org.osbuild.azure.image",
+				"target:org.osbuild.oci.objectstorage",
+				"label:fips",
+				"label:datacenter=ams",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.job.Requirements())
+		})
+	}
+}
@@ -18,7 +18,10 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/osbuild/image-builder/pkg/arch"
+	"github.com/osbuild/osbuild-composer/internal/cloudcredentials"
 	"github.com/osbuild/osbuild-composer/internal/common"
+	"github.com/osbuild/osbuild-composer/internal/secrets"
+	"github.com/osbuild/osbuild-composer/internal/tracing"
 	"github.com/osbuild/osbuild-composer/internal/worker/api"
 )
 
@@ -30,7 +33,10 @@ type Client struct {
 	accessToken  string
 	clientId     string
 	clientSecret string
+	capabilities []string
 	workerID     uuid.UUID
+	// set once the worker deregistered, it doesn't register again
+	deregistered bool
 
 	tokenMu    sync.RWMutex
 	workerIDMu sync.RWMutex
@@ -45,10 +51,19 @@ type ClientConfig struct {
 	ClientSecret string
 	BasePath     string
 	ProxyURL     string
+	// Capabilities are advertised to the server, which only hands out jobs
+	// the worker can satisfy. 'nil' means the worker can run any job.
+	Capabilities []string
 }
 
 type Job interface {
 	Id() uuid.UUID
+	// Context carries the trace the job is part of. The requests the job
+	// makes to the server are traced in it.
+	Context() context.Context
+	// WithContext returns a copy of the job with its context changed to
+	// ctx, e.g. to make the requests of the job part of a span
+	WithContext(ctx context.Context) Job
 	Type() string
 	Args(args interface{}) error
 	DynamicArgs(i int, args interface{}) error
@@ -57,12 +72,15 @@ type Job interface {
 	Finish(result interface{}) error
 	Canceled() (bool, error)
 	UploadArtifact(name string, readSeeker io.ReadSeeker) error
+	CloudCredentials(id uuid.UUID) (*cloudcredentials.Credentials, error)
+	Secret(ref string) (string, error)
 }
 
 var ErrClientRequestJobTimeout = errors.New("Dequeue timed out, retry")
 
 type job struct {
 	client           *Client
+	ctx              context.Context
 	id               uuid.UUID
 	location         string
 	artifactLocation string
@@ -112,7 +130,7 @@ func NewClient(conf ClientConfig) (*Client, error) {
 	if conf.TlsConfig != nil {
 		transport.TLSClientConfig = conf.TlsConfig
 	}
-	requester.Transport = transport
+	requester.Transport = tracing.Transport(transport)
 
 	client := &Client{
 		serverURL:    serverURL,
@@ -121,6 +139,7 @@ func NewClient(conf ClientConfig) (*Client, error) {
 		oAuthURL:     conf.OAuthURL,
 		clientId:     conf.ClientId,
 		clientSecret: conf.ClientSecret,
+		capabilities: conf.Capabilities,
 	}
 	err = client.registerWorker()
 	if err != nil {
@@ -144,15 +163,16 @@ func NewClientUnix(conf ClientConfig) *Client {
 	}
 
 	requester := &http.Client{
-		Transport: &http.Transport{
+		Transport: tracing.Transport(&http.Transport{
 			DialContext: func(context context.Context, network, addr string) (net.Conn, error) {
 				return net.Dial("unix", conf.BaseURL)
 			},
-		},
+		}),
 	}
 	client := &Client{
-		serverURL: serverURL,
-		requester: requester,
+		serverURL:    serverURL,
+		requester:    requester,
+		capabilities: conf.Capabilities,
 	}
 	err = client.registerWorker()
 	if err != nil {
@@ -166,15 +186,24 @@ func (c *Client) registerWorker() error {
 	c.workerIDMu.Lock()
 	defer c.workerIDMu.Unlock()
 
+	if c.deregistered {
+		return nil
+	}
+
 	url, err := c.serverURL.Parse("workers")
 	if err != nil {
 		return err
 	}
 
 	var buf bytes.Buffer
-	err = json.NewEncoder(&buf).Encode(api.PostWorkersRequest{
-		Arch: arch.Current().String(),
-	})
+	req := api.PostWorkersRequest{
+		Arch:    arch.Current().String(),
+		Version: common.ToPtr(common.BuildVersion()),
+	}
+	if c.capabilities != nil {
+		req.Capabilities = &c.capabilities
+	}
+	err = json.NewEncoder(&buf).Encode(req)
 	if err != nil {
 		logrus.Errorf("Unable create worker request: %v", err)
 		return err
@@ -201,6 +230,42 @@ func (c *Client) registerWorker() error {
 	return nil
 }
 
+// Deregister removes the worker from the server. It must not run any jobs
+// anymore, the server refuses to remove workers with running jobs.
+func (c *Client) Deregister() error {
+	c.workerIDMu.Lock()
+	defer c.workerIDMu.Unlock()
+
+	c.deregistered = true
+	if c.workerID == uuid.Nil {
+		return nil
+	}
+
+	url, err := c.serverURL.Parse(fmt.Sprintf("workers/%s", c.workerID))
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.NewRequest("DELETE", url.String(), nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return errorFromResponse(resp, "error deregistering worker")
+	}
+
+	c.workerID = uuid.Nil
+	return nil
+}
+
+func (c *Client) isDeregistered() bool {
+	c.workerIDMu.RLock()
+	defer c.workerIDMu.RUnlock()
+	return c.deregistered
+}
+
 func (c *Client) getWorkerID() uuid.UUID {
 	c.workerIDMu.RLock()
 	defer c.workerIDMu.RUnlock()
@@ -210,6 +275,9 @@ func (c *Client) getWorkerID() uuid.UUID {
 func (c *Client) workerHeartbeat() {
 	//nolint:staticcheck // avoid SA1015, this is an endless function
 	for range time.Tick(time.Minute * 1) {
+		if c.isDeregistered() {
+			return
+		}
 		workerID := c.getWorkerID()
 
 		if workerID == uuid.Nil {
@@ -278,6 +346,10 @@ func (c *Client) refreshAccessToken() error {
 }
 
 func (c *Client) NewRequest(method, url string, headers map[string]string, body io.ReadSeeker) (*http.Response, error) {
+	return c.newRequestWithContext(context.Background(), method, url, headers, body)
+}
+
+func (c *Client) newRequestWithContext(ctx context.Context, method, url string, headers map[string]string, body io.ReadSeeker) (*http.Response, error) {
 	token := func() string {
 		c.tokenMu.RLock()
 		defer c.tokenMu.RUnlock()
@@ -292,7 +364,7 @@ func (c *Client) NewRequest(method, url string, headers map[string]string, body
 			}
 		}
 
-		req, err := http.NewRequest(method, url, body)
+		req, err := http.NewRequestWithContext(ctx, method, url, body)
 		if err != nil {
 			return nil, err
 		}
@@ -332,7 +404,9 @@ func (c *Client) NewRequest(method, url string, headers map[string]string, body
 	return resp, err
 }
 
-func (c *Client) RequestJob(types []string, arch string) (Job, error) {
+// RequestJob waits for a job of one of the types. Canceling ctx stops
+// waiting.
+func (c *Client) RequestJob(ctx context.Context, types []string, arch string) (Job, error) {
 	url, err := c.serverURL.Parse("jobs")
 	if err != nil {
 		// This only happens when "jobs" cannot be parsed.
@@ -355,7 +429,7 @@ func (c *Client) RequestJob(types []string, arch string) (Job, error) {
 		panic(err)
 	}
 
-	response, err := c.NewRequest("POST", url.String(), map[string]string{"Content-Type": "application/json"}, bytes.NewReader(buf.Bytes()))
+	response, err := c.newRequestWithContext(ctx, "POST", url.String(), map[string]string{"Content-Type": "application/json"}, bytes.NewReader(buf.Bytes()))
 	if err != nil {
 		return nil, err
 	}
@@ -400,6 +474,7 @@ func (c *Client) RequestJob(types []string, arch string) (Job, error) {
 
 	return &job{
 		client:           c,
+		ctx:              tracing.ExtractArgs(context.Background(), args),
 		id:               jobId,
 		jobType:          jr.Type,
 		args:             args,
@@ -417,6 +492,16 @@ func (j *job) Type() string {
 	return j.jobType
 }
 
+func (j *job) Context() context.Context {
+	return j.ctx
+}
+
+func (j *job) WithContext(ctx context.Context) Job {
+	copy := *j
+	copy.ctx = ctx
+	return &copy
+}
+
 func (j *job) Args(args interface{}) error {
 	err := json.Unmarshal(j.args, args)
 	if err != nil {
@@ -476,7 +561,7 @@ func (j *job) patchJob(req api.UpdateJobRequest) error {
 		return fmt.Errorf("Unable to marshal update job request: %w", err)
 	}
 
-	response, err := j.client.NewRequest(http.MethodPatch, j.location, map[string]string{"Content-Type": "application/json"}, bytes.NewReader(body))
+	response, err := j.client.newRequestWithContext(j.ctx, http.MethodPatch, j.location, map[string]string{"Content-Type": "application/json"}, bytes.NewReader(body))
 	if err != nil {
 		return fmt.Errorf("error fetching job info: %w", err)
 	}
@@ -490,7 +575,7 @@ func (j *job) patchJob(req api.UpdateJobRequest) error {
 }
 
 func (j *job) Canceled() (bool, error) {
-	response, err := j.client.NewRequest("GET", j.location, map[string]string{}, nil)
+	response, err := j.client.newRequestWithContext(j.ctx, "GET", j.location, map[string]string{}, nil)
 	if err != nil {
 		return false, fmt.Errorf("error fetching job info: %v", err)
 	}
@@ -524,7 +609,7 @@ func (j *job) UploadArtifact(name string, readSeeker io.ReadSeeker) error {
 		panic(err)
 	}
 
-	response, err := j.client.NewRequest("PUT", loc.String(), map[string]string{"Content-Type": "application/octet-stream"}, readSeeker)
+	response, err := j.client.newRequestWithContext(j.ctx, "PUT", loc.String(), map[string]string{"Content-Type": "application/octet-stream"}, readSeeker)
 	if err != nil {
 		return fmt.Errorf("error uploading artifact: %v", err)
 	}
@@ -536,6 +621,58 @@ func (j *job) UploadArtifact(name string, readSeeker io.ReadSeeker) error {
 	return nil
 }
 
+// CloudCredentials resolves the cloud credentials an upload target of the
+// job refers to. The server only returns credentials of the tenant of the
+// job.
+func (j *job) CloudCredentials(id uuid.UUID) (*cloudcredentials.Credentials, error) {
+	response, err := j.client.newRequestWithContext(j.ctx, "GET", fmt.Sprintf("%s/cloud-credentials/%s", j.location, id), map[string]string{}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching cloud credentials: %v", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, errorFromResponse(response, "error fetching cloud credentials")
+	}
+
+	var cr api.GetJobCloudCredentialsResponse
+	err = json.NewDecoder(response.Body).Decode(&cr)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing reponse: %v", err)
+	}
+
+	var creds cloudcredentials.Credentials
+	err = json.Unmarshal(cr.Credentials, &creds)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing cloud credentials: %v", err)
+	}
+	return &creds, nil
+}
+
+// Secret resolves a secret reference in the arguments of the job
+func (j *job) Secret(ref string) (string, error) {
+	id, ok := secrets.ParseRef(ref)
+	if !ok {
+		return "", fmt.Errorf("invalid secret reference %q", ref)
+	}
+	response, err := j.client.newRequestWithContext(j.ctx, "GET", fmt.Sprintf("%s/secrets/%s", j.location, id), map[string]string{}, nil)
+	if err != nil {
+		return "", fmt.Errorf("error fetching secret: %v", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", errorFromResponse(response, "error fetching secret")
+	}
+
+	var sr api.GetJobSecretResponse
+	err = json.NewDecoder(response.Body).Decode(&sr)
+	if err != nil {
+		return "", fmt.Errorf("error parsing reponse: %v", err)
+	}
+	return sr.Secret, nil
+}
+
 // Parses an api.Error from a response and returns it as a golang error. Other
 // errors, such failing to parse the response, are returned as golang error as
 // well. If client code expects an error, it gets one.
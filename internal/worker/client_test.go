@@ -2,11 +2,13 @@ package worker_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
@@ -31,7 +33,7 @@ func newTestWorkerServer(t *testing.T) (string, string, string) {
 		BasePath:     "/api/image-builder-worker/v1",
 	}
 	workerServer := worker.NewServer(nil, q, config)
-	_, err = workerServer.EnqueueOSBuild("arch", &worker.OSBuildJob{}, "")
+	_, err = workerServer.EnqueueOSBuild(context.Background(), "arch", &worker.OSBuildJob{}, "")
 	require.NoError(t, err)
 
 	handler := workerServer.Handler()
@@ -94,7 +96,7 @@ func TestOAuth(t *testing.T) {
 		BasePath:     "/api/image-builder-worker/v1",
 	})
 	require.NoError(t, err)
-	job, err := client.RequestJob([]string{worker.JobTypeOSBuild}, "arch")
+	job, err := client.RequestJob(context.Background(), []string{worker.JobTypeOSBuild}, "arch")
 	require.NoError(t, err)
 	r := strings.NewReader("artifact contents")
 	require.NoError(t, job.UploadArtifact("some-artifact", r))
@@ -122,7 +124,7 @@ func TestProxy(t *testing.T) {
 	})
 
 	require.NoError(t, err)
-	job, err := client.RequestJob([]string{worker.JobTypeOSBuild}, "arch")
+	job, err := client.RequestJob(context.Background(), []string{worker.JobTypeOSBuild}, "arch")
 	require.NoError(t, err)
 	r := strings.NewReader("artifact contents")
 	require.NoError(t, job.UploadArtifact("some-artifact", r))
@@ -164,3 +166,37 @@ func TestNewClientWorkerNoErrorOnRegisterWorkerFailure(t *testing.T) {
 	require.Equal(t, 1, apiCalls)
 	require.Contains(t, logrusOutput.String(), `Error registering worker on startup, error registering worker: 400`)
 }
+
+func TestClientDeregister(t *testing.T) {
+	q, err := fsjobqueue.New(t.TempDir())
+	require.NoError(t, err)
+	workerServer := worker.NewServer(nil, q, worker.Config{
+		BasePath: "/api/image-builder-worker/v1",
+	})
+	srv := httptest.NewServer(workerServer.Handler())
+	t.Cleanup(srv.Close)
+
+	client, err := worker.NewClient(worker.ClientConfig{
+		BaseURL:  srv.URL,
+		BasePath: "/api/image-builder-worker/v1",
+	})
+	require.NoError(t, err)
+
+	workers, err := workerServer.Workers()
+	require.NoError(t, err)
+	require.Len(t, workers, 1)
+
+	// waiting for a job stops when the context is canceled
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+	_, err = client.RequestJob(ctx, []string{worker.JobTypeOSBuild}, "arch")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	require.NoError(t, client.Deregister())
+	workers, err = workerServer.Workers()
+	require.NoError(t, err)
+	require.Len(t, workers, 0)
+
+	// deregistering twice is fine
+	require.NoError(t, client.Deregister())
+}
@@ -47,6 +47,9 @@ const (
 	ErrorDepsolveTimeout      ClientErrorCode = 40
 	ErrorBootcInfoResolve     ClientErrorCode = 41
 	ErrorBuildVersionMismatch ClientErrorCode = 42
+	ErrorLockfileMismatch     ClientErrorCode = 43
+	ErrorWorkerEvicted        ClientErrorCode = 44
+	ErrorSourceBundle         ClientErrorCode = 45
 )
 
 type ClientErrorCode int
@@ -112,6 +115,10 @@ func GetStatusCode(err *Error) StatusCode {
 		return JobStatusUserInputError
 	case ErrorBuildVersionMismatch:
 		return JobStatusInternalError
+	case ErrorLockfileMismatch:
+		return JobStatusUserInputError
+	case ErrorSourceBundle:
+		return JobStatusUserInputError
 	default:
 		return JobStatusInternalError
 	}
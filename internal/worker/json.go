@@ -9,6 +9,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
+
 	"github.com/osbuild/blueprint/pkg/blueprint"
 	"github.com/osbuild/image-builder/pkg/bib/osinfo"
 	"github.com/osbuild/image-builder/pkg/bootc"
@@ -20,6 +22,7 @@ import (
 	"github.com/osbuild/image-builder/pkg/rpmmd"
 	"github.com/osbuild/image-builder/pkg/sbom"
 	"github.com/osbuild/osbuild-composer/internal/common"
+	"github.com/osbuild/osbuild-composer/internal/lockfile"
 	"github.com/osbuild/osbuild-composer/internal/target"
 	"github.com/osbuild/osbuild-composer/internal/worker/clienterrors"
 )
@@ -67,6 +70,19 @@ type OSBuildJob struct {
 	// the value can be accessed job which depend on it.
 	// (string representation of distro.BootMode values)
 	ImageBootMode string `json:"image_boot_mode,omitempty"`
+
+	// Labels the worker running the job needs to be configured with, for
+	// example "fips"
+	WorkerLabels []string `json:"worker_labels,omitempty"`
+
+	// File name of a source bundle in the source bundle directory of the
+	// worker. It is imported into the osbuild store before the build, and
+	// its manifest is built if the job has none. Without targets the image
+	// is uploaded to the worker server.
+	SourceBundle string `json:"source_bundle,omitempty"`
+
+	// Name of the image type, only used to label the metrics of the build
+	ImageType string `json:"image_type,omitempty"`
 }
 
 // OsbuildExports returns a slice of osbuild pipeline names, which should be
@@ -100,9 +116,49 @@ type OSBuildJobResult struct {
 	ImageBootMode string `json:"image_boot_mode,omitempty"`
 	// Version of the osbuild binary used by the worker to build the image
 	OSBuildVersion string `json:"osbuild_version,omitempty"`
+	// How the build used the osbuild store of the worker, only set by
+	// workers managing the size of their store
+	StoreStats *OSBuildStoreStats `json:"store_stats,omitempty"`
+	// How long the steps of the build took, for the metrics of composer
+	Timings *OSBuildTimings `json:"timings,omitempty"`
 	JobResult
 }
 
+type OSBuildStoreStats struct {
+	SourceHits      int   `json:"source_hits"`
+	SourceMisses    int   `json:"source_misses"`
+	DownloadedBytes int64 `json:"downloaded_bytes"`
+	EvictedSources  int   `json:"evicted_sources"`
+	EvictedBytes    int64 `json:"evicted_bytes"`
+	StoreSize       int64 `json:"store_size"`
+}
+
+type OSBuildTimings struct {
+	// Pipelines and stages in the order osbuild ran them
+	Steps []OSBuildStepTiming `json:"steps,omitempty"`
+	// Type of the executor and the time it took to provision it, e.g. to
+	// start the secure instance, only set for executors needing it
+	Executor                    string                `json:"executor,omitempty"`
+	ExecutorProvisioningSeconds float64               `json:"executor_provisioning_seconds,omitempty"`
+	Uploads                     []OSBuildUploadTiming `json:"uploads,omitempty"`
+}
+
+type OSBuildStepTiming struct {
+	Pipeline string `json:"pipeline"`
+	// Type of the stage, e.g. "org.osbuild.rpm", empty for the timing of
+	// the whole pipeline
+	Stage   string  `json:"stage,omitempty"`
+	Seconds float64 `json:"seconds"`
+}
+
+type OSBuildUploadTiming struct {
+	Target  string  `json:"target"`
+	Seconds float64 `json:"seconds"`
+	// Size of the uploaded artifact, zero if unknown
+	Bytes   int64 `json:"bytes,omitempty"`
+	Success bool  `json:"success"`
+}
+
 // TargetErrors returns a slice of *clienterrors.Error gathered
 // from the job result's target results. If there were no target errors
 // then the returned slice will be empty.
@@ -216,9 +272,17 @@ type DepsolveJob struct {
 	Arch             string                        `json:"arch"`
 	Releasever       string                        `json:"releasever"`
 
+	// Name of the distribution, only used to label the metrics of the
+	// depsolve
+	Distro string `json:"distro,omitempty"`
+
 	// NB: for now, the worker supports only a single SBOM type, but keep the options
 	// open for the future by passing the actual type and not just bool.
 	SbomType sbom.StandardType `json:"sbom_type,omitempty"`
+
+	// Lockfile, if set, lists the exact packages the result must consist of.
+	// The package sets are expected to be pinned to it already.
+	Lockfile *lockfile.Lockfile `json:"lockfile,omitempty"`
 }
 
 // SbomDoc represents a single SBOM document result.
@@ -800,6 +864,9 @@ type DepsolveJobResult struct {
 	// sets in a depsolve job are processed by the same solver instance.
 	Solver string `json:"solver,omitempty"`
 
+	// Time the worker spent depsolving, for the metrics of composer
+	SolveSeconds float64 `json:"solve_seconds,omitempty"`
+
 	JobResult
 }
 
@@ -1111,6 +1178,9 @@ type ContainerResolveJobResult struct {
 	// Deprecated: Use PipelineSpecs instead. Will be removed after migration.
 	Specs []ContainerSpec `json:"specs,omitempty"`
 
+	// Time the worker spent resolving, for the metrics of composer
+	ResolveSeconds float64 `json:"resolve_seconds,omitempty"`
+
 	JobResult
 }
 
@@ -1151,6 +1221,66 @@ type FileResolveJobResult struct {
 	JobResult
 }
 
+// RepositoryCheckJob probes the metadata of the given repositories without
+// running a depsolve
+type RepositoryCheckJob struct {
+	Repositories []rpmmd.RepoConfig `json:"repositories"`
+}
+
+// RepositoryCheckResultItem holds the health of a single repository. The
+// order of the items is the same as the order of the repositories in the job
+// args.
+type RepositoryCheckResultItem struct {
+	RepoID       string     `json:"repo_id,omitempty"`
+	URL          string     `json:"url,omitempty"`
+	Healthy      bool       `json:"healthy"`
+	Revision     string     `json:"revision,omitempty"`
+	LastUpdated  *time.Time `json:"last_updated,omitempty"`
+	PackageCount int        `json:"package_count"`
+	TLSError     string     `json:"tls_error,omitempty"`
+	GPGErrors    []string   `json:"gpg_errors,omitempty"`
+	Error        string     `json:"error,omitempty"`
+}
+
+type RepositoryCheckJobResult struct {
+	Results []RepositoryCheckResultItem `json:"results"`
+	JobResult
+}
+
+// SourceBundleExportJob fetches the sources of a manifest and uploads them
+// together with the manifest as a source bundle artifact. The manifest is
+// taken from the dynamic arguments if it isn't set.
+type SourceBundleExportJob struct {
+	Manifest manifest.OSBuildManifest `json:"manifest,omitempty"`
+
+	// the export of the image built by the manifest
+	ExportName     string `json:"export_name"`
+	ExportFilename string `json:"export_filename"`
+}
+
+// SourceBundleArtifact is the name of the source bundle artifact of a
+// SourceBundleExportJob
+const SourceBundleArtifact = "source-bundle.tar"
+
+type SourceBundleExportJobResult struct {
+	// number of sources and size of the bundle
+	Sources int   `json:"sources"`
+	Size    int64 `json:"size"`
+	JobResult
+}
+
+// SourceBundleImportJob imports a source bundle into the osbuild stores of
+// the worker. Name is the file name of the bundle in the source bundle
+// directory of the worker.
+type SourceBundleImportJob struct {
+	Name string `json:"name"`
+}
+
+type SourceBundleImportJobResult struct {
+	Sources int `json:"sources"`
+	JobResult
+}
+
 type OSTreeResolveSpec struct {
 	URL  string `json:"url"`
 	Ref  string `json:"ref"`
@@ -1179,6 +1309,9 @@ type AWSEC2ShareJob struct {
 	Ami               string   `json:"ami"`
 	Region            string   `json:"region"`
 	ShareWithAccounts []string `json:"shareWithAccounts"`
+	// CloudCredentials of the tenant the image was uploaded with, nil
+	// if it was uploaded with the credentials of the worker
+	CloudCredentials *uuid.UUID `json:"cloudCredentials,omitempty"`
 }
 
 type AWSEC2ShareJobResult struct {
@@ -1193,6 +1326,9 @@ type AWSEC2CopyJob struct {
 	SourceRegion string `json:"source_region"`
 	TargetRegion string `json:"target_region"`
 	TargetName   string `json:"target_name"`
+	// CloudCredentials of the tenant the image was uploaded with, nil
+	// if it was uploaded with the credentials of the worker
+	CloudCredentials *uuid.UUID `json:"cloud_credentials,omitempty"`
 }
 
 type AWSEC2CopyJobResult struct {
@@ -0,0 +1,97 @@
+package worker
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/osbuild/osbuild-composer/pkg/jobqueue"
+)
+
+// AnyChannel in the channels of a route matches the jobs of all channels
+const AnyChannel = "*"
+
+// Route sends jobs of other channels to a pool of workers, so that workers
+// can serve several tenants. Workers always serve the jobs of their own
+// channel as well.
+//
+// Channels and pools are channels as the worker and cloud APIs assign them,
+// "org-" followed by the tenant, or empty if JWT isn't enabled. The pool of
+// a worker is the channel of the token it requests jobs with.
+type Route struct {
+	// Channels the jobs were enqueued with, AnyChannel matches all
+	Channels []string
+	// ExcludeChannels aren't routed to the pool, e.g. the channels of
+	// tenants with reserved workers when Channels has AnyChannel
+	ExcludeChannels []string
+	// JobTypes of the jobs, like "osbuild" or "depsolve", all types if
+	// empty
+	JobTypes []string
+	// Arches the workers request jobs for, all architectures if empty
+	Arches []string
+	// Labels the jobs require, see OSBuildJob.WorkerLabels
+	Labels []string
+	// Pool is the channel of the workers serving the jobs
+	Pool string
+}
+
+// Validate checks that the route matches any job, only excludes channels
+// from all channels and only refers to job types workers can request
+func (r Route) Validate() error {
+	if len(r.Channels) == 0 {
+		return fmt.Errorf("route to pool %q doesn't have any channels", r.Pool)
+	}
+	if len(r.ExcludeChannels) > 0 && !slices.Contains(r.Channels, AnyChannel) {
+		return fmt.Errorf("route to pool %q: only routes of all channels can exclude channels", r.Pool)
+	}
+	for _, t := range r.JobTypes {
+		if t == JobTypeManifestIDOnly || t == JobTypeBootcPreManifest {
+			return fmt.Errorf("route to pool %q: job type %q isn't run by workers", r.Pool, t)
+		}
+	}
+	return nil
+}
+
+// queueRoutes returns the routes of the job queue for a worker of the
+// channels requesting jobs of jobTypes for arch. jobTypes are the types as
+// stored in the queue, with the architecture of arch-aware job types.
+func (s *Server) queueRoutes(arch string, jobTypes, channels []string) []jobqueue.Route {
+	routes := []jobqueue.Route{
+		{
+			JobTypes: jobTypes,
+			Channels: channels,
+		},
+	}
+
+	for _, r := range s.config.Routes {
+		if !slices.Contains(channels, r.Pool) {
+			continue
+		}
+		if len(r.Arches) > 0 && !slices.Contains(r.Arches, arch) {
+			continue
+		}
+
+		route := jobqueue.Route{
+			AnyChannel:      slices.Contains(r.Channels, AnyChannel),
+			ExcludeChannels: r.ExcludeChannels,
+		}
+		if !route.AnyChannel {
+			route.Channels = r.Channels
+		}
+		for _, t := range jobTypes {
+			base, _, _ := strings.Cut(t, ":")
+			if len(r.JobTypes) == 0 || slices.Contains(r.JobTypes, base) {
+				route.JobTypes = append(route.JobTypes, t)
+			}
+		}
+		if len(route.JobTypes) == 0 {
+			continue
+		}
+		for _, label := range r.Labels {
+			route.Requirements = append(route.Requirements, LabelCapability(label))
+		}
+		routes = append(routes, route)
+	}
+
+	return routes
+}
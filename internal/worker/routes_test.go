@@ -0,0 +1,72 @@
+package worker_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/osbuild/osbuild-composer/internal/worker"
+)
+
+func TestRouteValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		route worker.Route
+		valid bool
+	}{
+		{
+			name:  "any-channel",
+			route: worker.Route{Channels: []string{worker.AnyChannel}, Pool: "org-shared"},
+			valid: true,
+		},
+		{
+			name: "job-types",
+			route: worker.Route{
+				Channels: []string{"org-kingfisher"},
+				JobTypes: []string{worker.JobTypeOSBuild, worker.JobTypeDepsolve},
+				Pool:     "org-reserved",
+			},
+			valid: true,
+		},
+		{
+			name: "exclude-channels",
+			route: worker.Route{
+				Channels:        []string{worker.AnyChannel},
+				ExcludeChannels: []string{"org-kingfisher"},
+				Pool:            "org-shared",
+			},
+			valid: true,
+		},
+		{
+			name: "exclude-from-channels",
+			route: worker.Route{
+				Channels:        []string{"org-kingfisher", "org-penguin"},
+				ExcludeChannels: []string{"org-kingfisher"},
+				Pool:            "org-shared",
+			},
+		},
+		{
+			name:  "no-channels",
+			route: worker.Route{Pool: "org-shared"},
+		},
+		{
+			name: "manifest-id-only",
+			route: worker.Route{
+				Channels: []string{worker.AnyChannel},
+				JobTypes: []string{worker.JobTypeManifestIDOnly},
+				Pool:     "org-shared",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.route.Validate()
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
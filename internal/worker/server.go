@@ -1,7 +1,9 @@
 package worker
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -18,12 +20,18 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/osbuild/osbuild-composer/pkg/jobqueue"
 
+	"github.com/osbuild/osbuild-composer/internal/audit"
 	"github.com/osbuild/osbuild-composer/internal/auth"
+	"github.com/osbuild/osbuild-composer/internal/cloudcredentials"
 	"github.com/osbuild/osbuild-composer/internal/common"
 	"github.com/osbuild/osbuild-composer/internal/prometheus"
+	"github.com/osbuild/osbuild-composer/internal/secrets"
+	"github.com/osbuild/osbuild-composer/internal/target"
+	"github.com/osbuild/osbuild-composer/internal/tracing"
 	"github.com/osbuild/osbuild-composer/internal/worker/api"
 	"github.com/osbuild/osbuild-composer/internal/worker/clienterrors"
 )
@@ -42,6 +50,9 @@ const (
 	JobTypeAWSEC2Share          string = "aws-ec2-share"
 	JobTypeImageBuilderManifest string = "image-builder-manifest"
 	JobTypeBootcInfoResolve     string = "bootc-info-resolve"
+	JobTypeRepositoryCheck      string = "repository-check"
+	JobTypeSourceBundleExport   string = "source-bundle-export"
+	JobTypeSourceBundleImport   string = "source-bundle-import"
 	// JobTypeBootcPreManifest is a server-side job type handled by the
 	// bootcPreManifestLoop in the API server. Workers must NOT register
 	// this type in their acceptedJobTypes.
@@ -72,6 +83,7 @@ type JobInfo struct {
 var ErrInvalidToken = errors.New("token does not exist")
 var ErrJobNotRunning = errors.New("job isn't running")
 var ErrInvalidJobType = errors.New("job has invalid type")
+var ErrNoDepsolveJob = errors.New("job doesn't depend on a depsolve job")
 
 type Config struct {
 	ArtifactsDir         string
@@ -83,6 +95,24 @@ type Config struct {
 	JobWatchFreq         time.Duration
 	WorkerTimeout        time.Duration
 	WorkerWatchFreq      time.Duration
+	// AdminUsers may call the admin routes. Callers are identified by the
+	// common name of their TLS client certificate or by the first
+	// non-empty claim in AdminJWTUserFields.
+	AdminUsers         []string
+	AdminJWTUserFields []string
+	// CloudCredentials are resolved for the upload targets of jobs, nil if
+	// tenants can't register credentials
+	CloudCredentials *cloudcredentials.Registry
+	// Secrets keeps the secrets of the upload targets of jobs, the jobs
+	// only carry references to them. If nil, the secrets are kept in the
+	// job arguments.
+	Secrets *secrets.Vault
+	// Audit records the calls of the mutating admin routes, nil disables
+	// it
+	Audit *audit.Log
+	// Routes send the jobs of other channels to the workers of a pool, in
+	// addition to the jobs of their own channel
+	Routes []Route
 }
 
 func NewServer(logger *log.Logger, jobs jobqueue.JobQueue, config Config) *Server {
@@ -126,6 +156,8 @@ func (s *Server) Handler() http.Handler {
 	}
 
 	mws := []echo.MiddlewareFunc{
+		tracing.ContinueMiddleware("worker"),
+		s.auditAdmin,
 		prometheus.StatusMiddleware(prometheus.WorkerSubsystem),
 	}
 	if s.config.JWTEnabled {
@@ -191,72 +223,107 @@ func (s *Server) WatchWorkers() {
 	}
 }
 
-func (s *Server) EnqueueOSBuild(arch string, job *OSBuildJob, channel string) (uuid.UUID, error) {
-	return s.enqueue(JobTypeOSBuild+":"+arch, job, nil, channel)
+func (s *Server) EnqueueOSBuild(ctx context.Context, arch string, job *OSBuildJob, channel string) (uuid.UUID, error) {
+	return s.enqueueWithRequirements(ctx, JobTypeOSBuild+":"+arch, job, nil, channel, job.Requirements())
 }
 
-func (s *Server) EnqueueOSBuildAsDependency(arch string, job *OSBuildJob, dependencies []uuid.UUID, channel string) (uuid.UUID, error) {
-	return s.enqueue(JobTypeOSBuild+":"+arch, job, dependencies, channel)
+func (s *Server) EnqueueOSBuildAsDependency(ctx context.Context, arch string, job *OSBuildJob, dependencies []uuid.UUID, channel string) (uuid.UUID, error) {
+	return s.enqueueWithRequirements(ctx, JobTypeOSBuild+":"+arch, job, dependencies, channel, job.Requirements())
 }
 
-func (s *Server) EnqueueKojiInit(job *KojiInitJob, channel string) (uuid.UUID, error) {
-	return s.enqueue(JobTypeKojiInit, job, nil, channel)
+func (s *Server) EnqueueKojiInit(ctx context.Context, job *KojiInitJob, channel string) (uuid.UUID, error) {
+	return s.enqueue(ctx, JobTypeKojiInit, job, nil, channel)
 }
 
-func (s *Server) EnqueueKojiFinalize(job *KojiFinalizeJob, initID uuid.UUID, buildIDs []uuid.UUID, channel string) (uuid.UUID, error) {
-	return s.enqueue(JobTypeKojiFinalize, job, append([]uuid.UUID{initID}, buildIDs...), channel)
+func (s *Server) EnqueueKojiFinalize(ctx context.Context, job *KojiFinalizeJob, initID uuid.UUID, buildIDs []uuid.UUID, channel string) (uuid.UUID, error) {
+	return s.enqueue(ctx, JobTypeKojiFinalize, job, append([]uuid.UUID{initID}, buildIDs...), channel)
 }
 
-func (s *Server) EnqueueDepsolve(job *DepsolveJob, channel string) (uuid.UUID, error) {
-	return s.enqueue(JobTypeDepsolve, job, nil, channel)
+func (s *Server) EnqueueDepsolve(ctx context.Context, job *DepsolveJob, channel string) (uuid.UUID, error) {
+	return s.enqueue(ctx, JobTypeDepsolve, job, nil, channel)
 }
 
-func (s *Server) EnqueueSearchPackages(job *SearchPackagesJob, channel string) (uuid.UUID, error) {
-	return s.enqueue(JobTypeSearchPackages, job, nil, channel)
+func (s *Server) EnqueueSearchPackages(ctx context.Context, job *SearchPackagesJob, channel string) (uuid.UUID, error) {
+	return s.enqueue(ctx, JobTypeSearchPackages, job, nil, channel)
 }
 
-func (s *Server) EnqueueManifestJobByID(job *ManifestJobByID, dependencies []uuid.UUID, channel string) (uuid.UUID, error) {
+func (s *Server) EnqueueManifestJobByID(ctx context.Context, job *ManifestJobByID, dependencies []uuid.UUID, channel string) (uuid.UUID, error) {
 	if len(dependencies) == 0 {
 		panic("EnqueueManifestJobByID has no dependencies, expected at least one dependency")
 	}
-	return s.enqueue(JobTypeManifestIDOnly, job, dependencies, channel)
+	return s.enqueue(ctx, JobTypeManifestIDOnly, job, dependencies, channel)
 }
 
-func (s *Server) EnqueueContainerResolveJob(job *ContainerResolveJob, dependencies []uuid.UUID, channel string) (uuid.UUID, error) {
-	return s.enqueue(JobTypeContainerResolve, job, dependencies, channel)
+func (s *Server) EnqueueContainerResolveJob(ctx context.Context, job *ContainerResolveJob, dependencies []uuid.UUID, channel string) (uuid.UUID, error) {
+	return s.enqueue(ctx, JobTypeContainerResolve, job, dependencies, channel)
 }
 
-func (s *Server) EnqueueFileResolveJob(job *FileResolveJob, channel string) (uuid.UUID, error) {
-	return s.enqueue(JobTypeFileResolve, job, nil, channel)
+func (s *Server) EnqueueFileResolveJob(ctx context.Context, job *FileResolveJob, channel string) (uuid.UUID, error) {
+	return s.enqueue(ctx, JobTypeFileResolve, job, nil, channel)
 }
 
-func (s *Server) EnqueueOSTreeResolveJob(job *OSTreeResolveJob, channel string) (uuid.UUID, error) {
-	return s.enqueue(JobTypeOSTreeResolve, job, nil, channel)
+func (s *Server) EnqueueOSTreeResolveJob(ctx context.Context, job *OSTreeResolveJob, channel string) (uuid.UUID, error) {
+	return s.enqueue(ctx, JobTypeOSTreeResolve, job, nil, channel)
 }
 
-func (s *Server) EnqueueAWSEC2CopyJob(job *AWSEC2CopyJob, parent uuid.UUID, channel string) (uuid.UUID, error) {
-	return s.enqueue(JobTypeAWSEC2Copy, job, []uuid.UUID{parent}, channel)
+func (s *Server) EnqueueAWSEC2CopyJob(ctx context.Context, job *AWSEC2CopyJob, parent uuid.UUID, channel string) (uuid.UUID, error) {
+	return s.enqueue(ctx, JobTypeAWSEC2Copy, job, []uuid.UUID{parent}, channel)
 }
 
-func (s *Server) EnqueueAWSEC2ShareJob(job *AWSEC2ShareJob, parent uuid.UUID, channel string) (uuid.UUID, error) {
-	return s.enqueue(JobTypeAWSEC2Share, job, []uuid.UUID{parent}, channel)
+func (s *Server) EnqueueAWSEC2ShareJob(ctx context.Context, job *AWSEC2ShareJob, parent uuid.UUID, channel string) (uuid.UUID, error) {
+	return s.enqueue(ctx, JobTypeAWSEC2Share, job, []uuid.UUID{parent}, channel)
 }
 
-func (s *Server) EnqueueImageBuilderManifestJob(job *ImageBuilderManifestJob, channel string) (uuid.UUID, error) {
-	return s.enqueue(JobTypeImageBuilderManifest, job, nil, channel)
+func (s *Server) EnqueueImageBuilderManifestJob(ctx context.Context, job *ImageBuilderManifestJob, channel string) (uuid.UUID, error) {
+	return s.enqueue(ctx, JobTypeImageBuilderManifest, job, nil, channel)
 }
 
-func (s *Server) EnqueueBootcInfoResolveJob(arch string, job *BootcInfoResolveJob, channel string) (uuid.UUID, error) {
-	return s.enqueue(JobTypeBootcInfoResolve+":"+arch, job, nil, channel)
+func (s *Server) EnqueueBootcInfoResolveJob(ctx context.Context, arch string, job *BootcInfoResolveJob, channel string) (uuid.UUID, error) {
+	return s.enqueue(ctx, JobTypeBootcInfoResolve+":"+arch, job, nil, channel)
 }
 
-func (s *Server) EnqueueBootcPreManifestJob(job *BootcPreManifestJob, dependencies []uuid.UUID, channel string) (uuid.UUID, error) {
-	return s.enqueue(JobTypeBootcPreManifest, job, dependencies, channel)
+func (s *Server) EnqueueRepositoryCheckJob(ctx context.Context, job *RepositoryCheckJob, channel string) (uuid.UUID, error) {
+	return s.enqueue(ctx, JobTypeRepositoryCheck, job, nil, channel)
 }
 
-func (s *Server) enqueue(jobType string, job interface{}, dependencies []uuid.UUID, channel string) (uuid.UUID, error) {
+func (s *Server) EnqueueSourceBundleExportJob(ctx context.Context, job *SourceBundleExportJob, dependencies []uuid.UUID, channel string) (uuid.UUID, error) {
+	return s.enqueue(ctx, JobTypeSourceBundleExport, job, dependencies, channel)
+}
+
+func (s *Server) EnqueueSourceBundleImportJob(ctx context.Context, job *SourceBundleImportJob, channel string) (uuid.UUID, error) {
+	return s.enqueue(ctx, JobTypeSourceBundleImport, job, nil, channel)
+}
+
+func (s *Server) EnqueueBootcPreManifestJob(ctx context.Context, job *BootcPreManifestJob, dependencies []uuid.UUID, channel string) (uuid.UUID, error) {
+	return s.enqueue(ctx, JobTypeBootcPreManifest, job, dependencies, channel)
+}
+
+func (s *Server) enqueue(ctx context.Context, jobType string, job interface{}, dependencies []uuid.UUID, channel string) (uuid.UUID, error) {
+	return s.enqueueWithRequirements(ctx, jobType, job, dependencies, channel, nil)
+}
+
+// enqueueWithRequirements enqueues a job which is only handed out to workers
+// having the required capabilities. The trace context of ctx is stored in the
+// arguments of the job, so that the job is part of the same trace.
+func (s *Server) enqueueWithRequirements(ctx context.Context, jobType string, job interface{}, dependencies []uuid.UUID, channel string, requirements []string) (id uuid.UUID, err error) {
+	ctx, span := tracing.Start(ctx, "enqueue "+jobType,
+		attribute.String("composer.job.type", jobType),
+		attribute.String("composer.channel", channel))
+	defer func() {
+		span.SetAttributes(attribute.String("composer.job.id", id.String()))
+		tracing.End(span, err)
+	}()
+
+	args, err := tracing.InjectArgs(ctx, job)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
 	prometheus.EnqueueJobMetrics(strings.Split(jobType, ":")[0], channel)
-	return s.jobs.Enqueue(jobType, job, dependencies, channel)
+	if len(requirements) == 0 {
+		return s.jobs.Enqueue(jobType, args, dependencies, channel)
+	}
+	return s.jobs.EnqueueWithRequirements(jobType, args, dependencies, channel, requirements)
 }
 
 // DependencyChainErrors recursively gathers all errors from job's dependencies,
@@ -360,6 +427,27 @@ func (s *Server) JobDependencyChainErrors(id uuid.UUID) (*clienterrors.Error, er
 			return nil, err
 		}
 		jobResult = &bootcPreManifestJR.JobResult
+	case JobTypeRepositoryCheck:
+		var repositoryCheckJR RepositoryCheckJobResult
+		jobInfo, err = s.RepositoryCheckJobInfo(id, &repositoryCheckJR)
+		if err != nil {
+			return nil, err
+		}
+		jobResult = &repositoryCheckJR.JobResult
+	case JobTypeSourceBundleExport:
+		var sourceBundleExportJR SourceBundleExportJobResult
+		jobInfo, err = s.SourceBundleExportJobInfo(id, &sourceBundleExportJR)
+		if err != nil {
+			return nil, err
+		}
+		jobResult = &sourceBundleExportJR.JobResult
+	case JobTypeSourceBundleImport:
+		var sourceBundleImportJR SourceBundleImportJobResult
+		jobInfo, err = s.SourceBundleImportJobInfo(id, &sourceBundleImportJR)
+		if err != nil {
+			return nil, err
+		}
+		jobResult = &sourceBundleImportJR.JobResult
 	default:
 		return nil, fmt.Errorf("unexpected job type: %s", jobType)
 	}
@@ -513,6 +601,43 @@ func (s *Server) DepsolveJobInfo(id uuid.UUID, result *DepsolveJobResult) (*JobI
 	return jobInfo, nil
 }
 
+// OSBuildJobDepsolveInfo returns JobInfo for the depsolve job the manifest of
+// an osbuild job was serialized from and populates the result with its
+// DepsolveJobResult. The depsolve job is a dependency of the manifest job the
+// osbuild job depends on. ErrNoDepsolveJob is returned for osbuild jobs with
+// a static manifest.
+func (s *Server) OSBuildJobDepsolveInfo(osbuildJobID uuid.UUID, result *DepsolveJobResult) (*JobInfo, error) {
+	osbuildJobInfo, err := s.jobInfo(osbuildJobID, nil)
+	if err != nil {
+		return nil, err
+	}
+	if osbuildJobInfo.JobType != JobTypeOSBuild {
+		return nil, fmt.Errorf("expected %q, found %q job instead", JobTypeOSBuild, osbuildJobInfo.JobType)
+	}
+
+	for _, manifestJobID := range osbuildJobInfo.Deps {
+		manifestJobInfo, err := s.jobInfo(manifestJobID, nil)
+		if err != nil {
+			return nil, err
+		}
+		if manifestJobInfo.JobType != JobTypeManifestIDOnly {
+			continue
+		}
+
+		for _, id := range manifestJobInfo.Deps {
+			jobType, err := s.JobType(id)
+			if err != nil {
+				return nil, err
+			}
+			if jobType == JobTypeDepsolve {
+				return s.DepsolveJobInfo(id, result)
+			}
+		}
+	}
+
+	return nil, ErrNoDepsolveJob
+}
+
 // SearchPackagesJobInfo returns JobInfo for a Search job
 // and populates the result with the SearchJobResult data
 func (s *Server) SearchPackagesJobInfo(id uuid.UUID, result *SearchPackagesJobResult) (*JobInfo, error) {
@@ -648,6 +773,45 @@ func (s *Server) BootcPreManifestJobInfo(id uuid.UUID, result *BootcPreManifestJ
 	return jobInfo, nil
 }
 
+func (s *Server) RepositoryCheckJobInfo(id uuid.UUID, result *RepositoryCheckJobResult) (*JobInfo, error) {
+	jobInfo, err := s.jobInfo(id, result)
+	if err != nil {
+		return nil, err
+	}
+
+	if jobInfo.JobType != JobTypeRepositoryCheck {
+		return nil, fmt.Errorf("expected %q, found %q job instead", JobTypeRepositoryCheck, jobInfo.JobType)
+	}
+
+	return jobInfo, nil
+}
+
+func (s *Server) SourceBundleExportJobInfo(id uuid.UUID, result *SourceBundleExportJobResult) (*JobInfo, error) {
+	jobInfo, err := s.jobInfo(id, result)
+	if err != nil {
+		return nil, err
+	}
+
+	if jobInfo.JobType != JobTypeSourceBundleExport {
+		return nil, fmt.Errorf("expected %q, found %q job instead", JobTypeSourceBundleExport, jobInfo.JobType)
+	}
+
+	return jobInfo, nil
+}
+
+func (s *Server) SourceBundleImportJobInfo(id uuid.UUID, result *SourceBundleImportJobResult) (*JobInfo, error) {
+	jobInfo, err := s.jobInfo(id, result)
+	if err != nil {
+		return nil, err
+	}
+
+	if jobInfo.JobType != JobTypeSourceBundleImport {
+		return nil, fmt.Errorf("expected %q, found %q job instead", JobTypeSourceBundleImport, jobInfo.JobType)
+	}
+
+	return jobInfo, nil
+}
+
 func (s *Server) jobInfo(id uuid.UUID, result interface{}) (*JobInfo, error) {
 	jobType, channel, rawResult, queued, started, finished, canceled, deps, dependents, err := s.jobs.JobStatus(id)
 	if err != nil {
@@ -676,6 +840,12 @@ func (s *Server) jobInfo(id uuid.UUID, result interface{}) (*JobInfo, error) {
 	}, nil
 }
 
+// GenericJobInfo returns the status and dependencies of a job of any type,
+// together with the common part of its result
+func (s *Server) GenericJobInfo(id uuid.UUID, result *JobResult) (*JobInfo, error) {
+	return s.jobInfo(id, result)
+}
+
 // OSBuildJob returns the parameters of an OSBuildJob
 func (s *Server) OSBuildJob(id uuid.UUID, job *OSBuildJob) error {
 	jobType, rawArgs, _, _, err := s.jobs.Job(id)
@@ -699,6 +869,22 @@ func (s *Server) JobChannel(id uuid.UUID) (string, error) {
 	return channel, err
 }
 
+// ShareJob grants the channel the permission on the job, replacing the
+// permission of an existing grant
+func (s *Server) ShareJob(id uuid.UUID, channel, permission string) error {
+	return s.jobs.ShareJob(id, channel, permission)
+}
+
+// UnshareJob revokes the grant of the channel on the job
+func (s *Server) UnshareJob(id uuid.UUID, channel string) error {
+	return s.jobs.UnshareJob(id, channel)
+}
+
+// JobShares returns the grants of other channels on the job
+func (s *Server) JobShares(id uuid.UUID) ([]jobqueue.Share, error) {
+	return s.jobs.JobShares(id)
+}
+
 // JobTypeWithArch returns the type and architecture of the job
 // If the job type does not have an architecture, the second return value will be an empty string.
 func (s *Server) JobTypeWithArch(id uuid.UUID) (string, string, error) {
@@ -749,6 +935,47 @@ func (s *Server) ArtifactsDir() string {
 	return s.config.ArtifactsDir
 }
 
+// CloudCredentials returns the registry of the cloud credentials of the
+// tenants, nil if it isn't configured
+func (s *Server) CloudCredentials() *cloudcredentials.Registry {
+	return s.config.CloudCredentials
+}
+
+// SealTargetSecrets replaces the secrets in the options of targets, like
+// passwords and access keys, with references to the vault of the server.
+// They are sealed for channel, only jobs of that channel can resolve them.
+func (s *Server) SealTargetSecrets(channel string, targets []*target.Target) error {
+	if s.config.Secrets == nil {
+		return nil
+	}
+	for _, t := range targets {
+		err := target.SealSecrets(t, func(value string) (string, error) {
+			return s.config.Secrets.Seal(channel, value)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteTargetSecrets removes the secrets targets refer to from the vault
+// of the server
+func (s *Server) DeleteTargetSecrets(targets []*target.Target) error {
+	if s.config.Secrets == nil {
+		return nil
+	}
+	for _, t := range targets {
+		for _, ref := range target.SecretRefs(t) {
+			err := s.config.Secrets.Delete(ref)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // Provides access to artifacts of a job. Returns an io.Reader for the artifact
 // and the artifact's size.
 func (s *Server) JobArtifact(id uuid.UUID, name string) (io.Reader, int64, error) {
@@ -823,7 +1050,11 @@ func (s *Server) DeleteArtifacts(id uuid.UUID) error {
 
 func (s *Server) RequestJob(ctx context.Context, arch string, jobTypes, channels []string, workerID uuid.UUID) (uuid.UUID, uuid.UUID, string, json.RawMessage, []json.RawMessage, error) {
 	return s.requestJob(ctx, arch, jobTypes, func(ctx context.Context, jts []string) (uuid.UUID, uuid.UUID, []uuid.UUID, string, json.RawMessage, error) {
-		return s.jobs.Dequeue(ctx, workerID, jts, channels)
+		routes := s.queueRoutes(arch, jts, channels)
+		if len(routes) == 1 {
+			return s.jobs.Dequeue(ctx, workerID, jts, channels)
+		}
+		return s.jobs.DequeueRoutes(ctx, workerID, routes)
 	})
 }
 
@@ -910,6 +1141,14 @@ func (s *Server) requestJob(ctx context.Context, arch string, jobTypes []string,
 		dynamicArgs = append(dynamicArgs, result)
 	}
 
+	// the span covers the time the job waited for a worker
+	_, span := tracing.StartAt(tracing.ExtractArgs(ctx, args), "dequeue "+jobType, pending,
+		attribute.String("composer.job.id", jobId.String()),
+		attribute.String("composer.channel", jobInfo.Channel))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
 	if s.config.ArtifactsDir != "" {
 		err = os.MkdirAll(path.Join(s.config.ArtifactsDir, "tmp", token.String()), 0700)
 		if err != nil {
@@ -950,7 +1189,7 @@ func (s *Server) FinishJob(token uuid.UUID, result json.RawMessage) error {
 	return s.RequeueOrFinishJob(token, 0, result)
 }
 
-func (s *Server) RequeueOrFinishJob(token uuid.UUID, maxRetries uint64, result json.RawMessage) error {
+func (s *Server) RequeueOrFinishJob(token uuid.UUID, maxRetries uint64, result json.RawMessage) (err error) {
 	jobId, err := s.jobs.IdFromToken(token)
 	if err != nil {
 		switch err {
@@ -969,6 +1208,17 @@ func (s *Server) RequeueOrFinishJob(token uuid.UUID, maxRetries uint64, result j
 		return fmt.Errorf("error fetching job info: %v", err)
 	}
 
+	_, args, _, _, err := s.jobs.Job(jobId)
+	if err != nil {
+		return fmt.Errorf("error fetching job arguments: %v", err)
+	}
+	_, span := tracing.Start(tracing.ExtractArgs(context.Background(), args), "finish "+preJobInfo.JobType,
+		attribute.String("composer.job.id", jobId.String()),
+		attribute.String("composer.channel", preJobInfo.Channel))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
 	requeued, err := s.jobs.RequeueOrFinishJob(jobId, maxRetries, result)
 	if err != nil {
 		switch err {
@@ -978,6 +1228,7 @@ func (s *Server) RequeueOrFinishJob(token uuid.UUID, maxRetries uint64, result j
 			return fmt.Errorf("error finishing job: %v", err)
 		}
 	}
+	span.SetAttributes(attribute.Bool("composer.job.requeued", requeued))
 
 	if requeued {
 		prometheus.RequeueJobMetrics(preJobInfo.JobType, preJobInfo.Channel)
@@ -999,6 +1250,16 @@ func (s *Server) RequeueOrFinishJob(token uuid.UUID, maxRetries uint64, result j
 			return err
 		}
 		jobResult = &osbuildJR.JobResult
+		if stats := osbuildJR.StoreStats; stats != nil {
+			prometheus.StoreMetrics(jobArch, stats.SourceHits, stats.SourceMisses, stats.DownloadedBytes, stats.EvictedBytes)
+		}
+		if timings := osbuildJR.Timings; timings != nil {
+			var osbuildArgs OSBuildJob
+			if err := json.Unmarshal(args, &osbuildArgs); err != nil {
+				logrus.Errorf("Error reading the arguments of job %s for its metrics: %v", jobId, err)
+			}
+			osbuildTimingsMetrics(timings, jobInfo.Channel, jobArch, osbuildArgs.ImageType)
+		}
 
 	case JobTypeDepsolve:
 		var depsolveJR DepsolveJobResult
@@ -1007,6 +1268,13 @@ func (s *Server) RequeueOrFinishJob(token uuid.UUID, maxRetries uint64, result j
 			return err
 		}
 		jobResult = &depsolveJR.JobResult
+		if depsolveJR.SolveSeconds > 0 {
+			var depsolveArgs DepsolveJob
+			if err := json.Unmarshal(args, &depsolveArgs); err != nil {
+				logrus.Errorf("Error reading the arguments of job %s for its metrics: %v", jobId, err)
+			}
+			prometheus.DepsolveMetrics(depsolveArgs.Distro, depsolveJR.SolveSeconds, jobInfo.Channel, depsolveArgs.Arch)
+		}
 
 	case JobTypeSearchPackages:
 		var searchJR SearchPackagesJobResult
@@ -1060,6 +1328,13 @@ func (s *Server) RequeueOrFinishJob(token uuid.UUID, maxRetries uint64, result j
 			return err
 		}
 		jobResult = &containerResolveJR.JobResult
+		if containerResolveJR.ResolveSeconds > 0 {
+			var containerResolveArgs ContainerResolveJob
+			if err := json.Unmarshal(args, &containerResolveArgs); err != nil {
+				logrus.Errorf("Error reading the arguments of job %s for its metrics: %v", jobId, err)
+			}
+			prometheus.ContainerResolveMetrics(containerResolveJR.ResolveSeconds, jobInfo.Channel, containerResolveArgs.Arch)
+		}
 	case JobTypeFileResolve:
 		var fileResolveJR FileResolveJobResult
 		jobInfo, err = s.FileResolveJobInfo(jobId, &fileResolveJR)
@@ -1095,6 +1370,27 @@ func (s *Server) RequeueOrFinishJob(token uuid.UUID, maxRetries uint64, result j
 			return err
 		}
 		jobResult = &bootcPreManifestJR.JobResult
+	case JobTypeRepositoryCheck:
+		var repositoryCheckJR RepositoryCheckJobResult
+		jobInfo, err = s.RepositoryCheckJobInfo(jobId, &repositoryCheckJR)
+		if err != nil {
+			return err
+		}
+		jobResult = &repositoryCheckJR.JobResult
+	case JobTypeSourceBundleExport:
+		var sourceBundleExportJR SourceBundleExportJobResult
+		jobInfo, err = s.SourceBundleExportJobInfo(jobId, &sourceBundleExportJR)
+		if err != nil {
+			return err
+		}
+		jobResult = &sourceBundleExportJR.JobResult
+	case JobTypeSourceBundleImport:
+		var sourceBundleImportJR SourceBundleImportJobResult
+		jobInfo, err = s.SourceBundleImportJobInfo(jobId, &sourceBundleImportJR)
+		if err != nil {
+			return err
+		}
+		jobResult = &sourceBundleImportJR.JobResult
 	default:
 		return fmt.Errorf("unexpected job type: %s", jobType)
 	}
@@ -1115,15 +1411,50 @@ func (s *Server) RequeueOrFinishJob(token uuid.UUID, maxRetries uint64, result j
 	return nil
 }
 
-func (s *Server) RegisterWorker(c, a string) (uuid.UUID, error) {
-	workerID, err := s.jobs.InsertWorker(c, a)
+// osbuildTimingsMetrics records the timings a worker reported for a build
+func osbuildTimingsMetrics(timings *OSBuildTimings, tenant, arch, imageType string) {
+	for _, step := range timings.Steps {
+		prometheus.OSBuildStepMetrics(step.Pipeline, step.Stage, step.Seconds, tenant, arch, imageType)
+	}
+	if timings.Executor != "" {
+		prometheus.ExecutorProvisioningMetrics(timings.Executor, timings.ExecutorProvisioningSeconds, tenant, arch)
+	}
+	for _, upload := range timings.Uploads {
+		prometheus.UploadMetrics(upload.Target, upload.Success, upload.Seconds, upload.Bytes, tenant, arch, imageType)
+	}
+}
+
+// RegisterWorker registers a worker of the channel and arch. Workers
+// registered with 'nil' capabilities can run any job.
+func (s *Server) RegisterWorker(c, a, version string, capabilities []string) (uuid.UUID, error) {
+	workerID, err := s.jobs.InsertWorker(c, a, version, capabilities)
 	if err != nil {
 		return uuid.Nil, err
 	}
-	logrus.Infof("Worker (%v) registered", a)
+	logrus.Infof("Worker (%v, version %q) registered with capabilities %v", a, version, capabilities)
 	return workerID, nil
 }
 
+// CheckJobQueue verifies the backend of the job queue, queues which can't
+// check their backend are assumed to be fine
+func (s *Server) CheckJobQueue(ctx context.Context) error {
+	checker, ok := s.jobs.(jobqueue.HealthChecker)
+	if !ok {
+		return nil
+	}
+	return checker.CheckHealth(ctx)
+}
+
+// CheckJobQueueSchema verifies that the backend of the job queue is migrated
+// to the schema of the queue, queues without a schema are assumed to be fine
+func (s *Server) CheckJobQueueSchema(ctx context.Context) error {
+	checker, ok := s.jobs.(jobqueue.SchemaChecker)
+	if !ok {
+		return nil
+	}
+	return checker.CheckSchema(ctx)
+}
+
 func (s *Server) WorkerAvailableForArch(a string) (bool, error) {
 	workers, err := s.jobs.Workers(0)
 	if err != nil {
@@ -1354,6 +1685,102 @@ func (h *apiHandlers) UploadJobArtifact(ctx echo.Context, tokenstr string, name
 	return ctx.NoContent(http.StatusOK)
 }
 
+// jobOfToken returns the ID, the channel and the arguments of the job of
+// tokenstr
+func (h *apiHandlers) jobOfToken(tokenstr string) (uuid.UUID, string, json.RawMessage, error) {
+	token, err := uuid.Parse(tokenstr)
+	if err != nil {
+		return uuid.Nil, "", nil, api.HTTPErrorWithInternal(api.ErrorMalformedJobToken, err)
+	}
+
+	jobId, err := h.server.jobs.IdFromToken(token)
+	if err != nil {
+		switch err {
+		case jobqueue.ErrNotExist:
+			return uuid.Nil, "", nil, api.HTTPError(api.ErrorJobNotFound)
+		default:
+			return uuid.Nil, "", nil, api.HTTPErrorWithInternal(api.ErrorResolvingJobId, err)
+		}
+	}
+
+	_, args, _, channel, err := h.server.jobs.Job(jobId)
+	if err != nil {
+		return uuid.Nil, "", nil, api.HTTPErrorWithInternal(api.ErrorRetrievingJobStatus, err)
+	}
+	return jobId, channel, args, nil
+}
+
+func (h *apiHandlers) GetJobCloudCredentials(ctx echo.Context, tokenstr string, id uuid.UUID) error {
+	_, channel, args, err := h.jobOfToken(tokenstr)
+	if err != nil {
+		return err
+	}
+
+	registry := h.server.config.CloudCredentials
+	if registry == nil {
+		return api.HTTPError(api.ErrorCloudCredentialsNotFound)
+	}
+
+	// jobs only get the credentials of the tenant they belong to, and only
+	// the ones their targets refer to
+	if !bytes.Contains(args, []byte(id.String())) {
+		return api.HTTPError(api.ErrorCloudCredentialsNotFound)
+	}
+	creds, err := registry.Reveal(channel, id)
+	if errors.Is(err, cloudcredentials.ErrNotFound) {
+		return api.HTTPError(api.ErrorCloudCredentialsNotFound)
+	}
+	if err != nil {
+		return api.HTTPErrorWithInternal(api.ErrorResolvingCloudCredentials, err)
+	}
+
+	rawCreds, err := json.Marshal(creds)
+	if err != nil {
+		return api.HTTPErrorWithInternal(api.ErrorResolvingCloudCredentials, err)
+	}
+
+	return ctx.JSON(http.StatusOK, api.GetJobCloudCredentialsResponse{
+		Href:        fmt.Sprintf("%s/jobs/%v/cloud-credentials/%v", api.BasePath, tokenstr, id),
+		Id:          id.String(),
+		Kind:        "CloudCredentials",
+		Credentials: rawCreds,
+	})
+}
+
+func (h *apiHandlers) GetJobSecret(ctx echo.Context, tokenstr string, id uuid.UUID) error {
+	_, channel, args, err := h.jobOfToken(tokenstr)
+	if err != nil {
+		return err
+	}
+
+	vault := h.server.config.Secrets
+	if vault == nil {
+		return api.HTTPError(api.ErrorSecretNotFound)
+	}
+
+	// a job only resolves the secrets in its own arguments, the vault makes
+	// sure they belong to its tenant. Secrets in binary fields, like the GCP
+	// credentials, are base64 encoded in the arguments.
+	ref := secrets.Ref(id)
+	if !bytes.Contains(args, []byte(ref)) && !bytes.Contains(args, []byte(base64.StdEncoding.EncodeToString([]byte(ref)))) {
+		return api.HTTPError(api.ErrorSecretNotFound)
+	}
+	value, err := vault.Open(channel, ref)
+	if errors.Is(err, secrets.ErrNotFound) {
+		return api.HTTPError(api.ErrorSecretNotFound)
+	}
+	if err != nil {
+		return api.HTTPErrorWithInternal(api.ErrorResolvingSecret, err)
+	}
+
+	return ctx.JSON(http.StatusOK, api.GetJobSecretResponse{
+		Href:   fmt.Sprintf("%s/jobs/%v/secrets/%v", api.BasePath, tokenstr, id),
+		Id:     id.String(),
+		Kind:   "Secret",
+		Secret: value,
+	})
+}
+
 func (h *apiHandlers) PostWorkers(ctx echo.Context) error {
 	var body api.PostWorkersRequest
 	err := ctx.Bind(&body)
@@ -1361,18 +1788,16 @@ func (h *apiHandlers) PostWorkers(ctx echo.Context) error {
 		return err
 	}
 
-	var channel string
-	if h.server.config.JWTEnabled {
-		tenant, err := auth.GetFromClaims(ctx.Request().Context(), h.server.config.TenantProviderFields)
-		if err != nil {
-			return api.HTTPErrorWithInternal(api.ErrorTenantNotFound, err)
-		}
-
-		// prefix the tenant to prevent collisions if support for specifying channels in a request is ever added
-		channel = "org-" + tenant
+	channel, err := h.workerChannel(ctx)
+	if err != nil {
+		return err
 	}
 
-	workerID, err := h.server.RegisterWorker(channel, body.Arch)
+	var capabilities []string
+	if body.Capabilities != nil {
+		capabilities = *body.Capabilities
+	}
+	workerID, err := h.server.RegisterWorker(channel, body.Arch, common.DerefOrDefault(body.Version), capabilities)
 	if err != nil {
 		return api.HTTPErrorWithInternal(api.ErrorInsertingWorker, err)
 	}
@@ -1385,6 +1810,56 @@ func (h *apiHandlers) PostWorkers(ctx echo.Context) error {
 	})
 }
 
+// workerChannel returns the channel of the calling worker, it's empty if JWT
+// is not enabled
+func (h *apiHandlers) workerChannel(ctx echo.Context) (string, error) {
+	if !h.server.config.JWTEnabled {
+		return "", nil
+	}
+	tenant, err := auth.GetFromClaims(ctx.Request().Context(), h.server.config.TenantProviderFields)
+	if err != nil {
+		return "", api.HTTPErrorWithInternal(api.ErrorTenantNotFound, err)
+	}
+
+	// prefix the tenant to prevent collisions if support for specifying channels in a request is ever added
+	return "org-" + tenant, nil
+}
+
+func (h *apiHandlers) DeleteWorker(ctx echo.Context, workerID uuid.UUID) error {
+	// workers only deregister workers of their own channel, admins any
+	if !h.server.isAdmin(ctx.Request()) {
+		channel, err := h.workerChannel(ctx)
+		if err != nil {
+			return err
+		}
+		w, err := h.server.worker(workerID)
+		if err == jobqueue.ErrWorkerNotExist {
+			return api.HTTPErrorWithInternal(api.ErrorWorkerIdNotFound, err)
+		}
+		if err != nil {
+			return api.HTTPErrorWithInternal(api.ErrorDeletingWorker, err)
+		}
+		// don't reveal the workers of other channels
+		if w.Channel != channel {
+			return api.HTTPError(api.ErrorWorkerIdNotFound)
+		}
+	}
+
+	err := h.server.jobs.DeleteWorker(workerID)
+	if err == jobqueue.ErrWorkerNotExist {
+		return api.HTTPErrorWithInternal(api.ErrorWorkerIdNotFound, err)
+	}
+	if err == jobqueue.ErrActiveJobs {
+		return api.HTTPErrorWithInternal(api.ErrorWorkerHasActiveJobs, err)
+	}
+	if err != nil {
+		return api.HTTPErrorWithInternal(api.ErrorDeletingWorker, err)
+	}
+
+	logrus.Infof("Worker %s deregistered", workerID)
+	return ctx.NoContent(http.StatusNoContent)
+}
+
 func (h *apiHandlers) PostWorkerStatus(ctx echo.Context, workerID uuid.UUID) error {
 	err := h.server.jobs.UpdateWorkerStatus(workerID)
 
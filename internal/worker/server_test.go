@@ -2,10 +2,14 @@ package worker_test
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path"
 	"testing"
@@ -22,9 +26,12 @@ import (
 	"github.com/osbuild/image-builder/pkg/distro"
 	"github.com/osbuild/image-builder/pkg/distro/test_distro"
 	"github.com/osbuild/image-builder/pkg/osbuild"
+	"github.com/osbuild/osbuild-composer/internal/audit"
+	"github.com/osbuild/osbuild-composer/internal/cloudcredentials"
 	"github.com/osbuild/osbuild-composer/internal/common"
 	"github.com/osbuild/osbuild-composer/internal/jobqueue/fsjobqueue"
 	"github.com/osbuild/osbuild-composer/internal/prometheus"
+	"github.com/osbuild/osbuild-composer/internal/secrets"
 	"github.com/osbuild/osbuild-composer/internal/target"
 	"github.com/osbuild/osbuild-composer/internal/test"
 	"github.com/osbuild/osbuild-composer/internal/worker"
@@ -166,7 +173,7 @@ func TestCreate(t *testing.T) {
 	server := newTestServer(t, t.TempDir(), defaultConfig, false)
 	handler := server.Handler()
 
-	_, err = server.EnqueueOSBuild(arch.Name(), &worker.OSBuildJob{Manifest: mf}, "")
+	_, err = server.EnqueueOSBuild(context.Background(), arch.Name(), &worker.OSBuildJob{Manifest: mf}, "")
 	require.NoError(t, err)
 	require.Equal(t, float64(1), promtest.ToFloat64(prometheus.PendingJobs))
 
@@ -197,7 +204,7 @@ func TestCancel(t *testing.T) {
 	}
 	handler := server.Handler()
 
-	jobId, err := server.EnqueueOSBuild(arch.Name(), &worker.OSBuildJob{Manifest: mf}, "")
+	jobId, err := server.EnqueueOSBuild(context.Background(), arch.Name(), &worker.OSBuildJob{Manifest: mf}, "")
 	require.NoError(t, err)
 	require.Equal(t, float64(1), promtest.ToFloat64(prometheus.PendingJobs))
 
@@ -241,7 +248,7 @@ func TestUpdate(t *testing.T) {
 	}
 	handler := server.Handler()
 
-	jobId, err := server.EnqueueOSBuild(arch.Name(), &worker.OSBuildJob{Manifest: mf}, "")
+	jobId, err := server.EnqueueOSBuild(context.Background(), arch.Name(), &worker.OSBuildJob{Manifest: mf}, "")
 	require.NoError(t, err)
 	require.Equal(t, float64(1), promtest.ToFloat64(prometheus.PendingJobs))
 
@@ -308,7 +315,7 @@ func TestArgs(t *testing.T) {
 	}
 
 	server := newTestServer(t, t.TempDir(), defaultConfig, false)
-	jobId, err := server.EnqueueOSBuild(arch.Name(), &job, "")
+	jobId, err := server.EnqueueOSBuild(context.Background(), arch.Name(), &job, "")
 	require.NoError(t, err)
 
 	_, _, _, args, _, err := server.RequestJob(context.Background(), arch.Name(), []string{worker.JobTypeOSBuild}, []string{""}, uuid.Nil)
@@ -343,7 +350,7 @@ func TestUpload(t *testing.T) {
 	}
 	handler := server.Handler()
 
-	jobID, err := server.EnqueueOSBuild(arch.Name(), &worker.OSBuildJob{Manifest: mf}, "")
+	jobID, err := server.EnqueueOSBuild(context.Background(), arch.Name(), &worker.OSBuildJob{Manifest: mf}, "")
 	require.NoError(t, err)
 
 	j, token, typ, args, dynamicArgs, err := server.RequestJob(context.Background(), arch.Name(), []string{worker.JobTypeOSBuild}, []string{""}, uuid.Nil)
@@ -365,6 +372,38 @@ func TestUpload(t *testing.T) {
 	require.Equal(t, fmt.Sprintf("%s/artifacts/%s/foobar", storeDir, jobID), path)
 }
 
+func TestBuildMetrics(t *testing.T) {
+	server := newTestServer(t, t.TempDir(), defaultConfig, false)
+
+	jobID, err := server.EnqueueOSBuild(context.Background(), test_distro.TestArchName, &worker.OSBuildJob{ImageType: test_distro.TestImageTypeName}, "")
+	require.NoError(t, err)
+	j, token, _, _, _, err := server.RequestJob(context.Background(), test_distro.TestArchName, []string{worker.JobTypeOSBuild}, []string{""}, uuid.Nil)
+	require.NoError(t, err)
+	require.Equal(t, jobID, j)
+
+	result, err := json.Marshal(worker.OSBuildJobResult{
+		Timings: &worker.OSBuildTimings{
+			Steps: []worker.OSBuildStepTiming{
+				{Pipeline: "os", Stage: "org.osbuild.rpm", Seconds: 42},
+				{Pipeline: "os", Seconds: 50},
+			},
+			Executor:                    "aws.ec2",
+			ExecutorProvisioningSeconds: 90,
+			Uploads: []worker.OSBuildUploadTiming{
+				{Target: "org.osbuild.aws", Seconds: 300, Bytes: 1 << 30, Success: true},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, server.FinishJob(token, result))
+
+	require.Equal(t, 1, promtest.CollectAndCount(prometheus.OSBuildStageDuration))
+	require.Equal(t, 1, promtest.CollectAndCount(prometheus.OSBuildPipelineDuration))
+	require.Equal(t, 1, promtest.CollectAndCount(prometheus.ExecutorProvisioningDuration))
+	require.Equal(t, 1, promtest.CollectAndCount(prometheus.UploadDuration))
+	require.Equal(t, 1, promtest.CollectAndCount(prometheus.UploadSize))
+}
+
 func TestUploadNotAcceptingArtifacts(t *testing.T) {
 	distroStruct := newTestDistro(t)
 	arch, err := distroStruct.GetArch(test_distro.TestArchName)
@@ -386,7 +425,7 @@ func TestUploadNotAcceptingArtifacts(t *testing.T) {
 		t.Fatalf("error creating osbuild manifest: %v", err)
 	}
 
-	jobID, err := server.EnqueueOSBuild(arch.Name(), &worker.OSBuildJob{Manifest: mf}, "")
+	jobID, err := server.EnqueueOSBuild(context.Background(), arch.Name(), &worker.OSBuildJob{Manifest: mf}, "")
 	require.NoError(t, err)
 
 	j, token, typ, args, dynamicArgs, err := server.RequestJob(context.Background(), arch.Name(), []string{worker.JobTypeOSBuild}, []string{""}, uuid.Nil)
@@ -399,6 +438,115 @@ func TestUploadNotAcceptingArtifacts(t *testing.T) {
 	test.TestRoute(t, handler, false, "PUT", fmt.Sprintf("/api/worker/v1/jobs/%s/artifacts/foobar", token), `this is my artifact`, http.StatusBadRequest, `?`)
 }
 
+func TestJobCloudCredentials(t *testing.T) {
+	vault, err := secrets.NewVault(make([]byte, secrets.KeySize), secrets.NewMemoryBackend())
+	require.NoError(t, err)
+	registry, err := cloudcredentials.NewRegistry("", vault)
+	require.NoError(t, err)
+	creds, err := registry.Add("org-1", cloudcredentials.Credentials{
+		Name:  "azure",
+		Azure: &cloudcredentials.AzureServicePrincipal{ClientID: "id", ClientSecret: "secret"},
+	})
+	require.NoError(t, err)
+	otherCreds, err := registry.Add("org-2", cloudcredentials.Credentials{
+		Name:  "azure",
+		Azure: &cloudcredentials.AzureServicePrincipal{ClientID: "other-id", ClientSecret: "other-secret"},
+	})
+	require.NoError(t, err)
+
+	config := defaultConfig
+	config.CloudCredentials = registry
+	server := newTestServer(t, t.TempDir(), config, false)
+	handler := server.Handler()
+
+	unreferenced, err := registry.Add("org-1", cloudcredentials.Credentials{
+		Name:  "unreferenced",
+		Azure: &cloudcredentials.AzureServicePrincipal{ClientID: "id", ClientSecret: "secret"},
+	})
+	require.NoError(t, err)
+
+	jobID, err := server.EnqueueOSBuild(context.Background(), test_distro.TestArchName, &worker.OSBuildJob{
+		Targets: []*target.Target{
+			target.NewAzureImageTarget(&target.AzureImageTargetOptions{CloudCredentials: &creds.ID}),
+			target.NewAzureImageTarget(&target.AzureImageTargetOptions{CloudCredentials: &otherCreds.ID}),
+		},
+	}, "org-1")
+	require.NoError(t, err)
+	j, token, _, _, _, err := server.RequestJob(context.Background(), test_distro.TestArchName, []string{worker.JobTypeOSBuild}, []string{"org-1"}, uuid.Nil)
+	require.NoError(t, err)
+	require.Equal(t, jobID, j)
+
+	reply := test.TestRouteWithReply(t, handler, false, "GET", fmt.Sprintf("/api/worker/v1/jobs/%s/cloud-credentials/%s", token, creds.ID), ``, http.StatusOK,
+		`{"kind":"CloudCredentials"}`, "href", "id", "credentials")
+	var response api.GetJobCloudCredentialsResponse
+	require.NoError(t, json.Unmarshal(reply, &response))
+	require.Equal(t, creds.ID.String(), response.Id)
+	var got cloudcredentials.Credentials
+	require.NoError(t, json.Unmarshal(response.Credentials, &got))
+	require.Equal(t, "id", got.Azure.ClientID)
+	require.Equal(t, "secret", got.Azure.ClientSecret)
+
+	// the credentials of other tenants, the ones the job doesn't refer to and
+	// unknown ones aren't resolved
+	for _, id := range []uuid.UUID{otherCreds.ID, unreferenced.ID, uuid.New()} {
+		test.TestRoute(t, handler, false, "GET", fmt.Sprintf("/api/worker/v1/jobs/%s/cloud-credentials/%s", token, id), ``, http.StatusNotFound,
+			`{"kind":"Error","id":"22","code":"IMAGE-BUILDER-WORKER-22"}`, "href", "operation_id", "reason", "details", "message")
+	}
+	test.TestRoute(t, handler, false, "GET", fmt.Sprintf("/api/worker/v1/jobs/%s/cloud-credentials/%s", uuid.New(), creds.ID), ``, http.StatusNotFound,
+		`{"kind":"Error","id":"5","code":"IMAGE-BUILDER-WORKER-5"}`, "href", "operation_id", "reason", "details", "message")
+}
+
+func TestJobSecret(t *testing.T) {
+	vault, err := secrets.NewVault(make([]byte, secrets.KeySize), secrets.NewMemoryBackend())
+	require.NoError(t, err)
+	config := defaultConfig
+	config.Secrets = vault
+	server := newTestServer(t, t.TempDir(), config, false)
+	handler := server.Handler()
+
+	targets := []*target.Target{
+		target.NewContainerTarget(&target.ContainerTargetOptions{Username: "user", Password: "hunter2"}),
+		target.NewGCPTarget(&target.GCPTargetOptions{Credentials: []byte(`{"type": "service_account"}`)}),
+	}
+	require.NoError(t, server.SealTargetSecrets("org-1", targets))
+	passwordRef := targets[0].Options.(*target.ContainerTargetOptions).Password
+	require.True(t, secrets.IsRef(passwordRef))
+	gcpRef := string(targets[1].Options.(*target.GCPTargetOptions).Credentials)
+	require.True(t, secrets.IsRef(gcpRef))
+	otherRef, err := vault.Seal("org-1", "not-for-this-job")
+	require.NoError(t, err)
+
+	_, err = server.EnqueueOSBuild(context.Background(), test_distro.TestArchName, &worker.OSBuildJob{Targets: targets}, "org-1")
+	require.NoError(t, err)
+	_, token, _, args, _, err := server.RequestJob(context.Background(), test_distro.TestArchName, []string{worker.JobTypeOSBuild}, []string{"org-1"}, uuid.Nil)
+	require.NoError(t, err)
+	// the job arguments only carry the references
+	require.NotContains(t, string(args), "hunter2")
+
+	for ref, value := range map[string]string{passwordRef: "hunter2", gcpRef: `{"type": "service_account"}`} {
+		id, _ := secrets.ParseRef(ref)
+		reply := test.TestRouteWithReply(t, handler, false, "GET", fmt.Sprintf("/api/worker/v1/jobs/%s/secrets/%s", token, id), ``, http.StatusOK,
+			`{"kind":"Secret"}`, "href", "id", "secret")
+		var response api.GetJobSecretResponse
+		require.NoError(t, json.Unmarshal(reply, &response))
+		require.Equal(t, value, response.Secret)
+	}
+
+	// secrets the job doesn't refer to and unknown ones aren't resolved
+	otherId, _ := secrets.ParseRef(otherRef)
+	for _, id := range []uuid.UUID{otherId, uuid.New()} {
+		test.TestRoute(t, handler, false, "GET", fmt.Sprintf("/api/worker/v1/jobs/%s/secrets/%s", token, id), ``, http.StatusNotFound,
+			`{"kind":"Error","id":"23","code":"IMAGE-BUILDER-WORKER-23"}`, "href", "operation_id", "reason", "details", "message")
+	}
+	passwordId, _ := secrets.ParseRef(passwordRef)
+	test.TestRoute(t, handler, false, "GET", fmt.Sprintf("/api/worker/v1/jobs/%s/secrets/%s", uuid.New(), passwordId), ``, http.StatusNotFound,
+		`{"kind":"Error","id":"5","code":"IMAGE-BUILDER-WORKER-5"}`, "href", "operation_id", "reason", "details", "message")
+
+	require.NoError(t, server.DeleteTargetSecrets(targets))
+	_, err = vault.Open("org-1", passwordRef)
+	require.ErrorIs(t, err, secrets.ErrNotFound)
+}
+
 func TestUploadAlteredBasePath(t *testing.T) {
 	distroStruct := newTestDistro(t)
 	arch, err := distroStruct.GetArch(test_distro.TestArchName)
@@ -426,7 +574,7 @@ func TestUploadAlteredBasePath(t *testing.T) {
 	server := newTestServer(t, t.TempDir(), config, true)
 	handler := server.Handler()
 
-	jobID, err := server.EnqueueOSBuild(arch.Name(), &worker.OSBuildJob{Manifest: mf}, "")
+	jobID, err := server.EnqueueOSBuild(context.Background(), arch.Name(), &worker.OSBuildJob{Manifest: mf}, "")
 	require.NoError(t, err)
 
 	j, token, typ, args, dynamicArgs, err := server.RequestJob(context.Background(), arch.Name(), []string{worker.JobTypeOSBuild}, []string{""}, uuid.Nil)
@@ -467,10 +615,10 @@ func TestRequestJobById(t *testing.T) {
 	server := newTestServer(t, t.TempDir(), defaultConfig, false)
 	handler := server.Handler()
 
-	depsolveJobId, err := server.EnqueueDepsolve(&worker.DepsolveJob{}, "")
+	depsolveJobId, err := server.EnqueueDepsolve(context.Background(), &worker.DepsolveJob{}, "")
 	require.NoError(t, err)
 
-	jobId, err := server.EnqueueManifestJobByID(&worker.ManifestJobByID{}, []uuid.UUID{depsolveJobId}, "")
+	jobId, err := server.EnqueueManifestJobByID(context.Background(), &worker.ManifestJobByID{}, []uuid.UUID{depsolveJobId}, "")
 	require.NoError(t, err)
 
 	test.TestRoute(t, server.Handler(), false, "POST", "/api/worker/v1/jobs", fmt.Sprintf(`{"arch":"arch","types":["%s"]}`, worker.JobTypeManifestIDOnly), http.StatusBadRequest,
@@ -521,7 +669,7 @@ func enqueueAndFinishTestJobDependencies(s *worker.Server, deps []testJob) ([]uu
 		switch dep.main.(type) {
 		case *worker.OSBuildJob:
 			job := dep.main.(*worker.OSBuildJob)
-			id, err = s.EnqueueOSBuildAsDependency(arch.ARCH_X86_64.String(), job, depUUIDs, "")
+			id, err = s.EnqueueOSBuildAsDependency(context.Background(), arch.ARCH_X86_64.String(), job, depUUIDs, "")
 			if err != nil {
 				return nil, err
 			}
@@ -531,7 +679,7 @@ func enqueueAndFinishTestJobDependencies(s *worker.Server, deps []testJob) ([]uu
 			if len(depUUIDs) < 1 {
 				return nil, fmt.Errorf("at least one dependency is expected for ManifestJobByID, got: %d", len(depUUIDs))
 			}
-			id, err = s.EnqueueManifestJobByID(job, depUUIDs, "")
+			id, err = s.EnqueueManifestJobByID(context.Background(), job, depUUIDs, "")
 			if err != nil {
 				return nil, err
 			}
@@ -541,7 +689,7 @@ func enqueueAndFinishTestJobDependencies(s *worker.Server, deps []testJob) ([]uu
 			if len(depUUIDs) != 0 {
 				return nil, fmt.Errorf("dependencies are not supported for DepsolveJob, got: %d", len(depUUIDs))
 			}
-			id, err = s.EnqueueDepsolve(job, "")
+			id, err = s.EnqueueDepsolve(context.Background(), job, "")
 			if err != nil {
 				return nil, err
 			}
@@ -551,7 +699,7 @@ func enqueueAndFinishTestJobDependencies(s *worker.Server, deps []testJob) ([]uu
 			if len(depUUIDs) != 0 {
 				return nil, fmt.Errorf("dependencies are not supported for KojiInitJob, got: %d", len(depUUIDs))
 			}
-			id, err = s.EnqueueKojiInit(job, "")
+			id, err = s.EnqueueKojiInit(context.Background(), job, "")
 			if err != nil {
 				return nil, err
 			}
@@ -561,14 +709,14 @@ func enqueueAndFinishTestJobDependencies(s *worker.Server, deps []testJob) ([]uu
 			if len(depUUIDs) < 2 {
 				return nil, fmt.Errorf("at least two dependencies are expected for KojiFinalizeJob, got: %d", len(depUUIDs))
 			}
-			id, err = s.EnqueueKojiFinalize(job, depUUIDs[0], depUUIDs[1:], "")
+			id, err = s.EnqueueKojiFinalize(context.Background(), job, depUUIDs[0], depUUIDs[1:], "")
 			if err != nil {
 				return nil, err
 			}
 
 		case *worker.ContainerResolveJob:
 			job := dep.main.(*worker.ContainerResolveJob)
-			id, err = s.EnqueueContainerResolveJob(job, depUUIDs, "")
+			id, err = s.EnqueueContainerResolveJob(context.Background(), job, depUUIDs, "")
 			if err != nil {
 				return nil, err
 			}
@@ -578,7 +726,7 @@ func enqueueAndFinishTestJobDependencies(s *worker.Server, deps []testJob) ([]uu
 			if len(depUUIDs) != 0 {
 				return nil, fmt.Errorf("dependencies are not supported for OSTreeResolveJob, got: %d", len(depUUIDs))
 			}
-			id, err = s.EnqueueOSTreeResolveJob(job, "")
+			id, err = s.EnqueueOSTreeResolveJob(context.Background(), job, "")
 			if err != nil {
 				return nil, err
 			}
@@ -588,7 +736,7 @@ func enqueueAndFinishTestJobDependencies(s *worker.Server, deps []testJob) ([]uu
 			if len(depUUIDs) != 0 {
 				return nil, fmt.Errorf("dependencies are not supported for BootcInfoResolveJob, got: %d", len(depUUIDs))
 			}
-			id, err = s.EnqueueBootcInfoResolveJob(arch.ARCH_X86_64.String(), job, "")
+			id, err = s.EnqueueBootcInfoResolveJob(context.Background(), arch.ARCH_X86_64.String(), job, "")
 			if err != nil {
 				return nil, err
 			}
@@ -1396,7 +1544,7 @@ func TestRequestJobForWorker(t *testing.T) {
 	if err != nil {
 		t.Fatalf("error creating osbuild manifest: %v", err)
 	}
-	jobId, err := server.EnqueueOSBuild(arch.Name(), &worker.OSBuildJob{Manifest: mf}, "")
+	jobId, err := server.EnqueueOSBuild(context.Background(), arch.Name(), &worker.OSBuildJob{Manifest: mf}, "")
 	require.NoError(t, err)
 
 	// Can request a job with worker ID
@@ -1408,6 +1556,267 @@ func TestRequestJobForWorker(t *testing.T) {
 	require.Nil(t, dynamicArgs)
 }
 
+func TestRequestJobForWorkerCapabilities(t *testing.T) {
+	server := newTestServer(t, t.TempDir(), defaultConfig, false)
+	registerWorker := func(capabilities string) uuid.UUID {
+		reply := test.TestRouteWithReply(t, server.Handler(), false, "POST", "/api/worker/v1/workers", fmt.Sprintf(`{"arch":"%s","capabilities":%s}`, arch.Current().String(), capabilities), 201, `{"href":"/api/worker/v1/workers","kind":"WorkerID","id": "15"}`, "id", "worker_id")
+		var resp api.PostWorkersResponse
+		require.NoError(t, json.Unmarshal(reply, &resp))
+		return resp.WorkerId
+	}
+	plainWorker := registerWorker(`["executor:host"]`)
+	kojiWorker := registerWorker(`["executor:host","label:fips","target:org.osbuild.koji:koji.example.com"]`)
+
+	jobId, err := server.EnqueueOSBuild(context.Background(), arch.Current().String(), &worker.OSBuildJob{
+		Targets: []*target.Target{
+			target.NewKojiTarget(&target.KojiTargetOptions{Server: "https://koji.example.com/kojihub"}),
+		},
+		WorkerLabels: []string{"fips"},
+	}, "")
+	require.NoError(t, err)
+
+	// the job isn't handed out to a worker without Koji credentials
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+	_, _, _, _, _, err = server.RequestJob(ctx, arch.Current().String(), []string{worker.JobTypeOSBuild}, []string{""}, plainWorker)
+	require.Equal(t, jobqueue.ErrDequeueTimeout, err)
+
+	j, _, _, _, _, err := server.RequestJob(context.Background(), arch.Current().String(), []string{worker.JobTypeOSBuild}, []string{""}, kojiWorker)
+	require.NoError(t, err)
+	require.Equal(t, jobId, j)
+}
+
+func TestRequestJobRoutes(t *testing.T) {
+	config := defaultConfig
+	config.Routes = []worker.Route{
+		{
+			Channels:        []string{worker.AnyChannel},
+			ExcludeChannels: []string{"org-kingfisher"},
+			JobTypes:        []string{worker.JobTypeDepsolve},
+			Pool:            "org-shared",
+		},
+		{
+			Channels: []string{"org-kingfisher"},
+			JobTypes: []string{worker.JobTypeOSBuild},
+			Arches:   []string{arch.Current().String()},
+			Labels:   []string{"fips"},
+			Pool:     "org-reserved",
+		},
+	}
+	server := newTestServer(t, t.TempDir(), config, false)
+	reply := test.TestRouteWithReply(t, server.Handler(), false, "POST", "/api/worker/v1/workers", fmt.Sprintf(`{"arch":"%s","capabilities":["label:fips"]}`, arch.Current().String()), 201, `{"href":"/api/worker/v1/workers","kind":"WorkerID","id": "15"}`, "id", "worker_id")
+	var resp api.PostWorkersResponse
+	require.NoError(t, json.Unmarshal(reply, &resp))
+
+	requestJob := func(jobTypes []string, channel string) (uuid.UUID, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+		defer cancel()
+		j, _, _, _, _, err := server.RequestJob(ctx, arch.Current().String(), jobTypes, []string{channel}, resp.WorkerId)
+		return j, err
+	}
+
+	reservedDepsolveJob, err := server.EnqueueDepsolve(context.Background(), &worker.DepsolveJob{}, "org-kingfisher")
+	require.NoError(t, err)
+	depsolveJob, err := server.EnqueueDepsolve(context.Background(), &worker.DepsolveJob{}, "org-penguin")
+	require.NoError(t, err)
+	plainJob, err := server.EnqueueOSBuild(context.Background(), arch.Current().String(), &worker.OSBuildJob{}, "org-kingfisher")
+	require.NoError(t, err)
+	fipsJob, err := server.EnqueueOSBuild(context.Background(), arch.Current().String(), &worker.OSBuildJob{WorkerLabels: []string{"fips"}}, "org-kingfisher")
+	require.NoError(t, err)
+
+	// workers of other pools only serve their own channel
+	_, err = requestJob([]string{worker.JobTypeDepsolve, worker.JobTypeOSBuild}, "org-other")
+	require.Equal(t, jobqueue.ErrDequeueTimeout, err)
+
+	// the shared pool only serves the routed job types
+	_, err = requestJob([]string{worker.JobTypeOSBuild}, "org-shared")
+	require.Equal(t, jobqueue.ErrDequeueTimeout, err)
+	j, err := requestJob([]string{worker.JobTypeDepsolve, worker.JobTypeOSBuild}, "org-shared")
+	require.NoError(t, err)
+	require.Equal(t, depsolveJob, j)
+
+	// and not the jobs of excluded channels
+	_, err = requestJob([]string{worker.JobTypeDepsolve}, "org-shared")
+	require.Equal(t, jobqueue.ErrDequeueTimeout, err)
+
+	// the reserved pool only serves jobs with the routed labels
+	j, err = requestJob([]string{worker.JobTypeOSBuild}, "org-reserved")
+	require.NoError(t, err)
+	require.Equal(t, fipsJob, j)
+	_, err = requestJob([]string{worker.JobTypeOSBuild}, "org-reserved")
+	require.Equal(t, jobqueue.ErrDequeueTimeout, err)
+
+	// and the tenant's own workers still serve the rest
+	j, err = requestJob([]string{worker.JobTypeDepsolve}, "org-kingfisher")
+	require.NoError(t, err)
+	require.Equal(t, reservedDepsolveJob, j)
+	j, err = requestJob([]string{worker.JobTypeOSBuild}, "org-kingfisher")
+	require.NoError(t, err)
+	require.Equal(t, plainJob, j)
+}
+
+// adminRequest calls the handler as the user with the given common name in
+// the TLS client certificate, or anonymously if it is empty
+func adminRequest(t *testing.T, handler http.Handler, method, path, user string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if user != "" {
+		req.TLS = &tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{
+				{Subject: pkix.Name{CommonName: user}},
+			},
+		}
+	}
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestDeleteWorkerChannel(t *testing.T) {
+	config := defaultConfig
+	config.AdminUsers = []string{"admin"}
+	server := newTestServer(t, t.TempDir(), config, false)
+	handler := server.Handler()
+
+	own, err := server.RegisterWorker("", arch.Current().String(), "", nil)
+	require.NoError(t, err)
+	other, err := server.RegisterWorker("org-kingfisher", arch.Current().String(), "", nil)
+	require.NoError(t, err)
+
+	// workers can't deregister the workers of other channels
+	resp := adminRequest(t, handler, "DELETE", fmt.Sprintf("/api/worker/v1/workers/%s", other), "")
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+	require.Contains(t, resp.Body.String(), "IMAGE-BUILDER-WORKER-18")
+
+	resp = adminRequest(t, handler, "DELETE", fmt.Sprintf("/api/worker/v1/workers/%s", own), "")
+	require.Equal(t, http.StatusNoContent, resp.Code)
+
+	// but admins can
+	resp = adminRequest(t, handler, "DELETE", fmt.Sprintf("/api/worker/v1/workers/%s", other), "admin")
+	require.Equal(t, http.StatusNoContent, resp.Code)
+
+	workers, err := server.Workers()
+	require.NoError(t, err)
+	require.Empty(t, workers)
+}
+
+func TestAdminWorkers(t *testing.T) {
+	config := defaultConfig
+	config.AdminUsers = []string{"admin"}
+	server := newTestServer(t, t.TempDir(), config, false)
+	handler := server.Handler()
+
+	w, err := server.RegisterWorker("", arch.Current().String(), "v42", []string{"executor:host"})
+	require.NoError(t, err)
+	other, err := server.RegisterWorker("", arch.Current().String(), "", nil)
+	require.NoError(t, err)
+
+	first, err := server.EnqueueOSBuild(context.Background(), arch.Current().String(), &worker.OSBuildJob{}, "")
+	require.NoError(t, err)
+	j, _, _, _, _, err := server.RequestJob(context.Background(), arch.Current().String(), []string{worker.JobTypeOSBuild}, []string{""}, w)
+	require.NoError(t, err)
+	require.Equal(t, first, j)
+
+	// only admins may call the admin routes
+	for _, user := range []string{"", "someone"} {
+		resp := adminRequest(t, handler, "GET", "/api/worker/v1/admin/workers", user)
+		require.Equal(t, http.StatusForbidden, resp.Code)
+		resp = adminRequest(t, handler, "POST", fmt.Sprintf("/api/worker/v1/admin/workers/%s/drain", w), user)
+		require.Equal(t, http.StatusForbidden, resp.Code)
+		resp = adminRequest(t, handler, "DELETE", fmt.Sprintf("/api/worker/v1/admin/workers/%s", w), user)
+		require.Equal(t, http.StatusForbidden, resp.Code)
+	}
+
+	resp := adminRequest(t, handler, "GET", "/api/worker/v1/admin/workers", "admin")
+	require.Equal(t, http.StatusOK, resp.Code)
+	var list api.WorkerList
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &list))
+	require.Len(t, list.Items, 2)
+	for _, item := range list.Items {
+		require.False(t, item.Draining)
+		require.WithinDuration(t, time.Now(), item.LastHeartbeat, time.Minute)
+		if item.Id == w.String() {
+			require.Equal(t, "v42", item.Version)
+			require.Equal(t, []string{"executor:host"}, *item.Capabilities)
+			require.Equal(t, []uuid.UUID{first}, item.Jobs)
+		} else {
+			require.Equal(t, "", item.Version)
+			require.Nil(t, item.Capabilities)
+			require.Empty(t, item.Jobs)
+		}
+	}
+
+	// draining workers don't get new jobs
+	resp = adminRequest(t, handler, "POST", fmt.Sprintf("/api/worker/v1/admin/workers/%s/drain", w), "admin")
+	require.Equal(t, http.StatusOK, resp.Code)
+	var drained api.Worker
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &drained))
+	require.True(t, drained.Draining)
+
+	second, err := server.EnqueueOSBuild(context.Background(), arch.Current().String(), &worker.OSBuildJob{}, "")
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+	_, _, _, _, _, err = server.RequestJob(ctx, arch.Current().String(), []string{worker.JobTypeOSBuild}, []string{""}, w)
+	require.Equal(t, jobqueue.ErrDequeueTimeout, err)
+
+	// the job of an evicted worker is requeued
+	resp = adminRequest(t, handler, "DELETE", fmt.Sprintf("/api/worker/v1/admin/workers/%s", w), "admin")
+	require.Equal(t, http.StatusNoContent, resp.Code)
+
+	var requested []uuid.UUID
+	for i := 0; i < 2; i++ {
+		j, _, _, _, _, err := server.RequestJob(context.Background(), arch.Current().String(), []string{worker.JobTypeOSBuild}, []string{""}, other)
+		require.NoError(t, err)
+		requested = append(requested, j)
+	}
+	require.ElementsMatch(t, []uuid.UUID{first, second}, requested)
+
+	workers, err := server.Workers()
+	require.NoError(t, err)
+	require.Len(t, workers, 1)
+	require.Equal(t, other, workers[0].ID)
+
+	resp = adminRequest(t, handler, "DELETE", fmt.Sprintf("/api/worker/v1/admin/workers/%s", w), "admin")
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+	resp = adminRequest(t, handler, "POST", fmt.Sprintf("/api/worker/v1/admin/workers/%s/drain", uuid.New()), "admin")
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestAdminAudit(t *testing.T) {
+	dir := t.TempDir()
+	auditLog, err := audit.NewLog(path.Join(dir, "audit.jsonl"), audit.Options{})
+	require.NoError(t, err)
+	defer auditLog.Close()
+
+	config := defaultConfig
+	config.AdminUsers = []string{"admin"}
+	config.Audit = auditLog
+	server := newTestServer(t, dir, config, false)
+	handler := server.Handler()
+
+	w, err := server.RegisterWorker("", arch.Current().String(), "", nil)
+	require.NoError(t, err)
+
+	resp := adminRequest(t, handler, "POST", fmt.Sprintf("/api/worker/v1/admin/workers/%s/drain", w), "someone")
+	require.Equal(t, http.StatusForbidden, resp.Code)
+	resp = adminRequest(t, handler, "GET", "/api/worker/v1/admin/workers", "admin")
+	require.Equal(t, http.StatusOK, resp.Code)
+	resp = adminRequest(t, handler, "DELETE", fmt.Sprintf("/api/worker/v1/admin/workers/%s", w), "admin")
+	require.Equal(t, http.StatusNoContent, resp.Code)
+
+	records, err := auditLog.Query(audit.Filter{})
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	require.Equal(t, "worker", records[0].API)
+	require.Equal(t, "someone", records[0].Principal)
+	require.Equal(t, "postAdminWorkerDrain", records[0].Action)
+	require.Equal(t, audit.OutcomeFailure, records[0].Outcome)
+	require.Equal(t, "admin", records[1].Principal)
+	require.Equal(t, "deleteAdminWorker", records[1].Action)
+	require.Equal(t, http.StatusNoContent, records[1].Status)
+	require.Equal(t, audit.OutcomeSuccess, records[1].Outcome)
+}
+
 func TestJobHeartbeats(t *testing.T) {
 	config := defaultConfig
 	config.JobTimeout = time.Millisecond * 1
@@ -1431,7 +1840,7 @@ func TestJobHeartbeats(t *testing.T) {
 	if err != nil {
 		t.Fatalf("error creating osbuild manifest: %v", err)
 	}
-	jobId, err := server.EnqueueOSBuild(arch.Name(), &worker.OSBuildJob{Manifest: mf}, "")
+	jobId, err := server.EnqueueOSBuild(context.Background(), arch.Name(), &worker.OSBuildJob{Manifest: mf}, "")
 	require.NoError(t, err)
 	require.Eventually(t, func() bool {
 		return promtest.ToFloat64(prometheus.PendingJobs) == 1
@@ -1546,7 +1955,7 @@ func TestCleanupArtifacts(t *testing.T) {
 		t.Fatalf("error creating osbuild manifest: %v", err)
 	}
 
-	jobID, err := server.EnqueueOSBuild(arch.Name(), &worker.OSBuildJob{Manifest: mf}, "")
+	jobID, err := server.EnqueueOSBuild(context.Background(), arch.Name(), &worker.OSBuildJob{Manifest: mf}, "")
 	require.NoError(t, err)
 
 	// Make a fake artifact for the existing jobid
@@ -1587,7 +1996,7 @@ func TestBootcInfoResolveJob(t *testing.T) {
 	server := newTestServer(t, t.TempDir(), defaultConfig, false)
 	job := testBootcInfoResolveSampleJob()
 
-	jobID, err := server.EnqueueBootcInfoResolveJob("x86_64", job, "")
+	jobID, err := server.EnqueueBootcInfoResolveJob(context.Background(), "x86_64", job, "")
 	require.NoError(t, err)
 	require.NotEqual(t, uuid.Nil, jobID)
 
@@ -1638,7 +2047,7 @@ func TestBootcInfoResolveJobArchRouting(t *testing.T) {
 		BasePath:          "/api/worker/v1",
 	}, false)
 
-	_, err := server.EnqueueBootcInfoResolveJob("x86_64", testBootcInfoResolveSampleJob(), "")
+	_, err := server.EnqueueBootcInfoResolveJob(context.Background(), "x86_64", testBootcInfoResolveSampleJob(), "")
 	require.NoError(t, err)
 
 	_, _, _, _, _, err = server.RequestJob(
@@ -1651,7 +2060,7 @@ func TestBootcInfoResolveJobArchRouting(t *testing.T) {
 func TestBootcInfoResolveJobInfoWrongType(t *testing.T) {
 	server := newTestServer(t, t.TempDir(), defaultConfig, false)
 
-	depsolveJobID, err := server.EnqueueDepsolve(&worker.DepsolveJob{}, "")
+	depsolveJobID, err := server.EnqueueDepsolve(context.Background(), &worker.DepsolveJob{}, "")
 	require.NoError(t, err)
 
 	_, token, _, _, _, err := server.RequestJob(
@@ -1679,11 +2088,11 @@ func testBootcPreManifestSampleJob() *worker.BootcPreManifestJob {
 func TestEnqueueBootcPreManifestJob(t *testing.T) {
 	server := newTestServer(t, t.TempDir(), defaultConfig, false)
 
-	infoResolveJobID, err := server.EnqueueBootcInfoResolveJob("x86_64", testBootcInfoResolveSampleJob(), "")
+	infoResolveJobID, err := server.EnqueueBootcInfoResolveJob(context.Background(), "x86_64", testBootcInfoResolveSampleJob(), "")
 	require.NoError(t, err)
 
 	preManifestJobID, err := server.EnqueueBootcPreManifestJob(
-		testBootcPreManifestSampleJob(), []uuid.UUID{infoResolveJobID}, "",
+		context.Background(), testBootcPreManifestSampleJob(), []uuid.UUID{infoResolveJobID}, "",
 	)
 	require.NoError(t, err)
 	require.NotEqual(t, uuid.Nil, preManifestJobID)
@@ -1697,7 +2106,7 @@ func TestBootcPreManifestJobInfo(t *testing.T) {
 	server := newTestServer(t, t.TempDir(), defaultConfig, false)
 	job := testBootcPreManifestSampleJob()
 
-	preManifestJobID, err := server.EnqueueBootcPreManifestJob(job, nil, "")
+	preManifestJobID, err := server.EnqueueBootcPreManifestJob(context.Background(), job, nil, "")
 	require.NoError(t, err)
 
 	dequeuedID, token, jobType, args, dynamicArgs, err := server.RequestJob(
@@ -1747,7 +2156,7 @@ func TestBootcPreManifestJobInfoWrongType(t *testing.T) {
 	server := newTestServer(t, t.TempDir(), defaultConfig, false)
 
 	// Enqueue a depsolve job, then try to read it as a bootc-pre-manifest job
-	depsolveJobID, err := server.EnqueueDepsolve(&worker.DepsolveJob{}, "")
+	depsolveJobID, err := server.EnqueueDepsolve(context.Background(), &worker.DepsolveJob{}, "")
 	require.NoError(t, err)
 
 	_, token, _, _, _, err := server.RequestJob(
@@ -1763,3 +2172,51 @@ func TestBootcPreManifestJobInfoWrongType(t *testing.T) {
 	_, err = server.BootcPreManifestJobInfo(depsolveJobID, &readResult)
 	require.Error(t, err, "reading depsolve job as bootc-pre-manifest should fail")
 }
+
+func TestOSBuildJobDepsolveInfo(t *testing.T) {
+	server := newTestServer(t, t.TempDir(), defaultConfig, false)
+
+	depsolveJobID, err := server.EnqueueDepsolve(context.Background(), &worker.DepsolveJob{}, "")
+	require.NoError(t, err)
+	manifestJobID, err := server.EnqueueManifestJobByID(context.Background(), &worker.ManifestJobByID{}, []uuid.UUID{depsolveJobID}, "")
+	require.NoError(t, err)
+	osbuildJobID, err := server.EnqueueOSBuildAsDependency(context.Background(), "x86_64", &worker.OSBuildJob{}, []uuid.UUID{manifestJobID}, "")
+	require.NoError(t, err)
+
+	_, token, _, _, _, err := server.RequestJob(context.Background(), "x86_64", []string{worker.JobTypeDepsolve}, []string{""}, uuid.Nil)
+	require.NoError(t, err)
+	depsolveResult := worker.DepsolveJobResult{
+		Transactions: map[string][]worker.DepsolvedPackageList{
+			"os": {{{Name: "bash", Version: "5.2", Release: "1.fc42", Arch: "x86_64", RepoID: "baseos"}}},
+		},
+	}
+	rawResult, err := json.Marshal(depsolveResult)
+	require.NoError(t, err)
+	require.NoError(t, server.FinishJob(token, rawResult))
+
+	var result worker.DepsolveJobResult
+	jobInfo, err := server.OSBuildJobDepsolveInfo(osbuildJobID, &result)
+	require.NoError(t, err)
+	assert.Equal(t, worker.JobTypeDepsolve, jobInfo.JobType)
+	assert.Equal(t, depsolveResult.Transactions, result.Transactions)
+
+	// an osbuild job with a static manifest
+	staticJobID, err := server.EnqueueOSBuild(context.Background(), "x86_64", &worker.OSBuildJob{}, "")
+	require.NoError(t, err)
+	_, err = server.OSBuildJobDepsolveInfo(staticJobID, &result)
+	assert.ErrorIs(t, err, worker.ErrNoDepsolveJob)
+
+	_, err = server.OSBuildJobDepsolveInfo(depsolveJobID, &result)
+	assert.Error(t, err)
+}
+
+func TestCheckJobQueue(t *testing.T) {
+	tempdir := t.TempDir()
+	server := newTestServer(t, tempdir, defaultConfig, false)
+	require.NoError(t, server.CheckJobQueue(context.Background()))
+	// the fsjobqueue has no schema to migrate
+	require.NoError(t, server.CheckJobQueueSchema(context.Background()))
+
+	require.NoError(t, os.RemoveAll(path.Join(tempdir, "jobs")))
+	require.Error(t, server.CheckJobQueue(context.Background()))
+}
@@ -8,9 +8,14 @@ package dbjobqueue
 import (
 	"container/list"
 	"context"
+	"embed"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -29,7 +34,7 @@ const (
 	sqlListen   = `LISTEN jobs`
 	sqlUnlisten = `UNLISTEN jobs`
 
-	sqlEnqueue = `INSERT INTO jobs(id, type, args, queued_at, channel) VALUES ($1, $2, $3, statement_timestamp(), $4)`
+	sqlEnqueue = `INSERT INTO jobs(id, type, args, queued_at, channel, requirements) VALUES ($1, $2, $3, statement_timestamp(), $4, $5)`
 	sqlDequeue = `
 		UPDATE jobs
 		SET token = $1, started_at = statement_timestamp()
@@ -39,6 +44,9 @@ const (
 			  -- use ANY here, because "type in ()" doesn't work with bound parameters
 			  -- literal syntax for this is '{"a", "b"}': https://www.postgresql.org/docs/13/arrays.html
 		  WHERE type = ANY($2) AND channel = ANY($3)
+			  -- workers without capabilities or unknown workers can run any job
+			  AND requirements <@ COALESCE((SELECT capabilities FROM workers WHERE worker_id = $4), requirements)
+			  AND NOT COALESCE((SELECT draining FROM workers WHERE worker_id = $4), FALSE)
 		  LIMIT 1
 		  FOR UPDATE SKIP LOCKED
 		)
@@ -51,6 +59,31 @@ const (
 		  SELECT id
 		  FROM ready_jobs
 		  WHERE type = ANY($2)
+			  AND requirements <@ COALESCE((SELECT capabilities FROM workers WHERE worker_id = $3), requirements)
+			  AND NOT COALESCE((SELECT draining FROM workers WHERE worker_id = $3), FALSE)
+		  LIMIT 1
+		  FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, type, args`
+
+	// the routes are passed as a JSON array of dbRoute
+	sqlDequeueRoutes = `
+		UPDATE jobs
+		SET token = $1, started_at = statement_timestamp()
+		WHERE id = (
+		  SELECT id
+		  FROM ready_jobs
+		  WHERE EXISTS (
+			  SELECT 1
+			  FROM jsonb_to_recordset($2::jsonb)
+			    AS route(job_types varchar[], channels varchar[], any_channel boolean, exclude_channels varchar[], requirements varchar[])
+			  WHERE ready_jobs.type = ANY(route.job_types)
+			    AND (route.any_channel OR ready_jobs.channel = ANY(route.channels))
+			    AND NOT ready_jobs.channel = ANY(COALESCE(route.exclude_channels, '{}'))
+			    AND COALESCE(route.requirements, '{}') <@ ready_jobs.requirements
+		  )
+			  AND requirements <@ COALESCE((SELECT capabilities FROM workers WHERE worker_id = $3), requirements)
+			  AND NOT COALESCE((SELECT draining FROM workers WHERE worker_id = $3), FALSE)
 		  LIMIT 1
 		  FOR UPDATE SKIP LOCKED
 		)
@@ -148,21 +181,70 @@ const (
 		WHERE worker_id = $1`
 
 	sqlInsertWorker = `
-		INSERT INTO workers(worker_id, channel, arch, heartbeat)
-		VALUES($1, $2, $3, now())`
+		INSERT INTO workers(worker_id, channel, arch, version, capabilities, heartbeat)
+		VALUES($1, $2, $3, $4, $5, now())`
 	sqlUpdateWorkerStatus = `
 		UPDATE workers
 		SET heartbeat = now()
 		WHERE worker_id = $1`
+	sqlDrainWorker = `
+		UPDATE workers
+		SET draining = TRUE
+		WHERE worker_id = $1`
 	sqlQueryWorkers = `
-		SELECT worker_id, channel, arch
+		SELECT worker_id, channel, arch, version, capabilities, heartbeat, draining,
+		       ARRAY(SELECT token FROM heartbeats WHERE heartbeats.worker_id = workers.worker_id)
 		FROM workers
 		WHERE age(now(), heartbeat) > $1`
 	sqlDeleteWorker = `
 		DELETE FROM workers
 		WHERE worker_id = $1`
+
+	sqlShareJob = `
+		INSERT INTO job_shares(job_id, channel, permission, created_at)
+		SELECT id, $2, $3, statement_timestamp()
+		FROM jobs
+		WHERE id = $1
+		ON CONFLICT (job_id, channel) DO UPDATE
+		SET permission = excluded.permission`
+	sqlUnshareJob = `
+		DELETE FROM job_shares
+		WHERE job_id = $1 AND channel = $2`
+	sqlQueryJobExists = `
+		SELECT EXISTS(SELECT 1 FROM jobs WHERE id = $1)`
+	sqlQueryJobShares = `
+		SELECT channel, permission, created_at
+		FROM job_shares
+		WHERE job_id = $1
+		ORDER BY channel`
+
+	// the migrations are applied by tern, which keeps their version here
+	sqlSchemaVersion = `SELECT version FROM schema_version`
 )
 
+//go:embed schemas/*.sql
+var schemas embed.FS
+
+// SchemaVersion returns the version of the newest migration in schemas/,
+// which is the version the queue expects the database to be migrated to.
+func SchemaVersion() (int, error) {
+	files, err := fs.Glob(schemas, "schemas/*.sql")
+	if err != nil {
+		return 0, err
+	}
+	version := 0
+	for _, file := range files {
+		// migrations are named like 011_workers_version_draining.sql
+		prefix, _, _ := strings.Cut(path.Base(file), "_")
+		v, err := strconv.Atoi(prefix)
+		if err != nil {
+			return 0, fmt.Errorf("invalid migration name %q: %v", file, err)
+		}
+		version = max(version, v)
+	}
+	return version, nil
+}
+
 // connection unifies pgxpool.Conn and pgx.Tx interfaces
 // Some methods don't care whether they run queries on a raw connection,
 // or in a transaction. This interface thus abstracts this concept.
@@ -317,12 +399,49 @@ func (q *DBJobQueue) waitAndNotify(ctx context.Context) error {
 	return nil
 }
 
+// CheckHealth verifies that the database can be reached
+func (q *DBJobQueue) CheckHealth(ctx context.Context) error {
+	err := q.pool.Ping(ctx)
+	if err != nil {
+		return fmt.Errorf("error connecting to database: %w", err)
+	}
+	return nil
+}
+
+// CheckSchema verifies that the database is migrated to at least
+// SchemaVersion()
+func (q *DBJobQueue) CheckSchema(ctx context.Context) error {
+	expected, err := SchemaVersion()
+	if err != nil {
+		return err
+	}
+
+	var version int
+	err = q.pool.QueryRow(ctx, sqlSchemaVersion).Scan(&version)
+	if err != nil {
+		return fmt.Errorf("error querying the schema version: %w", err)
+	}
+	if version < expected {
+		return fmt.Errorf("database is migrated to version %d, expected at least version %d", version, expected)
+	}
+	return nil
+}
+
 func (q *DBJobQueue) Close() {
 	q.stopListener()
 	q.pool.Close()
 }
 
 func (q *DBJobQueue) Enqueue(jobType string, args interface{}, dependencies []uuid.UUID, channel string) (uuid.UUID, error) {
+	return q.EnqueueWithRequirements(jobType, args, dependencies, channel, nil)
+}
+
+func (q *DBJobQueue) EnqueueWithRequirements(jobType string, args interface{}, dependencies []uuid.UUID, channel string, requirements []string) (uuid.UUID, error) {
+	// the column isn't nullable, nil would be inserted as NULL
+	if requirements == nil {
+		requirements = []string{}
+	}
+
 	conn, err := q.pool.Acquire(context.Background())
 	if err != nil {
 		return uuid.Nil, fmt.Errorf("error connecting to database: %v", err)
@@ -341,7 +460,7 @@ func (q *DBJobQueue) Enqueue(jobType string, args interface{}, dependencies []uu
 	}()
 
 	id := uuid.New()
-	_, err = tx.Exec(context.Background(), sqlEnqueue, id, jobType, args, channel)
+	_, err = tx.Exec(context.Background(), sqlEnqueue, id, jobType, args, channel, requirements)
 	if err != nil {
 		return uuid.Nil, fmt.Errorf("error enqueuing job: %v", err)
 	}
@@ -457,7 +576,7 @@ func (q *DBJobQueue) tryDequeue(ctx context.Context, token, workerID uuid.UUID,
 func (q *DBJobQueue) Dequeue(ctx context.Context, workerID uuid.UUID, jobTypes, channels []string) (uuid.UUID, uuid.UUID, []uuid.UUID, string, json.RawMessage, error) {
 	token := uuid.New()
 	id, deps, jobType, args, err := q.dequeueLoop(ctx, func(ctx context.Context) (uuid.UUID, []uuid.UUID, string, json.RawMessage, error) {
-		return q.tryDequeue(ctx, token, workerID, sqlDequeue, token, jobTypes, channels)
+		return q.tryDequeue(ctx, token, workerID, sqlDequeue, token, jobTypes, channels, workerID)
 	})
 	if err != nil {
 		return uuid.Nil, uuid.Nil, nil, "", nil, err
@@ -468,7 +587,36 @@ func (q *DBJobQueue) Dequeue(ctx context.Context, workerID uuid.UUID, jobTypes,
 func (q *DBJobQueue) DequeueAnyChannel(ctx context.Context, workerID uuid.UUID, jobTypes []string) (uuid.UUID, uuid.UUID, []uuid.UUID, string, json.RawMessage, error) {
 	token := uuid.New()
 	id, deps, jobType, args, err := q.dequeueLoop(ctx, func(ctx context.Context) (uuid.UUID, []uuid.UUID, string, json.RawMessage, error) {
-		return q.tryDequeue(ctx, token, workerID, sqlDequeueAnyChannel, token, jobTypes)
+		return q.tryDequeue(ctx, token, workerID, sqlDequeueAnyChannel, token, jobTypes, workerID)
+	})
+	if err != nil {
+		return uuid.Nil, uuid.Nil, nil, "", nil, err
+	}
+	return id, token, deps, jobType, args, nil
+}
+
+// dbRoute is the representation of jobqueue.Route in sqlDequeueRoutes
+type dbRoute struct {
+	JobTypes        []string `json:"job_types"`
+	Channels        []string `json:"channels"`
+	AnyChannel      bool     `json:"any_channel"`
+	ExcludeChannels []string `json:"exclude_channels"`
+	Requirements    []string `json:"requirements"`
+}
+
+func (q *DBJobQueue) DequeueRoutes(ctx context.Context, workerID uuid.UUID, routes []jobqueue.Route) (uuid.UUID, uuid.UUID, []uuid.UUID, string, json.RawMessage, error) {
+	dbRoutes := make([]dbRoute, 0, len(routes))
+	for _, r := range routes {
+		dbRoutes = append(dbRoutes, dbRoute(r))
+	}
+	routesJSON, err := json.Marshal(dbRoutes)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, nil, "", nil, fmt.Errorf("error marshaling routes: %w", err)
+	}
+
+	token := uuid.New()
+	id, deps, jobType, args, err := q.dequeueLoop(ctx, func(ctx context.Context) (uuid.UUID, []uuid.UUID, string, json.RawMessage, error) {
+		return q.tryDequeue(ctx, token, workerID, sqlDequeueRoutes, token, string(routesJSON), workerID)
 	})
 	if err != nil {
 		return uuid.Nil, uuid.Nil, nil, "", nil, err
@@ -777,6 +925,89 @@ func (q *DBJobQueue) Job(id uuid.UUID) (jobType string, args json.RawMessage, de
 	return
 }
 
+func (q *DBJobQueue) ShareJob(id uuid.UUID, channel, permission string) error {
+	conn, err := q.pool.Acquire(context.Background())
+	if err != nil {
+		return fmt.Errorf("error connecting to database: %w", err)
+	}
+	defer conn.Release()
+
+	tag, err := conn.Exec(context.Background(), sqlShareJob, id, channel, permission)
+	if err != nil {
+		return fmt.Errorf("error sharing job %s: %w", id, err)
+	}
+	if tag.RowsAffected() != 1 {
+		return jobqueue.ErrNotExist
+	}
+
+	q.logger.Info("Shared job", "job_id", id.String(), "channel", channel, "permission", permission)
+	return nil
+}
+
+func (q *DBJobQueue) UnshareJob(id uuid.UUID, channel string) error {
+	conn, err := q.pool.Acquire(context.Background())
+	if err != nil {
+		return fmt.Errorf("error connecting to database: %w", err)
+	}
+	defer conn.Release()
+
+	tag, err := conn.Exec(context.Background(), sqlUnshareJob, id, channel)
+	if err != nil {
+		return fmt.Errorf("error unsharing job %s: %w", id, err)
+	}
+	if tag.RowsAffected() != 1 {
+		var exists bool
+		err = conn.QueryRow(context.Background(), sqlQueryJobExists, id).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("error querying job %s: %w", id, err)
+		}
+		if !exists {
+			return jobqueue.ErrNotExist
+		}
+		return jobqueue.ErrShareNotExist
+	}
+
+	q.logger.Info("Unshared job", "job_id", id.String(), "channel", channel)
+	return nil
+}
+
+func (q *DBJobQueue) JobShares(id uuid.UUID) ([]jobqueue.Share, error) {
+	conn, err := q.pool.Acquire(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to database: %w", err)
+	}
+	defer conn.Release()
+
+	var exists bool
+	err = conn.QueryRow(context.Background(), sqlQueryJobExists, id).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("error querying job %s: %w", id, err)
+	}
+	if !exists {
+		return nil, jobqueue.ErrNotExist
+	}
+
+	rows, err := conn.Query(context.Background(), sqlQueryJobShares, id)
+	if err != nil {
+		return nil, fmt.Errorf("error querying shares of job %s: %w", id, err)
+	}
+	defer rows.Close()
+
+	shares := []jobqueue.Share{}
+	for rows.Next() {
+		var share jobqueue.Share
+		err = rows.Scan(&share.Channel, &share.Permission, &share.Created)
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, share)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return shares, nil
+}
+
 // Find job by token, this will return an error if the job hasn't been dequeued
 func (q *DBJobQueue) IdFromToken(token uuid.UUID) (id uuid.UUID, err error) {
 	conn, err := q.pool.Acquire(context.Background())
@@ -843,7 +1074,7 @@ func (q *DBJobQueue) RefreshHeartbeat(token uuid.UUID) {
 	}
 }
 
-func (q *DBJobQueue) InsertWorker(channel, arch string) (uuid.UUID, error) {
+func (q *DBJobQueue) InsertWorker(channel, arch, version string, capabilities []string) (uuid.UUID, error) {
 	conn, err := q.pool.Acquire(context.Background())
 	if err != nil {
 		return uuid.Nil, err
@@ -851,7 +1082,7 @@ func (q *DBJobQueue) InsertWorker(channel, arch string) (uuid.UUID, error) {
 	defer conn.Release()
 
 	id := uuid.New()
-	_, err = conn.Exec(context.Background(), sqlInsertWorker, id, channel, arch)
+	_, err = conn.Exec(context.Background(), sqlInsertWorker, id, channel, arch, version, capabilities)
 	if err != nil {
 		q.logger.Error(err, "Error inserting worker")
 		return uuid.Nil, err
@@ -877,6 +1108,24 @@ func (q *DBJobQueue) UpdateWorkerStatus(workerID uuid.UUID) error {
 	return nil
 }
 
+func (q *DBJobQueue) DrainWorker(workerID uuid.UUID) error {
+	conn, err := q.pool.Acquire(context.Background())
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	tag, err := conn.Exec(context.Background(), sqlDrainWorker, workerID)
+	if err != nil {
+		q.logger.Error(err, "Error draining worker")
+		return err
+	}
+	if tag.RowsAffected() != 1 {
+		return jobqueue.ErrWorkerNotExist
+	}
+	return nil
+}
+
 func (q *DBJobQueue) Workers(olderThan time.Duration) ([]jobqueue.Worker, error) {
 	conn, err := q.pool.Acquire(context.Background())
 	if err != nil {
@@ -895,16 +1144,26 @@ func (q *DBJobQueue) Workers(olderThan time.Duration) ([]jobqueue.Worker, error)
 		var w uuid.UUID
 		var c string
 		var a string
-		err = rows.Scan(&w, &c, &a)
+		var v string
+		var caps []string
+		var heartbeat time.Time
+		var draining bool
+		var tokens []uuid.UUID
+		err = rows.Scan(&w, &c, &a, &v, &caps, &heartbeat, &draining, &tokens)
 		if err != nil {
 			// Log the error and try to continue with the next row
 			q.logger.Error(err, "Unable to read token from heartbeats")
 			continue
 		}
 		workers = append(workers, jobqueue.Worker{
-			ID:      w,
-			Channel: c,
-			Arch:    a,
+			ID:           w,
+			Channel:      c,
+			Arch:         a,
+			Version:      v,
+			Capabilities: caps,
+			Heartbeat:    heartbeat,
+			Draining:     draining,
+			Tokens:       tokens,
 		})
 	}
 	if rows.Err() != nil {
@@ -0,0 +1,21 @@
+-- requirements a worker needs to have as capabilities to dequeue a job
+ALTER TABLE jobs
+ADD COLUMN requirements varchar[] NOT NULL DEFAULT '{}';
+
+-- NULL for workers which didn't advertise any capabilities, they can
+-- dequeue any job
+ALTER TABLE workers
+ADD COLUMN capabilities varchar[];
+
+-- We added a column, thus we have to recreate the view.
+CREATE OR REPLACE VIEW ready_jobs AS
+SELECT *
+FROM jobs
+WHERE started_at IS NULL
+  AND canceled = FALSE
+  AND id NOT IN (
+    SELECT job_id
+    FROM job_dependencies JOIN jobs ON dependency_id = id
+    WHERE finished_at IS NULL
+)
+ORDER BY queued_at ASC;
@@ -0,0 +1,6 @@
+ALTER TABLE workers
+ADD COLUMN version varchar NOT NULL DEFAULT '';
+
+-- draining workers don't dequeue any new jobs
+ALTER TABLE workers
+ADD COLUMN draining boolean NOT NULL DEFAULT FALSE;
@@ -0,0 +1,9 @@
+-- grants of other channels than the job's own on a job
+CREATE TABLE job_shares(
+        job_id uuid NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+        channel varchar NOT NULL,
+        permission varchar NOT NULL,
+        created_at timestamp NOT NULL,
+
+        PRIMARY KEY (job_id, channel)
+);
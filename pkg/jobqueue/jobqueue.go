@@ -10,12 +10,21 @@
 //
 // A job can have dependencies. It is not run until all its dependencies have
 // finished.
+//
+// A job can have requirements, opaque strings which a worker needs to have
+// as capabilities to dequeue the job. Workers which registered without
+// capabilities can dequeue jobs regardless of their requirements.
+//
+// A job can be shared with other channels than the one it was enqueued
+// with. The job queue only stores these grants along with the job, what a
+// permission allows is up to the caller.
 package jobqueue
 
 import (
 	"context"
 	"encoding/json"
 	"errors"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
@@ -34,11 +43,17 @@ type JobQueue interface {
 	// Returns the id of the new job, or an error.
 	Enqueue(jobType string, args interface{}, dependencies []uuid.UUID, channel string) (uuid.UUID, error)
 
+	// Enqueues a job which can only be dequeued by workers having all of
+	// `requirements` as capabilities, see InsertWorker(). It is otherwise
+	// the same as Enqueue().
+	EnqueueWithRequirements(jobType string, args interface{}, dependencies []uuid.UUID, channel string, requirements []string) (uuid.UUID, error)
+
 	// Dequeues a job, blocking until one is available.
 	//
 	// Waits until a job with a type of any of `jobTypes` and any of `channels`
 	// is available, or `ctx` is canceled. If 'channels' is 'nil' or empty,
-	// no job will be matched and no job will be returned.
+	// no job will be matched and no job will be returned. Jobs with
+	// requirements the worker doesn't have as capabilities are skipped.
 	//
 	// Returns the job's id, token, dependencies, type, and arguments, or an error. Arguments
 	// can be unmarshaled to the type given in Enqueue().
@@ -61,6 +76,15 @@ type JobQueue interface {
 	// an error.
 	DequeueAnyChannel(ctx context.Context, workerID uuid.UUID, jobTypes []string) (uuid.UUID, uuid.UUID, []uuid.UUID, string, json.RawMessage, error)
 
+	// DequeueRoutes dequeues the oldest job matching any of `routes`,
+	// blocking until one is available or `ctx` is canceled. Like with
+	// Dequeue(), jobs with requirements the worker doesn't have as
+	// capabilities are skipped.
+	//
+	// Returns the job's id, token, dependencies, type, and arguments, or
+	// an error.
+	DequeueRoutes(ctx context.Context, workerID uuid.UUID, routes []Route) (uuid.UUID, uuid.UUID, []uuid.UUID, string, json.RawMessage, error)
+
 	// Updates the result of a job without finishing it
 	//
 	// This allows partial results to be set on a running job.
@@ -92,6 +116,17 @@ type JobQueue interface {
 	// Job returns all the parameters that define a job (everything provided during Enqueue).
 	Job(id uuid.UUID) (jobType string, args json.RawMessage, dependencies []uuid.UUID, channel string, err error)
 
+	// Grants `channel` the opaque `permission` on a job, replacing the
+	// permission of an existing grant to the same channel. Grants are
+	// deleted along with the job.
+	ShareJob(id uuid.UUID, channel, permission string) error
+
+	// Revokes the grant of `channel` on a job
+	UnshareJob(id uuid.UUID, channel string) error
+
+	// Returns the grants on a job, ordered by channel
+	JobShares(id uuid.UUID) ([]Share, error)
+
 	// Find job by token, this will return an error if the job hasn't been dequeued
 	IdFromToken(token uuid.UUID) (id uuid.UUID, err error)
 
@@ -102,11 +137,18 @@ type JobQueue interface {
 	RefreshHeartbeat(token uuid.UUID)
 
 	// Inserts the worker and creates a UUID for it
-	InsertWorker(channel, arch string) (uuid.UUID, error)
+	//
+	// A worker with 'nil' capabilities can dequeue any job, otherwise only
+	// jobs whose requirements are all in `capabilities`.
+	InsertWorker(channel, arch, version string, capabilities []string) (uuid.UUID, error)
 
 	// Reset the last worker's heartbeat time to time.Now()
 	UpdateWorkerStatus(workerID uuid.UUID) error
 
+	// Marks the worker as draining, it doesn't dequeue any new jobs
+	// afterwards. Jobs it is already running are not affected.
+	DrainWorker(workerID uuid.UUID) error
+
 	// Get a list of workers which haven't been updated in the specified time frame
 	Workers(olderThan time.Duration) ([]Worker, error)
 
@@ -120,6 +162,22 @@ type JobQueue interface {
 	DeleteJob(context.Context, uuid.UUID) error
 }
 
+// HealthChecker is implemented by job queues which can verify their backend
+// without changing any jobs.
+type HealthChecker interface {
+	// CheckHealth returns an error if the backend can't store jobs
+	CheckHealth(ctx context.Context) error
+}
+
+// SchemaChecker is implemented by job queues whose backend has to be migrated
+// to the schema the queue expects before it can store jobs.
+type SchemaChecker interface {
+	// CheckSchema returns an error if the backend is migrated to an older
+	// schema than the one of the queue. Newer schemas are fine, the backend
+	// is migrated before new versions of the queue are rolled out.
+	CheckSchema(ctx context.Context) error
+}
+
 // SimpleLogger provides a structured logging methods for the jobqueue library.
 type SimpleLogger interface {
 	// Info creates an info-level message and arbitrary amount of key-value string pairs which
@@ -142,10 +200,70 @@ var (
 	ErrWorkerNotExist = errors.New("worker does not exist")
 	ErrRunning        = errors.New("job is running, but wasn't expected to be")
 	ErrFinished       = errors.New("job is finished, but wasn't expected to be")
+	ErrShareNotExist  = errors.New("job is not shared with the channel")
 )
 
 type Worker struct {
-	ID      uuid.UUID
-	Channel string
-	Arch    string
+	ID           uuid.UUID
+	Channel      string
+	Arch         string
+	Version      string
+	Capabilities []string
+	Heartbeat    time.Time
+	Draining     bool
+	// Tokens of the jobs the worker is running
+	Tokens []uuid.UUID
+}
+
+// Route selects the jobs DequeueRoutes() may return: jobs of one of
+// JobTypes, which were enqueued with one of Channels, or with any channel if
+// AnyChannel is set, but none of ExcludeChannels, and whose requirements
+// include all of Requirements.
+type Route struct {
+	JobTypes        []string
+	Channels        []string
+	AnyChannel      bool
+	ExcludeChannels []string
+	Requirements    []string
+}
+
+// Matches returns whether the route selects a job of the given type,
+// channel and requirements
+func (r Route) Matches(jobType, channel string, requirements []string) bool {
+	if !slices.Contains(r.JobTypes, jobType) {
+		return false
+	}
+	if !r.AnyChannel && !slices.Contains(r.Channels, channel) {
+		return false
+	}
+	if slices.Contains(r.ExcludeChannels, channel) {
+		return false
+	}
+	for _, req := range r.Requirements {
+		if !slices.Contains(requirements, req) {
+			return false
+		}
+	}
+	return true
+}
+
+// Share grants a channel other than the job's own a permission on the job
+type Share struct {
+	Channel    string
+	Permission string
+	Created    time.Time
+}
+
+// HasCapabilities returns whether the capabilities satisfy all requirements,
+// 'nil' capabilities satisfy any.
+func HasCapabilities(capabilities, requirements []string) bool {
+	if capabilities == nil {
+		return true
+	}
+	for _, r := range requirements {
+		if !slices.Contains(capabilities, r) {
+			return false
+		}
+	}
+	return true
 }
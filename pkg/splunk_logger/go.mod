@@ -1,19 +0,0 @@
-module github.com/osbuild/osbuild-composer/pkg/splunk_logger
-
-go 1.25
-
-toolchain go1.25.9
-
-require (
-	github.com/hashicorp/go-retryablehttp v0.7.8
-	github.com/sirupsen/logrus v1.9.3
-	github.com/stretchr/testify v1.11.1
-)
-
-require (
-	github.com/davecgh/go-spew v1.1.1 // indirect
-	github.com/hashicorp/go-cleanhttp v0.5.2 // indirect
-	github.com/pmezard/go-difflib v1.0.0 // indirect
-	golang.org/x/sys v0.29.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
-)